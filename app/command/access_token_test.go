@@ -0,0 +1,135 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── CreateAccessToken ──────────────────────────────────────────────────────
+
+// noActiveSession returns a session repository in which the user has no
+// active session.
+func noActiveSession() *repoMock.SessionRepository {
+	repo := &repoMock.SessionRepository{}
+	repo.On("FindLatestByUserID", mock.Anything, mock.Anything).Return(nil, nil)
+	return repo
+}
+
+func TestCreateAccessToken_Handle_ValidationError(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(errors.New("failed"))
+
+	h := command.NewCreateAccessToken(nil, nil, v)
+	_, err := h.Handle(context.Background(), domainmodel.Identity{UserID: "user-1"}, command.CreateAccessTokenCmd{})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateAccessToken_Handle_StoresHashAndIdentity(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	var stored *domainrepo.AccessTokenRecord
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domainrepo.AccessTokenRecord) }).
+		Return(nil)
+
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("FindLatestByUserID", mock.Anything, "user-1").Return(&domainrepo.SessionRecord{
+		UserID:   "user-1",
+		Username: "sam",
+		Groups:   []string{"admin"},
+		IsAdmin:  true,
+	}, nil)
+
+	// identity also holds the groups managed in Dash, which stay off the token
+	h := command.NewCreateAccessToken(repo, sessionRepo, v)
+	secret, err := h.Handle(context.Background(), domainmodel.Identity{
+		UserID:   "user-1",
+		Username: "sam",
+		Groups:   []string{"admin", "guests"},
+		IsAdmin:  true,
+	}, command.CreateAccessTokenCmd{Name: "cli", Scope: "read", ExpiresInDays: 30})
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, domainmodel.AccessTokenPrefix))
+	require.NotNil(t, stored)
+	require.Equal(t, domainmodel.HashAccessToken(secret), stored.TokenHash)
+	require.NotContains(t, stored.TokenHash, secret)
+	require.Equal(t, "user-1", stored.UserID)
+	require.Equal(t, "read", stored.Scope)
+	require.Equal(t, "sam", stored.Username)
+	require.Equal(t, []string{"admin"}, stored.Groups)
+	require.True(t, stored.IsAdmin)
+	require.WithinDuration(t, time.Now().AddDate(0, 0, 30), stored.ExpiresAt, time.Minute)
+}
+
+func TestCreateAccessToken_Handle_NoExpiry(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.AccessTokenRecord) bool {
+		return r.ExpiresAt.IsZero()
+	})).Return(nil)
+
+	h := command.NewCreateAccessToken(repo, noActiveSession(), v)
+	_, err := h.Handle(context.Background(), domainmodel.Identity{UserID: "user-1"},
+		command.CreateAccessTokenCmd{Name: "cli", Scope: "read_write"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateAccessToken_Handle_RepoError(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	h := command.NewCreateAccessToken(repo, noActiveSession(), v)
+	_, err := h.Handle(context.Background(), domainmodel.Identity{UserID: "user-1"},
+		command.CreateAccessTokenCmd{Name: "cli", Scope: "read"})
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+// ── RevokeAccessToken ──────────────────────────────────────────────────────
+
+func TestRevokeAccessToken_Handle_Success(t *testing.T) {
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("DeleteByID", mock.Anything, "token-1", "user-1").Return(nil)
+
+	h := command.NewRevokeAccessToken(repo)
+	err := h.Handle(context.Background(), "user-1", "token-1")
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRevokeAccessToken_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("DeleteByID", mock.Anything, "token-1", "user-1").Return(errors.New("db error"))
+
+	h := command.NewRevokeAccessToken(repo)
+	err := h.Handle(context.Background(), "user-1", "token-1")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalGroupMemberAdder handles the add-local-group-member command.
+type LocalGroupMemberAdder interface {
+	Handle(ctx context.Context, isAdmin bool, groupID uint, userID string) error
+}
+
+type AddLocalGroupMember struct {
+	Repo domainrepo.LocalGroupRepository
+}
+
+func NewAddLocalGroupMember(repo domainrepo.LocalGroupRepository) *AddLocalGroupMember {
+	return &AddLocalGroupMember{Repo: repo}
+}
+
+// Handle assigns a user to a local group. Assigning a member again is a no-op.
+func (h *AddLocalGroupMember) Handle(ctx context.Context, isAdmin bool, groupID uint, userID string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage groups")
+	}
+	if userID == "" {
+		return domainerrors.Validation(domainerrors.Violation{Field: "UserID", Message: "required"})
+	}
+	if err := h.Repo.AddMember(ctx, groupID, userID); err != nil {
+		return domainerrors.WrapRepo("add local group member", err)
+	}
+	return nil
+}
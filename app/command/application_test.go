@@ -10,6 +10,7 @@ import (
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
 )
@@ -20,8 +21,8 @@ func TestCreateApplication_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("failed"))
 
-	h := command.NewCreateApplication(nil, v)
-	err := h.Handle(context.Background(), command.CreateApplicationCmd{})
+	h := command.NewCreateApplication(nil, v, nil)
+	err := h.Handle(context.Background(), testActor, command.CreateApplicationCmd{})
 
 	var ve *domainerrors.ValidationError
 	require.ErrorAs(t, err, &ve)
@@ -31,8 +32,8 @@ func TestCreateApplication_Handle_InvalidIcon(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(nil)
 
-	h := command.NewCreateApplication(nil, v)
-	err := h.Handle(context.Background(), command.CreateApplicationCmd{
+	h := command.NewCreateApplication(nil, v, nil)
+	err := h.Handle(context.Background(), testActor, command.CreateApplicationCmd{
 		Icon:        "bad-icon",
 		DisplayName: "App",
 		Url:         "https://example.com",
@@ -42,6 +43,24 @@ func TestCreateApplication_Handle_InvalidIcon(t *testing.T) {
 	require.ErrorAs(t, err, &ve)
 }
 
+func TestCreateApplication_Handle_InvalidExpectedStatus(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	h := command.NewCreateApplication(nil, v, nil)
+	err := h.Handle(context.Background(), testActor, command.CreateApplicationCmd{
+		Icon:                 "mdi:home",
+		DisplayName:          "App",
+		Url:                  "https://example.com",
+		HealthCheck:          true,
+		HealthExpectedStatus: "2xx",
+	})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Equal(t, "HealthExpectedStatus", ve.Violations[0].Field)
+}
+
 func TestCreateApplication_Handle_Success(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(nil)
@@ -51,8 +70,10 @@ func TestCreateApplication_Handle_Success(t *testing.T) {
 		return r.DisplayName == "My App" && r.Url == "https://example.com"
 	})).Return(nil)
 
-	h := command.NewCreateApplication(appRepo, v)
-	err := h.Handle(context.Background(), command.CreateApplicationCmd{
+	auditRepo := expectAudit(domainmodel.AuditActionApplicationCreate, "My App")
+
+	h := command.NewCreateApplication(appRepo, v, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, command.CreateApplicationCmd{
 		Icon:        "mdi:home",
 		DisplayName: "My App",
 		Url:         "https://example.com",
@@ -60,6 +81,7 @@ func TestCreateApplication_Handle_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	appRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestCreateApplication_Handle_RepoError(t *testing.T) {
@@ -69,8 +91,8 @@ func TestCreateApplication_Handle_RepoError(t *testing.T) {
 	appRepo := &repoMock.ApplicationRepository{}
 	appRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewCreateApplication(appRepo, v)
-	err := h.Handle(context.Background(), command.CreateApplicationCmd{
+	h := command.NewCreateApplication(appRepo, v, acceptAudit())
+	err := h.Handle(context.Background(), testActor, command.CreateApplicationCmd{
 		Icon:        "mdi:home",
 		DisplayName: "My App",
 		Url:         "https://example.com",
@@ -152,8 +174,8 @@ func TestUpdateApplication_Handle_Success(t *testing.T) {
 // ── DeleteApplication ──────────────────────────────────────────────────────
 
 func TestDeleteApplication_Handle_ZeroID(t *testing.T) {
-	h := command.NewDeleteApplication(nil)
-	err := h.Handle(context.Background(), 0)
+	h := command.NewDeleteApplication(nil, nil)
+	err := h.Handle(context.Background(), testActor, 0)
 
 	var ve *domainerrors.ValidationError
 	require.ErrorAs(t, err, &ve)
@@ -164,8 +186,8 @@ func TestDeleteApplication_Handle_NotFound(t *testing.T) {
 	appRepo.On("Get", mock.Anything, uint(5)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityApplication))
 
-	h := command.NewDeleteApplication(appRepo)
-	err := h.Handle(context.Background(), 5)
+	h := command.NewDeleteApplication(appRepo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, 5)
 
 	var nfe *domainerrors.NotFoundError
 	require.ErrorAs(t, err, &nfe)
@@ -174,14 +196,16 @@ func TestDeleteApplication_Handle_NotFound(t *testing.T) {
 func TestDeleteApplication_Handle_Success(t *testing.T) {
 	appRepo := &repoMock.ApplicationRepository{}
 	appRepo.On("Get", mock.Anything, uint(5)).
-		Return(&domainrepo.ApplicationRecord{ID: 5}, nil)
+		Return(&domainrepo.ApplicationRecord{ID: 5, DisplayName: "Grafana"}, nil)
 	appRepo.On("Delete", mock.Anything, uint(5)).Return(nil)
+	auditRepo := expectAudit(domainmodel.AuditActionApplicationDelete, "Grafana")
 
-	h := command.NewDeleteApplication(appRepo)
-	err := h.Handle(context.Background(), 5)
+	h := command.NewDeleteApplication(appRepo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, 5)
 
 	require.NoError(t, err)
 	appRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestDeleteApplication_Handle_DeleteError(t *testing.T) {
@@ -190,8 +214,8 @@ func TestDeleteApplication_Handle_DeleteError(t *testing.T) {
 		Return(&domainrepo.ApplicationRecord{ID: 5}, nil)
 	appRepo.On("Delete", mock.Anything, uint(5)).Return(errors.New("db error"))
 
-	h := command.NewDeleteApplication(appRepo)
-	err := h.Handle(context.Background(), 5)
+	h := command.NewDeleteApplication(appRepo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, 5)
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
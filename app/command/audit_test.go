@@ -0,0 +1,105 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// testActor is the actor of the audited commands in these tests.
+var testActor = domainmodel.AuditActor{UserID: "user-1", IP: "192.0.2.1"}
+
+// acceptAudit returns an audit recorder whose repository accepts every entry.
+func acceptAudit() *command.RecordAudit {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	return command.NewRecordAudit(repo)
+}
+
+// expectAudit returns an audit log mock that expects one entry of testActor
+// with the given action and target.
+func expectAudit(action domainmodel.AuditAction, target string) *repoMock.AuditLogRepository {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(e *domainmodel.AuditEntry) bool {
+		return e.ActorID == testActor.UserID && e.IP == testActor.IP &&
+			e.Action == action && e.Target == target
+	})).Return(nil).Once()
+	return repo
+}
+
+// ── RecordAudit ────────────────────────────────────────────────────────────
+
+func TestRecordAudit_Handle_Success(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("Create", mock.Anything, &domainmodel.AuditEntry{
+		ActorID:   "user-1",
+		Action:    domainmodel.AuditActionSessionRevoke,
+		Target:    "record-1",
+		IP:        "192.0.2.1",
+		CreatedAt: now,
+	}).Return(nil)
+
+	h := command.NewRecordAudit(repo)
+	h.Now = func() time.Time { return now }
+	h.Handle(context.Background(), testActor, domainmodel.AuditActionSessionRevoke, "record-1")
+
+	repo.AssertExpectations(t)
+}
+
+func TestRecordAudit_Handle_RepoErrorIsLogged(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	var logged []string
+	h := command.NewRecordAudit(repo)
+	h.Logf = func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) }
+	h.Handle(context.Background(), testActor, domainmodel.AuditActionLogin, "")
+
+	require.Len(t, logged, 1)
+	require.Contains(t, logged[0], "db error")
+}
+
+// ── CleanupAuditLog ────────────────────────────────────────────────────────
+
+func TestCleanupAuditLog_Handle_DeletesBeforeRetention(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("DeleteBefore", mock.Anything, now.Add(-90*24*time.Hour)).Return(nil)
+
+	h := command.NewCleanupAuditLog(repo, 90*24*time.Hour)
+	h.Now = func() time.Time { return now }
+
+	require.NoError(t, h.Handle(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestCleanupAuditLog_Handle_ZeroRetentionKeepsEverything(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+
+	h := command.NewCleanupAuditLog(repo, 0)
+
+	require.NoError(t, h.Handle(context.Background()))
+	repo.AssertNotCalled(t, "DeleteBefore", mock.Anything, mock.Anything)
+}
+
+func TestCleanupAuditLog_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("DeleteBefore", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	h := command.NewCleanupAuditLog(repo, time.Hour)
+	err := h.Handle(context.Background())
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
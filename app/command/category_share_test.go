@@ -0,0 +1,288 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// noCategoryShare returns a share repository in which no category is shared
+// with anyone.
+func noCategoryShare() *repoMock.CategoryShareRepository {
+	repo := &repoMock.CategoryShareRepository{}
+	repo.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, domainerrors.NotFound(domainerrors.EntityCategoryShare))
+	return repo
+}
+
+// categoryShare returns a share repository in which category 1 is shared
+// with user-1 in the given role.
+func categoryShare(role domainmodel.CategoryRole) *repoMock.CategoryShareRepository {
+	repo := &repoMock.CategoryShareRepository{}
+	repo.On("Get", mock.Anything, uint(1), "user-1").
+		Return(&domainrepo.CategoryShareRecord{CategoryID: 1, UserID: "user-1", Role: string(role)}, nil)
+	return repo
+}
+
+// ownerDashboardRepos returns repositories in which category 5 "Runbooks"
+// belongs to dashboard 10 of user-1.
+func ownerDashboardRepos() (*repoMock.DashboardRepository, *repoMock.CategoryRepository) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Runbooks"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Runbooks"},
+	}, nil)
+	return dashRepo, catRepo
+}
+
+// unsharedParents returns repositories in which no category is shared or
+// published, so categories may be nested below any of them.
+func unsharedParents() (*repoMock.CategoryShareRepository, *repoMock.SharedCategoryRepository) {
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByCategory", mock.Anything, mock.Anything).Return([]domainrepo.CategoryShareRecord{}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, mock.Anything).Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
+	return shareRepo, sharedRepo
+}
+
+// noUserIDs returns a user repository in which no input matches a user ID, so
+// recipients are looked up by username or email.
+func noUserIDs() *repoMock.UserRepository {
+	repo := &repoMock.UserRepository{}
+	repo.On("Exists", mock.Anything, mock.Anything).Return(false, nil)
+	return repo
+}
+
+func latestSessions() *repoMock.SessionRepository {
+	repo := &repoMock.SessionRepository{}
+	repo.On("ListLatest", mock.Anything).Return([]*domainrepo.SessionRecord{
+		{UserID: "user-1", Username: "alice", Email: "alice@example.com"},
+		{UserID: "user-2", Username: "bob", Email: "bob@example.com"},
+		{UserID: "user-3", Username: "carl", Email: "carl@example.com"},
+		{UserID: "user-4", Username: "carl", Email: "carl@example.org"},
+	}, nil)
+	return repo
+}
+
+// ── ShareCategory ──────────────────────────────────────────────────────────
+
+func TestShareCategory_Handle_SharesByEmail(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Upsert", mock.Anything, &domainrepo.CategoryShareRecord{CategoryID: 5, UserID: "user-2", Role: "editor"}).Return(nil)
+	audit := expectAudit(domainmodel.AuditActionCategoryShare, "Runbooks")
+
+	h := command.NewShareCategory(dashRepo, catRepo, shareRepo, noUserIDs(), latestSessions(), validation.New(), command.NewRecordAudit(audit))
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{
+		CategoryID: 5,
+		User:       " Bob@Example.com ",
+		Role:       "editor",
+	})
+
+	require.NoError(t, err)
+	shareRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestShareCategory_Handle_RejectsUnknownRole(t *testing.T) {
+	h := command.NewShareCategory(nil, nil, nil, nil, nil, validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "bob", Role: "owner"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestShareCategory_Handle_RejectsForeignCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(99)).Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99}, nil)
+	shareRepo := &repoMock.CategoryShareRepository{}
+
+	h := command.NewShareCategory(dashRepo, catRepo, shareRepo, noUserIDs(), latestSessions(), validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "bob", Role: "viewer"})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	shareRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestShareCategory_Handle_RejectsUnknownUser(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+
+	h := command.NewShareCategory(dashRepo, catRepo, &repoMock.CategoryShareRepository{}, noUserIDs(), latestSessions(), validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "carol", Role: "viewer"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestShareCategory_Handle_RejectsOwner(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+
+	h := command.NewShareCategory(dashRepo, catRepo, &repoMock.CategoryShareRepository{}, noUserIDs(), latestSessions(), validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "alice", Role: "viewer"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestShareCategory_Handle_SharesByUserID(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Upsert", mock.Anything, &domainrepo.CategoryShareRecord{CategoryID: 5, UserID: "user-9", Role: "viewer"}).Return(nil)
+	userRepo := &repoMock.UserRepository{}
+	userRepo.On("Exists", mock.Anything, "user-9").Return(true, nil)
+	sessionRepo := &repoMock.SessionRepository{}
+
+	h := command.NewShareCategory(dashRepo, catRepo, shareRepo, userRepo, sessionRepo, validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "user-9", Role: "viewer"})
+
+	require.NoError(t, err)
+	shareRepo.AssertExpectations(t)
+	sessionRepo.AssertNotCalled(t, "ListLatest", mock.Anything)
+}
+
+func TestShareCategory_Handle_RejectsAmbiguousUsername(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+	shareRepo := &repoMock.CategoryShareRepository{}
+
+	h := command.NewShareCategory(dashRepo, catRepo, shareRepo, noUserIDs(), latestSessions(), validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "carl", Role: "viewer"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	shareRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestShareCategory_Handle_RejectsCategoryWithSubCategories(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Runbooks"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Runbooks"},
+		{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Databases"},
+	}, nil)
+	shareRepo := &repoMock.CategoryShareRepository{}
+
+	h := command.NewShareCategory(dashRepo, catRepo, shareRepo, noUserIDs(), latestSessions(), validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", testActor, command.ShareCategoryCmd{CategoryID: 5, User: "bob", Role: "viewer"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	shareRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+// ── RevokeCategoryShare ────────────────────────────────────────────────────
+
+func TestRevokeCategoryShare_Handle_Success(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Delete", mock.Anything, uint(5), "user-2").Return(nil)
+	audit := expectAudit(domainmodel.AuditActionCategoryUnshare, "Runbooks")
+
+	h := command.NewRevokeCategoryShare(dashRepo, catRepo, shareRepo, command.NewRecordAudit(audit))
+	err := h.Handle(context.Background(), "user-1", testActor, 5, "user-2")
+
+	require.NoError(t, err)
+	shareRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestRevokeCategoryShare_Handle_RejectsForeignCategory(t *testing.T) {
+	dashRepo, catRepo := ownerDashboardRepos()
+	shareRepo := &repoMock.CategoryShareRepository{}
+
+	h := command.NewRevokeCategoryShare(dashRepo, catRepo, shareRepo, acceptAudit())
+	err := h.Handle(context.Background(), "user-2", testActor, 5, "user-3")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	shareRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ── LeaveCategoryShare ─────────────────────────────────────────────────────
+
+func TestLeaveCategoryShare_Handle_NotShared(t *testing.T) {
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Delete", mock.Anything, uint(5), "user-2").Return(domainerrors.NotFound(domainerrors.EntityCategoryShare))
+
+	h := command.NewLeaveCategoryShare(shareRepo)
+	err := h.Handle(context.Background(), "user-2", 5)
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+// ── Bookmarks of shared categories ─────────────────────────────────────────
+
+func TestCreateUserBookmark_Handle_Editor(t *testing.T) {
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.CategoryID == 1
+	})).Return(nil)
+
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, categoryShare(domainmodel.CategoryRoleEditor), validation.New())
+	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
+
+	require.NoError(t, err)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+func TestDeleteUserBookmark_Handle_Viewer(t *testing.T) {
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(7)).Return(&domainrepo.BookmarkRecord{ID: 7, CategoryID: 1}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+
+	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo, categoryShare(domainmodel.CategoryRoleViewer))
+	err := h.Handle(context.Background(), "user-1", 7)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	bookmarkRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserBookmark_Handle_MoveIntoViewerCategory(t *testing.T) {
+	cmd := validUpdateBookmarkCmd()
+	cmd.CategoryID = 1 // shared with user-1 as viewer
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(7)).Return(&domainrepo.BookmarkRecord{ID: 7, CategoryID: 2}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(2)).Return(&domainrepo.CategoryRecord{ID: 2, DashboardID: 10}, nil)
+	catRepo.On("Get", mock.Anything, uint(1)).Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, categoryShare(domainmodel.CategoryRoleViewer), validation.New())
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	bookmarkRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
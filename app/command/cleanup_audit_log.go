@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// AuditLogCleaner handles the cleanup-audit-log command.
+type AuditLogCleaner interface {
+	Handle(ctx context.Context) error
+}
+
+type CleanupAuditLog struct {
+	Repo domainrepo.AuditLogRepository
+	// Retention is how long entries are kept; zero keeps them forever.
+	Retention time.Duration
+	Now       func() time.Time
+}
+
+func NewCleanupAuditLog(repo domainrepo.AuditLogRepository, retention time.Duration) *CleanupAuditLog {
+	return &CleanupAuditLog{Repo: repo, Retention: retention, Now: time.Now}
+}
+
+// Handle deletes the entries older than Retention.
+func (h *CleanupAuditLog) Handle(ctx context.Context) error {
+	if h.Retention <= 0 {
+		return nil
+	}
+	if err := h.Repo.DeleteBefore(ctx, h.Now().Add(-h.Retention)); err != nil {
+		return domainerrors.Internal("cleanup audit log", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// FaviconsCleaner handles the cleanup-favicons command.
+type FaviconsCleaner interface {
+	Handle(ctx context.Context) error
+}
+
+type CleanupFavicons struct {
+	Repo domainrepo.FaviconRepository
+	// MaxAge is how long a favicon stays cached without being fetched again.
+	MaxAge time.Duration
+	Now    func() time.Time
+}
+
+func NewCleanupFavicons(repo domainrepo.FaviconRepository, maxAge time.Duration) *CleanupFavicons {
+	return &CleanupFavicons{Repo: repo, MaxAge: maxAge, Now: time.Now}
+}
+
+// Handle deletes favicons of sites that nobody looked at for MaxAge, e.g.
+// because the bookmark was removed.
+func (h *CleanupFavicons) Handle(ctx context.Context) error {
+	if err := h.Repo.DeleteFetchedBefore(ctx, h.Now().Add(-h.MaxAge)); err != nil {
+		return domainerrors.Internal("cleanup favicons", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/google/uuid"
+)
+
+// CreateAccessTokenCmd is the input for creating a personal access token.
+// ExpiresInDays = 0 creates a token that never expires.
+type CreateAccessTokenCmd struct {
+	Name          string `validate:"required,max=64"`
+	Scope         string `validate:"required,oneof=read read_write"`
+	ExpiresInDays int    `validate:"gte=0,lte=3650"`
+}
+
+// AccessTokenCreator handles the create-access-token command.
+// It returns the token secret, which is shown to the user exactly once.
+type AccessTokenCreator interface {
+	Handle(ctx context.Context, identity domainmodel.Identity, in CreateAccessTokenCmd) (string, error)
+}
+
+type CreateAccessToken struct {
+	Repo        domainrepo.AccessTokenRepository
+	SessionRepo domainrepo.SessionRepository
+	Validator   validation.Validator
+}
+
+func NewCreateAccessToken(repo domainrepo.AccessTokenRepository, sessionRepo domainrepo.SessionRepository, v validation.Validator) *CreateAccessToken {
+	return &CreateAccessToken{Repo: repo, SessionRepo: sessionRepo, Validator: v}
+}
+
+// Handle creates a token that carries a snapshot of the owner's identity. The
+// snapshot is taken from the owner's latest active session, whose groups come
+// from the IdP only; identity already holds the groups managed in Dash, which
+// the token loader resolves on every request instead.
+
+func (h *CreateAccessToken) Handle(ctx context.Context, identity domainmodel.Identity, in CreateAccessTokenCmd) (string, error) {
+	if err := h.Validator.Struct(in); err != nil {
+		return "", domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	secret, err := domainmodel.GenerateAccessToken()
+	if err != nil {
+		return "", domainerrors.Internal("create access token: generate", err)
+	}
+
+	var expiresAt time.Time
+	if in.ExpiresInDays > 0 {
+		expiresAt = time.Now().AddDate(0, 0, in.ExpiresInDays)
+	}
+
+	record := &domainrepo.AccessTokenRecord{
+		ID:          uuid.New().String(),
+		UserID:      identity.UserID,
+		Name:        in.Name,
+		TokenHash:   domainmodel.HashAccessToken(secret),
+		Scope:       in.Scope,
+		ExpiresAt:   expiresAt,
+		Username:    identity.Username,
+		Email:       identity.Email,
+		FirstName:   identity.FirstName,
+		LastName:    identity.LastName,
+		DisplayName: identity.DisplayName,
+	}
+	session, err := h.SessionRepo.FindLatestByUserID(ctx, identity.UserID)
+	if err != nil {
+		return "", domainerrors.Internal("create access token: find latest session", err)
+	}
+	if session != nil {
+		record.Username = session.Username
+		record.Email = session.Email
+		record.FirstName = session.FirstName
+		record.LastName = session.LastName
+		record.DisplayName = session.DisplayName
+		record.Picture = session.Picture
+		record.ProfileUrl = session.ProfileUrl
+		record.Groups = session.Groups
+		record.IsAdmin = session.IsAdmin
+	}
+
+	if err := h.Repo.Create(ctx, record); err != nil {
+		return "", domainerrors.Internal("create access token: create", err)
+	}
+
+	return secret, nil
+}
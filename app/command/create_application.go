@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"strings"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
 	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
@@ -17,35 +18,46 @@ type CreateApplicationCmd struct {
 	DisplayName     string   `validate:"required"`
 	Url             string   `validate:"required,url"`
 	VisibleToGroups []string `validate:"dive"`
+
+	HealthCheck          bool
+	HealthURL            string `validate:"omitempty,url"`
+	HealthExpectedStatus string
 }
 
 // ApplicationCreator handles the CreateApplicationCmd command.
 type ApplicationCreator interface {
-	Handle(ctx context.Context, in CreateApplicationCmd) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, in CreateApplicationCmd) error
 }
 
 type CreateApplication struct {
 	ApplicationRepo domainrepo.ApplicationRepository
 	Validator       validation.Validator
+	Audit           AuditRecorder
 }
 
 func NewCreateApplication(
 	applicationRepo domainrepo.ApplicationRepository,
 	validator validation.Validator,
+	audit AuditRecorder,
 ) *CreateApplication {
 	return &CreateApplication{
 		ApplicationRepo: applicationRepo,
 		Validator:       validator,
+		Audit:           audit,
 	}
 }
 
-func (h *CreateApplication) Handle(ctx context.Context, in CreateApplicationCmd) error {
+func (h *CreateApplication) Handle(ctx context.Context, actor domainmodel.AuditActor, in CreateApplicationCmd) error {
 	if err := h.Validator.Struct(in); err != nil {
 		return domainerrors.Validation(validation.ToViolations(err)...)
 	}
 	if _, err := domainmodel.ParseIcon(in.Icon); err != nil {
 		return domainerrors.Validation(domainerrors.Violation{Message: err.Error()})
 	}
+	expectedStatus, err := normalizeExpectedStatus(in.HealthExpectedStatus)
+	if err != nil {
+		return err
+	}
 
 	record := &domainrepo.ApplicationRecord{
 		CreatedBy:       in.CreatedBy,
@@ -53,9 +65,27 @@ func (h *CreateApplication) Handle(ctx context.Context, in CreateApplicationCmd)
 		DisplayName:     in.DisplayName,
 		Url:             in.Url,
 		VisibleToGroups: in.VisibleToGroups,
+
+		HealthCheck:          in.HealthCheck,
+		HealthURL:            in.HealthURL,
+		HealthExpectedStatus: expectedStatus,
 	}
 	if err := h.ApplicationRepo.Upsert(ctx, record); err != nil {
 		return domainerrors.Internal("create application: upsert", err)
 	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionApplicationCreate, in.DisplayName)
 	return nil
 }
+
+// normalizeExpectedStatus validates a list of expected status codes and returns
+// it in canonical form. An empty list stays empty so that the default applies.
+func normalizeExpectedStatus(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	codes, err := domainmodel.ParseStatusCodes(raw)
+	if err != nil {
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "HealthExpectedStatus", Message: err.Error()})
+	}
+	return codes.String(), nil
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/google/uuid"
+)
+
+// CreateInvitationCmd is the input for creating a guest invitation.
+type CreateInvitationCmd struct {
+	Groups        []string `validate:"min=1,dive,required,max=64"`
+	MaxUses       int      `validate:"gte=1,lte=1000"`
+	ExpiresInDays int      `validate:"gte=1,lte=365"`
+	GrantDays     int      `validate:"gte=1,lte=3650"`
+}
+
+// InvitationCreator handles the create-invitation command. It returns the
+// invitation secret, which is shown to the admin exactly once.
+type InvitationCreator interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, in CreateInvitationCmd) (string, error)
+}
+
+type CreateInvitation struct {
+	Repo      domainrepo.InvitationRepository
+	Validator validation.Validator
+	Audit     AuditRecorder
+	Now       func() time.Time
+}
+
+func NewCreateInvitation(repo domainrepo.InvitationRepository, v validation.Validator, audit AuditRecorder) *CreateInvitation {
+	return &CreateInvitation{Repo: repo, Validator: v, Audit: audit, Now: time.Now}
+}
+
+// Handle creates an invitation. Only admins may invite guests, and the
+// synthetic groups cannot be granted: dash_user is implied by signing in and
+// dash_admin must come from the IdP.
+func (h *CreateInvitation) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, in CreateInvitationCmd) (string, error) {
+	if !isAdmin {
+		return "", domainerrors.Forbidden("only admins may invite guests")
+	}
+	if err := h.Validator.Struct(in); err != nil {
+		return "", domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if slices.Contains(in.Groups, domainmodel.SyntheticGroupUser) || slices.Contains(in.Groups, domainmodel.SyntheticGroupAdmin) {
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "Groups", Message: "reserved"})
+	}
+
+	secret, err := domainmodel.GenerateInvitationToken()
+	if err != nil {
+		return "", domainerrors.Internal("create invitation: generate", err)
+	}
+
+	id := uuid.New().String()
+	if err := h.Repo.Create(ctx, &domainrepo.InvitationRecord{
+		ID:            id,
+		TokenHash:     domainmodel.HashInvitationToken(secret),
+		Groups:        in.Groups,
+		MaxUses:       in.MaxUses,
+		ExpiresAt:     h.Now().AddDate(0, 0, in.ExpiresInDays),
+		GrantDuration: time.Duration(in.GrantDays) * 24 * time.Hour,
+		CreatedBy:     actor.UserID,
+	}); err != nil {
+		return "", domainerrors.Internal("create invitation: create", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionInvitationCreate, id)
+	return secret, nil
+}
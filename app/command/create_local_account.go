@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CreateLocalAccountCmd is the input for creating a local account.
+type CreateLocalAccountCmd struct {
+	Username    string   `validate:"required,max=64,excludesall= /"`
+	DisplayName string   `validate:"max=128"`
+	Email       string   `validate:"omitempty,email,max=254"`
+	Groups      []string `validate:"dive,required,max=64"`
+	Password    string   `validate:"required,min=8,max=72"`
+}
+
+// LocalAccountCreator handles the create-local-account command.
+type LocalAccountCreator interface {
+	Handle(ctx context.Context, isAdmin bool, in CreateLocalAccountCmd) error
+}
+
+type CreateLocalAccount struct {
+	Repo      domainrepo.LocalAccountRepository
+	Validator validation.Validator
+}
+
+func NewCreateLocalAccount(repo domainrepo.LocalAccountRepository, v validation.Validator) *CreateLocalAccount {
+	return &CreateLocalAccount{Repo: repo, Validator: v}
+}
+
+// Handle creates a local account. Only admins may create accounts; the
+// username is normalized so sign-in is case-insensitive.
+func (h *CreateLocalAccount) Handle(ctx context.Context, isAdmin bool, in CreateLocalAccountCmd) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage local accounts")
+	}
+	in.Username = domainmodel.NormalizeUsername(in.Username)
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	_, err := h.Repo.GetByUsername(ctx, in.Username)
+	if err == nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Username", Message: "taken"})
+	}
+	var nfe *domainerrors.NotFoundError
+	if !errors.As(err, &nfe) {
+		return domainerrors.Internal("create local account: get by username", err)
+	}
+
+	hash, err := domainmodel.HashPassword(in.Password)
+	if err != nil {
+		return domainerrors.Internal("create local account: hash password", err)
+	}
+
+	if err := h.Repo.Create(ctx, &domainrepo.LocalAccountRecord{
+		Username:     in.Username,
+		PasswordHash: hash,
+		DisplayName:  in.DisplayName,
+		Email:        in.Email,
+		Groups:       in.Groups,
+	}); err != nil {
+		return domainerrors.Internal("create local account: create", err)
+	}
+	return nil
+}
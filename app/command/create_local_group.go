@@ -0,0 +1,75 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CreateLocalGroupCmd is the input for creating a local group.
+type CreateLocalGroupCmd struct {
+	Name      string
+	IdpGroups []string `validate:"dive,required,max=256"`
+}
+
+// LocalGroupCreator handles the create-local-group command.
+type LocalGroupCreator interface {
+	Handle(ctx context.Context, isAdmin bool, in CreateLocalGroupCmd) error
+}
+
+type CreateLocalGroup struct {
+	Repo      domainrepo.LocalGroupRepository
+	Validator validation.Validator
+}
+
+func NewCreateLocalGroup(repo domainrepo.LocalGroupRepository, v validation.Validator) *CreateLocalGroup {
+	return &CreateLocalGroup{Repo: repo, Validator: v}
+}
+
+// Handle creates a local group. Only admins may create groups.
+func (h *CreateLocalGroup) Handle(ctx context.Context, isAdmin bool, in CreateLocalGroupCmd) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage groups")
+	}
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	name, err := localGroupName(ctx, h.Repo, 0, in.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.Create(ctx, &domainrepo.LocalGroupRecord{
+		Name:      name,
+		IdpGroups: in.IdpGroups,
+	}); err != nil {
+		return domainerrors.Internal("create local group: create", err)
+	}
+	return nil
+}
+
+// localGroupName validates a group name and returns it normalized. The name
+// must not be taken by a group other than id.
+func localGroupName(ctx context.Context, repo domainrepo.LocalGroupRepository, id uint, raw string) (string, error) {
+	name, err := domainmodel.ParseLocalGroupName(raw)
+	if err != nil {
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "Name", Message: err.Error()})
+	}
+
+	existing, err := repo.GetByName(ctx, name)
+	if err == nil {
+		if existing.ID != id {
+			return "", domainerrors.Validation(domainerrors.Violation{Field: "Name", Message: "taken"})
+		}
+		return name, nil
+	}
+	var nfe *domainerrors.NotFoundError
+	if !errors.As(err, &nfe) {
+		return "", domainerrors.Internal("local group: get by name", err)
+	}
+	return name, nil
+}
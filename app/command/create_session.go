@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 	"github.com/google/uuid"
 )
@@ -12,23 +13,26 @@ import (
 // Identity fields are extracted from the OIDC token at login time and stored
 // server-side so the cookie only needs to carry the SessionID.
 type CreateSessionCmd struct {
-	SessionID   string
-	UserID      string
-	Sub         string
-	Username    string
-	Email       string
-	FirstName   string
-	LastName    string
-	DisplayName string
-	Picture     string
-	ProfileUrl  string
-	RawIDToken  string
-	Groups      []string
-	IsAdmin     bool
-	IssuedAt    time.Time
-	ExpiresAt   time.Time
-	IP          string
-	UserAgent   string
+	SessionID    string
+	UserID       string
+	Issuer       string
+	Sub          string
+	Sid          string
+	Username     string
+	Email        string
+	FirstName    string
+	LastName     string
+	DisplayName  string
+	Picture      string
+	ProfileUrl   string
+	RawIDToken   string
+	RefreshToken string
+	Groups       []string
+	IsAdmin      bool
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
+	IP           string
+	UserAgent    string
 }
 
 // SessionCreator handles the create-session command.
@@ -37,32 +41,43 @@ type SessionCreator interface {
 }
 
 type CreateSession struct {
-	Repo domainrepo.SessionRepository
+	Repo  domainrepo.SessionRepository
+	Audit AuditRecorder
 }
 
-func NewCreateSession(repo domainrepo.SessionRepository) *CreateSession {
-	return &CreateSession{Repo: repo}
+func NewCreateSession(repo domainrepo.SessionRepository, audit AuditRecorder) *CreateSession {
+	return &CreateSession{Repo: repo, Audit: audit}
 }
 
+// Handle stores the session and records the login, with the issuer the user
+// signed in with as the audit target.
 func (h *CreateSession) Handle(ctx context.Context, cmd CreateSessionCmd) error {
-	return h.Repo.Create(ctx, &domainrepo.SessionRecord{
-		ID:          uuid.New().String(),
-		UserID:      cmd.UserID,
-		SessionID:   cmd.SessionID,
-		Sub:         cmd.Sub,
-		Username:    cmd.Username,
-		Email:       cmd.Email,
-		FirstName:   cmd.FirstName,
-		LastName:    cmd.LastName,
-		DisplayName: cmd.DisplayName,
-		Picture:     cmd.Picture,
-		ProfileUrl:  cmd.ProfileUrl,
-		RawIDToken:  cmd.RawIDToken,
-		Groups:      cmd.Groups,
-		IsAdmin:     cmd.IsAdmin,
-		IssuedAt:    cmd.IssuedAt,
-		ExpiresAt:   cmd.ExpiresAt,
-		LastIP:      cmd.IP,
-		UserAgent:   cmd.UserAgent,
-	})
+	if err := h.Repo.Create(ctx, &domainrepo.SessionRecord{
+		ID:           uuid.New().String(),
+		UserID:       cmd.UserID,
+		SessionID:    cmd.SessionID,
+		Issuer:       cmd.Issuer,
+		Sub:          cmd.Sub,
+		Sid:          cmd.Sid,
+		Username:     cmd.Username,
+		Email:        cmd.Email,
+		FirstName:    cmd.FirstName,
+		LastName:     cmd.LastName,
+		DisplayName:  cmd.DisplayName,
+		Picture:      cmd.Picture,
+		ProfileUrl:   cmd.ProfileUrl,
+		RawIDToken:   cmd.RawIDToken,
+		RefreshToken: cmd.RefreshToken,
+		Groups:       cmd.Groups,
+		IsAdmin:      cmd.IsAdmin,
+		IssuedAt:     cmd.IssuedAt,
+		ExpiresAt:    cmd.ExpiresAt,
+		LastIP:       cmd.IP,
+		UserAgent:    cmd.UserAgent,
+	}); err != nil {
+		return err
+	}
+	actor := domainmodel.AuditActor{UserID: cmd.UserID, IP: cmd.IP}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionLogin, cmd.Issuer)
+	return nil
 }
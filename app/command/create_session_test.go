@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
 )
 
@@ -22,6 +23,7 @@ func TestCreateSession_Handle_Success(t *testing.T) {
 	cmd := command.CreateSessionCmd{
 		SessionID:   "cookie-session-1",
 		UserID:      "user-1",
+		Issuer:      "https://idp.example.com",
 		Sub:         "sub123",
 		Username:    "sam",
 		Email:       "sam@example.com",
@@ -36,18 +38,25 @@ func TestCreateSession_Handle_Success(t *testing.T) {
 		UserAgent:   "Mozilla/5.0",
 	}
 
-	h := command.NewCreateSession(repo)
+	auditRepo := &repoMock.AuditLogRepository{}
+	auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domainmodel.AuditEntry) bool {
+		return e.ActorID == "user-1" && e.IP == "127.0.0.1" &&
+			e.Action == domainmodel.AuditActionLogin && e.Target == "https://idp.example.com"
+	})).Return(nil)
+
+	h := command.NewCreateSession(repo, command.NewRecordAudit(auditRepo))
 	err := h.Handle(context.Background(), cmd)
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestCreateSession_Handle_RepoError(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewCreateSession(repo)
+	h := command.NewCreateSession(repo, nil)
 	err := h.Handle(context.Background(), command.CreateSessionCmd{})
 
 	require.Error(t, err)
@@ -16,6 +16,7 @@ type CreateUserBookmarkCmd struct {
 	DisplayName string `validate:"required"`
 	Url         string `validate:"required,url"`
 	CategoryID  uint   `validate:"required,gt=0"`
+	Tags        []string
 }
 
 // UserBookmarkCreator handles the CreateUserBookmarkCmd command.
@@ -24,23 +25,26 @@ type UserBookmarkCreator interface {
 }
 
 type CreateUserBookmark struct {
-	DashboardRepo domainrepo.DashboardRepository
-	CategoryRepo  domainrepo.CategoryRepository
-	BookmarkRepo  domainrepo.BookmarkRepository
-	Validator     validation.Validator
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	Validator         validation.Validator
 }
 
 func NewCreateUserBookmark(
 	dashboardRepo domainrepo.DashboardRepository,
 	categoryRepo domainrepo.CategoryRepository,
 	bookmarkRepo domainrepo.BookmarkRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
 	validator validation.Validator,
 ) *CreateUserBookmark {
 	return &CreateUserBookmark{
-		DashboardRepo: dashboardRepo,
-		CategoryRepo:  categoryRepo,
-		BookmarkRepo:  bookmarkRepo,
-		Validator:     validator,
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		BookmarkRepo:      bookmarkRepo,
+		CategoryShareRepo: categoryShareRepo,
+		Validator:         validator,
 	}
 }
 
@@ -51,19 +55,25 @@ func (h *CreateUserBookmark) Handle(ctx context.Context, userId string, in Creat
 	if _, err := domainmodel.ParseIcon(in.Icon); err != nil {
 		return domainerrors.Validation(domainerrors.Violation{Message: err.Error()})
 	}
+	tags, err := domainmodel.NormalizeTags(in.Tags)
+	if err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Tags", Message: err.Error()})
+	}
 
 	catRecord, err := h.CategoryRepo.Get(ctx, in.CategoryID)
 	if err != nil {
 		return domainerrors.WrapRepo("create user bookmark: get category", err)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
+	canEdit, err := canEditBookmarks(ctx, h.DashboardRepo, h.CategoryShareRepo, userId, catRecord)
 	if err != nil {
-		return domainerrors.WrapRepo("create user bookmark: get dashboard", err)
+		return domainerrors.WrapRepo("create user bookmark: check category access", err)
 	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return domainerrors.Forbidden("user does not own dashboard")
+	if !canEdit {
+		return domainerrors.Forbidden("user may not edit bookmarks of category")
+	}
+	if catRecord.TagQuery != "" {
+		return domainerrors.Validation(smartCategoryViolation)
 	}
 
 	if err := h.BookmarkRepo.Upsert(ctx, &domainrepo.BookmarkRecord{
@@ -71,8 +81,13 @@ func (h *CreateUserBookmark) Handle(ctx context.Context, userId string, in Creat
 		Icon:        in.Icon,
 		DisplayName: in.DisplayName,
 		Url:         in.Url,
+		Tags:        tags,
 	}); err != nil {
 		return domainerrors.Internal("create user bookmark: upsert", err)
 	}
 	return nil
 }
+
+// smartCategoryViolation rejects bookmarks added to smart categories, which
+// show the matching bookmarks of other categories instead.
+var smartCategoryViolation = domainerrors.Violation{Field: "CategoryID", Message: "smart categories cannot hold bookmarks"}
@@ -27,7 +27,7 @@ func TestCreateUserBookmark_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("validation failed"))
 
-	h := command.NewCreateUserBookmark(nil, nil, nil, v)
+	h := command.NewCreateUserBookmark(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserBookmarkCmd{})
 
 	var ve *domainerrors.ValidationError
@@ -41,7 +41,7 @@ func TestCreateUserBookmark_Handle_InvalidIcon(t *testing.T) {
 	cmd := validBookmarkCmd()
 	cmd.Icon = "not-an-icon"
 
-	h := command.NewCreateUserBookmark(nil, nil, nil, v)
+	h := command.NewCreateUserBookmark(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", cmd)
 
 	var ve *domainerrors.ValidationError
@@ -56,7 +56,7 @@ func TestCreateUserBookmark_Handle_CategoryNotFound(t *testing.T) {
 	catRepo.On("Get", mock.Anything, uint(1)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
 
-	h := command.NewCreateUserBookmark(nil, catRepo, nil, v)
+	h := command.NewCreateUserBookmark(nil, catRepo, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
 
 	var nfe *domainerrors.NotFoundError
@@ -72,10 +72,12 @@ func TestCreateUserBookmark_Handle_ForbiddenWrongDashboard(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
 
-	h := command.NewCreateUserBookmark(dashRepo, catRepo, nil, v)
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, nil, noCategoryShare(), v)
 	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
 
 	var fe *domainerrors.ForbiddenError
@@ -91,7 +93,7 @@ func TestCreateUserBookmark_Handle_Success(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	bookmarkRepo := &repoMock.BookmarkRepository{}
@@ -99,7 +101,7 @@ func TestCreateUserBookmark_Handle_Success(t *testing.T) {
 		return r.CategoryID == 1 && r.DisplayName == "GitHub"
 	})).Return(nil)
 
-	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, v)
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
 
 	require.NoError(t, err)
@@ -115,15 +117,78 @@ func TestCreateUserBookmark_Handle_UpsertError(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	bookmarkRepo := &repoMock.BookmarkRepository{}
 	bookmarkRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, v)
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
 }
+
+func TestCreateUserBookmark_Handle_NormalizesTags(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return len(r.Tags) == 2 && r.Tags[0] == "k8s" && r.Tags[1] == "ops"
+	})).Return(nil)
+
+	cmd := validBookmarkCmd()
+	cmd.Tags = []string{"Ops", "k8s", "ops"}
+
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	require.NoError(t, err)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+func TestCreateUserBookmark_Handle_InvalidTag(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	cmd := validBookmarkCmd()
+	cmd.Tags = []string{"tag:k8s"}
+
+	h := command.NewCreateUserBookmark(nil, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateUserBookmark_Handle_SmartCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10, TagQuery: "tag:k8s"}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+
+	h := command.NewCreateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", validBookmarkCmd())
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	bookmarkRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
@@ -11,9 +11,15 @@ import (
 )
 
 // CreateUserCategoryCmd is the input for creating a new category.
+// DashboardID selects one of the user's dashboards; 0 means the default one.
+// A non-zero ParentID nests the category inside another category of that
+// dashboard. A TagQuery makes it a smart category.
 type CreateUserCategoryCmd struct {
+	DashboardID uint
+	ParentID    uint
 	DisplayName string `validate:"required"`
 	IsShelved   bool
+	TagQuery    string
 }
 
 // UserCategoryCreator handles the CreateUserCategoryCmd command.
@@ -22,20 +28,26 @@ type UserCategoryCreator interface {
 }
 
 type CreateUserCategory struct {
-	DashboardRepo domainrepo.DashboardRepository
-	CategoryRepo  domainrepo.CategoryRepository
-	Validator     validation.Validator
+	DashboardRepo      domainrepo.DashboardRepository
+	CategoryRepo       domainrepo.CategoryRepository
+	CategoryShareRepo  domainrepo.CategoryShareRepository
+	SharedCategoryRepo domainrepo.SharedCategoryRepository
+	Validator          validation.Validator
 }
 
 func NewCreateUserCategory(
 	dashboardRepo domainrepo.DashboardRepository,
 	categoryRepo domainrepo.CategoryRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	sharedCategoryRepo domainrepo.SharedCategoryRepository,
 	validator validation.Validator,
 ) *CreateUserCategory {
 	return &CreateUserCategory{
-		DashboardRepo: dashboardRepo,
-		CategoryRepo:  categoryRepo,
-		Validator:     validator,
+		DashboardRepo:      dashboardRepo,
+		CategoryRepo:       categoryRepo,
+		CategoryShareRepo:  categoryShareRepo,
+		SharedCategoryRepo: sharedCategoryRepo,
+		Validator:          validator,
 	}
 }
 
@@ -44,11 +56,10 @@ func (h *CreateUserCategory) Handle(ctx context.Context, userId string, in Creat
 		return domainerrors.Validation(validation.ToViolations(err)...)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
+	dash, err := ownedDashboard(ctx, h.DashboardRepo, "create user category: get dashboard", userId, in.DashboardID)
 	if err != nil {
-		return domainerrors.WrapRepo("create user category: get dashboard", err)
+		return err
 	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
 
 	cat, err := domainmodel.NewCategory(in.DisplayName)
 	if err != nil {
@@ -57,13 +68,64 @@ func (h *CreateUserCategory) Handle(ctx context.Context, userId string, in Creat
 	if in.IsShelved {
 		cat.Shelve()
 	}
+	if err := cat.Nest(in.ParentID); err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IsShelved", Message: err.Error()})
+	}
+	if err := cat.Query(in.TagQuery); err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "TagQuery", Message: err.Error()})
+	}
+
+	if cat.ParentID != 0 {
+		categories, err := h.CategoryRepo.ListByDashboardID(ctx, dash.ID())
+		if err != nil {
+			return domainerrors.Internal("create user category: list categories", err)
+		}
+		if err := categoryTree(categories).CheckParent(0, cat.ParentID, 1); err != nil {
+			return domainerrors.Validation(domainerrors.Violation{Field: "ParentID", Message: err.Error()})
+		}
+		if err := checkUnsharedParent(ctx, h.CategoryShareRepo, h.SharedCategoryRepo, "create user category", cat.ParentID); err != nil {
+			return err
+		}
+	}
 
 	if err := h.CategoryRepo.Upsert(ctx, &domainrepo.CategoryRecord{
 		DashboardID: dash.ID(),
+		ParentID:    cat.ParentID,
 		DisplayName: cat.DisplayName,
 		IsShelved:   cat.IsShelved,
+		TagQuery:    cat.TagQuery,
 	}); err != nil {
 		return domainerrors.Internal("create user category: upsert", err)
 	}
 	return nil
 }
+
+// ownedDashboard loads the dashboard with the given id, or the user's default
+// dashboard when id is 0, and checks that it belongs to the user. op prefixes
+// repository errors.
+func ownedDashboard(ctx context.Context, dashboards domainrepo.DashboardRepository, op string, userId string, id uint) (domainmodel.UserDashboard, error) {
+	var record *domainrepo.DashboardRecord
+	var err error
+	if id == 0 {
+		record, err = dashboards.GetDefault(ctx, userId)
+	} else {
+		record, err = dashboards.Get(ctx, id)
+	}
+	if err != nil {
+		return domainmodel.UserDashboard{}, domainerrors.WrapRepo(op, err)
+	}
+	dash := domainmodel.NewUserDashboard(record.ID, record.UserID)
+	if !dash.OwnedBy(userId) {
+		return domainmodel.UserDashboard{}, domainerrors.Forbidden("user does not own dashboard")
+	}
+	return dash, nil
+}
+
+// categoryTree maps the categories of a dashboard to their parents.
+func categoryTree(categories []domainrepo.CategoryRecord) domainmodel.CategoryTree {
+	tree := make(domainmodel.CategoryTree, len(categories))
+	for _, c := range categories {
+		tree[c.ID] = c.ParentID
+	}
+	return tree
+}
@@ -19,7 +19,7 @@ func TestCreateUserCategory_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("validation failed"))
 
-	h := command.NewCreateUserCategory(nil, nil, v)
+	h := command.NewCreateUserCategory(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{})
 
 	var ve *domainerrors.ValidationError
@@ -31,10 +31,10 @@ func TestCreateUserCategory_Handle_DashboardNotFound(t *testing.T) {
 	v.On("Struct", mock.Anything).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
-	h := command.NewCreateUserCategory(dashRepo, nil, v)
+	h := command.NewCreateUserCategory(dashRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Work"})
 
 	var nfe *domainerrors.NotFoundError
@@ -46,10 +46,10 @@ func TestCreateUserCategory_Handle_DashboardRepoError(t *testing.T) {
 	v.On("Struct", mock.Anything).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, errors.New("db error"))
 
-	h := command.NewCreateUserCategory(dashRepo, nil, v)
+	h := command.NewCreateUserCategory(dashRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Work"})
 
 	var ie *domainerrors.InternalError
@@ -61,7 +61,7 @@ func TestCreateUserCategory_Handle_Success(t *testing.T) {
 	v.On("Struct", mock.Anything).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -69,7 +69,7 @@ func TestCreateUserCategory_Handle_Success(t *testing.T) {
 		return r.DashboardID == 10 && r.DisplayName == "Work" && !r.IsShelved
 	})).Return(nil)
 
-	h := command.NewCreateUserCategory(dashRepo, catRepo, v)
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Work"})
 
 	require.NoError(t, err)
@@ -81,7 +81,7 @@ func TestCreateUserCategory_Handle_Shelved(t *testing.T) {
 	v.On("Struct", mock.Anything).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -89,24 +89,133 @@ func TestCreateUserCategory_Handle_Shelved(t *testing.T) {
 		return r.IsShelved
 	})).Return(nil)
 
-	h := command.NewCreateUserCategory(dashRepo, catRepo, v)
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Archive", IsShelved: true})
 
 	require.NoError(t, err)
 }
 
+func TestCreateUserCategory_Handle_Nested(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 2, DashboardID: 10, ParentID: 1, DisplayName: "Proxmox"},
+	}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DashboardID == 10 && r.ParentID == 2 && r.DisplayName == "Nodes"
+	})).Return(nil)
+	shareRepo, sharedRepo := unsharedParents()
+
+	h := command.NewCreateUserCategory(dashRepo, catRepo, shareRepo, sharedRepo, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Nodes", ParentID: 2})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestCreateUserCategory_Handle_NestedBelowSharedCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Runbooks"},
+	}, nil)
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByCategory", mock.Anything, uint(1)).Return([]domainrepo.CategoryShareRecord{
+		{CategoryID: 1, UserID: "user-2", Role: "viewer"},
+	}, nil)
+
+	h := command.NewCreateUserCategory(dashRepo, catRepo, shareRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Databases", ParentID: 1})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserCategory_Handle_NestedTooDeep(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 2, DashboardID: 10, ParentID: 1, DisplayName: "Proxmox"},
+		{ID: 3, DashboardID: 10, ParentID: 2, DisplayName: "Nodes"},
+	}, nil)
+
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Disks", ParentID: 3})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserCategory_Handle_ParentOfOtherDashboard(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+	}, nil)
+
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Nodes", ParentID: 7})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserCategory_Handle_ShelvedSubCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewCreateUserCategory(dashRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Nodes", ParentID: 1, IsShelved: true})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
 func TestCreateUserCategory_Handle_UpsertError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewCreateUserCategory(dashRepo, catRepo, v)
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Work"})
 
 	var ie *domainerrors.InternalError
@@ -114,3 +223,38 @@ func TestCreateUserCategory_Handle_UpsertError(t *testing.T) {
 
 	_ = assert.Error // satisfy import
 }
+
+func TestCreateUserCategory_Handle_Smart(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.TagQuery == "tag:k8s AND NOT tag:legacy"
+	})).Return(nil)
+
+	h := command.NewCreateUserCategory(dashRepo, catRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Kubernetes", TagQuery: "tag:k8s and not tag:legacy"})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestCreateUserCategory_Handle_InvalidTagQuery(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewCreateUserCategory(dashRepo, &repoMock.CategoryRepository{}, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.CreateUserCategoryCmd{DisplayName: "Kubernetes", TagQuery: "k8s AND"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CreateUserDashboardCmd is the input for creating another dashboard.
+type CreateUserDashboardCmd struct {
+	Name string `validate:"required"`
+}
+
+// UserDashboardCreator handles the CreateUserDashboardCmd command.
+type UserDashboardCreator interface {
+	Handle(ctx context.Context, userId string, in CreateUserDashboardCmd) (uint, error)
+}
+
+type CreateUserDashboard struct {
+	DashboardRepo domainrepo.DashboardRepository
+	Validator     validation.Validator
+}
+
+func NewCreateUserDashboard(dashboardRepo domainrepo.DashboardRepository, validator validation.Validator) *CreateUserDashboard {
+	return &CreateUserDashboard{DashboardRepo: dashboardRepo, Validator: validator}
+}
+
+// Handle appends a dashboard to the user's dashboards and returns its id. The
+// first dashboard of a user becomes their default.
+func (h *CreateUserDashboard) Handle(ctx context.Context, userId string, in CreateUserDashboardCmd) (uint, error) {
+	if err := h.Validator.Struct(in); err != nil {
+		return 0, domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	existing, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return 0, domainerrors.Internal("create user dashboard: list dashboards", err)
+	}
+	name, err := dashboardName(existing, 0, in.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	record := &domainrepo.DashboardRecord{
+		UserID:    userId,
+		Name:      name,
+		IsDefault: len(existing) == 0,
+	}
+	if err := h.DashboardRepo.Upsert(ctx, record); err != nil {
+		return 0, domainerrors.Internal("create user dashboard: upsert", err)
+	}
+	return record.ID, nil
+}
+
+// dashboardName validates a dashboard name and returns it normalized. The
+// name must not be taken by one of the user's dashboards other than id.
+func dashboardName(existing []domainrepo.DashboardRecord, id uint, raw string) (string, error) {
+	name, err := domainmodel.ParseDashboardName(raw)
+	if err != nil {
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "Name", Message: err.Error()})
+	}
+	for _, d := range existing {
+		if d.ID != id && d.Name == name {
+			return "", domainerrors.Validation(domainerrors.Violation{Field: "Name", Message: "taken"})
+		}
+	}
+	return name, nil
+}
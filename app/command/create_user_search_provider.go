@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// CreateUserSearchProviderCmd is the input for creating a new search provider.
+type CreateUserSearchProviderCmd struct {
+	DisplayName string `validate:"required,max=64"`
+	Bang        string `validate:"required"`
+	URLTemplate string `validate:"required"`
+}
+
+// UserSearchProviderCreator handles the CreateUserSearchProviderCmd command.
+type UserSearchProviderCreator interface {
+	Handle(ctx context.Context, userID string, in CreateUserSearchProviderCmd) error
+}
+
+type CreateUserSearchProvider struct {
+	Repo        domainrepo.SearchProviderRepository
+	SettingRepo domainrepo.SettingRepository
+	Validator   validation.Validator
+}
+
+func NewCreateUserSearchProvider(
+	r domainrepo.SearchProviderRepository,
+	s domainrepo.SettingRepository,
+	v validation.Validator,
+) *CreateUserSearchProvider {
+	return &CreateUserSearchProvider{
+		Repo:        r,
+		SettingRepo: s,
+		Validator:   v,
+	}
+}
+
+// Handle creates the provider. The first provider of a user, or one created
+// while the default no longer exists, becomes the default.
+func (h *CreateUserSearchProvider) Handle(ctx context.Context, userID string, in CreateUserSearchProviderCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	bang, err := domainmodel.ParseBang(in.Bang)
+	if err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Bang", Message: err.Error()})
+	}
+	if err := domainmodel.ValidateSearchURLTemplate(in.URLTemplate); err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "URLTemplate", Message: err.Error()})
+	}
+
+	existing, err := h.Repo.ListByUser(ctx, userID)
+	if err != nil {
+		return domainerrors.Internal("create user search provider: list", err)
+	}
+	for _, p := range existing {
+		if p.Bang == bang {
+			return domainerrors.Validation(domainerrors.Violation{Field: "Bang", Message: "already in use"})
+		}
+	}
+
+	record := &domainrepo.SearchProviderRecord{
+		UserID:      userID,
+		DisplayName: in.DisplayName,
+		Bang:        bang,
+		URLTemplate: in.URLTemplate,
+	}
+	if err := h.Repo.Create(ctx, record); err != nil {
+		return domainerrors.Internal("create user search provider: create", err)
+	}
+
+	setting, err := h.SettingRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if !errors.As(err, &nfe) {
+			return domainerrors.Internal("create user search provider: get settings", err)
+		}
+		setting = &domainrepo.SettingRecord{UserID: userID}
+	}
+	for _, p := range existing {
+		if p.ID == setting.SearchProviderID {
+			return nil
+		}
+	}
+	setting.SearchProviderID = record.ID
+	if err := h.SettingRepo.Upsert(ctx, setting); err != nil {
+		return domainerrors.Internal("create user search provider: upsert settings", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func okValidator() *repoMock.Validator {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+	return v
+}
+
+// ── UploadCustomIcon ───────────────────────────────────────────────────────
+
+func TestUploadCustomIcon_Handle_StoresProcessedImage(t *testing.T) {
+	processor := &repoMock.CustomIconProcessor{}
+	processor.On("Process", []byte("raw")).Return("image/png", []byte("png"), nil)
+
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.CustomIconRecord) bool {
+		return r.UserID == "user-1" && r.DisplayName == "NAS" && r.ContentType == "image/png" && string(r.Data) == "png"
+	})).Return(nil)
+
+	h := command.NewUploadCustomIcon(repo, processor, okValidator())
+	err := h.Handle(context.Background(), "user-1", false, command.UploadCustomIconCmd{DisplayName: "NAS", Data: []byte("raw")})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUploadCustomIcon_Handle_SharedByAdmin(t *testing.T) {
+	processor := &repoMock.CustomIconProcessor{}
+	processor.On("Process", mock.Anything).Return("image/svg+xml", []byte("<svg/>"), nil)
+
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.CustomIconRecord) bool {
+		return r.UserID == ""
+	})).Return(nil)
+
+	h := command.NewUploadCustomIcon(repo, processor, okValidator())
+	err := h.Handle(context.Background(), "admin", true, command.UploadCustomIconCmd{DisplayName: "Proxmox", Data: []byte("<svg/>"), IsShared: true})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUploadCustomIcon_Handle_SharedRequiresAdmin(t *testing.T) {
+	h := command.NewUploadCustomIcon(nil, nil, okValidator())
+	err := h.Handle(context.Background(), "user-1", false, command.UploadCustomIconCmd{DisplayName: "NAS", Data: []byte("raw"), IsShared: true})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestUploadCustomIcon_Handle_TooLarge(t *testing.T) {
+	h := command.NewUploadCustomIcon(nil, nil, okValidator())
+	err := h.Handle(context.Background(), "user-1", false, command.UploadCustomIconCmd{
+		DisplayName: "NAS",
+		Data:        make([]byte, domainmodel.MaxCustomIconBytes+1),
+	})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Equal(t, "Data", ve.Violations[0].Field)
+}
+
+func TestUploadCustomIcon_Handle_UnsupportedImage(t *testing.T) {
+	processor := &repoMock.CustomIconProcessor{}
+	processor.On("Process", mock.Anything).Return("", nil, errors.New("must be a PNG, JPEG or SVG image"))
+	repo := &repoMock.CustomIconRepository{}
+
+	h := command.NewUploadCustomIcon(repo, processor, okValidator())
+	err := h.Handle(context.Background(), "user-1", false, command.UploadCustomIconCmd{DisplayName: "NAS", Data: []byte("GIF89a")})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// ── DeleteCustomIcon ───────────────────────────────────────────────────────
+
+func TestDeleteCustomIcon_Handle_Own(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("GetByID", mock.Anything, uint(3)).Return(&domainrepo.CustomIconRecord{ID: 3, UserID: "user-1"}, nil)
+	repo.On("Delete", mock.Anything, "user-1", uint(3)).Return(nil)
+
+	err := command.NewDeleteCustomIcon(repo).Handle(context.Background(), "user-1", false, 3)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteCustomIcon_Handle_OtherUsersIconIsIgnored(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("GetByID", mock.Anything, uint(3)).Return(&domainrepo.CustomIconRecord{ID: 3, UserID: "user-2"}, nil)
+
+	err := command.NewDeleteCustomIcon(repo).Handle(context.Background(), "user-1", true, 3)
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteCustomIcon_Handle_SharedRequiresAdmin(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("GetByID", mock.Anything, uint(3)).Return(&domainrepo.CustomIconRecord{ID: 3}, nil)
+	repo.On("Delete", mock.Anything, "", uint(3)).Return(nil)
+
+	err := command.NewDeleteCustomIcon(repo).Handle(context.Background(), "user-1", false, 3)
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+
+	err = command.NewDeleteCustomIcon(repo).Handle(context.Background(), "admin", true, 3)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
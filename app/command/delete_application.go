@@ -4,29 +4,31 @@ import (
 	"context"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
 // ApplicationDeleter handles the delete-application command.
 // Applications are admin-managed, so there is no user-ownership check.
 type ApplicationDeleter interface {
-	Handle(ctx context.Context, id uint) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, id uint) error
 }
 
 type DeleteApplication struct {
 	ApplicationRepo domainrepo.ApplicationRepository
+	Audit           AuditRecorder
 }
 
-func NewDeleteApplication(applicationRepo domainrepo.ApplicationRepository) *DeleteApplication {
-	return &DeleteApplication{ApplicationRepo: applicationRepo}
+func NewDeleteApplication(applicationRepo domainrepo.ApplicationRepository, audit AuditRecorder) *DeleteApplication {
+	return &DeleteApplication{ApplicationRepo: applicationRepo, Audit: audit}
 }
 
-func (h *DeleteApplication) Handle(ctx context.Context, id uint) error {
+func (h *DeleteApplication) Handle(ctx context.Context, actor domainmodel.AuditActor, id uint) error {
 	if id == 0 {
 		return domainerrors.Validation(domainerrors.Violation{Message: "id is required"})
 	}
 
-	_, err := h.ApplicationRepo.Get(ctx, id)
+	app, err := h.ApplicationRepo.Get(ctx, id)
 	if err != nil {
 		return domainerrors.WrapRepo("delete application: get", err)
 	}
@@ -34,5 +36,6 @@ func (h *DeleteApplication) Handle(ctx context.Context, id uint) error {
 	if err := h.ApplicationRepo.Delete(ctx, id); err != nil {
 		return domainerrors.Internal("delete application: delete", err)
 	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionApplicationDelete, app.DisplayName)
 	return nil
 }
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CustomIconDeleter handles the delete-custom-icon command.
+type CustomIconDeleter interface {
+	Handle(ctx context.Context, userID string, isAdmin bool, id uint) error
+}
+
+type DeleteCustomIcon struct {
+	Repo domainrepo.CustomIconRepository
+}
+
+func NewDeleteCustomIcon(r domainrepo.CustomIconRepository) *DeleteCustomIcon {
+	return &DeleteCustomIcon{Repo: r}
+}
+
+// Handle deletes one of the user's icons or, for admins, a shared icon.
+// Bookmarks and applications still referring to it fall back to an empty
+// image.
+func (h *DeleteCustomIcon) Handle(ctx context.Context, userID string, isAdmin bool, id uint) error {
+	icon, err := h.Repo.GetByID(ctx, id)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if errors.As(err, &nfe) {
+			return nil
+		}
+		return domainerrors.Internal("delete custom icon: get by id", err)
+	}
+
+	switch {
+	case icon.UserID == "" && !isAdmin:
+		return domainerrors.Forbidden("only admins may delete shared icons")
+	case icon.UserID != "" && icon.UserID != userID:
+		return nil
+	}
+
+	if err := h.Repo.Delete(ctx, icon.UserID, id); err != nil {
+		return domainerrors.Internal("delete custom icon: delete", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// InvitationDeleter handles the delete-invitation command.
+type InvitationDeleter interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, id string) error
+}
+
+type DeleteInvitation struct {
+	Repo  domainrepo.InvitationRepository
+	Audit AuditRecorder
+}
+
+func NewDeleteInvitation(repo domainrepo.InvitationRepository, audit AuditRecorder) *DeleteInvitation {
+	return &DeleteInvitation{Repo: repo, Audit: audit}
+}
+
+// Handle deletes an invitation. The link stops working and every guest who
+// redeemed it loses the granted groups.
+func (h *DeleteInvitation) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, id string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may invite guests")
+	}
+	if err := h.Repo.Delete(ctx, id); err != nil {
+		return domainerrors.WrapRepo("delete invitation", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionInvitationRevoke, id)
+	return nil
+}
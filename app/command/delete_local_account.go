@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalAccountDeleter handles the delete-local-account command.
+type LocalAccountDeleter interface {
+	Handle(ctx context.Context, isAdmin bool, username string) error
+}
+
+type DeleteLocalAccount struct {
+	Repo        domainrepo.LocalAccountRepository
+	SessionRepo domainrepo.SessionRepository
+	Issuer      string // issuer of local account sessions
+}
+
+func NewDeleteLocalAccount(repo domainrepo.LocalAccountRepository, sessionRepo domainrepo.SessionRepository, issuer string) *DeleteLocalAccount {
+	return &DeleteLocalAccount{Repo: repo, SessionRepo: sessionRepo, Issuer: issuer}
+}
+
+// Handle removes a local account and ends its sessions. The dashboard data of
+// the account is kept: it belongs to the internal user, which may have other
+// linked identities, and comes back if the username is created again.
+func (h *DeleteLocalAccount) Handle(ctx context.Context, isAdmin bool, username string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage local accounts")
+	}
+
+	account, err := h.Repo.GetByUsername(ctx, username)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if errors.As(err, &nfe) {
+			return nil
+		}
+		return domainerrors.Internal("delete local account: get by username", err)
+	}
+	if account.Managed {
+		return domainerrors.Forbidden("accounts from the users file cannot be changed")
+	}
+
+	if err := h.Repo.Delete(ctx, account.Username); err != nil {
+		return domainerrors.WrapRepo("delete local account: delete", err)
+	}
+	if err := h.SessionRepo.DeleteBySub(ctx, h.Issuer, account.Username); err != nil {
+		return domainerrors.Internal("delete local account: delete sessions", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalGroupDeleter handles the delete-local-group command.
+type LocalGroupDeleter interface {
+	Handle(ctx context.Context, isAdmin bool, id uint) error
+}
+
+type DeleteLocalGroup struct {
+	Repo domainrepo.LocalGroupRepository
+}
+
+func NewDeleteLocalGroup(repo domainrepo.LocalGroupRepository) *DeleteLocalGroup {
+	return &DeleteLocalGroup{Repo: repo}
+}
+
+// Handle removes a local group together with its memberships.
+func (h *DeleteLocalGroup) Handle(ctx context.Context, isAdmin bool, id uint) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage groups")
+	}
+	if err := h.Repo.Delete(ctx, id); err != nil {
+		return domainerrors.WrapRepo("delete local group", err)
+	}
+	return nil
+}
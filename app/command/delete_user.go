@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+)
+
+// UserDeleter handles the admin command that deletes another user's account.
+type UserDeleter interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, userID string) error
+}
+
+type DeleteUser struct {
+	DeleteUserData UserDataDeleter
+}
+
+func NewDeleteUser(deleteUserData UserDataDeleter) *DeleteUser {
+	return &DeleteUser{DeleteUserData: deleteUserData}
+}
+
+// Handle deletes the user and all of their data. Admins delete their own
+// account from the data settings instead, so they cannot lock themselves out
+// by accident here.
+func (h *DeleteUser) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, userID string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage users")
+	}
+	if actor.UserID == userID {
+		return domainerrors.Validation(domainerrors.Violation{Field: "user", Message: "cannot delete your own account here"})
+	}
+	return h.DeleteUserData.Handle(ctx, actor, userID)
+}
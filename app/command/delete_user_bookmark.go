@@ -4,7 +4,6 @@ import (
 	"context"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
-	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
@@ -14,20 +13,23 @@ type UserBookmarkDeleter interface {
 }
 
 type DeleteUserBookmark struct {
-	DashboardRepo domainrepo.DashboardRepository
-	CategoryRepo  domainrepo.CategoryRepository
-	BookmarkRepo  domainrepo.BookmarkRepository
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
 }
 
 func NewDeleteUserBookmark(
 	dashboardRepo domainrepo.DashboardRepository,
 	categoryRepo domainrepo.CategoryRepository,
 	bookmarkRepo domainrepo.BookmarkRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
 ) *DeleteUserBookmark {
 	return &DeleteUserBookmark{
-		DashboardRepo: dashboardRepo,
-		CategoryRepo:  categoryRepo,
-		BookmarkRepo:  bookmarkRepo,
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		BookmarkRepo:      bookmarkRepo,
+		CategoryShareRepo: categoryShareRepo,
 	}
 }
 
@@ -46,13 +48,12 @@ func (h *DeleteUserBookmark) Handle(ctx context.Context, userId string, id uint)
 		return domainerrors.WrapRepo("delete user bookmark: get category", err)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
+	canEdit, err := canEditBookmarks(ctx, h.DashboardRepo, h.CategoryShareRepo, userId, catRecord)
 	if err != nil {
-		return domainerrors.WrapRepo("delete user bookmark: get dashboard", err)
+		return domainerrors.WrapRepo("delete user bookmark: check category access", err)
 	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return domainerrors.Forbidden("user does not own dashboard")
+	if !canEdit {
+		return domainerrors.Forbidden("user may not edit bookmarks of category")
 	}
 
 	if err := h.BookmarkRepo.Delete(ctx, id); err != nil {
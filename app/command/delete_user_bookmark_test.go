@@ -15,7 +15,7 @@ import (
 )
 
 func TestDeleteUserBookmark_Handle_ZeroID(t *testing.T) {
-	h := command.NewDeleteUserBookmark(nil, nil, nil)
+	h := command.NewDeleteUserBookmark(nil, nil, nil, nil)
 	err := h.Handle(context.Background(), "user-1", 0)
 
 	var ve *domainerrors.ValidationError
@@ -27,7 +27,7 @@ func TestDeleteUserBookmark_Handle_BookmarkNotFound(t *testing.T) {
 	bookmarkRepo.On("Get", mock.Anything, uint(5)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityBookmark))
 
-	h := command.NewDeleteUserBookmark(nil, nil, bookmarkRepo)
+	h := command.NewDeleteUserBookmark(nil, nil, bookmarkRepo, nil)
 	err := h.Handle(context.Background(), "user-1", 5)
 
 	var nfe *domainerrors.NotFoundError
@@ -44,10 +44,12 @@ func TestDeleteUserBookmark_Handle_ForbiddenWrongOwner(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
 
-	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo)
+	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo, noCategoryShare())
 	err := h.Handle(context.Background(), "user-1", 5)
 
 	var fe *domainerrors.ForbiddenError
@@ -65,10 +67,10 @@ func TestDeleteUserBookmark_Handle_DeleteError(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo)
+	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo, nil)
 	err := h.Handle(context.Background(), "user-1", 5)
 
 	var ie *domainerrors.InternalError
@@ -86,10 +88,10 @@ func TestDeleteUserBookmark_Handle_Success(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo)
+	h := command.NewDeleteUserBookmark(dashRepo, catRepo, bookmarkRepo, nil)
 	err := h.Handle(context.Background(), "user-1", 5)
 
 	require.NoError(t, err)
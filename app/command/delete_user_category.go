@@ -4,7 +4,6 @@ import (
 	"context"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
-	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
@@ -38,13 +37,8 @@ func (h *DeleteUserCategory) Handle(ctx context.Context, userId string, id uint)
 		return domainerrors.WrapRepo("delete user category: get category", err)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
-	if err != nil {
-		return domainerrors.WrapRepo("delete user category: get dashboard", err)
-	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return domainerrors.Forbidden("user does not own dashboard")
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "delete user category: get dashboard", userId, catRecord.DashboardID); err != nil {
+		return err
 	}
 
 	if err := h.CategoryRepo.Delete(ctx, id); err != nil {
@@ -40,8 +40,10 @@ func TestDeleteUserCategory_Handle_ForbiddenWrongOwner(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
 
 	h := command.NewDeleteUserCategory(dashRepo, catRepo)
 	err := h.Handle(context.Background(), "user-1", 5)
@@ -57,7 +59,7 @@ func TestDeleteUserCategory_Handle_DeleteError(t *testing.T) {
 	catRepo.On("Delete", mock.Anything, uint(5)).Return(errors.New("db error"))
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	h := command.NewDeleteUserCategory(dashRepo, catRepo)
@@ -74,7 +76,7 @@ func TestDeleteUserCategory_Handle_Success(t *testing.T) {
 	catRepo.On("Delete", mock.Anything, uint(5)).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	h := command.NewDeleteUserCategory(dashRepo, catRepo)
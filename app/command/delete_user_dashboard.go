@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserDashboardDeleter handles the delete-user-dashboard command.
+type UserDashboardDeleter interface {
+	Handle(ctx context.Context, userId string, id uint) error
+}
+
+type DeleteUserDashboard struct {
+	DashboardRepo domainrepo.DashboardRepository
+}
+
+func NewDeleteUserDashboard(dashboardRepo domainrepo.DashboardRepository) *DeleteUserDashboard {
+	return &DeleteUserDashboard{DashboardRepo: dashboardRepo}
+}
+
+// Handle deletes one of the user's dashboards along with its categories and
+// bookmarks. Users keep at least one dashboard; when the default one is
+// deleted, the first remaining dashboard takes its place.
+func (h *DeleteUserDashboard) Handle(ctx context.Context, userId string, id uint) error {
+	if id == 0 {
+		return domainerrors.Validation(domainerrors.Violation{Message: "id is required"})
+	}
+
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "delete user dashboard: get dashboard", userId, id); err != nil {
+		return err
+	}
+	existing, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return domainerrors.Internal("delete user dashboard: list dashboards", err)
+	}
+	if len(existing) <= 1 {
+		return domainerrors.Validation(domainerrors.Violation{Message: "the last dashboard cannot be deleted"})
+	}
+
+	if err := h.DashboardRepo.Delete(ctx, id); err != nil {
+		return domainerrors.Internal("delete user dashboard: delete", err)
+	}
+	return nil
+}
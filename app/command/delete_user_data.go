@@ -4,27 +4,32 @@ import (
 	"context"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
-// UserDataDeleter handles the delete-user-data command.
+// UserDataDeleter handles the delete-user-data command. The actor is the user
+// themselves or, for the admin user management, an admin.
 type UserDataDeleter interface {
-	Handle(ctx context.Context, userID string) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, userID string) error
 }
 
 type DeleteUserData struct {
 	UserRepo domainrepo.UserRepository
+	Audit    AuditRecorder
 }
 
-func NewDeleteUserData(userRepo domainrepo.UserRepository) *DeleteUserData {
-	return &DeleteUserData{UserRepo: userRepo}
+func NewDeleteUserData(userRepo domainrepo.UserRepository, audit AuditRecorder) *DeleteUserData {
+	return &DeleteUserData{UserRepo: userRepo, Audit: audit}
 }
 
-func (h *DeleteUserData) Handle(ctx context.Context, userID string) error {
+func (h *DeleteUserData) Handle(ctx context.Context, actor domainmodel.AuditActor, userID string) error {
 	// Deleting the users row cascades to all dependent tables via FK constraints:
-	// dashboards (→ categories → bookmarks), settings, themes, sessions, idp_links.
+	// dashboards (→ categories → bookmarks), settings, themes, sessions, idp_links,
+	// access_tokens.
 	if err := h.UserRepo.DeleteByID(ctx, userID); err != nil {
 		return domainerrors.Internal("delete user data", err)
 	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionUserDelete, userID)
 	return nil
 }
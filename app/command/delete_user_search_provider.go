@@ -0,0 +1,31 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserSearchProviderDeleter handles the delete-user-search-provider command.
+type UserSearchProviderDeleter interface {
+	Handle(ctx context.Context, userID string, id uint) error
+}
+
+type DeleteUserSearchProvider struct {
+	Repo domainrepo.SearchProviderRepository
+}
+
+func NewDeleteUserSearchProvider(r domainrepo.SearchProviderRepository) *DeleteUserSearchProvider {
+	return &DeleteUserSearchProvider{Repo: r}
+}
+
+// Handle deletes the provider. Deleting the default provider is allowed; the
+// settings then point to a provider that no longer exists, which reads as
+// "no default".
+func (h *DeleteUserSearchProvider) Handle(ctx context.Context, userID string, id uint) error {
+	if err := h.Repo.Delete(ctx, userID, id); err != nil {
+		return domainerrors.Internal("delete user search provider", err)
+	}
+	return nil
+}
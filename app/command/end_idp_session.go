@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// EndIdpSessionCmd identifies the sessions to end after the user signed out
+// at the identity provider. Sid names one IdP session; without it, every
+// session of the Sub identity is ended.
+type EndIdpSessionCmd struct {
+	Issuer string
+	Sub    string
+	Sid    string
+}
+
+// IdpSessionEnder handles back-channel and front-channel logout requests.
+type IdpSessionEnder interface {
+	Handle(ctx context.Context, cmd EndIdpSessionCmd) error
+}
+
+type EndIdpSession struct {
+	Repo domainrepo.SessionRepository
+}
+
+func NewEndIdpSession(repo domainrepo.SessionRepository) *EndIdpSession {
+	return &EndIdpSession{Repo: repo}
+}
+
+func (h *EndIdpSession) Handle(ctx context.Context, cmd EndIdpSessionCmd) error {
+	switch {
+	case cmd.Issuer == "":
+		return domainerrors.Validation(domainerrors.Violation{Field: "iss", Message: "required"})
+	case cmd.Sid != "":
+		return h.Repo.DeleteBySid(ctx, cmd.Issuer, cmd.Sid)
+	case cmd.Sub != "":
+		return h.Repo.DeleteBySub(ctx, cmd.Issuer, cmd.Sub)
+	default:
+		return domainerrors.Validation(domainerrors.Violation{Message: "sub or sid is required"})
+	}
+}
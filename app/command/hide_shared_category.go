@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// SharedCategoryHider handles the hide-shared-category command.
+type SharedCategoryHider interface {
+	Handle(ctx context.Context, userId string, categoryID uint) error
+}
+
+type HideSharedCategory struct {
+	Repo domainrepo.SharedCategoryRepository
+}
+
+func NewHideSharedCategory(repo domainrepo.SharedCategoryRepository) *HideSharedCategory {
+	return &HideSharedCategory{Repo: repo}
+}
+
+// Handle hides a published category from the user's dashboard and search.
+func (h *HideSharedCategory) Handle(ctx context.Context, userId string, categoryID uint) error {
+	if err := h.Repo.Hide(ctx, userId, categoryID); err != nil {
+		return domainerrors.WrapRepo("hide shared category", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/transfer"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserBookmarksImporter handles the import-user-bookmarks command, which adds
+// categories and bookmarks from a foreign source (e.g. a browser bookmark file)
+// without touching the user's themes or settings.
+type UserBookmarksImporter interface {
+	Handle(ctx context.Context, actor domainmodel.AuditActor, categories []transfer.CategoryExport) error
+}
+
+type ImportUserBookmarks struct {
+	DashboardRepo domainrepo.DashboardRepository
+	CategoryRepo  domainrepo.CategoryRepository
+	BookmarkRepo  domainrepo.BookmarkRepository
+	Audit         AuditRecorder
+}
+
+func NewImportUserBookmarks(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	audit AuditRecorder,
+) *ImportUserBookmarks {
+	return &ImportUserBookmarks{
+		DashboardRepo: dashboardRepo,
+		CategoryRepo:  categoryRepo,
+		BookmarkRepo:  bookmarkRepo,
+		Audit:         audit,
+	}
+}
+
+// Handle adds the categories to the actor's default dashboard.
+func (h *ImportUserBookmarks) Handle(ctx context.Context, actor domainmodel.AuditActor, categories []transfer.CategoryExport) error {
+	if err := h.importBookmarks(ctx, actor.UserID, categories); err != nil {
+		return err
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionDataImport, "bookmarks")
+	return nil
+}
+
+func (h *ImportUserBookmarks) importBookmarks(ctx context.Context, userID string, categories []transfer.CategoryExport) error {
+	if err := checkCategoryExports(categories); err != nil {
+		return err
+	}
+	existingCategoryHashes := map[string]uint{}
+	dashboard, err := h.DashboardRepo.GetDefault(ctx, userID)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if !errors.As(err, &nfe) {
+			return domainerrors.Internal("import user bookmarks: get dashboard", err)
+		}
+		dashboard = &domainrepo.DashboardRecord{UserID: userID, Name: domainmodel.DefaultDashboardName, IsDefault: true}
+		if err := h.DashboardRepo.Upsert(ctx, dashboard); err != nil {
+			return domainerrors.Internal("import user bookmarks: upsert dashboard", err)
+		}
+	} else if existingCategoryHashes, err = categoryHashes(ctx, "import user bookmarks", h.CategoryRepo, dashboard.ID); err != nil {
+		return err
+	}
+
+	return importCategories(ctx, "import user bookmarks", h.CategoryRepo, h.BookmarkRepo, dashboard.ID, existingCategoryHashes, categories)
+}
@@ -0,0 +1,102 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/transfer"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func netscapeCategory(name string, bookmarks ...string) transfer.CategoryExport {
+	cat := transfer.CategoryExport{
+		Hash:        transfer.ContentHash(name, "false"),
+		DisplayName: name,
+	}
+	for _, b := range bookmarks {
+		url := "https://" + b + ".example.com"
+		cat.Bookmarks = append(cat.Bookmarks, transfer.BookmarkExport{
+			Hash:        transfer.ContentHash(transfer.NetscapeBookmarkIcon, b, url),
+			Icon:        transfer.NetscapeBookmarkIcon,
+			DisplayName: b,
+			URL:         url,
+		})
+	}
+	return cat
+}
+
+func TestImportUserBookmarks_Handle_DashboardRepoError(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+
+	h := command.NewImportUserBookmarks(dashRepo, nil, nil, nil)
+	err := h.Handle(context.Background(), testActor, nil)
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestImportUserBookmarks_Handle_CreatesDashboard(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.UserID == "user-1" && r.IsDefault
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.DashboardRecord).ID = 10
+	}).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DashboardID == 10 && r.DisplayName == "Dev"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 5
+	}).Return(nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	auditRepo := expectAudit(domainmodel.AuditActionDataImport, "bookmarks")
+
+	h := command.NewImportUserBookmarks(dashRepo, catRepo, bookmarkRepo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, []transfer.CategoryExport{netscapeCategory("Dev", "github")})
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+	bookmarkRepo.AssertNumberOfCalls(t, "Upsert", 1)
+}
+
+func TestImportUserBookmarks_Handle_Idempotent(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).
+		Return([]domainrepo.CategoryRecord{{ID: 5, DashboardID: 10, DisplayName: "Dev"}}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).Return([]domainrepo.BookmarkRecord{
+		{ID: 1, CategoryID: 5, Icon: transfer.NetscapeBookmarkIcon, DisplayName: "github", Url: "https://github.example.com"},
+	}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.CategoryID == 5 && r.DisplayName == "gitlab"
+	})).Return(nil)
+
+	h := command.NewImportUserBookmarks(dashRepo, catRepo, bookmarkRepo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, []transfer.CategoryExport{netscapeCategory("Dev", "github", "gitlab")})
+
+	require.NoError(t, err)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	bookmarkRepo.AssertNumberOfCalls(t, "Upsert", 1)
+}
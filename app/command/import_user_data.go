@@ -3,7 +3,8 @@ package command
 import (
 	"context"
 	"errors"
-	"strconv"
+	"fmt"
+	"slices"
 	"strings"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/transfer"
@@ -14,16 +15,20 @@ import (
 
 // UserDataImporter handles the import-user-data command.
 type UserDataImporter interface {
-	Handle(ctx context.Context, userID string, isAdmin bool, in *transfer.UserDataExport) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, isAdmin bool, in *transfer.UserDataExport) error
 }
 
 type ImportUserData struct {
-	DashboardRepo   domainrepo.DashboardRepository
-	CategoryRepo    domainrepo.CategoryRepository
-	BookmarkRepo    domainrepo.BookmarkRepository
-	ThemeRepo       domainrepo.ThemeRepository
-	SettingRepo     domainrepo.SettingRepository
-	ApplicationRepo domainrepo.ApplicationRepository
+	DashboardRepo      domainrepo.DashboardRepository
+	CategoryRepo       domainrepo.CategoryRepository
+	BookmarkRepo       domainrepo.BookmarkRepository
+	ThemeRepo          domainrepo.ThemeRepository
+	SettingRepo        domainrepo.SettingRepository
+	ApplicationRepo    domainrepo.ApplicationRepository
+	SearchProviderRepo domainrepo.SearchProviderRepository
+	CustomIconRepo     domainrepo.CustomIconRepository
+	IconProcessor      CustomIconProcessor
+	Audit              AuditRecorder
 }
 
 func NewImportUserData(
@@ -33,18 +38,44 @@ func NewImportUserData(
 	themeRepo domainrepo.ThemeRepository,
 	settingRepo domainrepo.SettingRepository,
 	applicationRepo domainrepo.ApplicationRepository,
+	searchProviderRepo domainrepo.SearchProviderRepository,
+	customIconRepo domainrepo.CustomIconRepository,
+	iconProcessor CustomIconProcessor,
+	audit AuditRecorder,
 ) *ImportUserData {
 	return &ImportUserData{
-		DashboardRepo:   dashboardRepo,
-		CategoryRepo:    categoryRepo,
-		BookmarkRepo:    bookmarkRepo,
-		ThemeRepo:       themeRepo,
-		SettingRepo:     settingRepo,
-		ApplicationRepo: applicationRepo,
+		DashboardRepo:      dashboardRepo,
+		CategoryRepo:       categoryRepo,
+		BookmarkRepo:       bookmarkRepo,
+		ThemeRepo:          themeRepo,
+		SettingRepo:        settingRepo,
+		ApplicationRepo:    applicationRepo,
+		SearchProviderRepo: searchProviderRepo,
+		CustomIconRepo:     customIconRepo,
+		IconProcessor:      iconProcessor,
+		Audit:              audit,
 	}
 }
 
-func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool, in *transfer.UserDataExport) error {
+// Handle imports the export into the actor's dashboard.
+func (h *ImportUserData) Handle(ctx context.Context, actor domainmodel.AuditActor, isAdmin bool, in *transfer.UserDataExport) error {
+	if err := h.importData(ctx, actor.UserID, isAdmin, in); err != nil {
+		return err
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionDataImport, "dash")
+	return nil
+}
+
+func (h *ImportUserData) importData(ctx context.Context, userID string, isAdmin bool, in *transfer.UserDataExport) error {
+	if err := checkCategoryExports(in.Categories); err != nil {
+		return err
+	}
+	for _, d := range in.Dashboards {
+		if err := checkCategoryExports(d.Categories); err != nil {
+			return err
+		}
+	}
+
 	// --- Load existing hashes for deduplication ---
 
 	existingThemeHashes := map[string]struct{}{}
@@ -56,9 +87,9 @@ func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool
 		existingThemeHashes[transfer.ContentHash(t.DisplayName, t.Primary, t.Secondary, t.Tertiary)] = struct{}{}
 	}
 
-	existingCategoryHashes := map[string]struct{}{}
+	existingCategoryHashes := map[string]uint{}
 	var dashboardID uint
-	dashboard, err := h.DashboardRepo.GetByUserID(ctx, userID)
+	dashboard, err := h.DashboardRepo.GetDefault(ctx, userID)
 	if err != nil {
 		var nfe *domainerrors.NotFoundError
 		if !errors.As(err, &nfe) {
@@ -66,12 +97,8 @@ func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool
 		}
 	} else {
 		dashboardID = dashboard.ID
-		categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID)
-		if err != nil {
-			return domainerrors.Internal("import user data: list existing categories", err)
-		}
-		for _, c := range categories {
-			existingCategoryHashes[transfer.ContentHash(c.DisplayName, strconv.FormatBool(c.IsShelved))] = struct{}{}
+		if existingCategoryHashes, err = categoryHashes(ctx, "import user data", h.CategoryRepo, dashboard.ID); err != nil {
+			return err
 		}
 	}
 
@@ -122,80 +149,40 @@ func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool
 
 	// --- Ensure dashboard exists ---
 	if dashboardID == 0 {
-		rec := &domainrepo.DashboardRecord{UserID: userID}
+		name := domainmodel.DefaultDashboardName
+		if parsed, err := domainmodel.ParseDashboardName(in.DashboardName); err == nil {
+			name = parsed
+		}
+		rec := &domainrepo.DashboardRecord{UserID: userID, Name: name, IsDefault: true}
 		if err := h.DashboardRepo.Upsert(ctx, rec); err != nil {
 			return domainerrors.Internal("import user data: upsert dashboard", err)
 		}
-		d, err := h.DashboardRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			return domainerrors.Internal("import user data: get dashboard after upsert", err)
-		}
-		dashboardID = d.ID
+		dashboardID = rec.ID
 	}
 
-	// --- Import categories and bookmarks ---
-	for _, cat := range in.Categories {
-		catHash := transfer.ContentHash(cat.DisplayName, strconv.FormatBool(cat.IsShelved))
-		var catID uint
-		if _, exists := existingCategoryHashes[catHash]; !exists {
-			rec := &domainrepo.CategoryRecord{
-				DashboardID: dashboardID,
-				DisplayName: cat.DisplayName,
-				IsShelved:   cat.IsShelved,
-			}
-			if err := h.CategoryRepo.Upsert(ctx, rec); err != nil {
-				return domainerrors.Internal("import user data: upsert category", err)
-			}
-			catID = rec.ID
-			existingCategoryHashes[catHash] = struct{}{}
-		} else {
-			// Find existing category ID by matching display name + shelved state
-			cats, err := h.CategoryRepo.ListByDashboardID(ctx, dashboardID)
-			if err != nil {
-				return domainerrors.Internal("import user data: list categories for bookmark lookup", err)
-			}
-			for _, c := range cats {
-				if c.DisplayName == cat.DisplayName && c.IsShelved == cat.IsShelved {
-					catID = c.ID
-					break
-				}
-			}
-		}
-
-		if catID == 0 {
-			continue
-		}
+	// --- Import custom icons ---
+	// Icons get new ids here, so "img:<id>" references are rewritten before
+	// the bookmarks and applications using them are imported.
+	iconIDs, err := h.importCustomIcons(ctx, userID, isAdmin, in.CustomIcons)
+	if err != nil {
+		return err
+	}
+	categories := remapCategoryIcons(in.Categories, iconIDs)
+	applications := remapApplicationIcons(in.Applications, iconIDs)
 
-		// Load existing bookmarks for this category to deduplicate
-		existingBookmarkHashes := map[string]struct{}{}
-		existingBms, err := h.BookmarkRepo.ListByCategoryIDs(ctx, []uint{catID})
-		if err != nil {
-			return domainerrors.Internal("import user data: list existing bookmarks for category", err)
-		}
-		for _, b := range existingBms {
-			existingBookmarkHashes[transfer.ContentHash(b.Icon, b.DisplayName, b.Url)] = struct{}{}
-		}
+	// --- Import categories and bookmarks ---
+	if err := importCategories(ctx, "import user data", h.CategoryRepo, h.BookmarkRepo, dashboardID, existingCategoryHashes, categories); err != nil {
+		return err
+	}
 
-		for _, bm := range cat.Bookmarks {
-			if _, exists := existingBookmarkHashes[bm.Hash]; exists {
-				continue
-			}
-			rec := &domainrepo.BookmarkRecord{
-				CategoryID:  catID,
-				Icon:        bm.Icon,
-				DisplayName: bm.DisplayName,
-				Url:         bm.URL,
-			}
-			if err := h.BookmarkRepo.Upsert(ctx, rec); err != nil {
-				return domainerrors.Internal("import user data: upsert bookmark", err)
-			}
-			existingBookmarkHashes[bm.Hash] = struct{}{}
-		}
+	// --- Import further dashboards ---
+	if err := h.importDashboards(ctx, userID, in.Dashboards, iconIDs); err != nil {
+		return err
 	}
 
 	// --- Import applications (admin only) ---
 	if isAdmin {
-		for _, a := range in.Applications {
+		for _, a := range applications {
 			if _, exists := existingAppHashes[a.Hash]; exists {
 				continue
 			}
@@ -217,7 +204,43 @@ func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool
 		}
 	}
 
+	// --- Import search providers ---
+	// Bangs are unique per user, so providers are matched by bang rather than
+	// by hash; an existing provider with the same bang wins.
+	bangToProviderID := map[string]uint{}
+	if len(in.SearchProviders) > 0 || in.Settings.SearchProvider != "" {
+		providers, err := h.SearchProviderRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return domainerrors.Internal("import user data: list existing search providers", err)
+		}
+		for _, p := range providers {
+			bangToProviderID[p.Bang] = p.ID
+		}
+	}
+	for _, p := range in.SearchProviders {
+		bang, err := domainmodel.ParseBang(p.Bang)
+		if err != nil || domainmodel.ValidateSearchURLTemplate(p.URLTemplate) != nil {
+			continue
+		}
+		if _, exists := bangToProviderID[bang]; exists {
+			continue
+		}
+		rec := &domainrepo.SearchProviderRecord{
+			UserID:      userID,
+			DisplayName: p.Name,
+			Bang:        bang,
+			URLTemplate: p.URLTemplate,
+		}
+		if err := h.SearchProviderRepo.Create(ctx, rec); err != nil {
+			return domainerrors.Internal("import user data: create search provider", err)
+		}
+		bangToProviderID[bang] = rec.ID
+	}
+
 	// --- Upsert settings ---
+	if in.Partial {
+		return nil
+	}
 	// ThemeID=0 means synthetic default; override only if the imported name maps to a persisted theme.
 	var themeID uint
 	if in.Settings.ThemeName != "" {
@@ -237,9 +260,348 @@ func (h *ImportUserData) Handle(ctx context.Context, userID string, isAdmin bool
 	existing.ThemeID = themeID
 	existing.Language = in.Settings.Language
 	existing.Timezone = in.Settings.Timezone
+	if id, ok := bangToProviderID[in.Settings.SearchProvider]; ok {
+		existing.SearchProviderID = id
+	}
 	if err := h.SettingRepo.Upsert(ctx, existing); err != nil {
 		return domainerrors.Internal("import user data: upsert settings", err)
 	}
 
 	return nil
 }
+
+// importCustomIcons adds the icons of the export to the library, reusing
+// identical icons the user can already see. Shared icons stay shared only for
+// admins. Icons that fail processing are skipped. It returns the new id of
+// every imported icon keyed by its id in the export.
+func (h *ImportUserData) importCustomIcons(ctx context.Context, userID string, isAdmin bool, icons []transfer.CustomIconExport) (map[uint]uint, error) {
+	ids := map[uint]uint{}
+	if len(icons) == 0 {
+		return ids, nil
+	}
+
+	existing, err := h.CustomIconRepo.ListVisible(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.Internal("import user data: list existing custom icons", err)
+	}
+	ownHashes := map[string]uint{}
+	sharedHashes := map[string]uint{}
+	for _, i := range existing {
+		hash := transfer.ContentHash(i.ContentType, string(i.Data))
+		if i.UserID == "" {
+			sharedHashes[hash] = i.ID
+		} else {
+			ownHashes[hash] = i.ID
+		}
+	}
+
+	for _, i := range icons {
+		if len(i.Data) == 0 || len(i.Data) > domainmodel.MaxCustomIconBytes {
+			continue
+		}
+		// The file may have been edited, so the image is processed like an upload.
+		contentType, data, err := h.IconProcessor.Process(i.Data)
+		if err != nil {
+			continue
+		}
+		shared := i.IsShared && isAdmin
+		hash := transfer.ContentHash(contentType, string(data))
+		if id, ok := sharedHashes[hash]; ok {
+			ids[i.ID] = id
+			continue
+		}
+		if id, ok := ownHashes[hash]; ok && !shared {
+			ids[i.ID] = id
+			continue
+		}
+
+		rec := &domainrepo.CustomIconRecord{
+			DisplayName: i.Name,
+			ContentType: contentType,
+			Data:        data,
+		}
+		if !shared {
+			rec.UserID = userID
+		}
+		if err := h.CustomIconRepo.Create(ctx, rec); err != nil {
+			return nil, domainerrors.Internal("import user data: create custom icon", err)
+		}
+		ids[i.ID] = rec.ID
+		if shared {
+			sharedHashes[hash] = rec.ID
+		} else {
+			ownHashes[hash] = rec.ID
+		}
+	}
+	return ids, nil
+}
+
+// importDashboards adds the categories of each exported dashboard to the
+// user's dashboard of the same name, which is created when missing.
+func (h *ImportUserData) importDashboards(ctx context.Context, userID string, dashboards []transfer.DashboardExport, iconIDs map[uint]uint) error {
+	if len(dashboards) == 0 {
+		return nil
+	}
+	existing, err := h.DashboardRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return domainerrors.Internal("import user data: list existing dashboards", err)
+	}
+	nameToDashboardID := map[string]uint{}
+	for _, d := range existing {
+		nameToDashboardID[d.Name] = d.ID
+	}
+
+	for _, d := range dashboards {
+		name, err := domainmodel.ParseDashboardName(d.Name)
+		if err != nil {
+			return domainerrors.Validation(domainerrors.Violation{Field: "Dashboards", Message: err.Error()})
+		}
+		existingCategoryHashes := map[string]uint{}
+		dashboardID, exists := nameToDashboardID[name]
+		if exists {
+			if existingCategoryHashes, err = categoryHashes(ctx, "import user data", h.CategoryRepo, dashboardID); err != nil {
+				return err
+			}
+		} else {
+			rec := &domainrepo.DashboardRecord{UserID: userID, Name: name}
+			if err := h.DashboardRepo.Upsert(ctx, rec); err != nil {
+				return domainerrors.Internal("import user data: upsert dashboard", err)
+			}
+			dashboardID = rec.ID
+			nameToDashboardID[name] = dashboardID
+		}
+		categories := remapCategoryIcons(d.Categories, iconIDs)
+		if err := importCategories(ctx, "import user data", h.CategoryRepo, h.BookmarkRepo, dashboardID, existingCategoryHashes, categories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remapCustomIcon rewrites an "img:<id>" reference to the id the icon got on
+// import. Other icons and unknown ids are returned unchanged.
+func remapCustomIcon(raw string, ids map[uint]uint) (string, bool) {
+	icon, err := domainmodel.ParseIcon(raw)
+	if err != nil || icon.Type() != domainmodel.CustomIconType {
+		return raw, false
+	}
+	oldID, _ := domainmodel.ParseCustomIconID(icon.Name())
+	newID, ok := ids[oldID]
+	if !ok {
+		return raw, false
+	}
+	return domainmodel.CustomIcon{ID: newID}.Icon().String(), true
+}
+
+// remapCategoryIcons returns a copy of cats whose bookmarks refer to the
+// imported custom icons; their hashes are recomputed to match.
+func remapCategoryIcons(cats []transfer.CategoryExport, ids map[uint]uint) []transfer.CategoryExport {
+	if len(ids) == 0 {
+		return cats
+	}
+	out := make([]transfer.CategoryExport, len(cats))
+	for i, c := range cats {
+		bookmarks := make([]transfer.BookmarkExport, len(c.Bookmarks))
+		for j, bm := range c.Bookmarks {
+			if icon, ok := remapCustomIcon(bm.Icon, ids); ok {
+				bm.Icon = icon
+				bm.Hash = transfer.BookmarkHash(bm.Icon, bm.DisplayName, bm.URL, bm.Tags)
+			}
+			bookmarks[j] = bm
+		}
+		c.Bookmarks = bookmarks
+		c.Children = remapCategoryIcons(c.Children, ids)
+		out[i] = c
+	}
+	return out
+}
+
+// remapApplicationIcons is remapCategoryIcons for applications.
+func remapApplicationIcons(apps []transfer.ApplicationExport, ids map[uint]uint) []transfer.ApplicationExport {
+	if len(ids) == 0 {
+		return apps
+	}
+	out := make([]transfer.ApplicationExport, len(apps))
+	for i, a := range apps {
+		if icon, ok := remapCustomIcon(a.Icon, ids); ok {
+			groups := a.VisibleToGroups
+			if groups == nil {
+				groups = []string{}
+			}
+			a.Icon = icon
+			a.Hash = transfer.ContentHash(a.Icon, a.DisplayName, a.URL, strings.Join(groups, ","))
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// categoryHashes maps the content hashes of the categories of the dashboard
+// to their ids. Errors are labelled with op.
+func categoryHashes(ctx context.Context, op string, categoryRepo domainrepo.CategoryRepository, dashboardID uint) (map[string]uint, error) {
+	categories, err := categoryRepo.ListByDashboardID(ctx, dashboardID)
+	if err != nil {
+		return nil, domainerrors.Internal(op+": list existing categories", err)
+	}
+	byID := make(map[uint]domainrepo.CategoryRecord, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	hashOf := make(map[uint]string, len(categories))
+	var hash func(c domainrepo.CategoryRecord, depth int) string
+	hash = func(c domainrepo.CategoryRecord, depth int) string {
+		if h, ok := hashOf[c.ID]; ok {
+			return h
+		}
+		parentHash := ""
+		// Bounded by the number of categories so that corrupt data cannot loop forever.
+		if parent, ok := byID[c.ParentID]; ok && depth < len(categories) {
+			parentHash = hash(parent, depth+1)
+		}
+		hashOf[c.ID] = transfer.CategoryHash(parentHash, c.DisplayName, c.IsShelved)
+		return hashOf[c.ID]
+	}
+	hashes := make(map[string]uint, len(categories))
+	for _, c := range categories {
+		if _, exists := hashes[hash(c, 0)]; !exists {
+			hashes[hash(c, 0)] = c.ID
+		}
+	}
+	return hashes, nil
+}
+
+// checkCategoryExports verifies that cats nest no deeper than categories may,
+// that only top-level categories are shelved, that smart categories have a
+// valid tag query and no bookmarks and that tags are normalized, before
+// anything is imported.
+func checkCategoryExports(cats []transfer.CategoryExport) error {
+	var check func(cats []transfer.CategoryExport, depth int) error
+	check = func(cats []transfer.CategoryExport, depth int) error {
+		if len(cats) > 0 && depth > domainmodel.MaxCategoryDepth {
+			return domainerrors.Validation(domainerrors.Violation{
+				Field:   "Categories",
+				Message: fmt.Sprintf("categories nest at most %d levels deep", domainmodel.MaxCategoryDepth),
+			})
+		}
+		for _, c := range cats {
+			if depth > 1 && c.IsShelved {
+				return domainerrors.Validation(domainerrors.Violation{Field: "Categories", Message: "only top-level categories can be shelved"})
+			}
+			if c.TagQuery != "" {
+				if _, err := domainmodel.ParseTagQuery(c.TagQuery); err != nil {
+					return domainerrors.Validation(domainerrors.Violation{Field: "Categories", Message: err.Error()})
+				}
+				if len(c.Bookmarks) > 0 {
+					return domainerrors.Validation(domainerrors.Violation{Field: "Categories", Message: "smart categories cannot hold bookmarks"})
+				}
+			}
+			for _, bm := range c.Bookmarks {
+				tags, err := domainmodel.NormalizeTags(bm.Tags)
+				if err != nil {
+					return domainerrors.Validation(domainerrors.Violation{Field: "Categories", Message: err.Error()})
+				}
+				// The tags take part in the bookmark's hash, so they are not
+				// normalized on import but have to be already.
+				if len(bm.Tags) > 0 && !slices.Equal(tags, bm.Tags) {
+					return domainerrors.Validation(domainerrors.Violation{Field: "Categories", Message: "bookmark tags must be lowercase, sorted and unique"})
+				}
+			}
+			if err := check(c.Children, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return check(cats, 1)
+}
+
+// importCategories adds cats, their sub-categories and bookmarks to the
+// dashboard, skipping every category and bookmark whose content hash is
+// already present. Bookmarks and sub-categories of an existing category are
+// merged into it. existingCategoryHashes is updated with the categories
+// created. Errors are labelled with op.
+func importCategories(
+	ctx context.Context,
+	op string,
+	categoryRepo domainrepo.CategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	dashboardID uint,
+	existingCategoryHashes map[string]uint,
+	cats []transfer.CategoryExport,
+) error {
+	im := categoryImporter{
+		op:             op,
+		categoryRepo:   categoryRepo,
+		bookmarkRepo:   bookmarkRepo,
+		dashboardID:    dashboardID,
+		existingHashes: existingCategoryHashes,
+	}
+	return im.importLevel(ctx, 0, "", cats)
+}
+
+type categoryImporter struct {
+	op             string
+	categoryRepo   domainrepo.CategoryRepository
+	bookmarkRepo   domainrepo.BookmarkRepository
+	dashboardID    uint
+	existingHashes map[string]uint
+}
+
+// importLevel imports cats as the sub-categories of parentID, whose hash is
+// parentHash; parentID 0 is the top level.
+func (im categoryImporter) importLevel(ctx context.Context, parentID uint, parentHash string, cats []transfer.CategoryExport) error {
+	for _, cat := range cats {
+		catHash := transfer.CategoryHash(parentHash, cat.DisplayName, cat.IsShelved)
+		catID, exists := im.existingHashes[catHash]
+		if !exists {
+			rec := &domainrepo.CategoryRecord{
+				DashboardID: im.dashboardID,
+				ParentID:    parentID,
+				DisplayName: cat.DisplayName,
+				IsShelved:   cat.IsShelved,
+				TagQuery:    cat.TagQuery,
+			}
+			if err := im.categoryRepo.Upsert(ctx, rec); err != nil {
+				return domainerrors.Internal(im.op+": upsert category", err)
+			}
+			catID = rec.ID
+			im.existingHashes[catHash] = catID
+		}
+
+		if catID == 0 {
+			continue
+		}
+
+		// Load existing bookmarks for this category to deduplicate
+		existingBookmarkHashes := map[string]struct{}{}
+		existingBms, err := im.bookmarkRepo.ListByCategoryIDs(ctx, []uint{catID})
+		if err != nil {
+			return domainerrors.Internal(im.op+": list existing bookmarks for category", err)
+		}
+		for _, b := range existingBms {
+			existingBookmarkHashes[transfer.BookmarkHash(b.Icon, b.DisplayName, b.Url, b.Tags)] = struct{}{}
+		}
+
+		for _, bm := range cat.Bookmarks {
+			if _, exists := existingBookmarkHashes[bm.Hash]; exists {
+				continue
+			}
+			rec := &domainrepo.BookmarkRecord{
+				CategoryID:  catID,
+				Icon:        bm.Icon,
+				DisplayName: bm.DisplayName,
+				Url:         bm.URL,
+				Tags:        bm.Tags,
+			}
+			if err := im.bookmarkRepo.Upsert(ctx, rec); err != nil {
+				return domainerrors.Internal(im.op+": upsert bookmark", err)
+			}
+			existingBookmarkHashes[bm.Hash] = struct{}{}
+		}
+
+		if err := im.importLevel(ctx, catID, catHash, cat.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
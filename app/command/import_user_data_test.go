@@ -27,7 +27,7 @@ func newImportHandler(
 	settingRepo *repoMock.SettingRepository,
 	appRepo *repoMock.ApplicationRepository,
 ) *command.ImportUserData {
-	return command.NewImportUserData(dashRepo, catRepo, bRepo, themeRepo, settingRepo, appRepo)
+	return command.NewImportUserData(dashRepo, catRepo, bRepo, themeRepo, settingRepo, appRepo, nil, nil, nil, acceptAudit())
 }
 
 func TestImportUserData_Handle_ListThemesError(t *testing.T) {
@@ -35,7 +35,7 @@ func TestImportUserData_Handle_ListThemesError(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return(nil, errors.New("db error"))
 
 	h := newImportHandler(nil, nil, nil, themeRepo, nil, nil)
-	err := h.Handle(context.Background(), "user-1", false, emptyExport())
+	err := h.Handle(context.Background(), testActor, false, emptyExport())
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
@@ -46,10 +46,10 @@ func TestImportUserData_Handle_DashboardRepoError(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+	dashRepo.On("GetDefault", mock.Anything, "user-1").Return(nil, errors.New("db error"))
 
 	h := newImportHandler(dashRepo, nil, nil, themeRepo, nil, nil)
-	err := h.Handle(context.Background(), "user-1", false, emptyExport())
+	err := h.Handle(context.Background(), testActor, false, emptyExport())
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
@@ -61,11 +61,13 @@ func TestImportUserData_Handle_EmptyExport_NoDashboard(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard)).Once()
-	dashRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil).Once()
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.UserID == "user-1" && r.IsDefault
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.DashboardRecord).ID = 10
+	}).Return(nil)
 
 	settingRepo := &repoMock.SettingRepository{}
 	settingRepo.On("GetByUserID", mock.Anything, "user-1").
@@ -73,7 +75,7 @@ func TestImportUserData_Handle_EmptyExport_NoDashboard(t *testing.T) {
 	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
 
 	h := newImportHandler(dashRepo, nil, nil, themeRepo, settingRepo, nil)
-	err := h.Handle(context.Background(), "user-1", false, emptyExport())
+	err := h.Handle(context.Background(), testActor, false, emptyExport())
 
 	require.NoError(t, err)
 }
@@ -83,7 +85,7 @@ func TestImportUserData_Handle_WithExistingDashboard_HappyPath(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -95,7 +97,7 @@ func TestImportUserData_Handle_WithExistingDashboard_HappyPath(t *testing.T) {
 	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
 
 	h := newImportHandler(dashRepo, catRepo, nil, themeRepo, settingRepo, nil)
-	err := h.Handle(context.Background(), "user-1", false, emptyExport())
+	err := h.Handle(context.Background(), testActor, false, emptyExport())
 
 	require.NoError(t, err)
 }
@@ -105,7 +107,7 @@ func TestImportUserData_Handle_ImportNewCategory(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -150,7 +152,7 @@ func TestImportUserData_Handle_ImportNewCategory(t *testing.T) {
 	}
 
 	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
-	err := h.Handle(context.Background(), "user-1", false, in)
+	err := h.Handle(context.Background(), testActor, false, in)
 
 	require.NoError(t, err)
 	catRepo.AssertExpectations(t)
@@ -162,7 +164,7 @@ func TestImportUserData_Handle_AdminImportsApplications(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -192,7 +194,7 @@ func TestImportUserData_Handle_AdminImportsApplications(t *testing.T) {
 	}
 
 	h := newImportHandler(dashRepo, catRepo, nil, themeRepo, settingRepo, appRepo)
-	err := h.Handle(context.Background(), "user-1", true, in)
+	err := h.Handle(context.Background(), testActor, true, in)
 
 	require.NoError(t, err)
 	appRepo.AssertExpectations(t)
@@ -203,7 +205,7 @@ func TestImportUserData_Handle_SkipsDuplicateCategory(t *testing.T) {
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	// Dashboard already has "Work" (same hash)
@@ -234,8 +236,418 @@ func TestImportUserData_Handle_SkipsDuplicateCategory(t *testing.T) {
 	}
 
 	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
-	err := h.Handle(context.Background(), "user-1", false, in)
+	err := h.Handle(context.Background(), testActor, false, in)
 
 	require.NoError(t, err)
 	catRepo.AssertNotCalled(t, "Upsert")
 }
+
+func TestImportUserData_Handle_NestedCategories(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	// "Infrastructure" exists; its new sub-category is merged into it.
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Infrastructure"},
+	}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DisplayName == "Proxmox" && r.ParentID == 5
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 6
+	}).Return(nil).Once()
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DisplayName == "Nodes" && r.ParentID == 6
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 7
+	}).Return(nil).Once()
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.DisplayName == "pve-1" && r.CategoryID == 7
+	})).Return(nil).Once()
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	infraHash := transfer.CategoryHash("", "Infrastructure", false)
+	proxmoxHash := transfer.CategoryHash(infraHash, "Proxmox", false)
+	in := &transfer.UserDataExport{
+		Version: 1,
+		Categories: []transfer.CategoryExport{{
+			Hash:        infraHash,
+			DisplayName: "Infrastructure",
+			Bookmarks:   []transfer.BookmarkExport{},
+			Children: []transfer.CategoryExport{{
+				Hash:        proxmoxHash,
+				DisplayName: "Proxmox",
+				Bookmarks:   []transfer.BookmarkExport{},
+				Children: []transfer.CategoryExport{{
+					Hash:        transfer.CategoryHash(proxmoxHash, "Nodes", false),
+					DisplayName: "Nodes",
+					Bookmarks: []transfer.BookmarkExport{{
+						Hash:        transfer.ContentHash("mdi:server", "pve-1", "https://pve-1.lan"),
+						Icon:        "mdi:server",
+						DisplayName: "pve-1",
+						URL:         "https://pve-1.lan",
+					}},
+				}},
+			}},
+		}},
+	}
+
+	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+func TestImportUserData_Handle_RejectsInvalidTree(t *testing.T) {
+	tests := map[string][]transfer.CategoryExport{
+		"too deep": {{DisplayName: "1", Children: []transfer.CategoryExport{{
+			DisplayName: "2", Children: []transfer.CategoryExport{{
+				DisplayName: "3", Children: []transfer.CategoryExport{{DisplayName: "4"}},
+			}},
+		}}}},
+		"shelved sub-category": {{DisplayName: "1", Children: []transfer.CategoryExport{{
+			DisplayName: "2", IsShelved: true,
+		}}}},
+		"invalid tag query": {{DisplayName: "1", TagQuery: "k8s AND"}},
+		"smart category with bookmarks": {{DisplayName: "1", TagQuery: "tag:k8s", Bookmarks: []transfer.BookmarkExport{
+			{Icon: "mdi:link", DisplayName: "GitHub", URL: "https://github.com"},
+		}}},
+		"unnormalized tags": {{DisplayName: "1", Bookmarks: []transfer.BookmarkExport{
+			{Icon: "mdi:link", DisplayName: "GitHub", URL: "https://github.com", Tags: []string{"ops", "K8s"}},
+		}}},
+	}
+	for name, categories := range tests {
+		t.Run(name, func(t *testing.T) {
+			catRepo := &repoMock.CategoryRepository{}
+
+			h := newImportHandler(nil, catRepo, nil, nil, nil, nil)
+			err := h.Handle(context.Background(), testActor, false, &transfer.UserDataExport{Version: 1, Categories: categories})
+
+			var ve *domainerrors.ValidationError
+			require.ErrorAs(t, err, &ve)
+			catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestImportUserData_Handle_PreservesBookmarkOrder(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 5
+	}).Return(nil)
+
+	var upserted []string
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		upserted = append(upserted, args.Get(1).(*domainrepo.BookmarkRecord).DisplayName)
+	}).Return(nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	bookmark := func(name string) transfer.BookmarkExport {
+		url := "https://" + name + ".example.com"
+		return transfer.BookmarkExport{
+			Hash:        transfer.ContentHash("mdi:link", name, url),
+			Icon:        "mdi:link",
+			DisplayName: name,
+			URL:         url,
+		}
+	}
+	in := &transfer.UserDataExport{
+		Version: 1,
+		Categories: []transfer.CategoryExport{
+			{
+				Hash:        transfer.ContentHash("Work", "false"),
+				DisplayName: "Work",
+				Bookmarks:   []transfer.BookmarkExport{bookmark("zulu"), bookmark("alpha"), bookmark("mike")},
+			},
+		},
+	}
+
+	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"zulu", "alpha", "mike"}, upserted)
+}
+
+func TestImportUserData_Handle_PartialKeepsSettings(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+
+	in := emptyExport()
+	in.Partial = true
+
+	h := newImportHandler(dashRepo, catRepo, nil, themeRepo, settingRepo, nil)
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	settingRepo.AssertNotCalled(t, "GetByUserID", mock.Anything, mock.Anything)
+	settingRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestImportUserData_Handle_SearchProviders(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+
+	searchProviderRepo := &repoMock.SearchProviderRepository{}
+	searchProviderRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{
+		{ID: 1, Bang: "ddg"},
+	}, nil)
+	searchProviderRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.SearchProviderRecord) bool {
+		return r.Bang == "gh"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.SearchProviderRecord).ID = 2
+	}).Return(nil).Once()
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.SettingRecord) bool {
+		return r.SearchProviderID == 2
+	})).Return(nil)
+
+	in := emptyExport()
+	in.Settings.SearchProvider = "gh"
+	in.SearchProviders = []transfer.SearchProviderExport{
+		{Name: "DuckDuckGo", Bang: "ddg", URLTemplate: "https://duckduckgo.com/?q={q}"},
+		{Name: "GitHub", Bang: "gh", URLTemplate: "https://github.com/search?q={q}"},
+		{Name: "Broken", Bang: "x", URLTemplate: "not a url"},
+	}
+
+	h := command.NewImportUserData(dashRepo, catRepo, nil, themeRepo, settingRepo, nil, searchProviderRepo, nil, nil, acceptAudit())
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	searchProviderRepo.AssertExpectations(t)
+	settingRepo.AssertExpectations(t)
+}
+
+func TestImportUserData_Handle_CustomIcons(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 5
+	}).Return(nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.DisplayName == "NAS" && r.Icon == "img:42"
+	})).Return(nil).Once()
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.DisplayName == "Router" && r.Icon == "img:9"
+	})).Return(nil).Once()
+
+	customIconRepo := &repoMock.CustomIconRepository{}
+	customIconRepo.On("ListVisible", mock.Anything, "user-1").Return([]domainrepo.CustomIconRecord{
+		{ID: 9, UserID: "user-1", ContentType: "image/png", Data: []byte("router")},
+	}, nil)
+	// A shared icon stays shared only for admins.
+	customIconRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.CustomIconRecord) bool {
+		return r.DisplayName == "NAS" && r.UserID == "user-1" && string(r.Data) == "nas"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CustomIconRecord).ID = 42
+	}).Return(nil).Once()
+
+	processor := &repoMock.CustomIconProcessor{}
+	processor.On("Process", []byte("nas")).Return("image/png", []byte("nas"), nil)
+	processor.On("Process", []byte("router")).Return("image/png", []byte("router"), nil)
+	processor.On("Process", []byte("<script/>")).Return("", nil, errors.New("invalid"))
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	in := emptyExport()
+	in.CustomIcons = []transfer.CustomIconExport{
+		{ID: 1, Name: "NAS", ContentType: "image/png", Data: []byte("nas"), IsShared: true},
+		{ID: 2, Name: "Router", ContentType: "image/png", Data: []byte("router")},
+		{ID: 3, Name: "Evil", ContentType: "image/svg+xml", Data: []byte("<script/>")},
+	}
+	in.Categories = []transfer.CategoryExport{{
+		Hash:        transfer.ContentHash("Home", "false"),
+		DisplayName: "Home",
+		Bookmarks: []transfer.BookmarkExport{
+			{Hash: transfer.ContentHash("img:1", "NAS", "https://nas.lan"), Icon: "img:1", DisplayName: "NAS", URL: "https://nas.lan"},
+			{Hash: transfer.ContentHash("img:2", "Router", "https://router.lan"), Icon: "img:2", DisplayName: "Router", URL: "https://router.lan"},
+		},
+	}}
+
+	h := command.NewImportUserData(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil, nil, customIconRepo, processor, acceptAudit())
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	customIconRepo.AssertExpectations(t)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+func TestImportUserData_Handle_FurtherDashboards(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Work", IsDefault: true}, nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.DashboardRecord{
+		{ID: 10, UserID: "user-1", Name: "Work", IsDefault: true},
+		{ID: 11, UserID: "user-1", Name: "Home"},
+	}, nil)
+	// "Lab" does not exist yet and is created next to the others.
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.Name == "Lab" && !r.IsDefault
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.DashboardRecord).ID = 12
+	}).Return(nil).Once()
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DashboardID == 11 && r.DisplayName == "Media"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 5
+	}).Return(nil).Once()
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DashboardID == 12 && r.DisplayName == "Servers"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 6
+	}).Return(nil).Once()
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).Return([]domainrepo.BookmarkRecord{}, nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	in := emptyExport()
+	in.Dashboards = []transfer.DashboardExport{
+		{Name: "Home", Categories: []transfer.CategoryExport{{DisplayName: "Media"}}},
+		{Name: "Lab", Categories: []transfer.CategoryExport{{DisplayName: "Servers"}}},
+	}
+
+	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+	catRepo.AssertExpectations(t)
+}
+
+func TestImportUserData_Handle_TagsAndSmartCategories(t *testing.T) {
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Work"},
+	}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DisplayName == "Kubernetes" && r.TagQuery == "tag:k8s"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.CategoryRecord).ID = 6
+	}).Return(nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	// The untagged bookmark already exists; its tagged version is new.
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).Return([]domainrepo.BookmarkRecord{
+		{ID: 1, CategoryID: 5, Icon: "mdi:link", DisplayName: "ArgoCD", Url: "https://argo.lan"},
+	}, nil)
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{6}).Return([]domainrepo.BookmarkRecord{}, nil).Maybe()
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return r.CategoryID == 5 && len(r.Tags) == 2 && r.Tags[0] == "k8s"
+	})).Return(nil).Once()
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	tags := []string{"k8s", "ops"}
+	in := &transfer.UserDataExport{
+		Version: 1,
+		Categories: []transfer.CategoryExport{
+			{
+				Hash:        transfer.CategoryHash("", "Work", false),
+				DisplayName: "Work",
+				Bookmarks: []transfer.BookmarkExport{{
+					Hash:        transfer.BookmarkHash("mdi:link", "ArgoCD", "https://argo.lan", tags),
+					Icon:        "mdi:link",
+					DisplayName: "ArgoCD",
+					URL:         "https://argo.lan",
+					Tags:        tags,
+				}},
+			},
+			{
+				Hash:        transfer.CategoryHash("", "Kubernetes", false),
+				DisplayName: "Kubernetes",
+				Bookmarks:   []transfer.BookmarkExport{},
+				TagQuery:    "tag:k8s",
+			},
+		},
+	}
+
+	h := newImportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	err := h.Handle(context.Background(), testActor, false, in)
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+	bookmarkRepo.AssertExpectations(t)
+}
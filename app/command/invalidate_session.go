@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
@@ -10,17 +11,22 @@ import (
 // Invalidation deletes the record entirely, causing the revocation check in
 // LoadIdentity to deny access on the next request from that device.
 type SessionInvalidator interface {
-	Handle(ctx context.Context, userID string, sessionID string) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, sessionID string) error
 }
 
 type InvalidateSession struct {
-	Repo domainrepo.SessionRepository
+	Repo  domainrepo.SessionRepository
+	Audit AuditRecorder
 }
 
-func NewInvalidateSession(repo domainrepo.SessionRepository) *InvalidateSession {
-	return &InvalidateSession{Repo: repo}
+func NewInvalidateSession(repo domainrepo.SessionRepository, audit AuditRecorder) *InvalidateSession {
+	return &InvalidateSession{Repo: repo, Audit: audit}
 }
 
-func (h *InvalidateSession) Handle(ctx context.Context, userID string, sessionID string) error {
-	return h.Repo.DeleteByID(ctx, sessionID, userID)
+func (h *InvalidateSession) Handle(ctx context.Context, actor domainmodel.AuditActor, sessionID string) error {
+	if err := h.Repo.DeleteByID(ctx, sessionID, actor.UserID); err != nil {
+		return err
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionSessionRevoke, sessionID)
+	return nil
 }
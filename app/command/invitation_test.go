@@ -0,0 +1,193 @@
+package command_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── CreateInvitation ───────────────────────────────────────────────────────
+
+func validInvitationCmd() command.CreateInvitationCmd {
+	return command.CreateInvitationCmd{
+		Groups:        []string{"media"},
+		MaxUses:       3,
+		ExpiresInDays: 7,
+		GrantDays:     30,
+	}
+}
+
+func TestCreateInvitation_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewCreateInvitation(nil, nil, nil)
+	_, err := h.Handle(context.Background(), false, testActor, validInvitationCmd())
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestCreateInvitation_Handle_RequiresGroups(t *testing.T) {
+	in := validInvitationCmd()
+	in.Groups = nil
+
+	h := command.NewCreateInvitation(&repoMock.InvitationRepository{}, validation.New(), acceptAudit())
+	_, err := h.Handle(context.Background(), true, testActor, in)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateInvitation_Handle_RejectsSyntheticGroups(t *testing.T) {
+	in := validInvitationCmd()
+	in.Groups = []string{"media", "dash_admin"}
+
+	h := command.NewCreateInvitation(&repoMock.InvitationRepository{}, validation.New(), acceptAudit())
+	_, err := h.Handle(context.Background(), true, testActor, in)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateInvitation_Handle_StoresHashedToken(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var stored *domainrepo.InvitationRecord
+	repo := &repoMock.InvitationRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domainrepo.InvitationRecord) }).
+		Return(nil)
+
+	h := command.NewCreateInvitation(repo, validation.New(), acceptAudit())
+	h.Now = func() time.Time { return now }
+	secret, err := h.Handle(context.Background(), true, testActor, validInvitationCmd())
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, domainmodel.InvitationTokenPrefix))
+	require.Equal(t, domainmodel.HashInvitationToken(secret), stored.TokenHash)
+	require.Equal(t, []string{"media"}, stored.Groups)
+	require.Equal(t, 3, stored.MaxUses)
+	require.Equal(t, now.AddDate(0, 0, 7), stored.ExpiresAt)
+	require.Equal(t, 30*24*time.Hour, stored.GrantDuration)
+	require.Equal(t, testActor.UserID, stored.CreatedBy)
+}
+
+// ── RedeemInvitation ───────────────────────────────────────────────────────
+
+func TestRedeemInvitation_Handle_UnknownToken(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, domainmodel.HashInvitationToken("dash_inv_x")).
+		Return(nil, domainerrors.NotFound(domainerrors.EntityInvitation))
+
+	h := command.NewRedeemInvitation(repo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, "dash_inv_x")
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+func TestRedeemInvitation_Handle_GrantsGroups(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(&domainrepo.InvitationRecord{
+		ID:            "inv-1",
+		Groups:        []string{"media"},
+		GrantDuration: 48 * time.Hour,
+	}, nil)
+	repo.On("HasRedeemed", mock.Anything, "inv-1", "user-1").Return(false, nil)
+	repo.On("Redeem", mock.Anything, &domainrepo.InvitationRedemptionRecord{
+		InvitationID: "inv-1",
+		UserID:       "user-1",
+		Groups:       []string{"media"},
+		ExpiresAt:    now.Add(48 * time.Hour),
+	}, now).Return(true, nil)
+	audit := expectAudit(domainmodel.AuditActionInvitationRedeem, "inv-1")
+
+	h := command.NewRedeemInvitation(repo, command.NewRecordAudit(audit))
+	h.Now = func() time.Time { return now }
+	err := h.Handle(context.Background(), testActor, "dash_inv_x")
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestRedeemInvitation_Handle_RedeemedBefore(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(&domainrepo.InvitationRecord{ID: "inv-1"}, nil)
+	repo.On("HasRedeemed", mock.Anything, "inv-1", "user-1").Return(true, nil)
+
+	h := command.NewRedeemInvitation(repo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, "dash_inv_x")
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRedeemInvitation_Handle_ExpiredOrUsedUp(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(&domainrepo.InvitationRecord{ID: "inv-1"}, nil)
+	repo.On("HasRedeemed", mock.Anything, "inv-1", "user-1").Return(false, nil)
+	repo.On("Redeem", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	h := command.NewRedeemInvitation(repo, acceptAudit())
+	err := h.Handle(context.Background(), testActor, "dash_inv_x")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+// ── DeleteInvitation / RevokeInvitationRedemption ──────────────────────────
+
+func TestDeleteInvitation_Handle_RequiresAdmin(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+
+	h := command.NewDeleteInvitation(repo, acceptAudit())
+	err := h.Handle(context.Background(), false, testActor, "inv-1")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteInvitation_Handle_RecordsAudit(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("Delete", mock.Anything, "inv-1").Return(nil)
+	audit := expectAudit(domainmodel.AuditActionInvitationRevoke, "inv-1")
+
+	h := command.NewDeleteInvitation(repo, command.NewRecordAudit(audit))
+	require.NoError(t, h.Handle(context.Background(), true, testActor, "inv-1"))
+	audit.AssertExpectations(t)
+}
+
+func TestRevokeInvitationRedemption_Handle_ExpiresRedemption(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &repoMock.InvitationRepository{}
+	repo.On("RevokeRedemption", mock.Anything, uint(7), now).Return(nil)
+	audit := expectAudit(domainmodel.AuditActionInvitationRevoke, "inv-1")
+
+	h := command.NewRevokeInvitationRedemption(repo, command.NewRecordAudit(audit))
+	h.Now = func() time.Time { return now }
+	require.NoError(t, h.Handle(context.Background(), true, testActor, "inv-1", 7))
+	repo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestRevokeInvitationRedemption_Handle_NotFound(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("RevokeRedemption", mock.Anything, uint(7), mock.Anything).Return(domainerrors.NotFound(domainerrors.EntityInvitation))
+
+	h := command.NewRevokeInvitationRedemption(repo, acceptAudit())
+	err := h.Handle(context.Background(), true, testActor, "inv-1", 7)
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
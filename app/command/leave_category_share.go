@@ -0,0 +1,30 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CategoryShareLeaver handles the leave-category-share command.
+type CategoryShareLeaver interface {
+	Handle(ctx context.Context, userId string, categoryID uint) error
+}
+
+type LeaveCategoryShare struct {
+	Repo domainrepo.CategoryShareRepository
+}
+
+func NewLeaveCategoryShare(repo domainrepo.CategoryShareRepository) *LeaveCategoryShare {
+	return &LeaveCategoryShare{Repo: repo}
+}
+
+// Handle removes a category another user shared with the user from their
+// dashboard. Only the owner can share it again.
+func (h *LeaveCategoryShare) Handle(ctx context.Context, userId string, categoryID uint) error {
+	if err := h.Repo.Delete(ctx, categoryID, userId); err != nil {
+		return domainerrors.WrapRepo("leave category share: delete", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// IdpIdentityLinker handles the link-idp-identity command.
+type IdpIdentityLinker interface {
+	Handle(ctx context.Context, userID, issuer, sub string) error
+}
+
+type LinkIdpIdentity struct {
+	Repo domainrepo.IdpLinkRepository
+}
+
+func NewLinkIdpIdentity(repo domainrepo.IdpLinkRepository) *LinkIdpIdentity {
+	return &LinkIdpIdentity{Repo: repo}
+}
+
+// Handle links (issuer, sub) to the user's account so signing in with that
+// identity resolves to the same user. Linking an identity the user already
+// owns is a no-op; an identity that belongs to another account is rejected,
+// because silently moving it would hand over that account's sign-in.
+func (h *LinkIdpIdentity) Handle(ctx context.Context, userID, issuer, sub string) error {
+	if userID == "" || issuer == "" || sub == "" {
+		return domainerrors.Validation(domainerrors.Violation{Message: "user, issuer and subject are required"})
+	}
+
+	ownerID, err := h.Repo.Link(ctx, userID, issuer, sub)
+	if err != nil {
+		return domainerrors.Internal("link idp identity", err)
+	}
+	if ownerID != userID {
+		return domainerrors.Forbidden("identity is already linked to another account")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── CreateLocalAccount ─────────────────────────────────────────────────────
+
+func TestCreateLocalAccount_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewCreateLocalAccount(nil, nil)
+	err := h.Handle(context.Background(), false, command.CreateLocalAccountCmd{})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestCreateLocalAccount_Handle_RejectsInvalidUsername(t *testing.T) {
+	h := command.NewCreateLocalAccount(nil, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalAccountCmd{
+		Username: "ada lovelace",
+		Password: "correct horse",
+	})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateLocalAccount_Handle_UsernameTaken(t *testing.T) {
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{Username: "ada"}, nil)
+
+	h := command.NewCreateLocalAccount(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalAccountCmd{
+		Username: "Ada",
+		Password: "correct horse",
+	})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateLocalAccount_Handle_StoresNormalizedAccount(t *testing.T) {
+	var stored *domainrepo.LocalAccountRecord
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalAccount))
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domainrepo.LocalAccountRecord) }).
+		Return(nil)
+
+	h := command.NewCreateLocalAccount(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalAccountCmd{
+		Username:    " Ada ",
+		DisplayName: "Ada Lovelace",
+		Groups:      []string{"family"},
+		Password:    "correct horse",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "ada", stored.Username)
+	require.Equal(t, []string{"family"}, stored.Groups)
+	require.False(t, stored.Managed)
+	require.True(t, domainmodel.CheckPassword(stored.PasswordHash, "correct horse"))
+}
+
+// ── SetLocalAccountPassword ────────────────────────────────────────────────
+
+func TestSetLocalAccountPassword_Handle_ManagedAccountForbidden(t *testing.T) {
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{Username: "ada", Managed: true}, nil)
+
+	h := command.NewSetLocalAccountPassword(repo, nil, validation.New(), "local")
+	err := h.Handle(context.Background(), true, command.SetLocalAccountPasswordCmd{Username: "ada", Password: "correct horse"})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "SetPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetLocalAccountPassword_Handle_EndsSessions(t *testing.T) {
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{Username: "ada"}, nil)
+	repo.On("SetPassword", mock.Anything, "ada", mock.MatchedBy(func(hash string) bool {
+		return domainmodel.CheckPassword(hash, "battery staple")
+	})).Return(nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteBySub", mock.Anything, "local", "ada").Return(nil)
+
+	h := command.NewSetLocalAccountPassword(repo, sessionRepo, validation.New(), "local")
+	err := h.Handle(context.Background(), true, command.SetLocalAccountPasswordCmd{Username: "ada", Password: "battery staple"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
+
+// ── DeleteLocalAccount ─────────────────────────────────────────────────────
+
+func TestDeleteLocalAccount_Handle_NotFoundIsNoop(t *testing.T) {
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalAccount))
+
+	h := command.NewDeleteLocalAccount(repo, nil, "local")
+	require.NoError(t, h.Handle(context.Background(), true, "ada"))
+}
+
+func TestDeleteLocalAccount_Handle_DeletesAndEndsSessions(t *testing.T) {
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{Username: "ada"}, nil)
+	repo.On("Delete", mock.Anything, "ada").Return(nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteBySub", mock.Anything, "local", "ada").Return(nil)
+
+	h := command.NewDeleteLocalAccount(repo, sessionRepo, "local")
+	require.NoError(t, h.Handle(context.Background(), true, "ada"))
+
+	repo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
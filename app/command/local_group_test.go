@@ -0,0 +1,172 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── CreateLocalGroup ───────────────────────────────────────────────────────
+
+func TestCreateLocalGroup_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewCreateLocalGroup(nil, nil)
+	err := h.Handle(context.Background(), false, command.CreateLocalGroupCmd{Name: "friends"})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestCreateLocalGroup_Handle_RejectsReservedName(t *testing.T) {
+	h := command.NewCreateLocalGroup(&repoMock.LocalGroupRepository{}, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalGroupCmd{Name: "dash_admin"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestCreateLocalGroup_Handle_NameTaken(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("GetByName", mock.Anything, "friends").Return(&domainrepo.LocalGroupRecord{ID: 1, Name: "friends"}, nil)
+
+	h := command.NewCreateLocalGroup(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalGroupCmd{Name: "friends"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateLocalGroup_Handle_StoresGroup(t *testing.T) {
+	var stored *domainrepo.LocalGroupRecord
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("GetByName", mock.Anything, "friends").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalGroup))
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domainrepo.LocalGroupRecord) }).
+		Return(nil)
+
+	h := command.NewCreateLocalGroup(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.CreateLocalGroupCmd{
+		Name:      " friends ",
+		IdpGroups: []string{"family"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "friends", stored.Name)
+	require.Equal(t, []string{"family"}, stored.IdpGroups)
+}
+
+// ── UpdateLocalGroup ───────────────────────────────────────────────────────
+
+func TestUpdateLocalGroup_Handle_KeepsOwnName(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("GetByName", mock.Anything, "friends").Return(&domainrepo.LocalGroupRecord{ID: 1, Name: "friends"}, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(r *domainrepo.LocalGroupRecord) bool {
+		return r.ID == 1 && r.Name == "friends" && len(r.IdpGroups) == 1
+	})).Return(nil)
+
+	h := command.NewUpdateLocalGroup(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.UpdateLocalGroupCmd{
+		ID:        1,
+		Name:      "friends",
+		IdpGroups: []string{"family"},
+	})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateLocalGroup_Handle_NameTakenByOtherGroup(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("GetByName", mock.Anything, "friends").Return(&domainrepo.LocalGroupRecord{ID: 2, Name: "friends"}, nil)
+
+	h := command.NewUpdateLocalGroup(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.UpdateLocalGroupCmd{ID: 1, Name: "friends"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateLocalGroup_Handle_NotFound(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("GetByName", mock.Anything, "friends").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalGroup))
+	repo.On("Update", mock.Anything, mock.Anything).Return(domainerrors.NotFound(domainerrors.EntityLocalGroup))
+
+	h := command.NewUpdateLocalGroup(repo, validation.New())
+	err := h.Handle(context.Background(), true, command.UpdateLocalGroupCmd{ID: 1, Name: "friends"})
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+// ── DeleteLocalGroup ───────────────────────────────────────────────────────
+
+func TestDeleteLocalGroup_Handle_RequiresAdmin(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+
+	h := command.NewDeleteLocalGroup(repo)
+	err := h.Handle(context.Background(), false, 1)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteLocalGroup_Handle_DeletesGroup(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("Delete", mock.Anything, uint(1)).Return(nil)
+
+	h := command.NewDeleteLocalGroup(repo)
+	require.NoError(t, h.Handle(context.Background(), true, 1))
+	repo.AssertExpectations(t)
+}
+
+// ── AddLocalGroupMember / RemoveLocalGroupMember ───────────────────────────
+
+func TestAddLocalGroupMember_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewAddLocalGroupMember(&repoMock.LocalGroupRepository{})
+	err := h.Handle(context.Background(), false, 1, "user-2")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestAddLocalGroupMember_Handle_AddsMember(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("AddMember", mock.Anything, uint(1), "user-2").Return(nil)
+
+	h := command.NewAddLocalGroupMember(repo)
+	require.NoError(t, h.Handle(context.Background(), true, 1, "user-2"))
+	repo.AssertExpectations(t)
+}
+
+func TestAddLocalGroupMember_Handle_GroupNotFound(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("AddMember", mock.Anything, uint(1), "user-2").Return(domainerrors.NotFound(domainerrors.EntityLocalGroup))
+
+	h := command.NewAddLocalGroupMember(repo)
+	err := h.Handle(context.Background(), true, 1, "user-2")
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+func TestRemoveLocalGroupMember_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("RemoveMember", mock.Anything, uint(1), "user-2").Return(errors.New("db error"))
+
+	h := command.NewRemoveLocalGroupMember(repo)
+	err := h.Handle(context.Background(), true, 1, "user-2")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
@@ -14,19 +15,26 @@ type PinSessionCmd struct {
 
 // SessionPinner handles the pin-session command.
 type SessionPinner interface {
-	Handle(ctx context.Context, userID string, cmd PinSessionCmd) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, cmd PinSessionCmd) error
 }
 
 type PinSession struct {
-	Repo domainrepo.SessionRepository
+	Repo  domainrepo.SessionRepository
+	Audit AuditRecorder
 }
 
-func NewPinSession(repo domainrepo.SessionRepository) *PinSession {
-	return &PinSession{Repo: repo}
+func NewPinSession(repo domainrepo.SessionRepository, audit AuditRecorder) *PinSession {
+	return &PinSession{Repo: repo, Audit: audit}
 }
 
 const pinnedSessionWindow = 365 * 24 * time.Hour
 
-func (h *PinSession) Handle(ctx context.Context, userID string, cmd PinSessionCmd) error {
-	return h.Repo.Pin(ctx, cmd.SessionID, userID, time.Now().Add(pinnedSessionWindow))
+// Handle pins the actor's current session. The cookie SessionID is not
+// recorded as the audit target, since it identifies a live session.
+func (h *PinSession) Handle(ctx context.Context, actor domainmodel.AuditActor, cmd PinSessionCmd) error {
+	if err := h.Repo.Pin(ctx, cmd.SessionID, actor.UserID, time.Now().Add(pinnedSessionWindow)); err != nil {
+		return err
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionSessionPin, "")
+	return nil
 }
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// HTTPProber requests url and returns the HTTP status code of the response.
+// Implementations must honour ctx cancellation.
+type HTTPProber interface {
+	Probe(ctx context.Context, url string) (int, error)
+}
+
+// ApplicationsProber handles the probe-applications command.
+type ApplicationsProber interface {
+	Handle(ctx context.Context) error
+}
+
+type ProbeApplications struct {
+	ApplicationRepo domainrepo.ApplicationRepository
+	Prober          HTTPProber
+	// Concurrency bounds the number of probes in flight.
+	Concurrency int
+	// Timeout bounds every single probe.
+	Timeout time.Duration
+	Now     func() time.Time
+}
+
+func NewProbeApplications(
+	applicationRepo domainrepo.ApplicationRepository,
+	prober HTTPProber,
+	concurrency int,
+	timeout time.Duration,
+) *ProbeApplications {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &ProbeApplications{
+		ApplicationRepo: applicationRepo,
+		Prober:          prober,
+		Concurrency:     concurrency,
+		Timeout:         timeout,
+		Now:             time.Now,
+	}
+}
+
+// Handle probes every application with an enabled health check once and
+// records the result. It returns after all probes have finished.
+func (h *ProbeApplications) Handle(ctx context.Context) error {
+	apps, err := h.ApplicationRepo.List(ctx)
+	if err != nil {
+		return domainerrors.Internal("probe applications: list", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		slots    = make(chan struct{}, h.Concurrency)
+	)
+	for _, app := range apps {
+		if !app.HealthCheck {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(app domainrepo.ApplicationRecord) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			health := h.probe(ctx, app)
+			if err := h.ApplicationRepo.RecordHealth(ctx, app.ID, health); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = domainerrors.Internal("probe applications: record health", err)
+				}
+				mu.Unlock()
+			}
+		}(app)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (h *ProbeApplications) probe(ctx context.Context, app domainrepo.ApplicationRecord) domainrepo.ApplicationHealthRecord {
+	url := app.HealthURL
+	if url == "" {
+		url = app.Url
+	}
+	expected, err := domainmodel.ParseStatusCodes(app.HealthExpectedStatus)
+	if err != nil {
+		expected, _ = domainmodel.ParseStatusCodes("")
+	}
+
+	probeCtx := ctx
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		probeCtx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	start := h.Now()
+	status, err := h.Prober.Probe(probeCtx, url)
+	checkedAt := h.Now()
+	health := domainrepo.ApplicationHealthRecord{
+		LatencyMs: checkedAt.Sub(start).Milliseconds(),
+		CheckedAt: checkedAt,
+	}
+	switch {
+	case err != nil:
+		health.State = string(domainmodel.HealthDown)
+		health.Error = err.Error()
+	case !expected.Contains(status):
+		health.State = string(domainmodel.HealthDown)
+		health.Error = "unexpected status " + strconv.Itoa(status)
+	default:
+		health.State = string(domainmodel.HealthUp)
+	}
+	return health
+}
@@ -0,0 +1,95 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestProbeApplications_Handle_ListError(t *testing.T) {
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return(nil, errors.New("db error"))
+
+	h := command.NewProbeApplications(appRepo, nil, 2, time.Second)
+	err := h.Handle(context.Background())
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestProbeApplications_Handle_RecordsResults(t *testing.T) {
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{
+		{ID: 1, Url: "https://up.lan", HealthCheck: true},
+		{ID: 2, Url: "https://app.lan", HealthCheck: true, HealthURL: "https://app.lan/healthz", HealthExpectedStatus: "204"},
+		{ID: 3, Url: "https://down.lan", HealthCheck: true},
+		{ID: 4, Url: "https://unchecked.lan"},
+	}, nil)
+	appRepo.On("RecordHealth", mock.Anything, uint(1), mock.MatchedBy(func(r domainrepo.ApplicationHealthRecord) bool {
+		return r.State == "up" && r.Error == "" && !r.CheckedAt.IsZero()
+	})).Return(nil)
+	appRepo.On("RecordHealth", mock.Anything, uint(2), mock.MatchedBy(func(r domainrepo.ApplicationHealthRecord) bool {
+		return r.State == "down" && r.Error == "unexpected status 200"
+	})).Return(nil)
+	appRepo.On("RecordHealth", mock.Anything, uint(3), mock.MatchedBy(func(r domainrepo.ApplicationHealthRecord) bool {
+		return r.State == "down" && r.Error == "connection refused"
+	})).Return(nil)
+
+	prober := &repoMock.HTTPProber{}
+	prober.On("Probe", mock.Anything, "https://up.lan").Return(301, nil)
+	prober.On("Probe", mock.Anything, "https://app.lan/healthz").Return(200, nil)
+	prober.On("Probe", mock.Anything, "https://down.lan").Return(0, errors.New("connection refused"))
+
+	h := command.NewProbeApplications(appRepo, prober, 2, time.Second)
+	err := h.Handle(context.Background())
+
+	require.NoError(t, err)
+	appRepo.AssertExpectations(t)
+	prober.AssertNotCalled(t, "Probe", mock.Anything, "https://unchecked.lan")
+}
+
+// blockingProber tracks how many probes run at the same time.
+type blockingProber struct {
+	inFlight, max atomic.Int32
+}
+
+func (p *blockingProber) Probe(ctx context.Context, url string) (int, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		m := p.max.Load()
+		if n <= m || p.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return 200, nil
+}
+
+func TestProbeApplications_Handle_BoundedConcurrency(t *testing.T) {
+	apps := make([]domainrepo.ApplicationRecord, 10)
+	for i := range apps {
+		apps[i] = domainrepo.ApplicationRecord{ID: uint(i + 1), Url: "https://app.lan", HealthCheck: true}
+	}
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return(apps, nil)
+	appRepo.On("RecordHealth", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	prober := &blockingProber{}
+	h := command.NewProbeApplications(appRepo, prober, 3, time.Second)
+	err := h.Handle(context.Background())
+
+	require.NoError(t, err)
+	require.LessOrEqual(t, prober.max.Load(), int32(3))
+	appRepo.AssertNumberOfCalls(t, "RecordHealth", 10)
+}
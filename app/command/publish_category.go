@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// PublishCategoryCmd is the input for publishing a category to groups. An
+// empty VisibleToGroups publishes it to everyone.
+type PublishCategoryCmd struct {
+	CategoryID      uint     `validate:"required,gt=0"`
+	VisibleToGroups []string `validate:"dive,required,max=256"`
+}
+
+// CategoryPublisher handles the publish-category command.
+type CategoryPublisher interface {
+	Handle(ctx context.Context, userId string, isAdmin bool, actor domainmodel.AuditActor, in PublishCategoryCmd) error
+}
+
+type PublishCategory struct {
+	DashboardRepo      domainrepo.DashboardRepository
+	CategoryRepo       domainrepo.CategoryRepository
+	SharedCategoryRepo domainrepo.SharedCategoryRepository
+	Validator          validation.Validator
+	Audit              AuditRecorder
+}
+
+func NewPublishCategory(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	sharedCategoryRepo domainrepo.SharedCategoryRepository,
+	validator validation.Validator,
+	audit AuditRecorder,
+) *PublishCategory {
+	return &PublishCategory{
+		DashboardRepo:      dashboardRepo,
+		CategoryRepo:       categoryRepo,
+		SharedCategoryRepo: sharedCategoryRepo,
+		Validator:          validator,
+		Audit:              audit,
+	}
+}
+
+// Handle publishes a category of the admin's own dashboard, or changes the
+// groups of a category that is already published, whoever published it.
+func (h *PublishCategory) Handle(ctx context.Context, userId string, isAdmin bool, actor domainmodel.AuditActor, in PublishCategoryCmd) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may publish categories")
+	}
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	catRecord, err := h.CategoryRepo.Get(ctx, in.CategoryID)
+	if err != nil {
+		return domainerrors.WrapRepo("publish category: get category", err)
+	}
+
+	_, err = h.SharedCategoryRepo.Get(ctx, in.CategoryID)
+	var nfe *domainerrors.NotFoundError
+	switch {
+	case errors.As(err, &nfe):
+		if _, err := ownedDashboard(ctx, h.DashboardRepo, "publish category: get dashboard", userId, catRecord.DashboardID); err != nil {
+			return err
+		}
+	case err != nil:
+		return domainerrors.Internal("publish category: get shared category", err)
+	}
+	if catRecord.TagQuery != "" {
+		return domainerrors.Validation(domainerrors.Violation{Field: "CategoryID", Message: "smart categories cannot be published"})
+	}
+	nested, err := hasSubCategories(ctx, h.CategoryRepo, catRecord)
+	if err != nil {
+		return domainerrors.Internal("publish category: list categories", err)
+	}
+	if nested {
+		return domainerrors.Validation(domainerrors.Violation{Field: "CategoryID", Message: "categories with sub-categories cannot be published"})
+	}
+
+	if err := h.SharedCategoryRepo.Publish(ctx, &domainrepo.SharedCategoryRecord{
+		CategoryID:      catRecord.ID,
+		VisibleToGroups: in.VisibleToGroups,
+		PublishedBy:     userId,
+	}); err != nil {
+		return domainerrors.Internal("publish category: publish", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionCategoryPublish, catRecord.DisplayName)
+	return nil
+}
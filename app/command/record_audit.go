@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// AuditRecorder handles the record-audit command. The audited commands use it
+// after their action succeeded; the delivery layer uses it directly for events
+// that have no command of their own, such as failed logins.
+//
+// Recording is best effort: the action has already happened, so a failure to
+// write the entry is logged instead of being reported to the caller.
+type AuditRecorder interface {
+	Handle(ctx context.Context, actor domainmodel.AuditActor, action domainmodel.AuditAction, target string)
+}
+
+type RecordAudit struct {
+	Repo domainrepo.AuditLogRepository
+	Now  func() time.Time
+	// Logf reports entries that could not be written.
+	Logf func(format string, args ...any)
+}
+
+func NewRecordAudit(repo domainrepo.AuditLogRepository) *RecordAudit {
+	return &RecordAudit{Repo: repo, Now: time.Now, Logf: log.Printf}
+}
+
+func (h *RecordAudit) Handle(ctx context.Context, actor domainmodel.AuditActor, action domainmodel.AuditAction, target string) {
+	if err := h.Repo.Create(ctx, &domainmodel.AuditEntry{
+		ActorID:   actor.UserID,
+		Action:    action,
+		Target:    target,
+		IP:        actor.IP,
+		CreatedAt: h.Now(),
+	}); err != nil {
+		h.Logf("record audit: %s by %q on %q: %v", action, actor.UserID, target, err)
+	}
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// InvitationRedeemer handles the redeem-invitation command.
+type InvitationRedeemer interface {
+	Handle(ctx context.Context, actor domainmodel.AuditActor, secret string) error
+}
+
+type RedeemInvitation struct {
+	Repo  domainrepo.InvitationRepository
+	Audit AuditRecorder
+	Now   func() time.Time
+}
+
+func NewRedeemInvitation(repo domainrepo.InvitationRepository, audit AuditRecorder) *RedeemInvitation {
+	return &RedeemInvitation{Repo: repo, Audit: audit, Now: time.Now}
+}
+
+// Handle grants the groups of the invitation to the signed-in user for the
+// invitation's grant duration. Every user can redeem an invitation once;
+// opening the link again does nothing, even after the grant has ended.
+func (h *RedeemInvitation) Handle(ctx context.Context, actor domainmodel.AuditActor, secret string) error {
+	invitation, err := h.Repo.GetByTokenHash(ctx, domainmodel.HashInvitationToken(secret))
+	if err != nil {
+		return domainerrors.WrapRepo("redeem invitation: get by token", err)
+	}
+
+	redeemedBefore, err := h.Repo.HasRedeemed(ctx, invitation.ID, actor.UserID)
+	if err != nil {
+		return domainerrors.Internal("redeem invitation: has redeemed", err)
+	}
+	if redeemedBefore {
+		return nil
+	}
+
+	now := h.Now()
+	redeemed, err := h.Repo.Redeem(ctx, &domainrepo.InvitationRedemptionRecord{
+		InvitationID: invitation.ID,
+		UserID:       actor.UserID,
+		Groups:       invitation.Groups,
+		ExpiresAt:    now.Add(invitation.GrantDuration),
+	}, now)
+	if err != nil {
+		return domainerrors.Internal("redeem invitation: redeem", err)
+	}
+	if !redeemed {
+		return domainerrors.Forbidden("invitation has expired or has been used up")
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionInvitationRedeem, invitation.ID)
+	return nil
+}
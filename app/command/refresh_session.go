@@ -11,20 +11,23 @@ import (
 // session after re-authentication via OIDC. Identity fields are re-extracted
 // from the new token so group changes take effect immediately.
 type RefreshSessionCmd struct {
-	SessionID   string
-	Sub         string
-	Username    string
-	Email       string
-	FirstName   string
-	LastName    string
-	DisplayName string
-	Picture     string
-	ProfileUrl  string
-	RawIDToken  string
-	Groups      []string
-	IsAdmin     bool
-	IssuedAt    time.Time
-	ExpiresAt   time.Time
+	SessionID    string
+	Issuer       string
+	Sub          string
+	Sid          string
+	Username     string
+	Email        string
+	FirstName    string
+	LastName     string
+	DisplayName  string
+	Picture      string
+	ProfileUrl   string
+	RawIDToken   string
+	RefreshToken string
+	Groups       []string
+	IsAdmin      bool
+	IssuedAt     time.Time
+	ExpiresAt    time.Time
 }
 
 // SessionRefresher handles the refresh-session command.
@@ -42,19 +45,22 @@ func NewRefreshSession(repo domainrepo.SessionRepository) *RefreshSession {
 
 func (h *RefreshSession) Handle(ctx context.Context, cmd RefreshSessionCmd) error {
 	return h.repo.RefreshBySessionID(ctx, &domainrepo.SessionRecord{
-		SessionID:   cmd.SessionID,
-		Sub:         cmd.Sub,
-		Username:    cmd.Username,
-		Email:       cmd.Email,
-		FirstName:   cmd.FirstName,
-		LastName:    cmd.LastName,
-		DisplayName: cmd.DisplayName,
-		Picture:     cmd.Picture,
-		ProfileUrl:  cmd.ProfileUrl,
-		RawIDToken:  cmd.RawIDToken,
-		Groups:      cmd.Groups,
-		IsAdmin:     cmd.IsAdmin,
-		IssuedAt:    cmd.IssuedAt,
-		ExpiresAt:   cmd.ExpiresAt,
+		SessionID:    cmd.SessionID,
+		Issuer:       cmd.Issuer,
+		Sub:          cmd.Sub,
+		Sid:          cmd.Sid,
+		Username:     cmd.Username,
+		Email:        cmd.Email,
+		FirstName:    cmd.FirstName,
+		LastName:     cmd.LastName,
+		DisplayName:  cmd.DisplayName,
+		Picture:      cmd.Picture,
+		ProfileUrl:   cmd.ProfileUrl,
+		RawIDToken:   cmd.RawIDToken,
+		RefreshToken: cmd.RefreshToken,
+		Groups:       cmd.Groups,
+		IsAdmin:      cmd.IsAdmin,
+		IssuedAt:     cmd.IssuedAt,
+		ExpiresAt:    cmd.ExpiresAt,
 	})
 }
@@ -0,0 +1,33 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalGroupMemberRemover handles the remove-local-group-member command.
+type LocalGroupMemberRemover interface {
+	Handle(ctx context.Context, isAdmin bool, groupID uint, userID string) error
+}
+
+type RemoveLocalGroupMember struct {
+	Repo domainrepo.LocalGroupRepository
+}
+
+func NewRemoveLocalGroupMember(repo domainrepo.LocalGroupRepository) *RemoveLocalGroupMember {
+	return &RemoveLocalGroupMember{Repo: repo}
+}
+
+// Handle removes a user from a local group. Users who belong to the group
+// through a mapped IdP group stay members.
+func (h *RemoveLocalGroupMember) Handle(ctx context.Context, isAdmin bool, groupID uint, userID string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage groups")
+	}
+	if err := h.Repo.RemoveMember(ctx, groupID, userID); err != nil {
+		return domainerrors.Internal("remove local group member", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// ReorderApplicationsCmd is the input for rearranging application links. IDs
+// lists applications in their new order; applications not listed keep their
+// position.
+type ReorderApplicationsCmd struct {
+	IDs []uint `validate:"required,min=1,dive,gt=0"`
+}
+
+// ApplicationsReorderer handles the ReorderApplicationsCmd command.
+type ApplicationsReorderer interface {
+	Handle(ctx context.Context, in ReorderApplicationsCmd) error
+}
+
+type ReorderApplications struct {
+	ApplicationRepo domainrepo.ApplicationRepository
+	Validator       validation.Validator
+}
+
+func NewReorderApplications(
+	applicationRepo domainrepo.ApplicationRepository,
+	validator validation.Validator,
+) *ReorderApplications {
+	return &ReorderApplications{
+		ApplicationRepo: applicationRepo,
+		Validator:       validator,
+	}
+}
+
+func (h *ReorderApplications) Handle(ctx context.Context, in ReorderApplicationsCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if hasDuplicateIDs(in.IDs) {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IDs", Message: "ids must be unique"})
+	}
+
+	apps, err := h.ApplicationRepo.List(ctx)
+	if err != nil {
+		return domainerrors.Internal("reorder applications: list", err)
+	}
+	if !containsAllIDs(apps, func(a domainrepo.ApplicationRecord) uint { return a.ID }, in.IDs) {
+		return domainerrors.NotFound(domainerrors.EntityApplication)
+	}
+
+	if err := h.ApplicationRepo.Reorder(ctx, in.IDs); err != nil {
+		return domainerrors.Internal("reorder applications: reorder", err)
+	}
+	return nil
+}
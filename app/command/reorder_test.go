@@ -0,0 +1,197 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── ReorderUserCategories ──────────────────────────────────────────────────
+
+func TestReorderUserCategories_Handle_ValidationError(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(errors.New("failed"))
+
+	h := command.NewReorderUserCategories(nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserCategoriesCmd{})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestReorderUserCategories_Handle_DuplicateIDs(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	h := command.NewReorderUserCategories(nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserCategoriesCmd{IDs: []uint{1, 2, 1}})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestReorderUserCategories_Handle_ForeignCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).
+		Return([]domainrepo.CategoryRecord{{ID: 1, DashboardID: 10}, {ID: 2, DashboardID: 10}}, nil)
+
+	h := command.NewReorderUserCategories(dashRepo, catRepo, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserCategoriesCmd{IDs: []uint{2, 99}})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	catRepo.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorderUserCategories_Handle_Success(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).
+		Return([]domainrepo.CategoryRecord{{ID: 1, DashboardID: 10}, {ID: 2, DashboardID: 10}}, nil)
+	catRepo.On("Reorder", mock.Anything, uint(10), []uint{2, 1}).Return(nil)
+
+	h := command.NewReorderUserCategories(dashRepo, catRepo, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserCategoriesCmd{IDs: []uint{2, 1}})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+// ── ReorderUserBookmarks ───────────────────────────────────────────────────
+
+func TestReorderUserBookmarks_Handle_ForbiddenWrongOwner(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+
+	h := command.NewReorderUserBookmarks(dashRepo, catRepo, nil, noCategoryShare(), v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserBookmarksCmd{CategoryID: 5, IDs: []uint{1}})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestReorderUserBookmarks_Handle_BookmarkOfOtherCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).
+		Return([]domainrepo.BookmarkRecord{{ID: 1, CategoryID: 5}}, nil)
+
+	h := command.NewReorderUserBookmarks(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserBookmarksCmd{CategoryID: 5, IDs: []uint{1, 7}})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	bookmarkRepo.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReorderUserBookmarks_Handle_Success(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).
+		Return([]domainrepo.BookmarkRecord{{ID: 1, CategoryID: 5}, {ID: 2, CategoryID: 5}}, nil)
+	bookmarkRepo.On("Reorder", mock.Anything, uint(5), []uint{2, 1}).Return(nil)
+
+	h := command.NewReorderUserBookmarks(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserBookmarksCmd{CategoryID: 5, IDs: []uint{2, 1}})
+
+	require.NoError(t, err)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+// ── ReorderApplications ────────────────────────────────────────────────────
+
+func TestReorderApplications_Handle_UnknownApplication(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).
+		Return([]domainrepo.ApplicationRecord{{ID: 1}}, nil)
+
+	h := command.NewReorderApplications(appRepo, v)
+	err := h.Handle(context.Background(), command.ReorderApplicationsCmd{IDs: []uint{1, 3}})
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+func TestReorderApplications_Handle_ReorderError(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).
+		Return([]domainrepo.ApplicationRecord{{ID: 1}, {ID: 2}}, nil)
+	appRepo.On("Reorder", mock.Anything, []uint{2, 1}).Return(errors.New("db error"))
+
+	h := command.NewReorderApplications(appRepo, v)
+	err := h.Handle(context.Background(), command.ReorderApplicationsCmd{IDs: []uint{2, 1}})
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestReorderApplications_Handle_Success(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).
+		Return([]domainrepo.ApplicationRecord{{ID: 1}, {ID: 2}}, nil)
+	appRepo.On("Reorder", mock.Anything, []uint{2, 1}).Return(nil)
+
+	h := command.NewReorderApplications(appRepo, v)
+	err := h.Handle(context.Background(), command.ReorderApplicationsCmd{IDs: []uint{2, 1}})
+
+	require.NoError(t, err)
+	appRepo.AssertExpectations(t)
+}
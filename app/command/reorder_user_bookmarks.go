@@ -0,0 +1,82 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// ReorderUserBookmarksCmd is the input for rearranging the bookmarks of one
+// category. IDs lists bookmarks in their new order; bookmarks not listed keep
+// their position.
+type ReorderUserBookmarksCmd struct {
+	CategoryID uint   `validate:"required,gt=0"`
+	IDs        []uint `validate:"required,min=1,dive,gt=0"`
+}
+
+// UserBookmarksReorderer handles the ReorderUserBookmarksCmd command.
+type UserBookmarksReorderer interface {
+	Handle(ctx context.Context, userId string, in ReorderUserBookmarksCmd) error
+}
+
+type ReorderUserBookmarks struct {
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	Validator         validation.Validator
+}
+
+func NewReorderUserBookmarks(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	validator validation.Validator,
+) *ReorderUserBookmarks {
+	return &ReorderUserBookmarks{
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		BookmarkRepo:      bookmarkRepo,
+		CategoryShareRepo: categoryShareRepo,
+		Validator:         validator,
+	}
+}
+
+func (h *ReorderUserBookmarks) Handle(ctx context.Context, userId string, in ReorderUserBookmarksCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if hasDuplicateIDs(in.IDs) {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IDs", Message: "ids must be unique"})
+	}
+
+	catRecord, err := h.CategoryRepo.Get(ctx, in.CategoryID)
+	if err != nil {
+		return domainerrors.WrapRepo("reorder user bookmarks: get category", err)
+	}
+
+	canEdit, err := canEditBookmarks(ctx, h.DashboardRepo, h.CategoryShareRepo, userId, catRecord)
+	if err != nil {
+		return domainerrors.WrapRepo("reorder user bookmarks: check category access", err)
+	}
+	if !canEdit {
+		return domainerrors.Forbidden("user may not edit bookmarks of category")
+	}
+
+	bookmarkRecords, err := h.BookmarkRepo.ListByCategoryIDs(ctx, []uint{catRecord.ID})
+	if err != nil {
+		return domainerrors.Internal("reorder user bookmarks: list bookmarks", err)
+	}
+	if !containsAllIDs(bookmarkRecords, func(b domainrepo.BookmarkRecord) uint { return b.ID }, in.IDs) {
+		return domainerrors.Forbidden("bookmark does not belong to category")
+	}
+
+	if err := h.BookmarkRepo.Reorder(ctx, catRecord.ID, in.IDs); err != nil {
+		return domainerrors.Internal("reorder user bookmarks: reorder", err)
+	}
+	return nil
+}
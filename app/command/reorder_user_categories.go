@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// ReorderUserCategoriesCmd is the input for rearranging the categories of one
+// of the user's dashboards; DashboardID 0 means the default one. IDs lists
+// categories in their new order; categories not listed keep their position.
+type ReorderUserCategoriesCmd struct {
+	DashboardID uint
+	IDs         []uint `validate:"required,min=1,dive,gt=0"`
+}
+
+// UserCategoriesReorderer handles the ReorderUserCategoriesCmd command.
+type UserCategoriesReorderer interface {
+	Handle(ctx context.Context, userId string, in ReorderUserCategoriesCmd) error
+}
+
+type ReorderUserCategories struct {
+	DashboardRepo domainrepo.DashboardRepository
+	CategoryRepo  domainrepo.CategoryRepository
+	Validator     validation.Validator
+}
+
+func NewReorderUserCategories(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	validator validation.Validator,
+) *ReorderUserCategories {
+	return &ReorderUserCategories{
+		DashboardRepo: dashboardRepo,
+		CategoryRepo:  categoryRepo,
+		Validator:     validator,
+	}
+}
+
+func (h *ReorderUserCategories) Handle(ctx context.Context, userId string, in ReorderUserCategoriesCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if hasDuplicateIDs(in.IDs) {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IDs", Message: "ids must be unique"})
+	}
+
+	dash, err := ownedDashboard(ctx, h.DashboardRepo, "reorder user categories: get dashboard", userId, in.DashboardID)
+	if err != nil {
+		return err
+	}
+
+	catRecords, err := h.CategoryRepo.ListByDashboardID(ctx, dash.ID())
+	if err != nil {
+		return domainerrors.Internal("reorder user categories: list categories", err)
+	}
+	if !containsAllIDs(catRecords, func(c domainrepo.CategoryRecord) uint { return c.ID }, in.IDs) {
+		return domainerrors.Forbidden("user does not own dashboard")
+	}
+
+	if err := h.CategoryRepo.Reorder(ctx, dash.ID(), in.IDs); err != nil {
+		return domainerrors.Internal("reorder user categories: reorder", err)
+	}
+	return nil
+}
+
+// hasDuplicateIDs reports whether ids lists any id more than once.
+func hasDuplicateIDs(ids []uint) bool {
+	seen := make(map[uint]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return true
+		}
+		seen[id] = struct{}{}
+	}
+	return false
+}
+
+// containsAllIDs reports whether every id in ids identifies one of records.
+func containsAllIDs[T any](records []T, idOf func(T) uint, ids []uint) bool {
+	known := make(map[uint]struct{}, len(records))
+	for _, r := range records {
+		known[idOf(r)] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := known[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
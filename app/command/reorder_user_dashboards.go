@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// ReorderUserDashboardsCmd is the input for rearranging the user's dashboards.
+// IDs lists dashboards in their new order; dashboards not listed keep their
+// position.
+type ReorderUserDashboardsCmd struct {
+	IDs []uint `validate:"required,min=1,dive,gt=0"`
+}
+
+// UserDashboardsReorderer handles the ReorderUserDashboardsCmd command.
+type UserDashboardsReorderer interface {
+	Handle(ctx context.Context, userId string, in ReorderUserDashboardsCmd) error
+}
+
+type ReorderUserDashboards struct {
+	DashboardRepo domainrepo.DashboardRepository
+	Validator     validation.Validator
+}
+
+func NewReorderUserDashboards(
+	dashboardRepo domainrepo.DashboardRepository,
+	validator validation.Validator,
+) *ReorderUserDashboards {
+	return &ReorderUserDashboards{
+		DashboardRepo: dashboardRepo,
+		Validator:     validator,
+	}
+}
+
+func (h *ReorderUserDashboards) Handle(ctx context.Context, userId string, in ReorderUserDashboardsCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if hasDuplicateIDs(in.IDs) {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IDs", Message: "ids must be unique"})
+	}
+
+	dashboards, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return domainerrors.Internal("reorder user dashboards: list dashboards", err)
+	}
+	if !containsAllIDs(dashboards, func(d domainrepo.DashboardRecord) uint { return d.ID }, in.IDs) {
+		return domainerrors.Forbidden("user does not own dashboard")
+	}
+
+	if err := h.DashboardRepo.Reorder(ctx, userId, in.IDs); err != nil {
+		return domainerrors.Internal("reorder user dashboards: reorder", err)
+	}
+	return nil
+}
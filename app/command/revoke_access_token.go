@@ -0,0 +1,30 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// AccessTokenRevoker handles the revoke-access-token command.
+// Revocation deletes the record, so the next request carrying the token is
+// treated as unauthenticated.
+type AccessTokenRevoker interface {
+	Handle(ctx context.Context, userID string, tokenID string) error
+}
+
+type RevokeAccessToken struct {
+	Repo domainrepo.AccessTokenRepository
+}
+
+func NewRevokeAccessToken(repo domainrepo.AccessTokenRepository) *RevokeAccessToken {
+	return &RevokeAccessToken{Repo: repo}
+}
+
+func (h *RevokeAccessToken) Handle(ctx context.Context, userID string, tokenID string) error {
+	if err := h.Repo.DeleteByID(ctx, tokenID, userID); err != nil {
+		return domainerrors.Internal("revoke access token", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CategoryShareRevoker handles the revoke-category-share command.
+type CategoryShareRevoker interface {
+	Handle(ctx context.Context, userId string, actor domainmodel.AuditActor, categoryID uint, shareUserID string) error
+}
+
+type RevokeCategoryShare struct {
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	Audit             AuditRecorder
+}
+
+func NewRevokeCategoryShare(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	audit AuditRecorder,
+) *RevokeCategoryShare {
+	return &RevokeCategoryShare{
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		CategoryShareRepo: categoryShareRepo,
+		Audit:             audit,
+	}
+}
+
+// Handle stops sharing a category of the user's own dashboard with another
+// user.
+func (h *RevokeCategoryShare) Handle(ctx context.Context, userId string, actor domainmodel.AuditActor, categoryID uint, shareUserID string) error {
+	catRecord, err := h.CategoryRepo.Get(ctx, categoryID)
+	if err != nil {
+		return domainerrors.WrapRepo("revoke category share: get category", err)
+	}
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "revoke category share: get dashboard", userId, catRecord.DashboardID); err != nil {
+		return err
+	}
+
+	if err := h.CategoryShareRepo.Delete(ctx, categoryID, shareUserID); err != nil {
+		return domainerrors.WrapRepo("revoke category share: delete", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionCategoryUnshare, catRecord.DisplayName)
+	return nil
+}
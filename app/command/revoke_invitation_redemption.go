@@ -0,0 +1,39 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// InvitationRedemptionRevoker handles the revoke-invitation-redemption
+// command.
+type InvitationRedemptionRevoker interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, invitationID string, id uint) error
+}
+
+type RevokeInvitationRedemption struct {
+	Repo  domainrepo.InvitationRepository
+	Audit AuditRecorder
+	Now   func() time.Time
+}
+
+func NewRevokeInvitationRedemption(repo domainrepo.InvitationRepository, audit AuditRecorder) *RevokeInvitationRedemption {
+	return &RevokeInvitationRedemption{Repo: repo, Audit: audit, Now: time.Now}
+}
+
+// Handle takes the granted groups away from one guest. The redemption stays
+// recorded, so the guest cannot redeem the same link again.
+func (h *RevokeInvitationRedemption) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, invitationID string, id uint) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may invite guests")
+	}
+	if err := h.Repo.RevokeRedemption(ctx, id, h.Now()); err != nil {
+		return domainerrors.WrapRepo("revoke invitation redemption", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionInvitationRevoke, invitationID)
+	return nil
+}
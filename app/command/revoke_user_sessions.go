@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserSessionsRevoker handles the admin command that signs a user out on
+// every device.
+type UserSessionsRevoker interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, userID string) error
+}
+
+type RevokeUserSessions struct {
+	Repo      domainrepo.SessionRepository
+	TokenRepo domainrepo.AccessTokenRepository
+	Audit     AuditRecorder
+}
+
+func NewRevokeUserSessions(repo domainrepo.SessionRepository, tokenRepo domainrepo.AccessTokenRepository, audit AuditRecorder) *RevokeUserSessions {
+	return &RevokeUserSessions{Repo: repo, TokenRepo: tokenRepo, Audit: audit}
+}
+
+// Handle deletes all sessions of the user, including pinned ones, and all of
+// their access tokens, so no client stays signed in. Admins sign themselves
+// out from the session settings instead, like DeleteUser.
+func (h *RevokeUserSessions) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, userID string) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage users")
+	}
+	if actor.UserID == userID {
+		return domainerrors.Validation(domainerrors.Violation{Field: "user", Message: "cannot sign yourself out here"})
+	}
+	if err := h.Repo.DeleteByUserID(ctx, userID); err != nil {
+		return domainerrors.Internal("revoke user sessions", err)
+	}
+	if err := h.TokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return domainerrors.Internal("revoke user sessions: delete access tokens", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionUserSessionsRevoke, userID)
+	return nil
+}
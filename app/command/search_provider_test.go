@@ -0,0 +1,170 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func validSearchProviderCmd() command.CreateUserSearchProviderCmd {
+	return command.CreateUserSearchProviderCmd{
+		DisplayName: "GitHub",
+		Bang:        "!GH",
+		URLTemplate: "https://github.com/search?q={q}",
+	}
+}
+
+// ── CreateUserSearchProvider ───────────────────────────────────────────────
+
+func TestCreateUserSearchProvider_Handle_InvalidBang(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	cmd := validSearchProviderCmd()
+	cmd.Bang = "g h"
+	h := command.NewCreateUserSearchProvider(nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Equal(t, "Bang", ve.Violations[0].Field)
+}
+
+func TestCreateUserSearchProvider_Handle_InvalidURLTemplate(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	cmd := validSearchProviderCmd()
+	cmd.URLTemplate = "https://github.com/search"
+	h := command.NewCreateUserSearchProvider(nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Equal(t, "URLTemplate", ve.Violations[0].Field)
+}
+
+func TestCreateUserSearchProvider_Handle_DuplicateBang(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{
+		{ID: 1, Bang: "gh"},
+	}, nil)
+
+	h := command.NewCreateUserSearchProvider(repo, nil, v)
+	err := h.Handle(context.Background(), "user-1", validSearchProviderCmd())
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserSearchProvider_Handle_FirstBecomesDefault(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{}, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.SearchProviderRecord) bool {
+		return r.Bang == "gh" && r.UserID == "user-1"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.SearchProviderRecord).ID = 7
+	}).Return(nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.SettingRecord) bool {
+		return r.SearchProviderID == 7
+	})).Return(nil)
+
+	h := command.NewCreateUserSearchProvider(repo, settingRepo, v)
+	err := h.Handle(context.Background(), "user-1", validSearchProviderCmd())
+
+	require.NoError(t, err)
+	settingRepo.AssertExpectations(t)
+}
+
+func TestCreateUserSearchProvider_Handle_KeepsExistingDefault(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{
+		{ID: 1, Bang: "ddg"},
+	}, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1", SearchProviderID: 1}, nil)
+
+	h := command.NewCreateUserSearchProvider(repo, settingRepo, v)
+	err := h.Handle(context.Background(), "user-1", validSearchProviderCmd())
+
+	require.NoError(t, err)
+	settingRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+// ── DeleteUserSearchProvider ───────────────────────────────────────────────
+
+func TestDeleteUserSearchProvider_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("Delete", mock.Anything, "user-1", uint(1)).Return(errors.New("db error"))
+
+	h := command.NewDeleteUserSearchProvider(repo)
+	err := h.Handle(context.Background(), "user-1", 1)
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+// ── UpdateUserSettings ─────────────────────────────────────────────────────
+
+func TestUpdateUserSettings_Handle_SearchProviderNotFound(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("GetByID", mock.Anything, "user-1", uint(4)).
+		Return(nil, domainerrors.NotFound(domainerrors.EntitySearchProvider))
+
+	id := uint(4)
+	h := command.NewUpdateUserSettings(settingRepo, nil, repo, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{SearchProviderID: &id})
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+func TestUpdateUserSettings_Handle_SearchProviderUnchangedWhenNil(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1", SearchProviderID: 2}, nil)
+	settingRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.SettingRecord) bool {
+		return r.SearchProviderID == 2
+	})).Return(nil)
+
+	h := command.NewUpdateUserSettings(settingRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{})
+
+	require.NoError(t, err)
+	settingRepo.AssertExpectations(t)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
 )
 
@@ -18,19 +20,22 @@ func TestInvalidateSession_Handle_Success(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("DeleteByID", mock.Anything, "record-1", "user-1").Return(nil)
 
-	h := command.NewInvalidateSession(repo)
-	err := h.Handle(context.Background(), "user-1", "record-1")
+	auditRepo := expectAudit(domainmodel.AuditActionSessionRevoke, "record-1")
+
+	h := command.NewInvalidateSession(repo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, "record-1")
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestInvalidateSession_Handle_RepoError(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("DeleteByID", mock.Anything, "record-1", "user-1").Return(errors.New("db error"))
 
-	h := command.NewInvalidateSession(repo)
-	err := h.Handle(context.Background(), "user-1", "record-1")
+	h := command.NewInvalidateSession(repo, nil)
+	err := h.Handle(context.Background(), testActor, "record-1")
 
 	require.Error(t, err)
 }
@@ -87,19 +92,22 @@ func TestPinSession_Handle_Success(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("Pin", mock.Anything, "session-abc", "user-1", mock.AnythingOfType("time.Time")).Return(nil)
 
-	h := command.NewPinSession(repo)
-	err := h.Handle(context.Background(), "user-1", command.PinSessionCmd{SessionID: "session-abc"})
+	auditRepo := expectAudit(domainmodel.AuditActionSessionPin, "")
+
+	h := command.NewPinSession(repo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, command.PinSessionCmd{SessionID: "session-abc"})
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestPinSession_Handle_RepoError(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("Pin", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewPinSession(repo)
-	err := h.Handle(context.Background(), "user-1", command.PinSessionCmd{SessionID: "session-abc"})
+	h := command.NewPinSession(repo, nil)
+	err := h.Handle(context.Background(), testActor, command.PinSessionCmd{SessionID: "session-abc"})
 
 	require.Error(t, err)
 }
@@ -110,19 +118,58 @@ func TestUnpinSession_Handle_Success(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("Unpin", mock.Anything, "session-abc", "user-1").Return(nil)
 
-	h := command.NewUnpinSession(repo)
-	err := h.Handle(context.Background(), "user-1", "session-abc")
+	auditRepo := expectAudit(domainmodel.AuditActionSessionUnpin, "session-abc")
+
+	h := command.NewUnpinSession(repo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, "session-abc")
 
 	require.NoError(t, err)
 	repo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestUnpinSession_Handle_RepoError(t *testing.T) {
 	repo := &repoMock.SessionRepository{}
 	repo.On("Unpin", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	h := command.NewUnpinSession(repo)
-	err := h.Handle(context.Background(), "user-1", "session-abc")
+	h := command.NewUnpinSession(repo, nil)
+	err := h.Handle(context.Background(), testActor, "session-abc")
 
 	require.Error(t, err)
 }
+
+// ── EndIdpSession ──────────────────────────────────────────────────────────
+
+func TestEndIdpSession_Handle_BySid(t *testing.T) {
+	repo := &repoMock.SessionRepository{}
+	repo.On("DeleteBySid", mock.Anything, "https://idp", "sid-1").Return(nil)
+
+	h := command.NewEndIdpSession(repo)
+	err := h.Handle(context.Background(), command.EndIdpSessionCmd{Issuer: "https://idp", Sub: "sub-1", Sid: "sid-1"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DeleteBySub", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEndIdpSession_Handle_BySub(t *testing.T) {
+	repo := &repoMock.SessionRepository{}
+	repo.On("DeleteBySub", mock.Anything, "https://idp", "sub-1").Return(nil)
+
+	h := command.NewEndIdpSession(repo)
+	err := h.Handle(context.Background(), command.EndIdpSessionCmd{Issuer: "https://idp", Sub: "sub-1"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestEndIdpSession_Handle_MissingSubAndSid(t *testing.T) {
+	repo := &repoMock.SessionRepository{}
+
+	h := command.NewEndIdpSession(repo)
+	err := h.Handle(context.Background(), command.EndIdpSessionCmd{Issuer: "https://idp"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	repo.AssertNotCalled(t, "DeleteBySub", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"context"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// SetLocalAccountPasswordCmd is the input for resetting a local account password.
+type SetLocalAccountPasswordCmd struct {
+	Username string `validate:"required"`
+	Password string `validate:"required,min=8,max=72"`
+}
+
+// LocalAccountPasswordSetter handles the set-local-account-password command.
+type LocalAccountPasswordSetter interface {
+	Handle(ctx context.Context, isAdmin bool, in SetLocalAccountPasswordCmd) error
+}
+
+type SetLocalAccountPassword struct {
+	Repo        domainrepo.LocalAccountRepository
+	SessionRepo domainrepo.SessionRepository
+	Validator   validation.Validator
+	Issuer      string // issuer of local account sessions
+}
+
+func NewSetLocalAccountPassword(repo domainrepo.LocalAccountRepository, sessionRepo domainrepo.SessionRepository, v validation.Validator, issuer string) *SetLocalAccountPassword {
+	return &SetLocalAccountPassword{Repo: repo, SessionRepo: sessionRepo, Validator: v, Issuer: issuer}
+}
+
+// Handle replaces the password of a local account and signs the account out
+// everywhere. Accounts from the users file can only be changed there.
+func (h *SetLocalAccountPassword) Handle(ctx context.Context, isAdmin bool, in SetLocalAccountPasswordCmd) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage local accounts")
+	}
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	account, err := h.Repo.GetByUsername(ctx, in.Username)
+	if err != nil {
+		return domainerrors.WrapRepo("set local account password: get by username", err)
+	}
+	if account.Managed {
+		return domainerrors.Forbidden("accounts from the users file cannot be changed")
+	}
+
+	hash, err := domainmodel.HashPassword(in.Password)
+	if err != nil {
+		return domainerrors.Internal("set local account password: hash password", err)
+	}
+	if err := h.Repo.SetPassword(ctx, account.Username, hash); err != nil {
+		return domainerrors.WrapRepo("set local account password: set password", err)
+	}
+	if err := h.SessionRepo.DeleteBySub(ctx, h.Issuer, account.Username); err != nil {
+		return domainerrors.Internal("set local account password: delete sessions", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// ShareCategoryCmd is the input for sharing a category with another user.
+// User is the recipient's user ID, or the username or email they last signed
+// in with.
+type ShareCategoryCmd struct {
+	CategoryID uint   `validate:"required,gt=0"`
+	User       string `validate:"required,max=256"`
+	Role       string `validate:"required"`
+}
+
+// CategorySharer handles the share-category command.
+type CategorySharer interface {
+	Handle(ctx context.Context, userId string, actor domainmodel.AuditActor, in ShareCategoryCmd) error
+}
+
+type ShareCategory struct {
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	UserRepo          domainrepo.UserRepository
+	SessionRepo       domainrepo.SessionRepository
+	Validator         validation.Validator
+	Audit             AuditRecorder
+}
+
+func NewShareCategory(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	userRepo domainrepo.UserRepository,
+	sessionRepo domainrepo.SessionRepository,
+	validator validation.Validator,
+	audit AuditRecorder,
+) *ShareCategory {
+	return &ShareCategory{
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		CategoryShareRepo: categoryShareRepo,
+		UserRepo:          userRepo,
+		SessionRepo:       sessionRepo,
+		Validator:         validator,
+		Audit:             audit,
+	}
+}
+
+// Handle shares a category of the user's own dashboard with another user, or
+// changes the role of an existing share. The recipient is taken by user ID
+// first; otherwise by the username or email of their latest session, which
+// has to match exactly one user.
+func (h *ShareCategory) Handle(ctx context.Context, userId string, actor domainmodel.AuditActor, in ShareCategoryCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	role, err := domainmodel.ParseCategoryRole(in.Role)
+	if err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Role", Message: err.Error()})
+	}
+
+	catRecord, err := h.CategoryRepo.Get(ctx, in.CategoryID)
+	if err != nil {
+		return domainerrors.WrapRepo("share category: get category", err)
+	}
+	dash, err := ownedDashboard(ctx, h.DashboardRepo, "share category: get dashboard", userId, catRecord.DashboardID)
+	if err != nil {
+		return err
+	}
+	// Smart categories show the owner's bookmarks by tag, which recipients
+	// cannot see.
+	if catRecord.TagQuery != "" {
+		return domainerrors.Validation(domainerrors.Violation{Field: "CategoryID", Message: "smart categories cannot be shared"})
+	}
+	// Recipients only get the category itself, not its sub-categories.
+	nested, err := hasSubCategories(ctx, h.CategoryRepo, catRecord)
+	if err != nil {
+		return domainerrors.Internal("share category: list categories", err)
+	}
+	if nested {
+		return domainerrors.Validation(domainerrors.Violation{Field: "CategoryID", Message: "categories with sub-categories cannot be shared"})
+	}
+
+	recipient, err := h.findRecipient(ctx, strings.TrimSpace(in.User))
+	if err != nil {
+		return err
+	}
+	if dash.OwnedBy(recipient) {
+		return domainerrors.Validation(domainerrors.Violation{Field: "User", Message: "a category cannot be shared with its owner"})
+	}
+
+	if err := h.CategoryShareRepo.Upsert(ctx, &domainrepo.CategoryShareRecord{
+		CategoryID: catRecord.ID,
+		UserID:     recipient,
+		Role:       string(role),
+	}); err != nil {
+		return domainerrors.Internal("share category: upsert", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionCategoryShare, catRecord.DisplayName)
+	return nil
+}
+
+// findRecipient returns the ID of the user to share with. Usernames and
+// emails are neither unique across identity providers nor kept after a
+// user's sessions are cleaned up, so the user ID always wins and a login
+// matching several users is rejected.
+func (h *ShareCategory) findRecipient(ctx context.Context, login string) (string, error) {
+	exists, err := h.UserRepo.Exists(ctx, login)
+	if err != nil {
+		return "", domainerrors.Internal("share category: user exists", err)
+	}
+	if exists {
+		return login, nil
+	}
+
+	sessions, err := h.SessionRepo.ListLatest(ctx)
+	if err != nil {
+		return "", domainerrors.Internal("share category: list latest sessions", err)
+	}
+	var matches []string
+	for _, s := range sessions {
+		if strings.EqualFold(s.Username, login) || (s.Email != "" && strings.EqualFold(s.Email, login)) {
+			matches = append(matches, s.UserID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "User", Message: "no user has this ID or has signed in with this username or email"})
+	case 1:
+		return matches[0], nil
+	default:
+		return "", domainerrors.Validation(domainerrors.Violation{Field: "User", Message: "several users have signed in with this username or email; use the user ID instead"})
+	}
+}
+
+// hasSubCategories reports whether other categories are nested below the
+// category.
+func hasSubCategories(ctx context.Context, categories domainrepo.CategoryRepository, catRecord *domainrepo.CategoryRecord) (bool, error) {
+	records, err := categories.ListByDashboardID(ctx, catRecord.DashboardID)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range records {
+		if r.ParentID == catRecord.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkUnsharedParent rejects nesting below parentID when that category is
+// shared with other users or published, as neither shows sub-categories.
+// op prefixes repository errors.
+func checkUnsharedParent(ctx context.Context, shares domainrepo.CategoryShareRepository, shared domainrepo.SharedCategoryRepository, op string, parentID uint) error {
+	records, err := shares.ListByCategory(ctx, parentID)
+	if err != nil {
+		return domainerrors.Internal(op+": list shares", err)
+	}
+	if len(records) > 0 {
+		return domainerrors.Validation(domainerrors.Violation{Field: "ParentID", Message: "categories cannot be nested below shared categories"})
+	}
+	_, err = shared.Get(ctx, parentID)
+	var nfe *domainerrors.NotFoundError
+	switch {
+	case errors.As(err, &nfe):
+		return nil
+	case err != nil:
+		return domainerrors.Internal(op+": get shared category", err)
+	}
+	return domainerrors.Validation(domainerrors.Violation{Field: "ParentID", Message: "categories cannot be nested below published categories"})
+}
+
+// canEditBookmarks reports whether the user may change the bookmarks of the
+// category: owners of its dashboard always may, everyone else needs the
+// editor role. The share is only looked up for categories of other users.
+func canEditBookmarks(ctx context.Context, dashboards domainrepo.DashboardRepository, shares domainrepo.CategoryShareRepository, userId string, catRecord *domainrepo.CategoryRecord) (bool, error) {
+	dashRecord, err := dashboards.Get(ctx, catRecord.DashboardID)
+	if err != nil {
+		return false, err
+	}
+	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
+	if dash.OwnedBy(userId) {
+		return true, nil
+	}
+	share, err := shares.Get(ctx, catRecord.ID, userId)
+	var nfe *domainerrors.NotFoundError
+	switch {
+	case errors.As(err, &nfe):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return dash.CanEditBookmarks(userId, domainmodel.CategoryRole(share.Role)), nil
+}
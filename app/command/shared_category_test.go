@@ -0,0 +1,172 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── PublishCategory ────────────────────────────────────────────────────────
+
+func TestPublishCategory_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewPublishCategory(nil, nil, nil, nil, nil)
+	err := h.Handle(context.Background(), "user-1", false, testActor, command.PublishCategoryCmd{CategoryID: 1})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestPublishCategory_Handle_PublishesOwnCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Family docs"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Family docs"},
+	}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, uint(5)).Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
+	sharedRepo.On("Publish", mock.Anything, &domainrepo.SharedCategoryRecord{
+		CategoryID:      5,
+		VisibleToGroups: []string{"family"},
+		PublishedBy:     "user-1",
+	}).Return(nil)
+	audit := expectAudit(domainmodel.AuditActionCategoryPublish, "Family docs")
+
+	h := command.NewPublishCategory(dashRepo, catRepo, sharedRepo, validation.New(), command.NewRecordAudit(audit))
+	err := h.Handle(context.Background(), "user-1", true, testActor, command.PublishCategoryCmd{
+		CategoryID:      5,
+		VisibleToGroups: []string{"family"},
+	})
+
+	require.NoError(t, err)
+	sharedRepo.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestPublishCategory_Handle_RejectsForeignCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, uint(5)).Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
+
+	h := command.NewPublishCategory(dashRepo, catRepo, sharedRepo, validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", true, testActor, command.PublishCategoryCmd{CategoryID: 5})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	sharedRepo.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestPublishCategory_Handle_UpdatesGroupsOfPublishedCategory(t *testing.T) {
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99, DisplayName: "Runbooks"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(99)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 99, DisplayName: "Runbooks"},
+	}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.SharedCategoryRecord{CategoryID: 5, PublishedBy: "admin-2"}, nil)
+	sharedRepo.On("Publish", mock.Anything, mock.MatchedBy(func(r *domainrepo.SharedCategoryRecord) bool {
+		return r.CategoryID == 5 && len(r.VisibleToGroups) == 1 && r.VisibleToGroups[0] == "dev"
+	})).Return(nil)
+
+	// no dashboard lookup: any admin may change the groups of a published category
+	h := command.NewPublishCategory(&repoMock.DashboardRepository{}, catRepo, sharedRepo, validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", true, testActor, command.PublishCategoryCmd{
+		CategoryID:      5,
+		VisibleToGroups: []string{"dev"},
+	})
+
+	require.NoError(t, err)
+	sharedRepo.AssertExpectations(t)
+}
+
+func TestPublishCategory_Handle_RejectsCategoryWithSubCategories(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Family docs"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Family docs"},
+		{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Insurance"},
+	}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, uint(5)).Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
+
+	h := command.NewPublishCategory(dashRepo, catRepo, sharedRepo, validation.New(), acceptAudit())
+	err := h.Handle(context.Background(), "user-1", true, testActor, command.PublishCategoryCmd{CategoryID: 5})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	sharedRepo.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+// ── UnpublishCategory ──────────────────────────────────────────────────────
+
+func TestUnpublishCategory_Handle_RequiresAdmin(t *testing.T) {
+	h := command.NewUnpublishCategory(nil, nil)
+	err := h.Handle(context.Background(), false, testActor, 5)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestUnpublishCategory_Handle_NotPublished(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("Get", mock.Anything, uint(5)).Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
+
+	h := command.NewUnpublishCategory(repo, acceptAudit())
+	err := h.Handle(context.Background(), true, testActor, 5)
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+	repo.AssertNotCalled(t, "Unpublish", mock.Anything, mock.Anything)
+}
+
+func TestUnpublishCategory_Handle_Success(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.SharedCategoryRecord{CategoryID: 5, DisplayName: "Runbooks"}, nil)
+	repo.On("Unpublish", mock.Anything, uint(5)).Return(nil)
+	audit := expectAudit(domainmodel.AuditActionCategoryUnpublish, "Runbooks")
+
+	h := command.NewUnpublishCategory(repo, command.NewRecordAudit(audit))
+	err := h.Handle(context.Background(), true, testActor, 5)
+
+	require.NoError(t, err)
+	audit.AssertExpectations(t)
+}
+
+// ── HideSharedCategory / ShowSharedCategory ────────────────────────────────
+
+func TestHideSharedCategory_Handle_NotPublished(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("Hide", mock.Anything, "user-1", uint(5)).Return(domainerrors.NotFound(domainerrors.EntityCategory))
+
+	h := command.NewHideSharedCategory(repo)
+	err := h.Handle(context.Background(), "user-1", 5)
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
+
+func TestShowSharedCategory_Handle_Success(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("Show", mock.Anything, "user-1", uint(5)).Return(nil)
+
+	h := command.NewShowSharedCategory(repo)
+	require.NoError(t, h.Handle(context.Background(), "user-1", 5))
+	repo.AssertExpectations(t)
+}
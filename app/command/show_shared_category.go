@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// SharedCategoryShower handles the show-shared-category command.
+type SharedCategoryShower interface {
+	Handle(ctx context.Context, userId string, categoryID uint) error
+}
+
+type ShowSharedCategory struct {
+	Repo domainrepo.SharedCategoryRepository
+}
+
+func NewShowSharedCategory(repo domainrepo.SharedCategoryRepository) *ShowSharedCategory {
+	return &ShowSharedCategory{Repo: repo}
+}
+
+// Handle shows a published category the user hid again.
+func (h *ShowSharedCategory) Handle(ctx context.Context, userId string, categoryID uint) error {
+	if err := h.Repo.Show(ctx, userId, categoryID); err != nil {
+		return domainerrors.Internal("show shared category", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// IdpIdentityUnlinker handles the unlink-idp-identity command.
+type IdpIdentityUnlinker interface {
+	Handle(ctx context.Context, userID, issuer, sub string) error
+}
+
+type UnlinkIdpIdentity struct {
+	Repo        domainrepo.IdpLinkRepository
+	SessionRepo domainrepo.SessionRepository
+}
+
+func NewUnlinkIdpIdentity(repo domainrepo.IdpLinkRepository, sessionRepo domainrepo.SessionRepository) *UnlinkIdpIdentity {
+	return &UnlinkIdpIdentity{Repo: repo, SessionRepo: sessionRepo}
+}
+
+// Handle removes a linked identity from the user's account and signs out the
+// sessions started with it. The last remaining identity cannot be removed,
+// otherwise nobody could sign in to the account anymore.
+func (h *UnlinkIdpIdentity) Handle(ctx context.Context, userID, issuer, sub string) error {
+	links, err := h.Repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return domainerrors.Internal("unlink idp identity: list", err)
+	}
+
+	found := false
+	for _, l := range links {
+		if l.Issuer == issuer && l.Sub == sub {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return domainerrors.NotFound(domainerrors.EntityIdpLink)
+	}
+	if len(links) == 1 {
+		return domainerrors.Forbidden("the last linked account cannot be removed")
+	}
+
+	if err := h.Repo.Unlink(ctx, userID, issuer, sub); err != nil {
+		return domainerrors.WrapRepo("unlink idp identity: unlink", err)
+	}
+	if err := h.SessionRepo.DeleteBySub(ctx, issuer, sub); err != nil {
+		return domainerrors.Internal("unlink idp identity: delete sessions", err)
+	}
+	return nil
+}
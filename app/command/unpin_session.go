@@ -3,25 +3,31 @@ package command
 import (
 	"context"
 
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
 // SessionUnpinner handles the unpin-session command.
 type SessionUnpinner interface {
-	Handle(ctx context.Context, userID string, sessionID string) error
+	Handle(ctx context.Context, actor domainmodel.AuditActor, sessionID string) error
 }
 
 type UnpinSession struct {
-	Repo domainrepo.SessionRepository
+	Repo  domainrepo.SessionRepository
+	Audit AuditRecorder
 }
 
-func NewUnpinSession(repo domainrepo.SessionRepository) *UnpinSession {
-	return &UnpinSession{Repo: repo}
+func NewUnpinSession(repo domainrepo.SessionRepository, audit AuditRecorder) *UnpinSession {
+	return &UnpinSession{Repo: repo, Audit: audit}
 }
 
 // Handle clears PinnedUntil on the session record, keeping it alive for as long
 // as the OIDC token is valid. The record is NOT deleted — that would log the user
 // out on the next request when the revocation check fails to find it.
-func (h *UnpinSession) Handle(ctx context.Context, userID string, sessionID string) error {
-	return h.Repo.Unpin(ctx, sessionID, userID)
+func (h *UnpinSession) Handle(ctx context.Context, actor domainmodel.AuditActor, sessionID string) error {
+	if err := h.Repo.Unpin(ctx, sessionID, actor.UserID); err != nil {
+		return err
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionSessionUnpin, sessionID)
+	return nil
 }
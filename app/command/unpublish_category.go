@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CategoryUnpublisher handles the unpublish-category command.
+type CategoryUnpublisher interface {
+	Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, categoryID uint) error
+}
+
+type UnpublishCategory struct {
+	Repo  domainrepo.SharedCategoryRepository
+	Audit AuditRecorder
+}
+
+func NewUnpublishCategory(repo domainrepo.SharedCategoryRepository, audit AuditRecorder) *UnpublishCategory {
+	return &UnpublishCategory{Repo: repo, Audit: audit}
+}
+
+// Handle stops sharing a category. The category itself stays on the
+// dashboard of its owner. Only admins may unpublish categories.
+func (h *UnpublishCategory) Handle(ctx context.Context, isAdmin bool, actor domainmodel.AuditActor, categoryID uint) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may publish categories")
+	}
+
+	shared, err := h.Repo.Get(ctx, categoryID)
+	if err != nil {
+		return domainerrors.WrapRepo("unpublish category: get", err)
+	}
+	if err := h.Repo.Unpublish(ctx, categoryID); err != nil {
+		return domainerrors.WrapRepo("unpublish category: unpublish", err)
+	}
+	h.Audit.Handle(ctx, actor, domainmodel.AuditActionCategoryUnpublish, shared.DisplayName)
+	return nil
+}
@@ -17,6 +17,10 @@ type UpdateApplicationCmd struct {
 	DisplayName     string   `validate:"required"`
 	Url             string   `validate:"required,url"`
 	VisibleToGroups []string `validate:"dive,required"`
+
+	HealthCheck          bool
+	HealthURL            string `validate:"omitempty,url"`
+	HealthExpectedStatus string
 }
 
 // ApplicationUpdater handles the UpdateApplicationCmd command.
@@ -46,6 +50,10 @@ func (h *UpdateApplication) Handle(ctx context.Context, in UpdateApplicationCmd)
 	if _, err := domainmodel.ParseIcon(in.Icon); err != nil {
 		return domainerrors.Validation(domainerrors.Violation{Message: err.Error()})
 	}
+	expectedStatus, err := normalizeExpectedStatus(in.HealthExpectedStatus)
+	if err != nil {
+		return err
+	}
 
 	app, err := h.ApplicationRepo.Get(ctx, in.ID)
 	if err != nil {
@@ -56,6 +64,9 @@ func (h *UpdateApplication) Handle(ctx context.Context, in UpdateApplicationCmd)
 	app.DisplayName = in.DisplayName
 	app.Url = in.Url
 	app.VisibleToGroups = in.VisibleToGroups
+	app.HealthCheck = in.HealthCheck
+	app.HealthURL = in.HealthURL
+	app.HealthExpectedStatus = expectedStatus
 
 	if err := h.ApplicationRepo.Upsert(ctx, app); err != nil {
 		return domainerrors.Internal("update application: upsert", err)
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"context"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UpdateLocalGroupCmd is the input for renaming a local group and changing
+// the IdP groups mapped onto it.
+type UpdateLocalGroupCmd struct {
+	ID        uint `validate:"required"`
+	Name      string
+	IdpGroups []string `validate:"dive,required,max=256"`
+}
+
+// LocalGroupUpdater handles the update-local-group command.
+type LocalGroupUpdater interface {
+	Handle(ctx context.Context, isAdmin bool, in UpdateLocalGroupCmd) error
+}
+
+type UpdateLocalGroup struct {
+	Repo      domainrepo.LocalGroupRepository
+	Validator validation.Validator
+}
+
+func NewUpdateLocalGroup(repo domainrepo.LocalGroupRepository, v validation.Validator) *UpdateLocalGroup {
+	return &UpdateLocalGroup{Repo: repo, Validator: v}
+}
+
+// Handle updates a local group. Applications scoped to the old name are not
+// renamed along with the group.
+func (h *UpdateLocalGroup) Handle(ctx context.Context, isAdmin bool, in UpdateLocalGroupCmd) error {
+	if !isAdmin {
+		return domainerrors.Forbidden("only admins may manage groups")
+	}
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	name, err := localGroupName(ctx, h.Repo, in.ID, in.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.Update(ctx, &domainrepo.LocalGroupRecord{
+		ID:        in.ID,
+		Name:      name,
+		IdpGroups: in.IdpGroups,
+	}); err != nil {
+		return domainerrors.WrapRepo("update local group", err)
+	}
+	return nil
+}
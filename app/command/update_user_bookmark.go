@@ -10,13 +10,15 @@ import (
 	"git.at.oechsler.it/samuel/dash/v2/app/validation"
 )
 
-// UpdateUserBookmarkCmd is the input for updating an existing bookmark.
+// UpdateUserBookmarkCmd is the input for updating an existing bookmark. Tags
+// replaces the bookmark's tags; nil keeps them.
 type UpdateUserBookmarkCmd struct {
 	ID          uint   `validate:"required,gt=0"`
 	Icon        string `validate:"required"`
 	DisplayName string `validate:"required"`
 	Url         string `validate:"required,url"`
 	CategoryID  uint   `validate:"required,gt=0"`
+	Tags        *[]string
 }
 
 // UserBookmarkUpdater handles the UpdateUserBookmarkCmd command.
@@ -25,23 +27,26 @@ type UserBookmarkUpdater interface {
 }
 
 type UpdateUserBookmark struct {
-	DashboardRepo domainrepo.DashboardRepository
-	CategoryRepo  domainrepo.CategoryRepository
-	BookmarkRepo  domainrepo.BookmarkRepository
-	Validator     validation.Validator
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	Validator         validation.Validator
 }
 
 func NewUpdateUserBookmark(
 	dashboardRepo domainrepo.DashboardRepository,
 	categoryRepo domainrepo.CategoryRepository,
 	bookmarkRepo domainrepo.BookmarkRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
 	validator validation.Validator,
 ) *UpdateUserBookmark {
 	return &UpdateUserBookmark{
-		DashboardRepo: dashboardRepo,
-		CategoryRepo:  categoryRepo,
-		BookmarkRepo:  bookmarkRepo,
-		Validator:     validator,
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		BookmarkRepo:      bookmarkRepo,
+		CategoryShareRepo: categoryShareRepo,
+		Validator:         validator,
 	}
 }
 
@@ -68,13 +73,12 @@ func (h *UpdateUserBookmark) Handle(ctx context.Context, userId string, in Updat
 		return domainerrors.WrapRepo("update user bookmark: get current category", err)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
+	canEdit, err := canEditBookmarks(ctx, h.DashboardRepo, h.CategoryShareRepo, userId, currentCatRecord)
 	if err != nil {
-		return domainerrors.WrapRepo("update user bookmark: get dashboard", err)
+		return domainerrors.WrapRepo("update user bookmark: check current category access", err)
 	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(currentCatRecord.DashboardID) {
-		return domainerrors.Forbidden("user does not own dashboard")
+	if !canEdit {
+		return domainerrors.Forbidden("user may not edit bookmarks of category")
 	}
 
 	if in.CategoryID != bookmarkRecord.CategoryID {
@@ -82,13 +86,15 @@ func (h *UpdateUserBookmark) Handle(ctx context.Context, userId string, in Updat
 		if err != nil {
 			return domainerrors.WrapRepo("update user bookmark: get target category", err)
 		}
-		targetDashRecord, err := h.DashboardRepo.Get(ctx, targetCatRecord.DashboardID)
+		canEdit, err := canEditBookmarks(ctx, h.DashboardRepo, h.CategoryShareRepo, userId, targetCatRecord)
 		if err != nil {
-			return domainerrors.WrapRepo("update user bookmark: get target dashboard", err)
+			return domainerrors.WrapRepo("update user bookmark: check target category access", err)
 		}
-		targetDash := domainmodel.NewUserDashboard(targetDashRecord.ID, targetDashRecord.UserID)
-		if !targetDash.OwnsCategory(targetCatRecord.DashboardID) {
-			return domainerrors.Forbidden("user does not own target dashboard")
+		if !canEdit {
+			return domainerrors.Forbidden("user may not edit bookmarks of target category")
+		}
+		if targetCatRecord.TagQuery != "" {
+			return domainerrors.Validation(smartCategoryViolation)
 		}
 	}
 
@@ -96,11 +102,17 @@ func (h *UpdateUserBookmark) Handle(ctx context.Context, userId string, in Updat
 		ID:          bookmarkRecord.ID,
 		CategoryID:  bookmarkRecord.CategoryID,
 		DisplayName: bookmarkRecord.DisplayName,
+		Tags:        bookmarkRecord.Tags,
 	}
 	bookmark.UpdateIcon(icon)
 	bookmark.Rename(in.DisplayName)
 	bookmark.ChangeURL(bUrl)
 	bookmark.MoveTo(in.CategoryID)
+	if in.Tags != nil {
+		if err := bookmark.Tag(*in.Tags); err != nil {
+			return domainerrors.Validation(domainerrors.Violation{Field: "Tags", Message: err.Error()})
+		}
+	}
 
 	if err := h.BookmarkRepo.Upsert(ctx, &domainrepo.BookmarkRecord{
 		ID:          bookmark.ID,
@@ -108,6 +120,7 @@ func (h *UpdateUserBookmark) Handle(ctx context.Context, userId string, in Updat
 		Icon:        bookmark.Icon.String(),
 		DisplayName: bookmark.DisplayName,
 		Url:         bookmark.Url.String(),
+		Tags:        bookmark.Tags,
 	}); err != nil {
 		return domainerrors.Internal("update user bookmark: upsert", err)
 	}
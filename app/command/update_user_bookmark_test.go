@@ -28,7 +28,7 @@ func TestUpdateUserBookmark_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("failed"))
 
-	h := command.NewUpdateUserBookmark(nil, nil, nil, v)
+	h := command.NewUpdateUserBookmark(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserBookmarkCmd{})
 
 	var ve *domainerrors.ValidationError
@@ -42,7 +42,7 @@ func TestUpdateUserBookmark_Handle_InvalidIcon(t *testing.T) {
 	cmd := validUpdateBookmarkCmd()
 	cmd.Icon = "bad-icon"
 
-	h := command.NewUpdateUserBookmark(nil, nil, nil, v)
+	h := command.NewUpdateUserBookmark(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", cmd)
 
 	var ve *domainerrors.ValidationError
@@ -56,7 +56,7 @@ func TestUpdateUserBookmark_Handle_InvalidURL(t *testing.T) {
 	cmd := validUpdateBookmarkCmd()
 	cmd.Url = "not-a-url"
 
-	h := command.NewUpdateUserBookmark(nil, nil, nil, v)
+	h := command.NewUpdateUserBookmark(nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", cmd)
 
 	var ve *domainerrors.ValidationError
@@ -71,7 +71,7 @@ func TestUpdateUserBookmark_Handle_BookmarkNotFound(t *testing.T) {
 	bookmarkRepo.On("Get", mock.Anything, uint(7)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityBookmark))
 
-	h := command.NewUpdateUserBookmark(nil, nil, bookmarkRepo, v)
+	h := command.NewUpdateUserBookmark(nil, nil, bookmarkRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", validUpdateBookmarkCmd())
 
 	var nfe *domainerrors.NotFoundError
@@ -91,10 +91,10 @@ func TestUpdateUserBookmark_Handle_ForbiddenWrongOwner(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil) // belongs to dash 99
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil) // owns dash 10
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil) // dash 99 belongs to someone else
 
-	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, v)
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, noCategoryShare(), v)
 	err := h.Handle(context.Background(), "user-1", validUpdateBookmarkCmd())
 
 	var fe *domainerrors.ForbiddenError
@@ -117,10 +117,10 @@ func TestUpdateUserBookmark_Handle_Success(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, v)
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", validUpdateBookmarkCmd())
 
 	require.NoError(t, err)
@@ -149,14 +149,95 @@ func TestUpdateUserBookmark_Handle_CrossCategoryMove(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 2, DashboardID: 10}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
-	// target dashboard lookup
+
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	require.NoError(t, err)
+}
+
+func TestUpdateUserBookmark_Handle_MoveToSmartCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	cmd := validUpdateBookmarkCmd()
+	cmd.CategoryID = 2
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(7)).
+		Return(&domainrepo.BookmarkRecord{ID: 7, CategoryID: 1}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
+	catRepo.On("Get", mock.Anything, uint(2)).
+		Return(&domainrepo.CategoryRecord{ID: 2, DashboardID: 10, TagQuery: "tag:k8s"}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", cmd)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	bookmarkRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserBookmark_Handle_Tags(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	cmd := validUpdateBookmarkCmd()
+	cmd.Tags = &[]string{"Home Lab"}
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(7)).
+		Return(&domainrepo.BookmarkRecord{ID: 7, CategoryID: 1, Tags: []string{"old"}}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return len(r.Tags) == 1 && r.Tags[0] == "home-lab"
+	})).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
 	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, v)
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", cmd)
 
 	require.NoError(t, err)
+	bookmarkRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserBookmark_Handle_KeepsTags(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(7)).
+		Return(&domainrepo.BookmarkRecord{ID: 7, CategoryID: 1, Tags: []string{"k8s"}}, nil)
+	bookmarkRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.BookmarkRecord) bool {
+		return len(r.Tags) == 1 && r.Tags[0] == "k8s"
+	})).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserBookmark(dashRepo, catRepo, bookmarkRepo, nil, v)
+	err := h.Handle(context.Background(), "user-1", validUpdateBookmarkCmd())
+
+	require.NoError(t, err)
+	bookmarkRepo.AssertExpectations(t)
 }
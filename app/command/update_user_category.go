@@ -11,10 +11,19 @@ import (
 )
 
 // UpdateUserCategoryCmd is the input for updating an existing category.
+// A non-zero DashboardID moves the category with its sub-categories to
+// another of the user's dashboards. ParentID nests it inside another category
+// of its dashboard, 0 moves it to the top level; nil keeps the current parent,
+// or moves it to the top level of the other dashboard. TagQuery turns a
+// category without bookmarks into a smart category, an empty query back into
+// a regular one; nil keeps it as it is.
 type UpdateUserCategoryCmd struct {
-	ID          uint   `validate:"required,gt=0"`
+	ID          uint `validate:"required,gt=0"`
+	DashboardID uint
+	ParentID    *uint
 	DisplayName string `validate:"required"`
 	IsShelved   bool
+	TagQuery    *string
 }
 
 // UserCategoryUpdater handles the UpdateUserCategoryCmd command.
@@ -23,20 +32,29 @@ type UserCategoryUpdater interface {
 }
 
 type UpdateUserCategory struct {
-	DashboardRepo domainrepo.DashboardRepository
-	CategoryRepo  domainrepo.CategoryRepository
-	Validator     validation.Validator
+	DashboardRepo      domainrepo.DashboardRepository
+	CategoryRepo       domainrepo.CategoryRepository
+	BookmarkRepo       domainrepo.BookmarkRepository
+	CategoryShareRepo  domainrepo.CategoryShareRepository
+	SharedCategoryRepo domainrepo.SharedCategoryRepository
+	Validator          validation.Validator
 }
 
 func NewUpdateUserCategory(
 	dashboardRepo domainrepo.DashboardRepository,
 	categoryRepo domainrepo.CategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	sharedCategoryRepo domainrepo.SharedCategoryRepository,
 	validator validation.Validator,
 ) *UpdateUserCategory {
 	return &UpdateUserCategory{
-		DashboardRepo: dashboardRepo,
-		CategoryRepo:  categoryRepo,
-		Validator:     validator,
+		DashboardRepo:      dashboardRepo,
+		CategoryRepo:       categoryRepo,
+		BookmarkRepo:       bookmarkRepo,
+		CategoryShareRepo:  categoryShareRepo,
+		SharedCategoryRepo: sharedCategoryRepo,
+		Validator:          validator,
 	}
 }
 
@@ -50,19 +68,32 @@ func (h *UpdateUserCategory) Handle(ctx context.Context, userId string, in Updat
 		return domainerrors.WrapRepo("update user category: get category", err)
 	}
 
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
+	dash, err := ownedDashboard(ctx, h.DashboardRepo, "update user category: get dashboard", userId, catRecord.DashboardID)
 	if err != nil {
-		return domainerrors.WrapRepo("update user category: get dashboard", err)
+		return err
 	}
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return domainerrors.Forbidden("user does not own dashboard")
+
+	source := dash
+	if in.DashboardID != 0 && in.DashboardID != dash.ID() {
+		if dash, err = ownedDashboard(ctx, h.DashboardRepo, "update user category: get target dashboard", userId, in.DashboardID); err != nil {
+			return err
+		}
+	}
+	moved := dash.ID() != source.ID()
+
+	parentID := catRecord.ParentID
+	if in.ParentID != nil {
+		parentID = *in.ParentID
+	} else if moved {
+		parentID = 0
 	}
 
 	cat := domainmodel.Category{
 		ID:          catRecord.ID,
+		ParentID:    catRecord.ParentID,
 		DisplayName: catRecord.DisplayName,
 		IsShelved:   catRecord.IsShelved,
+		TagQuery:    catRecord.TagQuery,
 	}
 	if err := cat.Rename(in.DisplayName); err != nil {
 		return domainerrors.Validation(domainerrors.Violation{Message: err.Error()})
@@ -72,14 +103,65 @@ func (h *UpdateUserCategory) Handle(ctx context.Context, userId string, in Updat
 	} else {
 		cat.Unshelve()
 	}
+	if err := cat.Nest(parentID); err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "IsShelved", Message: err.Error()})
+	}
+
+	if in.TagQuery != nil {
+		if err := cat.Query(*in.TagQuery); err != nil {
+			return domainerrors.Validation(domainerrors.Violation{Field: "TagQuery", Message: err.Error()})
+		}
+		if cat.IsSmart() && catRecord.TagQuery == "" {
+			bookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, []uint{cat.ID})
+			if err != nil {
+				return domainerrors.Internal("update user category: list bookmarks", err)
+			}
+			if len(bookmarks) > 0 {
+				return domainerrors.Validation(domainerrors.Violation{Field: "TagQuery", Message: "only categories without bookmarks can become smart categories"})
+			}
+		}
+	}
+
+	if cat.ParentID != 0 && (moved || cat.ParentID != catRecord.ParentID) {
+		if err := h.checkParent(ctx, source.ID(), dash.ID(), cat); err != nil {
+			return err
+		}
+	}
 
 	if err := h.CategoryRepo.Upsert(ctx, &domainrepo.CategoryRecord{
 		ID:          cat.ID,
 		DashboardID: dash.ID(),
+		ParentID:    cat.ParentID,
 		DisplayName: cat.DisplayName,
 		IsShelved:   cat.IsShelved,
+		TagQuery:    cat.TagQuery,
 	}); err != nil {
 		return domainerrors.Internal("update user category: upsert", err)
 	}
 	return nil
 }
+
+// checkParent verifies that cat, currently part of the dashboard sourceID,
+// may be nested below cat.ParentID of the dashboard targetID together with
+// its sub-categories, and that the parent is neither shared nor published.
+func (h *UpdateUserCategory) checkParent(ctx context.Context, sourceID, targetID uint, cat domainmodel.Category) error {
+	categories, err := h.CategoryRepo.ListByDashboardID(ctx, sourceID)
+	if err != nil {
+		return domainerrors.Internal("update user category: list categories", err)
+	}
+	tree := categoryTree(categories)
+	height := tree.Height(cat.ID)
+
+	id := cat.ID
+	if targetID != sourceID {
+		if categories, err = h.CategoryRepo.ListByDashboardID(ctx, targetID); err != nil {
+			return domainerrors.Internal("update user category: list target categories", err)
+		}
+		tree = categoryTree(categories)
+		id = 0
+	}
+	if err := tree.CheckParent(id, cat.ParentID, height); err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "ParentID", Message: err.Error()})
+	}
+	return checkUnsharedParent(ctx, h.CategoryShareRepo, h.SharedCategoryRepo, "update user category", cat.ParentID)
+}
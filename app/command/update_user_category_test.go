@@ -18,7 +18,7 @@ func TestUpdateUserCategory_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("validation failed"))
 
-	h := command.NewUpdateUserCategory(nil, nil, v)
+	h := command.NewUpdateUserCategory(nil, nil, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{})
 
 	var ve *domainerrors.ValidationError
@@ -33,7 +33,7 @@ func TestUpdateUserCategory_Handle_CategoryNotFound(t *testing.T) {
 	catRepo.On("Get", mock.Anything, uint(5)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityCategory))
 
-	h := command.NewUpdateUserCategory(nil, catRepo, v)
+	h := command.NewUpdateUserCategory(nil, catRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "New"})
 
 	var nfe *domainerrors.NotFoundError
@@ -49,10 +49,10 @@ func TestUpdateUserCategory_Handle_DashboardNotFound(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Old"}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
-	h := command.NewUpdateUserCategory(dashRepo, catRepo, v)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "New"})
 
 	var nfe *domainerrors.NotFoundError
@@ -68,10 +68,10 @@ func TestUpdateUserCategory_Handle_ForbiddenWrongOwner(t *testing.T) {
 		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 99, DisplayName: "Old"}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil) // owns dash 10, cat belongs to 99
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil) // cat belongs to dash 99 of user-2
 
-	h := command.NewUpdateUserCategory(dashRepo, catRepo, v)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "New"})
 
 	var fe *domainerrors.ForbiddenError
@@ -90,16 +90,62 @@ func TestUpdateUserCategory_Handle_Success(t *testing.T) {
 	})).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewUpdateUserCategory(dashRepo, catRepo, v)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "New", IsShelved: false})
 
 	require.NoError(t, err)
 	catRepo.AssertExpectations(t)
 }
 
+func TestUpdateUserCategory_Handle_MovesToOtherDashboard(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Old"}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.ID == 5 && r.DashboardID == 11
+	})).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DashboardID: 11, DisplayName: "Old"})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserCategory_Handle_MoveToForeignDashboard_Forbidden(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Old"}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(20)).
+		Return(&domainrepo.DashboardRecord{ID: 20, UserID: "user-2"}, nil)
+
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DashboardID: 20, DisplayName: "Old"})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
 func TestUpdateUserCategory_Handle_Shelve(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(nil)
@@ -112,11 +158,245 @@ func TestUpdateUserCategory_Handle_Shelve(t *testing.T) {
 	})).Return(nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
-	h := command.NewUpdateUserCategory(dashRepo, catRepo, v)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Old", IsShelved: true})
 
 	require.NoError(t, err)
 }
+
+func TestUpdateUserCategory_Handle_Nest(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Proxmox"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 5, DashboardID: 10, DisplayName: "Proxmox"},
+		{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Nodes"},
+	}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.ID == 5 && r.DashboardID == 10 && r.ParentID == 1
+	})).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	shareRepo, sharedRepo := unsharedParents()
+
+	parentID := uint(1)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, shareRepo, sharedRepo, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Proxmox", ParentID: &parentID})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserCategory_Handle_NestBelowPublishedCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Proxmox"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 5, DashboardID: 10, DisplayName: "Proxmox"},
+	}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByCategory", mock.Anything, uint(1)).Return([]domainrepo.CategoryShareRecord{}, nil)
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("Get", mock.Anything, uint(1)).Return(&domainrepo.SharedCategoryRecord{CategoryID: 1, PublishedBy: "user-1"}, nil)
+
+	parentID := uint(1)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, shareRepo, sharedRepo, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Proxmox", ParentID: &parentID})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserCategory_Handle_NestIntoOwnSubtree(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Proxmox"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Proxmox"},
+		{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Nodes"},
+	}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	parentID := uint(6)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Proxmox", ParentID: &parentID})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserCategory_Handle_MoveSubtreeTooDeep(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Proxmox"}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 5, DashboardID: 10, DisplayName: "Proxmox"},
+		{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Nodes"},
+	}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 11, DisplayName: "Infrastructure"},
+		{ID: 2, DashboardID: 11, ParentID: 1, DisplayName: "Virtualization"},
+	}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1"}, nil)
+
+	parentID := uint(2)
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DashboardID: 11, DisplayName: "Proxmox", ParentID: &parentID})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserCategory_Handle_MoveSubCategoryToOtherDashboard_TopLevel(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(6)).
+		Return(&domainrepo.CategoryRecord{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Nodes"}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.ID == 6 && r.DashboardID == 11 && r.ParentID == 0
+	})).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 6, DashboardID: 11, DisplayName: "Nodes"})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserCategory_Handle_ShelveSubCategory(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(6)).
+		Return(&domainrepo.CategoryRecord{ID: 6, DashboardID: 10, ParentID: 5, DisplayName: "Nodes"}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 6, DisplayName: "Nodes", IsShelved: true})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserCategory_Handle_MakeSmart(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Kubernetes"}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.TagQuery == "tag:k8s"
+	})).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+
+	query := "TAG:K8s"
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, bookmarkRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Kubernetes", TagQuery: &query})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserCategory_Handle_MakeSmartWithBookmarks(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Kubernetes"}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5}).
+		Return([]domainrepo.BookmarkRecord{{ID: 1, CategoryID: 5}}, nil)
+
+	query := "tag:k8s"
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, bookmarkRepo, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "Kubernetes", TagQuery: &query})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	catRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserCategory_Handle_KeepsTagQuery(t *testing.T) {
+	v := &repoMock.Validator{}
+	v.On("Struct", mock.Anything).Return(nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Old", TagQuery: "tag:k8s"}, nil)
+	catRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.CategoryRecord) bool {
+		return r.DisplayName == "New" && r.TagQuery == "tag:k8s"
+	})).Return(nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	h := command.NewUpdateUserCategory(dashRepo, catRepo, nil, nil, nil, v)
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserCategoryCmd{ID: 5, DisplayName: "New"})
+
+	require.NoError(t, err)
+	catRepo.AssertExpectations(t)
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UpdateUserDashboardCmd is the input for renaming a dashboard. MakeDefault
+// turns it into the user's default dashboard; the default cannot be unset
+// other than by picking another one.
+type UpdateUserDashboardCmd struct {
+	ID          uint   `validate:"required,gt=0"`
+	Name        string `validate:"required"`
+	MakeDefault bool
+}
+
+// UserDashboardUpdater handles the UpdateUserDashboardCmd command.
+type UserDashboardUpdater interface {
+	Handle(ctx context.Context, userId string, in UpdateUserDashboardCmd) error
+}
+
+type UpdateUserDashboard struct {
+	DashboardRepo domainrepo.DashboardRepository
+	Validator     validation.Validator
+}
+
+func NewUpdateUserDashboard(dashboardRepo domainrepo.DashboardRepository, validator validation.Validator) *UpdateUserDashboard {
+	return &UpdateUserDashboard{DashboardRepo: dashboardRepo, Validator: validator}
+}
+
+func (h *UpdateUserDashboard) Handle(ctx context.Context, userId string, in UpdateUserDashboardCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "update user dashboard: get dashboard", userId, in.ID); err != nil {
+		return err
+	}
+	existing, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return domainerrors.Internal("update user dashboard: list dashboards", err)
+	}
+	name, err := dashboardName(existing, in.ID, in.Name)
+	if err != nil {
+		return err
+	}
+
+	var record domainrepo.DashboardRecord
+	for _, d := range existing {
+		if d.ID == in.ID {
+			record = d
+		}
+	}
+	record.Name = name
+	if err := h.DashboardRepo.Upsert(ctx, &record); err != nil {
+		return domainerrors.Internal("update user dashboard: upsert", err)
+	}
+
+	if in.MakeDefault && !record.IsDefault {
+		if err := h.DashboardRepo.SetDefault(ctx, userId, in.ID); err != nil {
+			return domainerrors.WrapRepo("update user dashboard: set default", err)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,9 @@ type UpdateUserSettingsCmd struct {
 	ThemeID  uint   `validate:"gte=0"`
 	Language string `validate:"omitempty,oneof=auto en de"`
 	Timezone string `validate:"omitempty"`
+	// SearchProviderID selects the default web search provider; 0 clears it
+	// and nil leaves it unchanged.
+	SearchProviderID *uint
 }
 
 // UserSettingsUpdater handles the UpdateUserSettingsCmd command.
@@ -25,13 +28,19 @@ type UserSettingsUpdater interface {
 }
 
 type UpdateUserSettings struct {
-	SettingRepo domainrepo.SettingRepository
-	ThemeRepo   domainrepo.ThemeRepository
-	Validator   validation.Validator
+	SettingRepo        domainrepo.SettingRepository
+	ThemeRepo          domainrepo.ThemeRepository
+	SearchProviderRepo domainrepo.SearchProviderRepository
+	Validator          validation.Validator
 }
 
-func NewUpdateUserSettings(settingRepo domainrepo.SettingRepository, themeRepo domainrepo.ThemeRepository, v validation.Validator) *UpdateUserSettings {
-	return &UpdateUserSettings{SettingRepo: settingRepo, ThemeRepo: themeRepo, Validator: v}
+func NewUpdateUserSettings(
+	settingRepo domainrepo.SettingRepository,
+	themeRepo domainrepo.ThemeRepository,
+	searchProviderRepo domainrepo.SearchProviderRepository,
+	v validation.Validator,
+) *UpdateUserSettings {
+	return &UpdateUserSettings{SettingRepo: settingRepo, ThemeRepo: themeRepo, SearchProviderRepo: searchProviderRepo, Validator: v}
 }
 
 func (h *UpdateUserSettings) Handle(ctx context.Context, userID string, in UpdateUserSettingsCmd) error {
@@ -70,6 +79,15 @@ func (h *UpdateUserSettings) Handle(ctx context.Context, userID string, in Updat
 		existing.Timezone = in.Timezone
 	}
 
+	if in.SearchProviderID != nil {
+		if *in.SearchProviderID != 0 {
+			if _, err := h.SearchProviderRepo.GetByID(ctx, userID, *in.SearchProviderID); err != nil {
+				return domainerrors.WrapRepo("update user settings: get search provider by id", err)
+			}
+		}
+		existing.SearchProviderID = *in.SearchProviderID
+	}
+
 	if err := h.SettingRepo.Upsert(ctx, existing); err != nil {
 		return domainerrors.Internal("update user settings: upsert", err)
 	}
@@ -18,7 +18,7 @@ func TestUpdateUserSettings_Handle_ValidationError(t *testing.T) {
 	v := &repoMock.Validator{}
 	v.On("Struct", mock.Anything).Return(errors.New("failed"))
 
-	h := command.NewUpdateUserSettings(nil, nil, v)
+	h := command.NewUpdateUserSettings(nil, nil, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{})
 
 	var ve *domainerrors.ValidationError
@@ -39,7 +39,7 @@ func TestUpdateUserSettings_Handle_ExistingSettings_DefaultTheme(t *testing.T) {
 	// ThemeID=0 → default, so ThemeRepo.GetByID is NOT called
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{ThemeID: 0})
 
 	require.NoError(t, err)
@@ -59,7 +59,7 @@ func TestUpdateUserSettings_Handle_NewSettings_Provisioned(t *testing.T) {
 
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{ThemeID: 0})
 
 	require.NoError(t, err)
@@ -78,7 +78,7 @@ func TestUpdateUserSettings_Handle_NonDefaultTheme_Validated(t *testing.T) {
 	themeRepo.On("GetByID", mock.Anything, "user-1", uint(3)).
 		Return(&domainrepo.ThemeRecord{ID: 3}, nil)
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{ThemeID: 3})
 
 	require.NoError(t, err)
@@ -97,7 +97,7 @@ func TestUpdateUserSettings_Handle_ThemeNotFound(t *testing.T) {
 	themeRepo.On("GetByID", mock.Anything, "user-1", uint(3)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityTheme))
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{ThemeID: 3})
 
 	var nfe *domainerrors.NotFoundError
@@ -117,7 +117,7 @@ func TestUpdateUserSettings_Handle_Language(t *testing.T) {
 
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{Language: "de"})
 
 	require.NoError(t, err)
@@ -136,7 +136,7 @@ func TestUpdateUserSettings_Handle_ValidTimezone(t *testing.T) {
 
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{Timezone: "Europe/Vienna"})
 
 	require.NoError(t, err)
@@ -152,7 +152,7 @@ func TestUpdateUserSettings_Handle_InvalidTimezone(t *testing.T) {
 
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{Timezone: "Not/ATimezone"})
 
 	var ve *domainerrors.ValidationError
@@ -172,7 +172,7 @@ func TestUpdateUserSettings_Handle_AutoTimezone(t *testing.T) {
 
 	themeRepo := &repoMock.ThemeRepository{}
 
-	h := command.NewUpdateUserSettings(settingRepo, themeRepo, v)
+	h := command.NewUpdateUserSettings(settingRepo, themeRepo, nil, v)
 	err := h.Handle(context.Background(), "user-1", command.UpdateUserSettingsCmd{Timezone: "auto"})
 
 	require.NoError(t, err)
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+)
+
+// CustomIconProcessor turns an uploaded file into the image stored in the icon
+// library: raster images are scaled down and SVGs are sanitized. It returns an
+// error when data is no supported image.
+type CustomIconProcessor interface {
+	Process(data []byte) (contentType string, out []byte, err error)
+}
+
+// UploadCustomIconCmd is the input for adding an image to the icon library.
+type UploadCustomIconCmd struct {
+	DisplayName string `validate:"required,max=64"`
+	Data        []byte
+	// Shared icons belong to no user and can be used by applications.
+	// Only admins may upload them.
+	IsShared bool
+}
+
+// CustomIconUploader handles the UploadCustomIconCmd command.
+type CustomIconUploader interface {
+	Handle(ctx context.Context, userID string, isAdmin bool, in UploadCustomIconCmd) error
+}
+
+type UploadCustomIcon struct {
+	Repo      domainrepo.CustomIconRepository
+	Processor CustomIconProcessor
+	Validator validation.Validator
+}
+
+func NewUploadCustomIcon(
+	r domainrepo.CustomIconRepository,
+	p CustomIconProcessor,
+	v validation.Validator,
+) *UploadCustomIcon {
+	return &UploadCustomIcon{
+		Repo:      r,
+		Processor: p,
+		Validator: v,
+	}
+}
+
+func (h *UploadCustomIcon) Handle(ctx context.Context, userID string, isAdmin bool, in UploadCustomIconCmd) error {
+	if err := h.Validator.Struct(in); err != nil {
+		return domainerrors.Validation(validation.ToViolations(err)...)
+	}
+	if in.IsShared && !isAdmin {
+		return domainerrors.Forbidden("only admins may upload shared icons")
+	}
+	if len(in.Data) == 0 {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Data", Message: "required"})
+	}
+	if len(in.Data) > domainmodel.MaxCustomIconBytes {
+		return domainerrors.Validation(domainerrors.Violation{
+			Field:   "Data",
+			Message: fmt.Sprintf("must not be larger than %d KiB", domainmodel.MaxCustomIconBytes>>10),
+		})
+	}
+
+	contentType, data, err := h.Processor.Process(in.Data)
+	if err != nil {
+		return domainerrors.Validation(domainerrors.Violation{Field: "Data", Message: err.Error()})
+	}
+
+	record := &domainrepo.CustomIconRecord{
+		DisplayName: in.DisplayName,
+		ContentType: contentType,
+		Data:        data,
+	}
+	if !in.IsShared {
+		record.UserID = userID
+	}
+	if err := h.Repo.Create(ctx, record); err != nil {
+		return domainerrors.Internal("upload custom icon: create", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/validation"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func twoDashboards() []domainrepo.DashboardRecord {
+	return []domainrepo.DashboardRecord{
+		{ID: 10, UserID: "user-1", Name: "Work", IsDefault: true},
+		{ID: 11, UserID: "user-1", Name: "Home", Position: 1},
+	}
+}
+
+// ── CreateUserDashboard ────────────────────────────────────────────────────
+
+func TestCreateUserDashboard_Handle_FirstBecomesDefault(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.DashboardRecord{}, nil)
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.UserID == "user-1" && r.Name == "Work" && r.IsDefault
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.DashboardRecord).ID = 10
+	}).Return(nil)
+
+	h := command.NewCreateUserDashboard(dashRepo, validation.New())
+	id, err := h.Handle(context.Background(), "user-1", command.CreateUserDashboardCmd{Name: "  Work "})
+
+	require.NoError(t, err)
+	require.Equal(t, uint(10), id)
+	dashRepo.AssertExpectations(t)
+}
+
+func TestCreateUserDashboard_Handle_AppendsDashboard(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.Name == "Lab" && !r.IsDefault
+	})).Return(nil)
+
+	h := command.NewCreateUserDashboard(dashRepo, validation.New())
+	_, err := h.Handle(context.Background(), "user-1", command.CreateUserDashboardCmd{Name: "Lab"})
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+}
+
+func TestCreateUserDashboard_Handle_NameTaken(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+
+	h := command.NewCreateUserDashboard(dashRepo, validation.New())
+	_, err := h.Handle(context.Background(), "user-1", command.CreateUserDashboardCmd{Name: "Home"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	dashRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+// ── UpdateUserDashboard ────────────────────────────────────────────────────
+
+func TestUpdateUserDashboard_Handle_RenamesAndMakesDefault(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(11)).Return(&twoDashboards()[1], nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
+		return r.ID == 11 && r.Name == "House" && r.Position == 1
+	})).Return(nil)
+	dashRepo.On("SetDefault", mock.Anything, "user-1", uint(11)).Return(nil)
+
+	h := command.NewUpdateUserDashboard(dashRepo, validation.New())
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserDashboardCmd{ID: 11, Name: "House", MakeDefault: true})
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserDashboard_Handle_KeepsOwnName(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&twoDashboards()[0], nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+	dashRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	h := command.NewUpdateUserDashboard(dashRepo, validation.New())
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserDashboardCmd{ID: 10, Name: "Work", MakeDefault: true})
+
+	require.NoError(t, err)
+	dashRepo.AssertNotCalled(t, "SetDefault", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserDashboard_Handle_ForeignDashboard_Forbidden(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(20)).Return(&domainrepo.DashboardRecord{ID: 20, UserID: "user-2"}, nil)
+
+	h := command.NewUpdateUserDashboard(dashRepo, validation.New())
+	err := h.Handle(context.Background(), "user-1", command.UpdateUserDashboardCmd{ID: 20, Name: "Mine"})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	dashRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+// ── DeleteUserDashboard ────────────────────────────────────────────────────
+
+func TestDeleteUserDashboard_Handle_Deletes(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(11)).Return(&twoDashboards()[1], nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+	dashRepo.On("Delete", mock.Anything, uint(11)).Return(nil)
+
+	h := command.NewDeleteUserDashboard(dashRepo)
+	err := h.Handle(context.Background(), "user-1", 11)
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+}
+
+func TestDeleteUserDashboard_Handle_LastDashboard(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&twoDashboards()[0], nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards()[:1], nil)
+
+	h := command.NewDeleteUserDashboard(dashRepo)
+	err := h.Handle(context.Background(), "user-1", 10)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	dashRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteUserDashboard_Handle_ForeignDashboard_Forbidden(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(20)).Return(&domainrepo.DashboardRecord{ID: 20, UserID: "user-2"}, nil)
+
+	h := command.NewDeleteUserDashboard(dashRepo)
+	err := h.Handle(context.Background(), "user-1", 20)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	dashRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// ── ReorderUserDashboards ──────────────────────────────────────────────────
+
+func TestReorderUserDashboards_Handle_Reorders(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+	dashRepo.On("Reorder", mock.Anything, "user-1", []uint{11, 10}).Return(nil)
+
+	h := command.NewReorderUserDashboards(dashRepo, validation.New())
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserDashboardsCmd{IDs: []uint{11, 10}})
+
+	require.NoError(t, err)
+	dashRepo.AssertExpectations(t)
+}
+
+func TestReorderUserDashboards_Handle_ForeignID_Forbidden(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(twoDashboards(), nil)
+
+	h := command.NewReorderUserDashboards(dashRepo, validation.New())
+	err := h.Handle(context.Background(), "user-1", command.ReorderUserDashboardsCmd{IDs: []uint{11, 20}})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	dashRepo.AssertNotCalled(t, "Reorder", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -10,6 +10,8 @@ import (
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
 )
 
@@ -19,19 +21,38 @@ func TestDeleteUserData_Handle_Success(t *testing.T) {
 	userRepo := &repoMock.UserRepository{}
 	userRepo.On("DeleteByID", mock.Anything, "user-1").Return(nil)
 
-	h := command.NewDeleteUserData(userRepo)
-	err := h.Handle(context.Background(), "user-1")
+	auditRepo := expectAudit(domainmodel.AuditActionUserDelete, "user-1")
+
+	h := command.NewDeleteUserData(userRepo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), testActor, "user-1")
 
 	require.NoError(t, err)
 	userRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestDeleteUserData_Handle_AuditErrorIsNotReturned(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+	userRepo.On("DeleteByID", mock.Anything, "user-1").Return(nil)
+
+	auditRepo := &repoMock.AuditLogRepository{}
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
+	audit := command.NewRecordAudit(auditRepo)
+	audit.Logf = func(string, ...any) {}
+
+	h := command.NewDeleteUserData(userRepo, audit)
+	err := h.Handle(context.Background(), testActor, "user-1")
+
+	require.NoError(t, err)
+	auditRepo.AssertExpectations(t)
 }
 
 func TestDeleteUserData_Handle_RepoError(t *testing.T) {
 	userRepo := &repoMock.UserRepository{}
 	userRepo.On("DeleteByID", mock.Anything, "user-1").Return(errors.New("db error"))
 
-	h := command.NewDeleteUserData(userRepo)
-	err := h.Handle(context.Background(), "user-1")
+	h := command.NewDeleteUserData(userRepo, nil)
+	err := h.Handle(context.Background(), testActor, "user-1")
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
@@ -118,3 +139,211 @@ func TestMigrateUserID_Handle_RepoError(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+// ── LinkIdpIdentity ────────────────────────────────────────────────────────
+
+func TestLinkIdpIdentity_Handle_Success(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("Link", mock.Anything, "user-1", "https://work.example.com", "sub-w").Return("user-1", nil)
+
+	h := command.NewLinkIdpIdentity(idpRepo)
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "sub-w")
+
+	require.NoError(t, err)
+	idpRepo.AssertExpectations(t)
+}
+
+func TestLinkIdpIdentity_Handle_LinkedToOtherAccount(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("Link", mock.Anything, "user-1", "https://work.example.com", "sub-w").Return("user-2", nil)
+
+	h := command.NewLinkIdpIdentity(idpRepo)
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "sub-w")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestLinkIdpIdentity_Handle_MissingSubject(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+
+	h := command.NewLinkIdpIdentity(idpRepo)
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "")
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	idpRepo.AssertNotCalled(t, "Link")
+}
+
+func TestLinkIdpIdentity_Handle_RepoError(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("Link", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("db error"))
+
+	h := command.NewLinkIdpIdentity(idpRepo)
+	err := h.Handle(context.Background(), "user-1", "issuer", "sub")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+// ── UnlinkIdpIdentity ──────────────────────────────────────────────────────
+
+func TestUnlinkIdpIdentity_Handle_Success(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true},
+		{UserID: "user-1", Issuer: "https://work.example.com", Sub: "sub-w"},
+	}, nil)
+	idpRepo.On("Unlink", mock.Anything, "user-1", "https://work.example.com", "sub-w").Return(nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteBySub", mock.Anything, "https://work.example.com", "sub-w").Return(nil)
+
+	h := command.NewUnlinkIdpIdentity(idpRepo, sessionRepo)
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "sub-w")
+
+	require.NoError(t, err)
+	idpRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestUnlinkIdpIdentity_Handle_DeleteSessionsError(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true},
+		{UserID: "user-1", Issuer: "https://work.example.com", Sub: "sub-w"},
+	}, nil)
+	idpRepo.On("Unlink", mock.Anything, "user-1", "https://work.example.com", "sub-w").Return(nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteBySub", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	h := command.NewUnlinkIdpIdentity(idpRepo, sessionRepo)
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "sub-w")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestUnlinkIdpIdentity_Handle_LastLink(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true},
+	}, nil)
+
+	h := command.NewUnlinkIdpIdentity(idpRepo, &repoMock.SessionRepository{})
+	err := h.Handle(context.Background(), "user-1", "https://family.example.com", "sub-f")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	idpRepo.AssertNotCalled(t, "Unlink")
+}
+
+func TestUnlinkIdpIdentity_Handle_NotLinked(t *testing.T) {
+	idpRepo := &repoMock.IdpLinkRepository{}
+	idpRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true},
+		{UserID: "user-1", Issuer: "https://work.example.com", Sub: "sub-w"},
+	}, nil)
+
+	h := command.NewUnlinkIdpIdentity(idpRepo, &repoMock.SessionRepository{})
+	err := h.Handle(context.Background(), "user-1", "https://work.example.com", "someone-else")
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+	idpRepo.AssertNotCalled(t, "Unlink")
+}
+
+// ── DeleteUser ─────────────────────────────────────────────────────────────
+
+func TestDeleteUser_Handle_Success(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+	userRepo.On("DeleteByID", mock.Anything, "user-2").Return(nil)
+
+	auditRepo := expectAudit(domainmodel.AuditActionUserDelete, "user-2")
+
+	h := command.NewDeleteUser(command.NewDeleteUserData(userRepo, command.NewRecordAudit(auditRepo)))
+	err := h.Handle(context.Background(), true, testActor, "user-2")
+
+	require.NoError(t, err)
+	userRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestDeleteUser_Handle_NotAdmin(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+
+	h := command.NewDeleteUser(command.NewDeleteUserData(userRepo, nil))
+	err := h.Handle(context.Background(), false, testActor, "user-2")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	userRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestDeleteUser_Handle_Self(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+
+	h := command.NewDeleteUser(command.NewDeleteUserData(userRepo, nil))
+	err := h.Handle(context.Background(), true, testActor, testActor.UserID)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	userRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+// ── RevokeUserSessions ─────────────────────────────────────────────────────
+
+func TestRevokeUserSessions_Handle_Success(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteByUserID", mock.Anything, "user-2").Return(nil)
+
+	tokenRepo := &repoMock.AccessTokenRepository{}
+	tokenRepo.On("DeleteByUserID", mock.Anything, "user-2").Return(nil)
+
+	auditRepo := expectAudit(domainmodel.AuditActionUserSessionsRevoke, "user-2")
+
+	h := command.NewRevokeUserSessions(sessionRepo, tokenRepo, command.NewRecordAudit(auditRepo))
+	err := h.Handle(context.Background(), true, testActor, "user-2")
+
+	require.NoError(t, err)
+	sessionRepo.AssertExpectations(t)
+	tokenRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestRevokeUserSessions_Handle_TokenRepoError(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("DeleteByUserID", mock.Anything, "user-2").Return(nil)
+
+	tokenRepo := &repoMock.AccessTokenRepository{}
+	tokenRepo.On("DeleteByUserID", mock.Anything, "user-2").Return(errors.New("db error"))
+
+	h := command.NewRevokeUserSessions(sessionRepo, tokenRepo, nil)
+	err := h.Handle(context.Background(), true, testActor, "user-2")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestRevokeUserSessions_Handle_NotAdmin(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+
+	h := command.NewRevokeUserSessions(sessionRepo, nil, nil)
+	err := h.Handle(context.Background(), false, testActor, "user-2")
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	sessionRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything, mock.Anything)
+}
+
+func TestRevokeUserSessions_Handle_Self(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	tokenRepo := &repoMock.AccessTokenRepository{}
+
+	h := command.NewRevokeUserSessions(sessionRepo, tokenRepo, nil)
+	err := h.Handle(context.Background(), true, testActor, testActor.UserID)
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+	sessionRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything, mock.Anything)
+	tokenRepo.AssertNotCalled(t, "DeleteByUserID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// noReceivedCategories returns a query for a user no category is shared with.
+func noReceivedCategories() *query.GetReceivedCategories {
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByUser", mock.Anything, mock.Anything).Return([]domainrepo.CategoryShareRecord{}, nil)
+	return query.NewGetReceivedCategories(shareRepo, nil, nil)
+}
+
+func latestSessions() *repoMock.SessionRepository {
+	repo := &repoMock.SessionRepository{}
+	repo.On("ListLatest", mock.Anything).Return([]*domainrepo.SessionRecord{
+		{UserID: "user-1", Username: "alice", DisplayName: "Alice"},
+		{UserID: "user-2", Username: "bob"},
+	}, nil)
+	return repo
+}
+
+// ── GetReceivedCategories ──────────────────────────────────────────────────
+
+func TestGetReceivedCategories_Handle_Success(t *testing.T) {
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByUser", mock.Anything, "user-2").Return([]domainrepo.CategoryShareRecord{
+		{CategoryID: 5, UserID: "user-2", Role: "editor", DisplayName: "Runbooks", OwnerID: "user-1"},
+		{CategoryID: 6, UserID: "user-2", Role: "viewer", DisplayName: "Team links", OwnerID: "user-3"},
+	}, nil)
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5, 6}).Return([]domainrepo.BookmarkRecord{
+		{ID: 51, CategoryID: 5, Icon: "mdi:book", DisplayName: "Proxmox", Url: "https://pve.lan"},
+	}, nil)
+
+	h := query.NewGetReceivedCategories(shareRepo, bookmarkRepo, latestSessions())
+	categories, err := h.Handle(context.Background(), "user-2")
+
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	require.Equal(t, "Runbooks", categories[0].DisplayName)
+	require.Equal(t, domainmodel.CategoryRoleEditor, categories[0].Role)
+	require.Equal(t, "Alice", categories[0].OwnerLabel)
+	require.Len(t, categories[0].Bookmarks, 1)
+	require.Empty(t, categories[1].OwnerLabel)
+	require.Empty(t, categories[1].Bookmarks)
+}
+
+func TestGetReceivedCategories_Handle_None(t *testing.T) {
+	categories, err := noReceivedCategories().Handle(context.Background(), "user-2")
+
+	require.NoError(t, err)
+	require.Empty(t, categories)
+}
+
+// ── ListCategoryShares ─────────────────────────────────────────────────────
+
+func TestListCategoryShares_Handle_Success(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10}, nil)
+	sharedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("ListByCategory", mock.Anything, uint(5)).Return([]domainrepo.CategoryShareRecord{
+		{CategoryID: 5, UserID: "user-2", Role: "viewer", CreatedAt: sharedAt},
+	}, nil)
+
+	h := query.NewListCategoryShares(dashRepo, catRepo, shareRepo, latestSessions())
+	shares, err := h.Handle(context.Background(), "user-1", 5)
+
+	require.NoError(t, err)
+	require.Equal(t, []domainmodel.CategoryShare{
+		{CategoryID: 5, UserID: "user-2", UserLabel: "bob", Role: domainmodel.CategoryRoleViewer, SharedAt: sharedAt},
+	}, shares)
+}
+
+func TestListCategoryShares_Handle_RejectsForeignCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10}, nil)
+
+	h := query.NewListCategoryShares(dashRepo, catRepo, &repoMock.CategoryShareRepository{}, nil)
+	_, err := h.Handle(context.Background(), "user-2", 5)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
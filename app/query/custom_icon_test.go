@@ -0,0 +1,59 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── ListCustomIcons ────────────────────────────────────────────────────────
+
+func TestListCustomIcons_Handle_MarksSharedIcons(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("ListVisible", mock.Anything, "user-1").Return([]domainrepo.CustomIconRecord{
+		{ID: 1, UserID: "user-1", DisplayName: "NAS"},
+		{ID: 2, DisplayName: "Proxmox"},
+	}, nil)
+
+	icons, err := query.NewListCustomIcons(repo).Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Len(t, icons, 2)
+	require.False(t, icons[0].IsShared)
+	require.True(t, icons[1].IsShared)
+	require.Equal(t, "img:2", icons[1].Icon().String())
+}
+
+// ── GetCustomIcon ──────────────────────────────────────────────────────────
+
+func TestGetCustomIcon_Handle_OwnAndShared(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&domainrepo.CustomIconRecord{ID: 1, UserID: "user-1", Data: []byte("a")}, nil)
+	repo.On("GetByID", mock.Anything, uint(2)).Return(&domainrepo.CustomIconRecord{ID: 2, Data: []byte("b")}, nil)
+	h := query.NewGetCustomIcon(repo)
+
+	own, err := h.Handle(context.Background(), "user-1", 1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), own.Data)
+
+	shared, err := h.Handle(context.Background(), "user-1", 2)
+	require.NoError(t, err)
+	require.True(t, shared.IsShared)
+}
+
+func TestGetCustomIcon_Handle_HidesOtherUsersIcons(t *testing.T) {
+	repo := &repoMock.CustomIconRepository{}
+	repo.On("GetByID", mock.Anything, uint(1)).Return(&domainrepo.CustomIconRecord{ID: 1, UserID: "user-2"}, nil)
+
+	_, err := query.NewGetCustomIcon(repo).Handle(context.Background(), "user-1", 1)
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
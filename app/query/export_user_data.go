@@ -3,7 +3,6 @@ package query
 import (
 	"context"
 	"errors"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,12 +18,14 @@ type UserDataExporter interface {
 }
 
 type ExportUserData struct {
-	DashboardRepo   domainrepo.DashboardRepository
-	CategoryRepo    domainrepo.CategoryRepository
-	BookmarkRepo    domainrepo.BookmarkRepository
-	ThemeRepo       domainrepo.ThemeRepository
-	SettingRepo     domainrepo.SettingRepository
-	ApplicationRepo domainrepo.ApplicationRepository
+	DashboardRepo      domainrepo.DashboardRepository
+	CategoryRepo       domainrepo.CategoryRepository
+	BookmarkRepo       domainrepo.BookmarkRepository
+	ThemeRepo          domainrepo.ThemeRepository
+	SettingRepo        domainrepo.SettingRepository
+	ApplicationRepo    domainrepo.ApplicationRepository
+	SearchProviderRepo domainrepo.SearchProviderRepository
+	CustomIconRepo     domainrepo.CustomIconRepository
 }
 
 func NewExportUserData(
@@ -34,14 +35,18 @@ func NewExportUserData(
 	themeRepo domainrepo.ThemeRepository,
 	settingRepo domainrepo.SettingRepository,
 	applicationRepo domainrepo.ApplicationRepository,
+	searchProviderRepo domainrepo.SearchProviderRepository,
+	customIconRepo domainrepo.CustomIconRepository,
 ) *ExportUserData {
 	return &ExportUserData{
-		DashboardRepo:   dashboardRepo,
-		CategoryRepo:    categoryRepo,
-		BookmarkRepo:    bookmarkRepo,
-		ThemeRepo:       themeRepo,
-		SettingRepo:     settingRepo,
-		ApplicationRepo: applicationRepo,
+		DashboardRepo:      dashboardRepo,
+		CategoryRepo:       categoryRepo,
+		BookmarkRepo:       bookmarkRepo,
+		ThemeRepo:          themeRepo,
+		SettingRepo:        settingRepo,
+		ApplicationRepo:    applicationRepo,
+		SearchProviderRepo: searchProviderRepo,
+		CustomIconRepo:     customIconRepo,
 	}
 }
 
@@ -55,7 +60,7 @@ func (h *ExportUserData) Handle(ctx context.Context, userID string, username str
 	}
 
 	// Settings
-	var activeThemeID uint
+	var activeThemeID, defaultSearchProviderID uint
 	setting, err := h.SettingRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		var nfe *domainerrors.NotFoundError
@@ -64,6 +69,7 @@ func (h *ExportUserData) Handle(ctx context.Context, userID string, username str
 		}
 	} else {
 		activeThemeID = setting.ThemeID
+		defaultSearchProviderID = setting.SearchProviderID
 		export.Settings = transfer.SettingsExport{
 			Language: setting.Language,
 			Timezone: setting.Timezone,
@@ -96,53 +102,55 @@ func (h *ExportUserData) Handle(ctx context.Context, userID string, username str
 		})
 	}
 
-	// Categories + Bookmarks
-	dashboard, err := h.DashboardRepo.GetByUserID(ctx, userID)
+	// Search providers
+	providers, err := h.SearchProviderRepo.ListByUser(ctx, userID)
 	if err != nil {
-		var nfe *domainerrors.NotFoundError
-		if !errors.As(err, &nfe) {
-			return nil, domainerrors.Internal("export user data: get dashboard", err)
+		return nil, domainerrors.Internal("export user data: list search providers", err)
+	}
+	for _, p := range providers {
+		if p.ID == defaultSearchProviderID {
+			export.Settings.SearchProvider = p.Bang
 		}
-		// No dashboard yet → empty categories
-		return export, nil
+		export.SearchProviders = append(export.SearchProviders, transfer.SearchProviderExport{
+			Hash:        transfer.ContentHash(p.DisplayName, p.Bang, p.URLTemplate),
+			Name:        p.DisplayName,
+			Bang:        p.Bang,
+			URLTemplate: p.URLTemplate,
+		})
 	}
 
-	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID)
+	// Custom icons — all of the user's own; shared ones only when referenced,
+	// see exportSharedCustomIcons.
+	customIcons, err := h.CustomIconRepo.ListVisible(ctx, userID)
 	if err != nil {
-		return nil, domainerrors.Internal("export user data: list categories", err)
+		return nil, domainerrors.Internal("export user data: list custom icons", err)
 	}
-
-	categoryIDs := make([]uint, len(categories))
-	for i, c := range categories {
-		categoryIDs[i] = c.ID
+	for _, i := range customIcons {
+		if i.UserID != "" {
+			export.CustomIcons = append(export.CustomIcons, exportCustomIcon(i))
+		}
 	}
 
-	bookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	// Dashboards, categories + bookmarks
+	dashboards, err := h.DashboardRepo.ListByUserID(ctx, userID)
 	if err != nil {
-		return nil, domainerrors.Internal("export user data: list bookmarks", err)
-	}
-
-	bookmarksByCategory := make(map[uint][]domainrepo.BookmarkRecord)
-	for _, b := range bookmarks {
-		bookmarksByCategory[b.CategoryID] = append(bookmarksByCategory[b.CategoryID], b)
+		return nil, domainerrors.Internal("export user data: list dashboards", err)
 	}
-
-	for _, c := range categories {
-		catExport := transfer.CategoryExport{
-			Hash:        transfer.ContentHash(c.DisplayName, strconv.FormatBool(c.IsShelved)),
-			DisplayName: c.DisplayName,
-			IsShelved:   c.IsShelved,
-			Bookmarks:   []transfer.BookmarkExport{},
+	for _, d := range dashboards {
+		categories, err := h.exportCategories(ctx, d.ID)
+		if err != nil {
+			return nil, err
 		}
-		for _, b := range bookmarksByCategory[c.ID] {
-			catExport.Bookmarks = append(catExport.Bookmarks, transfer.BookmarkExport{
-				Hash:        transfer.ContentHash(b.Icon, b.DisplayName, b.Url),
-				Icon:        b.Icon,
-				DisplayName: b.DisplayName,
-				URL:         b.Url,
-			})
+		if d.IsDefault {
+			export.DashboardName = d.Name
+			export.Categories = categories
+			continue
 		}
-		export.Categories = append(export.Categories, catExport)
+		export.Dashboards = append(export.Dashboards, transfer.DashboardExport{
+			Hash:       transfer.ContentHash(d.Name),
+			Name:       d.Name,
+			Categories: categories,
+		})
 	}
 
 	// Applications (admin only)
@@ -167,5 +175,106 @@ func (h *ExportUserData) Handle(ctx context.Context, userID string, username str
 		}
 	}
 
+	exportSharedCustomIcons(export, customIcons)
 	return export, nil
 }
+
+// exportCategories returns the categories of a dashboard with their bookmarks,
+// sub-categories nested inside their parent.
+func (h *ExportUserData) exportCategories(ctx context.Context, dashboardID uint) ([]transfer.CategoryExport, error) {
+	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboardID)
+	if err != nil {
+		return nil, domainerrors.Internal("export user data: list categories", err)
+	}
+
+	categoryIDs := make([]uint, len(categories))
+	for i, c := range categories {
+		categoryIDs[i] = c.ID
+	}
+
+	bookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, domainerrors.Internal("export user data: list bookmarks", err)
+	}
+
+	bookmarksByCategory := make(map[uint][]domainrepo.BookmarkRecord)
+	for _, b := range bookmarks {
+		bookmarksByCategory[b.CategoryID] = append(bookmarksByCategory[b.CategoryID], b)
+	}
+
+	flat := make([]domainmodel.Category, len(categories))
+	for i, c := range categories {
+		flat[i] = domainmodel.Category{ID: c.ID, ParentID: c.ParentID, DisplayName: c.DisplayName, IsShelved: c.IsShelved, TagQuery: c.TagQuery}
+	}
+
+	var export func(tree []domainmodel.Category, parentHash string) []transfer.CategoryExport
+	export = func(tree []domainmodel.Category, parentHash string) []transfer.CategoryExport {
+		exports := make([]transfer.CategoryExport, 0, len(tree))
+		for _, c := range tree {
+			catExport := transfer.CategoryExport{
+				Hash:        transfer.CategoryHash(parentHash, c.DisplayName, c.IsShelved),
+				DisplayName: c.DisplayName,
+				IsShelved:   c.IsShelved,
+				Bookmarks:   []transfer.BookmarkExport{},
+				TagQuery:    c.TagQuery,
+			}
+			for _, b := range bookmarksByCategory[c.ID] {
+				catExport.Bookmarks = append(catExport.Bookmarks, transfer.BookmarkExport{
+					Hash:        transfer.BookmarkHash(b.Icon, b.DisplayName, b.Url, b.Tags),
+					Icon:        b.Icon,
+					DisplayName: b.DisplayName,
+					URL:         b.Url,
+					Tags:        b.Tags,
+				})
+			}
+			if len(c.Children) > 0 {
+				catExport.Children = export(c.Children, catExport.Hash)
+			}
+			exports = append(exports, catExport)
+		}
+		return exports
+	}
+	return export(domainmodel.NestCategories(flat), ""), nil
+}
+
+// exportSharedCustomIcons adds the shared icons that bookmarks or applications
+// of export refer to.
+func exportSharedCustomIcons(export *transfer.UserDataExport, customIcons []domainrepo.CustomIconRecord) {
+	referenced := map[uint]bool{}
+	mark := func(raw string) {
+		if icon, err := domainmodel.ParseIcon(raw); err == nil && icon.Type() == domainmodel.CustomIconType {
+			id, _ := domainmodel.ParseCustomIconID(icon.Name())
+			referenced[id] = true
+		}
+	}
+	markCategories := func(categories []transfer.CategoryExport) {
+		transfer.WalkCategories(categories, func(c *transfer.CategoryExport) {
+			for _, b := range c.Bookmarks {
+				mark(b.Icon)
+			}
+		})
+	}
+	markCategories(export.Categories)
+	for _, d := range export.Dashboards {
+		markCategories(d.Categories)
+	}
+	for _, a := range export.Applications {
+		mark(a.Icon)
+	}
+	for _, i := range customIcons {
+		if i.UserID == "" && referenced[i.ID] {
+			export.CustomIcons = append(export.CustomIcons, exportCustomIcon(i))
+		}
+	}
+}
+
+func exportCustomIcon(i domainrepo.CustomIconRecord) transfer.CustomIconExport {
+	return transfer.CustomIconExport{
+		Hash:        transfer.ContentHash(i.ContentType, string(i.Data)),
+		ID:          i.ID,
+		Name:        i.DisplayName,
+		ContentType: i.ContentType,
+		Data:        i.Data,
+		IsShared:    i.UserID == "",
+	}
+}
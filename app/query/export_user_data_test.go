@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	"git.at.oechsler.it/samuel/dash/v2/app/transfer"
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
 	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
@@ -23,7 +24,17 @@ func newExportHandler(
 	settingRepo *repoMock.SettingRepository,
 	appRepo *repoMock.ApplicationRepository,
 ) *query.ExportUserData {
-	return query.NewExportUserData(dashRepo, catRepo, bRepo, themeRepo, settingRepo, appRepo)
+	searchProviderRepo := &repoMock.SearchProviderRepository{}
+	searchProviderRepo.On("ListByUser", mock.Anything, mock.Anything).
+		Return([]domainrepo.SearchProviderRecord{}, nil).Maybe()
+	return query.NewExportUserData(dashRepo, catRepo, bRepo, themeRepo, settingRepo, appRepo, searchProviderRepo, emptyCustomIconRepo())
+}
+
+func emptyCustomIconRepo() *repoMock.CustomIconRepository {
+	customIconRepo := &repoMock.CustomIconRepository{}
+	customIconRepo.On("ListVisible", mock.Anything, mock.Anything).
+		Return([]domainrepo.CustomIconRecord{}, nil).Maybe()
+	return customIconRepo
 }
 
 func TestExportUserData_Handle_SettingsRepoError(t *testing.T) {
@@ -61,8 +72,8 @@ func TestExportUserData_Handle_NoDashboard_ReturnsEmptyCategories(t *testing.T)
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
 
 	h := newExportHandler(dashRepo, nil, nil, themeRepo, settingRepo, nil)
 	export, err := h.Handle(context.Background(), "user-1", "sam", false)
@@ -84,8 +95,8 @@ func TestExportUserData_Handle_WithCategoriesAndBookmarks(t *testing.T) {
 	}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
@@ -108,6 +119,87 @@ func TestExportUserData_Handle_WithCategoriesAndBookmarks(t *testing.T) {
 	require.Len(t, export.Themes, 1)
 	require.Equal(t, "Custom", export.Themes[0].Name)
 	require.Equal(t, "de", export.Settings.Language)
+	require.Equal(t, "Home", export.DashboardName)
+	require.Empty(t, export.Dashboards)
+}
+
+func TestExportUserData_Handle_NestedCategories(t *testing.T) {
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntitySetting))
+
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 2, DashboardID: 10, ParentID: 1, DisplayName: "Proxmox"},
+		{ID: 3, DashboardID: 10, DisplayName: "Dev"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1, 2, 3}).Return([]domainrepo.BookmarkRecord{
+		{ID: 20, CategoryID: 2, Icon: "mdi:server", DisplayName: "pve-1", Url: "https://pve-1.lan"},
+	}, nil)
+
+	h := newExportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	export, err := h.Handle(context.Background(), "user-1", "sam", false)
+
+	require.NoError(t, err)
+	require.Len(t, export.Categories, 2)
+	infra := export.Categories[0]
+	require.Equal(t, "Infrastructure", infra.DisplayName)
+	require.Equal(t, transfer.CategoryHash("", "Infrastructure", false), infra.Hash)
+	require.Len(t, infra.Children, 1)
+	require.Equal(t, "Proxmox", infra.Children[0].DisplayName)
+	require.Equal(t, transfer.CategoryHash(infra.Hash, "Proxmox", false), infra.Children[0].Hash)
+	require.Len(t, infra.Children[0].Bookmarks, 1)
+	require.Equal(t, "Dev", export.Categories[1].DisplayName)
+	require.Empty(t, export.Categories[1].Children)
+}
+
+func TestExportUserData_Handle_FurtherDashboards(t *testing.T) {
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntitySetting))
+
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.DashboardRecord{
+		{ID: 11, UserID: "user-1", Name: "Work"},
+		{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true},
+	}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Media"},
+	}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{
+		{ID: 2, DashboardID: 11, DisplayName: "Tickets"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+
+	h := newExportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	export, err := h.Handle(context.Background(), "user-1", "sam", false)
+
+	require.NoError(t, err)
+	require.Equal(t, "Home", export.DashboardName)
+	require.Len(t, export.Categories, 1)
+	require.Equal(t, "Media", export.Categories[0].DisplayName)
+	require.Len(t, export.Dashboards, 1)
+	require.Equal(t, "Work", export.Dashboards[0].Name)
+	require.Len(t, export.Dashboards[0].Categories, 1)
+	require.Equal(t, "Tickets", export.Dashboards[0].Categories[0].DisplayName)
 }
 
 func TestExportUserData_Handle_AdminExportsApplications(t *testing.T) {
@@ -118,10 +210,9 @@ func TestExportUserData_Handle_AdminExportsApplications(t *testing.T) {
 	themeRepo := &repoMock.ThemeRepository{}
 	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
 
-	// Admin must have a dashboard to reach the applications section
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
@@ -158,8 +249,8 @@ func TestExportUserData_Handle_FiltersSyntheticDuplicateThemes(t *testing.T) {
 	}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
 
 	h := newExportHandler(dashRepo, nil, nil, themeRepo, settingRepo, nil)
 	export, err := h.Handle(context.Background(), "user-1", "sam", false)
@@ -180,8 +271,8 @@ func TestExportUserData_Handle_ActiveThemeName(t *testing.T) {
 	}, nil)
 
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
 
 	h := newExportHandler(dashRepo, nil, nil, themeRepo, settingRepo, nil)
 	export, err := h.Handle(context.Background(), "user-1", "sam", false)
@@ -189,3 +280,110 @@ func TestExportUserData_Handle_ActiveThemeName(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "My Theme", export.Settings.ThemeName)
 }
+
+func TestExportUserData_Handle_SearchProviders(t *testing.T) {
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1", SearchProviderID: 5}, nil)
+
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	searchProviderRepo := &repoMock.SearchProviderRepository{}
+	searchProviderRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{
+		{ID: 5, DisplayName: "GitHub", Bang: "gh", URLTemplate: "https://github.com/search?q={q}"},
+	}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
+
+	h := query.NewExportUserData(dashRepo, nil, nil, themeRepo, settingRepo, nil, searchProviderRepo, emptyCustomIconRepo())
+	export, err := h.Handle(context.Background(), "user-1", "sam", false)
+
+	require.NoError(t, err)
+	require.Len(t, export.SearchProviders, 1)
+	require.Equal(t, "gh", export.SearchProviders[0].Bang)
+	require.Equal(t, "gh", export.Settings.SearchProvider)
+}
+
+func TestExportUserData_Handle_CustomIcons(t *testing.T) {
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntitySetting))
+
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Work"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1}).Return([]domainrepo.BookmarkRecord{
+		{ID: 10, CategoryID: 1, Icon: "img:7", DisplayName: "NAS", Url: "https://nas.lan"},
+	}, nil)
+
+	customIconRepo := &repoMock.CustomIconRepository{}
+	customIconRepo.On("ListVisible", mock.Anything, "user-1").Return([]domainrepo.CustomIconRecord{
+		{ID: 3, UserID: "user-1", DisplayName: "Unused", ContentType: "image/png", Data: []byte("own")},
+		{ID: 7, DisplayName: "NAS", ContentType: "image/svg+xml", Data: []byte("<svg/>")},
+		{ID: 8, DisplayName: "Other", ContentType: "image/png", Data: []byte("shared")},
+	}, nil)
+
+	searchProviderRepo := &repoMock.SearchProviderRepository{}
+	searchProviderRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{}, nil)
+
+	h := query.NewExportUserData(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil, searchProviderRepo, customIconRepo)
+	export, err := h.Handle(context.Background(), "user-1", "sam", false)
+
+	require.NoError(t, err)
+	// Own icons are exported; shared ones only when a bookmark uses them.
+	require.Len(t, export.CustomIcons, 2)
+	require.Equal(t, uint(3), export.CustomIcons[0].ID)
+	require.False(t, export.CustomIcons[0].IsShared)
+	require.Equal(t, uint(7), export.CustomIcons[1].ID)
+	require.True(t, export.CustomIcons[1].IsShared)
+	require.Equal(t, []byte("<svg/>"), export.CustomIcons[1].Data)
+}
+
+func TestExportUserData_Handle_TagsAndSmartCategories(t *testing.T) {
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1"}, nil)
+
+	themeRepo := &repoMock.ThemeRepository{}
+	themeRepo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.ThemeRecord{}, nil)
+
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Work"},
+		{ID: 2, DashboardID: 10, DisplayName: "Kubernetes", TagQuery: "tag:k8s"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).Return([]domainrepo.BookmarkRecord{
+		{ID: 10, CategoryID: 1, Icon: "mdi:link", DisplayName: "ArgoCD", Url: "https://argo.lan", Tags: []string{"k8s", "ops"}},
+	}, nil)
+
+	h := newExportHandler(dashRepo, catRepo, bookmarkRepo, themeRepo, settingRepo, nil)
+	export, err := h.Handle(context.Background(), "user-1", "sam", false)
+
+	require.NoError(t, err)
+	require.Len(t, export.Categories, 2)
+	bm := export.Categories[0].Bookmarks[0]
+	require.Equal(t, []string{"k8s", "ops"}, bm.Tags)
+	require.Equal(t, transfer.BookmarkHash("mdi:link", "ArgoCD", "https://argo.lan", bm.Tags), bm.Hash)
+	require.NotEqual(t, transfer.ContentHash("mdi:link", "ArgoCD", "https://argo.lan"), bm.Hash, "tags take part in the hash")
+	require.Equal(t, "tag:k8s", export.Categories[1].TagQuery)
+	require.Empty(t, export.Categories[1].Bookmarks)
+}
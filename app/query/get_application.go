@@ -34,11 +34,18 @@ func (h *GetApplication) Handle(ctx context.Context, id uint) (*domainmodel.AppL
 	if err != nil {
 		return nil, domainerrors.Internal("get application: parse url", err)
 	}
+	check, health, err := appHealth(*app)
+	if err != nil {
+		return nil, domainerrors.Internal("get application: parse health check", err)
+	}
 	return &domainmodel.AppLink{
 		ID:              app.ID,
 		Icon:            icon,
 		DisplayName:     app.DisplayName,
 		Url:             appUrl,
 		VisibleToGroups: app.VisibleToGroups,
+		Position:        app.Position,
+		HealthCheck:     check,
+		Health:          health,
 	}, nil
 }
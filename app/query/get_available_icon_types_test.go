@@ -17,4 +17,5 @@ func TestGetAvailableIconTypes_Handle(t *testing.T) {
 	require.NotEmpty(t, types)
 	require.Contains(t, types, "mdi")
 	require.Contains(t, types, "spi")
+	require.Contains(t, types, "favicon")
 }
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CustomIconGetter handles the get-custom-icon query.
+type CustomIconGetter interface {
+	Handle(ctx context.Context, userID string, id uint) (*domainmodel.CustomIcon, error)
+}
+
+type GetCustomIcon struct {
+	CustomIconRepo domainrepo.CustomIconRepository
+}
+
+func NewGetCustomIcon(customIconRepo domainrepo.CustomIconRepository) *GetCustomIcon {
+	return &GetCustomIcon{CustomIconRepo: customIconRepo}
+}
+
+// Handle returns the icon if it is shared or owned by the user. Icons of other
+// users are reported as not found.
+func (h *GetCustomIcon) Handle(ctx context.Context, userID string, id uint) (*domainmodel.CustomIcon, error) {
+	r, err := h.CustomIconRepo.GetByID(ctx, id)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if errors.As(err, &nfe) {
+			return nil, err
+		}
+		return nil, domainerrors.Internal("get custom icon", err)
+	}
+	if r.UserID != "" && r.UserID != userID {
+		return nil, domainerrors.NotFound(domainerrors.EntityCustomIcon)
+	}
+	icon := toCustomIcon(*r)
+	return &icon, nil
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// FaviconRetryAfter bounds how long a site without a usable icon is
+// remembered, so that a site that was down is retried soon.
+const FaviconRetryAfter = time.Hour
+
+// FaviconFetcher resolves and downloads the icon of the site at the given
+// origin. Implementations must honour ctx cancellation.
+type FaviconFetcher interface {
+	Fetch(ctx context.Context, site string) (contentType string, data []byte, err error)
+}
+
+// FaviconGetter handles the get-favicon query.
+type FaviconGetter interface {
+	Handle(ctx context.Context, site string) (*domainmodel.Favicon, error)
+}
+
+type GetFavicon struct {
+	FaviconRepo domainrepo.FaviconRepository
+	Fetcher     FaviconFetcher
+	// TTL is how long a fetched favicon is served before it is fetched again.
+	TTL time.Duration
+	// MaxEntries caps the cache; the least recently fetched favicons are
+	// dropped when a fetch would exceed it. 0 = no limit.
+	MaxEntries int
+	Now        func() time.Time
+}
+
+func NewGetFavicon(faviconRepo domainrepo.FaviconRepository, fetcher FaviconFetcher, ttl time.Duration, maxEntries int) *GetFavicon {
+	return &GetFavicon{
+		FaviconRepo: faviconRepo,
+		Fetcher:     fetcher,
+		TTL:         ttl,
+		MaxEntries:  maxEntries,
+		Now:         time.Now,
+	}
+}
+
+// Handle returns the favicon of site from the cache, fetching it when it is
+// missing or expired. A failed fetch keeps serving an expired icon until the
+// next attempt. It returns a NotFoundError when the site has no usable icon.
+func (h *GetFavicon) Handle(ctx context.Context, site string) (*domainmodel.Favicon, error) {
+	origin, err := domainmodel.ParseFaviconSite(site)
+	if err != nil {
+		return nil, domainerrors.Validation(domainerrors.Violation{Field: "site", Message: err.Error()})
+	}
+
+	cached, err := h.FaviconRepo.Get(ctx, origin)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if !errors.As(err, &nfe) {
+			return nil, domainerrors.Internal("get favicon: get", err)
+		}
+		cached = nil
+	}
+
+	now := h.Now()
+	if cached != nil {
+		ttl := h.TTL
+		if len(cached.Data) == 0 {
+			ttl = min(ttl, FaviconRetryAfter)
+		}
+		favicon := toFavicon(cached)
+		if !favicon.IsExpired(now, ttl) {
+			return presentFavicon(favicon)
+		}
+	}
+
+	record := &domainrepo.FaviconRecord{Site: origin, FetchedAt: now}
+	contentType, data, err := h.Fetcher.Fetch(ctx, origin)
+	switch {
+	case err == nil:
+		record.ContentType = contentType
+		record.Data = data
+	case cached != nil:
+		record.ContentType = cached.ContentType
+		record.Data = cached.Data
+	}
+	if err := h.FaviconRepo.Upsert(ctx, record); err != nil {
+		return nil, domainerrors.Internal("get favicon: upsert", err)
+	}
+	if h.MaxEntries > 0 {
+		if err := h.FaviconRepo.DeleteAllButNewest(ctx, h.MaxEntries); err != nil {
+			return nil, domainerrors.Internal("get favicon: prune", err)
+		}
+	}
+	return presentFavicon(toFavicon(record))
+}
+
+func toFavicon(r *domainrepo.FaviconRecord) domainmodel.Favicon {
+	return domainmodel.Favicon{
+		Site:        r.Site,
+		ContentType: r.ContentType,
+		Data:        r.Data,
+		FetchedAt:   r.FetchedAt,
+	}
+}
+
+func presentFavicon(f domainmodel.Favicon) (*domainmodel.Favicon, error) {
+	if len(f.Data) == 0 {
+		return nil, domainerrors.NotFound(domainerrors.EntityFavicon)
+	}
+	return &f, nil
+}
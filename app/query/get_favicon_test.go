@@ -0,0 +1,139 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+var faviconNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newGetFavicon(repo *repoMock.FaviconRepository, fetcher *repoMock.FaviconFetcher) *query.GetFavicon {
+	h := query.NewGetFavicon(repo, fetcher, 24*time.Hour, 0)
+	h.Now = func() time.Time { return faviconNow }
+	return h
+}
+
+// ── Handle ──
+
+func TestGetFavicon_Handle_InvalidSite(t *testing.T) {
+	h := newGetFavicon(&repoMock.FaviconRepository{}, &repoMock.FaviconFetcher{})
+	_, err := h.Handle(context.Background(), "ftp://nas.lan")
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestGetFavicon_Handle_ServesFreshCache(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(&domainrepo.FaviconRecord{
+		Site: "https://github.com", ContentType: "image/png", Data: []byte("png"), FetchedAt: faviconNow.Add(-time.Hour),
+	}, nil)
+	fetcher := &repoMock.FaviconFetcher{}
+
+	favicon, err := newGetFavicon(repo, fetcher).Handle(context.Background(), "GitHub.com")
+
+	require.NoError(t, err)
+	require.Equal(t, []byte("png"), favicon.Data)
+	fetcher.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything)
+}
+
+func TestGetFavicon_Handle_FetchesWhenMissing(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(nil, domainerrors.NotFound(domainerrors.EntityFavicon))
+	repo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.FaviconRecord) bool {
+		return r.Site == "https://github.com" && string(r.Data) == "png" && r.FetchedAt.Equal(faviconNow)
+	})).Return(nil)
+	fetcher := &repoMock.FaviconFetcher{}
+	fetcher.On("Fetch", mock.Anything, "https://github.com").Return("image/png", []byte("png"), nil)
+
+	favicon, err := newGetFavicon(repo, fetcher).Handle(context.Background(), "github.com")
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", favicon.ContentType)
+	repo.AssertExpectations(t)
+}
+
+func TestGetFavicon_Handle_PrunesCache(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(nil, domainerrors.NotFound(domainerrors.EntityFavicon))
+	repo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	repo.On("DeleteAllButNewest", mock.Anything, 100).Return(nil)
+	fetcher := &repoMock.FaviconFetcher{}
+	fetcher.On("Fetch", mock.Anything, "https://github.com").Return("image/png", []byte("png"), nil)
+	h := newGetFavicon(repo, fetcher)
+	h.MaxEntries = 100
+
+	_, err := h.Handle(context.Background(), "github.com")
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestGetFavicon_Handle_FailedFetchKeepsExpiredIcon(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(&domainrepo.FaviconRecord{
+		Site: "https://github.com", ContentType: "image/png", Data: []byte("old"), FetchedAt: faviconNow.Add(-48 * time.Hour),
+	}, nil)
+	repo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.FaviconRecord) bool {
+		return string(r.Data) == "old" && r.FetchedAt.Equal(faviconNow)
+	})).Return(nil)
+	fetcher := &repoMock.FaviconFetcher{}
+	fetcher.On("Fetch", mock.Anything, "https://github.com").Return("", nil, errors.New("timeout"))
+
+	favicon, err := newGetFavicon(repo, fetcher).Handle(context.Background(), "github.com")
+
+	require.NoError(t, err)
+	require.Equal(t, []byte("old"), favicon.Data)
+	repo.AssertExpectations(t)
+}
+
+func TestGetFavicon_Handle_FailedFetchIsRemembered(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(nil, domainerrors.NotFound(domainerrors.EntityFavicon))
+	repo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.FaviconRecord) bool {
+		return len(r.Data) == 0
+	})).Return(nil)
+	fetcher := &repoMock.FaviconFetcher{}
+	fetcher.On("Fetch", mock.Anything, "https://github.com").Return("", nil, errors.New("no icon"))
+
+	_, err := newGetFavicon(repo, fetcher).Handle(context.Background(), "github.com")
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+	repo.AssertExpectations(t)
+}
+
+func TestGetFavicon_Handle_RetriesMissingIconSooner(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(&domainrepo.FaviconRecord{
+		Site: "https://github.com", FetchedAt: faviconNow.Add(-2 * query.FaviconRetryAfter),
+	}, nil)
+	repo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	fetcher := &repoMock.FaviconFetcher{}
+	fetcher.On("Fetch", mock.Anything, "https://github.com").Return("image/png", []byte("png"), nil)
+
+	favicon, err := newGetFavicon(repo, fetcher).Handle(context.Background(), "github.com")
+
+	require.NoError(t, err)
+	require.Equal(t, []byte("png"), favicon.Data)
+}
+
+func TestGetFavicon_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.FaviconRepository{}
+	repo.On("Get", mock.Anything, "https://github.com").Return(nil, errors.New("db error"))
+
+	_, err := newGetFavicon(repo, &repoMock.FaviconFetcher{}).Handle(context.Background(), "github.com")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
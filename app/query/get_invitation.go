@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// InvitationGetter handles the get-invitation query.
+type InvitationGetter interface {
+	Handle(ctx context.Context, secret string) (domainmodel.Invitation, error)
+}
+
+type GetInvitation struct {
+	Repo domainrepo.InvitationRepository
+}
+
+func NewGetInvitation(repo domainrepo.InvitationRepository) *GetInvitation {
+	return &GetInvitation{Repo: repo}
+}
+
+// Handle returns the invitation behind a link so that guests can see what
+// they are about to join before redeeming it. Redemptions are left out.
+func (h *GetInvitation) Handle(ctx context.Context, secret string) (domainmodel.Invitation, error) {
+	r, err := h.Repo.GetByTokenHash(ctx, domainmodel.HashInvitationToken(secret))
+	if err != nil {
+		return domainmodel.Invitation{}, domainerrors.WrapRepo("get invitation", err)
+	}
+	return domainmodel.Invitation{
+		ID:            r.ID,
+		Groups:        r.Groups,
+		MaxUses:       r.MaxUses,
+		Uses:          r.Uses,
+		ExpiresAt:     r.ExpiresAt,
+		GrantDuration: r.GrantDuration,
+		CreatedBy:     r.CreatedBy,
+		CreatedAt:     r.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/samber/lo"
+)
+
+// ReceivedCategoriesGetter handles the get-received-categories query.
+type ReceivedCategoriesGetter interface {
+	Handle(ctx context.Context, userId string) ([]domainmodel.ReceivedCategory, error)
+}
+
+type GetReceivedCategories struct {
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	SessionRepo       domainrepo.SessionRepository
+}
+
+func NewGetReceivedCategories(
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	sessionRepo domainrepo.SessionRepository,
+) *GetReceivedCategories {
+	return &GetReceivedCategories{
+		CategoryShareRepo: categoryShareRepo,
+		BookmarkRepo:      bookmarkRepo,
+		SessionRepo:       sessionRepo,
+	}
+}
+
+// Handle returns the categories other users shared with the user, with their
+// bookmarks, ordered by name.
+func (h *GetReceivedCategories) Handle(ctx context.Context, userId string) ([]domainmodel.ReceivedCategory, error) {
+	shares, err := h.CategoryShareRepo.ListByUser(ctx, userId)
+	if err != nil {
+		return nil, domainerrors.Internal("get received categories: list", err)
+	}
+	if len(shares) == 0 {
+		return []domainmodel.ReceivedCategory{}, nil
+	}
+
+	labels, err := userLabels(ctx, h.SessionRepo)
+	if err != nil {
+		return nil, domainerrors.Internal("get received categories: list latest sessions", err)
+	}
+
+	categoryIDs := lo.Map(shares, func(share domainrepo.CategoryShareRecord, _ int) uint {
+		return share.CategoryID
+	})
+	dataBookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarksByCategory := map[uint][]domainmodel.Bookmark{}
+	for _, b := range dataBookmarks {
+		icon, err := domainmodel.ParseIcon(b.Icon)
+		if err != nil {
+			return nil, domainerrors.Internal("get received categories: parse icon", err)
+		}
+		bUrl, err := domainmodel.ParseBookmarkURL(b.Url)
+		if err != nil {
+			return nil, domainerrors.Internal("get received categories: parse url", err)
+		}
+		bookmarksByCategory[b.CategoryID] = append(bookmarksByCategory[b.CategoryID], domainmodel.Bookmark{
+			ID:          b.ID,
+			Icon:        icon,
+			DisplayName: b.DisplayName,
+			Url:         bUrl,
+			CategoryID:  b.CategoryID,
+			Position:    b.Position,
+			Tags:        b.Tags,
+		})
+	}
+
+	categories := make([]domainmodel.ReceivedCategory, 0, len(shares))
+	for _, share := range shares {
+		categories = append(categories, domainmodel.ReceivedCategory{
+			Category: domainmodel.Category{
+				ID:          share.CategoryID,
+				DisplayName: share.DisplayName,
+				Bookmarks:   bookmarksByCategory[share.CategoryID],
+			},
+			Role:       domainmodel.CategoryRole(share.Role),
+			OwnerID:    share.OwnerID,
+			OwnerLabel: labels[share.OwnerID],
+		})
+	}
+	return categories, nil
+}
+
+// userLabels maps user IDs to the name of their latest session: the display
+// name, or the username if the IdP sends none. Users without a session are
+// missing.
+func userLabels(ctx context.Context, sessionRepo domainrepo.SessionRepository) (map[string]string, error) {
+	sessions, err := sessionRepo.ListLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(sessions))
+	for _, s := range sessions {
+		label := s.DisplayName
+		if label == "" {
+			label = s.Username
+		}
+		labels[s.UserID] = label
+	}
+	return labels, nil
+}
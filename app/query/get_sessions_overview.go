@@ -54,7 +54,11 @@ func NewGetSessionsOverview(repo domainrepo.SessionRepository) *GetSessionsOverv
 // isSessionActive returns true while the OIDC token is still valid, or — for pinned
 // sessions — if the device has been active within one token-window since its last access.
 // Token window = exp - iat; a pinned session that goes unused for that duration is stale.
+// A session whose refresh token the IdP rejected is always stale.
 func isSessionActive(r *domainrepo.SessionRecord, now time.Time) bool {
+	if r.Stale {
+		return false
+	}
 	if r.ExpiresAt.After(now) {
 		return true // token still valid
 	}
@@ -177,3 +177,28 @@ func TestGetSessionsOverview_Handle_ZeroIssuedAt_NotActive(t *testing.T) {
 	require.Len(t, result.Sessions, 1)
 	require.False(t, result.Sessions[0].IsActive)
 }
+
+func TestGetSessionsOverview_Handle_StaleSession(t *testing.T) {
+	now := time.Now()
+	stale := &domainrepo.SessionRecord{
+		ID:        "rec-1",
+		SessionID: "current-session",
+		ExpiresAt: now.Add(time.Hour), // token still valid
+		IssuedAt:  now.Add(-10 * time.Minute),
+		Stale:     true, // but the refresh token was rejected
+	}
+
+	repo := &repoMock.SessionRepository{}
+	repo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]*domainrepo.SessionRecord{stale}, nil)
+
+	h := query.NewGetSessionsOverview(repo)
+	result, err := h.Handle(context.Background(), query.SessionsOverviewInput{
+		UserID:           "user-1",
+		CurrentSessionID: "current-session",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Sessions, 1)
+	require.False(t, result.Sessions[0].IsActive)
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"context"
+	"slices"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/domain/service"
+
+	"github.com/samber/lo"
+)
+
+// SharedCategoriesGetter handles the get-shared-categories query.
+type SharedCategoriesGetter interface {
+	Handle(ctx context.Context, userId string, userGroups []string) ([]domainmodel.SharedCategory, error)
+}
+
+type GetSharedCategories struct {
+	SharedCategoryRepo domainrepo.SharedCategoryRepository
+	BookmarkRepo       domainrepo.BookmarkRepository
+}
+
+func NewGetSharedCategories(
+	sharedCategoryRepo domainrepo.SharedCategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+) *GetSharedCategories {
+	return &GetSharedCategories{
+		SharedCategoryRepo: sharedCategoryRepo,
+		BookmarkRepo:       bookmarkRepo,
+	}
+}
+
+// Handle returns the published categories visible to the user with their
+// bookmarks, ordered by name. Categories the user hid are included and
+// marked with IsHidden, so callers decide whether to show them.
+func (h *GetSharedCategories) Handle(ctx context.Context, userId string, userGroups []string) ([]domainmodel.SharedCategory, error) {
+	records, err := h.SharedCategoryRepo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("get shared categories: list", err)
+	}
+	categories := service.FilterSharedCategoriesForUser(
+		lo.Map(records, func(r domainrepo.SharedCategoryRecord, _ int) domainmodel.SharedCategory {
+			return toSharedCategory(r)
+		}),
+		userId,
+		userGroups,
+	)
+	if len(categories) == 0 {
+		return []domainmodel.SharedCategory{}, nil
+	}
+
+	hidden, err := h.SharedCategoryRepo.ListHidden(ctx, userId)
+	if err != nil {
+		return nil, domainerrors.Internal("get shared categories: list hidden", err)
+	}
+
+	categoryIDs := lo.Map(categories, func(category domainmodel.SharedCategory, _ int) uint {
+		return category.ID
+	})
+	dataBookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarksByCategory := map[uint][]domainmodel.Bookmark{}
+	for _, b := range dataBookmarks {
+		icon, err := domainmodel.ParseIcon(b.Icon)
+		if err != nil {
+			return nil, domainerrors.Internal("get shared categories: parse icon", err)
+		}
+		bUrl, err := domainmodel.ParseBookmarkURL(b.Url)
+		if err != nil {
+			return nil, domainerrors.Internal("get shared categories: parse url", err)
+		}
+		bookmarksByCategory[b.CategoryID] = append(bookmarksByCategory[b.CategoryID], domainmodel.Bookmark{
+			ID:          b.ID,
+			Icon:        icon,
+			DisplayName: b.DisplayName,
+			Url:         bUrl,
+			CategoryID:  b.CategoryID,
+			Position:    b.Position,
+			Tags:        b.Tags,
+		})
+	}
+
+	for i := range categories {
+		categories[i].Bookmarks = bookmarksByCategory[categories[i].ID]
+		categories[i].IsHidden = slices.Contains(hidden, categories[i].ID)
+	}
+	return categories, nil
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
 	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
@@ -14,21 +15,17 @@ type UserBookmarkGetter interface {
 }
 
 type GetUserBookmark struct {
-	DashboardRepo domainrepo.DashboardRepository
-	BookmarkRepo  domainrepo.BookmarkRepository
-	CategoryRepo  domainrepo.CategoryRepository
+	DashboardRepo     domainrepo.DashboardRepository
+	BookmarkRepo      domainrepo.BookmarkRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
 }
 
-func NewGetUserBookmark(dashboardRepo domainrepo.DashboardRepository, bookmarkRepo domainrepo.BookmarkRepository, categoryRepo domainrepo.CategoryRepository) *GetUserBookmark {
-	return &GetUserBookmark{DashboardRepo: dashboardRepo, BookmarkRepo: bookmarkRepo, CategoryRepo: categoryRepo}
+func NewGetUserBookmark(dashboardRepo domainrepo.DashboardRepository, bookmarkRepo domainrepo.BookmarkRepository, categoryRepo domainrepo.CategoryRepository, categoryShareRepo domainrepo.CategoryShareRepository) *GetUserBookmark {
+	return &GetUserBookmark{DashboardRepo: dashboardRepo, BookmarkRepo: bookmarkRepo, CategoryRepo: categoryRepo, CategoryShareRepo: categoryShareRepo}
 }
 
 func (h *GetUserBookmark) Handle(ctx context.Context, userId string, bookmarkId uint) (*domainmodel.Bookmark, error) {
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
-	if err != nil {
-		return nil, domainerrors.WrapRepo("get user bookmark: get dashboard", err)
-	}
-
 	bookmarkRecord, err := h.BookmarkRepo.Get(ctx, bookmarkId)
 	if err != nil {
 		return nil, domainerrors.WrapRepo("get user bookmark: get bookmark", err)
@@ -39,9 +36,24 @@ func (h *GetUserBookmark) Handle(ctx context.Context, userId string, bookmarkId
 		return nil, domainerrors.WrapRepo("get user bookmark: get category", err)
 	}
 
+	dashRecord, err := h.DashboardRepo.Get(ctx, catRecord.DashboardID)
+	if err != nil {
+		return nil, domainerrors.WrapRepo("get user bookmark: get dashboard", err)
+	}
 	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return nil, domainerrors.Forbidden("user does not own dashboard")
+	if !dash.OwnedBy(userId) {
+		share, err := h.CategoryShareRepo.Get(ctx, catRecord.ID, userId)
+		var nfe *domainerrors.NotFoundError
+		if err != nil && !errors.As(err, &nfe) {
+			return nil, domainerrors.Internal("get user bookmark: get category share", err)
+		}
+		var role domainmodel.CategoryRole
+		if share != nil {
+			role = domainmodel.CategoryRole(share.Role)
+		}
+		if !dash.CanViewCategory(userId, role) {
+			return nil, domainerrors.Forbidden("user may not view category")
+		}
 	}
 
 	icon, err := domainmodel.ParseIcon(bookmarkRecord.Icon)
@@ -59,5 +71,7 @@ func (h *GetUserBookmark) Handle(ctx context.Context, userId string, bookmarkId
 		DisplayName: bookmarkRecord.DisplayName,
 		Url:         bUrl,
 		CategoryID:  bookmarkRecord.CategoryID,
+		Position:    bookmarkRecord.Position,
+		Tags:        bookmarkRecord.Tags,
 	}, nil
 }
@@ -15,10 +15,18 @@ import (
 
 func TestGetUserBookmark_Handle_DashboardNotFound(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
-	h := query.NewGetUserBookmark(dashRepo, nil, nil)
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.BookmarkRecord{ID: 5, CategoryID: 1}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
+
+	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo, nil)
 	_, err := h.Handle(context.Background(), "user-1", 5)
 
 	var nfe *domainerrors.NotFoundError
@@ -27,14 +35,14 @@ func TestGetUserBookmark_Handle_DashboardNotFound(t *testing.T) {
 
 func TestGetUserBookmark_Handle_BookmarkNotFound(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	bookmarkRepo := &repoMock.BookmarkRepository{}
 	bookmarkRepo.On("Get", mock.Anything, uint(5)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityBookmark))
 
-	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, nil)
+	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, nil, nil)
 	_, err := h.Handle(context.Background(), "user-1", 5)
 
 	var nfe *domainerrors.NotFoundError
@@ -43,8 +51,10 @@ func TestGetUserBookmark_Handle_BookmarkNotFound(t *testing.T) {
 
 func TestGetUserBookmark_Handle_ForbiddenWrongOwner(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
 
 	bookmarkRepo := &repoMock.BookmarkRepository{}
 	bookmarkRepo.On("Get", mock.Anything, uint(5)).
@@ -54,7 +64,11 @@ func TestGetUserBookmark_Handle_ForbiddenWrongOwner(t *testing.T) {
 	catRepo.On("Get", mock.Anything, uint(1)).
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
 
-	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo)
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Get", mock.Anything, uint(1), "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityCategoryShare))
+
+	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo, shareRepo)
 	_, err := h.Handle(context.Background(), "user-1", 5)
 
 	var fe *domainerrors.ForbiddenError
@@ -63,7 +77,7 @@ func TestGetUserBookmark_Handle_ForbiddenWrongOwner(t *testing.T) {
 
 func TestGetUserBookmark_Handle_Success(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	bookmarkRepo := &repoMock.BookmarkRepository{}
@@ -79,10 +93,41 @@ func TestGetUserBookmark_Handle_Success(t *testing.T) {
 	catRepo.On("Get", mock.Anything, uint(1)).
 		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 10}, nil)
 
-	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo)
+	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo, nil)
 	b, err := h.Handle(context.Background(), "user-1", 5)
 
 	require.NoError(t, err)
 	require.Equal(t, "GitHub", b.DisplayName)
 	require.Equal(t, uint(5), b.ID)
 }
+
+func TestGetUserBookmark_Handle_SharedCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("Get", mock.Anything, uint(5)).Return(&domainrepo.BookmarkRecord{
+		ID:          5,
+		CategoryID:  1,
+		Icon:        "mdi:link",
+		DisplayName: "GitHub",
+		Url:         "https://github.com",
+	}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(1)).
+		Return(&domainrepo.CategoryRecord{ID: 1, DashboardID: 99}, nil)
+
+	shareRepo := &repoMock.CategoryShareRepository{}
+	shareRepo.On("Get", mock.Anything, uint(1), "user-1").
+		Return(&domainrepo.CategoryShareRecord{CategoryID: 1, UserID: "user-1", Role: "viewer"}, nil)
+
+	h := query.NewGetUserBookmark(dashRepo, bookmarkRepo, catRepo, shareRepo)
+	b, err := h.Handle(context.Background(), "user-1", 5)
+
+	require.NoError(t, err)
+	require.Equal(t, "GitHub", b.DisplayName)
+}
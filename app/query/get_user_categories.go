@@ -12,7 +12,7 @@ import (
 
 // UserCategoriesGetter handles the get-user-categories query.
 type UserCategoriesGetter interface {
-	Handle(ctx context.Context, userId string) ([]domainmodel.Category, error)
+	Handle(ctx context.Context, userId string, dashboardID uint) ([]domainmodel.Category, error)
 }
 
 type GetUserCategories struct {
@@ -33,30 +33,91 @@ func NewGetUserCategories(
 	}
 }
 
-func (h *GetUserCategories) Handle(ctx context.Context, userId string) ([]domainmodel.Category, error) {
-	dashboard, err := h.DashboardRepo.GetByUserID(ctx, userId)
+// Handle returns the unshelved categories of the user's dashboard with the given
+// id, or of their default dashboard when dashboardID is 0, as a tree: sub-categories
+// are in the Children of their parent.
+func (h *GetUserCategories) Handle(ctx context.Context, userId string, dashboardID uint) ([]domainmodel.Category, error) {
+	dashboard, err := ownedDashboard(ctx, h.DashboardRepo, "get user categories: get dashboard", userId, dashboardID)
 	if err != nil {
 		var nfe *domainerrors.NotFoundError
 		if errors.As(err, &nfe) {
 			return []domainmodel.Category{}, nil
 		}
-		return nil, domainerrors.Internal("get user categories: get dashboard", err)
+		return nil, err
 	}
 
-	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID)
+	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID())
 	if err != nil {
 		return nil, domainerrors.Internal("get user categories: list categories", err)
 	}
 
+	shelved := shelvedTree(categories)
 	notShelvedCategories := lo.Filter(categories, func(category domainrepo.CategoryRecord, _ int) bool {
-		return !category.IsShelved
+		return !shelved[category.ID]
 	})
 
-	categoryIDs := lo.Map(notShelvedCategories, func(category domainrepo.CategoryRecord, _ int) uint {
-		return category.ID
-	})
+	bookmarksByCategory, err := categoryBookmarks(ctx, h.BookmarkRepo, "get user categories", categories, notShelvedCategories)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domainmodel.Category, 0, len(notShelvedCategories))
+	for _, category := range notShelvedCategories {
+		bookmarksOfCategory := bookmarksByCategory[category.ID]
+		result = append(result, domainmodel.Category{
+			ID:          category.ID,
+			ParentID:    category.ParentID,
+			DisplayName: category.DisplayName,
+			Position:    category.Position,
+			TagQuery:    category.TagQuery,
+			Bookmarks:   bookmarksOfCategory,
+		})
+	}
+	return domainmodel.NestCategories(result), nil
+}
+
+// ownedDashboard loads the dashboard with the given id, or the user's default
+// dashboard when id is 0, and checks that it belongs to the user. op prefixes
+// repository errors.
+func ownedDashboard(ctx context.Context, dashboards domainrepo.DashboardRepository, op string, userId string, id uint) (domainmodel.UserDashboard, error) {
+	var record *domainrepo.DashboardRecord
+	var err error
+	if id == 0 {
+		record, err = dashboards.GetDefault(ctx, userId)
+	} else {
+		record, err = dashboards.Get(ctx, id)
+	}
+	if err != nil {
+		return domainmodel.UserDashboard{}, domainerrors.WrapRepo(op, err)
+	}
+	dash := domainmodel.NewUserDashboard(record.ID, record.UserID)
+	if !dash.OwnedBy(userId) {
+		return domainmodel.UserDashboard{}, domainerrors.Forbidden("user does not own dashboard")
+	}
+	return dash, nil
+}
+
+// categoryBookmarks loads the bookmarks of categories, a selection of the
+// dashboard's categories, keyed by category id. Smart categories get the
+// bookmarks of all regular categories of the dashboard that match their tag
+// query, in dashboard order. op prefixes errors.
+func categoryBookmarks(
+	ctx context.Context,
+	bookmarkRepo domainrepo.BookmarkRepository,
+	op string,
+	dashboardCategories []domainrepo.CategoryRecord,
+	categories []domainrepo.CategoryRecord,
+) (map[uint][]domainmodel.Bookmark, error) {
+	// Smart categories may match bookmarks of any category of the dashboard.
+	sources := categories
+	if lo.SomeBy(categories, func(c domainrepo.CategoryRecord) bool { return c.TagQuery != "" }) {
+		sources = dashboardCategories
+	}
+	regular := lo.Filter(sources, func(c domainrepo.CategoryRecord, _ int) bool { return c.TagQuery == "" })
 
-	dataBookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	dataBookmarks, err := bookmarkRepo.ListByCategoryIDs(ctx, lo.Map(regular, func(c domainrepo.CategoryRecord, _ int) uint {
+		return c.ID
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -65,11 +126,11 @@ func (h *GetUserCategories) Handle(ctx context.Context, userId string) ([]domain
 	for _, b := range dataBookmarks {
 		icon, err := domainmodel.ParseIcon(b.Icon)
 		if err != nil {
-			return nil, domainerrors.Internal("get user categories: parse icon", err)
+			return nil, domainerrors.Internal(op+": parse icon", err)
 		}
 		bUrl, err := domainmodel.ParseBookmarkURL(b.Url)
 		if err != nil {
-			return nil, domainerrors.Internal("get user categories: parse url", err)
+			return nil, domainerrors.Internal(op+": parse url", err)
 		}
 		domainBookmarks = append(domainBookmarks, domainmodel.Bookmark{
 			ID:          b.ID,
@@ -77,21 +138,31 @@ func (h *GetUserCategories) Handle(ctx context.Context, userId string) ([]domain
 			DisplayName: b.DisplayName,
 			Url:         bUrl,
 			CategoryID:  b.CategoryID,
+			Position:    b.Position,
+			Tags:        b.Tags,
 		})
 	}
 
 	bookmarksByCategory := lo.GroupBy(domainBookmarks, func(bookmark domainmodel.Bookmark) uint {
 		return bookmark.CategoryID
 	})
-
-	result := make([]domainmodel.Category, 0, len(notShelvedCategories))
-	for _, category := range notShelvedCategories {
-		bookmarksOfCategory := bookmarksByCategory[category.ID]
-		result = append(result, domainmodel.Category{
-			ID:          category.ID,
-			DisplayName: category.DisplayName,
-			Bookmarks:   bookmarksOfCategory,
-		})
+	for _, category := range categories {
+		if category.TagQuery == "" {
+			continue
+		}
+		query, err := domainmodel.ParseTagQuery(category.TagQuery)
+		if err != nil {
+			return nil, domainerrors.Internal(op+": parse tag query", err)
+		}
+		var matches []domainmodel.Bookmark
+		for _, source := range regular {
+			for _, b := range bookmarksByCategory[source.ID] {
+				if query.Matches(b.Tags) {
+					matches = append(matches, b)
+				}
+			}
+		}
+		bookmarksByCategory[category.ID] = matches
 	}
-	return result, nil
+	return bookmarksByCategory, nil
 }
@@ -16,11 +16,11 @@ import (
 
 func TestGetUserCategories_Handle_NoDashboard_ReturnsEmpty(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
 	h := query.NewGetUserCategories(dashRepo, nil, nil)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Empty(t, cats)
@@ -28,11 +28,11 @@ func TestGetUserCategories_Handle_NoDashboard_ReturnsEmpty(t *testing.T) {
 
 func TestGetUserCategories_Handle_DashboardRepoError(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, errors.New("db error"))
 
 	h := query.NewGetUserCategories(dashRepo, nil, nil)
-	_, err := h.Handle(context.Background(), "user-1")
+	_, err := h.Handle(context.Background(), "user-1", 0)
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
@@ -40,7 +40,7 @@ func TestGetUserCategories_Handle_DashboardRepoError(t *testing.T) {
 
 func TestGetUserCategories_Handle_NoCategories(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -52,7 +52,7 @@ func TestGetUserCategories_Handle_NoCategories(t *testing.T) {
 		Return([]domainrepo.BookmarkRecord{}, nil)
 
 	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Empty(t, cats)
@@ -60,7 +60,7 @@ func TestGetUserCategories_Handle_NoCategories(t *testing.T) {
 
 func TestGetUserCategories_Handle_ShelvedFiltered(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -74,7 +74,7 @@ func TestGetUserCategories_Handle_ShelvedFiltered(t *testing.T) {
 		Return([]domainrepo.BookmarkRecord{}, nil)
 
 	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Len(t, cats, 1)
@@ -83,7 +83,7 @@ func TestGetUserCategories_Handle_ShelvedFiltered(t *testing.T) {
 
 func TestGetUserCategories_Handle_WithBookmarks(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -97,10 +97,111 @@ func TestGetUserCategories_Handle_WithBookmarks(t *testing.T) {
 	}, nil)
 
 	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Len(t, cats, 1)
 	require.Len(t, cats[0].Bookmarks, 1)
 	require.Equal(t, "GitHub", cats[0].Bookmarks[0].DisplayName)
 }
+
+func TestGetUserCategories_Handle_OtherDashboard(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{
+		{ID: 3, DashboardID: 11, DisplayName: "Home"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{3}).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+
+	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
+	cats, err := h.Handle(context.Background(), "user-1", 11)
+
+	require.NoError(t, err)
+	require.Len(t, cats, 1)
+	require.Equal(t, "Home", cats[0].DisplayName)
+	dashRepo.AssertNotCalled(t, "GetDefault", mock.Anything, mock.Anything)
+}
+
+func TestGetUserCategories_Handle_ForeignDashboard_Forbidden(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(20)).
+		Return(&domainrepo.DashboardRecord{ID: 20, UserID: "user-2"}, nil)
+
+	h := query.NewGetUserCategories(dashRepo, nil, nil)
+	_, err := h.Handle(context.Background(), "user-1", 20)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
+
+func TestGetUserCategories_Handle_NestedTree(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Infrastructure"},
+		{ID: 2, DashboardID: 10, ParentID: 1, DisplayName: "Proxmox"},
+		{ID: 3, DashboardID: 10, ParentID: 2, DisplayName: "Nodes"},
+		{ID: 4, DashboardID: 10, DisplayName: "Archive", IsShelved: true},
+		{ID: 5, DashboardID: 10, ParentID: 4, DisplayName: "Old nodes"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1, 2, 3}).Return([]domainrepo.BookmarkRecord{
+		{ID: 31, CategoryID: 3, Icon: "mdi:server", DisplayName: "pve-1", Url: "https://pve-1.lan"},
+	}, nil)
+
+	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
+	cats, err := h.Handle(context.Background(), "user-1", 0)
+
+	require.NoError(t, err)
+	require.Len(t, cats, 1)
+	require.Equal(t, "Infrastructure", cats[0].DisplayName)
+	require.Len(t, cats[0].Children, 1)
+	require.Equal(t, "Proxmox", cats[0].Children[0].DisplayName)
+	require.Len(t, cats[0].Children[0].Children, 1)
+	nodes := cats[0].Children[0].Children[0]
+	require.Equal(t, uint(2), nodes.ParentID)
+	require.Len(t, nodes.Bookmarks, 1)
+	require.Equal(t, "pve-1", nodes.Bookmarks[0].DisplayName)
+}
+
+func TestGetUserCategories_Handle_SmartCategory(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Work"},
+		{ID: 2, DashboardID: 10, DisplayName: "Archive", IsShelved: true},
+		{ID: 3, DashboardID: 10, DisplayName: "Kubernetes", TagQuery: "tag:k8s AND NOT tag:legacy"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1, 2}).Return([]domainrepo.BookmarkRecord{
+		{ID: 10, CategoryID: 1, Icon: "mdi:link", DisplayName: "ArgoCD", Url: "https://argo.lan", Tags: []string{"k8s"}},
+		{ID: 11, CategoryID: 1, Icon: "mdi:link", DisplayName: "Rancher", Url: "https://rancher.lan", Tags: []string{"k8s", "legacy"}},
+		{ID: 20, CategoryID: 2, Icon: "mdi:link", DisplayName: "Lens", Url: "https://lens.lan", Tags: []string{"k8s"}},
+	}, nil)
+
+	h := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
+	cats, err := h.Handle(context.Background(), "user-1", 0)
+
+	require.NoError(t, err)
+	require.Len(t, cats, 2)
+	require.Len(t, cats[0].Bookmarks, 2, "regular categories keep their own bookmarks")
+	smart := cats[1]
+	require.True(t, smart.IsSmart())
+	require.Len(t, smart.Bookmarks, 2)
+	require.Equal(t, "ArgoCD", smart.Bookmarks[0].DisplayName)
+	require.Equal(t, "Lens", smart.Bookmarks[1].DisplayName, "shelved bookmarks match too")
+}
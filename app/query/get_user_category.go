@@ -23,24 +23,22 @@ func NewGetUserCategory(dashboardRepo domainrepo.DashboardRepository, categoryRe
 }
 
 func (h *GetUserCategory) Handle(ctx context.Context, userId string, categoryId uint) (*domainmodel.Category, error) {
-	dashRecord, err := h.DashboardRepo.GetByUserID(ctx, userId)
-	if err != nil {
-		return nil, domainerrors.WrapRepo("get user category: get dashboard", err)
-	}
-
 	catRecord, err := h.CategoryRepo.Get(ctx, categoryId)
 	if err != nil {
 		return nil, domainerrors.WrapRepo("get user category: get category", err)
 	}
 
-	dash := domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID)
-	if !dash.OwnsCategory(catRecord.DashboardID) {
-		return nil, domainerrors.Forbidden("user does not own dashboard")
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "get user category: get dashboard", userId, catRecord.DashboardID); err != nil {
+		return nil, err
 	}
 
 	return &domainmodel.Category{
 		ID:          catRecord.ID,
+		DashboardID: catRecord.DashboardID,
+		ParentID:    catRecord.ParentID,
 		DisplayName: catRecord.DisplayName,
 		IsShelved:   catRecord.IsShelved,
+		TagQuery:    catRecord.TagQuery,
+		Position:    catRecord.Position,
 	}, nil
 }
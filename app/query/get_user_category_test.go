@@ -15,10 +15,14 @@ import (
 
 func TestGetUserCategory_Handle_DashboardNotFound(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
-	h := query.NewGetUserCategory(dashRepo, nil)
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("Get", mock.Anything, uint(5)).
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10}, nil)
+
+	h := query.NewGetUserCategory(dashRepo, catRepo)
 	_, err := h.Handle(context.Background(), "user-1", 5)
 
 	var nfe *domainerrors.NotFoundError
@@ -27,7 +31,7 @@ func TestGetUserCategory_Handle_DashboardNotFound(t *testing.T) {
 
 func TestGetUserCategory_Handle_CategoryNotFound(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -43,8 +47,10 @@ func TestGetUserCategory_Handle_CategoryNotFound(t *testing.T) {
 
 func TestGetUserCategory_Handle_ForbiddenWrongOwner(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("Get", mock.Anything, uint(99)).
+		Return(&domainrepo.DashboardRecord{ID: 99, UserID: "user-2"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("Get", mock.Anything, uint(5)).
@@ -59,12 +65,12 @@ func TestGetUserCategory_Handle_ForbiddenWrongOwner(t *testing.T) {
 
 func TestGetUserCategory_Handle_Success(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("Get", mock.Anything, uint(10)).
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("Get", mock.Anything, uint(5)).
-		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, DisplayName: "Work", IsShelved: false}, nil)
+		Return(&domainrepo.CategoryRecord{ID: 5, DashboardID: 10, ParentID: 3, DisplayName: "Work", IsShelved: false}, nil)
 
 	h := query.NewGetUserCategory(dashRepo, catRepo)
 	cat, err := h.Handle(context.Background(), "user-1", 5)
@@ -72,4 +78,6 @@ func TestGetUserCategory_Handle_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Work", cat.DisplayName)
 	require.Equal(t, uint(5), cat.ID)
+	require.Equal(t, uint(10), cat.DashboardID)
+	require.Equal(t, uint(3), cat.ParentID)
 }
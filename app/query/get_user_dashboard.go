@@ -8,53 +8,88 @@ import (
 	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
 	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/samber/lo"
 )
 
 // UserDashboardGetter handles the get-user-dashboard query.
 type UserDashboardGetter interface {
-	Handle(ctx context.Context, userId string, userGroups []string, userFirstName string, localTime time.Time) (*domainmodel.Dashboard, error)
+	Handle(ctx context.Context, userId string, dashboardID uint, userGroups []string, userFirstName string, localTime time.Time) (*domainmodel.Dashboard, error)
 }
 
 type GetUserDashboard struct {
-	DashboardRepo       domainrepo.DashboardRepository
-	GetUserCategories   *GetUserCategories
-	GetUserApplications *GetUserApplications
+	DashboardRepo         domainrepo.DashboardRepository
+	GetUserCategories     *GetUserCategories
+	GetUserApplications   *GetUserApplications
+	GetSharedCategories   *GetSharedCategories
+	GetReceivedCategories *GetReceivedCategories
 }
 
 func NewGetUserDashboard(
 	dashboardRepo domainrepo.DashboardRepository,
 	getUserCategories *GetUserCategories,
 	getUserApplications *GetUserApplications,
+	getSharedCategories *GetSharedCategories,
+	getReceivedCategories *GetReceivedCategories,
 ) *GetUserDashboard {
 	return &GetUserDashboard{
-		DashboardRepo:       dashboardRepo,
-		GetUserCategories:   getUserCategories,
-		GetUserApplications: getUserApplications,
+		DashboardRepo:         dashboardRepo,
+		GetUserCategories:     getUserCategories,
+		GetUserApplications:   getUserApplications,
+		GetSharedCategories:   getSharedCategories,
+		GetReceivedCategories: getReceivedCategories,
 	}
 }
 
+// Handle returns the user's dashboard with the given id, or their default
+// dashboard when dashboardID is 0. Users without any dashboard get a default
+// one. Categories other users shared or published only show up on the default
+// dashboard.
 func (h *GetUserDashboard) Handle(
 	ctx context.Context,
 	userId string,
+	dashboardID uint,
 	userGroups []string,
 	userFirstName string,
 	localTime time.Time,
 ) (*domainmodel.Dashboard, error) {
-	_, err := h.DashboardRepo.GetByUserID(ctx, userId)
-	if err != nil {
-		var nfe *domainerrors.NotFoundError
-		if !errors.As(err, &nfe) {
-			return nil, domainerrors.Internal("get user dashboard: get dashboard", err)
+	var dashRecord *domainrepo.DashboardRecord
+	if dashboardID == 0 {
+		var err error
+		dashRecord, err = h.DashboardRepo.GetDefault(ctx, userId)
+		if err != nil {
+			var nfe *domainerrors.NotFoundError
+			if !errors.As(err, &nfe) {
+				return nil, domainerrors.Internal("get user dashboard: get default dashboard", err)
+			}
+			// dashboard doesn't exist yet, auto-provision
+			dashRecord = &domainrepo.DashboardRecord{
+				UserID:    userId,
+				Name:      domainmodel.DefaultDashboardName,
+				IsDefault: true,
+			}
+			if err := h.DashboardRepo.Upsert(ctx, dashRecord); err != nil {
+				// A concurrent request may have provisioned it first; the
+				// unique index on default dashboards rejects the second one.
+				existing, getErr := h.DashboardRepo.GetDefault(ctx, userId)
+				if getErr != nil {
+					return nil, domainerrors.Internal("get user dashboard: upsert dashboard", err)
+				}
+				dashRecord = existing
+			}
 		}
-		// dashboard doesn't exist yet, auto-provision
-		if err := h.DashboardRepo.Upsert(ctx, &domainrepo.DashboardRecord{
-			UserID: userId,
-		}); err != nil {
-			return nil, domainerrors.Internal("get user dashboard: upsert dashboard", err)
+	} else {
+		var err error
+		dashRecord, err = h.DashboardRepo.Get(ctx, dashboardID)
+		if err != nil {
+			return nil, domainerrors.WrapRepo("get user dashboard: get dashboard", err)
+		}
+		if !domainmodel.NewUserDashboard(dashRecord.ID, dashRecord.UserID).OwnedBy(userId) {
+			return nil, domainerrors.Forbidden("user does not own dashboard")
 		}
 	}
 
-	categories, err := h.GetUserCategories.Handle(ctx, userId)
+	categories, err := h.GetUserCategories.Handle(ctx, userId, dashRecord.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +99,30 @@ func (h *GetUserDashboard) Handle(
 		return nil, err
 	}
 
-	return &domainmodel.Dashboard{
+	dashboard := &domainmodel.Dashboard{
+		ID:           dashRecord.ID,
+		Name:         dashRecord.Name,
+		IsDefault:    dashRecord.IsDefault,
 		Applications: apps,
 		Categories:   categories,
-	}, nil
+	}
+	if !dashRecord.IsDefault {
+		return dashboard, nil
+	}
+
+	shared, err := h.GetSharedCategories.Handle(ctx, userId, userGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	received, err := h.GetReceivedCategories.Handle(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard.ReceivedCategories = received
+	dashboard.SharedCategories = lo.Filter(shared, func(category domainmodel.SharedCategory, _ int) bool {
+		return !category.IsHidden
+	})
+	return dashboard, nil
 }
@@ -17,8 +17,10 @@ import (
 
 func TestGetUserDashboard_Handle_ExistingDashboard(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}, nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Home", IsDefault: true}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
 	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
@@ -30,54 +32,202 @@ func TestGetUserDashboard_Handle_ExistingDashboard(t *testing.T) {
 	appRepo := &repoMock.ApplicationRepository{}
 	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
 
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{}, nil)
+
 	getUserCats := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
 	listApps := query.NewListApplications(appRepo)
 	getUserApps := query.NewGetUserApplications(listApps)
+	getShared := query.NewGetSharedCategories(sharedRepo, bookmarkRepo)
 
-	h := query.NewGetUserDashboard(dashRepo, getUserCats, getUserApps)
-	dash, err := h.Handle(context.Background(), "user-1", []string{}, "Sam", time.Now())
+	h := query.NewGetUserDashboard(dashRepo, getUserCats, getUserApps, getShared, noReceivedCategories())
+	dash, err := h.Handle(context.Background(), "user-1", 0, []string{}, "Sam", time.Now())
 
 	require.NoError(t, err)
 	require.NotNil(t, dash)
+	require.Equal(t, uint(10), dash.ID)
+	require.Equal(t, "Home", dash.Name)
 }
 
 func TestGetUserDashboard_Handle_AutoProvisionsDashboard(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	// first call: not found → triggers upsert
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard)).Once()
+	// not found → triggers upsert of a default dashboard
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 	dashRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(r *domainrepo.DashboardRecord) bool {
-		return r.UserID == "user-1"
-	})).Return(nil)
-	// second call (inside GetUserCategories): also not found → returns empty
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
-		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard)).Once()
+		return r.UserID == "user-1" && r.Name == "Dashboard" && r.IsDefault
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domainrepo.DashboardRecord).ID = 10
+	}).Return(nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Dashboard", IsDefault: true}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
 	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).
+		Return([]domainrepo.BookmarkRecord{}, nil)
 	appRepo := &repoMock.ApplicationRepository{}
 	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
 
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{}, nil)
+
 	getUserCats := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
 	listApps := query.NewListApplications(appRepo)
 	getUserApps := query.NewGetUserApplications(listApps)
+	getShared := query.NewGetSharedCategories(sharedRepo, bookmarkRepo)
 
-	h := query.NewGetUserDashboard(dashRepo, getUserCats, getUserApps)
-	dash, err := h.Handle(context.Background(), "user-1", []string{}, "Sam", time.Now())
+	h := query.NewGetUserDashboard(dashRepo, getUserCats, getUserApps, getShared, noReceivedCategories())
+	dash, err := h.Handle(context.Background(), "user-1", 0, []string{}, "Sam", time.Now())
 
 	require.NoError(t, err)
 	require.NotNil(t, dash)
 	dashRepo.AssertCalled(t, "Upsert", mock.Anything, mock.Anything)
 }
 
+func TestGetUserDashboard_Handle_ProvisionConflictReloadsDefault(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard)).Once()
+	// a concurrent request provisioned the default dashboard first
+	dashRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("duplicate key"))
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Dashboard", IsDefault: true}, nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", Name: "Dashboard", IsDefault: true}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
+
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{}, nil)
+
+	getUserCats := query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo)
+	listApps := query.NewListApplications(appRepo)
+	getUserApps := query.NewGetUserApplications(listApps)
+	getShared := query.NewGetSharedCategories(sharedRepo, bookmarkRepo)
+
+	h := query.NewGetUserDashboard(dashRepo, getUserCats, getUserApps, getShared, noReceivedCategories())
+	dash, err := h.Handle(context.Background(), "user-1", 0, []string{}, "Sam", time.Now())
+
+	require.NoError(t, err)
+	require.Equal(t, uint(10), dash.ID)
+	dashRepo.AssertNumberOfCalls(t, "GetDefault", 2)
+}
+
+func TestGetUserDashboard_Handle_ProvisionError(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
+	dashRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	h := query.NewGetUserDashboard(dashRepo, nil, nil, nil, nil)
+	_, err := h.Handle(context.Background(), "user-1", 0, []string{}, "Sam", time.Now())
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
 func TestGetUserDashboard_Handle_DashboardRepoError(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, errors.New("db error"))
 
-	h := query.NewGetUserDashboard(dashRepo, nil, nil)
-	_, err := h.Handle(context.Background(), "user-1", []string{}, "Sam", time.Now())
+	h := query.NewGetUserDashboard(dashRepo, nil, nil, nil, nil)
+	_, err := h.Handle(context.Background(), "user-1", 0, []string{}, "Sam", time.Now())
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
 }
+
+func TestGetUserDashboard_Handle_SkipsHiddenSharedCategories(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", IsDefault: true}, nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", IsDefault: true}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{}).Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5, 6}).Return([]domainrepo.BookmarkRecord{
+		{ID: 51, CategoryID: 5, Icon: "mdi:book", DisplayName: "Wiki", Url: "https://wiki.lan"},
+	}, nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
+
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{
+		{CategoryID: 5, DisplayName: "Family docs", VisibleToGroups: []string{"family"}, PublishedBy: "admin-1"},
+		{CategoryID: 6, DisplayName: "Recipes", VisibleToGroups: []string{"family"}, PublishedBy: "admin-1"},
+		{CategoryID: 7, DisplayName: "Dev runbooks", VisibleToGroups: []string{"dev"}, PublishedBy: "admin-1"},
+	}, nil)
+	sharedRepo.On("ListHidden", mock.Anything, "user-1").Return([]uint{6}, nil)
+
+	h := query.NewGetUserDashboard(
+		dashRepo,
+		query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo),
+		query.NewGetUserApplications(query.NewListApplications(appRepo)),
+		query.NewGetSharedCategories(sharedRepo, bookmarkRepo),
+		noReceivedCategories(),
+	)
+	dash, err := h.Handle(context.Background(), "user-1", 0, []string{"family"}, "Sam", time.Now())
+
+	require.NoError(t, err)
+	require.Len(t, dash.SharedCategories, 1)
+	require.Equal(t, "Family docs", dash.SharedCategories[0].DisplayName)
+	require.Len(t, dash.SharedCategories[0].Bookmarks, 1)
+}
+
+func TestGetUserDashboard_Handle_OtherDashboardSkipsSharedCategories(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1", Name: "Work"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, mock.Anything).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
+
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+
+	h := query.NewGetUserDashboard(
+		dashRepo,
+		query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo),
+		query.NewGetUserApplications(query.NewListApplications(appRepo)),
+		query.NewGetSharedCategories(sharedRepo, bookmarkRepo),
+		noReceivedCategories(),
+	)
+	dash, err := h.Handle(context.Background(), "user-1", 11, []string{"family"}, "Sam", time.Now())
+
+	require.NoError(t, err)
+	require.Equal(t, "Work", dash.Name)
+	require.Empty(t, dash.SharedCategories)
+	sharedRepo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestGetUserDashboard_Handle_ForeignDashboard_Forbidden(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("Get", mock.Anything, uint(20)).
+		Return(&domainrepo.DashboardRecord{ID: 20, UserID: "user-2"}, nil)
+
+	h := query.NewGetUserDashboard(dashRepo, nil, nil, nil, nil)
+	_, err := h.Handle(context.Background(), "user-1", 20, []string{}, "Sam", time.Now())
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+}
@@ -61,8 +61,9 @@ func (h *GetUserSettings) Handle(ctx context.Context, userID string) (*domainmod
 	}
 
 	return &domainmodel.Setting{
-		ThemeID:  themeID,
-		Language: s.Language,
-		Timezone: s.Timezone,
+		ThemeID:          themeID,
+		Language:         s.Language,
+		Timezone:         s.Timezone,
+		SearchProviderID: s.SearchProviderID,
 	}, nil
 }
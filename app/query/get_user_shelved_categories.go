@@ -12,7 +12,7 @@ import (
 
 // UserShelvedCategoriesGetter handles the get-user-shelved-categories query.
 type UserShelvedCategoriesGetter interface {
-	Handle(ctx context.Context, userId string) ([]domainmodel.Category, error)
+	Handle(ctx context.Context, userId string, dashboardID uint) ([]domainmodel.Category, error)
 }
 
 type GetUserShelvedCategories struct {
@@ -33,66 +33,68 @@ func NewGetUserShelvedCategories(
 	}
 }
 
-func (h *GetUserShelvedCategories) Handle(ctx context.Context, userId string) ([]domainmodel.Category, error) {
-	dashboard, err := h.DashboardRepo.GetByUserID(ctx, userId)
+// Handle returns the shelved categories of the user's dashboard with the given
+// id, or of their default dashboard when dashboardID is 0. Sub-categories of
+// shelved categories are in their Children.
+func (h *GetUserShelvedCategories) Handle(ctx context.Context, userId string, dashboardID uint) ([]domainmodel.Category, error) {
+	dashboard, err := ownedDashboard(ctx, h.DashboardRepo, "get user shelved categories: get dashboard", userId, dashboardID)
 	if err != nil {
 		var nfe *domainerrors.NotFoundError
 		if errors.As(err, &nfe) {
 			return []domainmodel.Category{}, nil
 		}
-		return nil, domainerrors.Internal("get user shelved categories: get dashboard", err)
+		return nil, err
 	}
 
-	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID)
+	categories, err := h.CategoryRepo.ListByDashboardID(ctx, dashboard.ID())
 	if err != nil {
 		return nil, domainerrors.Internal("get user shelved categories: list categories", err)
 	}
 
+	shelved := shelvedTree(categories)
 	shelvedCategories := lo.Filter(categories, func(category domainrepo.CategoryRecord, _ int) bool {
-		return category.IsShelved
-	})
-
-	categoryIDs := lo.Map(shelvedCategories, func(category domainrepo.CategoryRecord, _ int) uint {
-		return category.ID
+		return shelved[category.ID]
 	})
 
-	dataBookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	bookmarksByCategory, err := categoryBookmarks(ctx, h.BookmarkRepo, "get user shelved categories", categories, shelvedCategories)
 	if err != nil {
 		return nil, err
 	}
 
-	domainBookmarks := make([]domainmodel.Bookmark, 0, len(dataBookmarks))
-	for _, b := range dataBookmarks {
-		icon, err := domainmodel.ParseIcon(b.Icon)
-		if err != nil {
-			return nil, domainerrors.Internal("get user shelved categories: parse icon", err)
-		}
-		bUrl, err := domainmodel.ParseBookmarkURL(b.Url)
-		if err != nil {
-			return nil, domainerrors.Internal("get user shelved categories: parse url", err)
-		}
-		domainBookmarks = append(domainBookmarks, domainmodel.Bookmark{
-			ID:          b.ID,
-			Icon:        icon,
-			DisplayName: b.DisplayName,
-			Url:         bUrl,
-			CategoryID:  b.CategoryID,
-		})
-	}
-
-	bookmarksByCategory := lo.GroupBy(domainBookmarks, func(bookmark domainmodel.Bookmark) uint {
-		return bookmark.CategoryID
-	})
-
 	result := make([]domainmodel.Category, 0, len(shelvedCategories))
 	for _, category := range shelvedCategories {
 		bookmarksOfCategory := bookmarksByCategory[category.ID]
 		result = append(result, domainmodel.Category{
 			ID:          category.ID,
+			ParentID:    category.ParentID,
 			DisplayName: category.DisplayName,
 			IsShelved:   category.IsShelved,
+			Position:    category.Position,
+			TagQuery:    category.TagQuery,
 			Bookmarks:   bookmarksOfCategory,
 		})
 	}
-	return result, nil
+	return domainmodel.NestCategories(result), nil
+}
+
+// shelvedTree reports for every category whether it is on the shelf, either
+// shelved itself or nested inside a shelved category.
+func shelvedTree(categories []domainrepo.CategoryRecord) map[uint]bool {
+	parents := make(map[uint]uint, len(categories))
+	isShelved := make(map[uint]bool, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+		isShelved[c.ID] = c.IsShelved
+	}
+	shelved := make(map[uint]bool, len(categories))
+	for _, c := range categories {
+		// Bounded by the number of categories so that corrupt data cannot loop forever.
+		for id, i := c.ID, 0; id != 0 && i <= len(categories); id, i = parents[id], i+1 {
+			if isShelved[id] {
+				shelved[c.ID] = true
+				break
+			}
+		}
+	}
+	return shelved
 }
@@ -16,11 +16,11 @@ import (
 
 func TestGetUserShelvedCategories_Handle_NoDashboard_ReturnsEmpty(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, domainerrors.NotFound(domainerrors.EntityDashboard))
 
 	h := query.NewGetUserShelvedCategories(dashRepo, nil, nil)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Empty(t, cats)
@@ -28,11 +28,11 @@ func TestGetUserShelvedCategories_Handle_NoDashboard_ReturnsEmpty(t *testing.T)
 
 func TestGetUserShelvedCategories_Handle_DashboardRepoError(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(nil, errors.New("db error"))
 
 	h := query.NewGetUserShelvedCategories(dashRepo, nil, nil)
-	_, err := h.Handle(context.Background(), "user-1")
+	_, err := h.Handle(context.Background(), "user-1", 0)
 
 	var ie *domainerrors.InternalError
 	require.ErrorAs(t, err, &ie)
@@ -40,7 +40,7 @@ func TestGetUserShelvedCategories_Handle_DashboardRepoError(t *testing.T) {
 
 func TestGetUserShelvedCategories_Handle_OnlyShelvedReturned(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -54,7 +54,7 @@ func TestGetUserShelvedCategories_Handle_OnlyShelvedReturned(t *testing.T) {
 		Return([]domainrepo.BookmarkRecord{}, nil)
 
 	h := query.NewGetUserShelvedCategories(dashRepo, catRepo, bookmarkRepo)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Len(t, cats, 1)
@@ -64,7 +64,7 @@ func TestGetUserShelvedCategories_Handle_OnlyShelvedReturned(t *testing.T) {
 
 func TestGetUserShelvedCategories_Handle_WithBookmarks(t *testing.T) {
 	dashRepo := &repoMock.DashboardRepository{}
-	dashRepo.On("GetByUserID", mock.Anything, "user-1").
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
 		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
 
 	catRepo := &repoMock.CategoryRepository{}
@@ -78,10 +78,37 @@ func TestGetUserShelvedCategories_Handle_WithBookmarks(t *testing.T) {
 	}, nil)
 
 	h := query.NewGetUserShelvedCategories(dashRepo, catRepo, bookmarkRepo)
-	cats, err := h.Handle(context.Background(), "user-1")
+	cats, err := h.Handle(context.Background(), "user-1", 0)
 
 	require.NoError(t, err)
 	require.Len(t, cats, 1)
 	require.Len(t, cats[0].Bookmarks, 1)
 	require.Equal(t, "Old Doc", cats[0].Bookmarks[0].DisplayName)
 }
+
+func TestGetUserShelvedCategories_Handle_SubCategoriesOfShelved(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("GetDefault", mock.Anything, "user-1").
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DashboardID: 10, DisplayName: "Work"},
+		{ID: 2, DashboardID: 10, ParentID: 1, DisplayName: "Tickets"},
+		{ID: 3, DashboardID: 10, DisplayName: "Archive", IsShelved: true},
+		{ID: 4, DashboardID: 10, ParentID: 3, DisplayName: "2023"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{3, 4}).
+		Return([]domainrepo.BookmarkRecord{}, nil)
+
+	h := query.NewGetUserShelvedCategories(dashRepo, catRepo, bookmarkRepo)
+	cats, err := h.Handle(context.Background(), "user-1", 0)
+
+	require.NoError(t, err)
+	require.Len(t, cats, 1)
+	require.Equal(t, "Archive", cats[0].DisplayName)
+	require.Len(t, cats[0].Children, 1)
+	require.Equal(t, "2023", cats[0].Children[0].DisplayName)
+}
@@ -0,0 +1,77 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListInvitations_Handle_NotAdmin(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+
+	h := query.NewListInvitations(repo)
+	_, err := h.Handle(context.Background(), false)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestListInvitations_Handle_AttachesRedemptions(t *testing.T) {
+	expires := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	repo := &repoMock.InvitationRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.InvitationRecord{
+		{ID: "inv-2", Groups: []string{"media"}, MaxUses: 5, Uses: 2},
+		{ID: "inv-1", Groups: []string{"family"}, MaxUses: 1},
+	}, nil)
+	repo.On("ListRedemptions", mock.Anything).Return([]domainrepo.InvitationRedemptionRecord{
+		{ID: 2, InvitationID: "inv-2", UserID: "user-3", ExpiresAt: expires},
+		{ID: 1, InvitationID: "inv-2", UserID: "user-2", ExpiresAt: expires},
+	}, nil)
+
+	h := query.NewListInvitations(repo)
+	invitations, err := h.Handle(context.Background(), true)
+
+	require.NoError(t, err)
+	require.Len(t, invitations, 2)
+	require.Equal(t, "inv-2", invitations[0].ID)
+	require.Len(t, invitations[0].Redemptions, 2)
+	require.Equal(t, "user-3", invitations[0].Redemptions[0].UserID)
+	require.Empty(t, invitations[1].Redemptions)
+}
+
+func TestGetInvitation_Handle_LooksUpByHash(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, domainmodel.HashInvitationToken("dash_inv_x")).
+		Return(&domainrepo.InvitationRecord{ID: "inv-1", Groups: []string{"media"}, GrantDuration: 48 * time.Hour}, nil)
+
+	h := query.NewGetInvitation(repo)
+	invitation, err := h.Handle(context.Background(), "dash_inv_x")
+
+	require.NoError(t, err)
+	require.Equal(t, "inv-1", invitation.ID)
+	require.Equal(t, []string{"media"}, invitation.Groups)
+	require.Equal(t, 48*time.Hour, invitation.GrantDuration)
+	repo.AssertNotCalled(t, "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetInvitation_Handle_NotFound(t *testing.T) {
+	repo := &repoMock.InvitationRepository{}
+	repo.On("GetByTokenHash", mock.Anything, mock.Anything).
+		Return(nil, domainerrors.NotFound(domainerrors.EntityInvitation))
+
+	h := query.NewGetInvitation(repo)
+	_, err := h.Handle(context.Background(), "dash_inv_x")
+
+	var nfe *domainerrors.NotFoundError
+	require.ErrorAs(t, err, &nfe)
+}
@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// AccessTokensLister handles the list-access-tokens query.
+type AccessTokensLister interface {
+	Handle(ctx context.Context, userID string) ([]domainmodel.AccessToken, error)
+}
+
+type ListAccessTokens struct {
+	Repo domainrepo.AccessTokenRepository
+}
+
+func NewListAccessTokens(repo domainrepo.AccessTokenRepository) *ListAccessTokens {
+	return &ListAccessTokens{Repo: repo}
+}
+
+func (h *ListAccessTokens) Handle(ctx context.Context, userID string) ([]domainmodel.AccessToken, error) {
+	records, err := h.Repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.Internal("list access tokens", err)
+	}
+
+	out := make([]domainmodel.AccessToken, 0, len(records))
+	for _, r := range records {
+		out = append(out, domainmodel.AccessToken{
+			ID:         r.ID,
+			Name:       r.Name,
+			Scope:      domainmodel.AccessTokenScope(r.Scope),
+			CreatedAt:  r.CreatedAt,
+			ExpiresAt:  r.ExpiresAt,
+			LastUsedAt: r.LastUsedAt,
+		})
+	}
+	return out, nil
+}
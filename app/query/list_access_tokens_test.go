@@ -0,0 +1,46 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListAccessTokens_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("ListByUserID", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+
+	h := query.NewListAccessTokens(repo)
+	_, err := h.Handle(context.Background(), "user-1")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestListAccessTokens_Handle_MapsRecords(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("ListByUserID", mock.Anything, "user-1").Return([]*domainrepo.AccessTokenRecord{
+		{ID: "t1", Name: "cli", Scope: "read", TokenHash: "secret-hash", CreatedAt: created},
+	}, nil)
+
+	h := query.NewListAccessTokens(repo)
+	tokens, err := h.Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "t1", tokens[0].ID)
+	require.Equal(t, "cli", tokens[0].Name)
+	require.Equal(t, domainmodel.AccessTokenScopeRead, tokens[0].Scope)
+	require.Equal(t, created, tokens[0].CreatedAt)
+}
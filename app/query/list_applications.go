@@ -36,13 +36,41 @@ func (h *ListApplications) Handle(ctx context.Context) ([]domainmodel.AppLink, e
 		if err != nil {
 			return nil, domainerrors.Internal("list applications: parse url", err)
 		}
+		check, health, err := appHealth(a)
+		if err != nil {
+			return nil, domainerrors.Internal("list applications: parse health check", err)
+		}
 		result = append(result, domainmodel.AppLink{
 			ID:              a.ID,
 			Icon:            icon,
 			DisplayName:     a.DisplayName,
 			Url:             appUrl,
 			VisibleToGroups: a.VisibleToGroups,
+			Position:        a.Position,
+			HealthCheck:     check,
+			Health:          health,
 		})
 	}
 	return result, nil
 }
+
+// appHealth maps the health check configuration and latest probe result of an
+// application record to the domain.
+func appHealth(a domainrepo.ApplicationRecord) (domainmodel.HealthCheck, domainmodel.AppHealth, error) {
+	expected, err := domainmodel.ParseStatusCodes(a.HealthExpectedStatus)
+	if err != nil {
+		return domainmodel.HealthCheck{}, domainmodel.AppHealth{}, err
+	}
+	check := domainmodel.HealthCheck{
+		Enabled:        a.HealthCheck,
+		URL:            a.HealthURL,
+		ExpectedStatus: expected,
+	}
+	health := domainmodel.AppHealth{
+		State:     domainmodel.HealthState(a.Health.State),
+		Latency:   a.Health.LatencyMs,
+		CheckedAt: a.Health.CheckedAt,
+		Error:     a.Health.Error,
+	}
+	return check, health, nil
+}
@@ -0,0 +1,57 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// auditLogLimit caps the number of entries shown at once.
+const auditLogLimit = 200
+
+// ListAuditLogQuery filters the audit log. Without AllUsers only the entries
+// of the requesting user are listed and ActorID is ignored.
+type ListAuditLogQuery struct {
+	AllUsers bool // admins only
+	ActorID  string
+	Action   domainmodel.AuditAction
+}
+
+// AuditLogLister handles the list-audit-log query.
+type AuditLogLister interface {
+	Handle(ctx context.Context, userID string, isAdmin bool, in ListAuditLogQuery) ([]domainmodel.AuditEntry, error)
+}
+
+type ListAuditLog struct {
+	Repo domainrepo.AuditLogRepository
+}
+
+func NewListAuditLog(repo domainrepo.AuditLogRepository) *ListAuditLog {
+	return &ListAuditLog{Repo: repo}
+}
+
+// Handle returns the newest matching entries, newest first.
+func (h *ListAuditLog) Handle(ctx context.Context, userID string, isAdmin bool, in ListAuditLogQuery) ([]domainmodel.AuditEntry, error) {
+	if in.AllUsers && !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may view the audit log of all users")
+	}
+	if in.Action != "" && !in.Action.IsValid() {
+		return nil, domainerrors.Validation(domainerrors.Violation{Field: "action", Message: "unknown action"})
+	}
+
+	filter := domainrepo.AuditLogFilter{
+		ActorID: userID,
+		Action:  in.Action,
+		Limit:   auditLogLimit,
+	}
+	if in.AllUsers {
+		filter.ActorID = in.ActorID
+	}
+	entries, err := h.Repo.List(ctx, filter)
+	if err != nil {
+		return nil, domainerrors.Internal("list audit log", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListAuditLog_Handle_OwnEntries(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f domainrepo.AuditLogFilter) bool {
+		return f.ActorID == "user-1" && f.Action == domainmodel.AuditActionLogin && f.Limit > 0
+	})).Return([]domainmodel.AuditEntry{{ID: 1, ActorID: "user-1", Action: domainmodel.AuditActionLogin}}, nil)
+
+	h := query.NewListAuditLog(repo)
+	// ActorID is ignored without AllUsers: users only see their own entries.
+	entries, err := h.Handle(context.Background(), "user-1", false, query.ListAuditLogQuery{
+		ActorID: "user-2",
+		Action:  domainmodel.AuditActionLogin,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestListAuditLog_Handle_AllUsersAsAdmin(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f domainrepo.AuditLogFilter) bool {
+		return f.ActorID == "" && f.Action == ""
+	})).Return([]domainmodel.AuditEntry{}, nil)
+
+	h := query.NewListAuditLog(repo)
+	_, err := h.Handle(context.Background(), "admin-1", true, query.ListAuditLogQuery{AllUsers: true})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestListAuditLog_Handle_OtherUserAsAdmin(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f domainrepo.AuditLogFilter) bool {
+		return f.ActorID == "user-2"
+	})).Return([]domainmodel.AuditEntry{}, nil)
+
+	h := query.NewListAuditLog(repo)
+	_, err := h.Handle(context.Background(), "admin-1", true, query.ListAuditLogQuery{AllUsers: true, ActorID: "user-2"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestListAuditLog_Handle_AllUsersNotAdmin(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+
+	h := query.NewListAuditLog(repo)
+	_, err := h.Handle(context.Background(), "user-1", false, query.ListAuditLogQuery{AllUsers: true})
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestListAuditLog_Handle_UnknownAction(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+
+	h := query.NewListAuditLog(repo)
+	_, err := h.Handle(context.Background(), "user-1", false, query.ListAuditLogQuery{Action: "drop_tables"})
+
+	var ve *domainerrors.ValidationError
+	require.ErrorAs(t, err, &ve)
+}
+
+func TestListAuditLog_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.AuditLogRepository{}
+	repo.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	h := query.NewListAuditLog(repo)
+	_, err := h.Handle(context.Background(), "user-1", false, query.ListAuditLogQuery{})
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CategorySharesLister handles the list-category-shares query.
+type CategorySharesLister interface {
+	Handle(ctx context.Context, userId string, categoryID uint) ([]domainmodel.CategoryShare, error)
+}
+
+type ListCategoryShares struct {
+	DashboardRepo     domainrepo.DashboardRepository
+	CategoryRepo      domainrepo.CategoryRepository
+	CategoryShareRepo domainrepo.CategoryShareRepository
+	SessionRepo       domainrepo.SessionRepository
+}
+
+func NewListCategoryShares(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	categoryShareRepo domainrepo.CategoryShareRepository,
+	sessionRepo domainrepo.SessionRepository,
+) *ListCategoryShares {
+	return &ListCategoryShares{
+		DashboardRepo:     dashboardRepo,
+		CategoryRepo:      categoryRepo,
+		CategoryShareRepo: categoryShareRepo,
+		SessionRepo:       sessionRepo,
+	}
+}
+
+// Handle lists the users a category of the user's own dashboard is shared
+// with, oldest share first.
+func (h *ListCategoryShares) Handle(ctx context.Context, userId string, categoryID uint) ([]domainmodel.CategoryShare, error) {
+	catRecord, err := h.CategoryRepo.Get(ctx, categoryID)
+	if err != nil {
+		return nil, domainerrors.WrapRepo("list category shares: get category", err)
+	}
+	if _, err := ownedDashboard(ctx, h.DashboardRepo, "list category shares: get dashboard", userId, catRecord.DashboardID); err != nil {
+		return nil, err
+	}
+
+	records, err := h.CategoryShareRepo.ListByCategory(ctx, categoryID)
+	if err != nil {
+		return nil, domainerrors.Internal("list category shares: list", err)
+	}
+	if len(records) == 0 {
+		return []domainmodel.CategoryShare{}, nil
+	}
+	labels, err := userLabels(ctx, h.SessionRepo)
+	if err != nil {
+		return nil, domainerrors.Internal("list category shares: list latest sessions", err)
+	}
+
+	shares := make([]domainmodel.CategoryShare, 0, len(records))
+	for _, r := range records {
+		shares = append(shares, domainmodel.CategoryShare{
+			CategoryID: r.CategoryID,
+			UserID:     r.UserID,
+			UserLabel:  labels[r.UserID],
+			Role:       domainmodel.CategoryRole(r.Role),
+			SharedAt:   r.CreatedAt,
+		})
+	}
+	return shares, nil
+}
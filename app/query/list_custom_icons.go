@@ -0,0 +1,45 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// CustomIconsLister handles the list-custom-icons query.
+type CustomIconsLister interface {
+	Handle(ctx context.Context, userID string) ([]domainmodel.CustomIcon, error)
+}
+
+type ListCustomIcons struct {
+	CustomIconRepo domainrepo.CustomIconRepository
+}
+
+func NewListCustomIcons(customIconRepo domainrepo.CustomIconRepository) *ListCustomIcons {
+	return &ListCustomIcons{CustomIconRepo: customIconRepo}
+}
+
+// Handle lists the user's own icons followed by the shared icons.
+func (h *ListCustomIcons) Handle(ctx context.Context, userID string) ([]domainmodel.CustomIcon, error) {
+	list, err := h.CustomIconRepo.ListVisible(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.Internal("list custom icons", err)
+	}
+	out := make([]domainmodel.CustomIcon, 0, len(list))
+	for _, r := range list {
+		out = append(out, toCustomIcon(r))
+	}
+	return out, nil
+}
+
+func toCustomIcon(r domainrepo.CustomIconRecord) domainmodel.CustomIcon {
+	return domainmodel.CustomIcon{
+		ID:          r.ID,
+		DisplayName: r.DisplayName,
+		ContentType: r.ContentType,
+		Data:        r.Data,
+		IsShared:    r.UserID == "",
+	}
+}
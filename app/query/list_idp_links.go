@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// IdpLinksLister handles the list-idp-links query.
+type IdpLinksLister interface {
+	Handle(ctx context.Context, userID string) ([]domainmodel.IdpLink, error)
+}
+
+type ListIdpLinks struct {
+	Repo domainrepo.IdpLinkRepository
+}
+
+func NewListIdpLinks(repo domainrepo.IdpLinkRepository) *ListIdpLinks {
+	return &ListIdpLinks{Repo: repo}
+}
+
+// Handle lists the identities linked to the user, primary first.
+func (h *ListIdpLinks) Handle(ctx context.Context, userID string) ([]domainmodel.IdpLink, error) {
+	records, err := h.Repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.Internal("list idp links", err)
+	}
+
+	out := make([]domainmodel.IdpLink, 0, len(records))
+	for _, r := range records {
+		out = append(out, domainmodel.IdpLink{
+			Issuer:    r.Issuer,
+			Sub:       r.Sub,
+			IsPrimary: r.IsPrimary,
+			LinkedAt:  r.LinkedAt,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListIdpLinks_Handle_RepoError(t *testing.T) {
+	repo := &repoMock.IdpLinkRepository{}
+	repo.On("ListByUserID", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+
+	h := query.NewListIdpLinks(repo)
+	_, err := h.Handle(context.Background(), "user-1")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestListIdpLinks_Handle_MapsRecords(t *testing.T) {
+	linked := time.Now().Add(-time.Hour)
+	repo := &repoMock.IdpLinkRepository{}
+	repo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true, LinkedAt: linked},
+		{UserID: "user-1", Issuer: "https://work.example.com", Sub: "sub-w", LinkedAt: linked},
+	}, nil)
+
+	h := query.NewListIdpLinks(repo)
+	links, err := h.Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Equal(t, []domainmodel.IdpLink{
+		{Issuer: "https://family.example.com", Sub: "sub-f", IsPrimary: true, LinkedAt: linked},
+		{Issuer: "https://work.example.com", Sub: "sub-w", LinkedAt: linked},
+	}, links)
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// InvitationsLister handles the list-invitations query.
+type InvitationsLister interface {
+	Handle(ctx context.Context, isAdmin bool) ([]domainmodel.Invitation, error)
+}
+
+type ListInvitations struct {
+	Repo domainrepo.InvitationRepository
+}
+
+func NewListInvitations(repo domainrepo.InvitationRepository) *ListInvitations {
+	return &ListInvitations{Repo: repo}
+}
+
+// Handle lists all invitations with their redemptions, newest first. Only
+// admins may see them.
+func (h *ListInvitations) Handle(ctx context.Context, isAdmin bool) ([]domainmodel.Invitation, error) {
+	if !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may invite guests")
+	}
+
+	records, err := h.Repo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list invitations", err)
+	}
+	redemptions, err := h.Repo.ListRedemptions(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list invitation redemptions", err)
+	}
+
+	byInvitation := map[string][]domainmodel.InvitationRedemption{}
+	for _, r := range redemptions {
+		byInvitation[r.InvitationID] = append(byInvitation[r.InvitationID], toInvitationRedemption(r))
+	}
+
+	out := make([]domainmodel.Invitation, 0, len(records))
+	for _, r := range records {
+		out = append(out, domainmodel.Invitation{
+			ID:            r.ID,
+			Groups:        r.Groups,
+			MaxUses:       r.MaxUses,
+			Uses:          r.Uses,
+			ExpiresAt:     r.ExpiresAt,
+			GrantDuration: r.GrantDuration,
+			CreatedBy:     r.CreatedBy,
+			CreatedAt:     r.CreatedAt,
+			Redemptions:   byInvitation[r.ID],
+		})
+	}
+	return out, nil
+}
+
+func toInvitationRedemption(r domainrepo.InvitationRedemptionRecord) domainmodel.InvitationRedemption {
+	return domainmodel.InvitationRedemption{
+		ID:           r.ID,
+		InvitationID: r.InvitationID,
+		UserID:       r.UserID,
+		Groups:       r.Groups,
+		ExpiresAt:    r.ExpiresAt,
+		CreatedAt:    r.CreatedAt,
+	}
+}
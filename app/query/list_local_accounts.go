@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalAccountsLister handles the list-local-accounts query.
+type LocalAccountsLister interface {
+	Handle(ctx context.Context, isAdmin bool) ([]domainmodel.LocalAccount, error)
+}
+
+type ListLocalAccounts struct {
+	Repo domainrepo.LocalAccountRepository
+}
+
+func NewListLocalAccounts(repo domainrepo.LocalAccountRepository) *ListLocalAccounts {
+	return &ListLocalAccounts{Repo: repo}
+}
+
+// Handle lists all local accounts ordered by username. Only admins may see them.
+func (h *ListLocalAccounts) Handle(ctx context.Context, isAdmin bool) ([]domainmodel.LocalAccount, error) {
+	if !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may manage local accounts")
+	}
+
+	records, err := h.Repo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list local accounts", err)
+	}
+
+	out := make([]domainmodel.LocalAccount, 0, len(records))
+	for _, r := range records {
+		out = append(out, domainmodel.LocalAccount{
+			Username:    r.Username,
+			DisplayName: r.DisplayName,
+			Email:       r.Email,
+			Groups:      r.Groups,
+			Managed:     r.Managed,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+	return out, nil
+}
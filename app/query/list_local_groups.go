@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalGroupsLister handles the list-local-groups query.
+type LocalGroupsLister interface {
+	Handle(ctx context.Context, isAdmin bool) ([]domainmodel.LocalGroup, error)
+}
+
+type ListLocalGroups struct {
+	Repo domainrepo.LocalGroupRepository
+}
+
+func NewListLocalGroups(repo domainrepo.LocalGroupRepository) *ListLocalGroups {
+	return &ListLocalGroups{Repo: repo}
+}
+
+// Handle lists all local groups with their members, ordered by name. Only
+// admins may see them.
+func (h *ListLocalGroups) Handle(ctx context.Context, isAdmin bool) ([]domainmodel.LocalGroup, error) {
+	if !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may manage groups")
+	}
+
+	records, err := h.Repo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list local groups", err)
+	}
+	return toLocalGroups(records), nil
+}
+
+func toLocalGroups(records []domainrepo.LocalGroupRecord) []domainmodel.LocalGroup {
+	out := make([]domainmodel.LocalGroup, 0, len(records))
+	for _, r := range records {
+		out = append(out, domainmodel.LocalGroup{
+			ID:        r.ID,
+			Name:      r.Name,
+			IdpGroups: r.IdpGroups,
+			Members:   r.Members,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return out
+}
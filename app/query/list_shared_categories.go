@@ -0,0 +1,52 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// SharedCategoriesLister handles the list-shared-categories query.
+type SharedCategoriesLister interface {
+	Handle(ctx context.Context, isAdmin bool) ([]domainmodel.SharedCategory, error)
+}
+
+type ListSharedCategories struct {
+	Repo domainrepo.SharedCategoryRepository
+}
+
+func NewListSharedCategories(repo domainrepo.SharedCategoryRepository) *ListSharedCategories {
+	return &ListSharedCategories{Repo: repo}
+}
+
+// Handle lists all published categories without their bookmarks, ordered by
+// name. Only admins may see them.
+func (h *ListSharedCategories) Handle(ctx context.Context, isAdmin bool) ([]domainmodel.SharedCategory, error) {
+	if !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may publish categories")
+	}
+
+	records, err := h.Repo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list shared categories", err)
+	}
+	out := make([]domainmodel.SharedCategory, 0, len(records))
+	for _, r := range records {
+		out = append(out, toSharedCategory(r))
+	}
+	return out, nil
+}
+
+func toSharedCategory(r domainrepo.SharedCategoryRecord) domainmodel.SharedCategory {
+	return domainmodel.SharedCategory{
+		Category: domainmodel.Category{
+			ID:          r.CategoryID,
+			DisplayName: r.DisplayName,
+		},
+		VisibleToGroups: r.VisibleToGroups,
+		PublishedBy:     r.PublishedBy,
+		PublishedAt:     r.PublishedAt,
+	}
+}
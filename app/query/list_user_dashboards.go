@@ -0,0 +1,40 @@
+package query
+
+import (
+	"context"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserDashboardsLister handles the list-user-dashboards query.
+type UserDashboardsLister interface {
+	Handle(ctx context.Context, userId string) ([]domainmodel.DashboardPage, error)
+}
+
+type ListUserDashboards struct {
+	DashboardRepo domainrepo.DashboardRepository
+}
+
+func NewListUserDashboards(dashboardRepo domainrepo.DashboardRepository) *ListUserDashboards {
+	return &ListUserDashboards{DashboardRepo: dashboardRepo}
+}
+
+// Handle lists the user's dashboards in their manual order.
+func (h *ListUserDashboards) Handle(ctx context.Context, userId string) ([]domainmodel.DashboardPage, error) {
+	list, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return nil, domainerrors.Internal("list user dashboards", err)
+	}
+	out := make([]domainmodel.DashboardPage, 0, len(list))
+	for _, r := range list {
+		out = append(out, domainmodel.DashboardPage{
+			ID:        r.ID,
+			Name:      r.Name,
+			Position:  r.Position,
+			IsDefault: r.IsDefault,
+		})
+	}
+	return out, nil
+}
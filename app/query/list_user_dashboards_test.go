@@ -0,0 +1,44 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListUserDashboards_Handle_RepoError(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return(nil, errors.New("db error"))
+
+	h := query.NewListUserDashboards(dashRepo)
+	_, err := h.Handle(context.Background(), "user-1")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestListUserDashboards_Handle_HappyPath(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.DashboardRecord{
+		{ID: 11, UserID: "user-1", Name: "Home"},
+		{ID: 10, UserID: "user-1", Name: "Work", Position: 1, IsDefault: true},
+	}, nil)
+
+	h := query.NewListUserDashboards(dashRepo)
+	pages, err := h.Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Equal(t, []domainmodel.DashboardPage{
+		{ID: 11, Name: "Home"},
+		{ID: 10, Name: "Work", Position: 1, IsDefault: true},
+	}, pages)
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserSearchProvidersLister handles the list-user-search-providers query.
+type UserSearchProvidersLister interface {
+	Handle(ctx context.Context, userID string) ([]domainmodel.SearchProvider, error)
+}
+
+type ListUserSearchProviders struct {
+	SearchProviderRepo domainrepo.SearchProviderRepository
+	SettingRepo        domainrepo.SettingRepository
+}
+
+func NewListUserSearchProviders(
+	searchProviderRepo domainrepo.SearchProviderRepository,
+	settingRepo domainrepo.SettingRepository,
+) *ListUserSearchProviders {
+	return &ListUserSearchProviders{
+		SearchProviderRepo: searchProviderRepo,
+		SettingRepo:        settingRepo,
+	}
+}
+
+// Handle lists the user's search providers and marks the one selected as
+// default in their settings.
+func (h *ListUserSearchProviders) Handle(ctx context.Context, userID string) ([]domainmodel.SearchProvider, error) {
+	list, err := h.SearchProviderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.Internal("list user search providers", err)
+	}
+
+	var defaultID uint
+	setting, err := h.SettingRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if !errors.As(err, &nfe) {
+			return nil, domainerrors.Internal("list user search providers: get settings", err)
+		}
+	} else {
+		defaultID = setting.SearchProviderID
+	}
+
+	out := make([]domainmodel.SearchProvider, 0, len(list))
+	for _, p := range list {
+		out = append(out, domainmodel.SearchProvider{
+			ID:          p.ID,
+			DisplayName: p.DisplayName,
+			Bang:        p.Bang,
+			URLTemplate: p.URLTemplate,
+			IsDefault:   p.ID == defaultID,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"slices"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UserTagsLister handles the list-user-tags query.
+type UserTagsLister interface {
+	Handle(ctx context.Context, userId string) ([]string, error)
+}
+
+type ListUserTags struct {
+	DashboardRepo domainrepo.DashboardRepository
+	CategoryRepo  domainrepo.CategoryRepository
+	BookmarkRepo  domainrepo.BookmarkRepository
+}
+
+func NewListUserTags(
+	dashboardRepo domainrepo.DashboardRepository,
+	categoryRepo domainrepo.CategoryRepository,
+	bookmarkRepo domainrepo.BookmarkRepository,
+) *ListUserTags {
+	return &ListUserTags{
+		DashboardRepo: dashboardRepo,
+		CategoryRepo:  categoryRepo,
+		BookmarkRepo:  bookmarkRepo,
+	}
+}
+
+// Handle lists the tags of the bookmarks on all the user's dashboards, sorted
+// and without duplicates, e.g. to suggest them while tagging a bookmark.
+func (h *ListUserTags) Handle(ctx context.Context, userId string) ([]string, error) {
+	dashboards, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return nil, domainerrors.Internal("list user tags: list dashboards", err)
+	}
+	var categoryIDs []uint
+	for _, d := range dashboards {
+		categories, err := h.CategoryRepo.ListByDashboardID(ctx, d.ID)
+		if err != nil {
+			return nil, domainerrors.Internal("list user tags: list categories", err)
+		}
+		for _, c := range categories {
+			categoryIDs = append(categoryIDs, c.ID)
+		}
+	}
+	bookmarks, err := h.BookmarkRepo.ListByCategoryIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, domainerrors.Internal("list user tags: list bookmarks", err)
+	}
+
+	tags := []string{}
+	for _, b := range bookmarks {
+		tags = append(tags, b.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags), nil
+}
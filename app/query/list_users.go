@@ -0,0 +1,91 @@
+package query
+
+import (
+	"context"
+	"slices"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// UsersLister handles the list-users query.
+type UsersLister interface {
+	Handle(ctx context.Context, isAdmin bool) ([]domainmodel.UserSummary, error)
+}
+
+type ListUsers struct {
+	UserRepo    domainrepo.UserRepository
+	IdpLinkRepo domainrepo.IdpLinkRepository
+	SessionRepo domainrepo.SessionRepository
+}
+
+func NewListUsers(
+	userRepo domainrepo.UserRepository,
+	idpLinkRepo domainrepo.IdpLinkRepository,
+	sessionRepo domainrepo.SessionRepository,
+) *ListUsers {
+	return &ListUsers{UserRepo: userRepo, IdpLinkRepo: idpLinkRepo, SessionRepo: sessionRepo}
+}
+
+// Handle lists all users, most recently seen first; users without a session
+// follow in ID order. Only admins may see them.
+func (h *ListUsers) Handle(ctx context.Context, isAdmin bool) ([]domainmodel.UserSummary, error) {
+	if !isAdmin {
+		return nil, domainerrors.Forbidden("only admins may manage users")
+	}
+
+	users, err := h.UserRepo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list users", err)
+	}
+	links, err := h.IdpLinkRepo.List(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list idp links", err)
+	}
+	sessions, err := h.SessionRepo.ListLatest(ctx)
+	if err != nil {
+		return nil, domainerrors.Internal("list latest sessions", err)
+	}
+
+	linksByUser := map[string][]domainmodel.IdpLink{}
+	for _, l := range links {
+		linksByUser[l.UserID] = append(linksByUser[l.UserID], domainmodel.IdpLink{
+			Issuer:    l.Issuer,
+			Sub:       l.Sub,
+			IsPrimary: l.IsPrimary,
+			LinkedAt:  l.LinkedAt,
+		})
+	}
+	sessionByUser := map[string]*domainrepo.SessionRecord{}
+	for _, s := range sessions {
+		sessionByUser[s.UserID] = s
+	}
+
+	out := make([]domainmodel.UserSummary, 0, len(users))
+	for _, u := range users {
+		summary := domainmodel.UserSummary{
+			UserID:        u.ID,
+			IdpLinks:      linksByUser[u.ID],
+			CategoryCount: u.CategoryCount,
+			BookmarkCount: u.BookmarkCount,
+		}
+		if s, ok := sessionByUser[u.ID]; ok {
+			summary.DisplayName = s.DisplayName
+			summary.Username = s.Username
+			summary.Email = s.Email
+			summary.IsAdmin = s.IsAdmin
+			summary.LastSeenAt = s.LastAccessedAt
+			if summary.LastSeenAt.IsZero() {
+				summary.LastSeenAt = s.CreatedAt
+			}
+		}
+		out = append(out, summary)
+	}
+
+	// Stable sort keeps the repository's ID order among users never seen.
+	slices.SortStableFunc(out, func(a, b domainmodel.UserSummary) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+	return out, nil
+}
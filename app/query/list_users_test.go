@@ -0,0 +1,76 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func TestListUsers_Handle_NotAdmin(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+
+	h := query.NewListUsers(userRepo, &repoMock.IdpLinkRepository{}, &repoMock.SessionRepository{})
+	_, err := h.Handle(context.Background(), false)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	userRepo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestListUsers_Handle_RepoError(t *testing.T) {
+	userRepo := &repoMock.UserRepository{}
+	userRepo.On("List", mock.Anything).Return(nil, errors.New("db error"))
+
+	h := query.NewListUsers(userRepo, &repoMock.IdpLinkRepository{}, &repoMock.SessionRepository{})
+	_, err := h.Handle(context.Background(), true)
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
+
+func TestListUsers_Handle_CombinesRecords(t *testing.T) {
+	now := time.Now()
+	userRepo := &repoMock.UserRepository{}
+	userRepo.On("List", mock.Anything).Return([]domainrepo.UserRecord{
+		{ID: "user-1", CategoryCount: 2, BookmarkCount: 5},
+		{ID: "user-2"},
+		{ID: "user-3", CategoryCount: 1, BookmarkCount: 1},
+	}, nil)
+	idpLinkRepo := &repoMock.IdpLinkRepository{}
+	idpLinkRepo.On("List", mock.Anything).Return([]domainrepo.IdpLinkRecord{
+		{UserID: "user-1", Issuer: "https://id.example.com", Sub: "sub-1", IsPrimary: true},
+		{UserID: "user-1", Issuer: "https://work.example.com", Sub: "sub-w"},
+		{UserID: "user-3", Issuer: "https://id.example.com", Sub: "sub-3", IsPrimary: true},
+	}, nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("ListLatest", mock.Anything).Return([]*domainrepo.SessionRecord{
+		{UserID: "user-1", DisplayName: "Sam O", Username: "sam", LastAccessedAt: now.Add(-time.Hour)},
+		{UserID: "user-3", DisplayName: "Alex", IsAdmin: true, LastAccessedAt: now},
+	}, nil)
+
+	h := query.NewListUsers(userRepo, idpLinkRepo, sessionRepo)
+	users, err := h.Handle(context.Background(), true)
+
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	// Most recently seen first, users without a session last.
+	require.Equal(t, "user-3", users[0].UserID)
+	require.True(t, users[0].IsAdmin)
+	require.Equal(t, "user-1", users[1].UserID)
+	require.Equal(t, "Sam O", users[1].DisplayName)
+	require.Len(t, users[1].IdpLinks, 2)
+	require.Equal(t, 2, users[1].CategoryCount)
+	require.Equal(t, 5, users[1].BookmarkCount)
+	require.Equal(t, "user-2", users[2].UserID)
+	require.True(t, users[2].LastSeenAt.IsZero())
+	require.Empty(t, users[2].IdpLinks)
+}
@@ -0,0 +1,101 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── ListLocalGroups ────────────────────────────────────────────────────────
+
+func TestListLocalGroups_Handle_NotAdmin(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+
+	h := query.NewListLocalGroups(repo)
+	_, err := h.Handle(context.Background(), false)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+func TestListLocalGroups_Handle_MapsRecords(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.LocalGroupRecord{
+		{ID: 1, Name: "friends", IdpGroups: []string{"family"}, Members: []string{"user-2"}},
+	}, nil)
+
+	h := query.NewListLocalGroups(repo)
+	groups, err := h.Handle(context.Background(), true)
+
+	require.NoError(t, err)
+	require.Equal(t, []domainmodel.LocalGroup{
+		{ID: 1, Name: "friends", IdpGroups: []string{"family"}, Members: []string{"user-2"}},
+	}, groups)
+}
+
+// ── ResolveLocalGroups ─────────────────────────────────────────────────────
+
+func TestResolveLocalGroups_Handle_AddsMatchingGroups(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.LocalGroupRecord{
+		{ID: 1, Name: "friends", Members: []string{"user-1"}},
+		{ID: 2, Name: "media", IdpGroups: []string{"family"}},
+		{ID: 3, Name: "ops", IdpGroups: []string{"ops"}},
+	}, nil)
+
+	invitationRepo := &repoMock.InvitationRepository{}
+	invitationRepo.On("ListActiveRedemptions", mock.Anything, "user-1", mock.Anything).Return([]domainrepo.InvitationRedemptionRecord{}, nil)
+
+	h := query.NewResolveLocalGroups(repo, invitationRepo)
+	identity, err := h.Handle(context.Background(), domainmodel.Identity{
+		UserID: "user-1",
+		Groups: []string{"dash_user", "family"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"dash_user", "family", "friends", "media"}, identity.Groups)
+}
+
+func TestResolveLocalGroups_Handle_AddsInvitationGrants(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.LocalGroupRecord{
+		{ID: 1, Name: "media", IdpGroups: []string{"guests"}},
+	}, nil)
+	invitationRepo := &repoMock.InvitationRepository{}
+	invitationRepo.On("ListActiveRedemptions", mock.Anything, "user-1", now).Return([]domainrepo.InvitationRedemptionRecord{
+		{ID: 1, UserID: "user-1", Groups: []string{"guests"}, ExpiresAt: now.Add(time.Hour)},
+	}, nil)
+
+	h := query.NewResolveLocalGroups(repo, invitationRepo)
+	h.Now = func() time.Time { return now }
+	identity, err := h.Handle(context.Background(), domainmodel.Identity{UserID: "user-1", Groups: []string{"dash_user"}})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"dash_user", "guests", "media"}, identity.Groups)
+}
+
+func TestResolveLocalGroups_Handle_RepoErrorKeepsIdentity(t *testing.T) {
+	repo := &repoMock.LocalGroupRepository{}
+	repo.On("List", mock.Anything).Return(nil, errors.New("db error"))
+	invitationRepo := &repoMock.InvitationRepository{}
+	invitationRepo.On("ListActiveRedemptions", mock.Anything, "user-1", mock.Anything).Return([]domainrepo.InvitationRedemptionRecord{}, nil)
+
+	h := query.NewResolveLocalGroups(repo, invitationRepo)
+	identity, err := h.Handle(context.Background(), domainmodel.Identity{UserID: "user-1", Groups: []string{"dash_user"}})
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+	require.Equal(t, []string{"dash_user"}, identity.Groups)
+}
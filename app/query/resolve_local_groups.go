@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+// LocalGroupsResolver handles the resolve-local-groups query.
+type LocalGroupsResolver interface {
+	Handle(ctx context.Context, identity domainmodel.Identity) (domainmodel.Identity, error)
+}
+
+type ResolveLocalGroups struct {
+	Repo           domainrepo.LocalGroupRepository
+	InvitationRepo domainrepo.InvitationRepository
+	Now            func() time.Time
+}
+
+func NewResolveLocalGroups(repo domainrepo.LocalGroupRepository, invitationRepo domainrepo.InvitationRepository) *ResolveLocalGroups {
+	return &ResolveLocalGroups{Repo: repo, InvitationRepo: invitationRepo, Now: time.Now}
+}
+
+// Handle adds the groups managed in Dash to the identity: first the groups
+// granted by redeemed invitations that are still active, then the local
+// groups the user belongs to, directly or through any of those groups. The
+// identity loaders call it on every request, so membership changes apply
+// without signing in again.
+func (h *ResolveLocalGroups) Handle(ctx context.Context, identity domainmodel.Identity) (domainmodel.Identity, error) {
+	redemptions, err := h.InvitationRepo.ListActiveRedemptions(ctx, identity.UserID, h.Now())
+	if err != nil {
+		return identity, domainerrors.Internal("resolve local groups: list redemptions", err)
+	}
+	records, err := h.Repo.List(ctx)
+	if err != nil {
+		return identity, domainerrors.Internal("resolve local groups", err)
+	}
+
+	resolved := identity
+	for _, r := range redemptions {
+		resolved = resolved.WithGrantedGroups(r.Groups)
+	}
+	return resolved.WithLocalGroups(toLocalGroups(records)), nil
+}
@@ -0,0 +1,38 @@
+package query
+
+import (
+	"context"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	"git.at.oechsler.it/samuel/dash/v2/domain/service"
+)
+
+// UserWebSearchResolver handles the resolve-user-web-search query.
+type UserWebSearchResolver interface {
+	Handle(ctx context.Context, userID string, q string) (*domainmodel.WebSearch, error)
+}
+
+type ResolveUserWebSearch struct {
+	ListUserSearchProviders *ListUserSearchProviders
+}
+
+func NewResolveUserWebSearch(listUserSearchProviders *ListUserSearchProviders) *ResolveUserWebSearch {
+	return &ResolveUserWebSearch{ListUserSearchProviders: listUserSearchProviders}
+}
+
+// Handle returns where q goes as a web search, or nil when neither a bang nor
+// a default provider applies (see service.ResolveWebSearch).
+func (h *ResolveUserWebSearch) Handle(ctx context.Context, userID string, q string) (*domainmodel.WebSearch, error) {
+	providers, err := h.ListUserSearchProviders.Handle(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	provider, terms, ok := service.ResolveWebSearch(providers, q)
+	if !ok {
+		return nil, nil
+	}
+	return &domainmodel.WebSearch{
+		Provider: provider,
+		URL:      provider.URLFor(terms),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func searchProviderRepos(defaultID uint) (*repoMock.SearchProviderRepository, *repoMock.SettingRepository) {
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{
+		{ID: 1, DisplayName: "DuckDuckGo", Bang: "ddg", URLTemplate: "https://duckduckgo.com/?q={q}"},
+		{ID: 2, DisplayName: "GitHub", Bang: "gh", URLTemplate: "https://github.com/search?q={q}"},
+	}, nil)
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SettingRecord{UserID: "user-1", SearchProviderID: defaultID}, nil)
+	return repo, settingRepo
+}
+
+// ── ListUserSearchProviders ────────────────────────────────────────────────
+
+func TestListUserSearchProviders_Handle_MarksDefault(t *testing.T) {
+	h := query.NewListUserSearchProviders(searchProviderRepos(2))
+	providers, err := h.Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	require.False(t, providers[0].IsDefault)
+	require.True(t, providers[1].IsDefault)
+}
+
+func TestListUserSearchProviders_Handle_NoSettings(t *testing.T) {
+	repo := &repoMock.SearchProviderRepository{}
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]domainrepo.SearchProviderRecord{{ID: 1, Bang: "ddg"}}, nil)
+	settingRepo := &repoMock.SettingRepository{}
+	settingRepo.On("GetByUserID", mock.Anything, "user-1").
+		Return(nil, domainerrors.NotFound(domainerrors.EntitySetting))
+
+	h := query.NewListUserSearchProviders(repo, settingRepo)
+	providers, err := h.Handle(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.False(t, providers[0].IsDefault)
+}
+
+// ── ResolveUserWebSearch ───────────────────────────────────────────────────
+
+func TestResolveUserWebSearch_Handle_Bang(t *testing.T) {
+	h := query.NewResolveUserWebSearch(query.NewListUserSearchProviders(searchProviderRepos(1)))
+	ws, err := h.Handle(context.Background(), "user-1", "!gh dash")
+
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	require.Equal(t, "GitHub", ws.Provider.DisplayName)
+	require.Equal(t, "https://github.com/search?q=dash", ws.URL)
+}
+
+func TestResolveUserWebSearch_Handle_Default(t *testing.T) {
+	h := query.NewResolveUserWebSearch(query.NewListUserSearchProviders(searchProviderRepos(1)))
+	ws, err := h.Handle(context.Background(), "user-1", "home lab")
+
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	require.Equal(t, "https://duckduckgo.com/?q=home+lab", ws.URL)
+}
+
+func TestResolveUserWebSearch_Handle_NoDefault(t *testing.T) {
+	h := query.NewResolveUserWebSearch(query.NewListUserSearchProviders(searchProviderRepos(0)))
+	ws, err := h.Handle(context.Background(), "user-1", "home lab")
+
+	require.NoError(t, err)
+	require.Nil(t, ws)
+}
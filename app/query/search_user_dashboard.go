@@ -0,0 +1,156 @@
+package query
+
+import (
+	"context"
+	"sort"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/domain/service"
+)
+
+// DefaultSearchLimit caps the number of hits returned by SearchUserDashboard.
+const DefaultSearchLimit = 10
+
+// UserDashboardSearcher handles the search-user-dashboard query.
+type UserDashboardSearcher interface {
+	Handle(ctx context.Context, userId string, userGroups []string, q string) ([]domainmodel.SearchHit, error)
+}
+
+type SearchUserDashboard struct {
+	DashboardRepo            domainrepo.DashboardRepository
+	GetUserCategories        *GetUserCategories
+	GetUserShelvedCategories *GetUserShelvedCategories
+	GetUserApplications      *GetUserApplications
+	GetSharedCategories      *GetSharedCategories
+	GetReceivedCategories    *GetReceivedCategories
+	Limit                    int
+}
+
+func NewSearchUserDashboard(
+	dashboardRepo domainrepo.DashboardRepository,
+	getUserCategories *GetUserCategories,
+	getUserShelvedCategories *GetUserShelvedCategories,
+	getUserApplications *GetUserApplications,
+	getSharedCategories *GetSharedCategories,
+	getReceivedCategories *GetReceivedCategories,
+) *SearchUserDashboard {
+	return &SearchUserDashboard{
+		DashboardRepo:            dashboardRepo,
+		GetUserCategories:        getUserCategories,
+		GetUserShelvedCategories: getUserShelvedCategories,
+		GetUserApplications:      getUserApplications,
+		GetSharedCategories:      getSharedCategories,
+		GetReceivedCategories:    getReceivedCategories,
+		Limit:                    DefaultSearchLimit,
+	}
+}
+
+// Handle searches the applications visible to the user and the bookmarks of
+// all categories on all their dashboards, shelved ones included, followed by the categories
+// other users shared with them and the published categories they have not
+// hidden. Hits are ordered by match rank
+// (see service.MatchSearch); hits of equal rank keep dashboard order, with
+// applications before bookmarks. An empty query yields no hits.
+func (h *SearchUserDashboard) Handle(
+	ctx context.Context,
+	userId string,
+	userGroups []string,
+	q string,
+) ([]domainmodel.SearchHit, error) {
+	type rankedHit struct {
+		hit  domainmodel.SearchHit
+		rank int
+	}
+	var ranked []rankedHit
+	add := func(hit domainmodel.SearchHit) {
+		if rank, ok := service.MatchSearch(q, hit.DisplayName, hit.Host); ok {
+			ranked = append(ranked, rankedHit{hit: hit, rank: rank})
+		}
+	}
+
+	apps, err := h.GetUserApplications.Handle(ctx, userGroups)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		add(domainmodel.SearchHit{
+			Kind:        domainmodel.SearchHitApplication,
+			ID:          app.ID,
+			Icon:        app.Icon,
+			DisplayName: app.DisplayName,
+			Url:         app.Url.String(),
+			Host:        app.Url.Host(),
+		})
+	}
+
+	dashboards, err := h.DashboardRepo.ListByUserID(ctx, userId)
+	if err != nil {
+		return nil, domainerrors.Internal("search user dashboard: list dashboards", err)
+	}
+	var categories, shelved []domainmodel.Category
+	for _, d := range dashboards {
+		dashCategories, err := h.GetUserCategories.Handle(ctx, userId, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		dashShelved, err := h.GetUserShelvedCategories.Handle(ctx, userId, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, domainmodel.FlattenCategories(dashCategories)...)
+		for _, category := range domainmodel.FlattenCategories(dashShelved) {
+			// Sub-categories are on the shelf along with their parent.
+			category.IsShelved = true
+			shelved = append(shelved, category)
+		}
+	}
+	received, err := h.GetReceivedCategories.Handle(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := h.GetSharedCategories.Handle(ctx, userId, userGroups)
+	if err != nil {
+		return nil, err
+	}
+	categories = append(categories, shelved...)
+	for _, category := range received {
+		categories = append(categories, category.Category)
+	}
+	for _, category := range shared {
+		if !category.IsHidden {
+			categories = append(categories, category.Category)
+		}
+	}
+	for _, category := range categories {
+		// Smart categories show bookmarks of other categories, which are
+		// found there already.
+		if category.IsSmart() {
+			continue
+		}
+		for _, b := range category.Bookmarks {
+			add(domainmodel.SearchHit{
+				Kind:        domainmodel.SearchHitBookmark,
+				ID:          b.ID,
+				Icon:        b.Icon,
+				DisplayName: b.DisplayName,
+				Url:         b.Url.String(),
+				Host:        b.Url.Host(),
+				Category:    category.DisplayName,
+				IsShelved:   category.IsShelved,
+			})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].rank < ranked[j].rank })
+	if h.Limit > 0 && len(ranked) > h.Limit {
+		ranked = ranked[:h.Limit]
+	}
+
+	hits := make([]domainmodel.SearchHit, 0, len(ranked))
+	for _, r := range ranked {
+		hits = append(hits, r.hit)
+	}
+	return hits, nil
+}
@@ -0,0 +1,182 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func newSearchUserDashboard(
+	dashRepo *repoMock.DashboardRepository,
+	catRepo *repoMock.CategoryRepository,
+	bookmarkRepo *repoMock.BookmarkRepository,
+	appRepo *repoMock.ApplicationRepository,
+	sharedRepo *repoMock.SharedCategoryRepository,
+) *query.SearchUserDashboard {
+	return query.NewSearchUserDashboard(
+		dashRepo,
+		query.NewGetUserCategories(dashRepo, catRepo, bookmarkRepo),
+		query.NewGetUserShelvedCategories(dashRepo, catRepo, bookmarkRepo),
+		query.NewGetUserApplications(query.NewListApplications(appRepo)),
+		query.NewGetSharedCategories(sharedRepo, bookmarkRepo),
+		noReceivedCategories(),
+	)
+}
+
+func noSharedCategories() *repoMock.SharedCategoryRepository {
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{}, nil)
+	return sharedRepo
+}
+
+func TestSearchUserDashboard_Handle_RanksAndFilters(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{{ID: 10, UserID: "user-1", IsDefault: true}}, nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", IsDefault: true}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DisplayName: "Dev"},
+		{ID: 2, DisplayName: "Archive", IsShelved: true},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1}).Return([]domainrepo.BookmarkRecord{
+		{ID: 11, CategoryID: 1, Icon: "mdi:git", DisplayName: "Source", Url: "https://git.lan"},
+		{ID: 12, CategoryID: 1, Icon: "mdi:book", DisplayName: "Docs", Url: "https://docs.lan"},
+	}, nil)
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{2}).Return([]domainrepo.BookmarkRecord{
+		{ID: 21, CategoryID: 2, Icon: "mdi:git", DisplayName: "Gitea (old)", Url: "https://old.lan"},
+	}, nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{
+		{ID: 1, Icon: "mdi:home", DisplayName: "Forgejo", Url: "https://git.example.com", VisibleToGroups: []string{}},
+		{ID: 2, Icon: "mdi:lock", DisplayName: "Git Admin", Url: "https://admin.lan", VisibleToGroups: []string{"admin"}},
+	}, nil)
+
+	h := newSearchUserDashboard(dashRepo, catRepo, bookmarkRepo, appRepo, noSharedCategories())
+	hits, err := h.Handle(context.Background(), "user-1", []string{"users"}, "git")
+
+	require.NoError(t, err)
+	require.Len(t, hits, 3)
+	// name prefix first, then host prefix in dashboard order
+	require.Equal(t, uint(21), hits[0].ID)
+	require.True(t, hits[0].IsShelved)
+	require.Equal(t, "Archive", hits[0].Category)
+	require.Equal(t, domainmodel.SearchHitApplication, hits[1].Kind)
+	require.Equal(t, uint(1), hits[1].ID)
+	require.Equal(t, domainmodel.SearchHitBookmark, hits[2].Kind)
+	require.Equal(t, uint(11), hits[2].ID)
+}
+
+func TestSearchUserDashboard_Handle_SearchesAllDashboards(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").Return([]domainrepo.DashboardRecord{
+		{ID: 10, UserID: "user-1", IsDefault: true},
+		{ID: 11, UserID: "user-1"},
+	}, nil)
+	dashRepo.On("Get", mock.Anything, uint(10)).
+		Return(&domainrepo.DashboardRecord{ID: 10, UserID: "user-1", IsDefault: true}, nil)
+	dashRepo.On("Get", mock.Anything, uint(11)).
+		Return(&domainrepo.DashboardRecord{ID: 11, UserID: "user-1"}, nil)
+
+	catRepo := &repoMock.CategoryRepository{}
+	catRepo.On("ListByDashboardID", mock.Anything, uint(10)).Return([]domainrepo.CategoryRecord{
+		{ID: 1, DisplayName: "Home"},
+	}, nil)
+	catRepo.On("ListByDashboardID", mock.Anything, uint(11)).Return([]domainrepo.CategoryRecord{
+		{ID: 2, DisplayName: "Work"},
+	}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{}).Return([]domainrepo.BookmarkRecord{}, nil)
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{1}).Return([]domainrepo.BookmarkRecord{
+		{ID: 11, CategoryID: 1, Icon: "mdi:movie", DisplayName: "Jellyfin", Url: "https://media.lan"},
+	}, nil)
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{2}).Return([]domainrepo.BookmarkRecord{
+		{ID: 21, CategoryID: 2, Icon: "mdi:git", DisplayName: "Jira", Url: "https://jira.corp"},
+	}, nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
+
+	h := newSearchUserDashboard(dashRepo, catRepo, bookmarkRepo, appRepo, noSharedCategories())
+	hits, err := h.Handle(context.Background(), "user-1", nil, "j")
+
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	require.Equal(t, "Home", hits[0].Category)
+	require.Equal(t, "Work", hits[1].Category)
+}
+
+func TestSearchUserDashboard_Handle_IncludesVisibleSharedCategories(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
+
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return([]domainrepo.ApplicationRecord{}, nil)
+
+	sharedRepo := &repoMock.SharedCategoryRepository{}
+	sharedRepo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{
+		{CategoryID: 5, DisplayName: "Runbooks", PublishedBy: "admin-1"},
+		{CategoryID: 6, DisplayName: "Old runbooks", PublishedBy: "admin-1"},
+	}, nil)
+	sharedRepo.On("ListHidden", mock.Anything, "user-1").Return([]uint{6}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5, 6}).Return([]domainrepo.BookmarkRecord{
+		{ID: 51, CategoryID: 5, Icon: "mdi:book", DisplayName: "Proxmox", Url: "https://pve.lan"},
+		{ID: 61, CategoryID: 6, Icon: "mdi:book", DisplayName: "Proxmox (old)", Url: "https://pve-old.lan"},
+	}, nil)
+
+	h := newSearchUserDashboard(dashRepo, &repoMock.CategoryRepository{}, bookmarkRepo, appRepo, sharedRepo)
+	hits, err := h.Handle(context.Background(), "user-1", nil, "prox")
+
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, uint(51), hits[0].ID)
+	require.Equal(t, "Runbooks", hits[0].Category)
+}
+
+func TestSearchUserDashboard_Handle_Limit(t *testing.T) {
+	dashRepo := &repoMock.DashboardRepository{}
+	dashRepo.On("ListByUserID", mock.Anything, "user-1").
+		Return([]domainrepo.DashboardRecord{}, nil)
+
+	records := make([]domainrepo.ApplicationRecord, 15)
+	for i := range records {
+		records[i] = domainrepo.ApplicationRecord{ID: uint(i + 1), Icon: "mdi:home", DisplayName: "App", Url: "https://app.lan", VisibleToGroups: []string{}}
+	}
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return(records, nil)
+
+	h := newSearchUserDashboard(dashRepo, &repoMock.CategoryRepository{}, &repoMock.BookmarkRepository{}, appRepo, noSharedCategories())
+	hits, err := h.Handle(context.Background(), "user-1", nil, "app")
+
+	require.NoError(t, err)
+	require.Len(t, hits, query.DefaultSearchLimit)
+}
+
+func TestSearchUserDashboard_Handle_RepoError(t *testing.T) {
+	appRepo := &repoMock.ApplicationRepository{}
+	appRepo.On("List", mock.Anything).Return(nil, errors.New("db error"))
+
+	h := newSearchUserDashboard(&repoMock.DashboardRepository{}, &repoMock.CategoryRepository{}, &repoMock.BookmarkRepository{}, appRepo, &repoMock.SharedCategoryRepository{})
+	_, err := h.Handle(context.Background(), "user-1", nil, "app")
+
+	var ie *domainerrors.InternalError
+	require.ErrorAs(t, err, &ie)
+}
@@ -0,0 +1,70 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+// ── ListSharedCategories ───────────────────────────────────────────────────
+
+func TestListSharedCategories_Handle_NotAdmin(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+
+	h := query.NewListSharedCategories(repo)
+	_, err := h.Handle(context.Background(), false)
+
+	var fe *domainerrors.ForbiddenError
+	require.ErrorAs(t, err, &fe)
+	repo.AssertNotCalled(t, "List", mock.Anything)
+}
+
+// ── GetSharedCategories ────────────────────────────────────────────────────
+
+func TestGetSharedCategories_Handle_FiltersAndMarksHidden(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{
+		{CategoryID: 5, DisplayName: "Family docs", VisibleToGroups: []string{"family"}, PublishedBy: "admin-1"},
+		{CategoryID: 6, DisplayName: "Dev runbooks", VisibleToGroups: []string{"dev"}, PublishedBy: "admin-1"},
+		{CategoryID: 7, DisplayName: "Everyone", PublishedBy: "admin-1"},
+		{CategoryID: 8, DisplayName: "Mine", PublishedBy: "user-1"},
+	}, nil)
+	repo.On("ListHidden", mock.Anything, "user-1").Return([]uint{7}, nil)
+
+	bookmarkRepo := &repoMock.BookmarkRepository{}
+	bookmarkRepo.On("ListByCategoryIDs", mock.Anything, []uint{5, 7}).Return([]domainrepo.BookmarkRecord{
+		{ID: 51, CategoryID: 5, Icon: "mdi:book", DisplayName: "Insurance", Url: "https://docs.lan"},
+	}, nil)
+
+	h := query.NewGetSharedCategories(repo, bookmarkRepo)
+	categories, err := h.Handle(context.Background(), "user-1", []string{"family"})
+
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	require.Equal(t, "Family docs", categories[0].DisplayName)
+	require.False(t, categories[0].IsHidden)
+	require.Len(t, categories[0].Bookmarks, 1)
+	require.Equal(t, "Everyone", categories[1].DisplayName)
+	require.True(t, categories[1].IsHidden)
+}
+
+func TestGetSharedCategories_Handle_NoneVisible(t *testing.T) {
+	repo := &repoMock.SharedCategoryRepository{}
+	repo.On("List", mock.Anything).Return([]domainrepo.SharedCategoryRecord{
+		{CategoryID: 6, DisplayName: "Dev runbooks", VisibleToGroups: []string{"dev"}, PublishedBy: "admin-1"},
+	}, nil)
+
+	h := query.NewGetSharedCategories(repo, &repoMock.BookmarkRepository{})
+	categories, err := h.Handle(context.Background(), "user-1", nil)
+
+	require.NoError(t, err)
+	require.Empty(t, categories)
+	repo.AssertNotCalled(t, "ListHidden", mock.Anything, mock.Anything)
+}
@@ -7,11 +7,14 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // UserDataExport is the top-level structure for exported user data.
+// Dashboards, categories, their bookmarks and applications are listed in their
+// manual order; importing appends new items in slice order, which preserves it.
 // Signature (when present) is the HMAC-SHA256 over the canonical JSON of this
 // struct with the Signature field set to "" (omitempty → absent).
 type UserDataExport struct {
@@ -22,13 +25,32 @@ type UserDataExport struct {
 	Themes       []ThemeExport       `json:"themes"`
 	Categories   []CategoryExport    `json:"categories"`
 	Applications []ApplicationExport `json:"applications,omitempty"`
-	Signature    string              `json:"signature,omitempty"`
+	// DashboardName is the name of the default dashboard, whose categories are
+	// listed in Categories, and Dashboards holds the user's other dashboards.
+	// Both are omitted when empty so that exports made before users could have
+	// several dashboards keep verifying against their signature.
+	DashboardName string            `json:"dashboard_name,omitempty"`
+	Dashboards    []DashboardExport `json:"dashboards,omitempty"`
+	// SearchProviders is omitted when empty so that exports made before
+	// search providers existed keep verifying against their signature.
+	SearchProviders []SearchProviderExport `json:"search_providers,omitempty"`
+	// CustomIcons holds the images that "img:<id>" icons of the categories
+	// and applications refer to, keyed by their id on the exporting instance.
+	CustomIcons []CustomIconExport `json:"custom_icons,omitempty"`
+	Signature   string             `json:"signature,omitempty"`
+
+	// Partial marks exports converted from another application's format. They
+	// carry no settings, so importing them leaves the user's settings alone.
+	// It is never serialised and thus cannot be set by an uploaded file.
+	Partial bool `json:"-"`
 }
 
 type SettingsExport struct {
 	Language  string `json:"language"`
 	Timezone  string `json:"timezone"`
 	ThemeName string `json:"theme_name,omitempty"`
+	// SearchProvider is the bang of the default search provider.
+	SearchProvider string `json:"search_provider,omitempty"`
 }
 
 type ThemeExport struct {
@@ -39,11 +61,40 @@ type ThemeExport struct {
 	Tertiary  string `json:"tertiary"`
 }
 
+type SearchProviderExport struct {
+	Hash        string `json:"hash"`
+	Name        string `json:"name"`
+	Bang        string `json:"bang"`
+	URLTemplate string `json:"url_template"`
+}
+
+type CustomIconExport struct {
+	Hash        string `json:"hash"`
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"` // base64 in JSON
+	IsShared    bool   `json:"is_shared,omitempty"`
+}
+
+type DashboardExport struct {
+	Hash       string           `json:"hash"`
+	Name       string           `json:"name"`
+	Categories []CategoryExport `json:"categories"`
+}
+
 type CategoryExport struct {
 	Hash        string           `json:"hash"`
 	DisplayName string           `json:"display_name"`
 	IsShelved   bool             `json:"is_shelved"`
 	Bookmarks   []BookmarkExport `json:"bookmarks"`
+	// Children holds the sub-categories. It is omitted when empty so that
+	// exports made before categories could be nested keep verifying against
+	// their signature.
+	Children []CategoryExport `json:"children,omitempty"`
+	// TagQuery is set for smart categories, which have no bookmarks. It is
+	// omitted when empty for the same reason as Children.
+	TagQuery string `json:"tag_query,omitempty"`
 }
 
 type BookmarkExport struct {
@@ -51,6 +102,9 @@ type BookmarkExport struct {
 	Icon        string `json:"icon"`
 	DisplayName string `json:"display_name"`
 	URL         string `json:"url"`
+	// Tags is omitted when empty so that exports made before bookmarks could
+	// be tagged keep verifying against their signature.
+	Tags []string `json:"tags,omitempty"`
 }
 
 type ApplicationExport struct {
@@ -67,6 +121,35 @@ func ContentHash(parts ...string) string {
 	return hex.EncodeToString(h[:])[:16]
 }
 
+// CategoryHash computes the content hash of a category. Sub-categories include
+// the hash of their parent, so equally named categories in different places of
+// the tree are told apart; top-level categories pass an empty parentHash.
+func CategoryHash(parentHash, displayName string, isShelved bool) string {
+	if parentHash == "" {
+		return ContentHash(displayName, strconv.FormatBool(isShelved))
+	}
+	return ContentHash(parentHash, displayName, strconv.FormatBool(isShelved))
+}
+
+// BookmarkHash computes the content hash of a bookmark. Tags, which must be
+// normalized, only take part when there are any, so untagged bookmarks keep
+// the hash they had before bookmarks could be tagged.
+func BookmarkHash(icon, displayName, url string, tags []string) string {
+	if len(tags) == 0 {
+		return ContentHash(icon, displayName, url)
+	}
+	return ContentHash(icon, displayName, url, strings.Join(tags, ","))
+}
+
+// WalkCategories calls fn for every category of the tree, parents before
+// their sub-categories.
+func WalkCategories(categories []CategoryExport, fn func(c *CategoryExport)) {
+	for i := range categories {
+		fn(&categories[i])
+		WalkCategories(categories[i].Children, fn)
+	}
+}
+
 // ErrInvalidSignature is returned by UnmarshalExport when the checksum does not match.
 var ErrInvalidSignature = errors.New("export signature is invalid")
 
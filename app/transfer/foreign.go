@@ -0,0 +1,430 @@
+package transfer
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ForeignFormat identifies the configuration format of another dashboard
+// application that can be imported.
+type ForeignFormat string
+
+const (
+	// ForeignFlame is Flame's SQLite database, data/db.sqlite.
+	ForeignFlame ForeignFormat = "flame"
+	// ForeignHomer is Homer's config.yml.
+	ForeignHomer ForeignFormat = "homer"
+	// ForeignHomepage is gethomepage's services.yaml or bookmarks.yaml.
+	ForeignHomepage ForeignFormat = "homepage"
+	// ForeignHeimdall is Heimdall's JSON item backup.
+	ForeignHeimdall ForeignFormat = "heimdall"
+)
+
+// Icons given to imported entries whose icon has no Dash equivalent.
+const (
+	ForeignServiceIcon  = "mdi:apps"
+	ForeignBookmarkIcon = "mdi:bookmark"
+)
+
+// foreignServicesCategory names the category for services that are not part
+// of any group in the source configuration.
+const foreignServicesCategory = "Applications"
+
+// ErrUnknownFormat is returned by ParseForeign for data in no supported format.
+var ErrUnknownFormat = errors.New("unsupported import format")
+
+// ErrNothingToImport is returned by ParseForeign when the configuration
+// contains no http(s) links.
+var ErrNothingToImport = errors.New("configuration contains no http(s) links")
+
+// foreignGroup is the common shape every foreign format is reduced to before
+// it is turned into a UserDataExport.
+type foreignGroup struct {
+	Name  string
+	Links []foreignLink
+}
+
+type foreignLink struct {
+	Name string
+	URL  string
+	// Icon is a Dash icon ("type:name") or empty for the default.
+	Icon string
+	// Service marks dashboard tiles as opposed to plain bookmarks.
+	Service bool
+}
+
+// DetectForeignFormat guesses the format of data. It never matches a Dash
+// export, which carries a "version" field.
+func DetectForeignFormat(data []byte) (ForeignFormat, bool) {
+	if isSQLite(data) {
+		return ForeignFlame, true
+	}
+	trimmed := bytes.TrimSpace(data)
+	if json.Valid(trimmed) {
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("[")):
+			return ForeignHeimdall, true
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			var keys map[string]json.RawMessage
+			if err := json.Unmarshal(trimmed, &keys); err != nil {
+				return "", false
+			}
+			if _, ok := keys["version"]; ok {
+				return "", false
+			}
+			if _, ok := keys["items"]; ok {
+				return ForeignHeimdall, true
+			}
+		}
+		return "", false
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return "", false
+	}
+	switch root := doc.Content[0]; root.Kind {
+	case yaml.SequenceNode:
+		return ForeignHomepage, true
+	case yaml.MappingNode:
+		for i := 0; i < len(root.Content); i += 2 {
+			if k := root.Content[i].Value; k == "services" || k == "links" {
+				return ForeignHomer, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ParseForeign converts the configuration of another dashboard into a
+// UserDataExport for ImportUserData. Service entries become applications when
+// servicesAsApplications is set (i.e. the importing user is an admin) and
+// bookmarks otherwise. The result is marked Partial so settings stay as they
+// are.
+func ParseForeign(format ForeignFormat, data []byte, servicesAsApplications bool) (*UserDataExport, error) {
+	var (
+		groups []foreignGroup
+		err    error
+	)
+	switch format {
+	case ForeignFlame:
+		groups, err = parseFlame(data)
+	case ForeignHomer:
+		groups, err = parseHomer(data)
+	case ForeignHomepage:
+		groups, err = parseHomepage(data)
+	case ForeignHeimdall:
+		groups, err = parseHeimdall(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+	export := foreignExport(groups, servicesAsApplications)
+	if len(export.Categories) == 0 && len(export.Applications) == 0 {
+		return nil, ErrNothingToImport
+	}
+	return export, nil
+}
+
+func foreignExport(groups []foreignGroup, servicesAsApplications bool) *UserDataExport {
+	export := &UserDataExport{
+		Version:    1,
+		ExportedAt: time.Now().UTC(),
+		Partial:    true,
+		Themes:     []ThemeExport{},
+		Categories: []CategoryExport{},
+	}
+	catIndex := map[string]int{}
+	seen := map[string]struct{}{}
+
+	for _, g := range groups {
+		for _, l := range g.Links {
+			if !isWebURL(l.URL) {
+				continue
+			}
+			name := strings.TrimSpace(l.Name)
+			if name == "" {
+				name = l.URL
+			}
+
+			if l.Service && servicesAsApplications {
+				icon := l.Icon
+				if icon == "" {
+					icon = ForeignServiceIcon
+				}
+				hash := ContentHash(icon, name, l.URL, "")
+				if _, dup := seen[hash]; dup {
+					continue
+				}
+				seen[hash] = struct{}{}
+				export.Applications = append(export.Applications, ApplicationExport{
+					Hash:            hash,
+					Icon:            icon,
+					DisplayName:     name,
+					URL:             l.URL,
+					VisibleToGroups: []string{},
+				})
+				continue
+			}
+
+			catName := strings.TrimSpace(g.Name)
+			if catName == "" {
+				catName = foreignServicesCategory
+			}
+			i, ok := catIndex[catName]
+			if !ok {
+				i = len(export.Categories)
+				catIndex[catName] = i
+				export.Categories = append(export.Categories, CategoryExport{
+					Hash:        ContentHash(catName, "false"),
+					DisplayName: catName,
+					Bookmarks:   []BookmarkExport{},
+				})
+			}
+			icon := l.Icon
+			if icon == "" {
+				icon = ForeignBookmarkIcon
+			}
+			hash := BookmarkHash(icon, name, l.URL, nil)
+			if _, dup := seen[catName+"\x00"+hash]; dup {
+				continue
+			}
+			seen[catName+"\x00"+hash] = struct{}{}
+			export.Categories[i].Bookmarks = append(export.Categories[i].Bookmarks, BookmarkExport{
+				Hash:        hash,
+				Icon:        icon,
+				DisplayName: name,
+				URL:         l.URL,
+			})
+		}
+	}
+	return export
+}
+
+// foreignIcon maps icon references of other dashboards to Dash icons where an
+// unambiguous equivalent exists: gethomepage's "si-github" and Font Awesome
+// brand icons ("fab fa-github") both name Simple Icons. Everything else
+// (image files, Font Awesome solid icons, MDI names) yields "".
+func foreignIcon(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if name, ok := strings.CutPrefix(raw, "si-"); ok && name != "" {
+		return "spi:" + name
+	}
+	fields := strings.Fields(raw)
+	if len(fields) == 2 && fields[0] == "fab" {
+		if name, ok := strings.CutPrefix(fields[1], "fa-"); ok && name != "" {
+			return "spi:" + name
+		}
+	}
+	return ""
+}
+
+// ── Flame ──────────────────────────────────────────────────────────────────
+
+// parseFlame reads the apps, categories and bookmarks tables of Flame's
+// database in Flame's order. Apps without a category (all of them before
+// Flame 2.3) go to the services category. Icons are Material Design Icons
+// names Dash cannot render and are left out.
+func parseFlame(data []byte) ([]foreignGroup, error) {
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, err
+	}
+	tables := map[string][]sqliteRow{}
+	for _, name := range []string{"apps", "categories", "bookmarks"} {
+		if tables[name], err = db.Table(name); err != nil {
+			return nil, err
+		}
+	}
+	if tables["apps"] == nil && tables["categories"] == nil && tables["bookmarks"] == nil {
+		return nil, errors.New("database has no apps, categories or bookmarks table")
+	}
+
+	groups := []foreignGroup{{Name: foreignServicesCategory}}
+	index := map[int64]int{}
+	for _, c := range sortFlameRows(tables["categories"]) {
+		id, _ := sqliteInt(c, "id")
+		index[id] = len(groups)
+		groups = append(groups, foreignGroup{Name: sqliteText(c, "name")})
+	}
+	for _, a := range sortFlameRows(tables["apps"]) {
+		i := 0
+		if id, ok := sqliteInt(a, "categoryId"); ok {
+			if j, known := index[id]; known {
+				i = j
+			}
+		}
+		groups[i].Links = append(groups[i].Links, foreignLink{Name: sqliteText(a, "name"), URL: sqliteText(a, "url"), Service: true})
+	}
+	for _, b := range sortFlameRows(tables["bookmarks"]) {
+		id, _ := sqliteInt(b, "categoryId")
+		i, known := index[id]
+		if !known {
+			continue
+		}
+		groups[i].Links = append(groups[i].Links, foreignLink{Name: sqliteText(b, "name"), URL: sqliteText(b, "url")})
+	}
+	return groups, nil
+}
+
+// sortFlameRows orders rows like Flame's custom order: by orderId, rows
+// without one last, then by id.
+func sortFlameRows(rows []sqliteRow) []sqliteRow {
+	slices.SortStableFunc(rows, func(a, b sqliteRow) int {
+		ao, aok := sqliteInt(a, "orderId")
+		bo, bok := sqliteInt(b, "orderId")
+		switch {
+		case aok != bok:
+			if aok {
+				return -1
+			}
+			return 1
+		case ao != bo:
+			return cmp.Compare(ao, bo)
+		}
+		ai, _ := sqliteInt(a, "id")
+		bi, _ := sqliteInt(b, "id")
+		return cmp.Compare(ai, bi)
+	})
+	return rows
+}
+
+// ── Homer ──────────────────────────────────────────────────────────────────
+
+type homerItem struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	Icon string `yaml:"icon"`
+}
+
+func parseHomer(data []byte) ([]foreignGroup, error) {
+	var doc struct {
+		Services []struct {
+			Name  string      `yaml:"name"`
+			Items []homerItem `yaml:"items"`
+		} `yaml:"services"`
+		Links []homerItem `yaml:"links"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var groups []foreignGroup
+	for _, s := range doc.Services {
+		g := foreignGroup{Name: s.Name}
+		for _, it := range s.Items {
+			g.Links = append(g.Links, foreignLink{Name: it.Name, URL: it.URL, Icon: foreignIcon(it.Icon), Service: true})
+		}
+		groups = append(groups, g)
+	}
+	links := foreignGroup{Name: "Links"}
+	for _, it := range doc.Links {
+		links.Links = append(links.Links, foreignLink{Name: it.Name, URL: it.URL, Icon: foreignIcon(it.Icon)})
+	}
+	return append(groups, links), nil
+}
+
+// ── gethomepage ────────────────────────────────────────────────────────────
+
+// parseHomepage reads both services.yaml and bookmarks.yaml. Both are lists of
+// single-key maps from group name to a list of single-key maps from entry name
+// to its settings. Services carry a map of settings, bookmarks a list with one
+// map, and a list of further single-key maps is a nested group. yaml.Node is
+// used throughout so that the order of the file is kept.
+func parseHomepage(data []byte) ([]foreignGroup, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, errors.New("expected a list of groups")
+	}
+	var groups []foreignGroup
+	for _, group := range doc.Content[0].Content {
+		eachHomepageEntry(group, func(name string, entries *yaml.Node) {
+			groups = appendHomepageGroup(groups, name, entries)
+		})
+	}
+	return groups, nil
+}
+
+// eachHomepageEntry calls fn for every key of the mapping node m.
+func eachHomepageEntry(m *yaml.Node, fn func(name string, value *yaml.Node)) {
+	if m.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		fn(m.Content[i].Value, m.Content[i+1])
+	}
+}
+
+func appendHomepageGroup(groups []foreignGroup, name string, entries *yaml.Node) []foreignGroup {
+	if entries.Kind != yaml.SequenceNode {
+		return groups
+	}
+
+	type settings struct {
+		Href string `yaml:"href"`
+		Icon string `yaml:"icon"`
+	}
+
+	g := foreignGroup{Name: name}
+	var nested []foreignGroup
+	for _, item := range entries.Content {
+		eachHomepageEntry(item, func(entryName string, value *yaml.Node) {
+			switch value.Kind {
+			case yaml.MappingNode:
+				var s settings
+				if err := value.Decode(&s); err == nil {
+					g.Links = append(g.Links, foreignLink{Name: entryName, URL: s.Href, Icon: foreignIcon(s.Icon), Service: true})
+				}
+			case yaml.SequenceNode:
+				var list []settings
+				if err := value.Decode(&list); err == nil && len(list) == 1 && list[0].Href != "" {
+					g.Links = append(g.Links, foreignLink{Name: entryName, URL: list[0].Href, Icon: foreignIcon(list[0].Icon)})
+					return
+				}
+				nested = appendHomepageGroup(nested, entryName, value)
+			}
+		})
+	}
+	return append(append(groups, g), nested...)
+}
+
+// ── Heimdall ───────────────────────────────────────────────────────────────
+
+func parseHeimdall(data []byte) ([]foreignGroup, error) {
+	type item struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+	}
+	var items []item
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		var doc struct {
+			Items []item `json:"items"`
+		}
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, err
+		}
+		items = doc.Items
+	} else if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, err
+	}
+
+	g := foreignGroup{Name: foreignServicesCategory}
+	for _, it := range items {
+		g.Links = append(g.Links, foreignLink{Name: it.Title, URL: it.URL, Service: true})
+	}
+	return []foreignGroup{g}, nil
+}
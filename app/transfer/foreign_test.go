@@ -0,0 +1,197 @@
+package transfer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const homerSample = `
+title: "Home"
+services:
+  - name: "Media"
+    icon: "fas fa-film"
+    items:
+      - name: "Jellyfin"
+        icon: "fab fa-jellyfin"
+        url: "https://jellyfin.lan"
+      - name: "Broken"
+        url: "not a url"
+links:
+  - name: "Docs"
+    icon: "fas fa-book"
+    url: "https://docs.lan"
+`
+
+const homepageServicesSample = `
+- Media:
+    - Sonarr:
+        href: https://sonarr.lan
+        icon: sonarr.png
+    - Radarr:
+        href: https://radarr.lan
+        icon: si-radarr
+- Infra:
+    - Network:
+        - Router:
+            href: https://router.lan
+`
+
+const homepageBookmarksSample = `
+- Developer:
+    - Github:
+        - abbr: GH
+          href: https://github.com/
+          icon: si-github
+- Social:
+    - Reddit:
+        - abbr: RE
+          href: https://reddit.com/
+`
+
+const heimdallSample = `[
+  {"title": "Nextcloud", "colour": "#0082c9", "url": "https://cloud.lan", "description": null},
+  {"title": "Pi-hole", "url": "https://pihole.lan"}
+]`
+
+func TestDetectForeignFormat(t *testing.T) {
+	cases := map[string]ForeignFormat{
+		homerSample:             ForeignHomer,
+		homepageServicesSample:  ForeignHomepage,
+		homepageBookmarksSample: ForeignHomepage,
+		heimdallSample:          ForeignHeimdall,
+	}
+	for data, want := range cases {
+		got, ok := DetectForeignFormat([]byte(data))
+		require.True(t, ok, want)
+		require.Equal(t, want, got)
+	}
+
+	got, ok := DetectForeignFormat(flameDB(t))
+	require.True(t, ok)
+	require.Equal(t, ForeignFlame, got)
+
+	b, err := MarshalExport(sampleExport())
+	require.NoError(t, err)
+	_, ok = DetectForeignFormat(b)
+	require.False(t, ok, "a Dash export must not be detected as a foreign format")
+}
+
+// flameDB returns testdata/flame.sqlite, a Flame database with 1 KiB pages:
+// two apps without a category, one of them stored before Flame added the
+// categoryId column; the categories Dev (GitHub and a bookmark whose URL
+// spans overflow pages), Reading (150 bookmarks across several b-tree pages)
+// and Media (the app Jellyfin), ordered by orderId rather than id.
+func flameDB(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/flame.sqlite")
+	require.NoError(t, err)
+	return data
+}
+
+func TestParseForeign_Flame(t *testing.T) {
+	export, err := ParseForeign(ForeignFlame, flameDB(t), true)
+	require.NoError(t, err)
+	require.True(t, export.Partial)
+
+	require.Len(t, export.Applications, 3)
+	require.Equal(t, "Grafana", export.Applications[0].DisplayName)
+	require.Equal(t, "Portainer", export.Applications[1].DisplayName)
+	require.Equal(t, "Jellyfin", export.Applications[2].DisplayName)
+	require.Equal(t, ForeignServiceIcon, export.Applications[1].Icon)
+	require.Equal(t, ContentHash(ForeignServiceIcon, "Portainer", "https://portainer.lan", ""), export.Applications[1].Hash)
+
+	require.Len(t, export.Categories, 2)
+	require.Equal(t, "Dev", export.Categories[0].DisplayName)
+	require.Len(t, export.Categories[0].Bookmarks, 2)
+	require.Equal(t, "https://github.com", export.Categories[0].Bookmarks[0].URL)
+	require.Len(t, export.Categories[0].Bookmarks[1].URL, len("https://example.com/?q=")+3000)
+	require.Equal(t, "Reading", export.Categories[1].DisplayName)
+	require.Len(t, export.Categories[1].Bookmarks, 150)
+	require.Equal(t, "Article 000", export.Categories[1].Bookmarks[0].DisplayName)
+	require.Equal(t, "https://blog.example.com/posts/149", export.Categories[1].Bookmarks[149].URL)
+}
+
+func TestParseForeign_FlameCorrupt(t *testing.T) {
+	data := flameDB(t)
+	for _, size := range []int{16, 100, 1024, 5000, len(data) / 2} {
+		_, err := ParseForeign(ForeignFlame, data[:size], true)
+		require.Error(t, err, "truncated to %d bytes", size)
+	}
+
+	garbled := append([]byte(nil), data...)
+	for i := 1024; i < len(garbled); i += 7 {
+		garbled[i] ^= 0x5a
+	}
+	_, err := ParseForeign(ForeignFlame, garbled, true)
+	require.Error(t, err)
+}
+
+func TestParseForeign_ServicesBecomeBookmarksForNonAdmins(t *testing.T) {
+	export, err := ParseForeign(ForeignFlame, flameDB(t), false)
+	require.NoError(t, err)
+
+	require.Empty(t, export.Applications)
+	require.Len(t, export.Categories, 4)
+	require.Equal(t, "Applications", export.Categories[0].DisplayName)
+	require.Equal(t, "Grafana", export.Categories[0].Bookmarks[0].DisplayName)
+	require.Equal(t, ForeignBookmarkIcon, export.Categories[0].Bookmarks[0].Icon)
+	require.Equal(t, "Media", export.Categories[3].DisplayName)
+	require.Equal(t, "Jellyfin", export.Categories[3].Bookmarks[0].DisplayName)
+}
+
+func TestParseForeign_Homer(t *testing.T) {
+	export, err := ParseForeign(ForeignHomer, []byte(homerSample), false)
+	require.NoError(t, err)
+
+	require.Len(t, export.Categories, 2)
+	require.Equal(t, "Media", export.Categories[0].DisplayName)
+	require.Len(t, export.Categories[0].Bookmarks, 1, "invalid URLs are skipped")
+	require.Equal(t, "spi:jellyfin", export.Categories[0].Bookmarks[0].Icon)
+	require.Equal(t, "Links", export.Categories[1].DisplayName)
+	require.Equal(t, ForeignBookmarkIcon, export.Categories[1].Bookmarks[0].Icon)
+}
+
+func TestParseForeign_HomepageServices(t *testing.T) {
+	export, err := ParseForeign(ForeignHomepage, []byte(homepageServicesSample), true)
+	require.NoError(t, err)
+
+	require.Empty(t, export.Categories)
+	require.Len(t, export.Applications, 3)
+	require.Equal(t, "Sonarr", export.Applications[0].DisplayName)
+	require.Equal(t, ForeignServiceIcon, export.Applications[0].Icon)
+	require.Equal(t, "spi:radarr", export.Applications[1].Icon)
+	require.Equal(t, "Router", export.Applications[2].DisplayName)
+}
+
+func TestParseForeign_HomepageBookmarks(t *testing.T) {
+	export, err := ParseForeign(ForeignHomepage, []byte(homepageBookmarksSample), true)
+	require.NoError(t, err)
+
+	require.Empty(t, export.Applications, "bookmarks are never applications")
+	require.Len(t, export.Categories, 2)
+	require.Equal(t, "Developer", export.Categories[0].DisplayName)
+	require.Equal(t, "Github", export.Categories[0].Bookmarks[0].DisplayName)
+	require.Equal(t, "spi:github", export.Categories[0].Bookmarks[0].Icon)
+	require.Equal(t, "Social", export.Categories[1].DisplayName)
+}
+
+func TestParseForeign_Heimdall(t *testing.T) {
+	export, err := ParseForeign(ForeignHeimdall, []byte(heimdallSample), true)
+	require.NoError(t, err)
+
+	require.Len(t, export.Applications, 2)
+	require.Equal(t, "Nextcloud", export.Applications[0].DisplayName)
+	require.Equal(t, "https://pihole.lan", export.Applications[1].URL)
+}
+
+func TestParseForeign_NothingToImport(t *testing.T) {
+	_, err := ParseForeign(ForeignHeimdall, []byte(`[]`), true)
+	require.ErrorIs(t, err, ErrNothingToImport)
+}
+
+func TestParseForeign_UnknownFormat(t *testing.T) {
+	_, err := ParseForeign("unknown", []byte(`{}`), true)
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
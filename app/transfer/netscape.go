@@ -0,0 +1,303 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NetscapeBookmarkIcon is the icon given to bookmarks imported from a browser
+// bookmark file, which carries no icon Dash could use.
+const NetscapeBookmarkIcon = "mdi:bookmark"
+
+// NetscapeUnsortedCategory names the category that collects links which are
+// not inside any folder of the bookmark file.
+const NetscapeUnsortedCategory = "Bookmarks"
+
+const netscapeDoctype = "netscape-bookmark-file-1"
+
+// ErrNoBookmarks is returned by ParseNetscapeBookmarks when the file contains
+// no importable links.
+var ErrNoBookmarks = errors.New("bookmark file contains no http(s) links")
+
+// IsNetscapeBookmarks reports whether data looks like a bookmarks.html file as
+// exported by browsers.
+func IsNetscapeBookmarks(data []byte) bool {
+	head := bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimSpace(head)
+	if len(head) > 256 {
+		head = head[:256]
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte(netscapeDoctype))
+}
+
+// ParseNetscapeBookmarks converts a browser bookmarks.html file into
+// categories. Every folder with links becomes a category named after the
+// folder and nested like the folder, down to domainmodel.MaxCategoryDepth
+// levels; links of deeper folders go to their deepest ancestor that is still
+// a category. Sibling folders sharing a name are merged, and folders without
+// any links in their subtree are left out. Links outside any folder go to
+// NetscapeUnsortedCategory. Only absolute http(s) links are kept. Order follows
+// the file, and hashes match those of a Dash export so imports deduplicate the
+// same way.
+func ParseNetscapeBookmarks(data []byte) ([]CategoryExport, error) {
+	var (
+		z          = html.NewTokenizer(bytes.NewReader(data))
+		root       = newNetscapeFolder(nil, "")
+		levels     []*netscapeLevel // each open <DL>
+		nextFolder string           // title of the last <H3>, applies to the next <DL>
+		links      int
+	)
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				if links == 0 {
+					return nil, ErrNoBookmarks
+				}
+				return root.categories(), nil
+			}
+			return nil, z.Err()
+
+		case html.StartTagToken:
+			tok := z.Token()
+			switch tok.DataAtom {
+			case atom.H3:
+				nextFolder = readText(z, atom.H3)
+			case atom.Dl:
+				level := &netscapeLevel{root: root, name: nextFolder}
+				if n := len(levels); n > 0 {
+					level.parent = levels[n-1]
+				}
+				levels = append(levels, level)
+				nextFolder = ""
+			case atom.A:
+				href := attr(tok, "href")
+				title := readText(z, atom.A)
+				if !isWebURL(href) {
+					continue
+				}
+				if title == "" {
+					title = href
+				}
+
+				var folder *netscapeFolder
+				if n := len(levels); n > 0 {
+					folder = levels[n-1].folder()
+				} else {
+					folder = root.child(NetscapeUnsortedCategory)
+				}
+				tags := netscapeTags(attr(tok, "tags"))
+				hash := BookmarkHash(NetscapeBookmarkIcon, title, href, tags)
+				if _, dup := folder.seen[hash]; dup {
+					continue
+				}
+				folder.seen[hash] = struct{}{}
+				folder.category.Bookmarks = append(folder.category.Bookmarks, BookmarkExport{
+					Hash:        hash,
+					Icon:        NetscapeBookmarkIcon,
+					DisplayName: title,
+					URL:         href,
+					Tags:        tags,
+				})
+				links++
+			}
+
+		case html.EndTagToken:
+			if tok := z.Token(); tok.DataAtom == atom.Dl && len(levels) > 0 {
+				levels = levels[:len(levels)-1]
+			}
+		}
+	}
+}
+
+// netscapeFolder is a category being built from the folders of a bookmark
+// file. The root folder only holds the top-level categories.
+type netscapeFolder struct {
+	category CategoryExport
+	depth    int
+	children []*netscapeFolder
+	byName   map[string]*netscapeFolder
+	seen     map[string]struct{} // hashes of the bookmarks added
+}
+
+func newNetscapeFolder(parent *netscapeFolder, name string) *netscapeFolder {
+	f := &netscapeFolder{byName: map[string]*netscapeFolder{}, seen: map[string]struct{}{}}
+	if parent != nil {
+		f.category = CategoryExport{
+			Hash:        CategoryHash(parent.category.Hash, name, false),
+			DisplayName: name,
+			Bookmarks:   []BookmarkExport{},
+		}
+		f.depth = parent.depth + 1
+	}
+	return f
+}
+
+// child returns the sub-folder called name, creating it on first use.
+func (f *netscapeFolder) child(name string) *netscapeFolder {
+	if c, ok := f.byName[name]; ok {
+		return c
+	}
+	c := newNetscapeFolder(f, name)
+	f.byName[name] = c
+	f.children = append(f.children, c)
+	return c
+}
+
+// categories returns the sub-folders of f as categories.
+func (f *netscapeFolder) categories() []CategoryExport {
+	if len(f.children) == 0 {
+		return nil
+	}
+	cats := make([]CategoryExport, len(f.children))
+	for i, c := range f.children {
+		cats[i] = c.category
+		cats[i].Children = c.categories()
+	}
+	return cats
+}
+
+// netscapeLevel is an open <DL> of a bookmark file. Its folder is only
+// created once a link is found inside, so that empty folders are left out.
+type netscapeLevel struct {
+	root     *netscapeFolder
+	parent   *netscapeLevel
+	name     string // "" for the root list and lists without a heading
+	resolved *netscapeFolder
+}
+
+// folder returns the category that links of the list go to.
+func (l *netscapeLevel) folder() *netscapeFolder {
+	if l.resolved == nil {
+		switch {
+		case l.name == "" && l.parent == nil:
+			l.resolved = l.root.child(NetscapeUnsortedCategory)
+		case l.name == "":
+			l.resolved = l.parent.folder()
+		default:
+			parent := l.root
+			if l.parent != nil {
+				parent = l.parent.container()
+			}
+			if parent.depth < domainmodel.MaxCategoryDepth {
+				l.resolved = parent.child(l.name)
+			} else {
+				l.resolved = parent
+			}
+		}
+	}
+	return l.resolved
+}
+
+// container returns the category that folders inside the list are nested in.
+func (l *netscapeLevel) container() *netscapeFolder {
+	switch {
+	case l.name == "" && l.parent == nil:
+		return l.root
+	case l.name == "":
+		return l.parent.container()
+	default:
+		return l.folder()
+	}
+}
+
+// MarshalNetscapeBookmarks writes categories as a browser bookmarks.html
+// file, one folder per category, nested like the categories.
+// ParseNetscapeBookmarks reads the folders back into the same tree.
+func MarshalNetscapeBookmarks(categories []CategoryExport) []byte {
+	var b bytes.Buffer
+	b.WriteString("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n")
+	b.WriteString("<!-- This is an automatically generated file.\n     It will be read and overwritten.\n     DO NOT EDIT! -->\n")
+	b.WriteString(`<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">` + "\n")
+	b.WriteString("<TITLE>Bookmarks</TITLE>\n<H1>Bookmarks</H1>\n")
+	b.WriteString("<DL><p>\n")
+	for _, c := range categories {
+		writeNetscapeFolder(&b, c, 1)
+	}
+	b.WriteString("</DL><p>\n")
+	return b.Bytes()
+}
+
+func writeNetscapeFolder(b *bytes.Buffer, c CategoryExport, depth int) {
+	// Smart categories only show bookmarks of other categories.
+	if c.TagQuery != "" && len(c.Children) == 0 {
+		return
+	}
+	indent := strings.Repeat("    ", depth)
+	fmt.Fprintf(b, "%s<DT><H3>%s</H3>\n%s<DL><p>\n", indent, html.EscapeString(c.DisplayName), indent)
+	for _, bm := range c.Bookmarks {
+		var tags string
+		if len(bm.Tags) > 0 {
+			tags = fmt.Sprintf(` TAGS="%s"`, html.EscapeString(strings.Join(bm.Tags, ",")))
+		}
+		fmt.Fprintf(b, "%s    <DT><A HREF=\"%s\"%s>%s</A>\n", indent, html.EscapeString(bm.URL), tags, html.EscapeString(bm.DisplayName))
+	}
+	for _, child := range c.Children {
+		writeNetscapeFolder(b, child, depth+1)
+	}
+	fmt.Fprintf(b, "%s</DL><p>\n", indent)
+}
+
+// netscapeTags returns the tags of a comma-separated TAGS attribute as
+// Firefox writes it, normalized; tags Dash does not accept are dropped.
+func netscapeTags(raw string) []string {
+	var tags []string
+	for _, t := range strings.Split(raw, ",") {
+		if tag, err := domainmodel.NormalizeTag(t); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+	if len(tags) > domainmodel.MaxBookmarkTags {
+		tags = tags[:domainmodel.MaxBookmarkTags]
+	}
+	return tags
+}
+
+// readText collects the text up to the closing tag of end and returns it with
+// whitespace collapsed. Entities are already decoded by the tokenizer.
+func readText(z *html.Tokenizer, end atom.Atom) string {
+	var b strings.Builder
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.EndTagToken:
+			if z.Token().DataAtom == end {
+				return strings.Join(strings.Fields(b.String()), " ")
+			}
+		}
+	}
+}
+
+func attr(tok html.Token, name string) string {
+	for _, a := range tok.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func isWebURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
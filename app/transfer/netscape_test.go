@@ -0,0 +1,126 @@
+package transfer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleBookmarksHTML = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file. -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><A HREF="https://example.com/loose" ADD_DATE="1">Loose</A>
+    <DT><H3 ADD_DATE="1" PERSONAL_TOOLBAR_FOLDER="true">Dev</H3>
+    <DL><p>
+        <DT><A HREF="https://github.com">GitHub</A>
+        <DT><A HREF="javascript:alert(1)">Bookmarklet</A>
+        <DT><H3>Docs</H3>
+        <DL><p>
+            <DT><A HREF="https://go.dev/doc/">Go &amp; Docs</A>
+        </DL><p>
+        <DT><A HREF="https://gitlab.com" TAGS="Ops, k8s,bad:tag,ops">GitLab</A>
+        <DT><A HREF="https://github.com">GitHub</A>
+    </DL><p>
+    <DT><H3>Empty</H3>
+    <DL><p>
+    </DL><p>
+    <DT><A HREF="https://example.com/untitled"></A>
+</DL><p>
+`
+
+func TestIsNetscapeBookmarks(t *testing.T) {
+	require.True(t, IsNetscapeBookmarks([]byte(sampleBookmarksHTML)))
+	require.True(t, IsNetscapeBookmarks([]byte("\xef\xbb\xbf\n<!doctype netscape-bookmark-file-1>")))
+	require.False(t, IsNetscapeBookmarks([]byte(`{"version":1}`)))
+}
+
+func TestParseNetscapeBookmarks(t *testing.T) {
+	cats, err := ParseNetscapeBookmarks([]byte(sampleBookmarksHTML))
+	require.NoError(t, err)
+
+	require.Len(t, cats, 2, "empty folders are left out")
+
+	require.Equal(t, NetscapeUnsortedCategory, cats[0].DisplayName)
+	require.Equal(t, ContentHash(NetscapeUnsortedCategory, "false"), cats[0].Hash)
+	require.Len(t, cats[0].Bookmarks, 2)
+	require.Equal(t, "Loose", cats[0].Bookmarks[0].DisplayName)
+	require.Equal(t, "https://example.com/untitled", cats[0].Bookmarks[1].DisplayName)
+
+	require.Equal(t, "Dev", cats[1].DisplayName)
+	require.Len(t, cats[1].Bookmarks, 2, "bookmarklets and duplicates are skipped")
+	require.Equal(t, "GitHub", cats[1].Bookmarks[0].DisplayName)
+	require.Equal(t, "GitLab", cats[1].Bookmarks[1].DisplayName)
+	require.Equal(t, NetscapeBookmarkIcon, cats[1].Bookmarks[0].Icon)
+	require.Equal(t, ContentHash(NetscapeBookmarkIcon, "GitHub", "https://github.com"), cats[1].Bookmarks[0].Hash)
+	require.Empty(t, cats[1].Bookmarks[0].Tags)
+	require.Equal(t, []string{"k8s", "ops"}, cats[1].Bookmarks[1].Tags, "tags are normalized, invalid ones dropped")
+	require.Equal(t, BookmarkHash(NetscapeBookmarkIcon, "GitLab", "https://gitlab.com", []string{"k8s", "ops"}), cats[1].Bookmarks[1].Hash)
+
+	require.Len(t, cats[1].Children, 1)
+	docs := cats[1].Children[0]
+	require.Equal(t, "Docs", docs.DisplayName)
+	require.Equal(t, CategoryHash(cats[1].Hash, "Docs", false), docs.Hash)
+	require.Equal(t, "Go & Docs", docs.Bookmarks[0].DisplayName)
+	require.Equal(t, "https://go.dev/doc/", docs.Bookmarks[0].URL)
+}
+
+func TestParseNetscapeBookmarks_DeepFolders(t *testing.T) {
+	cats, err := ParseNetscapeBookmarks([]byte(`<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+    <DT><H3>Infrastructure</H3>
+    <DL><p>
+        <DT><H3>Proxmox</H3>
+        <DL><p>
+            <DT><H3>Nodes</H3>
+            <DL><p>
+                <DT><A HREF="https://pve-1.lan">pve-1</A>
+                <DT><H3>Disks</H3>
+                <DL><p>
+                    <DT><A HREF="https://pve-1.lan/disks">Disks</A>
+                </DL><p>
+            </DL><p>
+        </DL><p>
+    </DL><p>
+</DL><p>
+`))
+	require.NoError(t, err)
+
+	require.Len(t, cats, 1)
+	require.Empty(t, cats[0].Bookmarks, "folders holding only folders become categories too")
+	proxmox := cats[0].Children[0]
+	require.Equal(t, "Proxmox", proxmox.DisplayName)
+	nodes := proxmox.Children[0]
+	require.Equal(t, "Nodes", nodes.DisplayName)
+	require.Empty(t, nodes.Children, "folders deeper than the category limit are merged into their parent")
+	require.Len(t, nodes.Bookmarks, 2)
+	require.Equal(t, "Disks", nodes.Bookmarks[1].DisplayName)
+}
+
+func TestMarshalNetscapeBookmarks_RoundTrip(t *testing.T) {
+	cats, err := ParseNetscapeBookmarks([]byte(sampleBookmarksHTML))
+	require.NoError(t, err)
+
+	data := MarshalNetscapeBookmarks(cats)
+	require.True(t, IsNetscapeBookmarks(data))
+
+	again, err := ParseNetscapeBookmarks(data)
+	require.NoError(t, err)
+	require.Equal(t, cats, again)
+}
+
+func TestMarshalNetscapeBookmarks_SkipsSmartCategories(t *testing.T) {
+	data := MarshalNetscapeBookmarks([]CategoryExport{
+		{DisplayName: "Kubernetes", TagQuery: "tag:k8s", Bookmarks: []BookmarkExport{}},
+		{DisplayName: "Dev", Bookmarks: []BookmarkExport{{DisplayName: "GitLab", URL: "https://gitlab.com", Tags: []string{"k8s"}}}},
+	})
+	require.NotContains(t, string(data), "Kubernetes")
+	require.Contains(t, string(data), `<A HREF="https://gitlab.com" TAGS="k8s">GitLab</A>`)
+}
+
+func TestParseNetscapeBookmarks_NoLinks(t *testing.T) {
+	_, err := ParseNetscapeBookmarks([]byte("<!DOCTYPE NETSCAPE-Bookmark-file-1><DL><p></DL>"))
+	require.ErrorIs(t, err, ErrNoBookmarks)
+}
@@ -0,0 +1,382 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+)
+
+// sqliteMagic starts every SQLite 3 database file.
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+var errCorruptSQLite = errors.New("corrupt SQLite database")
+
+// sqliteFile reads tables of a SQLite 3 database file held in memory. It
+// implements only what importing another dashboard's database needs: walking
+// table b-trees and decoding their records. Indexes, WAL files and writes are
+// not supported; a database in WAL mode has to be checkpointed first, as
+// copying it with the application stopped does.
+type sqliteFile struct {
+	data     []byte
+	pageSize int
+	usable   int
+}
+
+func isSQLite(data []byte) bool {
+	return bytes.HasPrefix(data, sqliteMagic)
+}
+
+func openSQLite(data []byte) (*sqliteFile, error) {
+	if !isSQLite(data) || len(data) < 100 {
+		return nil, errors.New("not a SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, errCorruptSQLite
+	}
+	usable := pageSize - int(data[20])
+	if usable < 480 {
+		return nil, errCorruptSQLite
+	}
+	return &sqliteFile{data: data, pageSize: pageSize, usable: usable}, nil
+}
+
+// sqliteRow maps column names to int64, float64, string, []byte or nil.
+type sqliteRow map[string]any
+
+// Table returns the rows of the named table in rowid order. It returns nil
+// without an error if the table does not exist.
+func (f *sqliteFile) Table(name string) ([]sqliteRow, error) {
+	var (
+		root    int
+		columns []string
+		rowidAt = -1
+	)
+	// sqlite_schema: type, name, tbl_name, rootpage, sql
+	err := f.walk(1, map[int]bool{}, func(_ int64, values []any) error {
+		if len(values) < 5 || values[0] != "table" {
+			return nil
+		}
+		if n, _ := values[1].(string); !strings.EqualFold(n, name) {
+			return nil
+		}
+		page, _ := values[3].(int64)
+		sql, _ := values[4].(string)
+		root = int(page)
+		columns, rowidAt = sqliteColumns(sql)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if root == 0 {
+		return nil, nil
+	}
+
+	var rows []sqliteRow
+	err = f.walk(root, map[int]bool{}, func(rowid int64, values []any) error {
+		row := make(sqliteRow, len(columns))
+		for i, col := range columns {
+			var v any
+			if i < len(values) {
+				v = values[i]
+			}
+			// An INTEGER PRIMARY KEY is stored as the rowid and as NULL in the record.
+			if i == rowidAt && v == nil {
+				v = rowid
+			}
+			row[col] = v
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	return rows, err
+}
+
+// page returns page n (1-based) and the offset of its b-tree header, which
+// follows the file header on page 1.
+func (f *sqliteFile) page(n int) ([]byte, int, error) {
+	if n < 1 || n > len(f.data)/f.pageSize {
+		return nil, 0, errCorruptSQLite
+	}
+	start := (n - 1) * f.pageSize
+	header := 0
+	if n == 1 {
+		header = 100
+	}
+	return f.data[start : start+f.pageSize], header, nil
+}
+
+// walk calls fn for every record of the table b-tree rooted at page n, in
+// rowid order. visited guards against pages referenced more than once.
+func (f *sqliteFile) walk(n int, visited map[int]bool, fn func(rowid int64, values []any) error) error {
+	if visited[n] {
+		return errCorruptSQLite
+	}
+	visited[n] = true
+	page, h, err := f.page(n)
+	if err != nil {
+		return err
+	}
+	if h+8 > len(page) {
+		return errCorruptSQLite
+	}
+	kind := page[h]
+	cells := int(binary.BigEndian.Uint16(page[h+3 : h+5]))
+
+	switch kind {
+	case 0x0d: // table leaf
+		pointers := h + 8
+		if pointers+2*cells > len(page) {
+			return errCorruptSQLite
+		}
+		for i := range cells {
+			off := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+			rowid, payload, err := f.leafCell(page, off)
+			if err != nil {
+				return err
+			}
+			values, err := sqliteRecord(payload)
+			if err != nil {
+				return err
+			}
+			if err := fn(rowid, values); err != nil {
+				return err
+			}
+		}
+		return nil
+	case 0x05: // table interior
+		pointers := h + 12
+		if pointers+2*cells > len(page) {
+			return errCorruptSQLite
+		}
+		for i := range cells {
+			off := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+			if off+4 > len(page) {
+				return errCorruptSQLite
+			}
+			if err := f.walk(int(binary.BigEndian.Uint32(page[off:])), visited, fn); err != nil {
+				return err
+			}
+		}
+		return f.walk(int(binary.BigEndian.Uint32(page[h+8:])), visited, fn)
+	default:
+		return errCorruptSQLite
+	}
+}
+
+// leafCell decodes the table leaf cell at off and returns its rowid and the
+// full payload, following overflow pages.
+func (f *sqliteFile) leafCell(page []byte, off int) (int64, []byte, error) {
+	if off >= len(page) {
+		return 0, nil, errCorruptSQLite
+	}
+	size, n := sqliteVarint(page[off:])
+	if n == 0 || size < 0 || size > int64(len(f.data)) {
+		return 0, nil, errCorruptSQLite
+	}
+	off += n
+	rowid, n := sqliteVarint(page[off:])
+	if n == 0 {
+		return 0, nil, errCorruptSQLite
+	}
+	off += n
+
+	total := int(size)
+	local := f.localPayload(total)
+	if off+local > len(page) {
+		return 0, nil, errCorruptSQLite
+	}
+	payload := make([]byte, 0, total)
+	payload = append(payload, page[off:off+local]...)
+	if local == total {
+		return rowid, payload, nil
+	}
+
+	if off+local+4 > len(page) {
+		return 0, nil, errCorruptSQLite
+	}
+	next := int(binary.BigEndian.Uint32(page[off+local:]))
+	for hops := 0; len(payload) < total; hops++ {
+		if next == 0 || hops > len(f.data)/f.pageSize {
+			return 0, nil, errCorruptSQLite
+		}
+		overflow, _, err := f.page(next)
+		if err != nil {
+			return 0, nil, err
+		}
+		chunk := min(total-len(payload), f.usable-4)
+		payload = append(payload, overflow[4:4+chunk]...)
+		next = int(binary.BigEndian.Uint32(overflow))
+	}
+	return rowid, payload, nil
+}
+
+// localPayload returns how many bytes of a table leaf payload of the given
+// size are stored on the b-tree page itself, as specified by the file format.
+func (f *sqliteFile) localPayload(size int) int {
+	maxLocal := f.usable - 35
+	if size <= maxLocal {
+		return size
+	}
+	minLocal := (f.usable-12)*32/255 - 23
+	k := minLocal + (size-minLocal)%(f.usable-4)
+	if k <= maxLocal {
+		return k
+	}
+	return minLocal
+}
+
+// sqliteRecord decodes a record into int64, float64, string, []byte or nil
+// values.
+func sqliteRecord(payload []byte) ([]any, error) {
+	headerSize, n := sqliteVarint(payload)
+	if n == 0 || headerSize < int64(n) || headerSize > int64(len(payload)) {
+		return nil, errCorruptSQLite
+	}
+	header := payload[n:headerSize]
+	body := payload[headerSize:]
+
+	var values []any
+	for len(header) > 0 {
+		serial, n := sqliteVarint(header)
+		if n == 0 {
+			return nil, errCorruptSQLite
+		}
+		header = header[n:]
+
+		size := sqliteSerialSize(serial)
+		if size < 0 || size > len(body) {
+			return nil, errCorruptSQLite
+		}
+		raw := body[:size]
+		body = body[size:]
+
+		switch {
+		case serial == 0:
+			values = append(values, nil)
+		case serial <= 6:
+			v := int64(int8(raw[0]))
+			for _, b := range raw[1:] {
+				v = v<<8 | int64(b)
+			}
+			values = append(values, v)
+		case serial == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(raw)))
+		case serial == 8:
+			values = append(values, int64(0))
+		case serial == 9:
+			values = append(values, int64(1))
+		case serial >= 12 && serial%2 == 0:
+			values = append(values, bytes.Clone(raw))
+		case serial >= 13:
+			values = append(values, string(raw))
+		default:
+			return nil, errCorruptSQLite
+		}
+	}
+	return values, nil
+}
+
+// sqliteSerialSize returns the size in bytes of a value of the given serial
+// type, or -1 for reserved types.
+func sqliteSerialSize(serial int64) int {
+	switch {
+	case serial >= 0 && serial <= 4:
+		return int(serial)
+	case serial == 5:
+		return 6
+	case serial == 6 || serial == 7:
+		return 8
+	case serial == 8 || serial == 9:
+		return 0
+	case serial >= 12 && serial <= math.MaxInt32:
+		return int((serial - 12) / 2)
+	default:
+		return -1
+	}
+}
+
+// sqliteVarint decodes a SQLite varint and returns it with its length, 0 if b
+// is too short.
+func sqliteVarint(b []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return int64(v<<8 | uint64(b[i])), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return int64(v), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// sqliteColumns returns the column names of a CREATE TABLE statement and the
+// index of its INTEGER PRIMARY KEY column, -1 if it has none.
+func sqliteColumns(sql string) ([]string, int) {
+	open, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if open < 0 || end <= open {
+		return nil, -1
+	}
+
+	var (
+		defs  []string
+		depth int
+		start = open + 1
+	)
+	for i := start; i < end; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				defs = append(defs, sql[start:i])
+				start = i + 1
+			}
+		}
+	}
+	defs = append(defs, sql[start:end])
+
+	var columns []string
+	rowidAt := -1
+	for _, def := range defs {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			continue
+		}
+		upper := strings.ToUpper(strings.Join(fields[1:], " "))
+		if strings.HasPrefix(upper, "INTEGER") && strings.Contains(upper, "PRIMARY KEY") {
+			rowidAt = len(columns)
+		}
+		columns = append(columns, strings.Trim(fields[0], "`\"[]"))
+	}
+	return columns, rowidAt
+}
+
+// sqliteText returns a TEXT value of a row, "" for other types.
+func sqliteText(row sqliteRow, column string) string {
+	s, _ := row[column].(string)
+	return s
+}
+
+// sqliteInt returns an INTEGER value of a row and whether it is set.
+func sqliteInt(row sqliteRow, column string) (int64, bool) {
+	v, ok := row[column].(int64)
+	return v, ok
+}
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"time"
+
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	"git.at.oechsler.it/samuel/dash/v2/app/query"
 	"git.at.oechsler.it/samuel/dash/v2/app/validation"
@@ -20,6 +22,16 @@ type Repos struct {
 	Session         domainrepo.SessionRepository
 	UserIDMigration domainrepo.UserIDMigrationRepository
 	IdpLink         domainrepo.IdpLinkRepository
+	AccessToken     domainrepo.AccessTokenRepository
+	SearchProvider  domainrepo.SearchProviderRepository
+	Favicon         domainrepo.FaviconRepository
+	CustomIcon      domainrepo.CustomIconRepository
+	LocalAccount    domainrepo.LocalAccountRepository
+	AuditLog        domainrepo.AuditLogRepository
+	LocalGroup      domainrepo.LocalGroupRepository
+	Invitation      domainrepo.InvitationRepository
+	SharedCategory  domainrepo.SharedCategoryRepository
+	CategoryShare   domainrepo.CategoryShareRepository
 }
 
 // UseCases bundles all use cases exposed to the delivery layer.
@@ -28,6 +40,7 @@ type UseCases struct {
 	// Queries
 	ExportUserData           query.UserDataExporter
 	GetUserDashboard         query.UserDashboardGetter
+	SearchUserDashboard      query.UserDashboardSearcher
 	GetUserSettings          query.UserSettingsGetter
 	GetUserThemeByID         query.UserThemeByIDGetter
 	GetUserApplications      query.UserApplicationsGetter
@@ -38,6 +51,7 @@ type UseCases struct {
 	GetUserShelvedCategories query.UserShelvedCategoriesGetter
 	GetUserCategory          query.UserCategoryGetter
 	GetUserBookmark          query.UserBookmarkGetter
+	ListUserTags             query.UserTagsLister
 	ListUserThemes           query.UserThemesLister
 	// Session use cases
 	GetSessionsOverview query.UserSessionsOverviewGetter
@@ -48,26 +62,121 @@ type UseCases struct {
 	InvalidateSession   command.SessionInvalidator
 	TerminateSession    command.SessionTerminator
 	CleanupSessions     command.SessionCleaner
+	EndIdpSession       command.IdpSessionEnder
 	MigrateUserID       command.UserIDMigrator
 	ResolveOrCreateUser command.UserResolver
+	// Linked account use cases
+	ListIdpLinks      query.IdpLinksLister
+	LinkIdpIdentity   command.IdpIdentityLinker
+	UnlinkIdpIdentity command.IdpIdentityUnlinker
+	// Local account use cases
+	ListLocalAccounts       query.LocalAccountsLister
+	CreateLocalAccount      command.LocalAccountCreator
+	SetLocalAccountPassword command.LocalAccountPasswordSetter
+	DeleteLocalAccount      command.LocalAccountDeleter
+	// User management use cases
+	ListUsers          query.UsersLister
+	RevokeUserSessions command.UserSessionsRevoker
+	DeleteUser         command.UserDeleter
+	// Local group use cases
+	ListLocalGroups        query.LocalGroupsLister
+	ResolveLocalGroups     query.LocalGroupsResolver
+	CreateLocalGroup       command.LocalGroupCreator
+	UpdateLocalGroup       command.LocalGroupUpdater
+	DeleteLocalGroup       command.LocalGroupDeleter
+	AddLocalGroupMember    command.LocalGroupMemberAdder
+	RemoveLocalGroupMember command.LocalGroupMemberRemover
+	// Invitation use cases
+	ListInvitations            query.InvitationsLister
+	GetInvitation              query.InvitationGetter
+	CreateInvitation           command.InvitationCreator
+	RedeemInvitation           command.InvitationRedeemer
+	DeleteInvitation           command.InvitationDeleter
+	RevokeInvitationRedemption command.InvitationRedemptionRevoker
+	// Shared category use cases
+	GetSharedCategories  query.SharedCategoriesGetter
+	ListSharedCategories query.SharedCategoriesLister
+	PublishCategory      command.CategoryPublisher
+	UnpublishCategory    command.CategoryUnpublisher
+	HideSharedCategory   command.SharedCategoryHider
+	ShowSharedCategory   command.SharedCategoryShower
+	// Category share use cases
+	GetReceivedCategories query.ReceivedCategoriesGetter
+	ListCategoryShares    query.CategorySharesLister
+	ShareCategory         command.CategorySharer
+	RevokeCategoryShare   command.CategoryShareRevoker
+	LeaveCategoryShare    command.CategoryShareLeaver
+	// Dashboard use cases
+	ListUserDashboards    query.UserDashboardsLister
+	CreateUserDashboard   command.UserDashboardCreator
+	UpdateUserDashboard   command.UserDashboardUpdater
+	DeleteUserDashboard   command.UserDashboardDeleter
+	ReorderUserDashboards command.UserDashboardsReorderer
+	// Audit log use cases
+	ListAuditLog    query.AuditLogLister
+	RecordAudit     command.AuditRecorder
+	CleanupAuditLog command.AuditLogCleaner
+	// Access token use cases
+	ListAccessTokens  query.AccessTokensLister
+	CreateAccessToken command.AccessTokenCreator
+	RevokeAccessToken command.AccessTokenRevoker
+	// Search provider use cases
+	ListUserSearchProviders  query.UserSearchProvidersLister
+	ResolveUserWebSearch     query.UserWebSearchResolver
+	CreateUserSearchProvider command.UserSearchProviderCreator
+	DeleteUserSearchProvider command.UserSearchProviderDeleter
+	// Favicon use cases
+	GetFavicon      query.FaviconGetter
+	CleanupFavicons command.FaviconsCleaner
+	// Custom icon use cases
+	ListCustomIcons  query.CustomIconsLister
+	GetCustomIcon    query.CustomIconGetter
+	UploadCustomIcon command.CustomIconUploader
+	DeleteCustomIcon command.CustomIconDeleter
 	// Commands
-	DeleteUserData     command.UserDataDeleter
-	ImportUserData     command.UserDataImporter
-	UpdateUserSettings command.UserSettingsUpdater
-	CreateUserTheme    command.UserThemeCreator
-	DeleteUserTheme    command.UserThemeDeleter
-	CreateApplication  command.ApplicationCreator
-	UpdateApplication  command.ApplicationUpdater
-	DeleteApplication  command.ApplicationDeleter
-	CreateUserCategory command.UserCategoryCreator
-	UpdateUserCategory command.UserCategoryUpdater
-	DeleteUserCategory command.UserCategoryDeleter
-	CreateUserBookmark command.UserBookmarkCreator
-	UpdateUserBookmark command.UserBookmarkUpdater
-	DeleteUserBookmark command.UserBookmarkDeleter
+	DeleteUserData        command.UserDataDeleter
+	ImportUserData        command.UserDataImporter
+	ImportUserBookmarks   command.UserBookmarksImporter
+	UpdateUserSettings    command.UserSettingsUpdater
+	CreateUserTheme       command.UserThemeCreator
+	DeleteUserTheme       command.UserThemeDeleter
+	CreateApplication     command.ApplicationCreator
+	UpdateApplication     command.ApplicationUpdater
+	DeleteApplication     command.ApplicationDeleter
+	ReorderApplications   command.ApplicationsReorderer
+	CreateUserCategory    command.UserCategoryCreator
+	UpdateUserCategory    command.UserCategoryUpdater
+	DeleteUserCategory    command.UserCategoryDeleter
+	ReorderUserCategories command.UserCategoriesReorderer
+	CreateUserBookmark    command.UserBookmarkCreator
+	UpdateUserBookmark    command.UserBookmarkUpdater
+	DeleteUserBookmark    command.UserBookmarkDeleter
+	ReorderUserBookmarks  command.UserBookmarksReorderer
+}
+
+// FaviconOptions configures the favicon use cases. The fetcher talks to the
+// web, so it is provided by the caller like the other infra adapters.
+type FaviconOptions struct {
+	Fetcher    query.FaviconFetcher
+	CacheTTL   time.Duration
+	MaxEntries int // 0 = no limit
+}
+
+// LocalAccountOptions configures the local account use cases.
+type LocalAccountOptions struct {
+	Issuer string // issuer recorded for local account sessions
 }
 
-func NewUseCases(repos Repos, v validation.Validator) *UseCases {
+// AuditOptions configures the audit log use cases.
+type AuditOptions struct {
+	Retention time.Duration // 0 = keep entries forever
+}
+
+// NewUseCases wires the use cases. iconProcessor prepares uploaded and
+// imported images for the icon library.
+func NewUseCases(repos Repos, v validation.Validator, favicon FaviconOptions, iconProcessor command.CustomIconProcessor, local LocalAccountOptions, audit AuditOptions) *UseCases {
+	recordAudit := command.NewRecordAudit(repos.AuditLog)
+
 	listApplications := query.NewListApplications(repos.Application)
 	getUserApplications := query.NewGetUserApplications(listApplications)
 	getApplication := query.NewGetApplication(repos.Application)
@@ -76,9 +185,12 @@ func NewUseCases(repos Repos, v validation.Validator) *UseCases {
 	getUserCategory := query.NewGetUserCategory(repos.Dashboard, repos.Category)
 	getUserShelvedCategories := query.NewGetUserShelvedCategories(repos.Dashboard, repos.Category, repos.Bookmark)
 
-	getUserBookmark := query.NewGetUserBookmark(repos.Dashboard, repos.Bookmark, repos.Category)
+	getUserBookmark := query.NewGetUserBookmark(repos.Dashboard, repos.Bookmark, repos.Category, repos.CategoryShare)
 
-	getUserDashboard := query.NewGetUserDashboard(repos.Dashboard, getUserCategories, getUserApplications)
+	getSharedCategories := query.NewGetSharedCategories(repos.SharedCategory, repos.Bookmark)
+	getReceivedCategories := query.NewGetReceivedCategories(repos.CategoryShare, repos.Bookmark, repos.Session)
+	getUserDashboard := query.NewGetUserDashboard(repos.Dashboard, getUserCategories, getUserApplications, getSharedCategories, getReceivedCategories)
+	searchUserDashboard := query.NewSearchUserDashboard(repos.Dashboard, getUserCategories, getUserShelvedCategories, getUserApplications, getSharedCategories, getReceivedCategories)
 
 	listUserThemes := query.NewListUserThemes(repos.Theme)
 	getUserThemeByID := query.NewGetUserThemeByID(repos.Theme)
@@ -87,35 +199,97 @@ func NewUseCases(repos Repos, v validation.Validator) *UseCases {
 	getUserSettings := query.NewGetUserSettings(repos.Setting, repos.Theme)
 
 	getSessionsOverview := query.NewGetSessionsOverview(repos.Session)
-	createSession := command.NewCreateSession(repos.Session)
+	createSession := command.NewCreateSession(repos.Session, recordAudit)
 	refreshSession := command.NewRefreshSession(repos.Session)
-	pinSession := command.NewPinSession(repos.Session)
-	unpinSession := command.NewUnpinSession(repos.Session)
-	invalidateSession := command.NewInvalidateSession(repos.Session)
+	pinSession := command.NewPinSession(repos.Session, recordAudit)
+	unpinSession := command.NewUnpinSession(repos.Session, recordAudit)
+	invalidateSession := command.NewInvalidateSession(repos.Session, recordAudit)
 	terminateSession := command.NewTerminateSession(repos.Session)
 	cleanupSessions := command.NewCleanupSessions(repos.Session)
+	endIdpSession := command.NewEndIdpSession(repos.Session)
 	migrateUserID := command.NewMigrateUserID(repos.UserIDMigration)
 	resolveOrCreateUser := command.NewResolveOrCreateUser(repos.IdpLink)
 
-	exportUserData := query.NewExportUserData(repos.Dashboard, repos.Category, repos.Bookmark, repos.Theme, repos.Setting, repos.Application)
-	deleteUserData := command.NewDeleteUserData(repos.User)
-	importUserData := command.NewImportUserData(repos.Dashboard, repos.Category, repos.Bookmark, repos.Theme, repos.Setting, repos.Application)
+	listUserSearchProviders := query.NewListUserSearchProviders(repos.SearchProvider, repos.Setting)
+
+	exportUserData := query.NewExportUserData(repos.Dashboard, repos.Category, repos.Bookmark, repos.Theme, repos.Setting, repos.Application, repos.SearchProvider, repos.CustomIcon)
+	deleteUserData := command.NewDeleteUserData(repos.User, recordAudit)
+	importUserData := command.NewImportUserData(repos.Dashboard, repos.Category, repos.Bookmark, repos.Theme, repos.Setting, repos.Application, repos.SearchProvider, repos.CustomIcon, iconProcessor, recordAudit)
 
 	return &UseCases{
-		GetSessionsOverview:      getSessionsOverview,
-		CreateSession:            createSession,
-		RefreshSession:           refreshSession,
-		PinSession:               pinSession,
-		UnpinSession:             unpinSession,
-		InvalidateSession:        invalidateSession,
-		TerminateSession:         terminateSession,
-		CleanupSessions:          cleanupSessions,
-		MigrateUserID:            migrateUserID,
-		ResolveOrCreateUser:      resolveOrCreateUser,
+		GetSessionsOverview:        getSessionsOverview,
+		CreateSession:              createSession,
+		RefreshSession:             refreshSession,
+		PinSession:                 pinSession,
+		UnpinSession:               unpinSession,
+		InvalidateSession:          invalidateSession,
+		TerminateSession:           terminateSession,
+		CleanupSessions:            cleanupSessions,
+		EndIdpSession:              endIdpSession,
+		MigrateUserID:              migrateUserID,
+		ResolveOrCreateUser:        resolveOrCreateUser,
+		ListIdpLinks:               query.NewListIdpLinks(repos.IdpLink),
+		LinkIdpIdentity:            command.NewLinkIdpIdentity(repos.IdpLink),
+		UnlinkIdpIdentity:          command.NewUnlinkIdpIdentity(repos.IdpLink, repos.Session),
+		ListLocalAccounts:          query.NewListLocalAccounts(repos.LocalAccount),
+		CreateLocalAccount:         command.NewCreateLocalAccount(repos.LocalAccount, v),
+		SetLocalAccountPassword:    command.NewSetLocalAccountPassword(repos.LocalAccount, repos.Session, v, local.Issuer),
+		DeleteLocalAccount:         command.NewDeleteLocalAccount(repos.LocalAccount, repos.Session, local.Issuer),
+		ListUsers:                  query.NewListUsers(repos.User, repos.IdpLink, repos.Session),
+		RevokeUserSessions:         command.NewRevokeUserSessions(repos.Session, repos.AccessToken, recordAudit),
+		DeleteUser:                 command.NewDeleteUser(deleteUserData),
+		ListLocalGroups:            query.NewListLocalGroups(repos.LocalGroup),
+		ResolveLocalGroups:         query.NewResolveLocalGroups(repos.LocalGroup, repos.Invitation),
+		CreateLocalGroup:           command.NewCreateLocalGroup(repos.LocalGroup, v),
+		UpdateLocalGroup:           command.NewUpdateLocalGroup(repos.LocalGroup, v),
+		DeleteLocalGroup:           command.NewDeleteLocalGroup(repos.LocalGroup),
+		AddLocalGroupMember:        command.NewAddLocalGroupMember(repos.LocalGroup),
+		RemoveLocalGroupMember:     command.NewRemoveLocalGroupMember(repos.LocalGroup),
+		ListInvitations:            query.NewListInvitations(repos.Invitation),
+		GetInvitation:              query.NewGetInvitation(repos.Invitation),
+		CreateInvitation:           command.NewCreateInvitation(repos.Invitation, v, recordAudit),
+		RedeemInvitation:           command.NewRedeemInvitation(repos.Invitation, recordAudit),
+		DeleteInvitation:           command.NewDeleteInvitation(repos.Invitation, recordAudit),
+		RevokeInvitationRedemption: command.NewRevokeInvitationRedemption(repos.Invitation, recordAudit),
+		GetSharedCategories:        getSharedCategories,
+		ListSharedCategories:       query.NewListSharedCategories(repos.SharedCategory),
+		PublishCategory:            command.NewPublishCategory(repos.Dashboard, repos.Category, repos.SharedCategory, v, recordAudit),
+		UnpublishCategory:          command.NewUnpublishCategory(repos.SharedCategory, recordAudit),
+		HideSharedCategory:         command.NewHideSharedCategory(repos.SharedCategory),
+		ShowSharedCategory:         command.NewShowSharedCategory(repos.SharedCategory),
+		GetReceivedCategories:      getReceivedCategories,
+		ListCategoryShares:         query.NewListCategoryShares(repos.Dashboard, repos.Category, repos.CategoryShare, repos.Session),
+		ShareCategory:              command.NewShareCategory(repos.Dashboard, repos.Category, repos.CategoryShare, repos.User, repos.Session, v, recordAudit),
+		RevokeCategoryShare:        command.NewRevokeCategoryShare(repos.Dashboard, repos.Category, repos.CategoryShare, recordAudit),
+		LeaveCategoryShare:         command.NewLeaveCategoryShare(repos.CategoryShare),
+		ListUserDashboards:         query.NewListUserDashboards(repos.Dashboard),
+		CreateUserDashboard:        command.NewCreateUserDashboard(repos.Dashboard, v),
+		UpdateUserDashboard:        command.NewUpdateUserDashboard(repos.Dashboard, v),
+		DeleteUserDashboard:        command.NewDeleteUserDashboard(repos.Dashboard),
+		ReorderUserDashboards:      command.NewReorderUserDashboards(repos.Dashboard, v),
+		ListAuditLog:               query.NewListAuditLog(repos.AuditLog),
+		RecordAudit:                recordAudit,
+		CleanupAuditLog:            command.NewCleanupAuditLog(repos.AuditLog, audit.Retention),
+		ListAccessTokens:           query.NewListAccessTokens(repos.AccessToken),
+		CreateAccessToken:          command.NewCreateAccessToken(repos.AccessToken, repos.Session, v),
+		RevokeAccessToken:          command.NewRevokeAccessToken(repos.AccessToken),
+		ListUserSearchProviders:    listUserSearchProviders,
+		ResolveUserWebSearch:       query.NewResolveUserWebSearch(listUserSearchProviders),
+		CreateUserSearchProvider:   command.NewCreateUserSearchProvider(repos.SearchProvider, repos.Setting, v),
+		DeleteUserSearchProvider:   command.NewDeleteUserSearchProvider(repos.SearchProvider),
+		GetFavicon:                 query.NewGetFavicon(repos.Favicon, favicon.Fetcher, favicon.CacheTTL, favicon.MaxEntries),
+		// Favicons nobody looked at for twice the TTL belong to removed bookmarks.
+		CleanupFavicons:          command.NewCleanupFavicons(repos.Favicon, 2*favicon.CacheTTL),
+		ListCustomIcons:          query.NewListCustomIcons(repos.CustomIcon),
+		GetCustomIcon:            query.NewGetCustomIcon(repos.CustomIcon),
+		UploadCustomIcon:         command.NewUploadCustomIcon(repos.CustomIcon, iconProcessor, v),
+		DeleteCustomIcon:         command.NewDeleteCustomIcon(repos.CustomIcon),
 		ExportUserData:           exportUserData,
 		DeleteUserData:           deleteUserData,
 		ImportUserData:           importUserData,
+		ImportUserBookmarks:      command.NewImportUserBookmarks(repos.Dashboard, repos.Category, repos.Bookmark, recordAudit),
 		GetUserDashboard:         getUserDashboard,
+		SearchUserDashboard:      searchUserDashboard,
 		GetUserSettings:          getUserSettings,
 		GetUserThemeByID:         getUserThemeByID,
 		GetUserApplications:      getUserApplications,
@@ -126,18 +300,22 @@ func NewUseCases(repos Repos, v validation.Validator) *UseCases {
 		GetUserShelvedCategories: getUserShelvedCategories,
 		GetUserCategory:          getUserCategory,
 		GetUserBookmark:          getUserBookmark,
+		ListUserTags:             query.NewListUserTags(repos.Dashboard, repos.Category, repos.Bookmark),
 		ListUserThemes:           listUserThemes,
-		UpdateUserSettings:       command.NewUpdateUserSettings(repos.Setting, repos.Theme, v),
+		UpdateUserSettings:       command.NewUpdateUserSettings(repos.Setting, repos.Theme, repos.SearchProvider, v),
 		CreateUserTheme:          command.NewCreateUserTheme(repos.Theme, v),
 		DeleteUserTheme:          command.NewDeleteUserTheme(repos.Theme, repos.Setting),
-		CreateApplication:        command.NewCreateApplication(repos.Application, v),
+		CreateApplication:        command.NewCreateApplication(repos.Application, v, recordAudit),
 		UpdateApplication:        command.NewUpdateApplication(repos.Application, v),
-		DeleteApplication:        command.NewDeleteApplication(repos.Application),
-		CreateUserCategory:       command.NewCreateUserCategory(repos.Dashboard, repos.Category, v),
-		UpdateUserCategory:       command.NewUpdateUserCategory(repos.Dashboard, repos.Category, v),
+		DeleteApplication:        command.NewDeleteApplication(repos.Application, recordAudit),
+		ReorderApplications:      command.NewReorderApplications(repos.Application, v),
+		CreateUserCategory:       command.NewCreateUserCategory(repos.Dashboard, repos.Category, repos.CategoryShare, repos.SharedCategory, v),
+		UpdateUserCategory:       command.NewUpdateUserCategory(repos.Dashboard, repos.Category, repos.Bookmark, repos.CategoryShare, repos.SharedCategory, v),
 		DeleteUserCategory:       command.NewDeleteUserCategory(repos.Dashboard, repos.Category),
-		CreateUserBookmark:       command.NewCreateUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark, v),
-		UpdateUserBookmark:       command.NewUpdateUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark, v),
-		DeleteUserBookmark:       command.NewDeleteUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark),
+		ReorderUserCategories:    command.NewReorderUserCategories(repos.Dashboard, repos.Category, v),
+		CreateUserBookmark:       command.NewCreateUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark, repos.CategoryShare, v),
+		UpdateUserBookmark:       command.NewUpdateUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark, repos.CategoryShare, v),
+		DeleteUserBookmark:       command.NewDeleteUserBookmark(repos.Dashboard, repos.Category, repos.Bookmark, repos.CategoryShare),
+		ReorderUserBookmarks:     command.NewReorderUserBookmarks(repos.Dashboard, repos.Category, repos.Bookmark, repos.CategoryShare, v),
 	}
 }
@@ -9,10 +9,17 @@ import (
 	"time"
 
 	"git.at.oechsler.it/samuel/dash/v2/app"
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	"git.at.oechsler.it/samuel/dash/v2/app/validation"
 	"git.at.oechsler.it/samuel/dash/v2/config"
 	"git.at.oechsler.it/samuel/dash/v2/delivery/web/handler"
 	webi18n "git.at.oechsler.it/samuel/dash/v2/delivery/web/i18n"
+	"git.at.oechsler.it/samuel/dash/v2/infra/accesstoken"
+	"git.at.oechsler.it/samuel/dash/v2/infra/customicon"
+	"git.at.oechsler.it/samuel/dash/v2/infra/favicon"
+	"git.at.oechsler.it/samuel/dash/v2/infra/forwardauth"
+	"git.at.oechsler.it/samuel/dash/v2/infra/health"
+	"git.at.oechsler.it/samuel/dash/v2/infra/localauth"
 	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
 	"git.at.oechsler.it/samuel/dash/v2/infra/persistence"
 
@@ -48,14 +55,9 @@ func main() {
 		log.Fatalf("failed to initialize repositories: %v", err)
 	}
 
-	oidcProvider, err := oidc.NewProvider(context.Background(), &cfg.OIDC)
+	oidcProviders, err := oidc.NewProviders(context.Background(), &cfg.OIDC)
 	if err != nil {
-		log.Fatalf("failed to initialize OIDC provider: %v", err)
-	}
-
-	sessionStore, err := oidc.NewSessionStore(&cfg.OIDC.Cookie, repos.Session)
-	if err != nil {
-		log.Fatalf("failed to initialize session store: %v", err)
+		log.Fatalf("failed to initialize OIDC providers: %v", err)
 	}
 
 	uc := app.NewUseCases(app.Repos{
@@ -69,18 +71,74 @@ func main() {
 		Session:         repos.Session,
 		UserIDMigration: repos.UserIDMigration,
 		IdpLink:         repos.IdpLink,
-	}, validation.New())
+		AccessToken:     repos.AccessToken,
+		SearchProvider:  repos.SearchProvider,
+		Favicon:         repos.Favicon,
+		CustomIcon:      repos.CustomIcon,
+		LocalAccount:    repos.LocalAccount,
+		AuditLog:        repos.AuditLog,
+		LocalGroup:      repos.LocalGroup,
+		Invitation:      repos.Invitation,
+		SharedCategory:  repos.SharedCategory,
+		CategoryShare:   repos.CategoryShare,
+	}, validation.New(), app.FaviconOptions{
+		Fetcher:    favicon.NewFetcher(cfg.Favicon.Timeout, cfg.Favicon.AllowPrivateNetworks),
+		CacheTTL:   cfg.Favicon.CacheTTL,
+		MaxEntries: cfg.Favicon.MaxEntries,
+	}, customicon.NewProcessor(), app.LocalAccountOptions{
+		Issuer: cfg.LocalAuth.Issuer,
+	}, app.AuditOptions{
+		Retention: cfg.Audit.Retention,
+	})
+
+	sessionStore, err := oidc.NewSessionStore(&cfg.OIDC.Cookie, repos.Session, uc.ResolveLocalGroups)
+	if err != nil {
+		log.Fatalf("failed to initialize session store: %v", err)
+	}
 
 	fiberApp := web.NewFiberApp(&cfg.App)
 	web.RegisterStaticFiles(fiberApp)
-	handler.RegisterAll(fiberApp, sessionStore, oidcProvider, uc, handler.BuildInfo{
+	tokenLoader := accesstoken.NewLoader(repos.AccessToken, repos.Session, uc.ResolveLocalGroups)
+
+	var forwardAuth *handler.ForwardAuth
+	if cfg.ForwardAuth.Enabled {
+		loader, err := forwardauth.NewLoader(cfg.ForwardAuth, sessionStore, uc.ResolveOrCreateUser, uc.RecordAudit)
+		if err != nil {
+			log.Fatalf("failed to initialize forward auth: %v", err)
+		}
+		forwardAuth = &handler.ForwardAuth{
+			Loader:    loader,
+			Issuer:    cfg.ForwardAuth.Issuer,
+			LoginURL:  cfg.ForwardAuth.LoginURL,
+			LogoutURL: cfg.ForwardAuth.LogoutURL,
+		}
+	}
+
+	var localAuth *handler.LocalAuth
+	if cfg.LocalAuth.Enabled {
+		authenticator, err := localauth.NewAuthenticator(cfg.LocalAuth, repos.LocalAccount)
+		if err != nil {
+			log.Fatalf("failed to initialize local accounts: %v", err)
+		}
+		if err := authenticator.Sync(context.Background()); err != nil {
+			log.Fatalf("failed to initialize local accounts: %v", err)
+		}
+		localAuth = &handler.LocalAuth{
+			Authenticator: authenticator,
+			Issuer:        cfg.LocalAuth.Issuer,
+			SessionTTL:    cfg.LocalAuth.SessionTTL,
+		}
+	}
+
+	handler.RegisterAll(fiberApp, sessionStore, oidcProviders, tokenLoader, forwardAuth, localAuth, uc, handler.BuildInfo{
 		Version:   version,
 		Commit:    commit,
 		BuildDate: buildDate,
 		RepoURL:   repoURL,
 	})
 
-	// Periodically delete expired sessions that are no longer pinned.
+	// Periodically delete expired sessions that are no longer pinned,
+	// favicons that are no longer shown and audit entries past retention.
 	go func() {
 		ticker := time.NewTicker(1 * time.Hour)
 		defer ticker.Stop()
@@ -88,10 +146,52 @@ func main() {
 			if err := uc.CleanupSessions.Handle(context.Background()); err != nil {
 				log.Printf("session cleanup error: %v", err)
 			}
+			if err := uc.CleanupFavicons.Handle(context.Background()); err != nil {
+				log.Printf("favicon cleanup error: %v", err)
+			}
+			if err := uc.CleanupAuditLog.Handle(context.Background()); err != nil {
+				log.Printf("audit log cleanup error: %v", err)
+			}
 			<-ticker.C
 		}
 	}()
 
+	// Refresh sessions signed in with a refresh token before their token
+	// expires, so group changes at the IdP reach Dash without a new sign-in.
+	if len(oidcProviders.List()) > 0 {
+		refresher := oidc.NewRefresher(oidcProviders, sessionStore)
+		go func() {
+			ticker := time.NewTicker(1 * time.Minute)
+			defer ticker.Stop()
+			for {
+				if err := refresher.RefreshDue(context.Background()); err != nil {
+					log.Printf("session refresh error: %v", err)
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
+	// Periodically probe applications that have a health check configured.
+	if cfg.Health.Enabled {
+		prober := command.NewProbeApplications(
+			repos.Application,
+			health.NewProber(cfg.Health.InsecureSkipVerify),
+			cfg.Health.Concurrency,
+			cfg.Health.Timeout,
+		)
+		go func() {
+			ticker := time.NewTicker(cfg.Health.Interval)
+			defer ticker.Stop()
+			for {
+				if err := prober.Handle(context.Background()); err != nil {
+					log.Printf("application health check error: %v", err)
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
 	interruptCtx, cancel := signal.NotifyContext(
 		context.Background(),
 		os.Interrupt,
@@ -1,9 +1,19 @@
 package config
 
+import (
+	"strings"
+	"time"
+)
+
 type Config struct {
-	App      AppConfig      `yaml:"app"`
-	Database DatabaseConfig `yaml:"database"`
-	OIDC     OIDCConfig     `yaml:"oidc"`
+	App         AppConfig         `yaml:"app"`
+	Database    DatabaseConfig    `yaml:"database"`
+	OIDC        OIDCConfig        `yaml:"oidc"`
+	ForwardAuth ForwardAuthConfig `yaml:"forward_auth"`
+	LocalAuth   LocalAuthConfig   `yaml:"local_auth"`
+	Health      HealthConfig      `yaml:"health"`
+	Favicon     FaviconConfig     `yaml:"favicon"`
+	Audit       AuditConfig       `yaml:"audit"`
 }
 
 type AppConfig struct {
@@ -17,20 +27,214 @@ type TLSConfig struct {
 	Key  string `yaml:"key"  env:"APP_TLS_KEY_FILE"`
 }
 
+// HealthConfig controls the background prober that checks applications with
+// an enabled health check.
+type HealthConfig struct {
+	Enabled            bool          `yaml:"enabled"              env:"HEALTH_ENABLED"              env-default:"false"`
+	Interval           time.Duration `yaml:"interval"             env:"HEALTH_INTERVAL"             env-default:"1m"`
+	Timeout            time.Duration `yaml:"timeout"              env:"HEALTH_TIMEOUT"              env-default:"5s"`
+	Concurrency        int           `yaml:"concurrency"          env:"HEALTH_CONCURRENCY"          env-default:"8"`
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify" env:"HEALTH_INSECURE_SKIP_VERIFY" env-default:"false"`
+}
+
+// FaviconConfig controls fetching and caching the site icons shown for icons
+// of type "favicon".
+type FaviconConfig struct {
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"FAVICON_CACHE_TTL" env-default:"168h"`
+	Timeout  time.Duration `yaml:"timeout"   env:"FAVICON_TIMEOUT"   env-default:"5s"`
+	// MaxEntries caps the cached icons; the least recently fetched are
+	// dropped first. 0 = no limit.
+	MaxEntries int `yaml:"max_entries" env:"FAVICON_CACHE_MAX_ENTRIES" env-default:"1000"`
+	// AllowPrivateNetworks lets the fetcher reach private addresses such as
+	// 192.168.0.0/16. Loopback and link-local addresses are never fetched.
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" env:"FAVICON_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+// AuditConfig controls how long the audit log keeps its entries.
+type AuditConfig struct {
+	Retention time.Duration `yaml:"retention" env:"AUDIT_RETENTION" env-default:"2160h"` // 0 = forever
+}
+
+// ForwardAuthConfig enables authentication by a reverse proxy such as
+// Authelia, an Authentik outpost or oauth2-proxy, which passes the signed-in
+// user in request headers. The headers are only trusted from TrustedProxies.
+type ForwardAuthConfig struct {
+	Enabled         bool          `yaml:"enabled"          env:"FORWARD_AUTH_ENABLED"          env-default:"false"`
+	TrustedProxies  []string      `yaml:"trusted_proxies"  env:"FORWARD_AUTH_TRUSTED_PROXIES"`
+	Issuer          string        `yaml:"issuer"           env:"FORWARD_AUTH_ISSUER"           env-default:"forward-auth"`
+	UserHeader      string        `yaml:"user_header"      env:"FORWARD_AUTH_USER_HEADER"      env-default:"Remote-User"`
+	GroupsHeader    string        `yaml:"groups_header"    env:"FORWARD_AUTH_GROUPS_HEADER"    env-default:"Remote-Groups"`
+	EmailHeader     string        `yaml:"email_header"     env:"FORWARD_AUTH_EMAIL_HEADER"     env-default:"Remote-Email"`
+	NameHeader      string        `yaml:"name_header"      env:"FORWARD_AUTH_NAME_HEADER"      env-default:"Remote-Name"`
+	GroupsSeparator string        `yaml:"groups_separator" env:"FORWARD_AUTH_GROUPS_SEPARATOR" env-default:","`
+	AdminGroups     []string      `yaml:"admin_groups"     env:"FORWARD_AUTH_ADMIN_GROUPS"     env-default:"admin"`
+	SessionTTL      time.Duration `yaml:"session_ttl"      env:"FORWARD_AUTH_SESSION_TTL"      env-default:"24h"`
+	LoginURL        string        `yaml:"login_url"        env:"FORWARD_AUTH_LOGIN_URL"`
+	LogoutURL       string        `yaml:"logout_url"       env:"FORWARD_AUTH_LOGOUT_URL"`
+}
+
+// LocalAuthConfig enables accounts that sign in with a password managed by
+// Dash. Accounts are stored in the database; UsersFile optionally defines
+// read-only accounts, and AdminPassword creates an admin account on first start.
+type LocalAuthConfig struct {
+	Enabled       bool          `yaml:"enabled"        env:"LOCAL_AUTH_ENABLED"        env-default:"false"`
+	Issuer        string        `yaml:"issuer"         env:"LOCAL_AUTH_ISSUER"         env-default:"local"`
+	UsersFile     string        `yaml:"users_file"     env:"LOCAL_AUTH_USERS_FILE"`
+	AdminGroups   []string      `yaml:"admin_groups"   env:"LOCAL_AUTH_ADMIN_GROUPS"   env-default:"admin"`
+	AdminUsername string        `yaml:"admin_username" env:"LOCAL_AUTH_ADMIN_USERNAME" env-default:"admin"`
+	AdminPassword string        `yaml:"admin_password" env:"LOCAL_AUTH_ADMIN_PASSWORD"` // plain text or bcrypt hash
+	SessionTTL    time.Duration `yaml:"session_ttl"    env:"LOCAL_AUTH_SESSION_TTL"    env-default:"24h"`
+}
+
 type DatabaseConfig struct {
 	URL string `yaml:"url" env:"DATABASE_URL" env-required:"true"`
 }
 
+// OIDCConfig configures the primary identity provider. Additional providers
+// can be listed under Providers; they share the cookie settings and fall back
+// to the primary provider's redirect URL, scopes and admin group.
+// The provider settings are optional when forward auth or local accounts are
+// enabled; the cookie settings are always required.
 type OIDCConfig struct {
-	Issuer        string           `yaml:"issuer"          env:"OIDC_ISSUER"          env-required:"true"`
-	ClientID      string           `yaml:"client_id"       env:"OIDC_CLIENT_ID"       env-required:"true"`
-	ClientSecret  string           `yaml:"client_secret"   env:"OIDC_CLIENT_SECRET"   env-required:"true"`
-	RedirectURL   string           `yaml:"redirect_url"    env:"OIDC_REDIRECT_URL"    env-required:"true"`
-	EndSessionURL string           `yaml:"end_session_url" env:"OIDC_END_SESSION_URL"`
-	Scopes        string           `yaml:"scopes"          env:"OIDC_SCOPES"          env-default:"openid profile email groups"`
-	AdminGroup    string           `yaml:"admin_group"     env:"OIDC_ADMIN_GROUP"     env-default:"admin"`
-	ProfileURL    string           `yaml:"profile_url"     env:"OIDC_PROFILE_URL"`
-	Cookie        OIDCCookieConfig `yaml:"cookie"`
+	ID            string               `yaml:"id"              env:"OIDC_PROVIDER_ID"     env-default:"default"`
+	Name          string               `yaml:"name"            env:"OIDC_PROVIDER_NAME"`
+	Issuer        string               `yaml:"issuer"          env:"OIDC_ISSUER"`
+	ClientID      string               `yaml:"client_id"       env:"OIDC_CLIENT_ID"`
+	ClientSecret  string               `yaml:"client_secret"   env:"OIDC_CLIENT_SECRET"`
+	RedirectURL   string               `yaml:"redirect_url"    env:"OIDC_REDIRECT_URL"`
+	EndSessionURL string               `yaml:"end_session_url" env:"OIDC_END_SESSION_URL"`
+	Scopes        string               `yaml:"scopes"          env:"OIDC_SCOPES"          env-default:"openid profile email groups"`
+	AdminGroup    string               `yaml:"admin_group"     env:"OIDC_ADMIN_GROUP"     env-default:"admin"`
+	AdminGroups   []string             `yaml:"admin_groups"    env:"OIDC_ADMIN_GROUPS"`
+	ProfileURL    string               `yaml:"profile_url"     env:"OIDC_PROFILE_URL"`
+	UserInfo      bool                 `yaml:"userinfo"        env:"OIDC_USERINFO"        env-default:"false"`
+	OfflineAccess bool                 `yaml:"offline_access"  env:"OIDC_OFFLINE_ACCESS"  env-default:"false"`
+	Claims        OIDCClaimsConfig     `yaml:"claims"`
+	Providers     []OIDCProviderConfig `yaml:"providers"`
+	Cookie        OIDCCookieConfig     `yaml:"cookie"`
+}
+
+// OIDCProviderConfig configures a single identity provider.
+type OIDCProviderConfig struct {
+	ID            string           `yaml:"id"`
+	Name          string           `yaml:"name"`
+	Issuer        string           `yaml:"issuer"`
+	ClientID      string           `yaml:"client_id"`
+	ClientSecret  string           `yaml:"client_secret"`
+	RedirectURL   string           `yaml:"redirect_url"`
+	EndSessionURL string           `yaml:"end_session_url"`
+	Scopes        string           `yaml:"scopes"`
+	AdminGroup    string           `yaml:"admin_group"`
+	AdminGroups   []string         `yaml:"admin_groups"`
+	ProfileURL    string           `yaml:"profile_url"`
+	UserInfo      bool             `yaml:"userinfo"`
+	OfflineAccess bool             `yaml:"offline_access"` // request a refresh token for silent session refresh
+	Claims        OIDCClaimsConfig `yaml:"claims"`
+}
+
+// OIDCClaimsConfig names the claims identity fields are read from. Paths may
+// point into nested objects with dots, e.g. "realm_access.roles" for Keycloak
+// realm roles.
+type OIDCClaimsConfig struct {
+	Groups     string `yaml:"groups"      env:"OIDC_CLAIM_GROUPS"      env-default:"groups"`
+	Username   string `yaml:"username"    env:"OIDC_CLAIM_USERNAME"    env-default:"preferred_username"`
+	Name       string `yaml:"name"        env:"OIDC_CLAIM_NAME"        env-default:"name"`
+	GivenName  string `yaml:"given_name"  env:"OIDC_CLAIM_GIVEN_NAME"  env-default:"given_name"`
+	FamilyName string `yaml:"family_name" env:"OIDC_CLAIM_FAMILY_NAME" env-default:"family_name"`
+	Email      string `yaml:"email"       env:"OIDC_CLAIM_EMAIL"       env-default:"email"`
+	Picture    string `yaml:"picture"     env:"OIDC_CLAIM_PICTURE"     env-default:"picture"`
+}
+
+// withDefaults fills unset claim paths with the standard OIDC claim names.
+func (c OIDCClaimsConfig) withDefaults() OIDCClaimsConfig {
+	defaults := OIDCClaimsConfig{
+		Groups:     "groups",
+		Username:   "preferred_username",
+		Name:       "name",
+		GivenName:  "given_name",
+		FamilyName: "family_name",
+		Email:      "email",
+		Picture:    "picture",
+	}
+	if c.Groups == "" {
+		c.Groups = defaults.Groups
+	}
+	if c.Username == "" {
+		c.Username = defaults.Username
+	}
+	if c.Name == "" {
+		c.Name = defaults.Name
+	}
+	if c.GivenName == "" {
+		c.GivenName = defaults.GivenName
+	}
+	if c.FamilyName == "" {
+		c.FamilyName = defaults.FamilyName
+	}
+	if c.Email == "" {
+		c.Email = defaults.Email
+	}
+	if c.Picture == "" {
+		c.Picture = defaults.Picture
+	}
+	return c
+}
+
+// AllAdminGroups returns AdminGroup and AdminGroups combined.
+func (c OIDCProviderConfig) AllAdminGroups() []string {
+	var groups []string
+	for _, g := range append([]string{c.AdminGroup}, c.AdminGroups...) {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// AllProviders returns the primary provider followed by all additional
+// providers, with unset fields inherited from the primary provider.
+// Returns nil when no OIDC provider is configured.
+func (c *OIDCConfig) AllProviders() []OIDCProviderConfig {
+	if c.Issuer == "" {
+		return nil
+	}
+
+	primary := OIDCProviderConfig{
+		ID:            c.ID,
+		Name:          c.Name,
+		Issuer:        c.Issuer,
+		ClientID:      c.ClientID,
+		ClientSecret:  c.ClientSecret,
+		RedirectURL:   c.RedirectURL,
+		EndSessionURL: c.EndSessionURL,
+		Scopes:        c.Scopes,
+		AdminGroup:    c.AdminGroup,
+		AdminGroups:   c.AdminGroups,
+		ProfileURL:    c.ProfileURL,
+		UserInfo:      c.UserInfo,
+		OfflineAccess: c.OfflineAccess,
+		Claims:        c.Claims.withDefaults(),
+	}
+	if primary.ID == "" {
+		primary.ID = "default"
+	}
+
+	all := []OIDCProviderConfig{primary}
+	for _, p := range c.Providers {
+		if p.RedirectURL == "" {
+			p.RedirectURL = primary.RedirectURL
+		}
+		if p.Scopes == "" {
+			p.Scopes = primary.Scopes
+		}
+		if p.AdminGroup == "" && len(p.AdminGroups) == 0 {
+			p.AdminGroup = primary.AdminGroup
+			p.AdminGroups = primary.AdminGroups
+		}
+		p.Claims = p.Claims.withDefaults()
+		all = append(all, p)
+	}
+	return all
 }
 
 type OIDCCookieConfig struct {
@@ -2,7 +2,9 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -13,12 +15,138 @@ func Load() (*Config, error) {
 	cfg := &Config{}
 
 	if _, err := os.Stat(configFile); err == nil {
-		return cfg, cleanenv.ReadConfig(configFile, cfg)
+		if err := cleanenv.ReadConfig(configFile, cfg); err != nil {
+			return cfg, err
+		}
+		return cfg, validate(cfg)
 	}
 
 	if err := cleanenv.ReadEnv(cfg); err != nil {
 		return nil, errors.New("failed to load config from environment: " + err.Error())
 	}
+	cfg.OIDC.Providers = providersFromEnv(os.Getenv)
+
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
+
+// providersFromEnv reads the additional identity providers listed in
+// OIDC_PROVIDERS (comma-separated IDs). Each provider is configured through
+// OIDC_<ID>_* variables, e.g. OIDC_WORK_ISSUER for the provider "work".
+func providersFromEnv(getenv func(string) string) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, id := range strings.Split(getenv("OIDC_PROVIDERS"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			ID:            id,
+			Name:          getenv(prefix + "NAME"),
+			Issuer:        getenv(prefix + "ISSUER"),
+			ClientID:      getenv(prefix + "CLIENT_ID"),
+			ClientSecret:  getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:   getenv(prefix + "REDIRECT_URL"),
+			EndSessionURL: getenv(prefix + "END_SESSION_URL"),
+			Scopes:        getenv(prefix + "SCOPES"),
+			AdminGroup:    getenv(prefix + "ADMIN_GROUP"),
+			AdminGroups:   splitList(getenv(prefix + "ADMIN_GROUPS")),
+			ProfileURL:    getenv(prefix + "PROFILE_URL"),
+			UserInfo:      getenv(prefix+"USERINFO") == "true",
+			OfflineAccess: getenv(prefix+"OFFLINE_ACCESS") == "true",
+			Claims: OIDCClaimsConfig{
+				Groups:     getenv(prefix + "CLAIM_GROUPS"),
+				Username:   getenv(prefix + "CLAIM_USERNAME"),
+				Name:       getenv(prefix + "CLAIM_NAME"),
+				GivenName:  getenv(prefix + "CLAIM_GIVEN_NAME"),
+				FamilyName: getenv(prefix + "CLAIM_FAMILY_NAME"),
+				Email:      getenv(prefix + "CLAIM_EMAIL"),
+				Picture:    getenv(prefix + "CLAIM_PICTURE"),
+			},
+		})
+	}
+	return providers
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// validate checks settings that depend on each other: at least one way to
+// sign in must be configured, and an enabled health prober needs positive
+// timings and concurrency.
+func validate(cfg *Config) error {
+	if cfg.OIDC.Issuer == "" {
+		if !cfg.ForwardAuth.Enabled && !cfg.LocalAuth.Enabled {
+			return errors.New("OIDC_ISSUER is required unless forward auth or local accounts are enabled")
+		}
+		if len(cfg.OIDC.Providers) > 0 {
+			return errors.New("additional oidc providers require a primary provider (OIDC_ISSUER)")
+		}
+	} else if cfg.OIDC.RedirectURL == "" {
+		return errors.New("OIDC_REDIRECT_URL is required")
+	}
+	if cfg.ForwardAuth.Enabled && len(cfg.ForwardAuth.TrustedProxies) == 0 {
+		return errors.New("FORWARD_AUTH_TRUSTED_PROXIES is required when forward auth is enabled")
+	}
+	if cfg.ForwardAuth.Enabled && cfg.LocalAuth.Enabled && cfg.ForwardAuth.Issuer == cfg.LocalAuth.Issuer {
+		return errors.New("FORWARD_AUTH_ISSUER and LOCAL_AUTH_ISSUER must differ")
+	}
+	if err := validateHealth(&cfg.Health); err != nil {
+		return err
+	}
+	return validateProviders(&cfg.OIDC)
+}
+
+// validateHealth rejects settings the prober cannot run with; they are only
+// checked while it is enabled.
+func validateHealth(cfg *HealthConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Interval <= 0 {
+		return errors.New("HEALTH_INTERVAL must be positive")
+	}
+	if cfg.Timeout <= 0 {
+		return errors.New("HEALTH_TIMEOUT must be positive")
+	}
+	if cfg.Concurrency < 1 {
+		return errors.New("HEALTH_CONCURRENCY must be at least 1")
+	}
+	return nil
+}
+
+// validateProviders ensures every provider has a unique ID and the settings
+// required for the authorization code flow.
+func validateProviders(cfg *OIDCConfig) error {
+	seenIDs := map[string]bool{}
+	seenIssuers := map[string]bool{}
+	for _, p := range cfg.AllProviders() {
+		if p.ID == "" {
+			return errors.New("oidc provider without id")
+		}
+		if seenIDs[p.ID] {
+			return fmt.Errorf("duplicate oidc provider id %q", p.ID)
+		}
+		seenIDs[p.ID] = true
+		if p.Issuer == "" || p.ClientID == "" || p.ClientSecret == "" {
+			return fmt.Errorf("oidc provider %q requires issuer, client_id and client_secret", p.ID)
+		}
+		if seenIssuers[p.Issuer] {
+			return fmt.Errorf("duplicate oidc issuer %q", p.Issuer)
+		}
+		seenIssuers[p.Issuer] = true
+	}
+	return nil
+}
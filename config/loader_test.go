@@ -0,0 +1,183 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProvidersFromEnv(t *testing.T) {
+	env := map[string]string{
+		"OIDC_PROVIDERS":             "work, pocket-id",
+		"OIDC_WORK_NAME":             "Work",
+		"OIDC_WORK_ISSUER":           "https://kc.example.com/realms/work",
+		"OIDC_WORK_CLIENT_ID":        "dash",
+		"OIDC_WORK_CLIENT_SECRET":    "secret",
+		"OIDC_WORK_OFFLINE_ACCESS":   "true",
+		"OIDC_POCKET_ID_ISSUER":      "https://id.example.com",
+		"OIDC_POCKET_ID_ADMIN_GROUP": "family-admins",
+	}
+	providers := providersFromEnv(func(k string) string { return env[k] })
+
+	if len(providers) != 2 {
+		t.Fatalf("providersFromEnv() returned %d providers, want 2", len(providers))
+	}
+	if providers[0].ID != "work" || providers[0].Name != "Work" || providers[0].Issuer != "https://kc.example.com/realms/work" || !providers[0].OfflineAccess {
+		t.Errorf("providersFromEnv()[0] = %+v", providers[0])
+	}
+	if providers[1].ID != "pocket-id" || providers[1].AdminGroup != "family-admins" || providers[1].OfflineAccess {
+		t.Errorf("providersFromEnv()[1] = %+v", providers[1])
+	}
+}
+
+func TestProvidersFromEnv_Unset(t *testing.T) {
+	if providers := providersFromEnv(func(string) string { return "" }); len(providers) != 0 {
+		t.Errorf("providersFromEnv() = %+v, want none", providers)
+	}
+}
+
+func TestAllProviders_InheritsDefaults(t *testing.T) {
+	cfg := OIDCConfig{
+		Issuer:      "https://id.example.com",
+		RedirectURL: "https://dash.example.com/session/login/callback",
+		Scopes:      "openid profile email groups",
+		AdminGroup:  "admin",
+		Providers: []OIDCProviderConfig{
+			{ID: "work", Issuer: "https://kc.example.com", Scopes: "openid"},
+		},
+	}
+
+	all := cfg.AllProviders()
+
+	if len(all) != 2 {
+		t.Fatalf("AllProviders() returned %d providers, want 2", len(all))
+	}
+	if all[0].ID != "default" {
+		t.Errorf("primary ID = %q, want %q", all[0].ID, "default")
+	}
+	work := all[1]
+	if work.RedirectURL != cfg.RedirectURL {
+		t.Errorf("RedirectURL = %q, want inherited %q", work.RedirectURL, cfg.RedirectURL)
+	}
+	if work.Scopes != "openid" {
+		t.Errorf("Scopes = %q, want %q", work.Scopes, "openid")
+	}
+	if work.AdminGroup != "admin" {
+		t.Errorf("AdminGroup = %q, want inherited %q", work.AdminGroup, "admin")
+	}
+}
+
+func TestValidateProviders(t *testing.T) {
+	base := OIDCConfig{ID: "default", Issuer: "https://id.example.com", ClientID: "dash", ClientSecret: "s"}
+
+	tests := []struct {
+		name    string
+		extra   []OIDCProviderConfig
+		wantErr bool
+	}{
+		{"primary only", nil, false},
+		{"valid extra", []OIDCProviderConfig{{ID: "work", Issuer: "https://kc.example.com", ClientID: "dash", ClientSecret: "s"}}, false},
+		{"duplicate id", []OIDCProviderConfig{{ID: "default", Issuer: "https://kc.example.com", ClientID: "dash", ClientSecret: "s"}}, true},
+		{"duplicate issuer", []OIDCProviderConfig{{ID: "work", Issuer: "https://id.example.com", ClientID: "dash", ClientSecret: "s"}}, true},
+		{"missing id", []OIDCProviderConfig{{Issuer: "https://kc.example.com", ClientID: "dash", ClientSecret: "s"}}, true},
+		{"missing secret", []OIDCProviderConfig{{ID: "work", Issuer: "https://kc.example.com", ClientID: "dash"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.Providers = tt.extra
+			err := validateProviders(&cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateProviders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllProviders_ClaimsAndAdminGroups(t *testing.T) {
+	cfg := OIDCConfig{
+		Issuer:      "https://id.example.com",
+		AdminGroup:  "admin",
+		AdminGroups: []string{"dash-admins", " "},
+		Claims:      OIDCClaimsConfig{Groups: "realm_access.roles"},
+		Providers: []OIDCProviderConfig{
+			{ID: "work", Issuer: "https://kc.example.com", AdminGroups: []string{"ops"}},
+		},
+	}
+
+	all := cfg.AllProviders()
+
+	if got := all[0].AllAdminGroups(); len(got) != 2 || got[0] != "admin" || got[1] != "dash-admins" {
+		t.Errorf("primary AllAdminGroups() = %v, want [admin dash-admins]", got)
+	}
+	if all[0].Claims.Groups != "realm_access.roles" || all[0].Claims.Username != "preferred_username" {
+		t.Errorf("primary Claims = %+v", all[0].Claims)
+	}
+	if got := all[1].AllAdminGroups(); len(got) != 1 || got[0] != "ops" {
+		t.Errorf("work AllAdminGroups() = %v, want [ops]", got)
+	}
+	if all[1].Claims.Groups != "groups" {
+		t.Errorf("work Claims.Groups = %q, want standard default", all[1].Claims.Groups)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	oidc := OIDCConfig{ID: "default", Issuer: "https://id.example.com", ClientID: "dash", ClientSecret: "s", RedirectURL: "https://dash.example.com/session/login/callback"}
+	proxy := ForwardAuthConfig{Enabled: true, TrustedProxies: []string{"10.0.0.0/8"}}
+	health := HealthConfig{Enabled: true, Interval: time.Minute, Timeout: 5 * time.Second, Concurrency: 8}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"oidc only", Config{OIDC: oidc}, false},
+		{"neither", Config{}, true},
+		{"forward auth only", Config{ForwardAuth: proxy}, false},
+		{"both", Config{OIDC: oidc, ForwardAuth: proxy}, false},
+		{"local accounts only", Config{LocalAuth: LocalAuthConfig{Enabled: true, Issuer: "local"}}, false},
+		{"same issuer for proxy and local accounts", Config{ForwardAuth: ForwardAuthConfig{Enabled: true, TrustedProxies: []string{"10.0.0.0/8"}, Issuer: "local"}, LocalAuth: LocalAuthConfig{Enabled: true, Issuer: "local"}}, true},
+		{"forward auth without proxies", Config{ForwardAuth: ForwardAuthConfig{Enabled: true}}, true},
+		{"extra provider without primary", Config{ForwardAuth: proxy, OIDC: OIDCConfig{Providers: []OIDCProviderConfig{{ID: "work"}}}}, true},
+		{"issuer without redirect url", Config{OIDC: OIDCConfig{ID: "default", Issuer: "https://id.example.com", ClientID: "dash", ClientSecret: "s"}}, true},
+		{"health checks", Config{OIDC: oidc, Health: health}, false},
+		{"health checks disabled with zero settings", Config{OIDC: oidc, Health: HealthConfig{}}, false},
+		{"health interval zero", Config{OIDC: oidc, Health: HealthConfig{Enabled: true, Timeout: time.Second, Concurrency: 1}}, true},
+		{"health interval negative", Config{OIDC: oidc, Health: HealthConfig{Enabled: true, Interval: -time.Minute, Timeout: time.Second, Concurrency: 1}}, true},
+		{"health timeout zero", Config{OIDC: oidc, Health: HealthConfig{Enabled: true, Interval: time.Minute, Concurrency: 1}}, true},
+		{"health concurrency zero", Config{OIDC: oidc, Health: HealthConfig{Enabled: true, Interval: time.Minute, Timeout: time.Second}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_RejectsInvalidHealthSettings(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://dash@localhost/dash")
+	t.Setenv("OIDC_COOKIE_HASH_KEY", "hash")
+	t.Setenv("OIDC_COOKIE_BLOCK_KEY", "block")
+	t.Setenv("LOCAL_AUTH_ENABLED", "true")
+	t.Setenv("HEALTH_ENABLED", "true")
+
+	for _, env := range []struct{ key, value string }{
+		{"HEALTH_INTERVAL", "0s"},
+		{"HEALTH_TIMEOUT", "-1s"},
+		{"HEALTH_CONCURRENCY", "0"},
+	} {
+		t.Run(env.key, func(t *testing.T) {
+			t.Setenv(env.key, env.value)
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), env.key) {
+				t.Errorf("Load() with %s=%s error = %v, want error about %s", env.key, env.value, err, env.key)
+			}
+		})
+	}
+
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() with default health settings: %v", err)
+	}
+}
@@ -0,0 +1,516 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/samber/lo"
+)
+
+const (
+	ApiDashboardRoute         = "ApiDashboardRoute"
+	ApiDashboardsRoute        = "ApiDashboardsRoute"
+	ApiSearchRoute            = "ApiSearchRoute"
+	ApiShelvedCategoriesRoute = "ApiShelvedCategoriesRoute"
+	ApiCategoryRoute          = "ApiCategoryRoute"
+	ApiCategoryCreateRoute    = "ApiCategoryCreateRoute"
+	ApiCategoryUpdateRoute    = "ApiCategoryUpdateRoute"
+	ApiCategoryDeleteRoute    = "ApiCategoryDeleteRoute"
+	ApiBookmarkRoute          = "ApiBookmarkRoute"
+	ApiBookmarkCreateRoute    = "ApiBookmarkCreateRoute"
+	ApiBookmarkUpdateRoute    = "ApiBookmarkUpdateRoute"
+	ApiBookmarkDeleteRoute    = "ApiBookmarkDeleteRoute"
+	ApiThemesRoute            = "ApiThemesRoute"
+	ApiSearchProvidersRoute   = "ApiSearchProvidersRoute"
+	ApiSettingsRoute          = "ApiSettingsRoute"
+	ApiSettingsUpdateRoute    = "ApiSettingsUpdateRoute"
+)
+
+type ApiDeps struct {
+	SessionStore             *oidc.SessionStore
+	TokenLoader              middleware.IdentityLoader
+	App                      *fiber.App
+	GetUserDashboard         query.UserDashboardGetter
+	ListUserDashboards       query.UserDashboardsLister
+	SearchUserDashboard      query.UserDashboardSearcher
+	GetUserShelvedCategories query.UserShelvedCategoriesGetter
+	GetUserCategory          query.UserCategoryGetter
+	GetUserBookmark          query.UserBookmarkGetter
+	GetUserSettings          query.UserSettingsGetter
+	ListUserThemes           query.UserThemesLister
+	ListSearchProviders      query.UserSearchProvidersLister
+	CategoryCreate           command.UserCategoryCreator
+	CategoryUpdate           command.UserCategoryUpdater
+	CategoryDelete           command.UserCategoryDeleter
+	BookmarkCreate           command.UserBookmarkCreator
+	BookmarkUpdate           command.UserBookmarkUpdater
+	BookmarkDelete           command.UserBookmarkDeleter
+	UpdateUserSettings       command.UserSettingsUpdater
+}
+
+// apiErrorBody is the structured error envelope returned by every /api/v1 route.
+type apiErrorBody struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Status     int            `json:"status"`
+	Message    string         `json:"message"`
+	Violations []apiViolation `json:"violations,omitempty"`
+}
+
+type apiViolation struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type apiCategoryBody struct {
+	DisplayName string `json:"display_name"`
+	IsShelved   bool   `json:"is_shelved"`
+	// DashboardID is optional; omitting it uses the default dashboard on
+	// create and keeps the current dashboard on update.
+	DashboardID uint `json:"dashboard_id"`
+	// ParentID nests the category; 0 is the top level. Omitting it creates a
+	// top-level category and keeps the current parent on update, or moves
+	// the category to the top level of another dashboard.
+	ParentID *uint `json:"parent_id"`
+	// TagQuery makes a smart category, e.g. "tag:k8s AND NOT tag:legacy";
+	// empty makes a regular one. Omitting it keeps the query on update.
+	TagQuery *string `json:"tag_query"`
+}
+
+type apiBookmarkBody struct {
+	Icon        string `json:"icon"`
+	DisplayName string `json:"display_name"`
+	Url         string `json:"url"`
+	CategoryID  uint   `json:"category_id"`
+	// Tags is optional; omitting it keeps the current tags on update.
+	Tags *[]string `json:"tags"`
+}
+
+type apiSettingsBody struct {
+	ThemeID  uint   `json:"theme_id"`
+	Language string `json:"language"`
+	Timezone string `json:"timezone"`
+	// SearchProviderID is optional; omitting it keeps the current default.
+	SearchProviderID *uint `json:"search_provider_id"`
+}
+
+// Api registers the versioned JSON API under /api/v1.
+// Must be called BEFORE any handler that invokes router.Use(HtmxOnly) for the
+// same reason as SettingPlain: API clients never send the HX-Request header.
+//
+// This group is the only place personal access tokens are accepted; every
+// other route authenticates with the session cookie alone. The session is
+// loaded after the token, so a browser session takes precedence.
+func Api(deps ApiDeps) {
+	router := deps.App.
+		Group("/api/v1").
+		Use(middleware.LoadUserFromSession(deps.TokenLoader)).
+		Use(middleware.LoadUserFromSession(deps.SessionStore))
+
+	router.Get("/dashboard", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		dashboardID, err := apiQueryID(c, "id")
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		dash, err := deps.GetUserDashboard.Handle(c.Context(), user.UserID, dashboardID, user.Groups, user.FirstName, time.Now())
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(dash)
+	}).Name(ApiDashboardRoute)
+
+	router.Get("/dashboards", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		dashboards, err := deps.ListUserDashboards.Handle(c.Context(), user.UserID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(dashboards)
+	}).Name(ApiDashboardsRoute)
+
+	router.Get("/search", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		hits, err := deps.SearchUserDashboard.Handle(c.Context(), user.UserID, user.Groups, c.Query("q"))
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(hits)
+	}).Name(ApiSearchRoute)
+
+	router.Get("/categories/shelved", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		dashboardID, err := apiQueryID(c, "dashboard")
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		categories, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, dashboardID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(categories)
+	}).Name(ApiShelvedCategoriesRoute)
+
+	router.Get("/categories/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		category, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, id)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(category)
+	}).Name(ApiCategoryRoute)
+
+	router.Post("/categories", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		var body apiCategoryBody
+		if err := c.Bind().JSON(&body); err != nil {
+			return apiError(c, fiber.NewError(fiber.StatusBadRequest, "invalid body"))
+		}
+
+		if err := deps.CategoryCreate.Handle(c.Context(), user.UserID, command.CreateUserCategoryCmd{
+			DashboardID: body.DashboardID,
+			ParentID:    lo.FromPtr(body.ParentID),
+			DisplayName: body.DisplayName,
+			IsShelved:   body.IsShelved,
+			TagQuery:    lo.FromPtr(body.TagQuery),
+		}); err != nil {
+			return apiError(c, err)
+		}
+
+		return c.SendStatus(fiber.StatusCreated)
+	}).Name(ApiCategoryCreateRoute)
+
+	router.Put("/categories/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		var body apiCategoryBody
+		if err := c.Bind().JSON(&body); err != nil {
+			return apiError(c, fiber.NewError(fiber.StatusBadRequest, "invalid body"))
+		}
+
+		if err := deps.CategoryUpdate.Handle(c.Context(), user.UserID, command.UpdateUserCategoryCmd{
+			ID:          id,
+			DashboardID: body.DashboardID,
+			ParentID:    body.ParentID,
+			DisplayName: body.DisplayName,
+			IsShelved:   body.IsShelved,
+			TagQuery:    body.TagQuery,
+		}); err != nil {
+			return apiError(c, err)
+		}
+
+		category, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, id)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(category)
+	}).Name(ApiCategoryUpdateRoute)
+
+	router.Delete("/categories/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		if err := deps.CategoryDelete.Handle(c.Context(), user.UserID, id); err != nil {
+			return apiError(c, err)
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}).Name(ApiCategoryDeleteRoute)
+
+	router.Get("/bookmarks/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		bookmark, err := deps.GetUserBookmark.Handle(c.Context(), user.UserID, id)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(bookmark)
+	}).Name(ApiBookmarkRoute)
+
+	router.Post("/bookmarks", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		var body apiBookmarkBody
+		if err := c.Bind().JSON(&body); err != nil {
+			return apiError(c, fiber.NewError(fiber.StatusBadRequest, "invalid body"))
+		}
+
+		if err := deps.BookmarkCreate.Handle(c.Context(), user.UserID, command.CreateUserBookmarkCmd{
+			Icon:        body.Icon,
+			DisplayName: body.DisplayName,
+			Url:         body.Url,
+			CategoryID:  body.CategoryID,
+			Tags:        lo.FromPtr(body.Tags),
+		}); err != nil {
+			return apiError(c, err)
+		}
+
+		return c.SendStatus(fiber.StatusCreated)
+	}).Name(ApiBookmarkCreateRoute)
+
+	router.Put("/bookmarks/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		var body apiBookmarkBody
+		if err := c.Bind().JSON(&body); err != nil {
+			return apiError(c, fiber.NewError(fiber.StatusBadRequest, "invalid body"))
+		}
+
+		if err := deps.BookmarkUpdate.Handle(c.Context(), user.UserID, command.UpdateUserBookmarkCmd{
+			ID:          id,
+			Icon:        body.Icon,
+			DisplayName: body.DisplayName,
+			Url:         body.Url,
+			CategoryID:  body.CategoryID,
+			Tags:        body.Tags,
+		}); err != nil {
+			return apiError(c, err)
+		}
+
+		bookmark, err := deps.GetUserBookmark.Handle(c.Context(), user.UserID, id)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(bookmark)
+	}).Name(ApiBookmarkUpdateRoute)
+
+	router.Delete("/bookmarks/:id", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		id, err := apiParamID(c)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		if err := deps.BookmarkDelete.Handle(c.Context(), user.UserID, id); err != nil {
+			return apiError(c, err)
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}).Name(ApiBookmarkDeleteRoute)
+
+	router.Get("/themes", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		themes, err := deps.ListUserThemes.Handle(c.Context(), user.UserID, settings.ThemeID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(themes)
+	}).Name(ApiThemesRoute)
+
+	router.Get("/search-providers", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		providers, err := deps.ListSearchProviders.Handle(c.Context(), user.UserID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(providers)
+	}).Name(ApiSearchProvidersRoute)
+
+	router.Get("/settings", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(settings)
+	}).Name(ApiSettingsRoute)
+
+	router.Put("/settings", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return apiUnauthorized(c)
+		}
+
+		var body apiSettingsBody
+		if err := c.Bind().JSON(&body); err != nil {
+			return apiError(c, fiber.NewError(fiber.StatusBadRequest, "invalid body"))
+		}
+
+		if err := deps.UpdateUserSettings.Handle(c.Context(), user.UserID, command.UpdateUserSettingsCmd{
+			ThemeID:          body.ThemeID,
+			Language:         body.Language,
+			Timezone:         body.Timezone,
+			SearchProviderID: body.SearchProviderID,
+		}); err != nil {
+			return apiError(c, err)
+		}
+
+		settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID)
+		if err != nil {
+			return apiError(c, err)
+		}
+
+		return c.JSON(settings)
+	}).Name(ApiSettingsUpdateRoute)
+
+	// Unknown API paths get a JSON 404 instead of falling through to the
+	// HTML routes registered later.
+	router.Use(func(c fiber.Ctx) error {
+		return apiError(c, fiber.NewError(fiber.StatusNotFound, "route not found"))
+	})
+}
+
+// apiParamID parses the ":id" route parameter.
+func apiParamID(c fiber.Ctx) (uint, error) {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+	}
+	return uint(id64), nil
+}
+
+// apiQueryID parses the optional id query parameter name; 0 when absent.
+func apiQueryID(c fiber.Ctx, name string) (uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	id64, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "invalid "+name)
+	}
+	return uint(id64), nil
+}
+
+// apiUnauthorized responds with a JSON 401 instead of the login redirect used
+// by the HTML routes.
+func apiUnauthorized(c fiber.Ctx) error {
+	return apiError(c, fiber.NewError(fiber.StatusUnauthorized, "unauthorized"))
+}
+
+// apiError writes err as a structured JSON error body. Domain errors are mapped
+// the same way as httpError; *fiber.Error keeps its code. Anything else is
+// logged and reported as a generic 500 so infrastructure details never leak.
+func apiError(c fiber.Ctx, err error) error {
+	detail := apiErrorDetail{Status: fiber.StatusInternalServerError, Message: "internal server error"}
+
+	var nfe *domainerrors.NotFoundError
+	var fe *domainerrors.ForbiddenError
+	var ve *domainerrors.ValidationError
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &nfe):
+		detail = apiErrorDetail{Status: fiber.StatusNotFound, Message: nfe.Error()}
+	case errors.As(err, &fe):
+		detail = apiErrorDetail{Status: fiber.StatusForbidden, Message: fe.Error()}
+	case errors.As(err, &ve):
+		detail = apiErrorDetail{Status: fiber.StatusBadRequest, Message: "validation error"}
+		for _, v := range ve.Violations {
+			detail.Violations = append(detail.Violations, apiViolation{Field: v.Field, Message: v.Message})
+		}
+	case errors.As(err, &fiberErr):
+		detail = apiErrorDetail{Status: fiberErr.Code, Message: fiberErr.Message}
+	default:
+		log.Printf("api: %s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	return c.Status(detail.Status).JSON(apiErrorBody{Error: detail})
+}
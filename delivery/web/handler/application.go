@@ -22,6 +22,7 @@ const (
 	ApplicationCreateRoute       = "ApplicationCreateRoute"
 	ApplicationUpdateRoute       = "ApplicationUpdateRoute"
 	ApplicationDeleteRoute       = "ApplicationDeleteRoute"
+	ApplicationReorderRoute      = "ApplicationReorderRoute"
 	ApplicationsModalCreateRoute = "ApplicationsModalCreateRoute"
 	ApplicationsModalEditRoute   = "ApplicationsModalEditRoute"
 	ApplicationsModalDeleteRoute = "ApplicationsModalDeleteRoute"
@@ -36,6 +37,7 @@ type ApplicationDeps struct {
 	CreateApplication     command.ApplicationCreator
 	DeleteApplication     command.ApplicationDeleter
 	UpdateApplication     command.ApplicationUpdater
+	ReorderApplications   command.ApplicationsReorderer
 	GetAvailableIconTypes query.AvailableIconTypesGetter
 }
 
@@ -65,6 +67,8 @@ func Application(deps ApplicationDeps) {
 					Icon:        app.Icon.Name(),
 					DisplayName: app.DisplayName,
 					Domain:      app.Url.Host(),
+					Health:      healthIndicator(app),
+					LatencyMs:   app.Health.Latency,
 				}
 			})
 			return middleware.Render(c, partials.Applications(inputs))
@@ -115,12 +119,15 @@ func Application(deps ApplicationDeps) {
 				DisplayName     string `form:"display_name"`
 				Url             string `form:"url"`
 				VisibleToGroups string `form:"visible_to_groups"`
+				HealthCheck     bool   `form:"health_check"`
+				HealthURL       string `form:"health_url"`
+				HealthExpected  string `form:"health_expected_status"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
 			}
 
-			if err := deps.CreateApplication.Handle(c.Context(), command.CreateApplicationCmd{
+			if err := deps.CreateApplication.Handle(c.Context(), auditActor(c, user), command.CreateApplicationCmd{
 				CreatedBy:   &user.UserID,
 				Icon:        body.IconType + ":" + body.IconName,
 				DisplayName: body.DisplayName,
@@ -131,6 +138,9 @@ func Application(deps ApplicationDeps) {
 					}
 					return strings.Split(body.VisibleToGroups, " ")
 				}(),
+				HealthCheck:          body.HealthCheck,
+				HealthURL:            body.HealthURL,
+				HealthExpectedStatus: body.HealthExpected,
 			}); err != nil {
 				return err
 			}
@@ -140,6 +150,38 @@ func Application(deps ApplicationDeps) {
 			}))
 		}).Name(ApplicationCreateRoute)
 
+	// Registered before ":id" so that "order" is not parsed as an application id.
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/order", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			if !user.IsAdmin {
+				return fiber.NewError(fiber.StatusForbidden, "forbidden")
+			}
+
+			var body struct {
+				IDs string `form:"ids"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+			ids, err := parseIDList(body.IDs)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid ids")
+			}
+
+			if err := deps.ReorderApplications.Handle(c.Context(), command.ReorderApplicationsCmd{
+				IDs: ids,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(ApplicationReorderRoute)
+
 	router.
 		Use(middleware.HtmxOnly).
 		Put(":id", func(c fiber.Ctx) error {
@@ -162,6 +204,9 @@ func Application(deps ApplicationDeps) {
 				DisplayName     string `form:"display_name"`
 				Url             string `form:"url"`
 				VisibleToGroups string `form:"visible_to_groups"`
+				HealthCheck     bool   `form:"health_check"`
+				HealthURL       string `form:"health_url"`
+				HealthExpected  string `form:"health_expected_status"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
@@ -178,6 +223,9 @@ func Application(deps ApplicationDeps) {
 					}
 					return strings.Split(body.VisibleToGroups, " ")
 				}(),
+				HealthCheck:          body.HealthCheck,
+				HealthURL:            body.HealthURL,
+				HealthExpectedStatus: body.HealthExpected,
 			}); err != nil {
 				return err
 			}
@@ -203,7 +251,7 @@ func Application(deps ApplicationDeps) {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 			}
 
-			if err := deps.DeleteApplication.Handle(c.Context(), uint(id64)); err != nil {
+			if err := deps.DeleteApplication.Handle(c.Context(), auditActor(c, user), uint(id64)); err != nil {
 				return err
 			}
 
@@ -265,6 +313,11 @@ func Application(deps ApplicationDeps) {
 				DisplayName:     app.DisplayName,
 				Url:             app.Url.String(),
 				VisibleToGroups: strings.Join(app.VisibleToGroups, " "),
+				Health: partials.ApplicationsHealthFieldsInput{
+					Enabled:        app.HealthCheck.Enabled,
+					URL:            app.HealthCheck.URL,
+					ExpectedStatus: app.HealthCheck.ExpectedStatus.String(),
+				},
 			}))
 		}).Name(ApplicationsModalEditRoute)
 
@@ -295,3 +348,15 @@ func Application(deps ApplicationDeps) {
 			}))
 		}).Name(ApplicationsModalDeleteRoute)
 }
+
+// healthIndicator maps an application's latest probe result to the status dot
+// shown on its tile. Applications without a health check get no dot.
+func healthIndicator(app model.AppLink) string {
+	if !app.HealthCheck.Enabled {
+		return ""
+	}
+	if app.Health.State == model.HealthUnknown {
+		return "unknown"
+	}
+	return string(app.Health.State)
+}
@@ -9,15 +9,18 @@ import (
 	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
 	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/components"
 	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/partials"
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
 	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/samber/lo"
 )
 
 const (
 	BookmarkCreateRoute      = "BookmarkCreateRoute"
 	BookmarkUpdateRoute      = "BookmarkUpdateRoute"
 	BookmarkDeleteRoute      = "BookmarkDeleteRoute"
+	BookmarkReorderRoute     = "BookmarkReorderRoute"
 	BookmarkModalCreateRoute = "BookmarkModalCreateRoute"
 	BookmarkModalEditRoute   = "BookmarkModalEditRoute"
 	BookmarkModalDeleteRoute = "BookmarkModalDeleteRoute"
@@ -33,7 +36,10 @@ type BookmarkDeps struct {
 	BookmarkCreate           command.UserBookmarkCreator
 	BookmarkUpdate           command.UserBookmarkUpdater
 	BookmarkDelete           command.UserBookmarkDeleter
+	BookmarkReorder          command.UserBookmarksReorderer
 	GetAvailableIconTypes    query.AvailableIconTypesGetter
+	GetReceivedCategories    query.ReceivedCategoriesGetter
+	ListUserTags             query.UserTagsLister
 }
 
 func Bookmark(deps BookmarkDeps) {
@@ -55,6 +61,7 @@ func Bookmark(deps BookmarkDeps) {
 				DisplayName string `form:"display_name"`
 				Url         string `form:"url"`
 				CategoryID  uint   `form:"category_id"`
+				Tags        string `form:"tags"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
@@ -65,6 +72,7 @@ func Bookmark(deps BookmarkDeps) {
 				DisplayName: body.DisplayName,
 				Url:         body.Url,
 				CategoryID:  body.CategoryID,
+				Tags:        model.SplitTags(body.Tags),
 			}); err != nil {
 				return httpError(err)
 			}
@@ -74,6 +82,37 @@ func Bookmark(deps BookmarkDeps) {
 			}))
 		}).Name(BookmarkCreateRoute)
 
+	// Registered before ":id" so that "order" is not parsed as a bookmark id.
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/order", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				CategoryID uint   `form:"category_id"`
+				IDs        string `form:"ids"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+			ids, err := parseIDList(body.IDs)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid ids")
+			}
+
+			if err := deps.BookmarkReorder.Handle(c.Context(), user.UserID, command.ReorderUserBookmarksCmd{
+				CategoryID: body.CategoryID,
+				IDs:        ids,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(BookmarkReorderRoute)
+
 	router.
 		Use(middleware.HtmxOnly).
 		Put(":id", func(c fiber.Ctx) error {
@@ -93,6 +132,7 @@ func Bookmark(deps BookmarkDeps) {
 				DisplayName string `form:"display_name"`
 				Url         string `form:"url"`
 				CategoryID  uint   `form:"category_id"`
+				Tags        string `form:"tags"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
@@ -104,6 +144,7 @@ func Bookmark(deps BookmarkDeps) {
 				DisplayName: body.DisplayName,
 				Url:         body.Url,
 				CategoryID:  body.CategoryID,
+				Tags:        lo.ToPtr(model.SplitTags(body.Tags)),
 			}); err != nil {
 				return httpError(err)
 			}
@@ -148,10 +189,21 @@ func Bookmark(deps BookmarkDeps) {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 			}
 
-			category, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, uint(id64))
+			// Editors of a category shared with them add bookmarks as well.
+			received, err := deps.GetReceivedCategories.Handle(c.Context(), user.UserID)
 			if err != nil {
 				return httpError(err)
 			}
+			category, ok := lo.Find(editableReceivedCategories(received), func(category model.Category) bool {
+				return category.ID == uint(id64)
+			})
+			if !ok {
+				own, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, uint(id64))
+				if err != nil {
+					return httpError(err)
+				}
+				category = *own
+			}
 
 			return middleware.Render(c, partials.BookmarksCreateModal(partials.BookmarksCreateModalInput{
 				CategoryID:          category.ID,
@@ -160,6 +212,7 @@ func Bookmark(deps BookmarkDeps) {
 					list, _ := deps.GetAvailableIconTypes.Handle(c.Context())
 					return list
 				}(),
+				TagSuggestions: tagSuggestions(c, deps.ListUserTags, user.UserID),
 			}))
 		}).Name(BookmarkModalCreateRoute)
 
@@ -181,9 +234,15 @@ func Bookmark(deps BookmarkDeps) {
 				return httpError(err)
 			}
 
-			categories, _ := deps.GetUserCategories.Handle(c.Context(), user.UserID)
-			shelvedCategories, _ := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID)
-			allCategories := append(categories, shelvedCategories...)
+			dashboardID := currentDashboardID(c)
+			categories, _ := deps.GetUserCategories.Handle(c.Context(), user.UserID, dashboardID)
+			shelvedCategories, _ := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, dashboardID)
+			received, _ := deps.GetReceivedCategories.Handle(c.Context(), user.UserID)
+			allCategories := model.FlattenCategories(append(categories, shelvedCategories...))
+			allCategories = append(allCategories, editableReceivedCategories(received)...)
+			allCategories = lo.Reject(allCategories, func(category model.Category, _ int) bool {
+				return category.IsSmart()
+			})
 			sort.Slice(allCategories, func(i, j int) bool {
 				return allCategories[i].DisplayName < allCategories[j].DisplayName
 			})
@@ -207,6 +266,8 @@ func Bookmark(deps BookmarkDeps) {
 					}
 					return res
 				}(),
+				Tags:           bookmark.Tags,
+				TagSuggestions: tagSuggestions(c, deps.ListUserTags, user.UserID),
 			}))
 		}).Name(BookmarkModalEditRoute)
 
@@ -234,3 +295,25 @@ func Bookmark(deps BookmarkDeps) {
 			}))
 		}).Name(BookmarkModalDeleteRoute)
 }
+
+// editableReceivedCategories returns the categories shared with the user in
+// which they may edit bookmarks.
+func editableReceivedCategories(received []model.ReceivedCategory) []model.Category {
+	var categories []model.Category
+	for _, category := range received {
+		if category.Role == model.CategoryRoleEditor {
+			categories = append(categories, category.Category)
+		}
+	}
+	return categories
+}
+
+// tagSuggestions lists the user's tags for autocompletion. Without them the
+// tag input still works, so errors are ignored like for the icon types.
+func tagSuggestions(c fiber.Ctx, lister query.UserTagsLister, userID string) []string {
+	tags, err := lister.Handle(c.Context(), userID)
+	if err != nil {
+		return []string{}
+	}
+	return tags
+}
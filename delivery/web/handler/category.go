@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"net/url"
 	"strconv"
+	"strings"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	"git.at.oechsler.it/samuel/dash/v2/app/query"
@@ -23,9 +25,18 @@ const (
 	CategoriesModalEditRoute    = "CategoriesModalEditRoute"
 	CategoriesModalDeleteRoute  = "CategoriesModalDeleteRoute"
 	CategoriesModalShelvedRoute = "CategoriesModalShelvedRoute"
+	CategoriesModalPublishRoute = "CategoriesModalPublishRoute"
+	CategoriesModalShareRoute   = "CategoriesModalShareRoute"
 	CategoryCreateRoute         = "CategoryCreateRoute"
 	CategoryUpdateRoute         = "CategoryUpdateRoute"
 	CategoryDeleteRoute         = "CategoryDeleteRoute"
+	CategoryReorderRoute        = "CategoryReorderRoute"
+	CategoryPublishRoute        = "CategoryPublishRoute"
+	CategoryUnpublishRoute      = "CategoryUnpublishRoute"
+	CategoryHideSharedRoute     = "CategoryHideSharedRoute"
+	CategoryShareRoute          = "CategoryShareRoute"
+	CategoryRevokeShareRoute    = "CategoryRevokeShareRoute"
+	CategoryLeaveShareRoute     = "CategoryLeaveShareRoute"
 )
 
 type CategoryDeps struct {
@@ -37,6 +48,18 @@ type CategoryDeps struct {
 	CategoryCreate           command.UserCategoryCreator
 	CategoryUpdate           command.UserCategoryUpdater
 	CategoryDelete           command.UserCategoryDeleter
+	CategoryReorder          command.UserCategoriesReorderer
+	GetSharedCategories      query.SharedCategoriesGetter
+	ListSharedCategories     query.SharedCategoriesLister
+	PublishCategory          command.CategoryPublisher
+	UnpublishCategory        command.CategoryUnpublisher
+	HideSharedCategory       command.SharedCategoryHider
+	GetReceivedCategories    query.ReceivedCategoriesGetter
+	ListCategoryShares       query.CategorySharesLister
+	ShareCategory            command.CategorySharer
+	RevokeCategoryShare      command.CategoryShareRevoker
+	LeaveCategoryShare       command.CategoryShareLeaver
+	ListUserDashboards       query.UserDashboardsLister
 }
 
 func Category(deps CategoryDeps) {
@@ -52,15 +75,37 @@ func Category(deps CategoryDeps) {
 				return redirectToLogin(c)
 			}
 
-			categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID)
+			dashboardID := currentDashboardID(c)
+			categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID, dashboardID)
 			if err != nil {
 				return err
 			}
+			tags := currentTagFilter(c)
+			categories = lo.FilterMap(categories, func(category model.Category, _ int) (model.Category, bool) {
+				return filterCategoryByTags(category, tags)
+			})
+			// Categories of other users only show up on the default dashboard.
+			var received []model.ReceivedCategory
+			var shared []model.SharedCategory
+			if dashboardID == 0 {
+				if received, err = deps.GetReceivedCategories.Handle(c.Context(), user.UserID); err != nil {
+					return err
+				}
+				if shared, err = deps.GetSharedCategories.Handle(c.Context(), user.UserID, user.Groups); err != nil {
+					return err
+				}
+			}
 
-			inputs := lo.Map(categories, func(category model.Category, _ int) partials.CategoriesInput {
+			var toInput func(category model.Category, isShared bool) partials.CategoriesInput
+			toInput = func(category model.Category, isShared bool) partials.CategoriesInput {
 				return partials.CategoriesInput{
 					ID:          category.ID,
 					DisplayName: category.DisplayName,
+					IsShared:    isShared,
+					TagQuery:    category.TagQuery,
+					Children: lo.Map(category.Children, func(child model.Category, _ int) partials.CategoriesInput {
+						return toInput(child, isShared)
+					}),
 					Bookmarks: lo.Map(
 						category.Bookmarks,
 						func(bookmark model.Bookmark, _ int) partials.CategoriesInputBookmark {
@@ -74,7 +119,25 @@ func Category(deps CategoryDeps) {
 						},
 					),
 				}
+			}
+			inputs := lo.Map(categories, func(category model.Category, _ int) partials.CategoriesInput {
+				return toInput(category, false)
 			})
+			for _, category := range received {
+				filtered, ok := filterCategoryByTags(category.Category, tags)
+				if !ok {
+					continue
+				}
+				input := toInput(filtered, false)
+				input.IsReceived = true
+				input.SharedBy = category.OwnerLabel
+				inputs = append(inputs, input)
+			}
+			for _, category := range shared {
+				if filtered, ok := filterCategoryByTags(category.Category, tags); ok && !category.IsHidden {
+					inputs = append(inputs, toInput(filtered, true))
+				}
+			}
 			return middleware.Render(c, partials.Categories(inputs))
 		}).Name(CategoriesRoute)
 
@@ -86,29 +149,7 @@ func Category(deps CategoryDeps) {
 				return redirectToLogin(c)
 			}
 
-			categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID)
-			if err != nil {
-				return err
-			}
-
-			inputs := lo.Map(categories, func(category model.Category, _ int) partials.CategoriesEditInput {
-				return partials.CategoriesEditInput{
-					ID:          category.ID,
-					DisplayName: category.DisplayName,
-					Bookmarks: lo.Map(
-						category.Bookmarks,
-						func(bookmark model.Bookmark, _ int) partials.CategoriesEditInputBookmark {
-							return partials.CategoriesEditInputBookmark{
-								ID:          bookmark.ID,
-								IconType:    bookmark.Icon.Type(),
-								Icon:        bookmark.Icon.Name(),
-								DisplayName: bookmark.DisplayName,
-							}
-						},
-					),
-				}
-			})
-			return middleware.Render(c, partials.CategoriesEdit(inputs))
+			return renderCategoriesEdit(c, deps, user)
 		}).Name(CategoriesEditRoute)
 
 	router.
@@ -122,14 +163,19 @@ func Category(deps CategoryDeps) {
 			var body struct {
 				DisplayName string `form:"display_name"`
 				IsShelved   bool   `form:"is_shelved"`
+				ParentID    uint   `form:"parent_id"`
+				TagQuery    string `form:"tag_query"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
 			}
 
 			if err := deps.CategoryCreate.Handle(c.Context(), user.UserID, command.CreateUserCategoryCmd{
+				DashboardID: currentDashboardID(c),
+				ParentID:    body.ParentID,
 				DisplayName: body.DisplayName,
 				IsShelved:   body.IsShelved,
+				TagQuery:    body.TagQuery,
 			}); err != nil {
 				return httpError(err)
 			}
@@ -139,6 +185,188 @@ func Category(deps CategoryDeps) {
 			}))
 		}).Name(CategoryCreateRoute)
 
+	// Registered before ":id" so that "order" is not parsed as a category id.
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/order", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				IDs string `form:"ids"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+			ids, err := parseIDList(body.IDs)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid ids")
+			}
+
+			if err := deps.CategoryReorder.Handle(c.Context(), user.UserID, command.ReorderUserCategoriesCmd{
+				DashboardID: currentDashboardID(c),
+				IDs:         ids,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(CategoryReorderRoute)
+
+	// Admins publish their categories to groups; see the publish modal.
+	router.
+		Use(middleware.HtmxOnly).
+		Post(":id/publish", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			var body struct {
+				VisibleToGroups string `form:"visible_to_groups"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.PublishCategory.Handle(c.Context(), user.UserID, user.IsAdmin, auditActor(c, user), command.PublishCategoryCmd{
+				CategoryID:      uint(id64),
+				VisibleToGroups: strings.Fields(body.VisibleToGroups),
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return middleware.Render(c, partials.ModalCloseReload(partials.ModalCloseReloadInput{
+				Trigger: partials.ModalCloseReloadCategories,
+			}))
+		}).Name(CategoryPublishRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete(":id/publish", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.UnpublishCategory.Handle(c.Context(), user.IsAdmin, auditActor(c, user), uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return middleware.Render(c, partials.ModalCloseReload(partials.ModalCloseReloadInput{
+				Trigger: partials.ModalCloseReloadCategories,
+			}))
+		}).Name(CategoryUnpublishRoute)
+
+	// Members hide a shared category for themselves; they can show it again
+	// in the settings.
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/shared/:id/hide", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.HideSharedCategory.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderCategoriesEdit(c, deps, user)
+		}).Name(CategoryHideSharedRoute)
+
+	// Owners share a category with single users; see the share modal.
+	router.
+		Use(middleware.HtmxOnly).
+		Post(":id/shares", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			var body struct {
+				User string `form:"user"`
+				Role string `form:"role"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.ShareCategory.Handle(c.Context(), user.UserID, auditActor(c, user), command.ShareCategoryCmd{
+				CategoryID: uint(id64),
+				User:       body.User,
+				Role:       body.Role,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderCategoryShareModal(c, deps, user, uint(id64))
+		}).Name(CategoryShareRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete(":id/shares/:userId", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.RevokeCategoryShare.Handle(c.Context(), user.UserID, auditActor(c, user), uint(id64), c.Params("userId")); err != nil {
+				return httpError(err)
+			}
+
+			return renderCategoryShareModal(c, deps, user, uint(id64))
+		}).Name(CategoryRevokeShareRoute)
+
+	// Recipients leave a category shared with them; only the owner can share
+	// it again.
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/received/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.LeaveCategoryShare.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderCategoriesEdit(c, deps, user)
+		}).Name(CategoryLeaveShareRoute)
+
 	router.
 		Use(middleware.HtmxOnly).
 		Put(":id", func(c fiber.Ctx) error {
@@ -155,15 +383,34 @@ func Category(deps CategoryDeps) {
 			var body struct {
 				DisplayName string `form:"display_name"`
 				IsShelved   bool   `form:"is_shelved"`
+				DashboardID uint   `form:"dashboard_id"`
+				// ParentID is missing when the select is disabled, which
+				// moves the category to the top level of another dashboard,
+				// and empty for the top level.
+				ParentID *string `form:"parent_id"`
+				TagQuery *string `form:"tag_query"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
 			}
+			var parentID *uint
+			if body.ParentID != nil {
+				var id uint64
+				if *body.ParentID != "" {
+					if id, err = strconv.ParseUint(*body.ParentID, 10, 64); err != nil {
+						return fiber.NewError(fiber.StatusBadRequest, "invalid parent id")
+					}
+				}
+				parentID = lo.ToPtr(uint(id))
+			}
 
 			if err := deps.CategoryUpdate.Handle(c.Context(), user.UserID, command.UpdateUserCategoryCmd{
 				ID:          uint(id64),
+				DashboardID: body.DashboardID,
+				ParentID:    parentID,
 				DisplayName: body.DisplayName,
 				IsShelved:   body.IsShelved,
+				TagQuery:    body.TagQuery,
 			}); err != nil {
 				return httpError(err)
 			}
@@ -203,15 +450,22 @@ func Category(deps CategoryDeps) {
 				return redirectToLogin(c)
 			}
 
-			shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID)
+			shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, currentDashboardID(c))
 			if err != nil {
 				return err
 			}
+			tags := currentTagFilter(c)
+			shelved = lo.FilterMap(shelved, func(category model.Category, _ int) (model.Category, bool) {
+				return filterCategoryByTags(category, tags)
+			})
 
-			inputs := lo.Map(shelved, func(category model.Category, _ int) partials.CategoriesShelvedInput {
+			var toInput func(category model.Category, _ int) partials.CategoriesShelvedInput
+			toInput = func(category model.Category, _ int) partials.CategoriesShelvedInput {
 				return partials.CategoriesShelvedInput{
 					ID:          category.ID,
 					DisplayName: category.DisplayName,
+					TagQuery:    category.TagQuery,
+					Children:    lo.Map(category.Children, toInput),
 					Bookmarks: lo.Map(category.Bookmarks, func(bookmark model.Bookmark, _ int) partials.CategoriesShelvedInputBookmark {
 						return partials.CategoriesShelvedInputBookmark{
 							ID:          bookmark.ID,
@@ -223,8 +477,8 @@ func Category(deps CategoryDeps) {
 						}
 					}),
 				}
-			})
-			return middleware.Render(c, partials.CategoriesShelved(inputs))
+			}
+			return middleware.Render(c, partials.CategoriesShelved(lo.Map(shelved, toInput)))
 		}).Name(CategoriesShelvedRoute)
 
 	router.
@@ -235,15 +489,18 @@ func Category(deps CategoryDeps) {
 				return redirectToLogin(c)
 			}
 
-			shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID)
+			shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, currentDashboardID(c))
 			if err != nil {
 				return err
 			}
 
-			inputs := lo.Map(shelved, func(category model.Category, _ int) partials.CategoriesShelvedEditInput {
+			var toInput func(category model.Category, _ int) partials.CategoriesShelvedEditInput
+			toInput = func(category model.Category, _ int) partials.CategoriesShelvedEditInput {
 				return partials.CategoriesShelvedEditInput{
 					ID:          category.ID,
 					DisplayName: category.DisplayName,
+					TagQuery:    category.TagQuery,
+					Children:    lo.Map(category.Children, toInput),
 					Bookmarks: lo.Map(category.Bookmarks, func(bookmark model.Bookmark, _ int) partials.CategoriesShelvedEditInputBookmark {
 						return partials.CategoriesShelvedEditInputBookmark{
 							ID:          bookmark.ID,
@@ -254,14 +511,36 @@ func Category(deps CategoryDeps) {
 						}
 					}),
 				}
-			})
-			return middleware.Render(c, partials.CategoriesShelvedEdit(inputs))
+			}
+			return middleware.Render(c, partials.CategoriesShelvedEdit(lo.Map(shelved, toInput)))
 		}).Name(CategoriesShelvedEditRoute)
 
 	router.
 		Use(middleware.HtmxOnly).
 		Get("/modal/create", func(c fiber.Ctx) error {
-			return middleware.Render(c, partials.CategoriesCreateModal())
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var parentID uint
+			if raw := c.Query("parent"); raw != "" {
+				id64, err := strconv.ParseUint(raw, 10, 64)
+				if err != nil {
+					return fiber.NewError(fiber.StatusBadRequest, "invalid parent")
+				}
+				parentID = uint(id64)
+			}
+
+			parents, err := categoryParentOptions(c, deps, user, currentDashboardID(c), 0)
+			if err != nil {
+				return httpError(err)
+			}
+
+			return middleware.Render(c, partials.CategoriesCreateModal(partials.CategoriesCreateModalInput{
+				ParentID: parentID,
+				Parents:  parents,
+			}))
 		}).Name(CategoriesModalCreateRoute)
 
 	router.
@@ -281,11 +560,26 @@ func Category(deps CategoryDeps) {
 			if err != nil {
 				return httpError(err)
 			}
+			dashboards, err := deps.ListUserDashboards.Handle(c.Context(), user.UserID)
+			if err != nil {
+				return httpError(err)
+			}
+			parents, err := categoryParentOptions(c, deps, user, category.DashboardID, category.ID)
+			if err != nil {
+				return httpError(err)
+			}
 
 			return middleware.Render(c, partials.CategoriesEditModal(partials.CategoriesEditModalInput{
 				ID:          category.ID,
 				DisplayName: category.DisplayName,
 				IsShelved:   category.IsShelved,
+				DashboardID: category.DashboardID,
+				ParentID:    category.ParentID,
+				Parents:     parents,
+				TagQuery:    category.TagQuery,
+				Dashboards: lo.Map(dashboards, func(d model.DashboardPage, _ int) partials.CategoriesEditModalInputDashboard {
+					return partials.CategoriesEditModalInputDashboard{ID: d.ID, Name: d.Name}
+				}),
 			}))
 		}).Name(CategoriesModalEditRoute)
 
@@ -313,6 +607,55 @@ func Category(deps CategoryDeps) {
 			}))
 		}).Name(CategoriesModalDeleteRoute)
 
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/modal/publish/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			category, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, uint(id64))
+			if err != nil {
+				return httpError(err)
+			}
+			published, err := deps.ListSharedCategories.Handle(c.Context(), user.IsAdmin)
+			if err != nil {
+				return httpError(err)
+			}
+
+			input := partials.CategoriesPublishModalInput{
+				ID:          category.ID,
+				DisplayName: category.DisplayName,
+			}
+			if shared, ok := lo.Find(published, func(s model.SharedCategory) bool { return s.ID == category.ID }); ok {
+				input.IsPublished = true
+				input.VisibleToGroups = strings.Join(shared.VisibleToGroups, " ")
+			}
+			return middleware.Render(c, partials.CategoriesPublishModal(input))
+		}).Name(CategoriesModalPublishRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/modal/share/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			return renderCategoryShareModal(c, deps, user, uint(id64))
+		}).Name(CategoriesModalShareRoute)
+
 	router.
 		Use(middleware.HtmxOnly).
 		Get("/modal/shelved/:isShelved", func(c fiber.Ctx) error {
@@ -331,3 +674,174 @@ func Category(deps CategoryDeps) {
 			}))
 		}).Name(CategoriesModalShelvedRoute)
 }
+
+// renderCategoriesEdit renders the categories list in edit mode: the user's
+// own categories of the current dashboard, then, on the default dashboard,
+// those other users shared with them and finally the published ones they have
+// not hidden.
+func renderCategoriesEdit(c fiber.Ctx, deps CategoryDeps, user model.Identity) error {
+	dashboardID := currentDashboardID(c)
+	categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID, dashboardID)
+	if err != nil {
+		return err
+	}
+	var received []model.ReceivedCategory
+	var shared []model.SharedCategory
+	if dashboardID == 0 {
+		if received, err = deps.GetReceivedCategories.Handle(c.Context(), user.UserID); err != nil {
+			return err
+		}
+		if shared, err = deps.GetSharedCategories.Handle(c.Context(), user.UserID, user.Groups); err != nil {
+			return err
+		}
+	}
+	published := map[uint]bool{}
+	if user.IsAdmin {
+		list, err := deps.ListSharedCategories.Handle(c.Context(), user.IsAdmin)
+		if err != nil {
+			return err
+		}
+		for _, s := range list {
+			published[s.ID] = true
+		}
+	}
+
+	toInput := func(category model.Category) partials.CategoriesEditInput {
+		return partials.CategoriesEditInput{
+			ID:          category.ID,
+			DisplayName: category.DisplayName,
+			IsSmart:     category.IsSmart(),
+			TagQuery:    category.TagQuery,
+			Bookmarks: lo.Map(
+				category.Bookmarks,
+				func(bookmark model.Bookmark, _ int) partials.CategoriesEditInputBookmark {
+					return partials.CategoriesEditInputBookmark{
+						ID:          bookmark.ID,
+						IconType:    bookmark.Icon.Type(),
+						Icon:        bookmark.Icon.Name(),
+						DisplayName: bookmark.DisplayName,
+						Tags:        bookmark.Tags,
+					}
+				},
+			),
+		}
+	}
+	var toOwnedInput func(category model.Category, depth int) partials.CategoriesEditInput
+	toOwnedInput = func(category model.Category, depth int) partials.CategoriesEditInput {
+		input := toInput(category)
+		input.CanPublish = user.IsAdmin
+		input.IsPublished = published[category.ID]
+		input.CanNest = depth < model.MaxCategoryDepth
+		input.Children = lo.Map(category.Children, func(child model.Category, _ int) partials.CategoriesEditInput {
+			return toOwnedInput(child, depth+1)
+		})
+		return input
+	}
+	inputs := lo.Map(categories, func(category model.Category, _ int) partials.CategoriesEditInput {
+		return toOwnedInput(category, 1)
+	})
+	for _, category := range received {
+		input := toInput(category.Category)
+		input.IsReceived = true
+		input.SharedBy = category.OwnerLabel
+		input.CanEditBookmarks = category.Role == model.CategoryRoleEditor
+		inputs = append(inputs, input)
+	}
+	for _, category := range shared {
+		if !category.IsHidden {
+			input := toInput(category.Category)
+			input.IsShared = true
+			inputs = append(inputs, input)
+		}
+	}
+	return middleware.Render(c, partials.CategoriesEdit(inputs))
+}
+
+// categoryParentOptions lists the categories of the user's dashboard, shelved
+// ones included, that the category with the given id can be nested in: not
+// its own subtree and none that would nest it too deep. id is 0 for a new
+// category.
+func categoryParentOptions(c fiber.Ctx, deps CategoryDeps, user model.Identity, dashboardID, id uint) ([]partials.CategoriesParentOption, error) {
+	categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+	shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := model.FlattenCategories(append(categories, shelved...))
+	tree := model.CategoryTree{}
+	for _, category := range flat {
+		tree[category.ID] = category.ParentID
+	}
+	height := 1
+	if id != 0 {
+		height = tree.Height(id)
+	}
+
+	var options []partials.CategoriesParentOption
+	for _, category := range flat {
+		if tree.CheckParent(id, category.ID, height) != nil {
+			continue
+		}
+		options = append(options, partials.CategoriesParentOption{
+			ID:          category.ID,
+			DisplayName: category.DisplayName,
+			Depth:       tree.Depth(category.ID),
+		})
+	}
+	return options, nil
+}
+
+// renderCategoryShareModal renders the share modal of one of the user's
+// categories with the users it is currently shared with.
+func renderCategoryShareModal(c fiber.Ctx, deps CategoryDeps, user model.Identity, categoryID uint) error {
+	category, err := deps.GetUserCategory.Handle(c.Context(), user.UserID, categoryID)
+	if err != nil {
+		return httpError(err)
+	}
+	shares, err := deps.ListCategoryShares.Handle(c.Context(), user.UserID, categoryID)
+	if err != nil {
+		return httpError(err)
+	}
+
+	input := partials.CategoriesShareModalInput{
+		ID:          category.ID,
+		DisplayName: category.DisplayName,
+	}
+	for _, share := range shares {
+		revokeURL, err := c.GetRouteURL(CategoryRevokeShareRoute, fiber.Map{"id": category.ID, "userId": url.PathEscape(share.UserID)})
+		if err != nil {
+			return err
+		}
+		label := share.UserLabel
+		if label == "" {
+			label = share.UserID
+		}
+		input.Shares = append(input.Shares, partials.CategoriesShareModalInputShare{
+			UserLabel: label,
+			IsEditor:  share.Role == model.CategoryRoleEditor,
+			RevokeURL: revokeURL,
+		})
+	}
+	return middleware.Render(c, partials.CategoriesShareModal(input))
+}
+
+// parseIDList parses the comma-separated ids sent by the drag-and-drop lists.
+func parseIDList(s string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
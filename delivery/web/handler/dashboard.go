@@ -2,8 +2,13 @@ package handler
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
 	"git.at.oechsler.it/samuel/dash/v2/app/query"
 	webi18n "git.at.oechsler.it/samuel/dash/v2/delivery/web/i18n"
 	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
@@ -14,6 +19,7 @@ import (
 	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
 	"github.com/invopop/ctxi18n"
 	"github.com/invopop/ctxi18n/i18n"
+	"github.com/samber/lo"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -21,20 +27,43 @@ import (
 const (
 	DashboardRoute                      = "DashboardRoute"
 	DashboardGreetingRoute              = "DashboardGreetingRoute"
+	DashboardSearchRoute                = "DashboardSearchRoute"
+	DashboardWebSearchRoute             = "DashboardWebSearchRoute"
 	DashboardTitleApplicationsRoute     = "DashboardTitleApplicationsRoute"
 	DashboardTitleApplicationsEditRoute = "DashboardTitleApplicationsEditRoute"
 	DashboardTitleBookmarksRoute        = "DashboardTitleBookmarksRoute"
 	DashboardTitleBookmarksEditRoute    = "DashboardTitleBookmarksEditRoute"
 	DashboardEditRoute                  = "DashboardEditRoute"
 	DashboardModalCloseRoute            = "DashboardModalCloseRoute"
+	DashboardPageRoute                  = "DashboardPageRoute"
+	DashboardTabsRoute                  = "DashboardTabsRoute"
+	DashboardTabsEditRoute              = "DashboardTabsEditRoute"
+	DashboardsModalCreateRoute          = "DashboardsModalCreateRoute"
+	DashboardsModalEditRoute            = "DashboardsModalEditRoute"
+	DashboardsModalDeleteRoute          = "DashboardsModalDeleteRoute"
+	DashboardCreateRoute                = "DashboardCreateRoute"
+	DashboardUpdateRoute                = "DashboardUpdateRoute"
+	DashboardDeleteRoute                = "DashboardDeleteRoute"
+	DashboardReorderRoute               = "DashboardReorderRoute"
 )
 
 type DashboardDeps struct {
-	SessionStore     *oidc.SessionStore
-	App              *fiber.App
-	GetUserDashboard query.UserDashboardGetter
-	GetUserSettings  query.UserSettingsGetter
-	GetUserThemeByID query.UserThemeByIDGetter
+	SessionStore        *oidc.SessionStore
+	App                 *fiber.App
+	GetUserDashboard    query.UserDashboardGetter
+	SearchUserDashboard query.UserDashboardSearcher
+	// GetUserCategories and GetUserShelvedCategories collect the tags of
+	// the filter bar.
+	GetUserCategories        query.UserCategoriesGetter
+	GetUserShelvedCategories query.UserShelvedCategoriesGetter
+	ResolveWebSearch         query.UserWebSearchResolver
+	GetUserSettings          query.UserSettingsGetter
+	GetUserThemeByID         query.UserThemeByIDGetter
+	ListUserDashboards       query.UserDashboardsLister
+	DashboardCreate          command.UserDashboardCreator
+	DashboardUpdate          command.UserDashboardUpdater
+	DashboardDelete          command.UserDashboardDeleter
+	DashboardReorder         command.UserDashboardsReorderer
 }
 
 func Dashboard(deps DashboardDeps) {
@@ -48,53 +77,46 @@ func Dashboard(deps DashboardDeps) {
 			return redirectToLogin(c)
 		}
 
-		// Trigger dashboard auto-provisioning if needed (also ensures settings exist).
-		if _, err := deps.GetUserDashboard.Handle(
-			c.Context(),
-			user.UserID,
-			user.Groups,
-			user.FirstName,
-			time.Time{},
-		); err != nil {
-			return err
+		return renderDashboardPage(c, deps, user, 0)
+	}).Name(DashboardRoute)
+
+	// Dashboards other than the default one live at their own URL; the
+	// HTMX partials read the id back from HX-Current-URL.
+	router.Get("/dashboards/:id<int>", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return redirectToLogin(c)
 		}
 
-		settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID)
-		if err != nil {
-			return err
+		id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+		if err != nil || id64 == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 		}
 
-		// Resolved language code for <html lang=...>.
-		ctx := c.Context()
-		resolvedLang := "en"
-		if locale := ctxi18n.Locale(ctx); locale != nil {
-			resolvedLang = locale.Code().String()
+		return renderDashboardPage(c, deps, user, uint(id64))
+	}).Name(DashboardPageRoute)
+
+	// Target of the OpenSearch description: bangs and the default provider
+	// redirect to the web, everything else opens the dashboard search.
+	router.Get("/search", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return redirectToLogin(c)
 		}
 
-		curTheme, err := deps.GetUserThemeByID.Handle(c.Context(), user.UserID, settings.ThemeID)
+		q := c.Query("q")
+		webSearch, err := deps.ResolveWebSearch.Handle(c.Context(), user.UserID, q)
 		if err != nil {
-			def := domainmodel.DefaultTheme()
-			curTheme = &def
+			return err
 		}
-
-		return middleware.Render(c, page.Dashboard(page.DashboardInput{
-			BaseInput: layout.BaseInput{
-				Title:    user.FirstName + "'s Dash",
-				Language: resolvedLang,
-				Theme: layout.Theme{
-					Primary:   curTheme.Primary,
-					Secondary: curTheme.Secondary,
-					Tertiary:  curTheme.Tertiary,
-				},
-			},
-			User: page.UserInfo{
-				Picture:       user.Picture,
-				DisplayName:   user.DisplayName,
-				ProfileUrl:    user.ProfileUrl,
-				SessionPinned: middleware.GetCurrentSessionPinned(c),
-			},
-		}))
-	}).Name(DashboardRoute)
+		if webSearch != nil {
+			return c.Redirect().Status(fiber.StatusFound).To(webSearch.URL)
+		}
+		if q == "" {
+			return c.Redirect().Status(fiber.StatusFound).To("/")
+		}
+		return c.Redirect().Status(fiber.StatusFound).To("/?q=" + url.QueryEscape(q))
+	}).Name(DashboardWebSearchRoute)
 
 	router.
 		Use(middleware.HtmxOnly).
@@ -146,6 +168,53 @@ func Dashboard(deps DashboardDeps) {
 			}))
 		}).Name(DashboardGreetingRoute)
 
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboard/search", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			q := c.Query("q")
+			hits, err := deps.SearchUserDashboard.Handle(c.Context(), user.UserID, user.Groups, q)
+			if err != nil {
+				return err
+			}
+
+			inputs := lo.Map(hits, func(hit domainmodel.SearchHit, _ int) partials.DashboardSearchResultInput {
+				return partials.DashboardSearchResultInput{
+					IsApplication: hit.Kind == domainmodel.SearchHitApplication,
+					Url:           hit.Url,
+					IconType:      hit.Icon.Type(),
+					Icon:          hit.Icon.Name(),
+					DisplayName:   hit.DisplayName,
+					Domain:        hit.Host,
+					Category:      hit.Category,
+				}
+			})
+
+			// The web search entry comes last so that Enter still opens the
+			// best dashboard hit; with a bang there usually is none.
+			webSearch, err := deps.ResolveWebSearch.Handle(c.Context(), user.UserID, q)
+			if err != nil {
+				return err
+			}
+			var webInput *partials.DashboardSearchWebInput
+			if webSearch != nil {
+				webInput = &partials.DashboardSearchWebInput{
+					ProviderName: webSearch.Provider.DisplayName,
+					Url:          webSearch.URL,
+				}
+			}
+
+			return middleware.Render(c, partials.DashboardSearchResults(partials.DashboardSearchResultsInput{
+				Query:     q,
+				Results:   inputs,
+				WebSearch: webInput,
+			}))
+		}).Name(DashboardSearchRoute)
+
 	router.
 		Use(middleware.HtmxOnly).
 		Get("/dashboard/title/applications", func(c fiber.Ctx) error {
@@ -175,12 +244,11 @@ func Dashboard(deps DashboardDeps) {
 	router.
 		Use(middleware.HtmxOnly).
 		Get("/dashboard/title/bookmarks", func(c fiber.Ctx) error {
-			if _, authorized := middleware.GetCurrentUser(c); !authorized {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
 				return redirectToLogin(c)
 			}
-			return middleware.Render(c, partials.DashboardTitleBookmarks(partials.DashboardTitleBookmarksInput{
-				EditMode: false,
-			}))
+			return renderDashboardTitleBookmarks(c, deps, user)
 		}).Name(DashboardTitleBookmarksRoute)
 
 	router.
@@ -221,4 +289,426 @@ func Dashboard(deps DashboardDeps) {
 			}
 			return c.SendString("")
 		}).Name(DashboardModalCloseRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboards/tabs", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderDashboardTabs(c, deps, user, false)
+		}).Name(DashboardTabsRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboards/tabs/edit", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderDashboardTabs(c, deps, user, true)
+		}).Name(DashboardTabsEditRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboards/modal/create", func(c fiber.Ctx) error {
+			if _, authorized := middleware.GetCurrentUser(c); !authorized {
+				return redirectToLogin(c)
+			}
+			return middleware.Render(c, partials.DashboardsCreateModal())
+		}).Name(DashboardsModalCreateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboards/modal/edit/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			dashboard, err := findUserDashboard(c, deps, user)
+			if err != nil {
+				return err
+			}
+
+			return middleware.Render(c, partials.DashboardsEditModal(partials.DashboardsEditModalInput{
+				ID:        dashboard.ID,
+				Name:      dashboard.Name,
+				IsDefault: dashboard.IsDefault,
+			}))
+		}).Name(DashboardsModalEditRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/dashboards/modal/delete/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			dashboard, err := findUserDashboard(c, deps, user)
+			if err != nil {
+				return err
+			}
+
+			return middleware.Render(c, partials.DashboardsDeleteModal(partials.DashboardsDeleteModalInput{
+				ID:   dashboard.ID,
+				Name: dashboard.Name,
+			}))
+		}).Name(DashboardsModalDeleteRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/dashboards", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Name string `form:"name"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			id, err := deps.DashboardCreate.Handle(c.Context(), user.UserID, command.CreateUserDashboardCmd{
+				Name: body.Name,
+			})
+			if err != nil {
+				return httpError(err)
+			}
+
+			// Open the new dashboard right away.
+			pageURL, err := c.GetRouteURL(DashboardPageRoute, fiber.Map{"id": id})
+			if err != nil {
+				return err
+			}
+			c.Set("HX-Redirect", pageURL)
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(DashboardCreateRoute)
+
+	// Registered before ":id" so that "order" is not parsed as a dashboard id.
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/dashboards/order", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				IDs string `form:"ids"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+			ids, err := parseIDList(body.IDs)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid ids")
+			}
+
+			if err := deps.DashboardReorder.Handle(c.Context(), user.UserID, command.ReorderUserDashboardsCmd{
+				IDs: ids,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(DashboardReorderRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/dashboards/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			var body struct {
+				Name        string `form:"name"`
+				MakeDefault bool   `form:"make_default"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.DashboardUpdate.Handle(c.Context(), user.UserID, command.UpdateUserDashboardCmd{
+				ID:          uint(id64),
+				Name:        body.Name,
+				MakeDefault: body.MakeDefault,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			// The new default dashboard moves to "/".
+			if body.MakeDefault {
+				c.Set("HX-Redirect", "/")
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return middleware.Render(c, partials.ModalCloseReload(partials.ModalCloseReloadInput{
+				Trigger: partials.ModalCloseReloadDashboards,
+			}))
+		}).Name(DashboardUpdateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/dashboards/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.DashboardDelete.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			// Leave a page that no longer exists; "/" may have been the
+			// deleted default dashboard as well.
+			if current := currentDashboardID(c); current == 0 || current == uint(id64) {
+				c.Set("HX-Redirect", "/")
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return middleware.Render(c, partials.ModalCloseReload(partials.ModalCloseReloadInput{
+				Trigger: partials.ModalCloseReloadDashboards,
+			}))
+		}).Name(DashboardDeleteRoute)
+}
+
+// renderDashboardPage renders the full page of one of the user's dashboards;
+// 0 selects the default dashboard. The default dashboard is only served at "/".
+func renderDashboardPage(c fiber.Ctx, deps DashboardDeps, user domainmodel.Identity, dashboardID uint) error {
+	// The IdP rejected the silent refresh: re-authenticate interactively.
+	if middleware.GetCurrentSessionStale(c) {
+		refreshURL, err := c.GetRouteURL(SessionRefreshRoute, fiber.Map{})
+		if err != nil {
+			return err
+		}
+		return c.Redirect().Status(fiber.StatusFound).To(refreshURL + "?rd=" + url.QueryEscape(c.Path()))
+	}
+
+	// Trigger dashboard auto-provisioning if needed (also ensures settings exist).
+	dashboard, err := deps.GetUserDashboard.Handle(
+		c.Context(),
+		user.UserID,
+		dashboardID,
+		user.Groups,
+		user.FirstName,
+		time.Time{},
+	)
+	if err != nil {
+		return httpError(err)
+	}
+	if dashboardID != 0 && dashboard.IsDefault {
+		return c.Redirect().Status(fiber.StatusFound).To("/")
+	}
+
+	settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Resolved language code for <html lang=...>.
+	ctx := c.Context()
+	resolvedLang := "en"
+	if locale := ctxi18n.Locale(ctx); locale != nil {
+		resolvedLang = locale.Code().String()
+	}
+
+	curTheme, err := deps.GetUserThemeByID.Handle(c.Context(), user.UserID, settings.ThemeID)
+	if err != nil {
+		def := domainmodel.DefaultTheme()
+		curTheme = &def
+	}
+
+	title := user.FirstName + "'s Dash"
+	if dashboardID != 0 {
+		title = dashboard.Name + " · " + title
+	}
+	return middleware.Render(c, page.Dashboard(page.DashboardInput{
+		BaseInput: layout.BaseInput{
+			Title:    title,
+			Language: resolvedLang,
+			Theme: layout.Theme{
+				Primary:   curTheme.Primary,
+				Secondary: curTheme.Secondary,
+				Tertiary:  curTheme.Tertiary,
+			},
+		},
+		User: page.UserInfo{
+			Picture:       user.Picture,
+			DisplayName:   user.DisplayName,
+			ProfileUrl:    user.ProfileUrl,
+			SessionPinned: middleware.GetCurrentSessionPinned(c),
+		},
+		Query: c.Query("q"),
+	}))
+}
+
+// renderDashboardTitleBookmarks renders the bookmarks title with the tag
+// filter bar: every tag of the current dashboard's bookmarks links to the page
+// with the tag toggled in its "tag" parameters.
+func renderDashboardTitleBookmarks(c fiber.Ctx, deps DashboardDeps, user domainmodel.Identity) error {
+	dashboardID := currentDashboardID(c)
+	categories, err := deps.GetUserCategories.Handle(c.Context(), user.UserID, dashboardID)
+	if err != nil {
+		return err
+	}
+	shelved, err := deps.GetUserShelvedCategories.Handle(c.Context(), user.UserID, dashboardID)
+	if err != nil {
+		return err
+	}
+
+	selected := currentTagFilter(c)
+	names := slices.Clone(selected)
+	for _, category := range domainmodel.FlattenCategories(append(categories, shelved...)) {
+		for _, bookmark := range category.Bookmarks {
+			names = append(names, bookmark.Tags...)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	path := "/"
+	if u, err := url.Parse(c.Get("HX-Current-URL")); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	filterURL := func(tags []string) string {
+		if len(tags) == 0 {
+			return path
+		}
+		return path + "?" + url.Values{"tag": tags}.Encode()
+	}
+
+	input := partials.DashboardTitleBookmarksInput{}
+	for _, name := range names {
+		isSelected := slices.Contains(selected, name)
+		tags := lo.Without(selected, name)
+		if !isSelected {
+			tags = append(slices.Clone(selected), name)
+		}
+		input.Tags = append(input.Tags, partials.DashboardTitleBookmarksInputTag{
+			Name:       name,
+			URL:        filterURL(tags),
+			IsSelected: isSelected,
+		})
+	}
+	if len(selected) > 0 {
+		input.ClearURL = filterURL(nil)
+	}
+	return middleware.Render(c, partials.DashboardTitleBookmarks(input))
+}
+
+// renderDashboardTabs renders the navigation between the user's dashboards,
+// marking the one the request was sent from.
+func renderDashboardTabs(c fiber.Ctx, deps DashboardDeps, user domainmodel.Identity, editMode bool) error {
+	dashboards, err := deps.ListUserDashboards.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	current := currentDashboardID(c)
+	tabs := make([]partials.DashboardTabsInputTab, 0, len(dashboards))
+	for _, d := range dashboards {
+		tabURL := "/"
+		if !d.IsDefault {
+			if tabURL, err = c.GetRouteURL(DashboardPageRoute, fiber.Map{"id": d.ID}); err != nil {
+				return err
+			}
+		}
+		tabs = append(tabs, partials.DashboardTabsInputTab{
+			ID:        d.ID,
+			Name:      d.Name,
+			URL:       tabURL,
+			IsCurrent: d.ID == current || (current == 0 && d.IsDefault),
+			IsDefault: d.IsDefault,
+		})
+	}
+	return middleware.Render(c, partials.DashboardTabs(partials.DashboardTabsInput{
+		Tabs:     tabs,
+		EditMode: editMode,
+	}))
+}
+
+// findUserDashboard looks up the dashboard named by the ":id" route parameter
+// among the user's dashboards.
+func findUserDashboard(c fiber.Ctx, deps DashboardDeps, user domainmodel.Identity) (domainmodel.DashboardPage, error) {
+	id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return domainmodel.DashboardPage{}, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+	}
+	dashboards, err := deps.ListUserDashboards.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return domainmodel.DashboardPage{}, err
+	}
+	dashboard, ok := lo.Find(dashboards, func(d domainmodel.DashboardPage) bool { return d.ID == uint(id64) })
+	if !ok {
+		return domainmodel.DashboardPage{}, fiber.NewError(fiber.StatusNotFound, "not found")
+	}
+	return dashboard, nil
+}
+
+// currentDashboardID returns the dashboard an HTMX request was sent from, read
+// from the page URL in HX-Current-URL. 0 stands for the default dashboard at
+// "/". The use cases check that the user owns the dashboard.
+func currentDashboardID(c fiber.Ctx) uint {
+	u, err := url.Parse(c.Get("HX-Current-URL"))
+	if err != nil {
+		return 0
+	}
+	raw, ok := strings.CutPrefix(u.Path, "/dashboards/")
+	if !ok {
+		return 0
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+// currentTagFilter returns the normalized tags selected in the filter bar,
+// read from the "tag" parameters of the page URL in HX-Current-URL. Invalid
+// tags are ignored.
+func currentTagFilter(c fiber.Ctx) []string {
+	u, err := url.Parse(c.Get("HX-Current-URL"))
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, raw := range u.Query()["tag"] {
+		if tag, err := domainmodel.NormalizeTag(raw); err == nil {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// filterCategoryByTags keeps the bookmarks of category and its sub-categories
+// that carry all of tags and reports whether anything is left to show.
+// Without tags the category is kept as it is.
+func filterCategoryByTags(category domainmodel.Category, tags []string) (domainmodel.Category, bool) {
+	if len(tags) == 0 {
+		return category, true
+	}
+	query := domainmodel.TagsQuery(tags)
+	category.Bookmarks = lo.Filter(category.Bookmarks, func(bookmark domainmodel.Bookmark, _ int) bool {
+		return query.Matches(bookmark.Tags)
+	})
+	category.Children = lo.FilterMap(category.Children, func(child domainmodel.Category, _ int) (domainmodel.Category, bool) {
+		return filterCategoryByTags(child, tags)
+	})
+	return category, len(category.Bookmarks) > 0 || len(category.Children) > 0
 }
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"strconv"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
+	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	IconFaviconRoute = "IconFaviconRoute"
+	IconCustomRoute  = "IconCustomRoute"
+)
+
+type IconDeps struct {
+	SessionStore  *oidc.SessionStore
+	App           *fiber.App
+	GetFavicon    query.FaviconGetter
+	GetCustomIcon query.CustomIconGetter
+}
+
+// Icon registers the routes that serve cached site favicons for icons of type
+// "favicon" and library images for icons of type "img". Only signed-in users
+// can trigger fetches, so Dash cannot be used as an open image proxy.
+func Icon(deps IconDeps) {
+	deps.App.Get("/icons/favicon", middleware.LoadUserFromSession(deps.SessionStore), func(c fiber.Ctx) error {
+		if _, authorized := middleware.GetCurrentUser(c); !authorized {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		favicon, err := deps.GetFavicon.Handle(c.Context(), c.Query("site"))
+		if err != nil {
+			return httpError(err)
+		}
+
+		return sendIcon(c, favicon.ContentType, favicon.Data)
+	}).Name(IconFaviconRoute)
+
+	deps.App.Get("/icons/img/:id", middleware.LoadUserFromSession(deps.SessionStore), func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+
+		id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+
+		icon, err := deps.GetCustomIcon.Handle(c.Context(), user.UserID, uint(id64))
+		if err != nil {
+			return httpError(err)
+		}
+
+		return sendIcon(c, icon.ContentType, icon.Data)
+	}).Name(IconCustomRoute)
+}
+
+// sendIcon sends an icon image. SVG icons are third-party markup served from
+// our origin, so they must never run scripts even when opened directly.
+func sendIcon(c fiber.Ctx, contentType string, data []byte) error {
+	c.Set("Content-Type", contentType)
+	c.Set("Cache-Control", "private, max-age=86400")
+	c.Set("X-Content-Type-Options", "nosniff")
+	c.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src data:; sandbox")
+	return c.Send(data)
+}
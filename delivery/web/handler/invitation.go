@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/app/query"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/layout"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/page"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+const (
+	InvitationRoute       = "InvitationRoute"
+	InvitationRedeemRoute = "InvitationRedeemRoute"
+)
+
+type InvitationDeps struct {
+	SessionStore     *oidc.SessionStore
+	App              *fiber.App
+	GetInvitation    query.InvitationGetter
+	RedeemInvitation command.InvitationRedeemer
+}
+
+// Invitation registers the plain HTTP route guests open from an invitation
+// link. Visitors without a session are sent to the login page first and come
+// back to the link afterwards, so any configured provider can be used.
+// Must be called BEFORE any handler that invokes router.Use(HtmxOnly).
+func Invitation(deps InvitationDeps) {
+	// Opening the link only shows what the invitation grants. Link previews
+	// and prefetchers follow GET requests, so redeeming here would let them
+	// use up the invitation.
+	deps.App.Get("/invite/:token", middleware.LoadUserFromSession(deps.SessionStore), func(c fiber.Ctx) error {
+		if _, authorized := middleware.GetCurrentUser(c); !authorized {
+			return redirectToLogin(c)
+		}
+
+		invitation, err := deps.GetInvitation.Handle(c.Context(), c.Params("token"))
+		if err != nil {
+			return httpError(err)
+		}
+		return renderInvitation(c, invitation)
+	}).Name(InvitationRoute)
+
+	// Redeeming requires the HX-Request header like every other mutation.
+	// Browsers only send it cross-origin after a CORS preflight, which Dash
+	// never grants, so other sites cannot redeem on behalf of the user.
+	deps.App.Post("/invite/:token", middleware.LoadUserFromSession(deps.SessionStore), middleware.HtmxOnly, func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return redirectToLogin(c)
+		}
+
+		if err := deps.RedeemInvitation.Handle(c.Context(), auditActor(c, user), c.Params("token")); err != nil {
+			return httpError(err)
+		}
+
+		// The session store merges the granted groups into the identity on the
+		// next request, so the dashboard already shows the shared categories.
+		c.Set("HX-Redirect", "/")
+		return c.SendStatus(fiber.StatusNoContent)
+	}).Name(InvitationRedeemRoute)
+}
+
+func renderInvitation(c fiber.Ctx, invitation domainmodel.Invitation) error {
+	resolvedLang := "en"
+	if locale := ctxi18n.Locale(c.Context()); locale != nil {
+		resolvedLang = locale.Code().String()
+	}
+	def := domainmodel.DefaultTheme()
+
+	input := page.InvitationInput{
+		BaseInput: layout.BaseInput{
+			Title:    i18n.T(c.Context(), "invite.title"),
+			Language: resolvedLang,
+			Theme: layout.Theme{
+				Primary:   def.Primary,
+				Secondary: def.Secondary,
+				Tertiary:  def.Tertiary,
+			},
+		},
+		Groups:    invitation.Groups,
+		GrantDays: int(invitation.GrantDuration / (24 * time.Hour)),
+	}
+	if !invitation.IsExpired(time.Now()) && !invitation.IsUsedUp() {
+		acceptURL, err := c.GetRouteURL(InvitationRedeemRoute, fiber.Map{"token": c.Params("token")})
+		if err != nil {
+			return err
+		}
+		input.AcceptURL = acceptURL
+	}
+	return middleware.Render(c, page.Invitation(input))
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"encoding/xml"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Method   string `xml:"method,attr"`
+	Template string `xml:"template,attr"`
+}
+
+type openSearchDescription struct {
+	XMLName       xml.Name      `xml:"http://a9.com/-/spec/opensearch/1.1/ OpenSearchDescription"`
+	ShortName     string        `xml:"ShortName"`
+	Description   string        `xml:"Description"`
+	InputEncoding string        `xml:"InputEncoding"`
+	Url           openSearchURL `xml:"Url"`
+}
+
+// OpenSearch registers /opensearch.xml, which lets browsers add Dash as a
+// search engine. Queries land on /search, which needs a session anyway, so
+// the document itself is public.
+func OpenSearch(app *fiber.App) {
+	app.Get("/opensearch.xml", func(c fiber.Ctx) error {
+		data, err := xml.Marshal(openSearchDescription{
+			ShortName:     "Dash",
+			Description:   "Search your dashboard and the web",
+			InputEncoding: "UTF-8",
+			Url: openSearchURL{
+				Type:     "text/html",
+				Method:   "get",
+				Template: c.BaseURL() + "/search?q={searchTerms}",
+			},
+		})
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to render opensearch description")
+		}
+
+		c.Set("Content-Type", "application/opensearchdescription+xml")
+		c.Set("Cache-Control", "public, max-age=3600")
+		return c.Send(append([]byte(xml.Header), data...))
+	})
+}
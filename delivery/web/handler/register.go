@@ -19,12 +19,21 @@ type BuildInfo struct {
 func RegisterAll(
 	fiberApp *fiber.App,
 	sessionStore *oidc.SessionStore,
-	oidcProvider *oidc.Provider,
+	oidcProviders *oidc.Providers,
+	tokenLoader middleware.IdentityLoader,
+	forwardAuth *ForwardAuth,
+	localAuth *LocalAuth,
 	uc *app.UseCases,
 	buildInfo BuildInfo,
 ) {
 	Health(fiberApp)
 
+	// Forward auth starts or resumes a cookie session for the proxy user, so
+	// the route groups below load the same identity from the session store.
+	if forwardAuth != nil {
+		fiberApp.Use(middleware.LoadUserFromSession(forwardAuth.Loader))
+	}
+
 	// Language middleware runs globally — resolves locale from user settings or Accept-Language header
 	// and stores it in the request user context for all Templ templates.
 	fiberApp.Use(middleware.WithLanguage(sessionStore, uc.GetUserSettings))
@@ -36,15 +45,74 @@ func RegisterAll(
 		BuildInfo:      buildInfo,
 	})
 
-	Session(fiberApp, oidcProvider, sessionStore, uc.CreateSession, uc.RefreshSession, uc.TerminateSession, uc.MigrateUserID, uc.ResolveOrCreateUser)
+	Invitation(InvitationDeps{
+		SessionStore:     sessionStore,
+		App:              fiberApp,
+		GetInvitation:    uc.GetInvitation,
+		RedeemInvitation: uc.RedeemInvitation,
+	})
+
+	Api(ApiDeps{
+		SessionStore:             sessionStore,
+		TokenLoader:              tokenLoader,
+		App:                      fiberApp,
+		GetUserDashboard:         uc.GetUserDashboard,
+		ListUserDashboards:       uc.ListUserDashboards,
+		SearchUserDashboard:      uc.SearchUserDashboard,
+		GetUserShelvedCategories: uc.GetUserShelvedCategories,
+		GetUserCategory:          uc.GetUserCategory,
+		GetUserBookmark:          uc.GetUserBookmark,
+		GetUserSettings:          uc.GetUserSettings,
+		ListUserThemes:           uc.ListUserThemes,
+		ListSearchProviders:      uc.ListUserSearchProviders,
+		CategoryCreate:           uc.CreateUserCategory,
+		CategoryUpdate:           uc.UpdateUserCategory,
+		CategoryDelete:           uc.DeleteUserCategory,
+		BookmarkCreate:           uc.CreateUserBookmark,
+		BookmarkUpdate:           uc.UpdateUserBookmark,
+		BookmarkDelete:           uc.DeleteUserBookmark,
+		UpdateUserSettings:       uc.UpdateUserSettings,
+	})
+
+	Session(SessionDeps{
+		App:                 fiberApp,
+		Providers:           oidcProviders,
+		SessionStore:        sessionStore,
+		ForwardAuth:         forwardAuth,
+		LocalAuth:           localAuth,
+		CreateSession:       uc.CreateSession,
+		RefreshSession:      uc.RefreshSession,
+		TerminateSession:    uc.TerminateSession,
+		EndIdpSession:       uc.EndIdpSession,
+		MigrateUserID:       uc.MigrateUserID,
+		ResolveOrCreateUser: uc.ResolveOrCreateUser,
+		LinkIdpIdentity:     uc.LinkIdpIdentity,
+		RecordAudit:         uc.RecordAudit,
+	})
 	Favicon(sessionStore, fiberApp)
+	OpenSearch(fiberApp)
+	Icon(IconDeps{
+		SessionStore:  sessionStore,
+		App:           fiberApp,
+		GetFavicon:    uc.GetFavicon,
+		GetCustomIcon: uc.GetCustomIcon,
+	})
 
 	Dashboard(DashboardDeps{
-		SessionStore:     sessionStore,
-		App:              fiberApp,
-		GetUserDashboard: uc.GetUserDashboard,
-		GetUserSettings:  uc.GetUserSettings,
-		GetUserThemeByID: uc.GetUserThemeByID,
+		SessionStore:             sessionStore,
+		App:                      fiberApp,
+		GetUserDashboard:         uc.GetUserDashboard,
+		SearchUserDashboard:      uc.SearchUserDashboard,
+		GetUserCategories:        uc.GetUserCategories,
+		GetUserShelvedCategories: uc.GetUserShelvedCategories,
+		ResolveWebSearch:         uc.ResolveUserWebSearch,
+		GetUserSettings:          uc.GetUserSettings,
+		GetUserThemeByID:         uc.GetUserThemeByID,
+		ListUserDashboards:       uc.ListUserDashboards,
+		DashboardCreate:          uc.CreateUserDashboard,
+		DashboardUpdate:          uc.UpdateUserDashboard,
+		DashboardDelete:          uc.DeleteUserDashboard,
+		DashboardReorder:         uc.ReorderUserDashboards,
 	})
 
 	Application(ApplicationDeps{
@@ -53,6 +121,7 @@ func RegisterAll(
 		CreateApplication:     uc.CreateApplication,
 		DeleteApplication:     uc.DeleteApplication,
 		UpdateApplication:     uc.UpdateApplication,
+		ReorderApplications:   uc.ReorderApplications,
 		GetUserApplications:   uc.GetUserApplications,
 		ListApplications:      uc.ListApplications,
 		GetApplication:        uc.GetApplication,
@@ -68,6 +137,18 @@ func RegisterAll(
 		CategoryCreate:           uc.CreateUserCategory,
 		CategoryUpdate:           uc.UpdateUserCategory,
 		CategoryDelete:           uc.DeleteUserCategory,
+		CategoryReorder:          uc.ReorderUserCategories,
+		GetSharedCategories:      uc.GetSharedCategories,
+		ListSharedCategories:     uc.ListSharedCategories,
+		PublishCategory:          uc.PublishCategory,
+		UnpublishCategory:        uc.UnpublishCategory,
+		HideSharedCategory:       uc.HideSharedCategory,
+		GetReceivedCategories:    uc.GetReceivedCategories,
+		ListCategoryShares:       uc.ListCategoryShares,
+		ShareCategory:            uc.ShareCategory,
+		RevokeCategoryShare:      uc.RevokeCategoryShare,
+		LeaveCategoryShare:       uc.LeaveCategoryShare,
+		ListUserDashboards:       uc.ListUserDashboards,
 	})
 
 	Bookmark(BookmarkDeps{
@@ -80,23 +161,65 @@ func RegisterAll(
 		BookmarkCreate:           uc.CreateUserBookmark,
 		BookmarkUpdate:           uc.UpdateUserBookmark,
 		BookmarkDelete:           uc.DeleteUserBookmark,
+		BookmarkReorder:          uc.ReorderUserBookmarks,
 		GetAvailableIconTypes:    uc.GetAvailableIconTypes,
+		GetReceivedCategories:    uc.GetReceivedCategories,
+		ListUserTags:             uc.ListUserTags,
 	})
 
 	Setting(SettingDeps{
-		SessionStore:        sessionStore,
-		App:                 fiberApp,
-		GetUserSettings:     uc.GetUserSettings,
-		UpdateUserSettings:  uc.UpdateUserSettings,
-		ListUserThemes:      uc.ListUserThemes,
-		ExportUserData:      uc.ExportUserData,
-		DeleteUserData:      uc.DeleteUserData,
-		ImportUserData:      uc.ImportUserData,
-		GetSessionsOverview: uc.GetSessionsOverview,
-		PinSession:          uc.PinSession,
-		UnpinSession:        uc.UnpinSession,
-		InvalidateSession:   uc.InvalidateSession,
-		BuildInfo:           buildInfo,
+		SessionStore:            sessionStore,
+		App:                     fiberApp,
+		GetUserSettings:         uc.GetUserSettings,
+		UpdateUserSettings:      uc.UpdateUserSettings,
+		ListUserThemes:          uc.ListUserThemes,
+		ExportUserData:          uc.ExportUserData,
+		DeleteUserData:          uc.DeleteUserData,
+		ImportUserData:          uc.ImportUserData,
+		ImportUserBookmarks:     uc.ImportUserBookmarks,
+		GetSessionsOverview:     uc.GetSessionsOverview,
+		PinSession:              uc.PinSession,
+		UnpinSession:            uc.UnpinSession,
+		InvalidateSession:       uc.InvalidateSession,
+		ListAccessTokens:        uc.ListAccessTokens,
+		CreateAccessToken:       uc.CreateAccessToken,
+		RevokeAccessToken:       uc.RevokeAccessToken,
+		ListSearchProviders:     uc.ListUserSearchProviders,
+		CreateSearchProvider:    uc.CreateUserSearchProvider,
+		DeleteSearchProvider:    uc.DeleteUserSearchProvider,
+		ListCustomIcons:         uc.ListCustomIcons,
+		UploadCustomIcon:        uc.UploadCustomIcon,
+		DeleteCustomIcon:        uc.DeleteCustomIcon,
+		ListIdpLinks:            uc.ListIdpLinks,
+		UnlinkIdpIdentity:       uc.UnlinkIdpIdentity,
+		ListLocalAccounts:       uc.ListLocalAccounts,
+		CreateLocalAccount:      uc.CreateLocalAccount,
+		SetLocalAccountPassword: uc.SetLocalAccountPassword,
+		DeleteLocalAccount:      uc.DeleteLocalAccount,
+		LocalAccounts:           localAuth != nil,
+		ListUsers:               uc.ListUsers,
+		RevokeUserSessions:      uc.RevokeUserSessions,
+		DeleteUser:              uc.DeleteUser,
+		ListLocalGroups:         uc.ListLocalGroups,
+		CreateLocalGroup:        uc.CreateLocalGroup,
+		UpdateLocalGroup:        uc.UpdateLocalGroup,
+		DeleteLocalGroup:        uc.DeleteLocalGroup,
+		AddLocalGroupMember:     uc.AddLocalGroupMember,
+		RemoveLocalGroupMember:  uc.RemoveLocalGroupMember,
+		ListInvitations:         uc.ListInvitations,
+		CreateInvitation:        uc.CreateInvitation,
+		DeleteInvitation:        uc.DeleteInvitation,
+		RevokeInvitationGrant:   uc.RevokeInvitationRedemption,
+		GetSharedCategories:     uc.GetSharedCategories,
+		ListSharedCategories:    uc.ListSharedCategories,
+		UnpublishCategory:       uc.UnpublishCategory,
+		HideSharedCategory:      uc.HideSharedCategory,
+		ShowSharedCategory:      uc.ShowSharedCategory,
+		GetReceivedCategories:   uc.GetReceivedCategories,
+		LeaveCategoryShare:      uc.LeaveCategoryShare,
+		ListAuditLog:            uc.ListAuditLog,
+		Providers:               oidcProviders,
+		BuildInfo:               buildInfo,
 	})
 
 	Theme(ThemeDeps{
@@ -1,86 +1,233 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/url"
+	"strings"
+	"time"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/middleware"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/layout"
+	"git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/page"
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	"git.at.oechsler.it/samuel/dash/v2/infra/localauth"
 	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/i18n"
 )
 
 const (
 	SessionLoginRoute          = "SessionLoginRoute"
+	SessionLocalLoginRoute     = "SessionLocalLoginRoute"
 	SessionLoginCallbackRoute  = "SessionLoginCallbackRoute"
 	SessionLogoutRoute         = "SessionLogoutRoute"
 	SessionLogoutCallbackRoute = "SessionLogoutCallbackRoute"
+	SessionBackChannelRoute    = "SessionBackChannelRoute"
+	SessionFrontChannelRoute   = "SessionFrontChannelRoute"
 	SessionRefreshRoute        = "SessionRefreshRoute"
+	SessionLinkRoute           = "SessionLinkRoute"
 )
 
-func Session(
-	app *fiber.App,
-	provider *oidc.Provider,
-	store *oidc.SessionStore,
-	createSession command.SessionCreator,
-	refreshSession command.SessionRefresher,
-	terminateSession command.SessionTerminator,
-	migrateUserID command.UserIDMigrator,
-	resolveOrCreateUser command.UserResolver,
-) {
-	router := app.Group("/session")
+// ForwardAuth configures sign-in through an authenticating reverse proxy.
+type ForwardAuth struct {
+	Loader    middleware.IdentityLoader
+	Issuer    string // issuer recorded for proxy sessions and IdP links
+	LoginURL  string // optional; where /session/login sends users without OIDC
+	LogoutURL string // optional; proxy logout endpoint
+}
+
+// LocalAuthenticator checks the passwords of local accounts.
+// localauth.Authenticator implements this interface.
+type LocalAuthenticator interface {
+	Authenticate(ctx context.Context, ip, username, password string) (domainmodel.Identity, error)
+	Identity(ctx context.Context, username string) (domainmodel.Identity, error)
+}
 
+// LocalAuth configures sign-in with local accounts.
+type LocalAuth struct {
+	Authenticator LocalAuthenticator
+	Issuer        string        // issuer recorded for local sessions and IdP links
+	SessionTTL    time.Duration // lifetime of a session that is not pinned
+}
+
+type SessionDeps struct {
+	App                 *fiber.App
+	Providers           *oidc.Providers
+	SessionStore        *oidc.SessionStore
+	ForwardAuth         *ForwardAuth // nil = forward auth disabled
+	LocalAuth           *LocalAuth   // nil = local accounts disabled
+	CreateSession       command.SessionCreator
+	RefreshSession      command.SessionRefresher
+	TerminateSession    command.SessionTerminator
+	EndIdpSession       command.IdpSessionEnder
+	MigrateUserID       command.UserIDMigrator
+	ResolveOrCreateUser command.UserResolver
+	LinkIdpIdentity     command.IdpIdentityLinker
+	RecordAudit         command.AuditRecorder
+}
+
+func Session(deps SessionDeps) {
+	router := deps.App.Group("/session")
+
+	// Login: with local accounts or several deps.Providers configured and none
+	// chosen yet, show the login page; otherwise start the authorization code flow.
 	router.Get("/login", func(c fiber.Ctx) error {
-		state, err := oidc.GenerateState()
-		if err != nil {
+		providerID := c.Query("provider")
+		if providerID == "" && (deps.LocalAuth != nil || len(deps.Providers.List()) > 1) {
+			return renderLogin(c, deps, c.Query("rd", "/"), "", "")
+		}
+
+		provider := deps.Providers.Default()
+		if providerID != "" {
+			p, ok := deps.Providers.Get(providerID)
+			if !ok {
+				return fiber.NewError(fiber.StatusNotFound, "unknown provider")
+			}
+			provider = p
+		}
+
+		// Without OIDC, signing in is up to the authentication proxy.
+		if provider == nil {
+			if deps.ForwardAuth != nil && deps.ForwardAuth.LoginURL != "" {
+				return c.Redirect().Status(fiber.StatusFound).To(deps.ForwardAuth.LoginURL)
+			}
+			return fiber.NewError(fiber.StatusUnauthorized, "not signed in by the authentication proxy")
+		}
+
+		return beginAuth(c, deps.SessionStore, provider, oidc.StateCookie{
+			ReturnTo: c.Query("rd", "/"),
+		})
+	}).Name(SessionLoginRoute)
+
+	// Local login: checks the password of a local account and starts a
+	// session. Failed attempts render the login page again with an error.
+	router.Post("/login", func(c fiber.Ctx) error {
+		if deps.LocalAuth == nil {
+			return fiber.ErrNotFound
+		}
+		// A form posted by another site must not sign the browser in to an
+		// account chosen by that site.
+		if c.Get("Sec-Fetch-Site") == "cross-site" {
+			return fiber.NewError(fiber.StatusForbidden, "forbidden")
+		}
+
+		var body struct {
+			Username string `form:"username"`
+			Password string `form:"password"`
+			ReturnTo string `form:"rd"`
+		}
+		if err := c.Bind().Body(&body); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+		}
+		returnTo := localReturnTo(body.ReturnTo)
+
+		identity, err := deps.LocalAuth.Authenticator.Authenticate(c.Context(), c.IP(), body.Username, body.Password)
+		switch {
+		case errors.Is(err, localauth.ErrThrottled):
+			c.Status(fiber.StatusTooManyRequests)
+			return renderLogin(c, deps, returnTo, body.Username, "login.throttled")
+		case errors.Is(err, localauth.ErrInvalidCredentials):
+			// Nobody is signed in yet; the attempted username is the target.
+			deps.RecordAudit.Handle(c.Context(), domainmodel.AuditActor{IP: c.IP()}, domainmodel.AuditActionLoginFailed, domainmodel.NormalizeUsername(body.Username))
+			c.Status(fiber.StatusUnauthorized)
+			return renderLogin(c, deps, returnTo, body.Username, "login.invalid_credentials")
+		case err != nil:
 			return err
 		}
-		codeVerifier, err := oidc.GenerateCodeVerifier()
+
+		userID, _, err := deps.ResolveOrCreateUser.Handle(c.Context(), deps.LocalAuth.Issuer, identity.Username)
 		if err != nil {
 			return err
 		}
+		identity.UserID = userID
 
-		if err := store.SaveStateCookie(c, oidc.StateCookie{
-			State:        state,
-			CodeVerifier: codeVerifier,
-			ReturnTo:     c.Query("rd", "/"),
-		}); err != nil {
+		sessionData, err := deps.SessionStore.Save(c)
+		if err != nil {
 			return err
 		}
 
-		return c.Redirect().Status(fiber.StatusFound).To(provider.BeginAuth(state, codeVerifier))
-	}).Name(SessionLoginRoute)
+		// Same as the OIDC callback: a DB hiccup must not prevent login.
+		if deps.CreateSession != nil {
+			now := time.Now()
+			_ = deps.CreateSession.Handle(c.Context(), command.CreateSessionCmd{
+				SessionID:   sessionData.SessionID,
+				UserID:      identity.UserID,
+				Issuer:      deps.LocalAuth.Issuer,
+				Sub:         identity.Username,
+				Username:    identity.Username,
+				Email:       identity.Email,
+				FirstName:   identity.FirstName,
+				LastName:    identity.LastName,
+				DisplayName: identity.DisplayName,
+				Groups:      identity.Groups,
+				IsAdmin:     identity.IsAdmin,
+				IssuedAt:    now,
+				ExpiresAt:   now.Add(deps.LocalAuth.SessionTTL),
+				IP:          c.IP(),
+				UserAgent:   c.Get("User-Agent"),
+			})
+		}
+
+		return c.Redirect().Status(fiber.StatusSeeOther).To(returnTo)
+	}).Name(SessionLocalLoginRoute)
+
+	// Link: signs in with another provider identity and links it to the
+	// current account instead of starting a new session. Only browser sessions
+	// may link identities, access tokens are not accepted here.
+	router.Get("/link", func(c fiber.Ctx) error {
+		user, ok := deps.SessionStore.LoadIdentity(c)
+		if !ok {
+			return redirectToLogin(c)
+		}
+
+		provider, ok := deps.Providers.Get(c.Query("provider"))
+		if !ok {
+			return fiber.NewError(fiber.StatusNotFound, "unknown provider")
+		}
+
+		return beginAuth(c, deps.SessionStore, provider, oidc.StateCookie{
+			ReturnTo:   c.Query("rd", "/"),
+			LinkUserID: user.UserID,
+		})
+	}).Name(SessionLinkRoute)
 
 	// Refresh: re-authenticates via OIDC to update identity on the current pinned session.
 	// The existing session record is updated in-place — no new SessionID is issued.
 	router.Get("/refresh", func(c fiber.Ctx) error {
-		sessionData, ok := store.LoadExpired(c)
+		sessionData, ok := deps.SessionStore.LoadExpired(c)
 		if !ok || sessionData.SessionID == "" {
 			return redirectToLogin(c)
 		}
 
-		state, err := oidc.GenerateState()
-		if err != nil {
-			return err
+		// Re-authenticate with the provider the session was signed in with.
+		// Proxy sessions are kept up to date by the forward auth loader, so
+		// there is nothing to refresh. Local sessions re-read their account.
+		provider := deps.Providers.Default()
+		if sessionRepo := deps.SessionStore.SessionRepo(); sessionRepo != nil {
+			if record, _ := sessionRepo.Touch(c.Context(), sessionData.SessionID, c.IP(), c.Get("User-Agent")); record != nil {
+				if deps.LocalAuth != nil && record.Issuer == deps.LocalAuth.Issuer {
+					return refreshLocalSession(c, deps, record.SessionID, record.Sub)
+				}
+				provider = sessionProvider(deps, record.Issuer)
+			}
 		}
-		codeVerifier, err := oidc.GenerateCodeVerifier()
-		if err != nil {
-			return err
+		returnTo := localReturnTo(c.Query("rd", "/"))
+		if provider == nil {
+			return c.Redirect().Status(fiber.StatusFound).To(returnTo)
 		}
 
-		if err := store.SaveStateCookie(c, oidc.StateCookie{
-			State:            state,
-			CodeVerifier:     codeVerifier,
-			ReturnTo:         c.Query("rd", "/"),
+		return beginAuth(c, deps.SessionStore, provider, oidc.StateCookie{
+			ReturnTo:         returnTo,
 			RefreshSessionID: sessionData.SessionID,
-		}); err != nil {
-			return err
-		}
-
-		return c.Redirect().Status(fiber.StatusFound).To(provider.BeginAuth(state, codeVerifier))
+		})
 	}).Name(SessionRefreshRoute)
 
 	router.Get("/login/callback", func(c fiber.Ctx) error {
-		stateCookie, err := store.LoadAndClearStateCookie(c)
+		stateCookie, err := deps.SessionStore.LoadAndClearStateCookie(c)
 		if err != nil {
 			return redirectToLogin(c)
 		}
@@ -94,29 +241,61 @@ func Session(
 			return fiber.NewError(fiber.StatusBadRequest, "missing code")
 		}
 
-		idToken, rawIDToken, err := provider.Exchange(c.Context(), code, stateCookie.CodeVerifier)
+		provider := deps.Providers.Default()
+		if stateCookie.ProviderID != "" {
+			p, ok := deps.Providers.Get(stateCookie.ProviderID)
+			if !ok {
+				return redirectToLogin(c)
+			}
+			provider = p
+		}
+		if provider == nil {
+			return redirectToLogin(c)
+		}
+
+		result, err := provider.Exchange(c.Context(), code, stateCookie.CodeVerifier)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "token exchange failed")
 		}
+		idToken, rawIDToken := result.IDToken, result.RawIDToken
 
-		identity, err := provider.ClaimsToIdentity(idToken)
-		if err != nil {
-			return fiber.NewError(fiber.StatusUnauthorized, "failed to extract identity")
+		returnTo := stateCookie.ReturnTo
+		if returnTo == "" {
+			returnTo = "/"
+		}
+
+		// Link flow: attach the identity to the account that started the flow.
+		// The session must still belong to that account, otherwise a stale
+		// state cookie could link an identity to someone else.
+		if stateCookie.LinkUserID != "" {
+			user, ok := deps.SessionStore.LoadIdentity(c)
+			if !ok || user.UserID != stateCookie.LinkUserID {
+				return redirectToLogin(c)
+			}
+			if deps.LinkIdpIdentity == nil {
+				return fiber.NewError(fiber.StatusNotImplemented, "account linking is not available")
+			}
+			if err := deps.LinkIdpIdentity.Handle(c.Context(), user.UserID, provider.Issuer(), idToken.Subject); err != nil {
+				return httpError(err)
+			}
+			return c.Redirect().Status(fiber.StatusFound).To(returnTo)
 		}
 
+		identity := provider.ClaimsToIdentity(result.Claims)
+
 		// Resolve the stable internal UserID via the IdP links table.
-		if resolveOrCreateUser != nil {
-			if userID, isNew, err := resolveOrCreateUser.Handle(c.Context(), provider.Issuer(), idToken.Subject); err == nil {
+		if deps.ResolveOrCreateUser != nil {
+			if userID, isNew, err := deps.ResolveOrCreateUser.Handle(c.Context(), provider.Issuer(), idToken.Subject); err == nil {
 				identity.UserID = userID
-				if isNew && migrateUserID != nil {
+				if isNew && deps.MigrateUserID != nil {
 					// First login after the UUID-based UserID scheme was introduced.
 					// Move any pre-existing data from sub and the legacy preferred_username
 					// fallback ID to the new stable UUID.
 					// TODO(v3): remove the legacy migration once all deployments have
 					// gone through at least one login cycle after this upgrade.
-					_ = migrateUserID.Handle(c.Context(), idToken.Subject, userID)
+					_ = deps.MigrateUserID.Handle(c.Context(), idToken.Subject, userID)
 					if legacyID, err := provider.LegacyUserID(idToken); err == nil {
-						_ = migrateUserID.Handle(c.Context(), legacyID, userID)
+						_ = deps.MigrateUserID.Handle(c.Context(), legacyID, userID)
 					}
 				}
 			}
@@ -124,105 +303,117 @@ func Session(
 
 		if stateCookie.RefreshSessionID != "" {
 			// Resolve UserID also on refresh so the stored identity stays consistent.
-			if resolveOrCreateUser != nil {
-				if userID, _, err := resolveOrCreateUser.Handle(c.Context(), provider.Issuer(), idToken.Subject); err == nil {
+			if deps.ResolveOrCreateUser != nil {
+				if userID, _, err := deps.ResolveOrCreateUser.Handle(c.Context(), provider.Issuer(), idToken.Subject); err == nil {
 					identity.UserID = userID
 				}
 			}
 			// Refresh flow: update identity and token timing on the existing session record.
-			if refreshSession != nil {
-				encToken, err := store.EncryptDBToken(rawIDToken)
+			if deps.RefreshSession != nil {
+				encToken, err := deps.SessionStore.EncryptDBToken(rawIDToken)
+				if err != nil {
+					return err
+				}
+				encRefreshToken, err := encryptRefreshToken(deps.SessionStore, result.RefreshToken)
 				if err != nil {
 					return err
 				}
-				_ = refreshSession.Handle(c.Context(), command.RefreshSessionCmd{
-					SessionID:   stateCookie.RefreshSessionID,
-					Sub:         idToken.Subject,
-					Username:    identity.Username,
-					Email:       identity.Email,
-					FirstName:   identity.FirstName,
-					LastName:    identity.LastName,
-					DisplayName: identity.DisplayName,
-					Picture:     ptrStr(identity.Picture),
-					ProfileUrl:  ptrStr(identity.ProfileUrl),
-					RawIDToken:  encToken,
-					Groups:      identity.Groups,
-					IsAdmin:     identity.IsAdmin,
-					IssuedAt:    idToken.IssuedAt,
-					ExpiresAt:   idToken.Expiry,
+				_ = deps.RefreshSession.Handle(c.Context(), command.RefreshSessionCmd{
+					SessionID:    stateCookie.RefreshSessionID,
+					Issuer:       provider.Issuer(),
+					Sub:          idToken.Subject,
+					Sid:          result.Sid,
+					Username:     identity.Username,
+					Email:        identity.Email,
+					FirstName:    identity.FirstName,
+					LastName:     identity.LastName,
+					DisplayName:  identity.DisplayName,
+					Picture:      ptrStr(identity.Picture),
+					ProfileUrl:   ptrStr(identity.ProfileUrl),
+					RawIDToken:   encToken,
+					RefreshToken: encRefreshToken,
+					Groups:       identity.Groups,
+					IsAdmin:      identity.IsAdmin,
+					IssuedAt:     idToken.IssuedAt,
+					ExpiresAt:    idToken.Expiry,
 				})
 			}
-			if _, err := store.SaveWithID(c, stateCookie.RefreshSessionID, true); err != nil {
+			if _, err := deps.SessionStore.SaveWithID(c, stateCookie.RefreshSessionID, true); err != nil {
 				return err
 			}
 
-			returnTo := stateCookie.ReturnTo
-			if returnTo == "" {
-				returnTo = "/"
-			}
 			return c.Redirect().Status(fiber.StatusFound).To(returnTo)
 		}
 
-		sessionData, err := store.Save(c)
+		sessionData, err := deps.SessionStore.Save(c)
 		if err != nil {
 			return err
 		}
 
-		encToken, err := store.EncryptDBToken(rawIDToken)
+		encToken, err := deps.SessionStore.EncryptDBToken(rawIDToken)
+		if err != nil {
+			return err
+		}
+		encRefreshToken, err := encryptRefreshToken(deps.SessionStore, result.RefreshToken)
 		if err != nil {
 			return err
 		}
 
 		// Persist the session to the DB so it appears in the session overview.
 		// Ignore errors — a DB hiccup must not prevent login.
-		if createSession != nil {
-			_ = createSession.Handle(c.Context(), command.CreateSessionCmd{
-				SessionID:   sessionData.SessionID,
-				UserID:      identity.UserID,
-				Sub:         idToken.Subject,
-				Username:    identity.Username,
-				Email:       identity.Email,
-				FirstName:   identity.FirstName,
-				LastName:    identity.LastName,
-				DisplayName: identity.DisplayName,
-				Picture:     ptrStr(identity.Picture),
-				ProfileUrl:  ptrStr(identity.ProfileUrl),
-				RawIDToken:  encToken,
-				Groups:      identity.Groups,
-				IsAdmin:     identity.IsAdmin,
-				IssuedAt:    idToken.IssuedAt,
-				ExpiresAt:   idToken.Expiry,
-				IP:          c.IP(),
-				UserAgent:   c.Get("User-Agent"),
+		if deps.CreateSession != nil {
+			_ = deps.CreateSession.Handle(c.Context(), command.CreateSessionCmd{
+				SessionID:    sessionData.SessionID,
+				UserID:       identity.UserID,
+				Issuer:       provider.Issuer(),
+				Sub:          idToken.Subject,
+				Sid:          result.Sid,
+				Username:     identity.Username,
+				Email:        identity.Email,
+				FirstName:    identity.FirstName,
+				LastName:     identity.LastName,
+				DisplayName:  identity.DisplayName,
+				Picture:      ptrStr(identity.Picture),
+				ProfileUrl:   ptrStr(identity.ProfileUrl),
+				RawIDToken:   encToken,
+				RefreshToken: encRefreshToken,
+				Groups:       identity.Groups,
+				IsAdmin:      identity.IsAdmin,
+				IssuedAt:     idToken.IssuedAt,
+				ExpiresAt:    idToken.Expiry,
+				IP:           c.IP(),
+				UserAgent:    c.Get("User-Agent"),
 			})
 		}
 
-		returnTo := stateCookie.ReturnTo
-		if returnTo == "" {
-			returnTo = "/"
-		}
 		return c.Redirect().Status(fiber.StatusFound).To(returnTo)
 	}).Name(SessionLoginCallbackRoute)
 
 	router.Get("/logout", func(c fiber.Ctx) error {
-		sessionData, ok := store.Load(c)
-		store.Clear(c)
+		sessionData, ok := deps.SessionStore.Load(c)
+		deps.SessionStore.Clear(c)
 
 		var idTokenHint string
-		// Delete the DB record so the session disappears from the overview
-		// and the revocation check denies any lingering cookie on other tabs.
+		provider := deps.Providers.Default()
 		if ok && sessionData.SessionID != "" {
-			if terminateSession != nil {
-				_ = terminateSession.Handle(c.Context(), sessionData.SessionID)
-			}
-			// Load the encrypted id_token from the DB session for id_token_hint.
-			if sessionRepo := store.SessionRepo(); sessionRepo != nil {
-				if record, _ := sessionRepo.Touch(c.Context(), sessionData.SessionID, c.IP(), c.Get("User-Agent")); record != nil && record.RawIDToken != "" {
-					if raw, err := store.DecryptDBToken(record.RawIDToken); err == nil {
-						idTokenHint = raw
+			// Load the encrypted id_token from the DB session for id_token_hint
+			// and the issuer to end the session at the right provider. This must
+			// happen before the record is deleted below.
+			if sessionRepo := deps.SessionStore.SessionRepo(); sessionRepo != nil {
+				if record, _ := sessionRepo.Touch(c.Context(), sessionData.SessionID, c.IP(), c.Get("User-Agent")); record != nil {
+					provider = sessionProvider(deps, record.Issuer)
+					if record.RawIDToken != "" {
+						if raw, err := deps.SessionStore.DecryptDBToken(record.RawIDToken); err == nil {
+							idTokenHint = raw
+						}
 					}
 				}
 			}
+			// Delete the DB record so the session disappears from the overview
+			// and the revocation check denies any lingering cookie on other tabs.
+			if deps.TerminateSession != nil {
+				_ = deps.TerminateSession.Handle(c.Context(), sessionData.SessionID)
+			}
 		}
 
 		logoutCallbackURL, err := c.GetRouteURL(SessionLogoutCallbackRoute, fiber.Map{})
@@ -230,10 +421,15 @@ func Session(
 			return err
 		}
 
-		endSessionURL := provider.EndSessionURL(
-			idTokenHint,
-			c.BaseURL()+logoutCallbackURL,
-		)
+		var endSessionURL string
+		if provider != nil {
+			endSessionURL = provider.EndSessionURL(
+				idTokenHint,
+				c.BaseURL()+logoutCallbackURL,
+			)
+		} else if deps.ForwardAuth != nil {
+			endSessionURL = deps.ForwardAuth.LogoutURL
+		}
 		if endSessionURL == "" {
 			return redirectToLogin(c)
 		}
@@ -244,6 +440,192 @@ func Session(
 	router.Get("/logout/callback", func(c fiber.Ctx) error {
 		return c.Redirect().Status(fiber.StatusFound).To("/")
 	}).Name(SessionLogoutCallbackRoute)
+
+	// Back-channel logout: the IdP posts a signed logout token when the user
+	// signs out there, and every session of that IdP session ends.
+	router.Post("/logout/backchannel", func(c fiber.Ctx) error {
+		c.Set("Cache-Control", "no-store")
+		if deps.EndIdpSession == nil {
+			return c.SendStatus(fiber.StatusNotImplemented)
+		}
+
+		token, err := deps.Providers.VerifyLogoutToken(c.Context(), c.FormValue("logout_token"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":             "invalid_request",
+				"error_description": "invalid logout_token",
+			})
+		}
+		if err := deps.EndIdpSession.Handle(c.Context(), command.EndIdpSessionCmd{
+			Issuer: token.Issuer,
+			Sub:    token.Subject,
+			Sid:    token.SessionID,
+		}); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	}).Name(SessionBackChannelRoute)
+
+	// Front-channel logout: the IdP loads this page in a hidden iframe. The
+	// session cookie is usually not sent in a third-party frame, so the
+	// sessions are found by the iss and sid parameters instead.
+	router.Get("/logout/frontchannel", func(c fiber.Ctx) error {
+		c.Set("Cache-Control", "no-store")
+
+		if iss, sid := c.Query("iss"), c.Query("sid"); sid != "" && deps.EndIdpSession != nil {
+			provider, ok := deps.Providers.ByIssuer(iss)
+			if !ok {
+				return fiber.NewError(fiber.StatusBadRequest, "unknown issuer")
+			}
+			if err := deps.EndIdpSession.Handle(c.Context(), command.EndIdpSessionCmd{
+				Issuer: provider.Issuer(),
+				Sid:    sid,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Where the cookie does arrive, end that session as well.
+		if sessionData, ok := deps.SessionStore.LoadExpired(c); ok && sessionData.SessionID != "" {
+			if deps.TerminateSession != nil {
+				_ = deps.TerminateSession.Handle(c.Context(), sessionData.SessionID)
+			}
+			deps.SessionStore.Clear(c)
+		}
+
+		c.Type("html", "utf-8")
+		return c.SendString("<!DOCTYPE html><title>Signed out</title>")
+	}).Name(SessionFrontChannelRoute)
+}
+
+// sessionProvider returns the provider a session was signed in with. Sessions
+// from before multi-provider support have no issuer and belong to the primary
+// provider; proxy and local sessions have none and yield nil.
+func sessionProvider(deps SessionDeps, issuer string) *oidc.Provider {
+	if deps.ForwardAuth != nil && issuer == deps.ForwardAuth.Issuer {
+		return nil
+	}
+	if deps.LocalAuth != nil && issuer == deps.LocalAuth.Issuer {
+		return nil
+	}
+	if p, ok := deps.Providers.ByIssuer(issuer); ok {
+		return p
+	}
+	return deps.Providers.Default()
+}
+
+// refreshLocalSession updates a local session with the current account data
+// and extends it. Sessions of deleted accounts are ended.
+func refreshLocalSession(c fiber.Ctx, deps SessionDeps, sessionID, username string) error {
+	identity, err := deps.LocalAuth.Authenticator.Identity(c.Context(), username)
+	if err != nil {
+		if deps.TerminateSession != nil {
+			_ = deps.TerminateSession.Handle(c.Context(), sessionID)
+		}
+		deps.SessionStore.Clear(c)
+		return redirectToLogin(c)
+	}
+
+	if deps.RefreshSession != nil {
+		now := time.Now()
+		_ = deps.RefreshSession.Handle(c.Context(), command.RefreshSessionCmd{
+			SessionID:   sessionID,
+			Issuer:      deps.LocalAuth.Issuer,
+			Sub:         identity.Username,
+			Username:    identity.Username,
+			Email:       identity.Email,
+			FirstName:   identity.FirstName,
+			LastName:    identity.LastName,
+			DisplayName: identity.DisplayName,
+			Groups:      identity.Groups,
+			IsAdmin:     identity.IsAdmin,
+			IssuedAt:    now,
+			ExpiresAt:   now.Add(deps.LocalAuth.SessionTTL),
+		})
+	}
+	return c.Redirect().Status(fiber.StatusFound).To(localReturnTo(c.Query("rd", "/")))
+}
+
+// localReturnTo only allows paths on this site as the target after a login or
+// session refresh, so neither can be used to send users elsewhere.
+func localReturnTo(returnTo string) string {
+	if !strings.HasPrefix(returnTo, "/") || strings.HasPrefix(returnTo, "//") || strings.HasPrefix(returnTo, "/\\") {
+		return "/"
+	}
+	return returnTo
+}
+
+// beginAuth stores the in-flight state for the callback and redirects to the
+// provider's authorization endpoint.
+func beginAuth(c fiber.Ctx, store *oidc.SessionStore, provider *oidc.Provider, stateCookie oidc.StateCookie) error {
+	state, err := oidc.GenerateState()
+	if err != nil {
+		return err
+	}
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return err
+	}
+
+	stateCookie.State = state
+	stateCookie.CodeVerifier = codeVerifier
+	stateCookie.ProviderID = provider.ID()
+	if err := store.SaveStateCookie(c, stateCookie); err != nil {
+		return err
+	}
+
+	return c.Redirect().Status(fiber.StatusFound).To(provider.BeginAuth(state, codeVerifier))
+}
+
+// renderLogin renders the login page: the local account form, if enabled,
+// and a button for every identity provider. errKey is an i18n key shown above
+// the form after a failed attempt.
+func renderLogin(c fiber.Ctx, deps SessionDeps, returnTo, username, errKey string) error {
+	loginURL, err := c.GetRouteURL(SessionLoginRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	resolvedLang := "en"
+	if locale := ctxi18n.Locale(c.Context()); locale != nil {
+		resolvedLang = locale.Code().String()
+	}
+	def := domainmodel.DefaultTheme()
+
+	input := page.LoginInput{
+		BaseInput: layout.BaseInput{
+			Title:    i18n.T(c.Context(), "login.title"),
+			Language: resolvedLang,
+			Theme: layout.Theme{
+				Primary:   def.Primary,
+				Secondary: def.Secondary,
+				Tertiary:  def.Tertiary,
+			},
+		},
+		ReturnTo: returnTo,
+		Username: username,
+	}
+	if errKey != "" {
+		input.Error = i18n.T(c.Context(), errKey)
+	}
+	if deps.LocalAuth != nil {
+		localLoginURL, err := c.GetRouteURL(SessionLocalLoginRoute, fiber.Map{})
+		if err != nil {
+			return err
+		}
+		input.LocalLoginURL = localLoginURL
+	}
+	for _, p := range deps.Providers.List() {
+		q := url.Values{}
+		q.Set("provider", p.ID())
+		q.Set("rd", returnTo)
+		input.Providers = append(input.Providers, page.LoginProvider{
+			Name: p.Name(),
+			URL:  loginURL + "?" + q.Encode(),
+		})
+	}
+
+	return middleware.Render(c, page.Login(input))
 }
 
 func redirectToLogin(c fiber.Ctx) error {
@@ -274,6 +656,15 @@ func redirectToLogin(c fiber.Ctx) error {
 	return c.Redirect().Status(fiber.StatusFound).To(loginURL)
 }
 
+// encryptRefreshToken encrypts a refresh token for the session record. An
+// absent token stays empty so the session is not picked up for silent refresh.
+func encryptRefreshToken(store *oidc.SessionStore, refreshToken string) (string, error) {
+	if refreshToken == "" {
+		return "", nil
+	}
+	return store.EncryptDBToken(refreshToken)
+}
+
 // ptrStr dereferences a *string, returning "" for nil.
 func ptrStr(p *string) string {
 	if p == nil {
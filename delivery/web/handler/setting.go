@@ -3,6 +3,9 @@ package handler
 import (
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"git.at.oechsler.it/samuel/dash/v2/app/command"
@@ -18,16 +21,52 @@ import (
 )
 
 const (
-	SettingsModalRoute              = "SettingsModalRoute"
-	SettingsModalThemesRoute        = "SettingsModalThemesRoute"
-	SettingsModalSessionsRoute      = "SettingsModalSessionsRoute"
-	SettingsUpdateRoute             = "SettingsUpdateRoute"
-	SettingsExportRoute             = "SettingsExportRoute"
-	SettingsImportRoute             = "SettingsImportRoute"
-	SettingsDeleteAccountRoute      = "SettingsDeleteAccountRoute"
-	SettingsSessionsPinRoute        = "SettingsSessionsPinRoute"
-	SettingsSessionsUnpinRoute      = "SettingsSessionsUnpinRoute"
-	SettingsSessionsInvalidateRoute = "SettingsSessionsInvalidateRoute"
+	SettingsModalRoute                 = "SettingsModalRoute"
+	SettingsModalThemesRoute           = "SettingsModalThemesRoute"
+	SettingsModalSessionsRoute         = "SettingsModalSessionsRoute"
+	SettingsUpdateRoute                = "SettingsUpdateRoute"
+	SettingsExportRoute                = "SettingsExportRoute"
+	SettingsExportBookmarksRoute       = "SettingsExportBookmarksRoute"
+	SettingsImportRoute                = "SettingsImportRoute"
+	SettingsDeleteAccountRoute         = "SettingsDeleteAccountRoute"
+	SettingsSessionsPinRoute           = "SettingsSessionsPinRoute"
+	SettingsSessionsUnpinRoute         = "SettingsSessionsUnpinRoute"
+	SettingsSessionsInvalidateRoute    = "SettingsSessionsInvalidateRoute"
+	SettingsModalTokensRoute           = "SettingsModalTokensRoute"
+	SettingsTokensCreateRoute          = "SettingsTokensCreateRoute"
+	SettingsTokensRevokeRoute          = "SettingsTokensRevokeRoute"
+	SettingsModalSearchRoute           = "SettingsModalSearchRoute"
+	SettingsSearchCreateRoute          = "SettingsSearchCreateRoute"
+	SettingsSearchDeleteRoute          = "SettingsSearchDeleteRoute"
+	SettingsModalIconsRoute            = "SettingsModalIconsRoute"
+	SettingsIconsUploadRoute           = "SettingsIconsUploadRoute"
+	SettingsIconsDeleteRoute           = "SettingsIconsDeleteRoute"
+	SettingsModalAccountsRoute         = "SettingsModalAccountsRoute"
+	SettingsAccountsUnlinkRoute        = "SettingsAccountsUnlinkRoute"
+	SettingsModalLocalAccountsRoute    = "SettingsModalLocalAccountsRoute"
+	SettingsLocalAccountsCreateRoute   = "SettingsLocalAccountsCreateRoute"
+	SettingsLocalAccountsPasswordRoute = "SettingsLocalAccountsPasswordRoute"
+	SettingsLocalAccountsDeleteRoute   = "SettingsLocalAccountsDeleteRoute"
+	SettingsModalUsersRoute            = "SettingsModalUsersRoute"
+	SettingsUsersLogoutRoute           = "SettingsUsersLogoutRoute"
+	SettingsUsersDeleteRoute           = "SettingsUsersDeleteRoute"
+	SettingsModalGroupsRoute           = "SettingsModalGroupsRoute"
+	SettingsGroupsCreateRoute          = "SettingsGroupsCreateRoute"
+	SettingsGroupsUpdateRoute          = "SettingsGroupsUpdateRoute"
+	SettingsGroupsDeleteRoute          = "SettingsGroupsDeleteRoute"
+	SettingsGroupsAddMemberRoute       = "SettingsGroupsAddMemberRoute"
+	SettingsGroupsRemoveMemberRoute    = "SettingsGroupsRemoveMemberRoute"
+	SettingsModalInvitationsRoute      = "SettingsModalInvitationsRoute"
+	SettingsInvitationsCreateRoute     = "SettingsInvitationsCreateRoute"
+	SettingsInvitationsDeleteRoute     = "SettingsInvitationsDeleteRoute"
+	SettingsInvitationsRevokeRoute     = "SettingsInvitationsRevokeRoute"
+	SettingsModalSharedCategoriesRoute = "SettingsModalSharedCategoriesRoute"
+	SettingsSharedCategoriesHideRoute  = "SettingsSharedCategoriesHideRoute"
+	SettingsSharedCategoriesShowRoute  = "SettingsSharedCategoriesShowRoute"
+	SettingsUnpublishCategoryRoute     = "SettingsUnpublishCategoryRoute"
+	SettingsModalReceivedRoute         = "SettingsModalReceivedRoute"
+	SettingsLeaveReceivedRoute         = "SettingsLeaveReceivedRoute"
+	SettingsModalAuditRoute            = "SettingsModalAuditRoute"
 )
 
 var availableLanguages = []string{"auto", "en", "de"}
@@ -69,19 +108,58 @@ var availableTimezones = []timezone{
 }
 
 type SettingDeps struct {
-	SessionStore        *oidc.SessionStore
-	App                 *fiber.App
-	GetUserSettings     query.UserSettingsGetter
-	UpdateUserSettings  command.UserSettingsUpdater
-	ListUserThemes      query.UserThemesLister
-	ExportUserData      query.UserDataExporter
-	DeleteUserData      command.UserDataDeleter
-	ImportUserData      command.UserDataImporter
-	GetSessionsOverview query.UserSessionsOverviewGetter
-	PinSession          command.SessionPinner
-	UnpinSession        command.SessionUnpinner
-	InvalidateSession   command.SessionInvalidator
-	BuildInfo           BuildInfo
+	SessionStore            *oidc.SessionStore
+	App                     *fiber.App
+	GetUserSettings         query.UserSettingsGetter
+	UpdateUserSettings      command.UserSettingsUpdater
+	ListUserThemes          query.UserThemesLister
+	ExportUserData          query.UserDataExporter
+	DeleteUserData          command.UserDataDeleter
+	ImportUserData          command.UserDataImporter
+	ImportUserBookmarks     command.UserBookmarksImporter
+	GetSessionsOverview     query.UserSessionsOverviewGetter
+	PinSession              command.SessionPinner
+	UnpinSession            command.SessionUnpinner
+	InvalidateSession       command.SessionInvalidator
+	ListAccessTokens        query.AccessTokensLister
+	CreateAccessToken       command.AccessTokenCreator
+	RevokeAccessToken       command.AccessTokenRevoker
+	ListSearchProviders     query.UserSearchProvidersLister
+	CreateSearchProvider    command.UserSearchProviderCreator
+	DeleteSearchProvider    command.UserSearchProviderDeleter
+	ListCustomIcons         query.CustomIconsLister
+	UploadCustomIcon        command.CustomIconUploader
+	DeleteCustomIcon        command.CustomIconDeleter
+	ListIdpLinks            query.IdpLinksLister
+	UnlinkIdpIdentity       command.IdpIdentityUnlinker
+	ListLocalAccounts       query.LocalAccountsLister
+	CreateLocalAccount      command.LocalAccountCreator
+	SetLocalAccountPassword command.LocalAccountPasswordSetter
+	DeleteLocalAccount      command.LocalAccountDeleter
+	LocalAccounts           bool // local accounts are enabled
+	ListUsers               query.UsersLister
+	RevokeUserSessions      command.UserSessionsRevoker
+	DeleteUser              command.UserDeleter
+	ListLocalGroups         query.LocalGroupsLister
+	CreateLocalGroup        command.LocalGroupCreator
+	UpdateLocalGroup        command.LocalGroupUpdater
+	DeleteLocalGroup        command.LocalGroupDeleter
+	AddLocalGroupMember     command.LocalGroupMemberAdder
+	RemoveLocalGroupMember  command.LocalGroupMemberRemover
+	ListInvitations         query.InvitationsLister
+	CreateInvitation        command.InvitationCreator
+	DeleteInvitation        command.InvitationDeleter
+	RevokeInvitationGrant   command.InvitationRedemptionRevoker
+	GetSharedCategories     query.SharedCategoriesGetter
+	ListSharedCategories    query.SharedCategoriesLister
+	UnpublishCategory       command.CategoryUnpublisher
+	HideSharedCategory      command.SharedCategoryHider
+	ShowSharedCategory      command.SharedCategoryShower
+	GetReceivedCategories   query.ReceivedCategoriesGetter
+	LeaveCategoryShare      command.CategoryShareLeaver
+	ListAuditLog            query.AuditLogLister
+	Providers               *oidc.Providers
+	BuildInfo               BuildInfo
 }
 
 // SettingPlain registers plain HTTP (non-HTMX) routes for settings.
@@ -117,6 +195,32 @@ func SettingPlain(deps SettingDeps) {
 		return c.Send(data)
 	}).Name(SettingsExportRoute)
 
+	// Bookmarks export: the categories of all dashboards as a browser
+	// bookmarks.html file, which the import reads back into the same tree.
+	r.Get("/settings/export/bookmarks", func(c fiber.Ctx) error {
+		user, authorized := middleware.GetCurrentUser(c)
+		if !authorized {
+			return redirectToLogin(c)
+		}
+
+		export, err := deps.ExportUserData.Handle(c.Context(), user.UserID, user.Username, user.IsAdmin)
+		if err != nil {
+			return err
+		}
+
+		categories := export.Categories
+		for _, d := range export.Dashboards {
+			categories = append(categories, d.Categories...)
+		}
+
+		timestamp := time.Now().UTC().Format("20060102-150405")
+		filename := fmt.Sprintf("dash-bookmarks-%s-%s.html", user.Username, timestamp)
+
+		c.Set("Content-Type", "text/html; charset=utf-8")
+		c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		return c.Send(transfer.MarshalNetscapeBookmarks(categories))
+	}).Name(SettingsExportBookmarksRoute)
+
 }
 
 func Setting(deps SettingDeps) {
@@ -133,18 +237,22 @@ func Setting(deps SettingDeps) {
 			}
 
 			var body struct {
-				ThemeID  uint   `form:"theme_id"`
-				Language string `form:"language"`
-				Timezone string `form:"timezone"`
+				ThemeID          uint   `form:"theme_id"`
+				Language         string `form:"language"`
+				Timezone         string `form:"timezone"`
+				SearchProviderID uint   `form:"search_provider_id"`
 			}
 			if err := c.Bind().Body(&body); err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
 			}
 
+			// The provider select is only rendered when the user has providers,
+			// so a missing field clears a default that no longer exists.
 			if err := deps.UpdateUserSettings.Handle(c.Context(), user.UserID, command.UpdateUserSettingsCmd{
-				ThemeID:  body.ThemeID,
-				Language: body.Language,
-				Timezone: body.Timezone,
+				ThemeID:          body.ThemeID,
+				Language:         body.Language,
+				Timezone:         body.Timezone,
+				SearchProviderID: &body.SearchProviderID,
 			}); err != nil {
 				return err
 			}
@@ -171,11 +279,17 @@ func Setting(deps SettingDeps) {
 				return err
 			}
 
+			providers, err := deps.ListSearchProviders.Handle(c.Context(), user.UserID)
+			if err != nil {
+				return err
+			}
+
 			return middleware.Render(c, partials.SettingsModal(partials.SettingsModalInput{
 				Settings: partials.SettingsModalInputSettings{
-					ThemeID:  settings.ThemeID,
-					Language: settings.Language,
-					Timezone: settings.Timezone,
+					ThemeID:          settings.ThemeID,
+					Language:         settings.Language,
+					Timezone:         settings.Timezone,
+					SearchProviderID: settings.SearchProviderID,
 				},
 				Themes: lo.Map(themes, func(theme domainmodel.Theme, _ int) partials.SettingsModalInputTheme {
 					return partials.SettingsModalInputTheme{
@@ -189,12 +303,19 @@ func Setting(deps SettingDeps) {
 				Timezones: lo.Map(availableTimezones, func(tz timezone, _ int) partials.SettingsModalInputTimezone {
 					return partials.SettingsModalInputTimezone{IANA: tz.IANA, Label: tz.Label}
 				}),
+				SearchProviders: lo.Map(providers, func(p domainmodel.SearchProvider, _ int) partials.SettingsModalInputSearchProvider {
+					return partials.SettingsModalInputSearchProvider{ID: p.ID, DisplayName: p.DisplayName}
+				}),
 				Build: partials.SettingsModalInputBuild{
 					Version:   deps.BuildInfo.Version,
 					Commit:    deps.BuildInfo.Commit,
 					BuildDate: deps.BuildInfo.BuildDate,
 					RepoURL:   deps.BuildInfo.RepoURL,
 				},
+				LocalAccounts: deps.LocalAccounts && user.IsAdmin,
+				Users:         user.IsAdmin,
+				Groups:        user.IsAdmin,
+				Invitations:   user.IsAdmin,
 			}))
 		}).Name(SettingsModalRoute)
 
@@ -245,7 +366,9 @@ func Setting(deps SettingDeps) {
 			}))
 		}).Name(SettingsModalThemesRoute)
 
-	// Import: HTMX, multipart file upload
+	// Import: HTMX, multipart file upload. Accepts a Dash JSON export, a
+	// browser bookmarks.html file, Flame's database or the configuration of
+	// Homer, gethomepage or Heimdall.
 	router.
 		Use(middleware.HtmxOnly).
 		Post("/settings/import", func(c fiber.Ctx) error {
@@ -270,12 +393,29 @@ func Setting(deps SettingDeps) {
 				return fiber.NewError(fiber.StatusBadRequest, "cannot read file")
 			}
 
-			export, err := transfer.UnmarshalExport(raw)
+			if transfer.IsNetscapeBookmarks(raw) {
+				categories, err := transfer.ParseNetscapeBookmarks(raw)
+				if err != nil {
+					return fiber.NewError(fiber.StatusBadRequest, err.Error())
+				}
+				if err := deps.ImportUserBookmarks.Handle(c.Context(), auditActor(c, user), categories); err != nil {
+					return err
+				}
+				c.Set("HX-Refresh", "true")
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+
+			var export *transfer.UserDataExport
+			if format, ok := transfer.DetectForeignFormat(raw); ok {
+				export, err = transfer.ParseForeign(format, raw, user.IsAdmin)
+			} else {
+				export, err = transfer.UnmarshalExport(raw)
+			}
 			if err != nil {
 				return fiber.NewError(fiber.StatusBadRequest, err.Error())
 			}
 
-			if err := deps.ImportUserData.Handle(c.Context(), user.UserID, user.IsAdmin, export); err != nil {
+			if err := deps.ImportUserData.Handle(c.Context(), auditActor(c, user), user.IsAdmin, export); err != nil {
 				return err
 			}
 
@@ -308,7 +448,7 @@ func Setting(deps SettingDeps) {
 				return fiber.NewError(fiber.StatusBadRequest, "no valid session to pin")
 			}
 
-			if err := deps.PinSession.Handle(c.Context(), user.UserID, command.PinSessionCmd{
+			if err := deps.PinSession.Handle(c.Context(), auditActor(c, user), command.PinSessionCmd{
 				SessionID: sessionData.SessionID,
 			}); err != nil {
 				return err
@@ -333,7 +473,7 @@ func Setting(deps SettingDeps) {
 				return redirectToLogin(c)
 			}
 
-			if err := deps.UnpinSession.Handle(c.Context(), user.UserID, c.Params("id")); err != nil {
+			if err := deps.UnpinSession.Handle(c.Context(), auditActor(c, user), c.Params("id")); err != nil {
 				return err
 			}
 
@@ -357,93 +497,1300 @@ func Setting(deps SettingDeps) {
 				return redirectToLogin(c)
 			}
 
-			if err := deps.InvalidateSession.Handle(c.Context(), user.UserID, c.Params("id")); err != nil {
+			if err := deps.InvalidateSession.Handle(c.Context(), auditActor(c, user), c.Params("id")); err != nil {
 				return err
 			}
 
 			return renderSessionsSection(c, deps, user)
 		}).Name(SettingsSessionsInvalidateRoute)
 
-	// Delete account: HTMX, deletes all user data then triggers OIDC logout
+	// Access tokens section: lists the user's personal access tokens.
+	// Token management requires a browser session — a token cannot mint or
+	// revoke other tokens.
 	router.
 		Use(middleware.HtmxOnly).
-		Delete("/settings/account", func(c fiber.Ctx) error {
+		Get("/settings/modal/tokens", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderTokensSection(c, deps, user, "")
+		}).Name(SettingsModalTokensRoute)
+
+	// Create: the secret is rendered exactly once in the response.
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/tokens", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Name          string `form:"name"`
+				Scope         string `form:"scope"`
+				ExpiresInDays int    `form:"expires_in_days"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			secret, err := deps.CreateAccessToken.Handle(c.Context(), user, command.CreateAccessTokenCmd{
+				Name:          body.Name,
+				Scope:         body.Scope,
+				ExpiresInDays: body.ExpiresInDays,
+			})
+			if err != nil {
+				return httpError(err)
+			}
+
+			return renderTokensSection(c, deps, user, secret)
+		}).Name(SettingsTokensCreateRoute)
+
+	// Revoke: deletes the token; the next request carrying it is unauthenticated.
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/tokens/:id", func(c fiber.Ctx) error {
 			user, authorized := middleware.GetCurrentUser(c)
 			if !authorized {
 				return redirectToLogin(c)
 			}
 
-			if err := deps.DeleteUserData.Handle(c.Context(), user.UserID); err != nil {
+			if err := deps.RevokeAccessToken.Handle(c.Context(), user.UserID, c.Params("id")); err != nil {
 				return err
 			}
 
-			// Redirect to the logout route which handles session clearing + OIDC end-session
-			logoutURL, err := c.GetRouteURL(SessionLogoutRoute, fiber.Map{})
+			return renderTokensSection(c, deps, user, "")
+		}).Name(SettingsTokensRevokeRoute)
+
+	// Web search section: lists the user's search providers and their bangs.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/search-providers", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderSearchProvidersSection(c, deps, user)
+		}).Name(SettingsModalSearchRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/search-providers", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				DisplayName string `form:"display_name"`
+				Bang        string `form:"bang"`
+				URLTemplate string `form:"url_template"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.CreateSearchProvider.Handle(c.Context(), user.UserID, command.CreateUserSearchProviderCmd{
+				DisplayName: body.DisplayName,
+				Bang:        body.Bang,
+				URLTemplate: body.URLTemplate,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderSearchProvidersSection(c, deps, user)
+		}).Name(SettingsSearchCreateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/search-providers/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
 			if err != nil {
-				logoutURL = "/session/logout"
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 			}
-			c.Set("HX-Redirect", logoutURL)
-			return c.SendStatus(fiber.StatusNoContent)
-		}).Name(SettingsDeleteAccountRoute)
 
-}
+			if err := deps.DeleteSearchProvider.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return err
+			}
 
-// renderSessionsSection renders the sessions section partial for HTMX responses.
-// The handler's only job here is to extract raw infra data (cookie, IP) and map
-// the app-layer result to the template input — no business logic.
-func renderSessionsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
-	// Extract cookie data — infra concern, stays in the handler.
-	var currentSessionID string
-	if raw, ok := deps.SessionStore.Load(c); ok {
-		currentSessionID = raw.SessionID
-	}
+			return renderSearchProvidersSection(c, deps, user)
+		}).Name(SettingsSearchDeleteRoute)
 
-	// Resolve user timezone for timestamp display.
-	var loc *time.Location
-	if settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID); err == nil {
-		tzName := settings.Timezone
-		if tzName == "" || tzName == "auto" {
-			tzName = tzCookie(c)
-		}
-		if l, err := time.LoadLocation(tzName); err == nil {
-			loc = l
-		}
-	}
-	if loc == nil {
-		loc = time.UTC
-	}
+	// Icons section: lists the icon library, i.e. the user's own and the shared icons.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/icons", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderIconsSection(c, deps, user)
+		}).Name(SettingsModalIconsRoute)
 
-	overview, err := deps.GetSessionsOverview.Handle(c.Context(), query.SessionsOverviewInput{
-		UserID:           user.UserID,
-		CurrentSessionID: currentSessionID,
-		CurrentIP:        c.IP(),
-		CurrentUserAgent: c.Get("User-Agent"),
-	})
-	if err != nil {
-		return err
-	}
+	// Upload: HTMX, multipart file upload of a PNG, JPEG or SVG image.
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/icons", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
 
-	var currentSessionPinned bool
-	sessionViews := lo.Map(overview.Sessions, func(s *query.SessionOverviewItem, _ int) partials.SettingsModalSessionsSectionInputSession {
-		if s.IsCurrent && s.IsPinned {
-			currentSessionPinned = true
-		}
-		return partials.SettingsModalSessionsSectionInputSession{
-			ID:             s.ID,
-			SessionID:      s.SessionID,
-			LastIP:         s.LastIP,
-			LastAccessedAt: s.LastAccessedAt,
-			CreatedAt:      s.CreatedAt,
-			PinnedUntil:    s.PinnedUntil,
-			UserAgent:      s.UserAgent,
-			IsActive:       s.IsActive,
-			IsCurrent:      s.IsCurrent,
-			IsPinned:       s.IsPinned,
-		}
-	})
+			file, err := c.FormFile("file")
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "missing file")
+			}
+			if file.Size > domainmodel.MaxCustomIconBytes {
+				return fiber.NewError(fiber.StatusRequestEntityTooLarge, "file too large")
+			}
 
-	return middleware.Render(c, partials.SettingsModalSessionsSectionWithNavOOB(partials.SettingsModalSessionsSectionInput{
-		Sessions: sessionViews,
-		Timezone: loc,
-	}, currentSessionPinned))
+			f, err := file.Open()
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "cannot open file")
+			}
+			defer f.Close()
+
+			raw, err := io.ReadAll(f)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "cannot read file")
+			}
+
+			if err := deps.UploadCustomIcon.Handle(c.Context(), user.UserID, user.IsAdmin, command.UploadCustomIconCmd{
+				DisplayName: c.FormValue("display_name"),
+				Data:        raw,
+				IsShared:    c.FormValue("is_shared") == "true",
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderIconsSection(c, deps, user)
+		}).Name(SettingsIconsUploadRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/icons/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.DeleteCustomIcon.Handle(c.Context(), user.UserID, user.IsAdmin, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderIconsSection(c, deps, user)
+		}).Name(SettingsIconsDeleteRoute)
+
+	// Linked accounts section: lists the provider identities that sign in to
+	// this account. Linking itself runs through the login flow (/session/link).
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/accounts", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderAccountsSection(c, deps, user)
+		}).Name(SettingsModalAccountsRoute)
+
+	// Unlink: the identity is passed as query parameters because issuers are URLs.
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/accounts", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			if err := deps.UnlinkIdpIdentity.Handle(c.Context(), user.UserID, c.Query("issuer"), c.Query("sub")); err != nil {
+				return httpError(err)
+			}
+
+			return renderAccountsSection(c, deps, user)
+		}).Name(SettingsAccountsUnlinkRoute)
+
+	// Local accounts section (admins only): lists the accounts that sign in
+	// with a password and lets admins create, reset and delete them.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/local-accounts", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderLocalAccountsSection(c, deps, user)
+		}).Name(SettingsModalLocalAccountsRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/local-accounts", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Username    string `form:"username"`
+				DisplayName string `form:"display_name"`
+				Email       string `form:"email"`
+				Groups      string `form:"groups"`
+				Password    string `form:"password"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.CreateLocalAccount.Handle(c.Context(), user.IsAdmin, command.CreateLocalAccountCmd{
+				Username:    body.Username,
+				DisplayName: strings.TrimSpace(body.DisplayName),
+				Email:       strings.TrimSpace(body.Email),
+				Groups:      strings.Fields(body.Groups),
+				Password:    body.Password,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderLocalAccountsSection(c, deps, user)
+		}).Name(SettingsLocalAccountsCreateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/settings/local-accounts/:username/password", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Password string `form:"password"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			username, err := url.PathUnescape(c.Params("username"))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid username")
+			}
+
+			if err := deps.SetLocalAccountPassword.Handle(c.Context(), user.IsAdmin, command.SetLocalAccountPasswordCmd{
+				Username: username,
+				Password: body.Password,
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderLocalAccountsSection(c, deps, user)
+		}).Name(SettingsLocalAccountsPasswordRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/local-accounts/:username", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			username, err := url.PathUnescape(c.Params("username"))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid username")
+			}
+
+			if err := deps.DeleteLocalAccount.Handle(c.Context(), user.IsAdmin, username); err != nil {
+				return httpError(err)
+			}
+
+			return renderLocalAccountsSection(c, deps, user)
+		}).Name(SettingsLocalAccountsDeleteRoute)
+
+	// Users section (admins only): lists everyone who has signed in and lets
+	// admins end their sessions or delete them with all their data.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/users", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderUsersSection(c, deps, user)
+		}).Name(SettingsModalUsersRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/users/:id/logout", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			userID, err := url.PathUnescape(c.Params("id"))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+			}
+
+			if err := deps.RevokeUserSessions.Handle(c.Context(), user.IsAdmin, auditActor(c, user), userID); err != nil {
+				return httpError(err)
+			}
+
+			return renderUsersSection(c, deps, user)
+		}).Name(SettingsUsersLogoutRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/users/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			userID, err := url.PathUnescape(c.Params("id"))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+			}
+
+			if err := deps.DeleteUser.Handle(c.Context(), user.IsAdmin, auditActor(c, user), userID); err != nil {
+				return httpError(err)
+			}
+
+			return renderUsersSection(c, deps, user)
+		}).Name(SettingsUsersDeleteRoute)
+
+	// Groups section (admins only): manages the groups kept in Dash, their
+	// members and the IdP groups mapped onto them.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/groups", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsModalGroupsRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/groups", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Name      string `form:"name"`
+				IdpGroups string `form:"idp_groups"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.CreateLocalGroup.Handle(c.Context(), user.IsAdmin, command.CreateLocalGroupCmd{
+				Name:      body.Name,
+				IdpGroups: strings.Fields(body.IdpGroups),
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsGroupsCreateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Put("/settings/groups/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			var body struct {
+				Name      string `form:"name"`
+				IdpGroups string `form:"idp_groups"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.UpdateLocalGroup.Handle(c.Context(), user.IsAdmin, command.UpdateLocalGroupCmd{
+				ID:        uint(id64),
+				Name:      body.Name,
+				IdpGroups: strings.Fields(body.IdpGroups),
+			}); err != nil {
+				return httpError(err)
+			}
+
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsGroupsUpdateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/groups/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.DeleteLocalGroup.Handle(c.Context(), user.IsAdmin, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsGroupsDeleteRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/groups/:id/members", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			var body struct {
+				UserID string `form:"user_id"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			if err := deps.AddLocalGroupMember.Handle(c.Context(), user.IsAdmin, uint(id64), body.UserID); err != nil {
+				return httpError(err)
+			}
+
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsGroupsAddMemberRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/groups/:id/members/:user", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+			userID, err := url.PathUnescape(c.Params("user"))
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid user id")
+			}
+
+			if err := deps.RemoveLocalGroupMember.Handle(c.Context(), user.IsAdmin, uint(id64), userID); err != nil {
+				return httpError(err)
+			}
+
+			return renderGroupsSection(c, deps, user)
+		}).Name(SettingsGroupsRemoveMemberRoute)
+
+	// Invitations section (admins only): creates guest invitation links and
+	// lists who redeemed them, so grants can be revoked before they expire.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/invitations", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderInvitationsSection(c, deps, user, "")
+		}).Name(SettingsModalInvitationsRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/invitations", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			var body struct {
+				Groups        string `form:"groups"`
+				MaxUses       int    `form:"max_uses"`
+				ExpiresInDays int    `form:"expires_in_days"`
+				GrantDays     int    `form:"grant_days"`
+			}
+			if err := c.Bind().Body(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid body")
+			}
+
+			secret, err := deps.CreateInvitation.Handle(c.Context(), user.IsAdmin, auditActor(c, user), command.CreateInvitationCmd{
+				Groups:        strings.Fields(body.Groups),
+				MaxUses:       body.MaxUses,
+				ExpiresInDays: body.ExpiresInDays,
+				GrantDays:     body.GrantDays,
+			})
+			if err != nil {
+				return httpError(err)
+			}
+
+			link, err := c.GetRouteURL(InvitationRoute, fiber.Map{"token": secret})
+			if err != nil {
+				return err
+			}
+
+			return renderInvitationsSection(c, deps, user, c.BaseURL()+link)
+		}).Name(SettingsInvitationsCreateRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/invitations/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			if err := deps.DeleteInvitation.Handle(c.Context(), user.IsAdmin, auditActor(c, user), c.Params("id")); err != nil {
+				return httpError(err)
+			}
+
+			return renderInvitationsSection(c, deps, user, "")
+		}).Name(SettingsInvitationsDeleteRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/invitations/:id/redemptions/:redemption", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("redemption"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.RevokeInvitationGrant.Handle(c.Context(), user.IsAdmin, auditActor(c, user), c.Params("id"), uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderInvitationsSection(c, deps, user, "")
+		}).Name(SettingsInvitationsRevokeRoute)
+
+	// Shared categories section: members hide or show the categories admins
+	// published to their groups; admins also see and revoke all publications.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/shared-categories", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderSharedCategoriesSection(c, deps, user)
+		}).Name(SettingsModalSharedCategoriesRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Post("/settings/shared-categories/:id/hide", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.HideSharedCategory.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderSharedCategoriesSection(c, deps, user)
+		}).Name(SettingsSharedCategoriesHideRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/shared-categories/:id/hide", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.ShowSharedCategory.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderSharedCategoriesSection(c, deps, user)
+		}).Name(SettingsSharedCategoriesShowRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/shared-categories/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.UnpublishCategory.Handle(c.Context(), user.IsAdmin, auditActor(c, user), uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderSharedCategoriesSection(c, deps, user)
+		}).Name(SettingsUnpublishCategoryRoute)
+
+	// Shared-with-me section: categories other users shared with the user,
+	// who can leave them.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/received-categories", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderReceivedCategoriesSection(c, deps, user)
+		}).Name(SettingsModalReceivedRoute)
+
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/received-categories/:id", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			id64, err := strconv.ParseUint(c.Params("id"), 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+			}
+
+			if err := deps.LeaveCategoryShare.Handle(c.Context(), user.UserID, uint(id64)); err != nil {
+				return httpError(err)
+			}
+
+			return renderReceivedCategoriesSection(c, deps, user)
+		}).Name(SettingsLeaveReceivedRoute)
+
+	// Audit log section: every user sees their own entries; admins can also
+	// filter by user or list everyone's.
+	router.
+		Use(middleware.HtmxOnly).
+		Get("/settings/modal/audit", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+			return renderAuditSection(c, deps, user)
+		}).Name(SettingsModalAuditRoute)
+
+	// Delete account: HTMX, deletes all user data then triggers OIDC logout
+	router.
+		Use(middleware.HtmxOnly).
+		Delete("/settings/account", func(c fiber.Ctx) error {
+			user, authorized := middleware.GetCurrentUser(c)
+			if !authorized {
+				return redirectToLogin(c)
+			}
+
+			if err := deps.DeleteUserData.Handle(c.Context(), auditActor(c, user), user.UserID); err != nil {
+				return err
+			}
+
+			// Redirect to the logout route which handles session clearing + OIDC end-session
+			logoutURL, err := c.GetRouteURL(SessionLogoutRoute, fiber.Map{})
+			if err != nil {
+				logoutURL = "/session/logout"
+			}
+			c.Set("HX-Redirect", logoutURL)
+			return c.SendStatus(fiber.StatusNoContent)
+		}).Name(SettingsDeleteAccountRoute)
+
+}
+
+// renderSessionsSection renders the sessions section partial for HTMX responses.
+// The handler's only job here is to extract raw infra data (cookie, IP) and map
+// the app-layer result to the template input — no business logic.
+func renderSessionsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	// Extract cookie data — infra concern, stays in the handler.
+	var currentSessionID string
+	if raw, ok := deps.SessionStore.Load(c); ok {
+		currentSessionID = raw.SessionID
+	}
+
+	// Resolve user timezone for timestamp display.
+	loc := userLocation(c, deps, user)
+
+	overview, err := deps.GetSessionsOverview.Handle(c.Context(), query.SessionsOverviewInput{
+		UserID:           user.UserID,
+		CurrentSessionID: currentSessionID,
+		CurrentIP:        c.IP(),
+		CurrentUserAgent: c.Get("User-Agent"),
+	})
+	if err != nil {
+		return err
+	}
+
+	var currentSessionPinned bool
+	sessionViews := lo.Map(overview.Sessions, func(s *query.SessionOverviewItem, _ int) partials.SettingsModalSessionsSectionInputSession {
+		if s.IsCurrent && s.IsPinned {
+			currentSessionPinned = true
+		}
+		return partials.SettingsModalSessionsSectionInputSession{
+			ID:             s.ID,
+			SessionID:      s.SessionID,
+			LastIP:         s.LastIP,
+			LastAccessedAt: s.LastAccessedAt,
+			CreatedAt:      s.CreatedAt,
+			PinnedUntil:    s.PinnedUntil,
+			UserAgent:      s.UserAgent,
+			IsActive:       s.IsActive,
+			IsCurrent:      s.IsCurrent,
+			IsPinned:       s.IsPinned,
+		}
+	})
+
+	return middleware.Render(c, partials.SettingsModalSessionsSectionWithNavOOB(partials.SettingsModalSessionsSectionInput{
+		Sessions: sessionViews,
+		Timezone: loc,
+	}, currentSessionPinned))
+}
+
+// renderTokensSection renders the access tokens section partial for HTMX responses.
+// createdSecret is non-empty only in the response to a create request.
+func renderTokensSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity, createdSecret string) error {
+	tokens, err := deps.ListAccessTokens.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return middleware.Render(c, partials.SettingsModalTokensSection(partials.SettingsModalTokensSectionInput{
+		Tokens: lo.Map(tokens, func(t domainmodel.AccessToken, _ int) partials.SettingsModalTokensSectionInputToken {
+			return partials.SettingsModalTokensSectionInputToken{
+				ID:         t.ID,
+				Name:       t.Name,
+				Scope:      string(t.Scope),
+				CreatedAt:  t.CreatedAt,
+				ExpiresAt:  t.ExpiresAt,
+				LastUsedAt: t.LastUsedAt,
+				IsExpired:  t.IsExpired(now),
+			}
+		}),
+		CreatedSecret: createdSecret,
+		Timezone:      userLocation(c, deps, user),
+	}))
+}
+
+// renderSearchProvidersSection renders the web search section partial for HTMX responses.
+func renderSearchProvidersSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	providers, err := deps.ListSearchProviders.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	return middleware.Render(c, partials.SettingsModalSearchProvidersSection(partials.SettingsModalSearchProvidersSectionInput{
+		Providers: lo.Map(providers, func(p domainmodel.SearchProvider, _ int) partials.SettingsModalSearchProvidersSectionInputProvider {
+			return partials.SettingsModalSearchProvidersSectionInputProvider{
+				ID:          p.ID,
+				DisplayName: p.DisplayName,
+				Bang:        p.Bang,
+				URLTemplate: p.URLTemplate,
+				IsDefault:   p.IsDefault,
+			}
+		}),
+	}))
+}
+
+// renderIconsSection renders the icon library section partial for HTMX responses.
+func renderIconsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	icons, err := deps.ListCustomIcons.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	return middleware.Render(c, partials.SettingsModalIconsSection(partials.SettingsModalIconsSectionInput{
+		Icons: lo.Map(icons, func(i domainmodel.CustomIcon, _ int) partials.SettingsModalIconsSectionInputIcon {
+			return partials.SettingsModalIconsSectionInputIcon{
+				ID:          i.ID,
+				DisplayName: i.DisplayName,
+				Reference:   i.Icon().String(),
+				IsShared:    i.IsShared,
+				Deletable:   !i.IsShared || user.IsAdmin,
+			}
+		}),
+		CanShare: user.IsAdmin,
+	}))
+}
+
+// renderAccountsSection renders the linked accounts section partial for HTMX responses.
+func renderAccountsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	links, err := deps.ListIdpLinks.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return err
+	}
+
+	unlinkURL, err := c.GetRouteURL(SettingsAccountsUnlinkRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+	linkURL, err := c.GetRouteURL(SessionLinkRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	input := partials.SettingsModalAccountsSectionInput{
+		Links: lo.Map(links, func(l domainmodel.IdpLink, _ int) partials.SettingsModalAccountsSectionInputLink {
+			q := url.Values{}
+			q.Set("issuer", l.Issuer)
+			q.Set("sub", l.Sub)
+			return partials.SettingsModalAccountsSectionInputLink{
+				ProviderName: providerName(deps, l.Issuer),
+				Sub:          l.Sub,
+				IsPrimary:    l.IsPrimary,
+				LinkedAt:     l.LinkedAt,
+				UnlinkURL:    unlinkURL + "?" + q.Encode(),
+			}
+		}),
+		CanUnlink: len(links) > 1,
+		Timezone:  userLocation(c, deps, user),
+	}
+	if deps.Providers != nil {
+		for _, p := range deps.Providers.List() {
+			q := url.Values{}
+			q.Set("provider", p.ID())
+			input.Providers = append(input.Providers, partials.SettingsModalAccountsSectionInputProvider{
+				Name:    p.Name(),
+				LinkURL: linkURL + "?" + q.Encode(),
+			})
+		}
+	}
+
+	return middleware.Render(c, partials.SettingsModalAccountsSection(input))
+}
+
+// renderLocalAccountsSection renders the local accounts section partial for HTMX responses.
+func renderLocalAccountsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	if !deps.LocalAccounts {
+		return fiber.ErrNotFound
+	}
+	accounts, err := deps.ListLocalAccounts.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+
+	createURL, err := c.GetRouteURL(SettingsLocalAccountsCreateRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	input := partials.SettingsModalLocalAccountsSectionInput{
+		CreateURL: createURL,
+		Timezone:  userLocation(c, deps, user),
+	}
+	for _, a := range accounts {
+		passwordURL, err := c.GetRouteURL(SettingsLocalAccountsPasswordRoute, fiber.Map{"username": url.PathEscape(a.Username)})
+		if err != nil {
+			return err
+		}
+		deleteURL, err := c.GetRouteURL(SettingsLocalAccountsDeleteRoute, fiber.Map{"username": url.PathEscape(a.Username)})
+		if err != nil {
+			return err
+		}
+		input.Accounts = append(input.Accounts, partials.SettingsModalLocalAccountsSectionInputAccount{
+			Username:    a.Username,
+			DisplayName: a.DisplayName,
+			Email:       a.Email,
+			Groups:      a.Groups,
+			Managed:     a.Managed,
+			CreatedAt:   a.CreatedAt,
+			PasswordURL: passwordURL,
+			DeleteURL:   deleteURL,
+		})
+	}
+
+	return middleware.Render(c, partials.SettingsModalLocalAccountsSection(input))
+}
+
+// renderUsersSection renders the user management section partial for HTMX responses.
+func renderUsersSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	users, err := deps.ListUsers.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+
+	input := partials.SettingsModalUsersSectionInput{
+		Timezone: userLocation(c, deps, user),
+	}
+	for _, u := range users {
+		logoutURL, err := c.GetRouteURL(SettingsUsersLogoutRoute, fiber.Map{"id": url.PathEscape(u.UserID)})
+		if err != nil {
+			return err
+		}
+		deleteURL, err := c.GetRouteURL(SettingsUsersDeleteRoute, fiber.Map{"id": url.PathEscape(u.UserID)})
+		if err != nil {
+			return err
+		}
+		displayName := u.DisplayName
+		if displayName == "" {
+			displayName = u.Username
+		}
+		input.Users = append(input.Users, partials.SettingsModalUsersSectionInputUser{
+			UserID:      u.UserID,
+			DisplayName: displayName,
+			Email:       u.Email,
+			IsAdmin:     u.IsAdmin,
+			IsCurrent:   u.UserID == user.UserID,
+			LastSeenAt:  u.LastSeenAt,
+			Identities: lo.Map(u.IdpLinks, func(l domainmodel.IdpLink, _ int) partials.SettingsModalUsersSectionInputIdentity {
+				return partials.SettingsModalUsersSectionInputIdentity{
+					ProviderName: providerName(deps, l.Issuer),
+					Sub:          l.Sub,
+				}
+			}),
+			CategoryCount: u.CategoryCount,
+			BookmarkCount: u.BookmarkCount,
+			LogoutURL:     logoutURL,
+			DeleteURL:     deleteURL,
+		})
+	}
+
+	return middleware.Render(c, partials.SettingsModalUsersSection(input))
+}
+
+// renderGroupsSection renders the local groups section partial for HTMX responses.
+func renderGroupsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	groups, err := deps.ListLocalGroups.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+	users, err := deps.ListUsers.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+
+	createURL, err := c.GetRouteURL(SettingsGroupsCreateRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	input := partials.SettingsModalGroupsSectionInput{CreateURL: createURL}
+	labels := map[string]string{}
+	for _, u := range users {
+		label := u.DisplayName
+		if label == "" {
+			label = u.Username
+		}
+		if label == "" {
+			label = u.UserID
+		}
+		labels[u.UserID] = label
+		input.Users = append(input.Users, partials.SettingsModalGroupsSectionInputUser{
+			UserID: u.UserID,
+			Label:  label,
+		})
+	}
+	for _, g := range groups {
+		params := fiber.Map{"id": g.ID}
+		updateURL, err := c.GetRouteURL(SettingsGroupsUpdateRoute, params)
+		if err != nil {
+			return err
+		}
+		deleteURL, err := c.GetRouteURL(SettingsGroupsDeleteRoute, params)
+		if err != nil {
+			return err
+		}
+		addMemberURL, err := c.GetRouteURL(SettingsGroupsAddMemberRoute, params)
+		if err != nil {
+			return err
+		}
+		group := partials.SettingsModalGroupsSectionInputGroup{
+			ID:           g.ID,
+			Name:         g.Name,
+			IdpGroups:    g.IdpGroups,
+			UpdateURL:    updateURL,
+			DeleteURL:    deleteURL,
+			AddMemberURL: addMemberURL,
+		}
+		for _, m := range g.Members {
+			removeURL, err := c.GetRouteURL(SettingsGroupsRemoveMemberRoute, fiber.Map{"id": g.ID, "user": url.PathEscape(m)})
+			if err != nil {
+				return err
+			}
+			label := labels[m]
+			if label == "" {
+				label = m
+			}
+			group.Members = append(group.Members, partials.SettingsModalGroupsSectionInputMember{
+				Label:     label,
+				RemoveURL: removeURL,
+			})
+		}
+		input.Groups = append(input.Groups, group)
+	}
+
+	return middleware.Render(c, partials.SettingsModalGroupsSection(input))
+}
+
+// renderInvitationsSection renders the invitations section partial for HTMX
+// responses. createdLink is only set right after an invitation was created.
+func renderInvitationsSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity, createdLink string) error {
+	invitations, err := deps.ListInvitations.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+	users, err := deps.ListUsers.Handle(c.Context(), user.IsAdmin)
+	if err != nil {
+		return httpError(err)
+	}
+
+	createURL, err := c.GetRouteURL(SettingsInvitationsCreateRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{}
+	for _, u := range users {
+		label := u.DisplayName
+		if label == "" {
+			label = u.Username
+		}
+		labels[u.UserID] = label
+	}
+
+	now := time.Now()
+	input := partials.SettingsModalInvitationsSectionInput{
+		CreatedLink: createdLink,
+		CreateURL:   createURL,
+		Timezone:    userLocation(c, deps, user),
+	}
+	for _, inv := range invitations {
+		deleteURL, err := c.GetRouteURL(SettingsInvitationsDeleteRoute, fiber.Map{"id": inv.ID})
+		if err != nil {
+			return err
+		}
+		invitation := partials.SettingsModalInvitationsSectionInputInvitation{
+			Groups:    inv.Groups,
+			MaxUses:   inv.MaxUses,
+			Uses:      inv.Uses,
+			ExpiresAt: inv.ExpiresAt,
+			IsExpired: inv.IsExpired(now),
+			IsUsedUp:  inv.IsUsedUp(),
+			GrantDays: int(inv.GrantDuration / (24 * time.Hour)),
+			DeleteURL: deleteURL,
+		}
+		for _, r := range inv.Redemptions {
+			revokeURL, err := c.GetRouteURL(SettingsInvitationsRevokeRoute, fiber.Map{"id": inv.ID, "redemption": r.ID})
+			if err != nil {
+				return err
+			}
+			label := labels[r.UserID]
+			if label == "" {
+				label = r.UserID
+			}
+			invitation.Redemptions = append(invitation.Redemptions, partials.SettingsModalInvitationsSectionInputRedemption{
+				UserLabel: label,
+				ExpiresAt: r.ExpiresAt,
+				IsActive:  r.IsActive(now),
+				RevokeURL: revokeURL,
+			})
+		}
+		input.Invitations = append(input.Invitations, invitation)
+	}
+
+	return middleware.Render(c, partials.SettingsModalInvitationsSection(input))
+}
+
+// renderSharedCategoriesSection renders the shared categories section partial
+// for HTMX responses.
+func renderSharedCategoriesSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	shared, err := deps.GetSharedCategories.Handle(c.Context(), user.UserID, user.Groups)
+	if err != nil {
+		return httpError(err)
+	}
+
+	input := partials.SettingsModalSharedCategoriesSectionInput{IsAdmin: user.IsAdmin}
+	for _, category := range shared {
+		route := SettingsSharedCategoriesHideRoute
+		if category.IsHidden {
+			route = SettingsSharedCategoriesShowRoute
+		}
+		toggleURL, err := c.GetRouteURL(route, fiber.Map{"id": category.ID})
+		if err != nil {
+			return err
+		}
+		input.Categories = append(input.Categories, partials.SettingsModalSharedCategoriesSectionInputCategory{
+			DisplayName: category.DisplayName,
+			IsHidden:    category.IsHidden,
+			ToggleURL:   toggleURL,
+		})
+	}
+
+	if user.IsAdmin {
+		published, err := deps.ListSharedCategories.Handle(c.Context(), user.IsAdmin)
+		if err != nil {
+			return httpError(err)
+		}
+		for _, category := range published {
+			unpublishURL, err := c.GetRouteURL(SettingsUnpublishCategoryRoute, fiber.Map{"id": category.ID})
+			if err != nil {
+				return err
+			}
+			input.Publications = append(input.Publications, partials.SettingsModalSharedCategoriesSectionInputPublication{
+				DisplayName:     category.DisplayName,
+				VisibleToGroups: category.VisibleToGroups,
+				UnpublishURL:    unpublishURL,
+			})
+		}
+	}
+
+	return middleware.Render(c, partials.SettingsModalSharedCategoriesSection(input))
+}
+
+// renderReceivedCategoriesSection renders the shared-with-me section partial
+// for HTMX responses.
+func renderReceivedCategoriesSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	received, err := deps.GetReceivedCategories.Handle(c.Context(), user.UserID)
+	if err != nil {
+		return httpError(err)
+	}
+
+	input := partials.SettingsModalReceivedCategoriesSectionInput{UserID: user.UserID}
+	for _, category := range received {
+		leaveURL, err := c.GetRouteURL(SettingsLeaveReceivedRoute, fiber.Map{"id": category.ID})
+		if err != nil {
+			return err
+		}
+		input.Categories = append(input.Categories, partials.SettingsModalReceivedCategoriesSectionInputCategory{
+			DisplayName: category.DisplayName,
+			OwnerLabel:  category.OwnerLabel,
+			IsEditor:    category.Role == domainmodel.CategoryRoleEditor,
+			LeaveURL:    leaveURL,
+		})
+	}
+
+	return middleware.Render(c, partials.SettingsModalReceivedCategoriesSection(input))
+}
+
+// renderAuditSection renders the audit log section partial for HTMX responses,
+// filtered by the actor and action query parameters.
+func renderAuditSection(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) error {
+	actor := c.Query("actor")
+	in := query.ListAuditLogQuery{Action: domainmodel.AuditAction(c.Query("action"))}
+	if actor != "" {
+		in.AllUsers = true
+		if actor != partials.SettingsModalAuditSectionAllUsers {
+			in.ActorID = actor
+		}
+	}
+	entries, err := deps.ListAuditLog.Handle(c.Context(), user.UserID, user.IsAdmin, in)
+	if err != nil {
+		return httpError(err)
+	}
+
+	auditURL, err := c.GetRouteURL(SettingsModalAuditRoute, fiber.Map{})
+	if err != nil {
+		return err
+	}
+
+	names := map[string]string{user.UserID: user.DisplayName}
+	input := partials.SettingsModalAuditSectionInput{
+		URL:       auditURL,
+		Action:    string(in.Action),
+		Actor:     actor,
+		ShowActor: in.AllUsers && in.ActorID == "",
+		Timezone:  userLocation(c, deps, user),
+	}
+	for _, a := range domainmodel.AuditActions {
+		input.Actions = append(input.Actions, string(a))
+	}
+	if user.IsAdmin {
+		users, err := deps.ListUsers.Handle(c.Context(), user.IsAdmin)
+		if err != nil {
+			return httpError(err)
+		}
+		for _, u := range users {
+			label := u.DisplayName
+			if label == "" {
+				label = u.Username
+			}
+			if label == "" {
+				label = u.UserID
+			}
+			names[u.UserID] = label
+			input.Actors = append(input.Actors, partials.SettingsModalAuditSectionInputOption{
+				Value: u.UserID,
+				Label: label,
+			})
+		}
+	}
+	for _, e := range entries {
+		name := names[e.ActorID]
+		if name == "" {
+			// The user has been deleted since.
+			name = e.ActorID
+		}
+		input.Entries = append(input.Entries, partials.SettingsModalAuditSectionInputEntry{
+			ActorName: name,
+			Action:    string(e.Action),
+			Target:    e.Target,
+			IP:        e.IP,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return middleware.Render(c, partials.SettingsModalAuditSection(input))
+}
+
+// providerName returns the configured name of the provider behind issuer, or
+// the issuer itself when the provider is no longer configured.
+func providerName(deps SettingDeps, issuer string) string {
+	if deps.Providers != nil {
+		if p, ok := deps.Providers.ByIssuer(issuer); ok {
+			return p.Name()
+		}
+	}
+	return issuer
+}
+
+// auditActor identifies the signed-in user and their address for the audit log.
+func auditActor(c fiber.Ctx, user domainmodel.Identity) domainmodel.AuditActor {
+	return domainmodel.AuditActor{UserID: user.UserID, IP: c.IP()}
+}
+
+// userLocation resolves the user's timezone for timestamp display, falling
+// back to the browser's tz cookie and finally UTC.
+func userLocation(c fiber.Ctx, deps SettingDeps, user domainmodel.Identity) *time.Location {
+	if settings, err := deps.GetUserSettings.Handle(c.Context(), user.UserID); err == nil {
+		tzName := settings.Timezone
+		if tzName == "" || tzName == "auto" {
+			tzName = tzCookie(c)
+		}
+		if l, err := time.LoadLocation(tzName); err == nil {
+			return l
+		}
+	}
+	return time.UTC
 }
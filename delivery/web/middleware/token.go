@@ -38,3 +38,11 @@ func GetCurrentSessionPinned(c fiber.Ctx) bool {
 	pinned, _ := c.Locals("session_pinned").(bool)
 	return pinned
 }
+
+// GetCurrentSessionStale reports whether the IdP rejected the refresh token
+// of the current session, so the user has to re-authenticate interactively.
+// Set by LoadUserFromSession via SessionStore.LoadIdentity.
+func GetCurrentSessionStale(c fiber.Ctx) bool {
+	stale, _ := c.Locals("session_stale").(bool)
+	return stale
+}
@@ -1,6 +1,6 @@
 // Code generated by templ - DO NOT EDIT.
 
-// templ: version: v0.3.1001
+// templ: version: v0.3.1020
 package layout
 
 //lint:file-ignore SA4006 This context is only used if a nested component is present.
@@ -8,6 +8,8 @@ package layout
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import "git.at.oechsler.it/samuel/dash/v2/delivery/web/templ/components"
+
 type Theme struct {
 	Primary   string
 	Secondary string
@@ -46,11 +48,11 @@ func Base(input BaseInput) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
-		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(input.Language)
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.ResolveAttributeValue(input.Language)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `delivery/web/templ/layout/base.templ`, Line: 17, Col: 28}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `delivery/web/templ/layout/base.templ`, Line: 19, Col: 28}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var2)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -61,13 +63,13 @@ func Base(input BaseInput) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(input.Title)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `delivery/web/templ/layout/base.templ`, Line: 21, Col: 23}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `delivery/web/templ/layout/base.templ`, Line: 23, Col: 23}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</title><script>\n\t\t\t\t(function () {\n\t\t\t\t\tvar tz = Intl.DateTimeFormat().resolvedOptions().timeZone;\n\t\t\t\t\tif (tz) {\n\t\t\t\t\t\tdocument.cookie = \"tz=\" + tz + \";path=/;SameSite=Lax;max-age=31536000\";\n\t\t\t\t\t}\n\t\t\t\t})();\n\t\t\t</script><script src=\"/static/js/htmx.min.js\" type=\"text/javascript\"></script><script src=\"/static/js/tailwind.min.js\" type=\"text/javascript\"></script><link href=\"/static/css/material-icons.min.css\" rel=\"stylesheet\" type=\"text/css\"><link href=\"/static/css/simple-icons.min.css\" rel=\"stylesheet\" type=\"text/css\"><link rel=\"icon\" href=\"/favicon.ico\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</title><script>\n\t\t\t\t(function () {\n\t\t\t\t\tvar tz = Intl.DateTimeFormat().resolvedOptions().timeZone;\n\t\t\t\t\tif (tz) {\n\t\t\t\t\t\tdocument.cookie = \"tz=\" + tz + \";path=/;SameSite=Lax;max-age=31536000\";\n\t\t\t\t\t}\n\t\t\t\t})();\n\t\t\t</script><script src=\"/static/js/htmx.min.js\" type=\"text/javascript\"></script><script src=\"/static/js/tailwind.min.js\" type=\"text/javascript\"></script><link href=\"/static/css/material-icons.min.css\" rel=\"stylesheet\" type=\"text/css\"><link href=\"/static/css/simple-icons.min.css\" rel=\"stylesheet\" type=\"text/css\"><link rel=\"icon\" href=\"/favicon.ico\"><link rel=\"search\" type=\"application/opensearchdescription+xml\" title=\"Dash\" href=\"/opensearch.xml\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -107,6 +109,10 @@ func Base(input BaseInput) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		templ_7745c5c3_Err = components.SortableScript().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</body></html>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -1,6 +1,6 @@
 // Code generated by templ - DO NOT EDIT.
 
-// templ: version: v0.3.1001
+// templ: version: v0.3.1020
 package partials
 
 //lint:file-ignore SA4006 This context is only used if a nested component is present.
@@ -50,7 +50,7 @@ func DashboardEdit(input DashboardEditInput) templ.Component {
 					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " <div hx-get=\"/categories/shelved/edit\" hx-trigger=\"load\" hx-target=\"#shelved-sections\" hx-swap=\"innerHTML\"></div><div hx-get=\"/dashboard/title/bookmarks/edit\" hx-trigger=\"load\" hx-target=\"#bookmarks-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories/edit\" hx-trigger=\"load\" hx-target=\"#categories-list\" hx-swap=\"innerHTML\"></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " <div hx-get=\"/dashboards/tabs/edit\" hx-trigger=\"load\" hx-target=\"#dashboard-tabs\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories/shelved/edit\" hx-trigger=\"load\" hx-target=\"#shelved-sections\" hx-swap=\"innerHTML\"></div><div hx-get=\"/dashboard/title/bookmarks/edit\" hx-trigger=\"load\" hx-target=\"#bookmarks-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories/edit\" hx-trigger=\"load\" hx-target=\"#categories-list\" hx-swap=\"innerHTML\"></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -66,7 +66,7 @@ func DashboardEdit(input DashboardEditInput) templ.Component {
 						return templ_7745c5c3_Err
 					}
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " <div hx-get=\"/dashboard/title/applications\" hx-trigger=\"load\" hx-target=\"#apps-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/applications\" hx-trigger=\"load\" hx-target=\"#apps-list\" hx-swap=\"innerHTML\"></div><div hx-get=\"/categories/shelved\" hx-trigger=\"load\" hx-target=\"#shelved-sections\" hx-swap=\"innerHTML\"></div><div hx-get=\"/dashboard/title/bookmarks\" hx-trigger=\"load\" hx-target=\"#bookmarks-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories\" hx-trigger=\"load\" hx-target=\"#categories-list\" hx-swap=\"innerHTML\"></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " <div hx-get=\"/dashboard/title/applications\" hx-trigger=\"load\" hx-target=\"#apps-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/applications\" hx-trigger=\"load\" hx-target=\"#apps-list\" hx-swap=\"innerHTML\"></div><div hx-get=\"/dashboards/tabs\" hx-trigger=\"load\" hx-target=\"#dashboard-tabs\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories/shelved\" hx-trigger=\"load\" hx-target=\"#shelved-sections\" hx-swap=\"innerHTML\"></div><div hx-get=\"/dashboard/title/bookmarks\" hx-trigger=\"load\" hx-target=\"#bookmarks-title\" hx-swap=\"outerHTML\"></div><div hx-get=\"/categories\" hx-trigger=\"load\" hx-target=\"#categories-list\" hx-swap=\"innerHTML\"></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
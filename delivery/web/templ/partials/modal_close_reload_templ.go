@@ -1,6 +1,6 @@
 // Code generated by templ - DO NOT EDIT.
 
-// templ: version: v0.3.1001
+// templ: version: v0.3.1020
 package partials
 
 //lint:file-ignore SA4006 This context is only used if a nested component is present.
@@ -13,6 +13,7 @@ type ModalCloseReloadTrigger string
 const (
 	ModalCloseReloadApps       ModalCloseReloadTrigger = "apps-list"
 	ModalCloseReloadCategories ModalCloseReloadTrigger = "categories-list"
+	ModalCloseReloadDashboards ModalCloseReloadTrigger = "dashboard-tabs"
 )
 
 type ModalCloseReloadInput struct {
@@ -55,8 +56,13 @@ func ModalCloseReload(input ModalCloseReloadInput) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+		case ModalCloseReloadDashboards:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<div hx-get=\"/dashboards/tabs/edit\" hx-trigger=\"load\" hx-target=\"#dashboard-tabs\" hx-swap=\"outerHTML\"></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
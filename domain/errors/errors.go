@@ -17,6 +17,15 @@ const (
 	EntitySetting     Entity = iota
 	EntityApplication   Entity = iota
 	EntitySession Entity = iota
+	EntityAccessToken Entity = iota
+	EntitySearchProvider Entity = iota
+	EntityFavicon Entity = iota
+	EntityCustomIcon Entity = iota
+	EntityIdpLink Entity = iota
+	EntityLocalAccount Entity = iota
+	EntityLocalGroup Entity = iota
+	EntityInvitation Entity = iota
+	EntityCategoryShare Entity = iota
 )
 
 func (e Entity) String() string {
@@ -35,6 +44,24 @@ func (e Entity) String() string {
 		return "application"
 	case EntitySession:
 		return "session"
+	case EntityAccessToken:
+		return "access token"
+	case EntitySearchProvider:
+		return "search provider"
+	case EntityFavicon:
+		return "favicon"
+	case EntityCustomIcon:
+		return "icon"
+	case EntityIdpLink:
+		return "linked account"
+	case EntityLocalAccount:
+		return "local account"
+	case EntityLocalGroup:
+		return "group"
+	case EntityInvitation:
+		return "invitation"
+	case EntityCategoryShare:
+		return "share"
 	default:
 		return "entity"
 	}
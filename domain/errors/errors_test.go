@@ -18,6 +18,7 @@ func TestEntity_String(t *testing.T) {
 		{EntitySetting, "setting"},
 		{EntityApplication, "application"},
 		{EntitySession, "session"},
+		{EntityAccessToken, "access token"},
 		{EntityUnknown, "entity"},
 		{Entity(9999), "entity"}, // unknown value falls through to default
 	}
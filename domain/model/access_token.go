@@ -0,0 +1,79 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AccessTokenPrefix marks personal access tokens so they are recognisable in
+// logs and secret scanners. It is part of the secret handed to the user.
+const AccessTokenPrefix = "dash_pat_"
+
+// AccessTokenScope restricts what a personal access token may do.
+type AccessTokenScope string
+
+const (
+	AccessTokenScopeRead      AccessTokenScope = "read"
+	AccessTokenScopeReadWrite AccessTokenScope = "read_write"
+)
+
+// ParseAccessTokenScope validates a scope string.
+func ParseAccessTokenScope(raw string) (AccessTokenScope, error) {
+	switch AccessTokenScope(raw) {
+	case AccessTokenScopeRead, AccessTokenScopeReadWrite:
+		return AccessTokenScope(raw), nil
+	default:
+		return "", fmt.Errorf("access token: unknown scope %q", raw)
+	}
+}
+
+// Permits reports whether a request with the given HTTP method is allowed under
+// this scope. Read-only tokens are limited to safe methods.
+func (s AccessTokenScope) Permits(method string) bool {
+	switch s {
+	case AccessTokenScopeReadWrite:
+		return true
+	case AccessTokenScopeRead:
+		return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	default:
+		return false
+	}
+}
+
+// AccessToken is the read model of a personal access token. The secret itself
+// is never part of it — only its hash is persisted.
+type AccessToken struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Scope      AccessTokenScope `json:"scope"`
+	CreatedAt  time.Time        `json:"created_at"`
+	ExpiresAt  time.Time        `json:"expires_at"`   // zero = never expires
+	LastUsedAt time.Time        `json:"last_used_at"` // zero = never used
+}
+
+// IsExpired reports whether the token has an expiry that lies before now.
+func (t AccessToken) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !t.ExpiresAt.After(now)
+}
+
+// GenerateAccessToken returns a new random token secret with AccessTokenPrefix.
+func GenerateAccessToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("access token: generate: %w", err)
+	}
+	return AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashAccessToken returns the hex-encoded SHA-256 of a token secret. Tokens
+// carry 256 bits of entropy, so a fast unsalted hash is sufficient for lookup.
+func HashAccessToken(raw string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(raw)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAccessTokenScope(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"read", false},
+		{"read_write", false},
+		{"", true},
+		{"write", true},
+		{"READ", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			scope, err := ParseAccessTokenScope(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccessTokenScope(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && string(scope) != tt.input {
+				t.Errorf("ParseAccessTokenScope(%q) = %q", tt.input, scope)
+			}
+		})
+	}
+}
+
+func TestAccessTokenScope_Permits(t *testing.T) {
+	tests := []struct {
+		scope  AccessTokenScope
+		method string
+		want   bool
+	}{
+		{AccessTokenScopeRead, http.MethodGet, true},
+		{AccessTokenScopeRead, http.MethodHead, true},
+		{AccessTokenScopeRead, http.MethodPost, false},
+		{AccessTokenScopeRead, http.MethodPut, false},
+		{AccessTokenScopeRead, http.MethodDelete, false},
+		{AccessTokenScopeReadWrite, http.MethodGet, true},
+		{AccessTokenScopeReadWrite, http.MethodDelete, true},
+		{AccessTokenScope("bogus"), http.MethodGet, false},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.Permits(tt.method); got != tt.want {
+			t.Errorf("%q.Permits(%s) = %v, want %v", tt.scope, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestAccessToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	if (AccessToken{}).IsExpired(now) {
+		t.Error("token without expiry must never be expired")
+	}
+	if !(AccessToken{ExpiresAt: now.Add(-time.Minute)}).IsExpired(now) {
+		t.Error("token with past expiry must be expired")
+	}
+	if (AccessToken{ExpiresAt: now.Add(time.Minute)}).IsExpired(now) {
+		t.Error("token with future expiry must not be expired")
+	}
+}
+
+func TestGenerateAccessToken(t *testing.T) {
+	a, err := GenerateAccessToken()
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	b, err := GenerateAccessToken()
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if !strings.HasPrefix(a, AccessTokenPrefix) {
+		t.Errorf("token %q should start with %q", a, AccessTokenPrefix)
+	}
+	if a == b {
+		t.Error("two generated tokens must differ")
+	}
+}
+
+func TestHashAccessToken(t *testing.T) {
+	h := HashAccessToken("dash_pat_abc")
+	if len(h) != 64 {
+		t.Errorf("hash length = %d, want 64", len(h))
+	}
+	if h != HashAccessToken("dash_pat_abc") {
+		t.Error("hash must be deterministic")
+	}
+	if h == HashAccessToken("dash_pat_abd") {
+		t.Error("different tokens must hash differently")
+	}
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultExpectedStatus is used when a health check does not configure its own
+// expected status codes: any success or redirect counts as up.
+const DefaultExpectedStatus = "200-399"
+
+// StatusCodes is a set of HTTP status codes written as a comma-separated list
+// of codes and inclusive ranges, e.g. "200-299,401". The zero value is not
+// valid; use ParseStatusCodes.
+type StatusCodes struct {
+	raw    string
+	ranges [][2]int
+}
+
+// ParseStatusCodes parses a comma-separated list of status codes and ranges.
+// An empty string yields DefaultExpectedStatus.
+func ParseStatusCodes(raw string) (StatusCodes, error) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), " ", "")
+	if raw == "" {
+		raw = DefaultExpectedStatus
+	}
+	var ranges [][2]int
+	for _, part := range strings.Split(raw, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		from, err := parseStatusCode(lo)
+		if err != nil {
+			return StatusCodes{}, err
+		}
+		to := from
+		if isRange {
+			if to, err = parseStatusCode(hi); err != nil {
+				return StatusCodes{}, err
+			}
+			if to < from {
+				return StatusCodes{}, fmt.Errorf("status codes: range %q is reversed", part)
+			}
+		}
+		ranges = append(ranges, [2]int{from, to})
+	}
+	return StatusCodes{raw: raw, ranges: ranges}, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("status codes: %q is not an HTTP status code", s)
+	}
+	return code, nil
+}
+
+// Contains reports whether code is one of the expected codes.
+func (s StatusCodes) Contains(code int) bool {
+	for _, r := range s.ranges {
+		if code >= r[0] && code <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s StatusCodes) String() string { return s.raw }
+func (s StatusCodes) IsZero() bool   { return s.raw == "" }
+
+// MarshalText encodes the codes in their list form so they serialize as a
+// plain string in JSON.
+func (s StatusCodes) MarshalText() ([]byte, error) { return []byte(s.raw), nil }
+
+// HealthCheck configures how the prober decides whether an application is up.
+type HealthCheck struct {
+	Enabled bool `json:"enabled"`
+	// URL is probed instead of the application URL when set.
+	URL            string      `json:"url,omitempty"`
+	ExpectedStatus StatusCodes `json:"expected_status"`
+}
+
+// HealthState is the outcome of the latest probe of an application.
+type HealthState string
+
+const (
+	HealthUnknown HealthState = ""
+	HealthUp      HealthState = "up"
+	HealthDown    HealthState = "down"
+)
+
+// AppHealth is the latest probe result of an application. The zero value means
+// the application has not been probed yet.
+type AppHealth struct {
+	State     HealthState `json:"state"`
+	Latency   int64       `json:"latency_ms"`
+	CheckedAt time.Time   `json:"checked_at"`
+	Error     string      `json:"error,omitempty"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestParseStatusCodes(t *testing.T) {
+	codes, err := ParseStatusCodes(" 200-299, 401 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if codes.String() != "200-299,401" {
+		t.Errorf("String() = %q", codes.String())
+	}
+	for code, want := range map[int]bool{200: true, 204: true, 299: true, 301: false, 401: true, 500: false} {
+		if got := codes.Contains(code); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParseStatusCodes_DefaultsWhenEmpty(t *testing.T) {
+	codes, err := ParseStatusCodes("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if codes.String() != DefaultExpectedStatus {
+		t.Errorf("String() = %q, want %q", codes.String(), DefaultExpectedStatus)
+	}
+	if !codes.Contains(302) || codes.Contains(404) {
+		t.Error("default should accept 2xx and 3xx only")
+	}
+}
+
+func TestParseStatusCodes_Invalid(t *testing.T) {
+	for _, raw := range []string{"abc", "99", "600", "300-200", "200,", "200--300"} {
+		if _, err := ParseStatusCodes(raw); err == nil {
+			t.Errorf("ParseStatusCodes(%q) should fail", raw)
+		}
+	}
+}
@@ -6,4 +6,7 @@ type AppLink struct {
 	DisplayName     string      `json:"display_name"`
 	Url             BookmarkURL `json:"url"`
 	VisibleToGroups []string    `json:"visible_to_groups"`
+	Position        int         `json:"position"`
+	HealthCheck     HealthCheck `json:"health_check"`
+	Health          AppHealth   `json:"health"`
 }
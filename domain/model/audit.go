@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// AuditAction names a security-relevant or administrative action recorded in
+// the audit log.
+type AuditAction string
+
+const (
+	AuditActionLogin              AuditAction = "login"
+	AuditActionLoginFailed        AuditAction = "login_failed"
+	AuditActionApplicationCreate  AuditAction = "application.create"
+	AuditActionApplicationDelete  AuditAction = "application.delete"
+	AuditActionDataImport         AuditAction = "data.import"
+	AuditActionUserDelete         AuditAction = "user.delete"
+	AuditActionUserSessionsRevoke AuditAction = "user.sessions_revoke"
+	AuditActionSessionPin         AuditAction = "session.pin"
+	AuditActionSessionUnpin       AuditAction = "session.unpin"
+	AuditActionSessionRevoke      AuditAction = "session.revoke"
+	AuditActionInvitationCreate   AuditAction = "invitation.create"
+	AuditActionInvitationRedeem   AuditAction = "invitation.redeem"
+	AuditActionInvitationRevoke   AuditAction = "invitation.revoke"
+	AuditActionCategoryPublish    AuditAction = "category.publish"
+	AuditActionCategoryUnpublish  AuditAction = "category.unpublish"
+	AuditActionCategoryShare      AuditAction = "category.share"
+	AuditActionCategoryUnshare    AuditAction = "category.unshare"
+)
+
+// AuditActions lists all actions in the order they are offered as filters.
+var AuditActions = []AuditAction{
+	AuditActionLogin,
+	AuditActionLoginFailed,
+	AuditActionApplicationCreate,
+	AuditActionApplicationDelete,
+	AuditActionDataImport,
+	AuditActionUserDelete,
+	AuditActionUserSessionsRevoke,
+	AuditActionSessionPin,
+	AuditActionSessionUnpin,
+	AuditActionSessionRevoke,
+	AuditActionInvitationCreate,
+	AuditActionInvitationRedeem,
+	AuditActionInvitationRevoke,
+	AuditActionCategoryPublish,
+	AuditActionCategoryUnpublish,
+	AuditActionCategoryShare,
+	AuditActionCategoryUnshare,
+}
+
+// IsValid reports whether a is one of the known audit actions.
+func (a AuditAction) IsValid() bool {
+	for _, known := range AuditActions {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditActor identifies who performed an audited action and from where.
+type AuditActor struct {
+	UserID string // empty = not signed in, e.g. a failed login
+	IP     string
+}
+
+// AuditEntry is one record of the audit log. Target names what the action
+// applied to — a user ID, an application or category name, a session ID, the
+// sign-in issuer or the username of a failed login — and is empty when the
+// action has no target besides the actor.
+type AuditEntry struct {
+	ID        uint
+	ActorID   string
+	Action    AuditAction
+	Target    string
+	IP        string
+	CreatedAt time.Time
+}
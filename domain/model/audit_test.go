@@ -0,0 +1,16 @@
+package model
+
+import "testing"
+
+func TestAuditAction_IsValid(t *testing.T) {
+	for _, a := range AuditActions {
+		if !a.IsValid() {
+			t.Errorf("%q.IsValid() = false, want true", a)
+		}
+	}
+	for _, a := range []AuditAction{"", "delete", "LOGIN"} {
+		if a.IsValid() {
+			t.Errorf("%q.IsValid() = true, want false", a)
+		}
+	}
+}
@@ -6,6 +6,9 @@ type Bookmark struct {
 	DisplayName string      `json:"display_name"`
 	Url         BookmarkURL `json:"url"`
 	CategoryID  uint        `json:"category_id"`
+	Position    int         `json:"position"`
+	// Tags are normalized, sorted and unique; see NormalizeTags.
+	Tags []string `json:"tags"`
 }
 
 // UpdateIcon replaces the bookmark's icon.
@@ -19,3 +22,13 @@ func (b *Bookmark) ChangeURL(url BookmarkURL) { b.Url = url }
 
 // MoveTo reassigns the bookmark to a different category.
 func (b *Bookmark) MoveTo(categoryID uint) { b.CategoryID = categoryID }
+
+// Tag replaces the bookmark's tags with the normalized tags.
+func (b *Bookmark) Tag(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	b.Tags = normalized
+	return nil
+}
@@ -33,3 +33,17 @@ func (u BookmarkURL) Host() string {
 }
 
 func (u BookmarkURL) IsZero() bool { return u.value == "" }
+
+// MarshalText encodes the URL as its raw string so it serializes as a plain
+// string in JSON.
+func (u BookmarkURL) MarshalText() ([]byte, error) { return []byte(u.value), nil }
+
+// UnmarshalText parses a raw URL string, applying the same validation as ParseBookmarkURL.
+func (u *BookmarkURL) UnmarshalText(text []byte) error {
+	parsed, err := ParseBookmarkURL(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
@@ -1,6 +1,9 @@
 package model
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestParseBookmarkURL(t *testing.T) {
 	tests := []struct {
@@ -60,3 +63,29 @@ func TestBookmarkURLZeroValue(t *testing.T) {
 		t.Errorf("zero BookmarkURL.Host() = %q, want \"\"", u.Host())
 	}
 }
+
+func TestBookmarkURLJSON(t *testing.T) {
+	u, err := ParseBookmarkURL("https://example.com/path")
+	if err != nil {
+		t.Fatalf("ParseBookmarkURL: %v", err)
+	}
+	data, err := json.Marshal(u)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if string(data) != `"https://example.com/path"` {
+		t.Errorf("json.Marshal(BookmarkURL) = %s, want \"https://example.com/path\"", data)
+	}
+
+	var decoded BookmarkURL
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if decoded != u {
+		t.Errorf("round trip = %v, want %v", decoded, u)
+	}
+
+	if err := json.Unmarshal([]byte(`"not a url"`), &decoded); err == nil {
+		t.Error("json.Unmarshal should reject invalid URLs")
+	}
+}
@@ -2,14 +2,27 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
+// MaxCategoryDepth is how many levels deep categories may be nested, counting
+// the top level.
+const MaxCategoryDepth = 3
+
 type Category struct {
 	ID          uint       `json:"id"`
+	DashboardID uint       `json:"dashboard_id,omitempty"` // only set when the owner loads a single category
+	ParentID    uint       `json:"parent_id,omitempty"`    // 0 for top-level categories
 	DisplayName string     `json:"display_name"`
 	IsShelved   bool       `json:"is_shelved"`
+	Position    int        `json:"position"`
 	Bookmarks   []Bookmark `json:"bookmarks"`
+	Children    []Category `json:"children,omitempty"`
+	// TagQuery makes the category a smart category: instead of holding
+	// bookmarks of its own it shows the bookmarks of its dashboard that match
+	// the query. It is empty for regular categories.
+	TagQuery string `json:"tag_query,omitempty"`
 }
 
 // NewCategory creates a Category with a validated display name.
@@ -36,3 +49,150 @@ func (c *Category) Rename(name string) error {
 	c.DisplayName = name
 	return nil
 }
+
+// Nest moves the category below the category parentID, or to the top level
+// when parentID is 0. Only top-level categories can be shelved; their
+// sub-categories are shelved along with them.
+func (c *Category) Nest(parentID uint) error {
+	if parentID != 0 && c.IsShelved {
+		return errors.New("category: only top-level categories can be shelved")
+	}
+	c.ParentID = parentID
+	return nil
+}
+
+// IsSmart reports whether the category is a smart category.
+func (c *Category) IsSmart() bool { return c.TagQuery != "" }
+
+// Query sets the tag query of a smart category in canonical form, or turns
+// the category into a regular one when raw is blank.
+func (c *Category) Query(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		c.TagQuery = ""
+		return nil
+	}
+	query, err := ParseTagQuery(raw)
+	if err != nil {
+		return err
+	}
+	c.TagQuery = query.String()
+	return nil
+}
+
+// CategoryTree maps the ID of every category of a dashboard to the ID of its
+// parent, 0 for top-level categories.
+type CategoryTree map[uint]uint
+
+// Depth returns the level of the category id, 1 for top-level categories.
+func (t CategoryTree) Depth(id uint) int {
+	depth := 1
+	// Bounded by the tree size so that corrupt data cannot loop forever.
+	for parent := t[id]; parent != 0 && depth <= len(t); parent = t[parent] {
+		depth++
+	}
+	return depth
+}
+
+// Height returns how many levels the subtree rooted at id spans, 1 for a
+// category without sub-categories.
+func (t CategoryTree) Height(id uint) int {
+	return t.height(id, len(t))
+}
+
+func (t CategoryTree) height(id uint, budget int) int {
+	height := 1
+	if budget <= 0 {
+		return height
+	}
+	for child, parent := range t {
+		if parent == id && child != id {
+			height = max(height, t.height(child, budget-1)+1)
+		}
+	}
+	return height
+}
+
+// Contains reports whether id is root or one of its descendants.
+func (t CategoryTree) Contains(root, id uint) bool {
+	for i := 0; id != 0 && i <= len(t); i++ {
+		if id == root {
+			return true
+		}
+		id = t[id]
+	}
+	return false
+}
+
+// CheckParent reports whether the category id, whose subtree spans height
+// levels, may be nested below parentID. id is 0 for a category that does not
+// exist yet or is not part of the tree; parentID 0 is the top level.
+func (t CategoryTree) CheckParent(id, parentID uint, height int) error {
+	if parentID == 0 {
+		return nil
+	}
+	if _, ok := t[parentID]; !ok {
+		return errors.New("category: unknown parent category")
+	}
+	if id != 0 && t.Contains(id, parentID) {
+		return errors.New("category: cannot be nested inside itself")
+	}
+	if t.Depth(parentID)+height > MaxCategoryDepth {
+		return fmt.Errorf("category: categories nest at most %d levels deep", MaxCategoryDepth)
+	}
+	return nil
+}
+
+// NestCategories arranges a flat list of categories into a tree following
+// their ParentID, keeping the order of the list among siblings. Categories
+// whose parent is not part of the list end up at the top level.
+func NestCategories(flat []Category) []Category {
+	known := make(map[uint]bool, len(flat))
+	for _, c := range flat {
+		known[c.ID] = true
+	}
+	children := make(map[uint][]Category)
+	var roots []Category
+	for _, c := range flat {
+		if c.ParentID != 0 && c.ParentID != c.ID && known[c.ParentID] {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	visited := make(map[uint]bool, len(flat))
+	var attach func(list []Category) []Category
+	attach = func(list []Category) []Category {
+		if len(list) == 0 {
+			return nil
+		}
+		result := make([]Category, 0, len(list))
+		for _, c := range list {
+			if visited[c.ID] {
+				continue
+			}
+			visited[c.ID] = true
+			c.Children = attach(children[c.ID])
+			result = append(result, c)
+		}
+		return result
+	}
+	return attach(roots)
+}
+
+// FlattenCategories lists the categories of a tree depth-first, parents
+// before their sub-categories. The returned categories have no Children.
+func FlattenCategories(tree []Category) []Category {
+	var flat []Category
+	var walk func(list []Category)
+	walk = func(list []Category) {
+		for _, c := range list {
+			children := c.Children
+			c.Children = nil
+			flat = append(flat, c)
+			walk(children)
+		}
+	}
+	walk(tree)
+	return flat
+}
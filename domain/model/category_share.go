@@ -0,0 +1,47 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// CategoryRole is the access a user has to a category another user shared
+// with them.
+type CategoryRole string
+
+const (
+	// CategoryRoleViewer sees the category and its bookmarks.
+	CategoryRoleViewer CategoryRole = "viewer"
+	// CategoryRoleEditor may also add, edit, reorder and remove bookmarks.
+	CategoryRoleEditor CategoryRole = "editor"
+)
+
+// ParseCategoryRole validates a role name.
+func ParseCategoryRole(raw string) (CategoryRole, error) {
+	switch role := CategoryRole(raw); role {
+	case CategoryRoleViewer, CategoryRoleEditor:
+		return role, nil
+	default:
+		return "", fmt.Errorf("category share: unknown role %q", raw)
+	}
+}
+
+// CategoryShare grants a user access to a category of another user's
+// dashboard, as seen by the owner.
+type CategoryShare struct {
+	CategoryID uint
+	UserID     string
+	UserLabel  string // name of the user from their latest session; empty if unknown
+	Role       CategoryRole
+	SharedAt   time.Time
+}
+
+// ReceivedCategory is a category another user shared with the current user.
+// Unlike a SharedCategory it is shared with the user directly, and editors
+// may change its bookmarks.
+type ReceivedCategory struct {
+	Category
+	Role       CategoryRole `json:"role"`
+	OwnerID    string       `json:"-"`
+	OwnerLabel string       `json:"owner"` // empty if unknown
+}
@@ -0,0 +1,16 @@
+package model
+
+import "testing"
+
+func TestParseCategoryRole(t *testing.T) {
+	for _, raw := range []string{"viewer", "editor"} {
+		if role, err := ParseCategoryRole(raw); err != nil || string(role) != raw {
+			t.Errorf("ParseCategoryRole(%q) = %q, %v", raw, role, err)
+		}
+	}
+	for _, raw := range []string{"", "owner", "Editor"} {
+		if _, err := ParseCategoryRole(raw); err == nil {
+			t.Errorf("ParseCategoryRole(%q) should fail", raw)
+		}
+	}
+}
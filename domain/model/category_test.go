@@ -85,3 +85,127 @@ func TestCategoryRename(t *testing.T) {
 		})
 	}
 }
+
+func TestCategoryNest(t *testing.T) {
+	c := Category{ID: 2}
+	if err := c.Nest(1); err != nil {
+		t.Fatalf("Nest(1) unexpected error: %v", err)
+	}
+	if c.ParentID != 1 {
+		t.Errorf("ParentID = %d, want 1", c.ParentID)
+	}
+
+	shelved := Category{ID: 3, IsShelved: true}
+	if err := shelved.Nest(1); err == nil {
+		t.Error("Nest on a shelved category expected error, got nil")
+	}
+	if err := shelved.Nest(0); err != nil {
+		t.Errorf("Nest(0) on a shelved category unexpected error: %v", err)
+	}
+}
+
+// treeFixture is Infrastructure(1) → Proxmox(2) → Nodes(3), plus Dev(4) at the top level.
+func treeFixture() CategoryTree {
+	return CategoryTree{1: 0, 2: 1, 3: 2, 4: 0}
+}
+
+func TestCategoryTreeDepthAndHeight(t *testing.T) {
+	tree := treeFixture()
+	for id, want := range map[uint]int{1: 1, 2: 2, 3: 3, 4: 1} {
+		if got := tree.Depth(id); got != want {
+			t.Errorf("Depth(%d) = %d, want %d", id, got, want)
+		}
+	}
+	for id, want := range map[uint]int{1: 3, 2: 2, 3: 1, 4: 1} {
+		if got := tree.Height(id); got != want {
+			t.Errorf("Height(%d) = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestCategoryTreeDepth_Cycle(t *testing.T) {
+	tree := CategoryTree{1: 2, 2: 1}
+	if got := tree.Depth(1); got > len(tree)+1 {
+		t.Errorf("Depth on a cycle = %d, want it bounded", got)
+	}
+	_ = tree.Height(1)
+}
+
+func TestCategoryTreeCheckParent(t *testing.T) {
+	tree := treeFixture()
+	tests := []struct {
+		name     string
+		id       uint
+		parentID uint
+		height   int
+		wantErr  bool
+	}{
+		{"top level", 2, 0, 2, false},
+		{"new below top level", 0, 1, 1, false},
+		{"new below second level", 0, 2, 1, false},
+		{"new below third level", 0, 3, 1, true},
+		{"unknown parent", 0, 99, 1, true},
+		{"into itself", 2, 2, 2, true},
+		{"into own descendant", 1, 3, 3, true},
+		{"subtree too high", 1, 4, 3, true},
+		{"subtree fits", 2, 4, 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.CheckParent(tt.id, tt.parentID, tt.height)
+			if tt.wantErr && err == nil {
+				t.Errorf("CheckParent(%d, %d, %d) expected error, got nil", tt.id, tt.parentID, tt.height)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckParent(%d, %d, %d) unexpected error: %v", tt.id, tt.parentID, tt.height, err)
+			}
+		})
+	}
+}
+
+func TestNestAndFlattenCategories(t *testing.T) {
+	flat := []Category{
+		{ID: 1, DisplayName: "Infrastructure"},
+		{ID: 4, DisplayName: "Dev"},
+		{ID: 2, ParentID: 1, DisplayName: "Proxmox"},
+		{ID: 5, ParentID: 1, DisplayName: "Network"},
+		{ID: 3, ParentID: 2, DisplayName: "Nodes"},
+		{ID: 6, ParentID: 99, DisplayName: "Orphan"},
+	}
+
+	tree := NestCategories(flat)
+	if len(tree) != 3 {
+		t.Fatalf("len(tree) = %d, want 3", len(tree))
+	}
+	if tree[0].DisplayName != "Infrastructure" || tree[1].DisplayName != "Dev" || tree[2].DisplayName != "Orphan" {
+		t.Errorf("roots = %q, %q, %q", tree[0].DisplayName, tree[1].DisplayName, tree[2].DisplayName)
+	}
+	infra := tree[0]
+	if len(infra.Children) != 2 || infra.Children[0].DisplayName != "Proxmox" || infra.Children[1].DisplayName != "Network" {
+		t.Fatalf("Infrastructure children = %+v", infra.Children)
+	}
+	if len(infra.Children[0].Children) != 1 || infra.Children[0].Children[0].DisplayName != "Nodes" {
+		t.Errorf("Proxmox children = %+v", infra.Children[0].Children)
+	}
+	if tree[1].Children != nil {
+		t.Errorf("Dev children = %+v, want nil", tree[1].Children)
+	}
+
+	var names []string
+	for _, c := range FlattenCategories(tree) {
+		if c.Children != nil {
+			t.Errorf("flattened %q still has children", c.DisplayName)
+		}
+		names = append(names, c.DisplayName)
+	}
+	want := []string{"Infrastructure", "Proxmox", "Nodes", "Network", "Dev", "Orphan"}
+	if len(names) != len(want) {
+		t.Fatalf("flattened = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("flattened = %v, want %v", names, want)
+			break
+		}
+	}
+}
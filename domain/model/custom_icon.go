@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CustomIconType is the icon type of images uploaded to the icon library; the
+// name is the id of the image, e.g. "img:12".
+const CustomIconType = "img"
+
+// MaxCustomIconBytes bounds the size of an uploaded icon file.
+const MaxCustomIconBytes = 1 << 20
+
+// CustomIcon is an image in the icon library. Shared icons belong to no user;
+// admins manage them and every user may show them, e.g. on applications.
+type CustomIcon struct {
+	ID          uint   `json:"id"`
+	DisplayName string `json:"display_name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+	IsShared    bool   `json:"is_shared"`
+}
+
+// Icon returns the reference under which bookmarks and applications show the
+// image.
+func (i CustomIcon) Icon() Icon {
+	return Icon{iconType: CustomIconType, name: strconv.FormatUint(uint64(i.ID), 10)}
+}
+
+// ParseCustomIconID parses the name of a custom icon into the id of its image.
+func ParseCustomIconID(name string) (uint, error) {
+	id, err := strconv.ParseUint(name, 10, 64)
+	if err != nil || id == 0 || strconv.FormatUint(id, 10) != name {
+		return 0, fmt.Errorf("img: %q is not a valid icon id", name)
+	}
+	return uint(id), nil
+}
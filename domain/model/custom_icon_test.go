@@ -0,0 +1,49 @@
+package model
+
+import "testing"
+
+func TestParseCustomIconID(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    uint
+		wantErr bool
+	}{
+		{"1", 1, false},
+		{"4711", 4711, false},
+		{"", 0, true},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"+1", 0, true},
+		{"01", 0, true},
+		{"logo", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCustomIconID(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseCustomIconID(%q) = %d, expected error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCustomIconID(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCustomIconID(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomIcon_Icon(t *testing.T) {
+	icon := CustomIcon{ID: 12}.Icon()
+
+	if icon.String() != "img:12" {
+		t.Errorf("Icon() = %q, want %q", icon.String(), "img:12")
+	}
+	if _, err := ParseIcon(icon.String()); err != nil {
+		t.Errorf("ParseIcon(%q) unexpected error: %v", icon.String(), err)
+	}
+}
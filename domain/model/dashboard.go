@@ -1,6 +1,46 @@
 package model
 
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultDashboardName is the name of the dashboard every user starts with.
+const DefaultDashboardName = "Dashboard"
+
+// MaxDashboardNameLength is the longest name a dashboard may have.
+const MaxDashboardNameLength = 64
+
 type Dashboard struct {
+	ID           uint       `json:"id"`
+	Name         string     `json:"name"`
+	IsDefault    bool       `json:"is_default"`
 	Applications []AppLink  `json:"applications"`
 	Categories   []Category `json:"categories"`
+	// ReceivedCategories are the categories other users shared with the user.
+	// Only the default dashboard carries them.
+	ReceivedCategories []ReceivedCategory `json:"received_categories"`
+	// SharedCategories are the published categories the user sees and has
+	// not hidden. Only the default dashboard carries them.
+	SharedCategories []SharedCategory `json:"shared_categories"`
+}
+
+// DashboardPage is one of a user's dashboards, without its content.
+type DashboardPage struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Position  int    `json:"position"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// ParseDashboardName trims and validates a dashboard name.
+func ParseDashboardName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	switch {
+	case name == "":
+		return "", errors.New("dashboard: name must not be empty")
+	case len(name) > MaxDashboardNameLength:
+		return "", errors.New("dashboard: name is too long")
+	}
+	return name, nil
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDashboardName(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"Work", "Work", false},
+		{"  Home lab ", "Home lab", false},
+		{"", "", true},
+		{"   ", "", true},
+		{strings.Repeat("a", MaxDashboardNameLength), strings.Repeat("a", MaxDashboardNameLength), false},
+		{strings.Repeat("a", MaxDashboardNameLength+1), "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDashboardName(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDashboardName(%q) = %q, %v; want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
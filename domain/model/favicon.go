@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FaviconIconType is the icon type whose name is a site; the icon shows that
+// site's favicon, e.g. "favicon:github.com".
+const FaviconIconType = "favicon"
+
+// Favicon is the cached icon of a site. Empty Data records a site without a
+// usable icon so that it is not fetched again on every request.
+type Favicon struct {
+	Site        string
+	ContentType string
+	Data        []byte
+	FetchedAt   time.Time
+}
+
+// IsExpired reports whether the favicon was fetched longer than ttl ago.
+func (f Favicon) IsExpired(now time.Time, ttl time.Duration) bool {
+	return now.Sub(f.FetchedAt) >= ttl
+}
+
+// ParseFaviconSite normalises the name of a favicon icon to the origin of the
+// site, e.g. "https://github.com" for "github.com". URLs keep their scheme and
+// port; paths, queries and fragments are dropped.
+func ParseFaviconSite(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("favicon: site must not be empty")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("favicon: %q is not a valid site", raw)
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host), nil
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFaviconSite(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"github.com", "https://github.com", false},
+		{"GitHub.com", "https://github.com", false},
+		{" github.com ", "https://github.com", false},
+		{"http://nas.lan:5000", "http://nas.lan:5000", false},
+		{"https://git.example.com/explore?q=x#top", "https://git.example.com", false},
+		{"", "", true},
+		{"ftp://nas.lan", "", true},
+		{"https://", "", true},
+		{"javascript:alert(1)", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFaviconSite(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseFaviconSite(%q) = %q, expected error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFaviconSite(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFaviconSite(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaviconIsExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	f := Favicon{FetchedAt: now.Add(-time.Hour)}
+
+	if f.IsExpired(now, 2*time.Hour) {
+		t.Error("favicon fetched an hour ago should not expire with a 2h TTL")
+	}
+	if !f.IsExpired(now, time.Hour) {
+		t.Error("favicon fetched an hour ago should expire with a 1h TTL")
+	}
+}
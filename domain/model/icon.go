@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-var knownIconTypes = []string{"mdi", "spi"}
+var knownIconTypes = []string{"mdi", "spi", FaviconIconType, CustomIconType}
 
 // Icon represents a namespaced icon reference in "type:name" format (e.g. "mdi:home").
 // The zero value is not a valid Icon; use ParseIcon or NewIcon to construct one.
@@ -41,6 +41,16 @@ func NewIcon(iconType, name string) (Icon, error) {
 	if !valid {
 		return Icon{}, fmt.Errorf("icon: unknown type %q, must be one of %v", iconType, knownIconTypes)
 	}
+	if iconType == FaviconIconType {
+		if _, err := ParseFaviconSite(name); err != nil {
+			return Icon{}, err
+		}
+	}
+	if iconType == CustomIconType {
+		if _, err := ParseCustomIconID(name); err != nil {
+			return Icon{}, err
+		}
+	}
 	return Icon{iconType: iconType, name: name}, nil
 }
 
@@ -49,5 +59,19 @@ func (i Icon) Name() string   { return i.name }
 func (i Icon) String() string { return i.iconType + ":" + i.name }
 func (i Icon) IsZero() bool   { return i.iconType == "" }
 
+// MarshalText encodes the icon in its "type:name" form so it serializes as a
+// plain string in JSON.
+func (i Icon) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText parses a "type:name" string, applying the same validation as ParseIcon.
+func (i *Icon) UnmarshalText(text []byte) error {
+	parsed, err := ParseIcon(string(text))
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
+
 // KnownIconTypes returns the list of valid icon type prefixes.
 func KnownIconTypes() []string { return knownIconTypes }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -15,6 +16,13 @@ func TestParseIcon(t *testing.T) {
 		{"mdi:arrow-left", false, "mdi", "arrow-left"},
 		{"spi:server", false, "spi", "server"},
 		{"spi:some:name", false, "spi", "some:name"}, // SplitN(2) keeps remainder
+		{"favicon:github.com", false, "favicon", "github.com"},
+		{"favicon:http://nas.lan:5000", false, "favicon", "http://nas.lan:5000"},
+		{"favicon:ftp://nas.lan", true, "", ""},
+		{"img:12", false, "img", "12"},
+		{"img:0", true, "", ""},
+		{"img:012", true, "", ""},
+		{"img:logo", true, "", ""},
 		{"", true, "", ""},
 		{"mdi", true, "", ""},
 		{"nocolon", true, "", ""},
@@ -115,3 +123,29 @@ func TestKnownIconTypes(t *testing.T) {
 		t.Error("KnownIconTypes() must include \"spi\"")
 	}
 }
+
+func TestIconJSON(t *testing.T) {
+	icon, err := ParseIcon("mdi:home")
+	if err != nil {
+		t.Fatalf("ParseIcon: %v", err)
+	}
+	data, err := json.Marshal(icon)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if string(data) != `"mdi:home"` {
+		t.Errorf("json.Marshal(Icon) = %s, want \"mdi:home\"", data)
+	}
+
+	var decoded Icon
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if decoded != icon {
+		t.Errorf("round trip = %v, want %v", decoded, icon)
+	}
+
+	if err := json.Unmarshal([]byte(`"bogus:home"`), &decoded); err == nil {
+		t.Error("json.Unmarshal should reject unknown icon types")
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/samber/lo"
+import (
+	"slices"
+
+	"github.com/samber/lo"
+)
 
 // Identity represents the authenticated user making a request.
 // UserID is always the OIDC sub claim — stable across username/email changes.
@@ -18,15 +22,42 @@ type Identity struct {
 	ProfileUrl  *string  `json:"profile_url"`
 }
 
+// Synthetic groups that every identity gets in addition to its IdP groups.
+const (
+	SyntheticGroupUser  = "dash_user"
+	SyntheticGroupAdmin = "dash_admin"
+)
+
 // WithSyntheticGroups returns a copy of the identity with synthetic groups
 // injected: dash_user for every authenticated user, dash_admin for admins.
 // These groups allow applications to be scoped to all users or all admins
 // without depending on IdP-specific group names.
 func (i Identity) WithSyntheticGroups() Identity {
-	groups := append([]string{"dash_user"}, i.Groups...)
+	groups := append([]string{SyntheticGroupUser}, i.Groups...)
 	if i.IsAdmin {
-		groups = append(groups, "dash_admin")
+		groups = append(groups, SyntheticGroupAdmin)
 	}
 	i.Groups = lo.Uniq(groups)
 	return i
 }
+
+// WithGrantedGroups returns a copy of the identity that is also a member of
+// the given groups, e.g. those granted by a guest invitation.
+func (i Identity) WithGrantedGroups(groups []string) Identity {
+	i.Groups = lo.Uniq(append(slices.Clone(i.Groups), groups...))
+	return i
+}
+
+// WithLocalGroups returns a copy of the identity that is also a member of the
+// Dash-managed groups that include it, either directly or through one of its
+// IdP or synthetic groups.
+func (i Identity) WithLocalGroups(groups []LocalGroup) Identity {
+	merged := slices.Clone(i.Groups)
+	for _, g := range groups {
+		if g.Includes(i.UserID, i.Groups) {
+			merged = append(merged, g.Name)
+		}
+	}
+	i.Groups = lo.Uniq(merged)
+	return i
+}
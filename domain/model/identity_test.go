@@ -93,3 +93,17 @@ func TestWithSyntheticGroups_EmptyGroups(t *testing.T) {
 		t.Error("user with no groups should still get dash_user")
 	}
 }
+
+func TestWithGrantedGroups(t *testing.T) {
+	original := make([]string, 2, 4)
+	copy(original, []string{"dash_user", "family"})
+	id := Identity{UserID: "user-1", Groups: original}
+	result := id.WithGrantedGroups([]string{"family", "media"})
+
+	if !slices.Equal(result.Groups, []string{"dash_user", "family", "media"}) {
+		t.Errorf("Groups = %v, want [dash_user family media]", result.Groups)
+	}
+	if len(id.Groups) != 2 || original[:3][2] != "" {
+		t.Error("WithGrantedGroups must not mutate the original identity")
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// IdpLink is an identity from an external identity provider that signs in to
+// a user's account. Every account has at least one link.
+type IdpLink struct {
+	Issuer    string
+	Sub       string
+	IsPrimary bool // the identity the account was originally created with
+	LinkedAt  time.Time
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InvitationTokenPrefix marks invitation secrets. Like AccessTokenPrefix it is
+// part of the secret that ends up in the invitation link.
+const InvitationTokenPrefix = "dash_inv_"
+
+// Invitation is a link that admins hand out to guests. Whoever signs in and
+// opens it before it expires is granted its groups for GrantDuration, until
+// MaxUses guests have redeemed it.
+type Invitation struct {
+	ID            string
+	Groups        []string
+	MaxUses       int
+	Uses          int
+	ExpiresAt     time.Time
+	GrantDuration time.Duration
+	CreatedBy     string // user ID of the admin
+	CreatedAt     time.Time
+	Redemptions   []InvitationRedemption
+}
+
+// IsExpired reports whether the link can no longer be opened because of its
+// age.
+func (i Invitation) IsExpired(now time.Time) bool {
+	return !i.ExpiresAt.After(now)
+}
+
+// IsUsedUp reports whether the link has reached its usage limit.
+func (i Invitation) IsUsedUp() bool {
+	return i.Uses >= i.MaxUses
+}
+
+// InvitationRedemption records that a user opened an invitation and holds
+// its groups until ExpiresAt.
+type InvitationRedemption struct {
+	ID           uint
+	InvitationID string
+	UserID       string
+	Groups       []string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// IsActive reports whether the redemption still grants its groups.
+func (r InvitationRedemption) IsActive(now time.Time) bool {
+	return r.ExpiresAt.After(now)
+}
+
+// GenerateInvitationToken returns a new random invitation secret with
+// InvitationTokenPrefix.
+func GenerateInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("invitation: generate: %w", err)
+	}
+	return InvitationTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashInvitationToken returns the hex-encoded SHA-256 of an invitation secret.
+// See HashAccessToken for why an unsalted hash is sufficient.
+func HashInvitationToken(raw string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(raw)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvitation_IsExpired(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	if (Invitation{ExpiresAt: now.Add(time.Hour)}).IsExpired(now) {
+		t.Error("invitation expiring later should not be expired")
+	}
+	if !(Invitation{ExpiresAt: now}).IsExpired(now) {
+		t.Error("invitation expiring now should be expired")
+	}
+}
+
+func TestInvitation_IsUsedUp(t *testing.T) {
+	if (Invitation{MaxUses: 2, Uses: 1}).IsUsedUp() {
+		t.Error("invitation with uses left should not be used up")
+	}
+	if !(Invitation{MaxUses: 2, Uses: 2}).IsUsedUp() {
+		t.Error("invitation at its limit should be used up")
+	}
+}
+
+func TestInvitationRedemption_IsActive(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	if !(InvitationRedemption{ExpiresAt: now.Add(time.Hour)}).IsActive(now) {
+		t.Error("redemption expiring later should be active")
+	}
+	if (InvitationRedemption{ExpiresAt: now.Add(-time.Hour)}).IsActive(now) {
+		t.Error("expired redemption should not be active")
+	}
+}
+
+func TestGenerateInvitationToken(t *testing.T) {
+	a, err := GenerateInvitationToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateInvitationToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(a, InvitationTokenPrefix) {
+		t.Errorf("token %q lacks prefix %q", a, InvitationTokenPrefix)
+	}
+	if a == b {
+		t.Error("tokens should be random")
+	}
+	if HashInvitationToken(a) != HashInvitationToken(" "+a+" ") {
+		t.Error("hash should ignore surrounding whitespace")
+	}
+}
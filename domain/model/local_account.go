@@ -0,0 +1,56 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// LocalAccount is a user account that signs in with a password managed by
+// Dash instead of an external identity provider.
+type LocalAccount struct {
+	Username    string
+	DisplayName string
+	Email       string
+	Groups      []string
+	Managed     bool // defined in the users file; cannot be changed in the UI
+	CreatedAt   time.Time
+}
+
+// Password length limits for local accounts. bcrypt ignores everything after
+// 72 bytes, which makes it the upper bound.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// NormalizeUsername trims and lower-cases a local account username, so
+// sign-in is case-insensitive.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// HashPassword returns the bcrypt hash of a local account password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches the bcrypt hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// IsPasswordHash reports whether s looks like a bcrypt hash, so configuration
+// values may hold either a hash or a plain password.
+func IsPasswordHash(s string) bool {
+	if !strings.HasPrefix(s, "$2") {
+		return false
+	}
+	_, err := bcrypt.Cost([]byte(s))
+	return err == nil
+}
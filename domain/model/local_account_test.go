@@ -0,0 +1,40 @@
+package model
+
+import "testing"
+
+func TestHashPassword_CheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("CheckPassword() = false for the right password")
+	}
+	if CheckPassword(hash, "battery staple") {
+		t.Error("CheckPassword() = true for a wrong password")
+	}
+	if CheckPassword("not-a-hash", "correct horse") {
+		t.Error("CheckPassword() = true for an invalid hash")
+	}
+}
+
+func TestIsPasswordHash(t *testing.T) {
+	hash, err := HashPassword("secret123")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{hash, true},
+		{"secret123", false},
+		{"$2a$", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsPasswordHash(tt.in); got != tt.want {
+			t.Errorf("IsPasswordHash(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
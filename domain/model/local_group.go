@@ -0,0 +1,52 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxLocalGroupNameLength is the longest name a local group may have.
+const MaxLocalGroupNameLength = 64
+
+// LocalGroup is a group managed in Dash rather than at the IdP. Its members
+// are the users an admin assigned plus everyone in one of the mapped IdP
+// groups. Applications are scoped to it by name like to any IdP group.
+type LocalGroup struct {
+	ID        uint
+	Name      string
+	IdpGroups []string // IdP groups whose members belong to this group
+	Members   []string // user IDs of the directly assigned users
+	CreatedAt time.Time
+}
+
+// Includes reports whether the user belongs to the group, directly or through
+// one of the given IdP groups.
+func (g LocalGroup) Includes(userID string, idpGroups []string) bool {
+	if slices.Contains(g.Members, userID) {
+		return true
+	}
+	return slices.ContainsFunc(g.IdpGroups, func(idpGroup string) bool {
+		return slices.Contains(idpGroups, idpGroup)
+	})
+}
+
+// ParseLocalGroupName trims and validates a group name. Names cannot contain
+// whitespace, since application group lists are separated by spaces, and
+// cannot be one of the synthetic groups.
+func ParseLocalGroupName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	switch {
+	case name == "":
+		return "", errors.New("name is required")
+	case len(name) > MaxLocalGroupNameLength:
+		return "", errors.New("name is too long")
+	case strings.ContainsFunc(name, unicode.IsSpace):
+		return "", errors.New("name must not contain spaces")
+	case name == SyntheticGroupUser || name == SyntheticGroupAdmin:
+		return "", errors.New("name is reserved")
+	}
+	return name, nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLocalGroup_Includes(t *testing.T) {
+	g := LocalGroup{Name: "friends", IdpGroups: []string{"family"}, Members: []string{"user-1"}}
+
+	if !g.Includes("user-1", nil) {
+		t.Error("assigned member should be included")
+	}
+	if !g.Includes("user-2", []string{"dev", "family"}) {
+		t.Error("member of a mapped IdP group should be included")
+	}
+	if g.Includes("user-2", []string{"dev"}) {
+		t.Error("other users should not be included")
+	}
+}
+
+func TestParseLocalGroupName(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"friends", "friends", false},
+		{"  media-users ", "media-users", false},
+		{"", "", true},
+		{"two words", "", true},
+		{"dash_admin", "", true},
+		{"dash_user", "", true},
+		{"averyveryveryveryveryveryveryveryveryveryveryveryveryverylonggroup", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLocalGroupName(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLocalGroupName(%q) = %q, %v; want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWithLocalGroups(t *testing.T) {
+	id := Identity{UserID: "user-1", Groups: []string{"dash_user", "dev"}}
+	result := id.WithLocalGroups([]LocalGroup{
+		{Name: "friends", Members: []string{"user-1"}},
+		{Name: "everyone", IdpGroups: []string{"dash_user"}},
+		{Name: "ops", IdpGroups: []string{"ops"}, Members: []string{"user-2"}},
+		{Name: "dev", IdpGroups: []string{"dev"}},
+	})
+
+	want := []string{"dash_user", "dev", "friends", "everyone"}
+	if !slices.Equal(result.Groups, want) {
+		t.Errorf("Groups = %v, want %v", result.Groups, want)
+	}
+	if len(id.Groups) != 2 {
+		t.Error("original identity should not be modified")
+	}
+}
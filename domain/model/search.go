@@ -0,0 +1,22 @@
+package model
+
+// SearchHitKind tells what a search hit links to.
+type SearchHitKind string
+
+const (
+	SearchHitBookmark    SearchHitKind = "bookmark"
+	SearchHitApplication SearchHitKind = "application"
+)
+
+// SearchHit is a single result of a dashboard search.
+type SearchHit struct {
+	Kind        SearchHitKind `json:"kind"`
+	ID          uint          `json:"id"`
+	Icon        Icon          `json:"icon"`
+	DisplayName string        `json:"display_name"`
+	Url         string        `json:"url"`
+	Host        string        `json:"host"`
+	// Category is the name of the bookmark's category; empty for applications.
+	Category  string `json:"category,omitempty"`
+	IsShelved bool   `json:"is_shelved,omitempty"`
+}
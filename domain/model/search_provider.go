@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// SearchTermsPlaceholder marks where the query goes in a search URL template.
+const SearchTermsPlaceholder = "{q}"
+
+// BangPrefix introduces a bang in a search query, e.g. "!gh dash".
+const BangPrefix = "!"
+
+var bangPattern = regexp.MustCompile(`^[a-z0-9]{1,16}$`)
+
+// SearchProvider is a web search engine a user can fall through to from the
+// dashboard search. Queries starting or ending with its bang go to it directly.
+type SearchProvider struct {
+	ID          uint   `json:"id"`
+	DisplayName string `json:"display_name"`
+	Bang        string `json:"bang"`
+	URLTemplate string `json:"url_template"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// WebSearch is the target of a query that falls through to a web search.
+type WebSearch struct {
+	Provider SearchProvider `json:"provider"`
+	URL      string         `json:"url"`
+}
+
+// ParseBang normalises a bang: the leading "!" is optional, letters are
+// lower-cased and only 1–16 letters and digits are allowed.
+func ParseBang(raw string) (string, error) {
+	bang := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), BangPrefix))
+	if !bangPattern.MatchString(bang) {
+		return "", errors.New("bang: must be 1-16 letters or digits")
+	}
+	return bang, nil
+}
+
+// ValidateSearchURLTemplate checks that raw is an absolute http(s) URL
+// containing SearchTermsPlaceholder.
+func ValidateSearchURLTemplate(raw string) error {
+	if !strings.Contains(raw, SearchTermsPlaceholder) {
+		return errors.New("search url: must contain " + SearchTermsPlaceholder)
+	}
+	u, err := url.Parse(strings.ReplaceAll(raw, SearchTermsPlaceholder, "q"))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("search url: must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+// URLFor returns the provider's search URL for query.
+func (p SearchProvider) URLFor(query string) string {
+	return strings.ReplaceAll(p.URLTemplate, SearchTermsPlaceholder, url.QueryEscape(query))
+}
@@ -0,0 +1,54 @@
+package model
+
+import "testing"
+
+func TestParseBang(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"gh", "gh", false},
+		{"!GH", "gh", false},
+		{" !yt ", "yt", false},
+		{"", "", true},
+		{"!", "", true},
+		{"g h", "", true},
+		{"!averyveryverylongbang", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBang(tt.raw)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBang(%q) = %q, %v; want %q, error %v", tt.raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSearchURLTemplate(t *testing.T) {
+	valid := []string{
+		"https://duckduckgo.com/?q={q}",
+		"http://searx.lan/search?q={q}&language=en",
+	}
+	for _, raw := range valid {
+		if err := ValidateSearchURLTemplate(raw); err != nil {
+			t.Errorf("ValidateSearchURLTemplate(%q) = %v; want nil", raw, err)
+		}
+	}
+	invalid := []string{
+		"https://duckduckgo.com/",
+		"/search?q={q}",
+		"javascript:alert({q})",
+	}
+	for _, raw := range invalid {
+		if err := ValidateSearchURLTemplate(raw); err == nil {
+			t.Errorf("ValidateSearchURLTemplate(%q) = nil; want error", raw)
+		}
+	}
+}
+
+func TestSearchProvider_URLFor(t *testing.T) {
+	p := SearchProvider{URLTemplate: "https://example.com/search?q={q}"}
+	if got := p.URLFor("a&b c"); got != "https://example.com/search?q=a%26b+c" {
+		t.Errorf("URLFor = %q", got)
+	}
+}
@@ -4,4 +4,6 @@ type Setting struct {
 	ThemeID  uint   `json:"theme_id"`
 	Language string `json:"language"`
 	Timezone string `json:"timezone"`
+	// SearchProviderID is the default web search provider; 0 means none.
+	SearchProviderID uint `json:"search_provider_id"`
 }
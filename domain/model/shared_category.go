@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// SharedCategory is a category of an admin's dashboard published to groups.
+// Like an application, it is visible to everyone when VisibleToGroups is
+// empty. Members see it read-only next to their own categories and can hide
+// it for themselves.
+type SharedCategory struct {
+	Category
+	VisibleToGroups []string  `json:"visible_to_groups"`
+	PublishedBy     string    `json:"-"` // user ID of the admin
+	PublishedAt     time.Time `json:"-"`
+	// IsHidden reports whether the current user has hidden the category.
+	IsHidden bool `json:"-"`
+}
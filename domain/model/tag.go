@@ -0,0 +1,275 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTagLength is the maximum number of characters of a tag.
+	MaxTagLength = 32
+	// MaxBookmarkTags is the maximum number of tags of a bookmark.
+	MaxBookmarkTags = 20
+)
+
+// NormalizeTag lowercases and trims a tag and joins its words with dashes.
+// Tags consist of letters, digits and the characters "-", "_", "." and "/".
+func NormalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.Join(strings.Fields(raw), "-"))
+	if tag == "" {
+		return "", fmt.Errorf("tag: must not be empty")
+	}
+	if len([]rune(tag)) > MaxTagLength {
+		return "", fmt.Errorf("tag: %q is longer than %d characters", tag, MaxTagLength)
+	}
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return "", fmt.Errorf("tag: %q may only contain letters, digits and - _ . /", tag)
+		}
+	}
+	return tag, nil
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./", r)
+}
+
+// NormalizeTags normalizes every tag and returns them sorted and without
+// duplicates. The result is never nil.
+func NormalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	for _, r := range raw {
+		tag, err := NormalizeTag(r)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+	if len(tags) > MaxBookmarkTags {
+		return nil, fmt.Errorf("tag: a bookmark has at most %d tags", MaxBookmarkTags)
+	}
+	return tags, nil
+}
+
+// SplitTags splits user input at commas and whitespace.
+func SplitTags(raw string) []string {
+	return strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// TagQuery selects bookmarks by their tags, e.g. "tag:k8s AND NOT tag:legacy".
+// Terms are combined with AND, OR and NOT, case-insensitive, and grouped with
+// parentheses; AND binds stronger than OR and adjacent terms are ANDed. The
+// zero value matches nothing; use ParseTagQuery to construct one.
+type TagQuery struct {
+	expr tagExpr
+}
+
+// ParseTagQuery parses and validates a tag query.
+func ParseTagQuery(raw string) (TagQuery, error) {
+	p := tagQueryParser{tokens: tokenizeTagQuery(raw)}
+	if len(p.tokens) == 0 {
+		return TagQuery{}, fmt.Errorf("tag query: must not be empty")
+	}
+	expr, err := p.or()
+	if err != nil {
+		return TagQuery{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return TagQuery{}, fmt.Errorf("tag query: unexpected %q", p.tokens[p.pos])
+	}
+	return TagQuery{expr: expr}, nil
+}
+
+// Matches reports whether a bookmark with the given tags is selected.
+func (q TagQuery) Matches(tags []string) bool {
+	if q.expr == nil {
+		return false
+	}
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[t] = true
+	}
+	return q.expr.match(set)
+}
+
+// String returns the query in canonical form, which ParseTagQuery parses to
+// an equal query.
+func (q TagQuery) String() string {
+	if q.expr == nil {
+		return ""
+	}
+	return q.expr.String()
+}
+
+func (q TagQuery) IsZero() bool { return q.expr == nil }
+
+// TagsQuery returns the query matching bookmarks that carry all of the given
+// tags, which must be normalized.
+func TagsQuery(tags []string) TagQuery {
+	var expr tagExpr
+	for _, t := range tags {
+		if expr == nil {
+			expr = tagTerm(t)
+		} else {
+			expr = tagAnd{expr, tagTerm(t)}
+		}
+	}
+	return TagQuery{expr: expr}
+}
+
+type tagExpr interface {
+	match(tags map[string]bool) bool
+	String() string
+}
+
+type tagTerm string
+
+func (t tagTerm) match(tags map[string]bool) bool { return tags[string(t)] }
+func (t tagTerm) String() string                  { return "tag:" + string(t) }
+
+type tagNot struct{ expr tagExpr }
+
+func (n tagNot) match(tags map[string]bool) bool { return !n.expr.match(tags) }
+func (n tagNot) String() string {
+	switch n.expr.(type) {
+	case tagAnd, tagOr:
+		return "NOT (" + n.expr.String() + ")"
+	}
+	return "NOT " + n.expr.String()
+}
+
+type tagAnd struct{ left, right tagExpr }
+
+func (a tagAnd) match(tags map[string]bool) bool { return a.left.match(tags) && a.right.match(tags) }
+func (a tagAnd) String() string {
+	return groupTagExpr(a.left) + " AND " + groupTagExpr(a.right)
+}
+
+type tagOr struct{ left, right tagExpr }
+
+func (o tagOr) match(tags map[string]bool) bool { return o.left.match(tags) || o.right.match(tags) }
+func (o tagOr) String() string                  { return o.left.String() + " OR " + o.right.String() }
+
+// groupTagExpr parenthesizes OR expressions, which bind weaker than AND.
+func groupTagExpr(e tagExpr) string {
+	if _, ok := e.(tagOr); ok {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// tokenizeTagQuery splits the query into parentheses and words.
+func tokenizeTagQuery(raw string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range raw {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type tagQueryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagQueryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *tagQueryParser) or() (tagExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = tagOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagQueryParser) and() (tagExpr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || strings.EqualFold(next, "OR") {
+			return left, nil
+		}
+		if strings.EqualFold(next, "AND") {
+			p.pos++
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = tagAnd{left, right}
+	}
+}
+
+func (p *tagQueryParser) not() (tagExpr, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("tag query: unexpected end")
+	case strings.EqualFold(token, "NOT"):
+		p.pos++
+		expr, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{expr}, nil
+	case token == "(":
+		p.pos++
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("tag query: missing )")
+		}
+		p.pos++
+		return expr, nil
+	}
+	name, ok := strings.CutPrefix(strings.ToLower(token), "tag:")
+	if !ok {
+		return nil, fmt.Errorf("tag query: expected tag:<name>, got %q", token)
+	}
+	tag, err := NormalizeTag(name)
+	if err != nil {
+		return nil, fmt.Errorf("tag query: %w", err)
+	}
+	p.pos++
+	return tagTerm(tag), nil
+}
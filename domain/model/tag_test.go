@@ -0,0 +1,164 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "k8s", want: "k8s"},
+		{raw: "  Home Lab ", want: "home-lab"},
+		{raw: "infra/proxmox", want: "infra/proxmox"},
+		{raw: "Über_1.0", want: "über_1.0"},
+		{raw: "", wantErr: true},
+		{raw: "   ", wantErr: true},
+		{raw: "tag:k8s", wantErr: true},
+		{raw: "a(b)", wantErr: true},
+		{raw: strings.Repeat("x", MaxTagLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeTag(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeTag(%q): err = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := NormalizeTags([]string{"ops", "K8s", "k8s", "Ops"})
+	if err != nil {
+		t.Fatalf("NormalizeTags: %v", err)
+	}
+	if want := []string{"k8s", "ops"}; !slices.Equal(got, want) {
+		t.Errorf("NormalizeTags = %v, want %v", got, want)
+	}
+
+	empty, err := NormalizeTags(nil)
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("NormalizeTags(nil) = %#v, %v; want empty non-nil slice", empty, err)
+	}
+
+	tooMany := make([]string, MaxBookmarkTags+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	if _, err := NormalizeTags(tooMany); err == nil {
+		t.Error("NormalizeTags: expected error for too many tags")
+	}
+}
+
+func TestSplitTags(t *testing.T) {
+	got := SplitTags(" k8s, ops\tlegacy,,dev ")
+	if want := []string{"k8s", "ops", "legacy", "dev"}; !slices.Equal(got, want) {
+		t.Errorf("SplitTags = %v, want %v", got, want)
+	}
+}
+
+func TestBookmarkTag(t *testing.T) {
+	b := Bookmark{}
+	if err := b.Tag([]string{"Ops", "k8s"}); err != nil {
+		t.Fatalf("Tag: %v", err)
+	}
+	if want := []string{"k8s", "ops"}; !slices.Equal(b.Tags, want) {
+		t.Errorf("Tags = %v, want %v", b.Tags, want)
+	}
+	if err := b.Tag([]string{"no:colon"}); err == nil {
+		t.Error("Tag: expected error for invalid tag")
+	}
+}
+
+func TestParseTagQuery_Matches(t *testing.T) {
+	tests := []struct {
+		query string
+		tags  []string
+		want  bool
+	}{
+		{query: "tag:k8s", tags: []string{"k8s"}, want: true},
+		{query: "tag:k8s", tags: []string{"ops"}, want: false},
+		{query: "tag:k8s AND NOT tag:legacy", tags: []string{"k8s"}, want: true},
+		{query: "tag:k8s AND NOT tag:legacy", tags: []string{"k8s", "legacy"}, want: false},
+		{query: "tag:k8s tag:ops", tags: []string{"k8s"}, want: false},
+		{query: "tag:k8s tag:ops", tags: []string{"ops", "k8s"}, want: true},
+		{query: "tag:a OR tag:b AND tag:c", tags: []string{"a"}, want: true},
+		{query: "(tag:a OR tag:b) AND tag:c", tags: []string{"a"}, want: false},
+		{query: "not (tag:a or tag:b)", tags: nil, want: true},
+		{query: "TAG:K8S", tags: []string{"k8s"}, want: true},
+	}
+	for _, tt := range tests {
+		q, err := ParseTagQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseTagQuery(%q): %v", tt.query, err)
+			continue
+		}
+		if got := q.Matches(tt.tags); got != tt.want {
+			t.Errorf("ParseTagQuery(%q).Matches(%v) = %v, want %v", tt.query, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestParseTagQuery_Errors(t *testing.T) {
+	for _, query := range []string{"", "   ", "k8s", "tag:", "tag:k8s AND", "(tag:k8s", "tag:k8s)", "NOT", "tag:a OR OR tag:b"} {
+		if _, err := ParseTagQuery(query); err == nil {
+			t.Errorf("ParseTagQuery(%q): expected error", query)
+		}
+	}
+}
+
+func TestTagQueryString(t *testing.T) {
+	tests := []struct{ query, want string }{
+		{query: "tag:K8s and not tag:legacy", want: "tag:k8s AND NOT tag:legacy"},
+		{query: "tag:a tag:b", want: "tag:a AND tag:b"},
+		{query: "(tag:a or tag:b) tag:c", want: "(tag:a OR tag:b) AND tag:c"},
+		{query: "NOT (tag:a AND tag:b)", want: "NOT (tag:a AND tag:b)"},
+		{query: "tag:a OR tag:b AND tag:c", want: "tag:a OR tag:b AND tag:c"},
+	}
+	for _, tt := range tests {
+		q, err := ParseTagQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseTagQuery(%q): %v", tt.query, err)
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("ParseTagQuery(%q).String() = %q, want %q", tt.query, got, tt.want)
+		}
+		again, err := ParseTagQuery(q.String())
+		if err != nil || again.String() != q.String() {
+			t.Errorf("canonical form %q does not round-trip: %q, %v", q.String(), again.String(), err)
+		}
+	}
+}
+
+func TestTagsQuery(t *testing.T) {
+	q := TagsQuery([]string{"k8s", "ops"})
+	if !q.Matches([]string{"k8s", "ops", "dev"}) || q.Matches([]string{"k8s"}) {
+		t.Errorf("TagsQuery(k8s, ops) = %q matches wrong tags", q)
+	}
+	if zero := TagsQuery(nil); !zero.IsZero() || zero.Matches([]string{"k8s"}) {
+		t.Error("TagsQuery(nil) should be the zero query matching nothing")
+	}
+}
+
+func TestCategoryQuery(t *testing.T) {
+	c := Category{DisplayName: "Kubernetes"}
+	if err := c.Query("tag:k8s and not tag:legacy"); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if !c.IsSmart() || c.TagQuery != "tag:k8s AND NOT tag:legacy" {
+		t.Errorf("TagQuery = %q, IsSmart = %v", c.TagQuery, c.IsSmart())
+	}
+	if err := c.Query("k8s"); err == nil {
+		t.Error("Query: expected error for invalid query")
+	}
+	if err := c.Query("  "); err != nil || c.IsSmart() {
+		t.Errorf("Query(blank): err = %v, IsSmart = %v; want regular category", err, c.IsSmart())
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserSummary describes an account in the admin user overview. Dash keeps no
+// profile of its own, so the name, email and admin flag come from the user's
+// most recent session and are empty for users without one.
+type UserSummary struct {
+	UserID        string
+	DisplayName   string
+	Username      string
+	Email         string
+	IsAdmin       bool
+	LastSeenAt    time.Time // zero = no session
+	IdpLinks      []IdpLink
+	CategoryCount int
+	BookmarkCount int
+}
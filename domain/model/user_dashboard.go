@@ -1,7 +1,7 @@
 package model
 
-// UserDashboard is the aggregate root for a user's personal dashboard.
-// It enforces ownership semantics for categories and bookmarks.
+// UserDashboard is the aggregate root for one of a user's dashboards.
+// It enforces ownership and sharing semantics for categories and bookmarks.
 // On the write path, load it from a DashboardRecord and use its methods
 // to express ownership checks as domain concepts instead of raw comparisons.
 type UserDashboard struct {
@@ -25,3 +25,18 @@ func (d UserDashboard) OwnedBy(userID string) bool { return d.userID == userID }
 
 // OwnsCategory reports whether a category with the given dashboardID belongs to this dashboard.
 func (d UserDashboard) OwnsCategory(dashboardID uint) bool { return dashboardID == d.id }
+
+// CanViewCategory reports whether the given user may see a category of this
+// dashboard. Owners always may; everyone else needs a share, whose role is
+// passed in (empty without one).
+func (d UserDashboard) CanViewCategory(userID string, role CategoryRole) bool {
+	return d.OwnedBy(userID) || role == CategoryRoleViewer || role == CategoryRoleEditor
+}
+
+// CanEditBookmarks reports whether the given user may add, edit, reorder and
+// remove bookmarks in a category of this dashboard. Owners always may;
+// everyone else needs a share with the editor role. Only owners may change
+// the category itself.
+func (d UserDashboard) CanEditBookmarks(userID string, role CategoryRole) bool {
+	return d.OwnedBy(userID) || role == CategoryRoleEditor
+}
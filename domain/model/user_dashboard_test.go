@@ -40,3 +40,37 @@ func TestUserDashboard_OwnsCategory(t *testing.T) {
 		t.Error("OwnsCategory should return false for zero ID")
 	}
 }
+
+func TestUserDashboard_CanViewCategory(t *testing.T) {
+	ud := NewUserDashboard(5, "user-abc")
+
+	if !ud.CanViewCategory("user-abc", "") {
+		t.Error("CanViewCategory should return true for the owner")
+	}
+	if !ud.CanViewCategory("other-user", CategoryRoleViewer) {
+		t.Error("CanViewCategory should return true for a viewer")
+	}
+	if !ud.CanViewCategory("other-user", CategoryRoleEditor) {
+		t.Error("CanViewCategory should return true for an editor")
+	}
+	if ud.CanViewCategory("other-user", "") {
+		t.Error("CanViewCategory should return false without a share")
+	}
+}
+
+func TestUserDashboard_CanEditBookmarks(t *testing.T) {
+	ud := NewUserDashboard(5, "user-abc")
+
+	if !ud.CanEditBookmarks("user-abc", "") {
+		t.Error("CanEditBookmarks should return true for the owner")
+	}
+	if !ud.CanEditBookmarks("other-user", CategoryRoleEditor) {
+		t.Error("CanEditBookmarks should return true for an editor")
+	}
+	if ud.CanEditBookmarks("other-user", CategoryRoleViewer) {
+		t.Error("CanEditBookmarks should return false for a viewer")
+	}
+	if ud.CanEditBookmarks("other-user", "") {
+		t.Error("CanEditBookmarks should return false without a share")
+	}
+}
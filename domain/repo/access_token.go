@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// AccessTokenRecord is the data transfer type exchanged with the AccessTokenRepository.
+// Like SessionRecord, it carries a snapshot of the owner's identity, so a token
+// authenticates without a session. The snapshot holds the IdP groups only;
+// groups managed in Dash are resolved on every request.
+type AccessTokenRecord struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string // SHA-256 of the secret; the secret itself is never stored
+	Scope      string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time // zero = never expires
+	LastUsedAt time.Time // zero = never used
+	LastIP     string
+	// Identity snapshot — see SessionRecord for field semantics.
+	Username    string
+	Email       string
+	FirstName   string
+	LastName    string
+	DisplayName string
+	Picture     string
+	ProfileUrl  string
+	Groups      []string
+	IsAdmin     bool
+}
+
+// AccessTokenRepository manages personal access tokens.
+type AccessTokenRepository interface {
+	// Create stores a new token record.
+	Create(ctx context.Context, record *AccessTokenRecord) error
+	// ListByUserID returns all tokens of the given user (including expired ones), newest first.
+	ListByUserID(ctx context.Context, userID string) ([]*AccessTokenRecord, error)
+	// Touch updates LastUsedAt and LastIP for the token with the given hash and
+	// returns the updated record — nil if no such token exists or it has expired.
+	Touch(ctx context.Context, tokenHash string, lastIP string) (*AccessTokenRecord, error)
+	// UpdateIdentity copies the identity snapshot of record to every token of
+	// record.UserID.
+	UpdateIdentity(ctx context.Context, record *AccessTokenRecord) error
+	// DeleteByID removes a token by its record ID, scoped to userID for safety.
+	DeleteByID(ctx context.Context, id string, userID string) error
+	// DeleteByUserID removes all tokens of the given user.
+	DeleteByUserID(ctx context.Context, userID string) error
+}
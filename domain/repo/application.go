@@ -1,6 +1,9 @@
 package repo
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ApplicationRecord is the data transfer type exchanged with the ApplicationRepository.
 type ApplicationRecord struct {
@@ -10,11 +13,36 @@ type ApplicationRecord struct {
 	DisplayName     string
 	Url             string
 	VisibleToGroups []string
+	Position        int // managed by the repository; see ApplicationRepository
+
+	HealthCheck          bool
+	HealthURL            string
+	HealthExpectedStatus string
+	// Health is the latest probe result. It is only written by RecordHealth;
+	// Upsert ignores it.
+	Health ApplicationHealthRecord
+}
+
+// ApplicationHealthRecord is the latest probe result of an application. The
+// zero value means it has not been probed yet.
+type ApplicationHealthRecord struct {
+	State     string
+	LatencyMs int64
+	CheckedAt time.Time
+	Error     string
 }
 
+// ApplicationRepository persists applications. Positions are owned by the
+// repository: Upsert appends new applications to the end and keeps the position
+// of existing ones; Reorder is the only way to rearrange them.
 type ApplicationRepository interface {
 	Upsert(ctx context.Context, record *ApplicationRecord) error
 	Get(ctx context.Context, id uint) (*ApplicationRecord, error)
 	List(ctx context.Context) ([]ApplicationRecord, error)
 	Delete(ctx context.Context, id uint) error
+	// Reorder assigns positions to the given applications in slice order.
+	Reorder(ctx context.Context, orderedIDs []uint) error
+	// RecordHealth replaces the latest probe result of an application. It is a
+	// no-op for applications that no longer exist.
+	RecordHealth(ctx context.Context, id uint, health ApplicationHealthRecord) error
 }
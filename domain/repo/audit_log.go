@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+)
+
+// AuditLogFilter narrows down AuditLogRepository.List. Zero fields match
+// everything.
+type AuditLogFilter struct {
+	ActorID string
+	Action  domainmodel.AuditAction
+	Limit   int // 0 = no limit
+}
+
+// AuditLogRepository stores the audit log. Entries are not tied to a users row
+// so that they outlive the accounts they describe.
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domainmodel.AuditEntry) error
+	// List returns the entries matching filter, newest first.
+	List(ctx context.Context, filter AuditLogFilter) ([]domainmodel.AuditEntry, error)
+	// DeleteBefore removes all entries created before t.
+	DeleteBefore(ctx context.Context, t time.Time) error
+}
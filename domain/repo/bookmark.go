@@ -9,11 +9,19 @@ type BookmarkRecord struct {
 	Icon        string
 	DisplayName string
 	Url         string
+	Tags        []string
+	Position    int // managed by the repository; see BookmarkRepository
 }
 
+// BookmarkRepository persists bookmarks. Positions are owned by the repository:
+// Upsert appends new bookmarks to the end of their category, keeps the position
+// of existing ones (or appends them when they move to another category), and
+// Reorder is the only way to rearrange them.
 type BookmarkRepository interface {
 	Upsert(ctx context.Context, record *BookmarkRecord) error
 	Get(ctx context.Context, id uint) (*BookmarkRecord, error)
 	ListByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]BookmarkRecord, error)
 	Delete(ctx context.Context, id uint) error
+	// Reorder assigns positions to the given bookmarks of categoryID in slice order.
+	Reorder(ctx context.Context, categoryID uint, orderedIDs []uint) error
 }
@@ -6,13 +6,27 @@ import "context"
 type CategoryRecord struct {
 	ID          uint
 	DashboardID uint
+	ParentID    uint // 0 for top-level categories
 	DisplayName string
 	IsShelved   bool
+	TagQuery    string // non-empty for smart categories
+	Position    int    // managed by the repository; see CategoryRepository
 }
 
+// CategoryRepository persists categories. Positions are owned by the repository:
+// Upsert appends new categories to the end of their dashboard and keeps the
+// position of existing ones unless they move to another parent or dashboard;
+// Reorder is the only way to rearrange them. Positions are scoped to the
+// dashboard, so siblings keep their relative order wherever they are nested.
+//
+// Sub-categories follow their parent: moving a category to another dashboard
+// moves its subtree along, and Delete removes the whole subtree with all its
+// bookmarks.
 type CategoryRepository interface {
 	Upsert(ctx context.Context, record *CategoryRecord) error
 	Get(ctx context.Context, id uint) (*CategoryRecord, error)
 	ListByDashboardID(ctx context.Context, dashboardID uint) ([]CategoryRecord, error)
 	Delete(ctx context.Context, id uint) error
+	// Reorder assigns positions to the given categories of dashboardID in slice order.
+	Reorder(ctx context.Context, dashboardID uint, orderedIDs []uint) error
 }
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// CategoryShareRecord is the data transfer type exchanged with the
+// CategoryShareRepository. It grants a user a role on another user's category.
+type CategoryShareRecord struct {
+	CategoryID  uint
+	UserID      string // the user the category is shared with
+	Role        string
+	CreatedAt   time.Time
+	DisplayName string // of the category; filled by ListByUser only
+	OwnerID     string // user ID of the category owner; filled by ListByUser only
+}
+
+// CategoryShareRepository stores which categories are shared with which
+// users. Shares are removed together with their category or user.
+type CategoryShareRepository interface {
+	// ListByCategory returns the shares of a category, oldest first.
+	ListByCategory(ctx context.Context, categoryID uint) ([]CategoryShareRecord, error)
+	// ListByUser returns the categories shared with the user, ordered by the
+	// category's display name.
+	ListByUser(ctx context.Context, userID string) ([]CategoryShareRecord, error)
+	// Get returns the share of a category with a user, or a NotFoundError if
+	// the category is not shared with them.
+	Get(ctx context.Context, categoryID uint, userID string) (*CategoryShareRecord, error)
+	// Upsert shares the category with the user, or changes the role of an
+	// existing share.
+	Upsert(ctx context.Context, record *CategoryShareRecord) error
+	// Delete removes the share. Returns a NotFoundError if it does not exist.
+	Delete(ctx context.Context, categoryID uint, userID string) error
+}
@@ -0,0 +1,26 @@
+package repo
+
+import "context"
+
+// CustomIconRecord is the data transfer type exchanged with the
+// CustomIconRepository.
+type CustomIconRecord struct {
+	ID          uint
+	UserID      string // owner; "" = shared icon managed by admins
+	DisplayName string
+	ContentType string
+	Data        []byte
+}
+
+// CustomIconRepository stores the images of the icon library.
+type CustomIconRepository interface {
+	Create(ctx context.Context, record *CustomIconRecord) error
+	// GetByID returns the icon with the given id, or a NotFoundError if there is none.
+	GetByID(ctx context.Context, id uint) (*CustomIconRecord, error)
+	// ListVisible returns the icons of userID followed by the shared icons,
+	// both ordered by name.
+	ListVisible(ctx context.Context, userID string) ([]CustomIconRecord, error)
+	// Delete removes the icon with the given id owned by userID; userID ""
+	// deletes a shared icon.
+	Delete(ctx context.Context, userID string, id uint) error
+}
@@ -4,13 +4,29 @@ import "context"
 
 // DashboardRecord is the data transfer type exchanged with the DashboardRepository.
 type DashboardRecord struct {
-	ID     uint
-	UserID string
+	ID        uint
+	UserID    string
+	Name      string
+	Position  int // managed by the repository; see DashboardRepository
+	IsDefault bool
 }
 
+// DashboardRepository persists dashboards. A user has any number of them, of
+// which exactly one is the default. Positions are owned by the repository:
+// Upsert appends new dashboards to the end of the user's list and keeps the
+// position of existing ones; Reorder is the only way to rearrange them.
 type DashboardRepository interface {
 	Upsert(ctx context.Context, record *DashboardRecord) error
 	Get(ctx context.Context, id uint) (*DashboardRecord, error)
-	GetByUserID(ctx context.Context, userID string) (*DashboardRecord, error)
+	// GetDefault returns the user's default dashboard, or NotFound if the
+	// user has none yet.
+	GetDefault(ctx context.Context, userID string) (*DashboardRecord, error)
+	ListByUserID(ctx context.Context, userID string) ([]DashboardRecord, error)
+	// SetDefault makes the dashboard with id the default of its user.
+	SetDefault(ctx context.Context, userID string, id uint) error
+	// Delete removes a dashboard with its categories. When it was the default,
+	// the user's first remaining dashboard becomes the default.
 	Delete(ctx context.Context, id uint) error
+	// Reorder assigns positions to the given dashboards of userID in slice order.
+	Reorder(ctx context.Context, userID string, orderedIDs []uint) error
 }
@@ -0,0 +1,28 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// FaviconRecord is the data transfer type exchanged with the FaviconRepository.
+// Favicons are cached per site and shared by all users.
+type FaviconRecord struct {
+	Site        string // origin, e.g. "https://github.com"
+	ContentType string
+	Data        []byte // empty = the site has no usable icon
+	FetchedAt   time.Time
+}
+
+// FaviconRepository caches the favicons of bookmarked sites.
+type FaviconRepository interface {
+	// Get returns the cached favicon of site, or a NotFoundError if there is none.
+	Get(ctx context.Context, site string) (*FaviconRecord, error)
+	// Upsert stores the favicon of record.Site, replacing any cached one.
+	Upsert(ctx context.Context, record *FaviconRecord) error
+	// DeleteFetchedBefore removes favicons fetched before t.
+	DeleteFetchedBefore(ctx context.Context, t time.Time) error
+	// DeleteAllButNewest keeps the keep most recently fetched favicons and
+	// removes the others.
+	DeleteAllButNewest(ctx context.Context, keep int) error
+}
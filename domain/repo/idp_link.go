@@ -1,15 +1,38 @@
 package repo
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// IdpLinkRecord is a single (issuer, sub) identity linked to an internal user.
+type IdpLinkRecord struct {
+	UserID    string
+	Issuer    string
+	Sub       string
+	IsPrimary bool // true for the identity the account was created with
+	LinkedAt  time.Time
+}
 
 // IdpLinkRepository maps (issuer, sub) pairs to internal user IDs.
-// This is the foundation for multi-IdP support: a user can link multiple
-// IdP identities to a single internal account.
+// A user can link multiple IdP identities to a single internal account; every
+// linked identity resolves to the same UserID.
 type IdpLinkRepository interface {
 	// ResolveOrCreate looks up the internal UserID for (issuer, sub).
 	// If no link exists yet, creates one with a UUID v5 derived from (issuer, sub)
 	// and returns isNew=true so the caller can migrate any pre-existing data.
 	ResolveOrCreate(ctx context.Context, issuer, sub string) (userID string, isNew bool, err error)
+	// Link attaches (issuer, sub) to userID unless the identity is already
+	// linked. It returns the UserID the identity belongs to afterwards, which
+	// differs from userID when another account already owns the identity.
+	Link(ctx context.Context, userID, issuer, sub string) (ownerID string, err error)
+	// ListByUserID returns all identities linked to the given user, primary first.
+	ListByUserID(ctx context.Context, userID string) ([]IdpLinkRecord, error)
+	// List returns the identities of all users, ordered by user, primary first.
+	List(ctx context.Context) ([]IdpLinkRecord, error)
+	// Unlink removes a single identity from the given user.
+	// Returns NotFoundError when the user has no such link.
+	Unlink(ctx context.Context, userID, issuer, sub string) error
 	// DeleteByUserID removes all IdP links for the given user.
 	// Must be called when the user's account data is deleted.
 	DeleteByUserID(ctx context.Context, userID string) error
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// InvitationRecord is the data transfer type exchanged with the
+// InvitationRepository.
+type InvitationRecord struct {
+	ID            string
+	TokenHash     string // SHA-256 of the secret; the secret itself is never stored
+	Groups        []string
+	MaxUses       int
+	Uses          int
+	ExpiresAt     time.Time
+	GrantDuration time.Duration
+	CreatedBy     string
+	CreatedAt     time.Time
+}
+
+// InvitationRedemptionRecord is the data transfer type for a redeemed
+// invitation. Groups is copied from the invitation at redemption time.
+type InvitationRedemptionRecord struct {
+	ID           uint
+	InvitationID string
+	UserID       string
+	Groups       []string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// InvitationRepository stores guest invitations and their redemptions.
+// Redemptions are removed together with their invitation or their user.
+type InvitationRepository interface {
+	// Create stores a new invitation.
+	Create(ctx context.Context, record *InvitationRecord) error
+	// List returns all invitations, newest first.
+	List(ctx context.Context) ([]InvitationRecord, error)
+	// GetByTokenHash returns the invitation, or a NotFoundError if there is none.
+	GetByTokenHash(ctx context.Context, tokenHash string) (*InvitationRecord, error)
+	// Delete removes an invitation with its redemptions. Returns a
+	// NotFoundError if there is no such invitation.
+	Delete(ctx context.Context, id string) error
+	// Redeem counts a use of the invitation and stores the redemption in one
+	// step. It returns false without storing anything when the invitation has
+	// expired by now or reached its usage limit.
+	Redeem(ctx context.Context, redemption *InvitationRedemptionRecord, now time.Time) (bool, error)
+	// ListRedemptions returns the redemptions of all invitations, newest first.
+	ListRedemptions(ctx context.Context) ([]InvitationRedemptionRecord, error)
+	// ListActiveRedemptions returns the redemptions of the user that have not
+	// expired by now.
+	ListActiveRedemptions(ctx context.Context, userID string, now time.Time) ([]InvitationRedemptionRecord, error)
+	// HasRedeemed reports whether the user has ever redeemed the invitation,
+	// including redemptions that have expired or were revoked since.
+	HasRedeemed(ctx context.Context, invitationID, userID string) (bool, error)
+	// RevokeRedemption ends a redemption by moving its ExpiresAt to now; the
+	// record is kept. Returns a NotFoundError if there is no such redemption.
+	RevokeRedemption(ctx context.Context, id uint, now time.Time) error
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// LocalAccountRecord is a user account that signs in with a password instead
+// of an external identity provider.
+type LocalAccountRecord struct {
+	Username     string
+	PasswordHash string // bcrypt
+	DisplayName  string
+	Email        string
+	Groups       []string
+	Managed      bool // true for accounts defined in the users file; read-only in the UI
+	CreatedAt    time.Time
+}
+
+// LocalAccountRepository stores the local accounts. Usernames are unique and
+// double as the sub of the session and IdP link records.
+type LocalAccountRepository interface {
+	// GetByUsername returns the account, or a NotFoundError if there is none.
+	GetByUsername(ctx context.Context, username string) (*LocalAccountRecord, error)
+	// List returns all accounts ordered by username.
+	List(ctx context.Context) ([]LocalAccountRecord, error)
+	// Create stores a new account.
+	Create(ctx context.Context, record *LocalAccountRecord) error
+	// SetPassword replaces the password hash of an account.
+	// Returns a NotFoundError if there is no such account.
+	SetPassword(ctx context.Context, username, passwordHash string) error
+	// Delete removes an account. Returns a NotFoundError if there is no such account.
+	Delete(ctx context.Context, username string) error
+	// ReplaceManaged makes the managed accounts match records: accounts are
+	// created or updated, and managed accounts missing from records are removed.
+	// Unmanaged accounts with the same username are taken over.
+	ReplaceManaged(ctx context.Context, records []LocalAccountRecord) error
+}
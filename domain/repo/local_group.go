@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// LocalGroupRecord is the data transfer type exchanged with the
+// LocalGroupRepository.
+type LocalGroupRecord struct {
+	ID        uint
+	Name      string
+	IdpGroups []string
+	Members   []string // user IDs
+	CreatedAt time.Time
+}
+
+// LocalGroupRepository stores the groups managed in Dash. Group names are
+// unique; memberships are removed together with the group or the user.
+type LocalGroupRepository interface {
+	// List returns all groups with their members, ordered by name.
+	List(ctx context.Context) ([]LocalGroupRecord, error)
+	// GetByName returns the group, or a NotFoundError if there is none.
+	GetByName(ctx context.Context, name string) (*LocalGroupRecord, error)
+	// Create stores a new group; Members is ignored.
+	Create(ctx context.Context, record *LocalGroupRecord) error
+	// Update replaces the name and IdP groups of a group; Members is ignored.
+	// Returns a NotFoundError if there is no such group.
+	Update(ctx context.Context, record *LocalGroupRecord) error
+	// Delete removes a group. Returns a NotFoundError if there is no such group.
+	Delete(ctx context.Context, id uint) error
+	// AddMember assigns the user to the group; assigning a member again is a
+	// no-op. Returns a NotFoundError if there is no such group.
+	AddMember(ctx context.Context, groupID uint, userID string) error
+	// RemoveMember removes the user from the group.
+	RemoveMember(ctx context.Context, groupID uint, userID string) error
+}
@@ -22,7 +22,9 @@ type SessionRecord struct {
 	CreatedAt      time.Time
 	// Identity fields — stored at login/refresh time; used by LoadIdentity to
 	// reconstruct the domain Identity without touching the OIDC token.
+	Issuer      string // OIDC issuer the session was signed in with; empty = primary provider
 	Sub         string
+	Sid         string // IdP session ID (sid claim); empty = the IdP sends none
 	Username    string
 	Email       string
 	FirstName   string
@@ -33,6 +35,10 @@ type SessionRecord struct {
 	RawIDToken  string // raw OIDC id_token for logout (id_token_hint)
 	Groups      []string
 	IsAdmin     bool
+	// Silent refresh — RefreshToken is encrypted like RawIDToken. Stale is set
+	// when the IdP rejected it; the user then has to re-authenticate interactively.
+	RefreshToken string // empty = no silent refresh
+	Stale        bool
 }
 
 // SessionRepository manages all user sessions.
@@ -51,14 +57,30 @@ type SessionRepository interface {
 	// ListByUserID returns all sessions still relevant for the given user
 	// (token valid OR pin still active), newest first.
 	ListByUserID(ctx context.Context, userID string) ([]*SessionRecord, error)
+	// ListLatest returns the most recently used session of every user that has one.
+	ListLatest(ctx context.Context) ([]*SessionRecord, error)
+	// FindLatestByUserID returns the most recently used active session of the
+	// given user (token valid OR pin still active) — nil if the user has none.
+	FindLatestByUserID(ctx context.Context, userID string) (*SessionRecord, error)
 	// DeleteByID removes a specific session by its DB record ID, scoped to userID for safety.
 	DeleteByID(ctx context.Context, recordID string, userID string) error
 	// DeleteBySessionID removes the session with the given cookie SessionID.
 	DeleteBySessionID(ctx context.Context, sessionID string) error
 	// DeleteByUserID removes all sessions for the given user.
 	DeleteByUserID(ctx context.Context, userID string) error
-	// RefreshBySessionID updates token timing, groups, and IsAdmin for an existing session.
+	// DeleteBySub removes all sessions signed in with the (issuer, sub) identity.
+	DeleteBySub(ctx context.Context, issuer, sub string) error
+	// DeleteBySid removes all sessions belonging to the IdP session sid of issuer.
+	DeleteBySid(ctx context.Context, issuer, sid string) error
+	// RefreshBySessionID updates token timing, groups, and IsAdmin for an existing
+	// session and clears Stale.
 	RefreshBySessionID(ctx context.Context, record *SessionRecord) error
+	// ListRefreshable returns the sessions with a refresh token that are not stale
+	// and whose token expires before the given time — either still valid, or
+	// pinned and used since the token expired.
+	ListRefreshable(ctx context.Context, before time.Time) ([]*SessionRecord, error)
+	// MarkStale flags a session as stale and drops its refresh token.
+	MarkStale(ctx context.Context, sessionID string) error
 	// DeleteExpired removes all sessions whose token has expired and that are no
 	// longer pinned (or whose pin has also expired).
 	DeleteExpired(ctx context.Context) error
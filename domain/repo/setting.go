@@ -9,6 +9,8 @@ type SettingRecord struct {
 	ThemeID  uint
 	Language string
 	Timezone string
+	// SearchProviderID is the default web search provider; 0 means none.
+	SearchProviderID uint
 }
 
 type SettingRepository interface {
@@ -16,3 +18,22 @@ type SettingRepository interface {
 	GetByUserID(ctx context.Context, userID string) (*SettingRecord, error)
 	DeleteByUserID(ctx context.Context, userID string) error
 }
+
+// SearchProviderRecord is the data transfer type exchanged with the
+// SearchProviderRepository.
+type SearchProviderRecord struct {
+	ID          uint
+	UserID      string
+	DisplayName string
+	// Bang is stored without the leading "!" and is unique per user.
+	Bang        string
+	URLTemplate string
+}
+
+type SearchProviderRepository interface {
+	Create(ctx context.Context, record *SearchProviderRecord) error
+	Delete(ctx context.Context, userID string, id uint) error
+	ListByUser(ctx context.Context, userID string) ([]SearchProviderRecord, error)
+	// GetByID returns a NotFoundError when the search provider is not found.
+	GetByID(ctx context.Context, userID string, id uint) (*SearchProviderRecord, error)
+}
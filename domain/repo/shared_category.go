@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// SharedCategoryRecord is the data transfer type exchanged with the
+// SharedCategoryRepository. It publishes a category to groups.
+type SharedCategoryRecord struct {
+	CategoryID      uint
+	DisplayName     string // of the category; ignored by Publish
+	VisibleToGroups []string
+	PublishedBy     string // user ID of the admin
+	PublishedAt     time.Time
+}
+
+// SharedCategoryRepository stores which categories are published to which
+// groups and which users have hidden them. Publications are removed together
+// with their category, and hiding records together with the publication or
+// the user.
+type SharedCategoryRepository interface {
+	// List returns all published categories, ordered by display name.
+	List(ctx context.Context) ([]SharedCategoryRecord, error)
+	// Get returns the publication of a category, or a NotFoundError if it is
+	// not published.
+	Get(ctx context.Context, categoryID uint) (*SharedCategoryRecord, error)
+	// Publish shares the category with the groups. Publishing a category
+	// again replaces its groups and keeps publisher and date.
+	Publish(ctx context.Context, record *SharedCategoryRecord) error
+	// Unpublish stops sharing the category. Returns a NotFoundError if it is
+	// not published.
+	Unpublish(ctx context.Context, categoryID uint) error
+	// ListHidden returns the IDs of the published categories the user hid.
+	ListHidden(ctx context.Context, userID string) ([]uint, error)
+	// Hide hides a published category for the user; hiding it again is a
+	// no-op. Returns a NotFoundError if the category is not published.
+	Hide(ctx context.Context, userID string, categoryID uint) error
+	// Show undoes Hide.
+	Show(ctx context.Context, userID string, categoryID uint) error
+}
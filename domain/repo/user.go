@@ -2,11 +2,23 @@ package repo
 
 import "context"
 
+// UserRecord is a user with the size of their dashboard.
+type UserRecord struct {
+	ID            string
+	CategoryCount int
+	BookmarkCount int
+}
+
 // UserRepository manages the internal user records that act as the relational
 // anchor for all user-owned data. User records are created implicitly via
 // IdpLinkRepository.ResolveOrCreate and deleted explicitly to cascade all
 // associated data (dashboards, settings, themes, sessions, idp_links).
 type UserRepository interface {
+	// List returns all users with the number of categories and bookmarks on
+	// their dashboard.
+	List(ctx context.Context) ([]UserRecord, error)
+	// Exists reports whether a user with the given ID exists.
+	Exists(ctx context.Context, id string) (bool, error)
 	// DeleteByID removes the user record. All associated data is deleted via
 	// ON DELETE CASCADE constraints on the dependent tables.
 	DeleteByID(ctx context.Context, id string) error
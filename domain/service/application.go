@@ -10,11 +10,17 @@ import (
 // Business rule: an application with no groups is visible to everyone.
 func FilterForUser(apps []model.AppLink, userGroups []string) []model.AppLink {
 	return lo.Filter(apps, func(app model.AppLink, _ int) bool {
-		if len(app.VisibleToGroups) == 0 {
-			return true
-		}
-		return lo.ContainsBy(userGroups, func(group string) bool {
-			return lo.Contains(app.VisibleToGroups, group)
-		})
+		return visibleToGroups(app.VisibleToGroups, userGroups)
+	})
+}
+
+// visibleToGroups reports whether something restricted to visibleTo is
+// visible to a member of userGroups. No restriction means everyone.
+func visibleToGroups(visibleTo, userGroups []string) bool {
+	if len(visibleTo) == 0 {
+		return true
+	}
+	return lo.ContainsBy(userGroups, func(group string) bool {
+		return lo.Contains(visibleTo, group)
 	})
 }
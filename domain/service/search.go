@@ -0,0 +1,90 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+)
+
+// Match ranks, best first, used by MatchSearch.
+const (
+	MatchNamePrefix = iota
+	MatchWordPrefix
+	MatchHostPrefix
+	MatchNameContains
+	MatchHostContains
+)
+
+// MatchSearch reports whether an entry with the given display name and URL
+// host matches query, and how well. Matching is case-insensitive. Prefix
+// matches rank before substring matches, and the display name before the
+// host; a leading "www." of the host is ignored.
+func MatchSearch(query, displayName, host string) (rank int, ok bool) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return 0, false
+	}
+	name := strings.ToLower(displayName)
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	switch {
+	case strings.HasPrefix(name, q):
+		return MatchNamePrefix, true
+	case hasWordPrefix(name, q):
+		return MatchWordPrefix, true
+	case strings.HasPrefix(host, q):
+		return MatchHostPrefix, true
+	case strings.Contains(name, q):
+		return MatchNameContains, true
+	case strings.Contains(host, q):
+		return MatchHostContains, true
+	}
+	return 0, false
+}
+
+func hasWordPrefix(s, prefix string) bool {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveWebSearch picks the provider a query falls through to. A bang at the
+// start or end of the query ("!gh dash" or "dash !gh") selects the provider
+// with that bang and is stripped from the returned terms; otherwise the
+// default provider gets the whole query. ok is false when no provider applies.
+func ResolveWebSearch(providers []model.SearchProvider, query string) (provider model.SearchProvider, terms string, ok bool) {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return model.SearchProvider{}, "", false
+	}
+
+	for _, i := range []int{0, len(fields) - 1} {
+		if !strings.HasPrefix(fields[i], model.BangPrefix) {
+			continue
+		}
+		bang, err := model.ParseBang(fields[i])
+		if err != nil {
+			continue
+		}
+		for _, p := range providers {
+			if p.Bang == bang {
+				rest := append(append([]string{}, fields[:i]...), fields[i+1:]...)
+				return p, strings.Join(rest, " "), true
+			}
+		}
+	}
+
+	for _, p := range providers {
+		if p.IsDefault {
+			return p, strings.Join(fields, " "), true
+		}
+	}
+	return model.SearchProvider{}, "", false
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+)
+
+func TestMatchSearch(t *testing.T) {
+	tests := []struct {
+		query, name, host string
+		wantRank          int
+		wantOK            bool
+	}{
+		{"graf", "Grafana", "grafana.lan", MatchNamePrefix, true},
+		{"GRAF", "grafana", "", MatchNamePrefix, true},
+		{"board", "Home Board", "", MatchWordPrefix, true},
+		{"git", "Source code", "www.github.com", MatchHostPrefix, true},
+		{"afa", "Grafana", "", MatchNameContains, true},
+		{"hub", "Source code", "github.com", MatchHostContains, true},
+		{"xyz", "Grafana", "grafana.lan", 0, false},
+		{"  ", "Grafana", "grafana.lan", 0, false},
+	}
+	for _, tt := range tests {
+		rank, ok := MatchSearch(tt.query, tt.name, tt.host)
+		if ok != tt.wantOK || rank != tt.wantRank {
+			t.Errorf("MatchSearch(%q, %q, %q) = %d, %v; want %d, %v", tt.query, tt.name, tt.host, rank, ok, tt.wantRank, tt.wantOK)
+		}
+	}
+}
+
+func TestResolveWebSearch(t *testing.T) {
+	providers := []model.SearchProvider{
+		{ID: 1, Bang: "ddg", IsDefault: true},
+		{ID: 2, Bang: "gh"},
+	}
+	tests := []struct {
+		query     string
+		wantID    uint
+		wantTerms string
+		wantOK    bool
+	}{
+		{"dash dashboard", 1, "dash dashboard", true},
+		{"!gh dash", 2, "dash", true},
+		{"dash  !GH", 2, "dash", true},
+		{"dash !gh go", 1, "dash !gh go", true},
+		{"!nope dash", 1, "!nope dash", true},
+		{"   ", 0, "", false},
+	}
+	for _, tt := range tests {
+		p, terms, ok := ResolveWebSearch(providers, tt.query)
+		if ok != tt.wantOK || p.ID != tt.wantID || terms != tt.wantTerms {
+			t.Errorf("ResolveWebSearch(%q) = %d, %q, %v; want %d, %q, %v", tt.query, p.ID, terms, ok, tt.wantID, tt.wantTerms, tt.wantOK)
+		}
+	}
+
+	if _, _, ok := ResolveWebSearch(providers[1:], "dash"); ok {
+		t.Errorf("without a default provider a query without bang must not resolve")
+	}
+}
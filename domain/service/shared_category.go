@@ -0,0 +1,17 @@
+package service
+
+import (
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+
+	"github.com/samber/lo"
+)
+
+// FilterSharedCategoriesForUser returns only the shared categories visible to
+// a user based on group membership, with the same rule as FilterForUser.
+// Categories the user published themselves are left out, since they already
+// appear among the user's own categories.
+func FilterSharedCategoriesForUser(categories []model.SharedCategory, userID string, userGroups []string) []model.SharedCategory {
+	return lo.Filter(categories, func(category model.SharedCategory, _ int) bool {
+		return category.PublishedBy != userID && visibleToGroups(category.VisibleToGroups, userGroups)
+	})
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+)
+
+func makeSharedCategory(id uint, publishedBy string, groups ...string) model.SharedCategory {
+	return model.SharedCategory{
+		Category:        model.Category{ID: id, DisplayName: "Docs"},
+		VisibleToGroups: groups,
+		PublishedBy:     publishedBy,
+	}
+}
+
+func TestFilterSharedCategoriesForUser_NoGroupRestriction(t *testing.T) {
+	categories := []model.SharedCategory{makeSharedCategory(1, "admin-1")}
+	result := FilterSharedCategoriesForUser(categories, "user-1", nil)
+	if len(result) != 1 {
+		t.Errorf("categories with no groups should be visible to everyone, got %d", len(result))
+	}
+}
+
+func TestFilterSharedCategoriesForUser_GroupMembership(t *testing.T) {
+	categories := []model.SharedCategory{
+		makeSharedCategory(1, "admin-1", "family"),
+		makeSharedCategory(2, "admin-1", "dev"),
+	}
+	result := FilterSharedCategoriesForUser(categories, "user-1", []string{"family"})
+	if len(result) != 1 || result[0].ID != 1 {
+		t.Errorf("user should only see the category of their group, got %v", result)
+	}
+}
+
+func TestFilterSharedCategoriesForUser_SkipsOwnCategories(t *testing.T) {
+	categories := []model.SharedCategory{makeSharedCategory(1, "admin-1")}
+	result := FilterSharedCategoriesForUser(categories, "admin-1", nil)
+	if len(result) != 0 {
+		t.Errorf("the publisher should not see their category twice, got %d", len(result))
+	}
+}
@@ -0,0 +1,141 @@
+package accesstoken
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// LocalGroupsResolver adds the Dash-managed groups of a user to the identity.
+// query.ResolveLocalGroups implements this interface.
+type LocalGroupsResolver interface {
+	Handle(ctx context.Context, identity model.Identity) (model.Identity, error)
+}
+
+// Loader authenticates requests carrying a personal access token in the
+// Authorization header ("Bearer dash_pat_…").
+// This type implements the middleware.IdentityLoader interface.
+type Loader struct {
+	repo        domainrepo.AccessTokenRepository
+	sessionRepo domainrepo.SessionRepository
+	localGroups LocalGroupsResolver // optional
+}
+
+func NewLoader(repo domainrepo.AccessTokenRepository, sessionRepo domainrepo.SessionRepository, localGroups LocalGroupsResolver) *Loader {
+	return &Loader{repo: repo, sessionRepo: sessionRepo, localGroups: localGroups}
+}
+
+// LoadIdentity resolves the token owner's identity from the snapshot kept
+// with the token, so a token keeps working without a session. While the owner
+// has an active session, the snapshot is refreshed from it, so groups and
+// admin status follow the IdP. Requests whose method is not permitted by the
+// token's scope are treated as unauthenticated, so read-only tokens cannot
+// reach any mutating handler.
+func (l *Loader) LoadIdentity(c fiber.Ctx) (model.Identity, bool) {
+	secret, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+	if !ok {
+		return model.Identity{}, false
+	}
+
+	ctx := context.Background()
+	record, err := l.repo.Touch(ctx, model.HashAccessToken(secret), c.IP())
+	if err != nil || record == nil {
+		// Fail closed on DB errors, same as SessionStore.LoadIdentity.
+		return model.Identity{}, false
+	}
+
+	scope, err := model.ParseAccessTokenScope(record.Scope)
+	if err != nil || !scope.Permits(c.Method()) {
+		return model.Identity{}, false
+	}
+
+	if session, err := l.sessionRepo.FindLatestByUserID(ctx, record.UserID); err == nil && session != nil {
+		fresh := withSessionIdentity(*record, session)
+		if !sameIdentity(record, &fresh) {
+			// Best effort: this request already uses the fresh identity.
+			_ = l.repo.UpdateIdentity(ctx, &fresh)
+		}
+		record = &fresh
+	}
+
+	identity := tokenToIdentity(record)
+	if l.localGroups != nil {
+		// Same as SessionStore.WithLocalGroups: keep the IdP groups on errors.
+		if resolved, err := l.localGroups.Handle(ctx, identity); err == nil {
+			identity = resolved
+		}
+	}
+	return identity, true
+}
+
+// bearerToken extracts a personal access token from an Authorization header.
+// Bearer values without the dash token prefix are ignored so that other
+// bearer schemes passing through a proxy never hit the database.
+func bearerToken(header string) (string, bool) {
+	scheme, value, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, model.AccessTokenPrefix) {
+		return "", false
+	}
+	return value, true
+}
+
+// withSessionIdentity returns a copy of the token with the identity snapshot
+// of the session.
+func withSessionIdentity(r domainrepo.AccessTokenRecord, s *domainrepo.SessionRecord) domainrepo.AccessTokenRecord {
+	r.Username = s.Username
+	r.Email = s.Email
+	r.FirstName = s.FirstName
+	r.LastName = s.LastName
+	r.DisplayName = s.DisplayName
+	r.Picture = s.Picture
+	r.ProfileUrl = s.ProfileUrl
+	r.Groups = s.Groups
+	r.IsAdmin = s.IsAdmin
+	return r
+}
+
+// sameIdentity reports whether both tokens carry the same identity snapshot.
+func sameIdentity(a, b *domainrepo.AccessTokenRecord) bool {
+	return a.Username == b.Username &&
+		a.Email == b.Email &&
+		a.FirstName == b.FirstName &&
+		a.LastName == b.LastName &&
+		a.DisplayName == b.DisplayName &&
+		a.Picture == b.Picture &&
+		a.ProfileUrl == b.ProfileUrl &&
+		slices.Equal(a.Groups, b.Groups) &&
+		a.IsAdmin == b.IsAdmin
+}
+
+// tokenToIdentity maps the identity snapshot of a token to a domain Identity.
+func tokenToIdentity(r *domainrepo.AccessTokenRecord) model.Identity {
+	var picture *string
+	if r.Picture != "" {
+		picture = &r.Picture
+	}
+	var profileUrl *string
+	if r.ProfileUrl != "" {
+		profileUrl = &r.ProfileUrl
+	}
+	return model.Identity{
+		UserID:      r.UserID,
+		FirstName:   r.FirstName,
+		LastName:    r.LastName,
+		DisplayName: r.DisplayName,
+		Username:    r.Username,
+		Email:       r.Email,
+		Picture:     picture,
+		Groups:      r.Groups,
+		IsAdmin:     r.IsAdmin,
+		ProfileUrl:  profileUrl,
+	}
+}
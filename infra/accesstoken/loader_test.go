@@ -0,0 +1,167 @@
+package accesstoken
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+const testSecret = "dash_pat_secret"
+
+type resolverFunc func(ctx context.Context, identity model.Identity) (model.Identity, error)
+
+func (f resolverFunc) Handle(ctx context.Context, identity model.Identity) (model.Identity, error) {
+	return f(ctx, identity)
+}
+
+// serve runs a single request with the test token through a Fiber app that
+// exposes the loaded identity.
+func serve(t *testing.T, loader *Loader, method string) (model.Identity, bool) {
+	t.Helper()
+	var (
+		identity model.Identity
+		ok       bool
+	)
+	app := fiber.New()
+	app.Add([]string{method}, "/", func(c fiber.Ctx) error {
+		identity, ok = loader.LoadIdentity(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(method, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testSecret)
+	_, err := app.Test(req)
+	require.NoError(t, err)
+	return identity, ok
+}
+
+func tokenRepo(scope string) *repoMock.AccessTokenRepository {
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("Touch", mock.Anything, model.HashAccessToken(testSecret), mock.Anything).
+		Return(&domainrepo.AccessTokenRecord{
+			ID:       "tok-1",
+			UserID:   "user-1",
+			Scope:    scope,
+			Username: "ada",
+			Groups:   []string{"users"},
+		}, nil)
+	return repo
+}
+
+func noSession() *repoMock.SessionRepository {
+	repo := &repoMock.SessionRepository{}
+	repo.On("FindLatestByUserID", mock.Anything, "user-1").Return(nil, nil)
+	return repo
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		wantOk bool
+	}{
+		{"Bearer dash_pat_abc", "dash_pat_abc", true},
+		{"bearer dash_pat_abc", "dash_pat_abc", true},
+		{"  Bearer   dash_pat_abc  ", "dash_pat_abc", true},
+		{"Bearer eyJhbGciOi", "", false}, // foreign bearer token
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"dash_pat_abc", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := bearerToken(tt.header)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("bearerToken(%q) = (%q, %v), want (%q, %v)", tt.header, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestLoadIdentity_RefreshesSnapshotFromSession(t *testing.T) {
+	repo := tokenRepo("read")
+	repo.On("UpdateIdentity", mock.Anything, mock.MatchedBy(func(r *domainrepo.AccessTokenRecord) bool {
+		return r.UserID == "user-1" && r.Username == "ada" && len(r.Groups) == 1 && r.Groups[0] == "admins" && r.IsAdmin
+	})).Return(nil)
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("FindLatestByUserID", mock.Anything, "user-1").Return(&domainrepo.SessionRecord{
+		UserID:   "user-1",
+		Username: "ada",
+		Groups:   []string{"admins"},
+		IsAdmin:  true,
+	}, nil)
+
+	identity, ok := serve(t, NewLoader(repo, sessionRepo, nil), fiber.MethodGet)
+
+	require.True(t, ok)
+	require.Equal(t, []string{"admins"}, identity.Groups)
+	require.True(t, identity.IsAdmin)
+	repo.AssertExpectations(t)
+}
+
+func TestLoadIdentity_UnchangedSnapshotNotWritten(t *testing.T) {
+	repo := tokenRepo("read")
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("FindLatestByUserID", mock.Anything, "user-1").
+		Return(&domainrepo.SessionRecord{UserID: "user-1", Username: "ada", Groups: []string{"users"}}, nil)
+
+	_, ok := serve(t, NewLoader(repo, sessionRepo, nil), fiber.MethodGet)
+
+	require.True(t, ok)
+	repo.AssertNotCalled(t, "UpdateIdentity", mock.Anything, mock.Anything)
+}
+
+// Tokens for scripts outlive the owner's sessions, which end with the ID
+// token, on logout or when an admin revokes them.
+func TestLoadIdentity_NoSessionUsesSnapshot(t *testing.T) {
+	identity, ok := serve(t, NewLoader(tokenRepo("read"), noSession(), nil), fiber.MethodGet)
+
+	require.True(t, ok)
+	require.Equal(t, "user-1", identity.UserID)
+	require.Equal(t, "ada", identity.Username)
+	require.Equal(t, []string{"users"}, identity.Groups)
+	require.False(t, identity.IsAdmin)
+}
+
+func TestLoadIdentity_ScopeRejected(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+
+	_, ok := serve(t, NewLoader(tokenRepo("read"), sessionRepo, nil), fiber.MethodPost)
+
+	require.False(t, ok)
+	sessionRepo.AssertNotCalled(t, "FindLatestByUserID", mock.Anything, mock.Anything)
+}
+
+func TestLoadIdentity_UnknownToken(t *testing.T) {
+	repo := &repoMock.AccessTokenRepository{}
+	repo.On("Touch", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, ok := serve(t, NewLoader(repo, &repoMock.SessionRepository{}, nil), fiber.MethodGet)
+
+	require.False(t, ok)
+}
+
+// Groups granted by an invitation are resolved on every request rather than
+// kept with the token, so revoking the grant also ends it for the token.
+func TestLoadIdentity_GrantedGroupsResolvedPerRequest(t *testing.T) {
+	granted := []string{"guests"}
+	loader := NewLoader(tokenRepo("read"), noSession(), resolverFunc(func(_ context.Context, identity model.Identity) (model.Identity, error) {
+		require.Equal(t, []string{"users"}, identity.Groups, "resolver must start from the IdP groups of the snapshot")
+		return identity.WithGrantedGroups(granted), nil
+	}))
+
+	identity, ok := serve(t, loader, fiber.MethodGet)
+	require.True(t, ok)
+	require.Contains(t, identity.Groups, "guests")
+
+	granted = nil // redemption revoked
+	identity, ok = serve(t, loader, fiber.MethodGet)
+	require.True(t, ok)
+	require.NotContains(t, identity.Groups, "guests")
+}
@@ -0,0 +1,90 @@
+// Package customicon prepares uploaded images for the icon library.
+package customicon
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// Size is the edge length in pixels raster icons are scaled down to.
+	Size = 128
+	// maxPixels bounds the decoded size of raster uploads (decompression bombs).
+	maxPixels = 4096 * 4096
+)
+
+// ErrUnsupported is returned for files that are neither PNG, JPEG nor SVG.
+var ErrUnsupported = errors.New("must be a PNG, JPEG or SVG image")
+
+// Processor scales raster icons down into a transparent square PNG of at most
+// Size×Size pixels and strips
+// scripts, event handlers and external references from SVGs.
+// This type implements the command.CustomIconProcessor interface.
+type Processor struct{}
+
+func NewProcessor() *Processor { return &Processor{} }
+
+func (p *Processor) Process(data []byte) (string, []byte, error) {
+	if isSVG(data) {
+		out, err := SanitizeSVG(data)
+		if err != nil {
+			return "", nil, err
+		}
+		return "image/svg+xml", out, nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return "", nil, ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return "", nil, errors.New("image dimensions are too large")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", nil, ErrUnsupported
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, Fit(img, Size)); err != nil {
+		return "", nil, err
+	}
+	return "image/png", buf.Bytes(), nil
+}
+
+// Fit centres src on a transparent square canvas as wide as its longer side,
+// scaling it down first when that side exceeds size.
+func Fit(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	edge := max(w, h)
+	if edge > size {
+		w, h = max(1, w*size/edge), max(1, h*size/edge)
+		edge = size
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, edge, edge))
+	dst := image.Rect((edge-w)/2, (edge-h)/2, (edge-w)/2+w, (edge-h)/2+h)
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(out, dst, src, b.Min, draw.Src)
+	} else {
+		xdraw.CatmullRom.Scale(out, dst, src, b, xdraw.Src, nil)
+	}
+	return out
+}
+
+func isSVG(data []byte) bool {
+	head := bytes.TrimPrefix(data, utf8BOM)
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	head = bytes.ToLower(bytes.TrimSpace(head))
+	return bytes.HasPrefix(head, []byte("<svg")) ||
+		(bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<!--")) || bytes.HasPrefix(head, []byte("<!doctype"))) &&
+			bytes.Contains(head, []byte("<svg"))
+}
@@ -0,0 +1,68 @@
+package customicon
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestProcessor_Process_ScalesLargePNG(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, testImage(512, 256, color.NRGBA{R: 255, A: 255})))
+
+	contentType, data, err := NewProcessor().Process(buf.Bytes())
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, Size, Size), img.Bounds())
+	// The wide image is centred; the bands above and below stay transparent.
+	_, _, _, a := img.At(Size/2, 0).RGBA()
+	require.Zero(t, a)
+	r, _, _, _ := img.At(Size/2, Size/2).RGBA()
+	require.Equal(t, uint32(0xffff), r)
+}
+
+func TestProcessor_Process_KeepsSmallJPEGSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, jpeg.Encode(buf, testImage(32, 16, color.White), nil))
+
+	contentType, data, err := NewProcessor().Process(buf.Bytes())
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 32, 32), img.Bounds())
+}
+
+func TestProcessor_Process_SanitizesSVG(t *testing.T) {
+	contentType, data, err := NewProcessor().Process([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+
+	require.NoError(t, err)
+	require.Equal(t, "image/svg+xml", contentType)
+	require.NotContains(t, string(data), "script")
+}
+
+func TestProcessor_Process_Unsupported(t *testing.T) {
+	for _, data := range [][]byte{[]byte("GIF89a"), []byte("hello"), {}} {
+		_, _, err := NewProcessor().Process(data)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,188 @@
+package customicon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+const svgNamespace = "http://www.w3.org/2000/svg"
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// svgElements lists the elements kept by SanitizeSVG. Anything else, notably
+// <script>, <foreignObject> and editor metadata, is dropped with its content.
+var svgElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "title": true, "desc": true, "symbol": true, "use": true,
+	"path": true, "rect": true, "circle": true, "ellipse": true, "line": true, "polyline": true, "polygon": true,
+	"text": true, "tspan": true, "textPath": true, "image": true, "style": true,
+	"linearGradient": true, "radialGradient": true, "stop": true, "pattern": true, "clipPath": true, "mask": true,
+	"marker": true, "filter": true, "feBlend": true, "feColorMatrix": true, "feComponentTransfer": true,
+	"feComposite": true, "feDropShadow": true, "feFlood": true, "feFuncA": true, "feFuncB": true, "feFuncG": true,
+	"feFuncR": true, "feGaussianBlur": true, "feMerge": true, "feMergeNode": true, "feMorphology": true,
+	"feOffset": true,
+}
+
+// SanitizeSVG re-serialises an SVG document keeping only known elements and
+// attributes without active content: event handlers, external links and
+// stylesheets referring to other resources are removed. Comments, processing
+// instructions and DOCTYPEs are dropped as well.
+func SanitizeSVG(data []byte) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+
+	out := &bytes.Buffer{}
+	skip := 0 // > 0: inside a dropped element, at that depth
+	// RawToken does not match end tags against start tags, so all elements
+	// are tracked; open holds those that are written.
+	var names []xml.Name
+	var open []string
+	var style strings.Builder // text of the open <style>, checked as a whole
+	for {
+		tok, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid SVG: " + err.Error())
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			names = append(names, t.Name)
+			depth := len(names)
+			if skip > 0 {
+				continue
+			}
+			if depth == 1 && (t.Name.Space != "" || t.Name.Local != "svg" || out.Len() > 0) {
+				return nil, errors.New("invalid SVG: root element must be a single <svg>")
+			}
+			if t.Name.Space != "" || !svgElements[t.Name.Local] {
+				skip = depth
+				continue
+			}
+			writeStartElement(out, t, depth == 1)
+			open = append(open, t.Name.Local)
+		case xml.EndElement:
+			depth := len(names)
+			if depth == 0 || names[depth-1] != t.Name {
+				return nil, errors.New("invalid SVG: unexpected end element </" + t.Name.Local + ">")
+			}
+			names = names[:depth-1]
+			if skip == depth {
+				skip = 0
+			} else if skip == 0 {
+				if open[len(open)-1] == "style" {
+					if !hasExternalReference(style.String()) {
+						_ = xml.EscapeText(out, []byte(style.String()))
+					}
+					style.Reset()
+				}
+				out.WriteString("</" + open[len(open)-1] + ">")
+				open = open[:len(open)-1]
+			}
+		case xml.CharData:
+			if skip > 0 || len(names) == 0 {
+				continue
+			}
+			if open[len(open)-1] == "style" {
+				style.Write(t)
+				continue
+			}
+			_ = xml.EscapeText(out, t)
+		}
+	}
+	if len(names) > 0 {
+		return nil, errors.New("invalid SVG: unexpected end of document")
+	}
+	if out.Len() == 0 {
+		return nil, errors.New("invalid SVG: no <svg> element")
+	}
+	return out.Bytes(), nil
+}
+
+func writeStartElement(out *bytes.Buffer, t xml.StartElement, root bool) {
+	out.WriteString("<" + t.Name.Local)
+	hasNamespace := false
+	for _, a := range t.Attr {
+		name, ok := attrName(a.Name)
+		if !ok || !safeAttr(t.Name.Local, name, a.Value) {
+			continue
+		}
+		if name == "xmlns" {
+			if a.Value != svgNamespace {
+				continue
+			}
+			hasNamespace = true
+		}
+		out.WriteString(" " + name + `="`)
+		_ = xml.EscapeText(out, []byte(a.Value))
+		out.WriteString(`"`)
+	}
+	// Browsers only render SVGs in the SVG namespace.
+	if root && !hasNamespace {
+		out.WriteString(` xmlns="` + svgNamespace + `"`)
+	}
+	out.WriteString(">")
+}
+
+// attrName returns the qualified name of an attribute the sanitizer knows the
+// namespace of. Attributes of other namespaces (e.g. inkscape:) are dropped
+// because their prefix declarations are dropped too.
+func attrName(n xml.Name) (string, bool) {
+	switch n.Space {
+	case "":
+		return n.Local, true
+	case "xlink", "xml":
+		return n.Space + ":" + n.Local, true
+	case "xmlns":
+		return "xmlns:" + n.Local, n.Local == "xlink"
+	}
+	return "", false
+}
+
+func safeAttr(element, name, value string) bool {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(lower, "on"):
+		return false
+	case lower == "href" || lower == "xlink:href":
+		v := strings.TrimSpace(value)
+		if strings.HasPrefix(v, "#") {
+			return true
+		}
+		return element == "image" && isRasterDataURL(v)
+	}
+	return !hasExternalReference(value)
+}
+
+// hasExternalReference reports whether CSS or a presentation attribute refers
+// to anything but a fragment of the document, e.g. url(https://…) or @import.
+// CSS escapes could hide such references, so any backslash counts as one.
+func hasExternalReference(s string) bool {
+	lower := strings.ToLower(s)
+	if strings.Contains(lower, "@import") || strings.Contains(lower, "image-set(") || strings.Contains(lower, `\`) {
+		return true
+	}
+	for {
+		i := strings.Index(lower, "url(")
+		if i < 0 {
+			return false
+		}
+		lower = strings.TrimLeft(lower[i+len("url("):], " \t\r\n'\"")
+		if !strings.HasPrefix(lower, "#") {
+			return true
+		}
+	}
+}
+
+func isRasterDataURL(v string) bool {
+	v = strings.ToLower(v)
+	for _, prefix := range []string{"data:image/png;", "data:image/jpeg;", "data:image/gif;", "data:image/webp;"} {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return false
+}
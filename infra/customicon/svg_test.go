@@ -0,0 +1,77 @@
+package customicon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "keeps shapes",
+			input: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M0 0h24v24H0z" fill="#fff"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M0 0h24v24H0z" fill="#fff"></path></svg>`,
+		},
+		{
+			name:  "adds namespace",
+			input: `<svg><rect width="1" height="1"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><rect width="1" height="1"></rect></svg>`,
+		},
+		{
+			name:  "drops scripts and foreign objects with content",
+			input: `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><foreignObject><div>x</div></foreignObject><g/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><g></g></svg>`,
+		},
+		{
+			name:  "drops event handlers",
+			input: `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><circle r="1" onClick="alert(1)"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><circle r="1"></circle></svg>`,
+		},
+		{
+			name:  "keeps fragment links only",
+			input: `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="#a"/><use href="https://evil.test/x.svg#a"/><image href="javascript:alert(1)"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="#a"></use><use></use><image></image></svg>`,
+		},
+		{
+			name:  "keeps embedded raster images",
+			input: `<svg xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><image href="data:image/png;base64,AAAA"></image></svg>`,
+		},
+		{
+			name:  "drops external references in styles",
+			input: `<svg xmlns="http://www.w3.org/2000/svg"><style><![CDATA[@imp]]><![CDATA[ort url(x.css);]]></style><rect fill="url(#g)" style="fill: url( 'https://evil.test/a.png' )"/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><style></style><rect fill="url(#g)"></rect></svg>`,
+		},
+		{
+			name:  "drops editor metadata",
+			input: `<?xml version="1.0"?><!-- Created with Inkscape --><svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" inkscape:version="1.0"><metadata>x</metadata><inkscape:grid/><g/></svg>`,
+			want:  `<svg xmlns="http://www.w3.org/2000/svg"><g></g></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeSVG([]byte(tt.input))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestSanitizeSVG_Invalid(t *testing.T) {
+	for _, input := range []string{
+		`<html><svg/></html>`,
+		`<svg><g></svg>`,
+		`<!DOCTYPE svg [<!ENTITY x "y">]><svg>&x;</svg>`,
+		`<!-- empty -->`,
+	} {
+		_, err := SanitizeSVG([]byte(input))
+		require.Error(t, err, input)
+	}
+}
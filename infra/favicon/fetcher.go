@@ -0,0 +1,356 @@
+package favicon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/infra/customicon"
+
+	_ "golang.org/x/image/webp"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// Size is the edge length in pixels raster icons are scaled to.
+	Size = 64
+
+	maxPageBytes     = 1 << 20
+	maxManifestBytes = 256 << 10
+	maxIconBytes     = 512 << 10
+	// maxAttempts bounds the icon URLs tried per site.
+	maxAttempts = 5
+)
+
+// errBlockedAddress is returned for connections to addresses the fetcher
+// must not reach.
+var errBlockedAddress = errors.New("address not allowed")
+
+var (
+	// thisNetwork (0.0.0.0/8) reaches the local host on Linux.
+	thisNetwork = netip.MustParsePrefix("0.0.0.0/8")
+	// sharedAddressSpace (RFC 6598) is used by carrier-grade NAT and VPNs
+	// such as Tailscale.
+	sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+)
+
+// Fetcher resolves the icon of a site from the <link rel="icon"> tags of its
+// start page, its web app manifest and finally /favicon.ico. Raster icons are
+// scaled to Size×Size PNGs; SVG icons are sanitised like uploaded ones.
+// This type implements the query.FaviconFetcher interface.
+type Fetcher struct {
+	client *http.Client
+}
+
+// NewFetcher returns a Fetcher whose requests each time out after timeout.
+// Sites are entered by users, so it only connects to public addresses, and
+// to private networks if allowPrivate is set; see allowedAddr.
+func NewFetcher(timeout time.Duration, allowPrivate bool) *Fetcher {
+	return newFetcher(timeout, func(ip netip.Addr) bool {
+		return allowedAddr(ip, allowPrivate)
+	})
+}
+
+func newFetcher(timeout time.Duration, allow func(netip.Addr) bool) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Control sees the resolved address of every connection, including
+		// those of redirects, so DNS cannot be used to reach a blocked one.
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil || !allow(ap.Addr().Unmap()) {
+				return fmt.Errorf("favicon: %s: %w", address, errBlockedAddress)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the site on our behalf, past the check above.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Fetcher{client: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// allowedAddr reports whether the fetcher may connect to ip. Loopback,
+// link-local (including cloud metadata services), unspecified and multicast
+// addresses are always refused; private networks (RFC 1918, fc00::/7 and
+// 100.64.0.0/10) only unless allowPrivate is set.
+func allowedAddr(ip netip.Addr, allowPrivate bool) bool {
+	switch {
+	case !ip.IsValid(), ip.IsLoopback(), ip.IsUnspecified(), thisNetwork.Contains(ip),
+		ip.IsLinkLocalUnicast(), ip.IsMulticast():
+		return false
+	case ip.IsPrivate(), sharedAddressSpace.Contains(ip):
+		return allowPrivate
+	default:
+		return true
+	}
+}
+
+type iconCandidate struct {
+	url  string
+	size int // largest declared edge length, 0 = unknown
+	svg  bool
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, site string) (string, []byte, error) {
+	candidates := f.discover(ctx, site)
+	candidates = append(candidates, iconCandidate{url: site + "/favicon.ico"})
+
+	var lastErr error
+	tried := map[string]bool{}
+	for _, c := range candidates {
+		if len(tried) == maxAttempts {
+			break
+		}
+		if tried[c.url] {
+			continue
+		}
+		tried[c.url] = true
+
+		contentType, data, err := f.fetchIcon(ctx, c.url)
+		if err == nil {
+			return contentType, data, nil
+		}
+		lastErr = err
+	}
+	return "", nil, fmt.Errorf("favicon: no usable icon for %s: %w", site, lastErr)
+}
+
+// discover returns the icons declared by the start page of site and its
+// manifest, best first. Errors are ignored: /favicon.ico is still worth a try.
+func (f *Fetcher) discover(ctx context.Context, site string) []iconCandidate {
+	body, base, err := f.get(ctx, site+"/", "text/html", maxPageBytes)
+	if err != nil {
+		return nil
+	}
+
+	var icons []iconCandidate
+	var manifest string
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		if tok.DataAtom == atom.Body {
+			break
+		}
+		if tok.DataAtom != atom.Link {
+			continue
+		}
+
+		var rel, href, sizes, typ string
+		for _, a := range tok.Attr {
+			switch a.Key {
+			case "rel":
+				rel = strings.ToLower(a.Val)
+			case "href":
+				href = a.Val
+			case "sizes":
+				sizes = a.Val
+			case "type":
+				typ = a.Val
+			}
+		}
+		u := resolve(base, href)
+		if u == "" {
+			continue
+		}
+		for _, r := range strings.Fields(rel) {
+			switch r {
+			case "icon", "apple-touch-icon", "apple-touch-icon-precomposed":
+				icons = append(icons, newCandidate(u, sizes, typ))
+			case "manifest":
+				manifest = u
+			}
+		}
+	}
+
+	if manifest != "" {
+		icons = append(icons, f.manifestIcons(ctx, manifest)...)
+	}
+
+	sort.SliceStable(icons, func(i, j int) bool {
+		return betterIcon(icons[i], icons[j])
+	})
+	return icons
+}
+
+// manifestIcons returns the icons listed in the web app manifest at u.
+func (f *Fetcher) manifestIcons(ctx context.Context, u string) []iconCandidate {
+	body, base, err := f.get(ctx, u, "application/manifest+json, application/json", maxManifestBytes)
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Icons []struct {
+			Src   string `json:"src"`
+			Sizes string `json:"sizes"`
+			Type  string `json:"type"`
+		} `json:"icons"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil
+	}
+	var icons []iconCandidate
+	for _, icon := range manifest.Icons {
+		if src := resolve(base, icon.Src); src != "" {
+			icons = append(icons, newCandidate(src, icon.Sizes, icon.Type))
+		}
+	}
+	return icons
+}
+
+// fetchIcon downloads the icon at u and normalises it.
+func (f *Fetcher) fetchIcon(ctx context.Context, u string) (string, []byte, error) {
+	data, _, err := f.get(ctx, u, "image/*, */*;q=0.8", maxIconBytes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if isSVG(data) {
+		clean, err := customicon.SanitizeSVG(data)
+		if err != nil {
+			return "", nil, fmt.Errorf("favicon: %s: %w", u, err)
+		}
+		return "image/svg+xml", clean, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil && isICO(data) {
+		img, err = decodeICO(data)
+		if err != nil {
+			// Leave exotic icons to the browser.
+			return "image/x-icon", data, nil
+		}
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("favicon: %s: %w", u, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, Resize(img, Size)); err != nil {
+		return "", nil, err
+	}
+	return "image/png", buf.Bytes(), nil
+}
+
+// get requests u and returns at most limit bytes of the body together with
+// the URL after redirects, against which relative links resolve.
+func (f *Fetcher) get(ctx context.Context, u string, accept string, limit int64) ([]byte, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", "Dash favicon fetcher")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("favicon: %s: unexpected status %d", u, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("favicon: %s: larger than %d bytes", u, limit)
+	}
+	return data, resp.Request.URL, nil
+}
+
+func newCandidate(u, sizes, typ string) iconCandidate {
+	c := iconCandidate{url: u}
+	if mt, _, err := mime.ParseMediaType(typ); err == nil && mt == "image/svg+xml" {
+		c.svg = true
+	}
+	if path, _, _ := strings.Cut(u, "?"); strings.HasSuffix(strings.ToLower(path), ".svg") {
+		c.svg = true
+	}
+	for _, s := range strings.Fields(strings.ToLower(sizes)) {
+		w, _, ok := strings.Cut(s, "x")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(w); err == nil && n > c.size {
+			c.size = n
+		}
+	}
+	return c
+}
+
+// betterIcon orders candidates: raster icons of at least Size first, the
+// smallest of them preferred, then SVGs, then smaller raster icons, larger
+// first, and icons without declared size last.
+func betterIcon(a, b iconCandidate) bool {
+	rank := func(c iconCandidate) int {
+		switch {
+		case c.svg:
+			return 1
+		case c.size >= Size:
+			return 0
+		case c.size > 0:
+			return 2
+		default:
+			return 3
+		}
+	}
+	ra, rb := rank(a), rank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	if ra == 0 {
+		return a.size < b.size
+	}
+	return a.size > b.size
+}
+
+// resolve returns href as an absolute http(s) URL relative to base, or ""
+// when it is empty or uses another scheme (e.g. data:).
+func resolve(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func isSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	head = bytes.ToLower(bytes.TrimSpace(head))
+	return bytes.HasPrefix(head, []byte("<svg")) ||
+		(bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<!--"))) && bytes.Contains(head, []byte("<svg"))
+}
@@ -0,0 +1,230 @@
+package favicon
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, size int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, png.Encode(buf, img))
+	return buf.Bytes()
+}
+
+// testFetcher returns a Fetcher that may connect to the loopback address of
+// httptest servers.
+func testFetcher() *Fetcher {
+	return newFetcher(time.Second, func(netip.Addr) bool { return true })
+}
+
+func decodePNG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return img
+}
+
+func TestFetcher_Fetch_PrefersLinkedIcon(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<html><head>
+			<link rel="icon" href="/small.png" sizes="16x16">
+			<link rel="icon" href="/static/large.png" sizes="128x128">
+		</head><body></body></html>`))
+	})
+	mux.HandleFunc("/static/large.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(testPNG(t, 128, red))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	contentType, data, err := testFetcher().Fetch(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	img := decodePNG(t, data)
+	require.Equal(t, Size, img.Bounds().Dx())
+	r, _, _, _ := img.At(Size/2, Size/2).RGBA()
+	require.Equal(t, uint32(0xffff), r)
+}
+
+func TestFetcher_Fetch_ManifestIcons(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<head><link rel="manifest" href="/app/manifest.json"></head>`))
+	})
+	mux.HandleFunc("/app/manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"icons":[{"src":"icon-192.png","sizes":"192x192","type":"image/png"}]}`))
+	})
+	mux.HandleFunc("/app/icon-192.png", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(testPNG(t, 192, color.White))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	contentType, _, err := testFetcher().Fetch(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+}
+
+func TestFetcher_Fetch_FallsBackToFaviconICO(t *testing.T) {
+	ico, err := BuildICO([]image.Image{decodePNG(t, testPNG(t, 32, color.White))}, []int{32})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<head><link rel="icon" href="/missing.png"></head>`))
+	})
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(ico)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	contentType, data, err := testFetcher().Fetch(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	require.Equal(t, Size, decodePNG(t, data).Bounds().Dx())
+}
+
+func TestFetcher_Fetch_SanitizesSVG(t *testing.T) {
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 1 1" onload="alert(1)"><script>alert(2)</script><rect width="1" height="1"/></svg>`)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<head><link rel="icon" href="/icon.svg" type="image/svg+xml"></head>`))
+	})
+	mux.HandleFunc("/icon.svg", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(svg)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	contentType, data, err := testFetcher().Fetch(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, "image/svg+xml", contentType)
+	require.Contains(t, string(data), "<rect")
+	require.NotContains(t, string(data), "alert")
+}
+
+func TestFetcher_Fetch_NoIcon(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, _, err := testFetcher().Fetch(context.Background(), srv.URL)
+
+	require.Error(t, err)
+}
+
+func TestFetcher_Fetch_RefusesLoopback(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	_, _, err := NewFetcher(time.Second, true).Fetch(context.Background(), srv.URL)
+
+	require.ErrorIs(t, err, errBlockedAddress)
+	require.Zero(t, requests)
+}
+
+func TestFetcher_Fetch_RefusesRedirectToBlockedAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+	// Only the test server itself is reachable.
+	local := netip.MustParseAddrPort(srv.Listener.Addr().String()).Addr()
+	f := newFetcher(time.Second, func(ip netip.Addr) bool { return ip == local })
+
+	_, _, err := f.Fetch(context.Background(), srv.URL)
+
+	require.ErrorIs(t, err, errBlockedAddress)
+}
+
+func TestAllowedAddr(t *testing.T) {
+	tests := []struct {
+		addr         string
+		allowPrivate bool
+		want         bool
+	}{
+		{"140.82.121.4", false, true},
+		{"2606:4700::1111", false, true},
+		{"127.0.0.1", true, false},
+		{"::1", true, false},
+		{"0.0.0.0", true, false},
+		{"0.1.2.3", true, false},
+		{"169.254.169.254", true, false},
+		{"fe80::1", true, false},
+		{"224.0.0.1", true, false},
+		{"192.168.1.10", false, false},
+		{"192.168.1.10", true, true},
+		{"10.0.0.1", false, false},
+		{"172.16.0.1", true, true},
+		{"fd00::1", false, false},
+		{"100.100.1.1", false, false},
+		{"100.100.1.1", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			require.Equal(t, tt.want, allowedAddr(netip.MustParseAddr(tt.addr), tt.allowPrivate))
+		})
+	}
+}
+
+func TestDecodeICO_32BitBitmap(t *testing.T) {
+	// A 2×2 32-bit BMP entry: bottom-up BGRA rows followed by the AND mask.
+	dib := make([]byte, 40)
+	binary.LittleEndian.PutUint32(dib[0:4], 40)
+	binary.LittleEndian.PutUint32(dib[4:8], 2)
+	binary.LittleEndian.PutUint32(dib[8:12], 4)
+	binary.LittleEndian.PutUint16(dib[12:14], 1)
+	binary.LittleEndian.PutUint16(dib[14:16], 32)
+	dib = append(dib,
+		0, 0, 255, 255, 0, 0, 255, 255, // bottom row: red
+		255, 0, 0, 128, 255, 0, 0, 128, // top row: half-transparent blue
+	)
+	dib = append(dib, make([]byte, 8)...)
+
+	ico := []byte{0, 0, 1, 0, 1, 0}
+	entry := make([]byte, 16)
+	entry[0], entry[1] = 2, 2
+	binary.LittleEndian.PutUint16(entry[4:6], 1)
+	binary.LittleEndian.PutUint16(entry[6:8], 32)
+	binary.LittleEndian.PutUint32(entry[8:12], uint32(len(dib)))
+	binary.LittleEndian.PutUint32(entry[12:16], 22)
+	ico = append(append(ico, entry...), dib...)
+
+	img, err := decodeICO(ico)
+
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 2, 2), img.Bounds())
+	require.Equal(t, color.NRGBA{B: 255, A: 128}, img.At(0, 0))
+	require.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 1))
+}
@@ -0,0 +1,121 @@
+package favicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/png"
+
+	"golang.org/x/image/bmp"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// isICO reports whether data starts with an ICONDIR header.
+func isICO(data []byte) bool {
+	return len(data) >= 6 && bytes.Equal(data[:4], []byte{0, 0, 1, 0})
+}
+
+// decodeICO decodes the largest image of an .ico file. Entries are either
+// embedded PNGs or BMPs without file header whose height counts the AND mask
+// too.
+func decodeICO(data []byte) (image.Image, error) {
+	if !isICO(data) {
+		return nil, errors.New("ico: invalid header")
+	}
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if count == 0 || len(data) < 6+16*count {
+		return nil, errors.New("ico: truncated directory")
+	}
+
+	var best []byte
+	bestSize, bestBpp := -1, -1
+	for i := 0; i < count; i++ {
+		e := data[6+16*i : 6+16*(i+1)]
+		size := int(e[0])
+		if size == 0 {
+			size = 256
+		}
+		bpp := int(binary.LittleEndian.Uint16(e[6:8]))
+		length := int(binary.LittleEndian.Uint32(e[8:12]))
+		offset := int(binary.LittleEndian.Uint32(e[12:16]))
+		if offset < 0 || length <= 0 || offset+length > len(data) {
+			continue
+		}
+		if size > bestSize || (size == bestSize && bpp > bestBpp) {
+			best, bestSize, bestBpp = data[offset:offset+length], size, bpp
+		}
+	}
+	if best == nil {
+		return nil, errors.New("ico: no valid entry")
+	}
+
+	if bytes.HasPrefix(best, pngSignature) {
+		return png.Decode(bytes.NewReader(best))
+	}
+	return decodeICODIB(best)
+}
+
+// decodeICODIB decodes a BMP entry of an .ico file. 32-bit entries carry
+// their own alpha channel; other depths are handed to the BMP decoder and
+// come out opaque.
+func decodeICODIB(dib []byte) (image.Image, error) {
+	if len(dib) < 40 {
+		return nil, errors.New("ico: truncated bitmap header")
+	}
+	headerLen := int(binary.LittleEndian.Uint32(dib[0:4]))
+	width := int(int32(binary.LittleEndian.Uint32(dib[4:8])))
+	height := int(int32(binary.LittleEndian.Uint32(dib[8:12]))) / 2
+	bpp := int(binary.LittleEndian.Uint16(dib[14:16]))
+	if headerLen < 40 || headerLen > len(dib) || width <= 0 || height <= 0 || width > 256 || height > 256 {
+		return nil, errors.New("ico: unsupported bitmap")
+	}
+
+	if bpp == 32 {
+		pixels := dib[headerLen:]
+		if len(pixels) < width*height*4 {
+			return nil, errors.New("ico: truncated bitmap")
+		}
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		noAlpha := true
+		for y := 0; y < height; y++ {
+			row := pixels[(height-1-y)*width*4:]
+			for x := 0; x < width; x++ {
+				j := img.PixOffset(x, y)
+				img.Pix[j+0] = row[x*4+2]
+				img.Pix[j+1] = row[x*4+1]
+				img.Pix[j+2] = row[x*4+0]
+				img.Pix[j+3] = row[x*4+3]
+				if row[x*4+3] != 0 {
+					noAlpha = false
+				}
+			}
+		}
+		// Old icons leave the alpha byte zero and rely on the AND mask.
+		if noAlpha {
+			for i := 3; i < len(img.Pix); i += 4 {
+				img.Pix[i] = 0xff
+			}
+		}
+		return img, nil
+	}
+
+	// Prepend a BITMAPFILEHEADER and halve the height so that the BMP
+	// decoder stops before the AND mask.
+	paletteLen := 0
+	if bpp <= 8 {
+		colors := int(binary.LittleEndian.Uint32(dib[32:36]))
+		if colors == 0 {
+			colors = 1 << bpp
+		}
+		paletteLen = colors * 4
+	}
+	file := make([]byte, 14, 14+len(dib))
+	copy(file, "BM")
+	binary.LittleEndian.PutUint32(file[2:6], uint32(14+len(dib)))
+	binary.LittleEndian.PutUint32(file[10:14], uint32(14+headerLen+paletteLen))
+	file = append(file, dib...)
+	binary.LittleEndian.PutUint32(file[14+8:14+12], uint32(height))
+	return bmp.Decode(bytes.NewReader(file))
+}
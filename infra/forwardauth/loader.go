@@ -0,0 +1,245 @@
+package forwardauth
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// UserResolver resolves the internal UserID for a proxy identity.
+// command.ResolveOrCreateUser implements this interface.
+type UserResolver interface {
+	Handle(ctx context.Context, issuer, sub string) (userID string, isNew bool, err error)
+}
+
+// AuditRecorder records the sign-in when a proxy identity starts a new
+// session. command.RecordAudit implements this interface.
+type AuditRecorder interface {
+	Handle(ctx context.Context, actor model.AuditActor, action model.AuditAction, target string)
+}
+
+// Loader authenticates requests forwarded by a trusted reverse proxy that
+// passes the signed-in user in request headers. Every proxy identity gets a
+// regular session record and cookie, so the sessions overview, pinning and
+// revocation work the same as for OIDC logins.
+// This type implements the middleware.IdentityLoader interface.
+type Loader struct {
+	cfg      config.ForwardAuthConfig
+	trusted  []netip.Prefix
+	store    *oidc.SessionStore
+	resolver UserResolver
+	audit    AuditRecorder
+}
+
+// NewLoader parses the trusted proxy CIDRs. A bare IP address is treated as a
+// single-host prefix.
+func NewLoader(cfg config.ForwardAuthConfig, store *oidc.SessionStore, resolver UserResolver, audit AuditRecorder) (*Loader, error) {
+	trusted, err := parsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = 24 * time.Hour
+	}
+	return &Loader{cfg: cfg, trusted: trusted, store: store, resolver: resolver, audit: audit}, nil
+}
+
+// LoadIdentity reads the identity from the trusted headers. Requests that do
+// not come directly from a trusted proxy, or carry no user header, are left to
+// the other loaders. When the cookie session belongs to a different user than
+// the header, that session is ended and a new one is started — the proxy is
+// authoritative for who is signed in. The session stores the proxy groups
+// only; local groups are merged in on every request by the session store.
+func (l *Loader) LoadIdentity(c fiber.Ctx) (model.Identity, bool) {
+	if !l.isTrusted(c) {
+		return model.Identity{}, false
+	}
+	username := strings.TrimSpace(c.Get(l.cfg.UserHeader))
+	if username == "" {
+		return model.Identity{}, false
+	}
+	sessionRepo := l.store.SessionRepo()
+	if sessionRepo == nil {
+		return model.Identity{}, false
+	}
+
+	ctx := context.Background()
+	identity := l.headersToIdentity(c, username)
+
+	if data, ok := l.store.Load(c); ok && data.SessionID != "" {
+		record, err := sessionRepo.Touch(ctx, data.SessionID, c.IP(), c.Get("User-Agent"))
+		if err != nil {
+			// Fail closed, same as SessionStore.LoadIdentity.
+			return model.Identity{}, false
+		}
+		if record != nil && record.Issuer == l.cfg.Issuer && record.Sub == username {
+			identity.UserID = record.UserID
+			if l.needsRefresh(record, identity) {
+				now := time.Now()
+				_ = sessionRepo.RefreshBySessionID(ctx, l.sessionRecord(data.SessionID, identity, now))
+			}
+			return l.store.WithLocalGroups(identity), true
+		}
+		if record != nil {
+			_ = sessionRepo.DeleteBySessionID(ctx, data.SessionID)
+		}
+	}
+
+	userID, _, err := l.resolver.Handle(ctx, l.cfg.Issuer, username)
+	if err != nil {
+		return model.Identity{}, false
+	}
+	identity.UserID = userID
+
+	data, err := l.store.Save(c)
+	if err != nil {
+		return model.Identity{}, false
+	}
+	now := time.Now()
+	record := l.sessionRecord(data.SessionID, identity, now)
+	record.ID = uuid.New().String()
+	record.UserID = userID
+	record.LastIP = c.IP()
+	record.UserAgent = c.Get("User-Agent")
+	if err := sessionRepo.Create(ctx, record); err != nil {
+		l.store.Clear(c)
+		return model.Identity{}, false
+	}
+	// Like the login handlers: a failed audit write must not prevent login.
+	l.audit.Handle(ctx, model.AuditActor{UserID: userID, IP: c.IP()}, model.AuditActionLogin, l.cfg.Issuer)
+	return l.store.WithLocalGroups(identity), true
+}
+
+// isTrusted reports whether the direct peer is a trusted proxy. The socket
+// address is used instead of c.IP(), which honours X-Forwarded-For and could
+// be spoofed by the client.
+func (l *Loader) isTrusted(c fiber.Ctx) bool {
+	addr, ok := netip.AddrFromSlice(c.RequestCtx().RemoteIP())
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	return slices.ContainsFunc(l.trusted, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+// headersToIdentity maps the proxy headers to a domain Identity. UserID is
+// filled in by the caller.
+func (l *Loader) headersToIdentity(c fiber.Ctx, username string) model.Identity {
+	var groups []string
+	if l.cfg.GroupsHeader != "" {
+		groups = splitGroups(c.Get(l.cfg.GroupsHeader), l.cfg.GroupsSeparator)
+	}
+
+	displayName := username
+	firstName := username
+	var lastName string
+	if l.cfg.NameHeader != "" {
+		if name := strings.TrimSpace(c.Get(l.cfg.NameHeader)); name != "" {
+			displayName = name
+			firstName, lastName, _ = strings.Cut(name, " ")
+		}
+	}
+
+	var email string
+	if l.cfg.EmailHeader != "" {
+		email = strings.TrimSpace(c.Get(l.cfg.EmailHeader))
+	}
+
+	isAdmin := lo.SomeBy(l.cfg.AdminGroups, func(g string) bool {
+		return g == "*" || lo.Contains(groups, g)
+	})
+
+	return model.Identity{
+		FirstName:   firstName,
+		LastName:    lastName,
+		DisplayName: displayName,
+		Username:    username,
+		Email:       email,
+		Groups:      groups,
+		IsAdmin:     isAdmin,
+	}.WithSyntheticGroups()
+}
+
+// needsRefresh reports whether the stored session should be updated: the
+// proxy reported different identity data, or the session is in the second
+// half of its lifetime and should be extended.
+func (l *Loader) needsRefresh(record *domainrepo.SessionRecord, identity model.Identity) bool {
+	if time.Until(record.ExpiresAt) < l.cfg.SessionTTL/2 {
+		return true
+	}
+	return record.Username != identity.Username ||
+		record.Email != identity.Email ||
+		record.DisplayName != identity.DisplayName ||
+		record.IsAdmin != identity.IsAdmin ||
+		!slices.Equal(record.Groups, identity.Groups)
+}
+
+// sessionRecord builds the session fields shared by create and refresh.
+func (l *Loader) sessionRecord(sessionID string, identity model.Identity, now time.Time) *domainrepo.SessionRecord {
+	return &domainrepo.SessionRecord{
+		SessionID:   sessionID,
+		Issuer:      l.cfg.Issuer,
+		Sub:         identity.Username,
+		Username:    identity.Username,
+		Email:       identity.Email,
+		FirstName:   identity.FirstName,
+		LastName:    identity.LastName,
+		DisplayName: identity.DisplayName,
+		Groups:      identity.Groups,
+		IsAdmin:     identity.IsAdmin,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(l.cfg.SessionTTL),
+	}
+}
+
+func splitGroups(header, separator string) []string {
+	if separator == "" {
+		separator = ","
+	}
+	var groups []string
+	for _, g := range strings.Split(header, separator) {
+		if g = strings.TrimSpace(g); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("forward auth requires at least one trusted proxy")
+	}
+	return prefixes, nil
+}
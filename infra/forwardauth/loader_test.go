@@ -0,0 +1,265 @@
+package forwardauth
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/oidc"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+type resolverFunc func(ctx context.Context, issuer, sub string) (string, bool, error)
+
+func (f resolverFunc) Handle(ctx context.Context, issuer, sub string) (string, bool, error) {
+	return f(ctx, issuer, sub)
+}
+
+type auditFunc func(ctx context.Context, actor model.AuditActor, action model.AuditAction, target string)
+
+func (f auditFunc) Handle(ctx context.Context, actor model.AuditActor, action model.AuditAction, target string) {
+	f(ctx, actor, action, target)
+}
+
+var noAudit = auditFunc(func(context.Context, model.AuditActor, model.AuditAction, string) {})
+
+func testConfig(trusted ...string) config.ForwardAuthConfig {
+	return config.ForwardAuthConfig{
+		Enabled:         true,
+		TrustedProxies:  trusted,
+		Issuer:          "forward-auth",
+		UserHeader:      "Remote-User",
+		GroupsHeader:    "Remote-Groups",
+		EmailHeader:     "Remote-Email",
+		NameHeader:      "Remote-Name",
+		GroupsSeparator: ",",
+		AdminGroups:     []string{"admin"},
+		SessionTTL:      24 * time.Hour,
+	}
+}
+
+func testStore(t *testing.T, repo domainrepo.SessionRepository) *oidc.SessionStore {
+	t.Helper()
+	store, err := oidc.NewSessionStore(&config.OIDCCookieConfig{
+		HashKey:  strings.Repeat("ab", 64),
+		BlockKey: strings.Repeat("cd", 32),
+		Name:     "dash-session",
+	}, repo, nil)
+	require.NoError(t, err)
+	return store
+}
+
+// serve runs a single request through a Fiber app that exposes the loaded
+// identity, returning the identity (if any) and the response cookies.
+func serve(t *testing.T, loader *Loader, headers map[string]string) (model.Identity, bool, []string) {
+	t.Helper()
+	var (
+		identity model.Identity
+		ok       bool
+	)
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		identity, ok = loader.LoadIdentity(c)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return identity, ok, resp.Header.Values("Set-Cookie")
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := parsePrefixes([]string{"10.0.0.0/8", " 192.168.1.5 ", "fd00::/8", ""})
+	require.NoError(t, err)
+	require.Len(t, prefixes, 3)
+	require.Equal(t, 32, prefixes[1].Bits())
+
+	_, err = parsePrefixes([]string{"not-an-ip"})
+	require.Error(t, err)
+
+	_, err = parsePrefixes(nil)
+	require.Error(t, err)
+}
+
+func TestSplitGroups(t *testing.T) {
+	require.Equal(t, []string{"admin", "users"}, splitGroups(" admin, ,users ", ","))
+	require.Equal(t, []string{"a", "b"}, splitGroups("a|b", "|"))
+	require.Nil(t, splitGroups("", ","))
+}
+
+func TestLoadIdentity_UntrustedPeerIgnored(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	loader, err := NewLoader(testConfig("10.99.0.0/16"), testStore(t, sessionRepo), resolverFunc(func(context.Context, string, string) (string, bool, error) {
+		t.Fatal("resolver must not be called for untrusted peers")
+		return "", false, nil
+	}), noAudit)
+	require.NoError(t, err)
+
+	_, ok, _ := serve(t, loader, map[string]string{"Remote-User": "mallory", "X-Forwarded-For": "10.99.0.1"})
+
+	require.False(t, ok)
+	sessionRepo.AssertNotCalled(t, "Create")
+}
+
+func TestLoadIdentity_MissingHeaderIgnored(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	loader, err := NewLoader(testConfig("0.0.0.0/0", "::/0"), testStore(t, sessionRepo), nil, noAudit)
+	require.NoError(t, err)
+
+	_, ok, _ := serve(t, loader, nil)
+
+	require.False(t, ok)
+}
+
+func TestLoadIdentity_CreatesSession(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.SessionRecord) bool {
+		return r.UserID == "user-1" && r.Issuer == "forward-auth" && r.Sub == "ada" &&
+			r.IsAdmin && r.SessionID != "" && time.Until(r.ExpiresAt) > 23*time.Hour
+	})).Return(nil)
+
+	var resolvedIssuer, resolvedSub string
+	var audited []model.AuditAction
+	loader, err := NewLoader(testConfig("0.0.0.0/0", "::/0"), testStore(t, sessionRepo), resolverFunc(func(_ context.Context, issuer, sub string) (string, bool, error) {
+		resolvedIssuer, resolvedSub = issuer, sub
+		return "user-1", true, nil
+	}), auditFunc(func(_ context.Context, actor model.AuditActor, action model.AuditAction, target string) {
+		if actor.UserID == "user-1" && target == "forward-auth" {
+			audited = append(audited, action)
+		}
+	}))
+	require.NoError(t, err)
+
+	identity, ok, cookies := serve(t, loader, map[string]string{
+		"Remote-User":   "ada",
+		"Remote-Groups": "users,admin",
+		"Remote-Email":  "ada@example.com",
+		"Remote-Name":   "Ada Lovelace",
+	})
+
+	require.True(t, ok)
+	require.Equal(t, "forward-auth", resolvedIssuer)
+	require.Equal(t, "ada", resolvedSub)
+	require.Equal(t, "user-1", identity.UserID)
+	require.Equal(t, "Ada", identity.FirstName)
+	require.Equal(t, "Lovelace", identity.LastName)
+	require.Equal(t, "Ada Lovelace", identity.DisplayName)
+	require.Equal(t, "ada@example.com", identity.Email)
+	require.True(t, identity.IsAdmin)
+	require.Contains(t, identity.Groups, "dash_admin")
+	require.NotEmpty(t, cookies)
+	require.Equal(t, []model.AuditAction{model.AuditActionLogin}, audited)
+	sessionRepo.AssertExpectations(t)
+}
+
+type localGroupsFunc func(ctx context.Context, identity model.Identity) (model.Identity, error)
+
+func (f localGroupsFunc) Handle(ctx context.Context, identity model.Identity) (model.Identity, error) {
+	return f(ctx, identity)
+}
+
+func TestLoadIdentity_MergesLocalGroups(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domainrepo.SessionRecord) bool {
+		return !slices.Contains(r.Groups, "friends")
+	})).Return(nil)
+
+	store, err := oidc.NewSessionStore(&config.OIDCCookieConfig{
+		HashKey:  strings.Repeat("ab", 64),
+		BlockKey: strings.Repeat("cd", 32),
+		Name:     "dash-session",
+	}, sessionRepo, localGroupsFunc(func(_ context.Context, identity model.Identity) (model.Identity, error) {
+		identity.Groups = append(identity.Groups, "friends")
+		return identity, nil
+	}))
+	require.NoError(t, err)
+	loader, err := NewLoader(testConfig("0.0.0.0/0", "::/0"), store, resolverFunc(func(context.Context, string, string) (string, bool, error) {
+		return "user-1", true, nil
+	}), noAudit)
+	require.NoError(t, err)
+
+	identity, ok, _ := serve(t, loader, map[string]string{"Remote-User": "ada"})
+
+	require.True(t, ok)
+	require.Contains(t, identity.Groups, "friends")
+	sessionRepo.AssertExpectations(t)
+}
+
+// sessionCookie signs in once and returns the session cookie header value.
+func sessionCookie(t *testing.T, loader *Loader, username string) string {
+	t.Helper()
+	_, ok, cookies := serve(t, loader, map[string]string{"Remote-User": username})
+	require.True(t, ok)
+	require.NotEmpty(t, cookies)
+	cookie, _, _ := strings.Cut(cookies[0], ";")
+	return cookie
+}
+
+func TestLoadIdentity_ReusesSession(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	calls := 0
+	loader, err := NewLoader(testConfig("0.0.0.0/0", "::/0"), testStore(t, sessionRepo), resolverFunc(func(context.Context, string, string) (string, bool, error) {
+		calls++
+		return "user-1", false, nil
+	}), noAudit)
+	require.NoError(t, err)
+	cookie := sessionCookie(t, loader, "ada")
+
+	sessionRepo.On("Touch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domainrepo.SessionRecord{
+		UserID:      "user-1",
+		Issuer:      "forward-auth",
+		Sub:         "ada",
+		Username:    "ada",
+		DisplayName: "ada",
+		Groups:      []string{"dash_user"},
+		ExpiresAt:   time.Now().Add(23 * time.Hour),
+	}, nil)
+
+	identity, ok, _ := serve(t, loader, map[string]string{"Remote-User": "ada", "Cookie": cookie})
+
+	require.True(t, ok)
+	require.Equal(t, "user-1", identity.UserID)
+	require.Equal(t, 1, calls, "the resolver must only run when a new session is started")
+	sessionRepo.AssertNotCalled(t, "RefreshBySessionID", mock.Anything, mock.Anything)
+	sessionRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestLoadIdentity_SwitchedUserEndsOldSession(t *testing.T) {
+	sessionRepo := &repoMock.SessionRepository{}
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	loader, err := NewLoader(testConfig("0.0.0.0/0", "::/0"), testStore(t, sessionRepo), resolverFunc(func(_ context.Context, _, sub string) (string, bool, error) {
+		return "user-" + sub, false, nil
+	}), noAudit)
+	require.NoError(t, err)
+	cookie := sessionCookie(t, loader, "ada")
+
+	sessionRepo.On("Touch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&domainrepo.SessionRecord{
+		UserID:    "user-ada",
+		Issuer:    "forward-auth",
+		Sub:       "ada",
+		ExpiresAt: time.Now().Add(23 * time.Hour),
+	}, nil)
+	sessionRepo.On("DeleteBySessionID", mock.Anything, mock.Anything).Return(nil)
+
+	identity, ok, _ := serve(t, loader, map[string]string{"Remote-User": "bob", "Cookie": cookie})
+
+	require.True(t, ok)
+	require.Equal(t, "user-bob", identity.UserID)
+	sessionRepo.AssertCalled(t, "DeleteBySessionID", mock.Anything, mock.Anything)
+	sessionRepo.AssertNumberOfCalls(t, "Create", 2)
+}
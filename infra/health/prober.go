@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+)
+
+// Prober checks application URLs with a plain GET request. Redirects are not
+// followed, so a login redirect in front of an application counts as a
+// response of its own.
+// This type implements the command.HTTPProber interface.
+type Prober struct {
+	client *http.Client
+}
+
+// NewProber returns a Prober. With insecureSkipVerify set, self-signed
+// certificates common in home labs are accepted.
+func NewProber(insecureSkipVerify bool) *Prober {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &Prober{client: &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (p *Prober) Probe(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Dash health check")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber_Probe_ReturnsStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	status, err := NewProber(false).Probe(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTeapot, status)
+}
+
+func TestProber_Probe_DoesNotFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	status, err := NewProber(false).Probe(context.Background(), srv.URL)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusFound, status)
+}
+
+func TestProber_Probe_HonoursContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewProber(false).Probe(ctx, srv.URL)
+
+	require.Error(t, err)
+}
+
+func TestProber_Probe_SelfSignedCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, err := NewProber(false).Probe(context.Background(), srv.URL)
+	require.Error(t, err)
+
+	status, err := NewProber(true).Probe(context.Background(), srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, status)
+}
@@ -0,0 +1,208 @@
+package localauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"github.com/samber/lo"
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown username or a wrong password.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrThrottled is returned while sign-in is blocked after too many failures.
+	ErrThrottled = errors.New("too many failed sign-in attempts")
+)
+
+const (
+	maxFailures   = 5
+	failureWindow = 15 * time.Minute
+	// sweepEvery is the number of recorded failures after which entries of
+	// all clients are checked, so keys that are never tried again do not
+	// pile up.
+	sweepEvery = 100
+)
+
+// Authenticator checks local account passwords and maps accounts to
+// identities. Failed attempts are counted per client IP and username; after
+// maxFailures within failureWindow further attempts are rejected until the
+// window has passed.
+type Authenticator struct {
+	cfg  config.LocalAuthConfig
+	repo domainrepo.LocalAccountRepository
+
+	// dummyHash is compared against for unknown usernames, so the response
+	// time does not reveal which usernames exist.
+	dummyHash string
+
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	recorded int // failures since the last sweep
+}
+
+func NewAuthenticator(cfg config.LocalAuthConfig, repo domainrepo.LocalAccountRepository) (*Authenticator, error) {
+	dummyHash, err := model.HashPassword("dash-local-auth-dummy")
+	if err != nil {
+		return nil, err
+	}
+	return &Authenticator{
+		cfg:       cfg,
+		repo:      repo,
+		dummyHash: dummyHash,
+		failures:  map[string][]time.Time{},
+	}, nil
+}
+
+// Sync replaces the managed accounts with the ones from the users file and
+// creates the bootstrap admin account if it does not exist yet. Run it once
+// at startup.
+func (a *Authenticator) Sync(ctx context.Context) error {
+	var managed []domainrepo.LocalAccountRecord
+	if a.cfg.UsersFile != "" {
+		records, err := LoadUsersFile(a.cfg.UsersFile)
+		if err != nil {
+			return err
+		}
+		managed = records
+	}
+	if err := a.repo.ReplaceManaged(ctx, managed); err != nil {
+		return fmt.Errorf("local auth: sync users file: %w", err)
+	}
+
+	if a.cfg.AdminPassword == "" {
+		return nil
+	}
+	username := model.NormalizeUsername(a.cfg.AdminUsername)
+	_, err := a.repo.GetByUsername(ctx, username)
+	if err == nil {
+		return nil
+	}
+	var nfe *domainerrors.NotFoundError
+	if !errors.As(err, &nfe) {
+		return fmt.Errorf("local auth: bootstrap admin: %w", err)
+	}
+
+	hash := a.cfg.AdminPassword
+	if !model.IsPasswordHash(hash) {
+		if hash, err = model.HashPassword(a.cfg.AdminPassword); err != nil {
+			return fmt.Errorf("local auth: bootstrap admin: %w", err)
+		}
+	}
+	var groups []string
+	if len(a.cfg.AdminGroups) > 0 && a.cfg.AdminGroups[0] != "*" {
+		groups = []string{a.cfg.AdminGroups[0]}
+	}
+	if err := a.repo.Create(ctx, &domainrepo.LocalAccountRecord{
+		Username:     username,
+		PasswordHash: hash,
+		Groups:       groups,
+	}); err != nil {
+		return fmt.Errorf("local auth: bootstrap admin: %w", err)
+	}
+	return nil
+}
+
+// Authenticate verifies the password of a local account and returns its
+// identity. UserID is left empty for the caller to resolve.
+func (a *Authenticator) Authenticate(ctx context.Context, ip, username, password string) (model.Identity, error) {
+	username = model.NormalizeUsername(username)
+	key := ip + "\x00" + username
+	if a.throttled(key) {
+		return model.Identity{}, ErrThrottled
+	}
+
+	record, err := a.repo.GetByUsername(ctx, username)
+	if err != nil {
+		var nfe *domainerrors.NotFoundError
+		if !errors.As(err, &nfe) {
+			return model.Identity{}, err
+		}
+		model.CheckPassword(a.dummyHash, password)
+		a.fail(key)
+		return model.Identity{}, ErrInvalidCredentials
+	}
+	if !model.CheckPassword(record.PasswordHash, password) {
+		a.fail(key)
+		return model.Identity{}, ErrInvalidCredentials
+	}
+
+	a.reset(key)
+	return a.toIdentity(record), nil
+}
+
+// Identity returns the current identity of a local account without checking
+// a password. Used to refresh the identity of an existing session.
+func (a *Authenticator) Identity(ctx context.Context, username string) (model.Identity, error) {
+	record, err := a.repo.GetByUsername(ctx, model.NormalizeUsername(username))
+	if err != nil {
+		return model.Identity{}, err
+	}
+	return a.toIdentity(record), nil
+}
+
+func (a *Authenticator) toIdentity(r *domainrepo.LocalAccountRecord) model.Identity {
+	displayName := r.DisplayName
+	if displayName == "" {
+		displayName = r.Username
+	}
+	firstName, lastName, _ := strings.Cut(displayName, " ")
+
+	isAdmin := lo.SomeBy(a.cfg.AdminGroups, func(g string) bool {
+		return g == "*" || lo.Contains(r.Groups, g)
+	})
+
+	return model.Identity{
+		FirstName:   firstName,
+		LastName:    lastName,
+		DisplayName: displayName,
+		Username:    r.Username,
+		Email:       r.Email,
+		Groups:      r.Groups,
+		IsAdmin:     isAdmin,
+	}.WithSyntheticGroups()
+}
+
+func (a *Authenticator) throttled(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.recentFailures(key)) >= maxFailures
+}
+
+func (a *Authenticator) fail(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failures[key] = append(a.recentFailures(key), time.Now())
+	if a.recorded++; a.recorded >= sweepEvery {
+		a.recorded = 0
+		for k := range a.failures {
+			a.recentFailures(k)
+		}
+	}
+}
+
+func (a *Authenticator) reset(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.failures, key)
+}
+
+// recentFailures drops failures older than failureWindow. Callers hold mu.
+func (a *Authenticator) recentFailures(key string) []time.Time {
+	cutoff := time.Now().Add(-failureWindow)
+	recent := lo.Filter(a.failures[key], func(t time.Time, _ int) bool { return t.After(cutoff) })
+	if len(recent) == 0 {
+		delete(a.failures, key)
+		return nil
+	}
+	a.failures[key] = recent
+	return recent
+}
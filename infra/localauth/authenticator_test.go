@@ -0,0 +1,154 @@
+package localauth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+)
+
+func testConfig() config.LocalAuthConfig {
+	return config.LocalAuthConfig{
+		Enabled:       true,
+		Issuer:        "local",
+		AdminGroups:   []string{"admin"},
+		AdminUsername: "admin",
+	}
+}
+
+func newAuthenticator(t *testing.T, cfg config.LocalAuthConfig, repo domainrepo.LocalAccountRepository) *Authenticator {
+	t.Helper()
+	a, err := NewAuthenticator(cfg, repo)
+	require.NoError(t, err)
+	return a
+}
+
+func TestAuthenticate(t *testing.T) {
+	hash, err := model.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{
+		Username:     "ada",
+		PasswordHash: hash,
+		DisplayName:  "Ada Lovelace",
+		Email:        "ada@example.com",
+		Groups:       []string{"admin"},
+	}, nil)
+	repo.On("GetByUsername", mock.Anything, "bob").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalAccount))
+
+	a := newAuthenticator(t, testConfig(), repo)
+
+	identity, err := a.Authenticate(context.Background(), "10.0.0.1", " Ada ", "correct horse")
+	require.NoError(t, err)
+	require.Equal(t, "ada", identity.Username)
+	require.Equal(t, "Ada", identity.FirstName)
+	require.Equal(t, "Lovelace", identity.LastName)
+	require.True(t, identity.IsAdmin)
+	require.Contains(t, identity.Groups, "dash_admin")
+
+	_, err = a.Authenticate(context.Background(), "10.0.0.1", "ada", "battery staple")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = a.Authenticate(context.Background(), "10.0.0.1", "bob", "correct horse")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthenticate_Throttled(t *testing.T) {
+	hash, err := model.HashPassword("correct horse")
+	require.NoError(t, err)
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("GetByUsername", mock.Anything, "ada").Return(&domainrepo.LocalAccountRecord{Username: "ada", PasswordHash: hash}, nil)
+
+	a := newAuthenticator(t, testConfig(), repo)
+	for range maxFailures {
+		_, err := a.Authenticate(context.Background(), "10.0.0.1", "ada", "wrong")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+
+	_, err = a.Authenticate(context.Background(), "10.0.0.1", "ada", "correct horse")
+	require.ErrorIs(t, err, ErrThrottled)
+
+	// Other clients are not affected.
+	_, err = a.Authenticate(context.Background(), "10.0.0.2", "ada", "correct horse")
+	require.NoError(t, err)
+}
+
+func TestSync_BootstrapsAdmin(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminPassword = "changeme123"
+
+	var created *domainrepo.LocalAccountRecord
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("ReplaceManaged", mock.Anything, []domainrepo.LocalAccountRecord(nil)).Return(nil)
+	repo.On("GetByUsername", mock.Anything, "admin").Return(nil, domainerrors.NotFound(domainerrors.EntityLocalAccount))
+	repo.On("Create", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*domainrepo.LocalAccountRecord) }).
+		Return(nil)
+
+	require.NoError(t, newAuthenticator(t, cfg, repo).Sync(context.Background()))
+	require.Equal(t, "admin", created.Username)
+	require.Equal(t, []string{"admin"}, created.Groups)
+	require.True(t, model.CheckPassword(created.PasswordHash, "changeme123"))
+}
+
+func TestSync_KeepsExistingAdmin(t *testing.T) {
+	cfg := testConfig()
+	cfg.AdminPassword = "changeme123"
+
+	repo := &repoMock.LocalAccountRepository{}
+	repo.On("ReplaceManaged", mock.Anything, mock.Anything).Return(nil)
+	repo.On("GetByUsername", mock.Anything, "admin").Return(&domainrepo.LocalAccountRecord{Username: "admin"}, nil)
+
+	require.NoError(t, newAuthenticator(t, cfg, repo).Sync(context.Background()))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestParseUsersFile(t *testing.T) {
+	hash, err := model.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	records, err := parseUsersFile([]byte(`
+users:
+  - username: Ada
+    password_hash: "` + hash + `"
+    display_name: Ada Lovelace
+    groups: [admin, family]
+`))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "ada", records[0].Username)
+	require.Equal(t, []string{"admin", "family"}, records[0].Groups)
+	require.True(t, records[0].Managed)
+
+	_, err = parseUsersFile([]byte("users:\n  - username: ada\n    password_hash: plain\n"))
+	require.Error(t, err)
+
+	_, err = parseUsersFile([]byte("users:\n  - username: ada\n    password_hash: \"" + hash + "\"\n  - username: ADA\n    password_hash: \"" + hash + "\"\n"))
+	require.Error(t, err)
+}
+
+func TestFail_SweepsExpiredFailures(t *testing.T) {
+	a := newAuthenticator(t, testConfig(), &repoMock.LocalAccountRepository{})
+	expired := time.Now().Add(-2 * failureWindow)
+	for i := range 1000 {
+		a.failures[fmt.Sprintf("10.0.%d.%d\x00ada", i/256, i%256)] = []time.Time{expired}
+	}
+
+	for i := range sweepEvery - 1 {
+		a.fail(fmt.Sprintf("10.1.0.1\x00user%d", i))
+	}
+	require.Len(t, a.failures, 1000+sweepEvery-1)
+
+	a.fail("10.1.0.1\x00ada")
+	require.Len(t, a.failures, sweepEvery)
+}
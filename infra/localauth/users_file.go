@@ -0,0 +1,71 @@
+package localauth
+
+import (
+	"fmt"
+	"os"
+
+	"git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// usersFile is the YAML layout of LOCAL_AUTH_USERS_FILE:
+//
+//	users:
+//	  - username: ada
+//	    password_hash: $2y$10$…   # bcrypt, e.g. from htpasswd -nbB
+//	    display_name: Ada Lovelace
+//	    email: ada@example.com
+//	    groups: [admin, family]
+type usersFile struct {
+	Users []struct {
+		Username     string   `yaml:"username"`
+		PasswordHash string   `yaml:"password_hash"`
+		DisplayName  string   `yaml:"display_name"`
+		Email        string   `yaml:"email"`
+		Groups       []string `yaml:"groups"`
+	} `yaml:"users"`
+}
+
+// LoadUsersFile reads the accounts defined in a users file. Passwords must be
+// bcrypt hashes so the file never holds plain text passwords.
+func LoadUsersFile(path string) ([]domainrepo.LocalAccountRecord, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("local auth: read users file: %w", err)
+	}
+	return parseUsersFile(raw)
+}
+
+func parseUsersFile(raw []byte) ([]domainrepo.LocalAccountRecord, error) {
+	var file usersFile
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("local auth: parse users file: %w", err)
+	}
+
+	seen := map[string]bool{}
+	records := make([]domainrepo.LocalAccountRecord, 0, len(file.Users))
+	for i, u := range file.Users {
+		username := model.NormalizeUsername(u.Username)
+		if username == "" {
+			return nil, fmt.Errorf("local auth: users file: user %d has no username", i+1)
+		}
+		if seen[username] {
+			return nil, fmt.Errorf("local auth: users file: duplicate username %q", username)
+		}
+		seen[username] = true
+		if !model.IsPasswordHash(u.PasswordHash) {
+			return nil, fmt.Errorf("local auth: users file: password_hash of %q is not a bcrypt hash", username)
+		}
+		records = append(records, domainrepo.LocalAccountRecord{
+			Username:     username,
+			PasswordHash: u.PasswordHash,
+			DisplayName:  u.DisplayName,
+			Email:        u.Email,
+			Groups:       u.Groups,
+			Managed:      true,
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,78 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Claims holds the raw claims of an ID token, merged with the userinfo
+// response when the provider is configured to fetch it.
+type Claims map[string]any
+
+// lookupClaim resolves a claim path in claims. Dots descend into nested
+// objects ("realm_access.roles"); a key that itself contains dots, such as a
+// namespaced claim "https://example.com/groups", is matched as a whole first.
+func lookupClaim(claims map[string]any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	if v, ok := claims[path]; ok {
+		return v, true
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] != '.' {
+			continue
+		}
+		nested, ok := claims[path[:i]].(map[string]any)
+		if !ok {
+			continue
+		}
+		if v, ok := lookupClaim(nested, path[i+1:]); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// claimString returns the claim at path as a string. Non-string scalars are
+// formatted; missing claims and objects yield "".
+func claimString(claims map[string]any, path string) string {
+	v, ok := lookupClaim(claims, path)
+	if !ok || v == nil {
+		return ""
+	}
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64, bool:
+		return fmt.Sprint(t)
+	default:
+		return ""
+	}
+}
+
+// claimStrings returns the claim at path as a list of strings. A single
+// string is treated as a one-element list; non-string entries are skipped.
+func claimStrings(claims map[string]any, path string) []string {
+	v, ok := lookupClaim(claims, path)
+	if !ok || v == nil {
+		return nil
+	}
+	switch t := v.(type) {
+	case string:
+		if t = strings.TrimSpace(t); t != "" {
+			return []string{t}
+		}
+		return nil
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := e.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package oidc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+)
+
+func mustClaims(t *testing.T, raw string) Claims {
+	t.Helper()
+	var c Claims
+	if err := json.Unmarshal([]byte(raw), &c); err != nil {
+		t.Fatalf("invalid claims JSON: %v", err)
+	}
+	return c
+}
+
+func TestLookupClaim(t *testing.T) {
+	claims := mustClaims(t, `{
+		"groups": ["a"],
+		"realm_access": {"roles": ["admin", "user"]},
+		"resource_access": {"dash": {"roles": ["editor"]}},
+		"https://example.com/groups": ["namespaced"],
+		"age": 42
+	}`)
+
+	tests := []struct {
+		path string
+		want any
+		ok   bool
+	}{
+		{"groups", []any{"a"}, true},
+		{"realm_access.roles", []any{"admin", "user"}, true},
+		{"resource_access.dash.roles", []any{"editor"}, true},
+		{"https://example.com/groups", []any{"namespaced"}, true},
+		{"realm_access.missing", nil, false},
+		{"age.value", nil, false},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := lookupClaim(claims, tt.path)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookupClaim(%q) = %v, %v; want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	claims := mustClaims(t, `{"list": ["a", 1, "", "b"], "single": "admins", "obj": {"x": 1}}`)
+
+	if got := claimStrings(claims, "list"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("claimStrings(list) = %v, want [a b]", got)
+	}
+	if got := claimStrings(claims, "single"); !reflect.DeepEqual(got, []string{"admins"}) {
+		t.Errorf("claimStrings(single) = %v, want [admins]", got)
+	}
+	if got := claimStrings(claims, "obj"); got != nil {
+		t.Errorf("claimStrings(obj) = %v, want nil", got)
+	}
+}
+
+func TestClaimString(t *testing.T) {
+	claims := mustClaims(t, `{"name": "Ada", "id": 7, "obj": {}}`)
+
+	if got := claimString(claims, "name"); got != "Ada" {
+		t.Errorf("claimString(name) = %q, want %q", got, "Ada")
+	}
+	if got := claimString(claims, "id"); got != "7" {
+		t.Errorf("claimString(id) = %q, want %q", got, "7")
+	}
+	if got := claimString(claims, "obj"); got != "" {
+		t.Errorf("claimString(obj) = %q, want empty", got)
+	}
+}
+
+func TestMergeClaims_KeepsTokenIdentity(t *testing.T) {
+	dst := Claims{"sub": "a", "iss": "https://id.example.com", "email": "old@example.com"}
+	mergeClaims(dst, Claims{"sub": "b", "iss": "https://evil.example.com", "email": "new@example.com", "groups": []any{"x"}})
+
+	if dst["sub"] != "a" || dst["iss"] != "https://id.example.com" {
+		t.Errorf("mergeClaims() overwrote token claims: %v", dst)
+	}
+	if dst["email"] != "new@example.com" {
+		t.Errorf("mergeClaims() email = %v, want userinfo value", dst["email"])
+	}
+	if _, ok := dst["groups"]; !ok {
+		t.Error("mergeClaims() must add claims only present in userinfo")
+	}
+}
+
+func TestClaimsToIdentity_KeycloakMapping(t *testing.T) {
+	p := &Provider{
+		adminGroups: []string{"admin", "dash-admin"},
+		claims: config.OIDCClaimsConfig{
+			Groups:     "realm_access.roles",
+			Username:   "preferred_username",
+			Name:       "name",
+			GivenName:  "given_name",
+			FamilyName: "family_name",
+			Email:      "email",
+			Picture:    "picture",
+		},
+	}
+	claims := mustClaims(t, `{
+		"sub": "kc-123",
+		"preferred_username": "ada",
+		"given_name": "Ada",
+		"family_name": "Lovelace",
+		"realm_access": {"roles": ["offline_access", "dash-admin"]}
+	}`)
+
+	identity := p.ClaimsToIdentity(claims)
+
+	if !identity.IsAdmin {
+		t.Error("ClaimsToIdentity() IsAdmin = false, want true for any configured admin group")
+	}
+	if identity.Username != "ada" || identity.DisplayName != "Ada Lovelace" {
+		t.Errorf("ClaimsToIdentity() = %+v", identity)
+	}
+}
+
+func TestClaimsToIdentity_CustomClaimNames(t *testing.T) {
+	p := &Provider{
+		adminGroups: []string{"admin"},
+		claims: config.OIDCClaimsConfig{
+			Groups:   "ak_groups",
+			Username: "nickname",
+			Email:    "mail",
+			Picture:  "avatar",
+		},
+	}
+	claims := mustClaims(t, `{"sub": "s", "nickname": "bob", "mail": "bob@example.com", "avatar": "https://img/bob.png", "ak_groups": ["users"]}`)
+
+	identity := p.ClaimsToIdentity(claims)
+
+	if identity.IsAdmin {
+		t.Error("ClaimsToIdentity() IsAdmin = true, want false")
+	}
+	if identity.Username != "bob" || identity.Email != "bob@example.com" {
+		t.Errorf("ClaimsToIdentity() = %+v", identity)
+	}
+	if identity.Picture == nil || *identity.Picture != "https://img/bob.png" {
+		t.Errorf("ClaimsToIdentity() Picture = %v", identity.Picture)
+	}
+}
+
+func TestClaimsToIdentity_WildcardAdmin(t *testing.T) {
+	p := &Provider{adminGroups: []string{"*"}, claims: config.OIDCClaimsConfig{Groups: "groups"}}
+
+	if identity := p.ClaimsToIdentity(Claims{"sub": "s"}); !identity.IsAdmin {
+		t.Error("ClaimsToIdentity() IsAdmin = false, want true for wildcard admin group")
+	}
+}
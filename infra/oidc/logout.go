@@ -0,0 +1,108 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// backChannelLogoutEvent is the event a logout token must carry
+// (OIDC Back-Channel Logout 1.0, section 2.4).
+const backChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// logoutTokenMaxAge limits how old a logout token without exp claim may be.
+const logoutTokenMaxAge = 5 * time.Minute
+
+// ErrUnknownIssuer is returned for logout tokens of an issuer that is not configured.
+var ErrUnknownIssuer = errors.New("unknown issuer")
+
+// LogoutToken is the content of a verified back-channel logout token. At
+// least one of Subject and SessionID is set.
+type LogoutToken struct {
+	Issuer    string
+	Subject   string
+	SessionID string // IdP session (sid claim)
+}
+
+type logoutTokenClaims struct {
+	Sid    string                     `json:"sid"`
+	Events map[string]json.RawMessage `json:"events"`
+	Nonce  *string                    `json:"nonce"`
+}
+
+// VerifyLogoutToken verifies a back-channel logout token against the
+// provider's JWKS and checks the claims required by the specification.
+func (p *Provider) VerifyLogoutToken(ctx context.Context, rawToken string) (*LogoutToken, error) {
+	token, err := p.logoutVerifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("logout_token verification: %w", err)
+	}
+	// exp is optional in earlier drafts of the specification, so the
+	// verifier skips it and the age is checked here instead.
+	now := time.Now()
+	if token.Expiry.IsZero() {
+		if now.Sub(token.IssuedAt) > logoutTokenMaxAge {
+			return nil, fmt.Errorf("logout_token expired")
+		}
+	} else if now.After(token.Expiry) {
+		return nil, fmt.Errorf("logout_token expired")
+	}
+
+	var claims logoutTokenClaims
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("extracting logout_token claims: %w", err)
+	}
+	if _, ok := claims.Events[backChannelLogoutEvent]; !ok {
+		return nil, fmt.Errorf("logout_token has no back-channel logout event")
+	}
+	// A nonce marks an ID token, which must not be accepted as logout token.
+	if claims.Nonce != nil {
+		return nil, fmt.Errorf("logout_token must not contain a nonce")
+	}
+	if token.Subject == "" && claims.Sid == "" {
+		return nil, fmt.Errorf("logout_token has neither sub nor sid")
+	}
+
+	return &LogoutToken{Issuer: p.issuer, Subject: token.Subject, SessionID: claims.Sid}, nil
+}
+
+// VerifyLogoutToken verifies a back-channel logout token with the provider
+// named by its iss claim. The claim is read before verification only to pick
+// the provider whose keys the token is then checked against.
+func (p *Providers) VerifyLogoutToken(ctx context.Context, rawToken string) (*LogoutToken, error) {
+	issuer, err := unverifiedIssuer(rawToken)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := p.ByIssuer(issuer)
+	if !ok {
+		return nil, ErrUnknownIssuer
+	}
+	return provider.VerifyLogoutToken(ctx, rawToken)
+}
+
+// unverifiedIssuer reads the iss claim of a JWT without checking its signature.
+func unverifiedIssuer(rawToken string) (string, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed jwt payload: %w", err)
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("malformed jwt payload: %w", err)
+	}
+	if claims.Issuer == "" {
+		return "", fmt.Errorf("jwt has no iss claim")
+	}
+	return claims.Issuer, nil
+}
@@ -0,0 +1,135 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+const testIssuer = "https://id.example.com"
+
+// newLogoutTestProvider returns a provider that trusts key for logout tokens.
+func newLogoutTestProvider(t *testing.T, key *rsa.PrivateKey) *Provider {
+	t.Helper()
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{key.Public()}}
+	return &Provider{
+		id:             "default",
+		issuer:         testIssuer,
+		logoutVerifier: oidc.NewVerifier(testIssuer, keySet, &oidc.Config{ClientID: "dash", SkipExpiryCheck: true}),
+	}
+}
+
+// signJWT builds an RS256 signed JWT with the given claims.
+func signJWT(t *testing.T, key *rsa.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "logout+jwt"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func logoutClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":    testIssuer,
+		"aud":    "dash",
+		"iat":    now.Unix(),
+		"exp":    now.Add(time.Minute).Unix(),
+		"jti":    "jti-1",
+		"sub":    "sub-1",
+		"sid":    "sid-1",
+		"events": map[string]any{backChannelLogoutEvent: map[string]any{}},
+	}
+}
+
+func TestVerifyLogoutToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	providers := NewProvidersFrom(newLogoutTestProvider(t, key))
+
+	t.Run("valid", func(t *testing.T) {
+		got, err := providers.VerifyLogoutToken(context.Background(), signJWT(t, key, logoutClaims()))
+		if err != nil {
+			t.Fatalf("VerifyLogoutToken() error = %v", err)
+		}
+		want := LogoutToken{Issuer: testIssuer, Subject: "sub-1", SessionID: "sid-1"}
+		if *got != want {
+			t.Errorf("VerifyLogoutToken() = %+v, want %+v", *got, want)
+		}
+	})
+
+	t.Run("recent token without exp", func(t *testing.T) {
+		claims := logoutClaims()
+		delete(claims, "exp")
+		if _, err := providers.VerifyLogoutToken(context.Background(), signJWT(t, key, claims)); err != nil {
+			t.Errorf("VerifyLogoutToken() error = %v", err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		modify func(map[string]any)
+	}{
+		{"foreign key", otherKey, func(map[string]any) {}},
+		{"wrong audience", key, func(c map[string]any) { c["aud"] = "other" }},
+		{"expired", key, func(c map[string]any) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{"old token without exp", key, func(c map[string]any) {
+			delete(c, "exp")
+			c["iat"] = time.Now().Add(-time.Hour).Unix()
+		}},
+		{"missing event", key, func(c map[string]any) { delete(c, "events") }},
+		{"nonce", key, func(c map[string]any) { c["nonce"] = "n" }},
+		{"neither sub nor sid", key, func(c map[string]any) {
+			delete(c, "sub")
+			delete(c, "sid")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := logoutClaims()
+			tt.modify(claims)
+			if _, err := providers.VerifyLogoutToken(context.Background(), signJWT(t, tt.key, claims)); err == nil {
+				t.Error("VerifyLogoutToken() must reject the token")
+			}
+		})
+	}
+
+	t.Run("unknown issuer", func(t *testing.T) {
+		claims := logoutClaims()
+		claims["iss"] = "https://other.example.com"
+		_, err := providers.VerifyLogoutToken(context.Background(), signJWT(t, key, claims))
+		if !errors.Is(err, ErrUnknownIssuer) {
+			t.Errorf("VerifyLogoutToken() error = %v, want ErrUnknownIssuer", err)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := providers.VerifyLogoutToken(context.Background(), "not-a-jwt"); err == nil {
+			t.Error("VerifyLogoutToken() must reject a malformed token")
+		}
+	})
+}
@@ -32,4 +32,6 @@ type StateCookie struct {
 	CodeVerifier     string `json:"cv"`
 	ReturnTo         string `json:"rt"`
 	RefreshSessionID string `json:"rsid,omitempty"` // set during group-refresh flow; preserves existing session
+	ProviderID       string `json:"pid,omitempty"`  // provider the flow was started with; empty = primary provider
+	LinkUserID       string `json:"luid,omitempty"` // set during account linking; the identity is linked to this user
 }
@@ -17,13 +17,18 @@ import (
 // Provider wraps the OIDC provider and OAuth2 configuration.
 // It handles discovery, token exchange, and claim extraction.
 type Provider struct {
-	issuer        string
-	oidcProvider  *oidc.Provider
-	verifier      *oidc.IDTokenVerifier
-	oauth2Config  oauth2.Config
-	adminGroup    string
-	profileUrl    string
-	endSessionURL string
+	id             string
+	name           string
+	issuer         string
+	oidcProvider   *oidc.Provider
+	verifier       *oidc.IDTokenVerifier
+	logoutVerifier *oidc.IDTokenVerifier
+	oauth2Config   oauth2.Config
+	adminGroups    []string
+	profileUrl     string
+	endSessionURL  string
+	claims         config.OIDCClaimsConfig
+	userInfo       bool
 }
 
 type providerClaims struct {
@@ -31,7 +36,7 @@ type providerClaims struct {
 }
 
 // NewProvider initialises the OIDC provider via discovery (/.well-known/openid-configuration).
-func NewProvider(ctx context.Context, cfg *config.OIDCConfig) (*Provider, error) {
+func NewProvider(ctx context.Context, cfg *config.OIDCProviderConfig) (*Provider, error) {
 	oidcProvider, err := oidc.NewProvider(ctx, cfg.Issuer)
 	if err != nil {
 		return nil, fmt.Errorf("OIDC discovery failed for %s: %w", cfg.Issuer, err)
@@ -47,8 +52,12 @@ func NewProvider(ctx context.Context, cfg *config.OIDCConfig) (*Provider, error)
 	}
 
 	scopes := strings.Fields(cfg.Scopes)
+	if cfg.OfflineAccess && !lo.Contains(scopes, oidc.ScopeOfflineAccess) {
+		scopes = append(scopes, oidc.ScopeOfflineAccess)
+	}
 
 	verifier := oidcProvider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
+	logoutVerifier := oidcProvider.Verifier(&oidc.Config{ClientID: cfg.ClientID, SkipExpiryCheck: true})
 
 	oauth2Config := oauth2.Config{
 		ClientID:     cfg.ClientID,
@@ -58,44 +67,128 @@ func NewProvider(ctx context.Context, cfg *config.OIDCConfig) (*Provider, error)
 		Scopes:       scopes,
 	}
 
+	name := cfg.Name
+	if name == "" {
+		name = displayNameFromIssuer(cfg.Issuer)
+	}
+
 	return &Provider{
-		issuer:        cfg.Issuer,
-		oidcProvider:  oidcProvider,
-		verifier:      verifier,
-		oauth2Config:  oauth2Config,
-		adminGroup:    cfg.AdminGroup,
-		profileUrl:    cfg.ProfileURL,
-		endSessionURL: endSessionURL,
+		id:             cfg.ID,
+		name:           name,
+		issuer:         cfg.Issuer,
+		oidcProvider:   oidcProvider,
+		verifier:       verifier,
+		logoutVerifier: logoutVerifier,
+		oauth2Config:   oauth2Config,
+		adminGroups:    cfg.AllAdminGroups(),
+		profileUrl:     cfg.ProfileURL,
+		endSessionURL:  endSessionURL,
+		claims:         cfg.Claims,
+		userInfo:       cfg.UserInfo,
 	}, nil
 }
 
+// ID returns the configured provider ID used in login and link URLs.
+func (p *Provider) ID() string { return p.id }
+
+// Name returns the human-readable provider name shown on the login chooser.
+func (p *Provider) Name() string { return p.name }
+
 // Issuer returns the OIDC issuer URL this provider was configured with.
 func (p *Provider) Issuer() string { return p.issuer }
 
+// displayNameFromIssuer falls back to the issuer host when no name is configured.
+func displayNameFromIssuer(issuer string) string {
+	if u, err := url.Parse(issuer); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return issuer
+}
+
 // BeginAuth returns the authorization URL with PKCE S256 challenge and state.
 func (p *Provider) BeginAuth(state, codeVerifier string) string {
 	return p.oauth2Config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier))
 }
 
+// AuthResult is the outcome of a successful authorization code exchange.
+type AuthResult struct {
+	IDToken    *oidc.IDToken
+	RawIDToken string // needed for logout (id_token_hint)
+	Sid        string // IdP session ID for back-/front-channel logout; empty if not sent
+	// RefreshToken is only issued when offline access is configured and
+	// granted by the IdP; empty otherwise.
+	RefreshToken string
+	Claims       Claims
+}
+
 // Exchange performs the authorization code exchange, verifies the ID token against JWKS,
-// and returns the verified IDToken and the raw ID token string (needed for logout).
-func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*oidc.IDToken, string, error) {
+// and collects the claims used for the identity. With userinfo enabled, the
+// userinfo response is merged over the ID token claims.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*AuthResult, error) {
 	token, err := p.oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
 	if err != nil {
-		return nil, "", fmt.Errorf("token exchange: %w", err)
+		return nil, fmt.Errorf("token exchange: %w", err)
 	}
 
 	rawIDToken, ok := token.Extra("id_token").(string)
 	if !ok {
-		return nil, "", fmt.Errorf("no id_token in token response")
+		return nil, fmt.Errorf("no id_token in token response")
 	}
 
 	idToken, err := p.verifier.Verify(ctx, rawIDToken)
 	if err != nil {
-		return nil, "", fmt.Errorf("id_token verification: %w", err)
+		return nil, fmt.Errorf("id_token verification: %w", err)
+	}
+
+	claims := Claims{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("extracting claims: %w", err)
+	}
+
+	if p.userInfo {
+		if err := p.mergeUserInfo(ctx, token, idToken.Subject, claims); err != nil {
+			return nil, err
+		}
 	}
 
-	return idToken, rawIDToken, nil
+	return &AuthResult{
+		IDToken:      idToken,
+		RawIDToken:   rawIDToken,
+		Sid:          claimString(claims, "sid"),
+		RefreshToken: token.RefreshToken,
+		Claims:       claims,
+	}, nil
+}
+
+// mergeUserInfo fetches the userinfo response and merges it over claims.
+func (p *Provider) mergeUserInfo(ctx context.Context, token *oauth2.Token, subject string, claims Claims) error {
+	userInfo, err := p.oidcProvider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+	if err != nil {
+		return fmt.Errorf("userinfo: %w", err)
+	}
+	// The userinfo sub must match the ID token, otherwise the response
+	// belongs to someone else (OIDC Core 5.3.2).
+	if userInfo.Subject != subject {
+		return fmt.Errorf("userinfo: subject mismatch")
+	}
+	var extra Claims
+	if err := userInfo.Claims(&extra); err != nil {
+		return fmt.Errorf("userinfo claims: %w", err)
+	}
+	mergeClaims(claims, extra)
+	return nil
+}
+
+// mergeClaims copies the userinfo claims over the ID token claims. Claims that
+// identify the token itself are kept from the verified ID token.
+func mergeClaims(dst, src Claims) {
+	for k, v := range src {
+		switch k {
+		case "sub", "iss", "aud", "exp", "iat", "nonce", "sid":
+			continue
+		}
+		dst[k] = v
+	}
 }
 
 // EndSessionURL builds the OIDC end_session_endpoint URL for logout.
@@ -119,7 +212,8 @@ func (p *Provider) EndSessionURL(idTokenHint, postLogoutRedirectURI string) stri
 	return u.String()
 }
 
-// idTokenClaims holds the standard OIDC claims we care about.
+// idTokenClaims holds the identity claims after applying the provider's
+// claim mapping.
 type idTokenClaims struct {
 	Sub               string   `json:"sub"`
 	Name              string   `json:"name"`
@@ -129,17 +223,26 @@ type idTokenClaims struct {
 	Email             string   `json:"email"`
 	Picture           string   `json:"picture"`
 	Groups            []string `json:"groups"`
-	Exp               int64    `json:"exp"`
 }
 
-// ClaimsToIdentity extracts standard OIDC claims from the verified ID token
-// and maps them to the domain Identity value object.
-func (p *Provider) ClaimsToIdentity(idToken *oidc.IDToken) (model.Identity, error) {
-	var claims idTokenClaims
-	if err := idToken.Claims(&claims); err != nil {
-		return model.Identity{}, fmt.Errorf("extracting claims: %w", err)
+// ClaimsToIdentity maps the claims collected by Exchange to the domain
+// Identity value object using the configured claim paths.
+func (p *Provider) ClaimsToIdentity(claims Claims) model.Identity {
+	return p.claimsToIdentity(p.mapClaims(claims))
+}
+
+// mapClaims reads the identity claims from the configured claim paths.
+func (p *Provider) mapClaims(claims Claims) idTokenClaims {
+	return idTokenClaims{
+		Sub:               claimString(claims, "sub"),
+		Name:              claimString(claims, p.claims.Name),
+		GivenName:         claimString(claims, p.claims.GivenName),
+		FamilyName:        claimString(claims, p.claims.FamilyName),
+		PreferredUsername: claimString(claims, p.claims.Username),
+		Email:             claimString(claims, p.claims.Email),
+		Picture:           claimString(claims, p.claims.Picture),
+		Groups:            claimStrings(claims, p.claims.Groups),
 	}
-	return p.claimsToIdentity(claims), nil
 }
 
 // LegacyUserID returns the UserID that was assigned to this user before the
@@ -206,7 +309,9 @@ func (p *Provider) claimsToIdentity(claims idTokenClaims) model.Identity {
 		displayName = claims.Sub
 	}
 
-	isAdmin := p.adminGroup == "*" || lo.Contains(claims.Groups, p.adminGroup)
+	isAdmin := lo.SomeBy(p.adminGroups, func(g string) bool {
+		return g == "*" || lo.Contains(claims.Groups, g)
+	})
 
 	var picture *string
 	if claims.Picture != "" {
@@ -0,0 +1,63 @@
+package oidc
+
+import (
+	"context"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+)
+
+// Providers holds all configured identity providers. The first provider is
+// the primary one and is used whenever no provider is specified.
+type Providers struct {
+	list []*Provider
+}
+
+// NewProviders initialises every configured provider via discovery.
+func NewProviders(ctx context.Context, cfg *config.OIDCConfig) (*Providers, error) {
+	configs := cfg.AllProviders()
+	list := make([]*Provider, 0, len(configs))
+	for i := range configs {
+		p, err := NewProvider(ctx, &configs[i])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return NewProvidersFrom(list...), nil
+}
+
+// NewProvidersFrom builds a registry from already initialised providers.
+func NewProvidersFrom(providers ...*Provider) *Providers {
+	return &Providers{list: providers}
+}
+
+// Default returns the primary provider.
+func (p *Providers) Default() *Provider {
+	if len(p.list) == 0 {
+		return nil
+	}
+	return p.list[0]
+}
+
+// List returns all providers in configuration order.
+func (p *Providers) List() []*Provider { return p.list }
+
+// Get returns the provider with the given ID.
+func (p *Providers) Get(id string) (*Provider, bool) {
+	for _, provider := range p.list {
+		if provider.id == id {
+			return provider, true
+		}
+	}
+	return nil, false
+}
+
+// ByIssuer returns the provider configured for the given issuer URL.
+func (p *Providers) ByIssuer(issuer string) (*Provider, bool) {
+	for _, provider := range p.list {
+		if provider.issuer == issuer {
+			return provider, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,31 @@
+package oidc
+
+import "testing"
+
+func TestProviders_Lookup(t *testing.T) {
+	family := &Provider{id: "default", name: "Family", issuer: "https://id.example.com"}
+	work := &Provider{id: "work", name: "Work", issuer: "https://kc.example.com/realms/work"}
+	providers := NewProvidersFrom(family, work)
+
+	if got := providers.Default(); got != family {
+		t.Errorf("Default() = %v, want family provider", got)
+	}
+	if got, ok := providers.Get("work"); !ok || got != work {
+		t.Errorf("Get(work) = %v, %v", got, ok)
+	}
+	if _, ok := providers.Get("unknown"); ok {
+		t.Error("Get(unknown) must not find a provider")
+	}
+	if got, ok := providers.ByIssuer("https://kc.example.com/realms/work"); !ok || got != work {
+		t.Errorf("ByIssuer() = %v, %v", got, ok)
+	}
+}
+
+func TestDisplayNameFromIssuer(t *testing.T) {
+	if got := displayNameFromIssuer("https://id.example.com/realms/x"); got != "id.example.com" {
+		t.Errorf("displayNameFromIssuer() = %q, want %q", got, "id.example.com")
+	}
+	if got := displayNameFromIssuer("not a url"); got != "not a url" {
+		t.Errorf("displayNameFromIssuer() = %q, want input unchanged", got)
+	}
+}
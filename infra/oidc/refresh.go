@@ -0,0 +1,186 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshLeeway is how long before its token expires a session is refreshed.
+const refreshLeeway = 5 * time.Minute
+
+// defaultTokenLifetime is assumed when a refresh response states no expiry.
+const defaultTokenLifetime = time.Hour
+
+// ErrRefreshRejected is returned when the IdP no longer accepts a refresh
+// token, e.g. because the user signed out or the offline session expired.
+var ErrRefreshRejected = errors.New("refresh token rejected")
+
+// RefreshResult is the outcome of a successful refresh token grant.
+type RefreshResult struct {
+	RawIDToken   string // empty when the IdP issued no new ID token
+	RefreshToken string // the rotated refresh token, or the old one if none was issued
+	Claims       Claims
+	IssuedAt     time.Time
+	Expiry       time.Time
+}
+
+// Refresh redeems a refresh token and collects the current claims of the
+// subject it was issued for.
+func (p *Provider) Refresh(ctx context.Context, refreshToken, subject string) (*RefreshResult, error) {
+	token, err := p.oauth2Config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+			return nil, ErrRefreshRejected
+		}
+		return nil, fmt.Errorf("token refresh: %w", err)
+	}
+
+	result := &RefreshResult{
+		RefreshToken: token.RefreshToken,
+		Claims:       Claims{},
+		IssuedAt:     time.Now(),
+		Expiry:       token.Expiry,
+	}
+	if result.Expiry.IsZero() {
+		result.Expiry = result.IssuedAt.Add(defaultTokenLifetime)
+	}
+
+	// The ID token is optional in a refresh response (OIDC Core 12.2).
+	// Without one, the identity is read from the userinfo endpoint.
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		if err := p.mergeUserInfo(ctx, token, subject, result.Claims); err != nil {
+			return nil, err
+		}
+		result.Claims["sub"] = subject
+		return result, nil
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token verification: %w", err)
+	}
+	if idToken.Subject != subject {
+		return nil, fmt.Errorf("id_token: subject mismatch")
+	}
+	if err := idToken.Claims(&result.Claims); err != nil {
+		return nil, fmt.Errorf("extracting claims: %w", err)
+	}
+	if p.userInfo {
+		if err := p.mergeUserInfo(ctx, token, subject, result.Claims); err != nil {
+			return nil, err
+		}
+	}
+
+	result.RawIDToken = rawIDToken
+	result.IssuedAt = idToken.IssuedAt
+	result.Expiry = idToken.Expiry
+	return result, nil
+}
+
+// Refresher renews sessions with their refresh token shortly before the
+// token expires, so identity and group changes at the IdP reach Dash without
+// an interactive sign-in.
+type Refresher struct {
+	providers *Providers
+	store     *SessionStore
+}
+
+func NewRefresher(providers *Providers, store *SessionStore) *Refresher {
+	return &Refresher{providers: providers, store: store}
+}
+
+// RefreshDue refreshes every session whose token expires within refreshLeeway.
+// Sessions whose refresh token is rejected are marked stale; other failures
+// are retried on the next run.
+func (r *Refresher) RefreshDue(ctx context.Context) error {
+	repo := r.store.SessionRepo()
+	if repo == nil {
+		return nil
+	}
+	records, err := repo.ListRefreshable(ctx, time.Now().Add(refreshLeeway))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, record := range records {
+		err := r.refresh(ctx, repo, record)
+		if errors.Is(err, ErrRefreshRejected) {
+			err = repo.MarkStale(ctx, record.SessionID)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("session %s: %w", record.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Refresher) refresh(ctx context.Context, repo domainrepo.SessionRepository, record *domainrepo.SessionRecord) error {
+	// A provider that was removed from the configuration or a refresh token
+	// encrypted with an old cookie key cannot be used any more.
+	provider, ok := r.providers.ByIssuer(record.Issuer)
+	if !ok {
+		return ErrRefreshRejected
+	}
+	refreshToken, err := r.store.DecryptDBToken(record.RefreshToken)
+	if err != nil {
+		return ErrRefreshRejected
+	}
+
+	result, err := provider.Refresh(ctx, refreshToken, record.Sub)
+	if err != nil {
+		return err
+	}
+
+	rawIDToken := record.RawIDToken
+	if result.RawIDToken != "" {
+		if rawIDToken, err = r.store.EncryptDBToken(result.RawIDToken); err != nil {
+			return err
+		}
+	}
+	encRefreshToken, err := r.store.EncryptDBToken(result.RefreshToken)
+	if err != nil {
+		return err
+	}
+	sid := record.Sid
+	if s := claimString(result.Claims, "sid"); s != "" {
+		sid = s
+	}
+
+	identity := provider.ClaimsToIdentity(result.Claims)
+	return repo.RefreshBySessionID(ctx, &domainrepo.SessionRecord{
+		SessionID:    record.SessionID,
+		Issuer:       record.Issuer,
+		Sub:          record.Sub,
+		Sid:          sid,
+		Username:     identity.Username,
+		Email:        identity.Email,
+		FirstName:    identity.FirstName,
+		LastName:     identity.LastName,
+		DisplayName:  identity.DisplayName,
+		Picture:      ptrStr(identity.Picture),
+		ProfileUrl:   ptrStr(identity.ProfileUrl),
+		RawIDToken:   rawIDToken,
+		RefreshToken: encRefreshToken,
+		Groups:       identity.Groups,
+		IsAdmin:      identity.IsAdmin,
+		IssuedAt:     result.IssuedAt,
+		ExpiresAt:    result.Expiry,
+	})
+}
+
+// ptrStr dereferences a *string, returning "" for nil.
+func ptrStr(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
@@ -0,0 +1,234 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"git.at.oechsler.it/samuel/dash/v2/config"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	repoMock "git.at.oechsler.it/samuel/dash/v2/internal/mock"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/oauth2"
+)
+
+// newRefreshTestProvider returns a provider whose token endpoint answers with
+// respond and that trusts key for ID tokens.
+func newRefreshTestProvider(t *testing.T, key *rsa.PrivateKey, respond func(w http.ResponseWriter, r *http.Request)) *Provider {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(respond))
+	t.Cleanup(server.Close)
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{key.Public()}}
+	return &Provider{
+		id:       "default",
+		issuer:   testIssuer,
+		verifier: oidc.NewVerifier(testIssuer, keySet, &oidc.Config{ClientID: "dash"}),
+		oauth2Config: oauth2.Config{
+			ClientID:     "dash",
+			ClientSecret: "secret",
+			Endpoint:     oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams},
+		},
+		adminGroups: []string{"admin"},
+		claims: config.OIDCClaimsConfig{
+			Groups:   "groups",
+			Username: "preferred_username",
+			Email:    "email",
+		},
+	}
+}
+
+func refreshedIDTokenClaims(sub string) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":                testIssuer,
+		"aud":                "dash",
+		"sub":                sub,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+		"preferred_username": "sam",
+		"groups":             []string{"admin"},
+	}
+}
+
+func tokenResponse(t *testing.T, body map[string]any) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "refresh_token" {
+			t.Errorf("unexpected token request: %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func TestProviderRefresh(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("new id token", func(t *testing.T) {
+		provider := newRefreshTestProvider(t, key, tokenResponse(t, map[string]any{
+			"access_token":  "at",
+			"token_type":    "Bearer",
+			"expires_in":    300,
+			"refresh_token": "rt-2",
+			"id_token":      signJWT(t, key, refreshedIDTokenClaims("sub-1")),
+		}))
+
+		got, err := provider.Refresh(context.Background(), "rt-1", "sub-1")
+		if err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+		if got.RefreshToken != "rt-2" || got.RawIDToken == "" {
+			t.Errorf("Refresh() = %+v, want rotated refresh token and id token", got)
+		}
+		if time.Until(got.Expiry) < 50*time.Minute {
+			t.Errorf("Refresh() Expiry = %v, want the id token expiry", got.Expiry)
+		}
+		if identity := provider.ClaimsToIdentity(got.Claims); !identity.IsAdmin || identity.Username != "sam" {
+			t.Errorf("ClaimsToIdentity() = %+v", identity)
+		}
+	})
+
+	t.Run("refresh token kept when not rotated", func(t *testing.T) {
+		provider := newRefreshTestProvider(t, key, tokenResponse(t, map[string]any{
+			"access_token": "at",
+			"token_type":   "Bearer",
+			"id_token":     signJWT(t, key, refreshedIDTokenClaims("sub-1")),
+		}))
+
+		got, err := provider.Refresh(context.Background(), "rt-1", "sub-1")
+		if err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+		if got.RefreshToken != "rt-1" {
+			t.Errorf("Refresh() RefreshToken = %q, want rt-1", got.RefreshToken)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		provider := newRefreshTestProvider(t, key, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		})
+
+		if _, err := provider.Refresh(context.Background(), "rt-1", "sub-1"); !errors.Is(err, ErrRefreshRejected) {
+			t.Errorf("Refresh() error = %v, want ErrRefreshRejected", err)
+		}
+	})
+
+	t.Run("server error is not a rejection", func(t *testing.T) {
+		provider := newRefreshTestProvider(t, key, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := provider.Refresh(context.Background(), "rt-1", "sub-1")
+		if err == nil || errors.Is(err, ErrRefreshRejected) {
+			t.Errorf("Refresh() error = %v, want a transient error", err)
+		}
+	})
+
+	t.Run("subject mismatch", func(t *testing.T) {
+		provider := newRefreshTestProvider(t, key, tokenResponse(t, map[string]any{
+			"access_token": "at",
+			"token_type":   "Bearer",
+			"id_token":     signJWT(t, key, refreshedIDTokenClaims("someone-else")),
+		}))
+
+		if _, err := provider.Refresh(context.Background(), "rt-1", "sub-1"); err == nil {
+			t.Error("Refresh() must reject an id token of another subject")
+		}
+	})
+}
+
+func newTestSessionStore(t *testing.T, repo domainrepo.SessionRepository) *SessionStore {
+	t.Helper()
+	store, err := NewSessionStore(&config.OIDCCookieConfig{
+		HashKey:  strings.Repeat("ab", 64),
+		BlockKey: strings.Repeat("cd", 32),
+		Name:     "dash-session",
+	}, repo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestRefresherRefreshDue(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("updates the session", func(t *testing.T) {
+		repo := &repoMock.SessionRepository{}
+		store := newTestSessionStore(t, repo)
+		provider := newRefreshTestProvider(t, key, tokenResponse(t, map[string]any{
+			"access_token":  "at",
+			"token_type":    "Bearer",
+			"refresh_token": "rt-2",
+			"id_token":      signJWT(t, key, refreshedIDTokenClaims("sub-1")),
+		}))
+		encRefreshToken, err := store.EncryptDBToken("rt-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo.On("ListRefreshable", mock.Anything, mock.Anything).Return([]*domainrepo.SessionRecord{{
+			ID:           "record-1",
+			SessionID:    "session-1",
+			Issuer:       testIssuer,
+			Sub:          "sub-1",
+			Sid:          "sid-1",
+			RefreshToken: encRefreshToken,
+		}}, nil)
+		repo.On("RefreshBySessionID", mock.Anything, mock.MatchedBy(func(r *domainrepo.SessionRecord) bool {
+			refreshToken, err := store.DecryptDBToken(r.RefreshToken)
+			return r.SessionID == "session-1" && r.Sid == "sid-1" && r.IsAdmin &&
+				err == nil && refreshToken == "rt-2" && r.ExpiresAt.After(time.Now())
+		})).Return(nil)
+
+		if err := NewRefresher(NewProvidersFrom(provider), store).RefreshDue(context.Background()); err != nil {
+			t.Fatalf("RefreshDue() error = %v", err)
+		}
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("marks rejected sessions stale", func(t *testing.T) {
+		repo := &repoMock.SessionRepository{}
+		store := newTestSessionStore(t, repo)
+		provider := newRefreshTestProvider(t, key, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		})
+		encRefreshToken, err := store.EncryptDBToken("rt-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repo.On("ListRefreshable", mock.Anything, mock.Anything).Return([]*domainrepo.SessionRecord{
+			{ID: "record-1", SessionID: "session-1", Issuer: testIssuer, Sub: "sub-1", RefreshToken: encRefreshToken},
+			{ID: "record-2", SessionID: "session-2", Issuer: "https://removed.example.com", Sub: "sub-1", RefreshToken: encRefreshToken},
+		}, nil)
+		repo.On("MarkStale", mock.Anything, "session-1").Return(nil)
+		repo.On("MarkStale", mock.Anything, "session-2").Return(nil)
+
+		if err := NewRefresher(NewProvidersFrom(provider), store).RefreshDue(context.Background()); err != nil {
+			t.Fatalf("RefreshDue() error = %v", err)
+		}
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "RefreshBySessionID", mock.Anything, mock.Anything)
+	})
+}
@@ -17,6 +17,17 @@ import (
 
 const stateCookieName = "dash-oidc-state"
 
+// sessionLocalKey holds the session written or cleared earlier in the same
+// request, so identity loaders running later see it instead of the stale
+// request cookie.
+const sessionLocalKey = "session_data"
+
+// LocalGroupsResolver adds the Dash-managed groups of a user to the identity.
+// query.ResolveLocalGroups implements this interface.
+type LocalGroupsResolver interface {
+	Handle(ctx context.Context, identity model.Identity) (model.Identity, error)
+}
+
 // SessionData is the minimal payload stored in the encrypted session cookie.
 //   - SessionID      — used for server-side session revocation and DB lookup.
 //   - CookieIssuedAt — unix timestamp of the last time the cookie was written;
@@ -36,12 +47,14 @@ type SessionStore struct {
 	secure      bool
 	maxAge      int
 	sessionRepo domainrepo.SessionRepository // optional; enables session fallback and revocation
+	localGroups LocalGroupsResolver          // optional; merges local groups into loaded identities
 }
 
 // NewSessionStore creates a SessionStore from cookie configuration.
 // HashKey (64 bytes hex) and BlockKey (32 bytes hex) are required.
 // sessionRepo is optional; pass nil to disable DB-backed session features.
-func NewSessionStore(cfg *config.OIDCCookieConfig, sessionRepo domainrepo.SessionRepository) (*SessionStore, error) {
+// localGroups is optional; pass nil to load identities with their IdP groups only.
+func NewSessionStore(cfg *config.OIDCCookieConfig, sessionRepo domainrepo.SessionRepository, localGroups LocalGroupsResolver) (*SessionStore, error) {
 	hashKey, err := hex.DecodeString(cfg.HashKey)
 	if err != nil {
 		return nil, fmt.Errorf("OIDC_COOKIE_HASH_KEY is not valid hex: %w", err)
@@ -65,6 +78,7 @@ func NewSessionStore(cfg *config.OIDCCookieConfig, sessionRepo domainrepo.Sessio
 		secure:      cfg.Secure,
 		maxAge:      cfg.MaxAge,
 		sessionRepo: sessionRepo,
+		localGroups: localGroups,
 	}, nil
 }
 
@@ -111,6 +125,9 @@ func (s *SessionStore) LoadExpired(c fiber.Ctx) (SessionData, bool) {
 }
 
 func (s *SessionStore) loadRaw(c fiber.Ctx) (SessionData, bool) {
+	if data, ok := c.Locals(sessionLocalKey).(SessionData); ok {
+		return data, data.SessionID != ""
+	}
 	encoded := c.Cookies(s.cookieName)
 	if encoded == "" {
 		return SessionData{}, false
@@ -150,8 +167,25 @@ func (s *SessionStore) LoadIdentity(c fiber.Ctx) (model.Identity, bool) {
 		c.Locals("session_pinned", true)
 		_ = s.PersistCookie(c)
 	}
+	if record.Stale {
+		c.Locals("session_stale", true)
+	}
 
-	return recordToIdentity(record), true
+	return s.WithLocalGroups(recordToIdentity(record)), true
+}
+
+// WithLocalGroups merges the Dash-managed groups into the identity. Groups
+// only widen what a user can see, so on errors the identity is returned with
+// its IdP groups rather than denied.
+func (s *SessionStore) WithLocalGroups(identity model.Identity) model.Identity {
+	if s.localGroups == nil {
+		return identity
+	}
+	resolved, err := s.localGroups.Handle(context.Background(), identity)
+	if err != nil {
+		return identity
+	}
+	return resolved
 }
 
 // recordToIdentity maps a SessionRecord's stored identity fields to a domain Identity.
@@ -223,6 +257,7 @@ func (s *SessionStore) RevertCookie(c fiber.Ctx) {
 
 // Clear instructs the browser to delete the session cookie.
 func (s *SessionStore) Clear(c fiber.Ctx) {
+	c.Locals(sessionLocalKey, SessionData{})
 	c.Cookie(&fiber.Cookie{
 		Name:     s.cookieName,
 		Value:    "",
@@ -264,6 +299,7 @@ func (s *SessionStore) writeCookie(c fiber.Ctx, data SessionData, maxAge int) er
 	if err != nil {
 		return fmt.Errorf("encoding session: %w", err)
 	}
+	c.Locals(sessionLocalKey, data)
 	c.Cookie(&fiber.Cookie{
 		Name:     s.cookieName,
 		Value:    encoded,
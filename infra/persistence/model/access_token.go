@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// AccessToken is the GORM model for the access_tokens table.
+// Only the SHA-256 of the token secret is stored.
+type AccessToken struct {
+	ID         string `gorm:"primaryKey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     string `gorm:"not null;index"`
+	User       User   `gorm:"constraint:fk_access_tokens_user,OnDelete:CASCADE"`
+	Name       string `gorm:"not null"`
+	TokenHash  string `gorm:"not null;uniqueIndex"`
+	Scope      string `gorm:"not null"`
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	LastIP     string
+	// Identity snapshot; the defaults let AutoMigrate add the columns to
+	// tables that already hold tokens.
+	Username    string `gorm:"not null;default:''"`
+	Email       string `gorm:"not null;default:''"`
+	FirstName   string
+	LastName    string
+	DisplayName string
+	Picture     string
+	ProfileUrl  string
+	Groups      string `gorm:"type:text"` // JSON-encoded []string
+	IsAdmin     bool
+}
+
+func (AccessToken) TableName() string { return "access_tokens" }
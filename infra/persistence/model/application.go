@@ -1,5 +1,6 @@
 package model
 
+import "time"
 
 type Application struct {
 	Base
@@ -8,8 +9,28 @@ type Application struct {
 	DisplayName     string   `gorm:"not null"`
 	Url             string   `gorm:"not null"`
 	VisibleToGroups []string `gorm:"serializer:json;not null;default:'[]'"`
+	Position        int      `gorm:"not null;default:0;index"`
+
+	HealthCheck          bool   `gorm:"not null;default:false"`
+	HealthURL            string `gorm:"not null;default:''"`
+	HealthExpectedStatus string `gorm:"not null;default:''"`
 }
 
 func (a *Application) TableName() string {
 	return "applications"
 }
+
+// ApplicationHealth holds the latest probe result of an application. It lives
+// in its own table so that saving an application never overwrites it.
+type ApplicationHealth struct {
+	ApplicationID uint        `gorm:"primaryKey"`
+	Application   Application `gorm:"constraint:fk_application_health_application,OnDelete:CASCADE"`
+	State         string      `gorm:"not null"`
+	LatencyMs     int64       `gorm:"not null;default:0"`
+	CheckedAt     time.Time   `gorm:"not null"`
+	Error         string      `gorm:"not null;default:''"`
+}
+
+func (h *ApplicationHealth) TableName() string {
+	return "application_health"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// AuditLog is the GORM model for the audit_logs table. ActorID has no foreign
+// key to users: entries must survive the deletion of the account they
+// describe.
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null;index"`
+	ActorID   string    `gorm:"not null;default:'';index"`
+	Action    string    `gorm:"not null;index"`
+	Target    string    `gorm:"not null;default:''"`
+	IP        string    `gorm:"not null;default:''"`
+}
+
+func (AuditLog) TableName() string { return "audit_logs" }
@@ -8,6 +8,8 @@ type Bookmark struct {
 	Icon        string   `gorm:"not null"`
 	DisplayName string   `gorm:"not null"`
 	Url         string   `gorm:"not null"`
+	Tags        []string `gorm:"serializer:json;not null;default:'[]'"`
+	Position    int      `gorm:"not null;default:0;index"`
 }
 
 func (b *Bookmark) TableName() string {
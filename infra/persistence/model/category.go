@@ -5,8 +5,13 @@ type Category struct {
 	Base
 	DashboardID uint      `gorm:"not null;index"`
 	Dashboard   Dashboard `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// ParentID is NULL for top-level categories.
+	ParentID    *uint     `gorm:"index"`
+	Parent      *Category `gorm:"constraint:fk_categories_parent,OnDelete:CASCADE"`
 	DisplayName string    `gorm:"not null:"`
 	IsShelved   bool      `gorm:"not null;default:false"`
+	TagQuery    string    `gorm:"not null;default:''"`
+	Position    int       `gorm:"not null;default:0;index"`
 }
 
 func (c *Category) TableName() string {
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// CategoryShare grants a user a role on another user's category. Rows are
+// removed together with the category or the user.
+type CategoryShare struct {
+	CategoryID uint     `gorm:"primaryKey"`
+	Category   Category `gorm:"constraint:fk_category_shares_category,OnDelete:CASCADE"`
+	UserID     string   `gorm:"primaryKey;index"`
+	User       User     `gorm:"constraint:fk_category_shares_user,OnDelete:CASCADE"`
+	Role       string   `gorm:"not null"`
+	CreatedAt  time.Time
+}
+
+func (CategoryShare) TableName() string { return "category_shares" }
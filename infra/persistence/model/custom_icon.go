@@ -0,0 +1,15 @@
+package model
+
+// CustomIcon is an image of the icon library. Icons without UserID are shared.
+type CustomIcon struct {
+	Base
+	UserID      *string `gorm:"index"`
+	User        *User   `gorm:"constraint:fk_custom_icons_user,OnDelete:CASCADE"`
+	DisplayName string  `gorm:"not null"`
+	ContentType string  `gorm:"not null"`
+	Data        []byte  `gorm:"not null"`
+}
+
+func (i *CustomIcon) TableName() string {
+	return "custom_icons"
+}
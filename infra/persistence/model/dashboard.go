@@ -3,8 +3,11 @@ package model
 
 type Dashboard struct {
 	Base
-	UserID string `gorm:"uniqueIndex"`
-	User   User   `gorm:"constraint:fk_dashboards_user,OnDelete:CASCADE"`
+	UserID    string `gorm:"index"`
+	User      User   `gorm:"constraint:fk_dashboards_user,OnDelete:CASCADE"`
+	Name      string `gorm:"not null;default:'Dashboard'"`
+	Position  int    `gorm:"not null;default:0"`
+	IsDefault bool   `gorm:"not null;default:false"`
 }
 
 func (d *Dashboard) TableName() string {
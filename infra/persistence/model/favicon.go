@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Favicon caches the icon of a site. It is not tied to a user.
+type Favicon struct {
+	Site        string    `gorm:"primaryKey"`
+	ContentType string    `gorm:"not null;default:''"`
+	Data        []byte    `gorm:"not null"`
+	FetchedAt   time.Time `gorm:"not null;index"`
+}
+
+func (f *Favicon) TableName() string {
+	return "favicons"
+}
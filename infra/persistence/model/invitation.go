@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Invitation is the GORM model for the invitations table. Only the SHA-256
+// of the invitation secret is stored. CreatedBy has no foreign key, so
+// invitations outlive the admin who created them.
+type Invitation struct {
+	ID            string `gorm:"primaryKey"`
+	CreatedAt     time.Time
+	TokenHash     string `gorm:"not null;uniqueIndex"`
+	Groups        string `gorm:"type:text"` // JSON-encoded []string
+	MaxUses       int    `gorm:"not null"`
+	Uses          int    `gorm:"not null;default:0"`
+	ExpiresAt     time.Time
+	GrantDuration time.Duration `gorm:"not null"`
+	CreatedBy     string        `gorm:"not null;default:''"`
+}
+
+func (Invitation) TableName() string { return "invitations" }
+
+// InvitationRedemption is the GORM model for the invitation_redemptions
+// table.
+type InvitationRedemption struct {
+	ID           uint `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	InvitationID string     `gorm:"not null;index"`
+	Invitation   Invitation `gorm:"constraint:fk_invitation_redemptions_invitation,OnDelete:CASCADE"`
+	UserID       string     `gorm:"not null;index"`
+	User         User       `gorm:"constraint:fk_invitation_redemptions_user,OnDelete:CASCADE"`
+	Groups       string     `gorm:"type:text"` // JSON-encoded []string
+	ExpiresAt    time.Time  `gorm:"index"`
+}
+
+func (InvitationRedemption) TableName() string { return "invitation_redemptions" }
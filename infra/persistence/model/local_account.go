@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// LocalAccount is the GORM model for the local_accounts table. Accounts are
+// not tied to a users row: the internal UserID is resolved through the
+// idp_links table on first sign-in, like for any other identity.
+type LocalAccount struct {
+	Username     string `gorm:"primaryKey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	PasswordHash string `gorm:"not null"`
+	DisplayName  string
+	Email        string
+	Groups       string `gorm:"type:text"` // JSON-encoded []string
+	Managed      bool   `gorm:"not null;default:false"`
+}
+
+func (LocalAccount) TableName() string { return "local_accounts" }
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// LocalGroup is the GORM model for the local_groups table.
+type LocalGroup struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null;uniqueIndex"`
+	IdpGroups string `gorm:"type:text"` // JSON-encoded []string
+	CreatedAt time.Time
+}
+
+func (LocalGroup) TableName() string { return "local_groups" }
+
+// LocalGroupMember assigns a user to a local group. Rows are removed together
+// with the group or the user.
+type LocalGroupMember struct {
+	GroupID uint       `gorm:"primaryKey"`
+	Group   LocalGroup `gorm:"constraint:fk_local_group_members_group,OnDelete:CASCADE"`
+	UserID  string     `gorm:"primaryKey;index"`
+	User    User       `gorm:"constraint:fk_local_group_members_user,OnDelete:CASCADE"`
+}
+
+func (LocalGroupMember) TableName() string { return "local_group_members" }
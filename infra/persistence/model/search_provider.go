@@ -0,0 +1,14 @@
+package model
+
+type SearchProvider struct {
+	Base
+	UserID      string `gorm:"not null;uniqueIndex:idx_search_providers_user_bang"`
+	User        User   `gorm:"constraint:fk_search_providers_user,OnDelete:CASCADE"`
+	DisplayName string `gorm:"not null"`
+	Bang        string `gorm:"not null;uniqueIndex:idx_search_providers_user_bang"`
+	URLTemplate string `gorm:"not null"`
+}
+
+func (p *SearchProvider) TableName() string {
+	return "search_providers"
+}
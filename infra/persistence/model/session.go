@@ -16,7 +16,9 @@ type Session struct {
 	LastAccessedAt time.Time
 	LastIP         string
 	UserAgent      string
+	Issuer      string
 	Sub         string `gorm:"not null"`
+	Sid         string `gorm:"index"`
 	Username    string `gorm:"not null"`
 	Email       string `gorm:"not null"`
 	FirstName   string
@@ -25,6 +27,8 @@ type Session struct {
 	Picture     string
 	ProfileUrl  string
 	RawIDToken  string    `gorm:"type:text"`
+	RefreshToken string   `gorm:"type:text"`
+	Stale       bool
 	Groups      string    `gorm:"type:text"` // JSON-encoded []string
 	IsAdmin     bool
 }
@@ -7,6 +7,9 @@ type Setting struct {
 	ThemeID  *uint  `gorm:"index"`
 	Language string `gorm:"not null;default:'auto'"`
 	Timezone string `gorm:"not null;default:'auto'"`
+	// SearchProviderID is NULL when no default search provider is chosen. A
+	// deleted provider leaves a dangling id that reads as "none".
+	SearchProviderID *uint
 }
 
 func (s *Setting) TableName() string {
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// SharedCategory publishes a category to groups. It lives in its own table so
+// that saving a category never overwrites it.
+type SharedCategory struct {
+	CategoryID      uint     `gorm:"primaryKey"`
+	Category        Category `gorm:"constraint:fk_shared_categories_category,OnDelete:CASCADE"`
+	VisibleToGroups []string `gorm:"serializer:json;not null;default:'[]'"`
+	PublishedBy     string   `gorm:"not null;index"`
+	CreatedAt       time.Time
+}
+
+func (SharedCategory) TableName() string { return "shared_categories" }
+
+// SharedCategoryHide records that a user hid a published category. Rows are
+// removed together with the publication or the user.
+type SharedCategoryHide struct {
+	CategoryID     uint           `gorm:"primaryKey"`
+	SharedCategory SharedCategory `gorm:"foreignKey:CategoryID;references:CategoryID;constraint:fk_shared_category_hides_category,OnDelete:CASCADE"`
+	UserID         string         `gorm:"primaryKey;index"`
+	User           User           `gorm:"constraint:fk_shared_category_hides_user,OnDelete:CASCADE"`
+}
+
+func (SharedCategoryHide) TableName() string { return "shared_category_hides" }
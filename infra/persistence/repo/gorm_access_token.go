@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.AccessTokenRepository = (*GormAccessTokenRepo)(nil)
+
+type GormAccessTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewGormAccessTokenRepo(db *gorm.DB) (*GormAccessTokenRepo, error) {
+	if err := db.AutoMigrate(&model.AccessToken{}); err != nil {
+		return nil, err
+	}
+	return &GormAccessTokenRepo{db: db}, nil
+}
+
+func (r *GormAccessTokenRepo) Create(ctx context.Context, record *domainrepo.AccessTokenRecord) error {
+	m := &model.AccessToken{
+		ID:          record.ID,
+		UserID:      record.UserID,
+		Name:        record.Name,
+		TokenHash:   record.TokenHash,
+		Scope:       record.Scope,
+		ExpiresAt:   record.ExpiresAt,
+		Username:    record.Username,
+		Email:       record.Email,
+		FirstName:   record.FirstName,
+		LastName:    record.LastName,
+		DisplayName: record.DisplayName,
+		Picture:     record.Picture,
+		ProfileUrl:  record.ProfileUrl,
+		Groups:      encodeGroups(record.Groups),
+		IsAdmin:     record.IsAdmin,
+	}
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+func (r *GormAccessTokenRepo) ListByUserID(ctx context.Context, userID string) ([]*domainrepo.AccessTokenRecord, error) {
+	var ms []model.AccessToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*domainrepo.AccessTokenRecord, 0, len(ms))
+	for i := range ms {
+		records = append(records, toAccessTokenRecord(&ms[i]))
+	}
+	return records, nil
+}
+
+// Touch records the usage and returns the token if it exists and has not
+// expired. A zero expires_at means the token never expires.
+func (r *GormAccessTokenRepo) Touch(ctx context.Context, tokenHash string, lastIP string) (*domainrepo.AccessTokenRecord, error) {
+	now := time.Now()
+	var m model.AccessToken
+	tx := r.db.WithContext(ctx).Raw(`
+		UPDATE access_tokens
+		SET last_used_at = ?,
+		    last_ip      = ?,
+		    updated_at   = ?
+		WHERE token_hash = ?
+		  AND (expires_at = ? OR expires_at > NOW())
+		RETURNING *`,
+		now, lastIP, now, tokenHash, time.Time{},
+	).Scan(&m)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil, nil // token not found or expired
+	}
+	return toAccessTokenRecord(&m), nil
+}
+
+func (r *GormAccessTokenRepo) UpdateIdentity(ctx context.Context, record *domainrepo.AccessTokenRecord) error {
+	return r.db.WithContext(ctx).Model(&model.AccessToken{}).
+		Where("user_id = ?", record.UserID).
+		Updates(map[string]any{
+			"username":     record.Username,
+			"email":        record.Email,
+			"first_name":   record.FirstName,
+			"last_name":    record.LastName,
+			"display_name": record.DisplayName,
+			"picture":      record.Picture,
+			"profile_url":  record.ProfileUrl,
+			"groups":       encodeGroups(record.Groups),
+			"is_admin":     record.IsAdmin,
+		}).Error
+}
+
+func (r *GormAccessTokenRepo) DeleteByID(ctx context.Context, id string, userID string) error {
+	return r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.AccessToken{}).Error
+}
+
+func (r *GormAccessTokenRepo) DeleteByUserID(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.AccessToken{}).Error
+}
+
+func toAccessTokenRecord(m *model.AccessToken) *domainrepo.AccessTokenRecord {
+	return &domainrepo.AccessTokenRecord{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Name:        m.Name,
+		TokenHash:   m.TokenHash,
+		Scope:       m.Scope,
+		CreatedAt:   m.CreatedAt,
+		ExpiresAt:   m.ExpiresAt,
+		LastUsedAt:  m.LastUsedAt,
+		LastIP:      m.LastIP,
+		Username:    m.Username,
+		Email:       m.Email,
+		FirstName:   m.FirstName,
+		LastName:    m.LastName,
+		DisplayName: m.DisplayName,
+		Picture:     m.Picture,
+		ProfileUrl:  m.ProfileUrl,
+		Groups:      decodeGroups(m.Groups),
+		IsAdmin:     m.IsAdmin,
+	}
+}
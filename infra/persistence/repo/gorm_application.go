@@ -18,7 +18,10 @@ type GormApplicationRepo struct {
 }
 
 func NewGormApplicationRepo(db *gorm.DB) (*GormApplicationRepo, error) {
-	if err := db.AutoMigrate(&model.Application{}); err != nil {
+	if err := migratePositions(db, "applications", ""); err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&model.Application{}, &model.ApplicationHealth{}); err != nil {
 		return nil, err
 	}
 	// ON DELETE SET NULL: when the creator's account is deleted, the application
@@ -47,11 +50,37 @@ func (r *GormApplicationRepo) Upsert(ctx context.Context, record *domainrepo.App
 		DisplayName:     record.DisplayName,
 		Url:             record.Url,
 		VisibleToGroups: record.VisibleToGroups,
+
+		HealthCheck:          record.HealthCheck,
+		HealthURL:            record.HealthURL,
+		HealthExpectedStatus: record.HealthExpectedStatus,
 	}
-	if record.ID != 0 {
-		m.ID = record.ID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if record.ID != 0 {
+			var existing model.Application
+			err := tx.Select("id", "position").First(&existing, record.ID).Error
+			if err == nil {
+				m.ID = existing.ID
+				m.Position = existing.Position
+				return tx.Save(m).Error
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			m.ID = record.ID
+		}
+		next, err := nextPosition(tx, "applications", "", 0)
+		if err != nil {
+			return err
+		}
+		m.Position = next
+		return tx.Save(m).Error
+	})
+	if err != nil {
+		return err
 	}
-	return r.db.WithContext(ctx).Save(m).Error
+	record.Position = m.Position
+	return nil
 }
 
 func (r *GormApplicationRepo) Get(ctx context.Context, id uint) (*domainrepo.ApplicationRecord, error) {
@@ -62,30 +91,35 @@ func (r *GormApplicationRepo) Get(ctx context.Context, id uint) (*domainrepo.App
 		}
 		return nil, err
 	}
-	return &domainrepo.ApplicationRecord{
-		ID:              app.ID,
-		CreatedBy:       app.CreatedBy,
-		Icon:            app.Icon,
-		DisplayName:     app.DisplayName,
-		Url:             app.Url,
-		VisibleToGroups: app.VisibleToGroups,
-	}, nil
+	var health []model.ApplicationHealth
+	if err := r.db.WithContext(ctx).Where("application_id = ?", app.ID).Find(&health).Error; err != nil {
+		return nil, err
+	}
+	record := toApplicationRecord(app)
+	if len(health) > 0 {
+		record.Health = toApplicationHealthRecord(health[0])
+	}
+	return &record, nil
 }
 
 func (r *GormApplicationRepo) List(ctx context.Context) ([]domainrepo.ApplicationRecord, error) {
 	var apps []model.Application
-	if err := r.db.WithContext(ctx).Order("LOWER(display_name) ASC, id ASC").Find(&apps).Error; err != nil {
+	if err := r.db.WithContext(ctx).Order("position ASC, LOWER(display_name) ASC, id ASC").Find(&apps).Error; err != nil {
 		return nil, err
 	}
+	var health []model.ApplicationHealth
+	if err := r.db.WithContext(ctx).Find(&health).Error; err != nil {
+		return nil, err
+	}
+	healthByApp := make(map[uint]model.ApplicationHealth, len(health))
+	for _, h := range health {
+		healthByApp[h.ApplicationID] = h
+	}
 	records := make([]domainrepo.ApplicationRecord, len(apps))
 	for i, app := range apps {
-		records[i] = domainrepo.ApplicationRecord{
-			ID:              app.ID,
-			CreatedBy:       app.CreatedBy,
-			Icon:            app.Icon,
-			DisplayName:     app.DisplayName,
-			Url:             app.Url,
-			VisibleToGroups: app.VisibleToGroups,
+		records[i] = toApplicationRecord(app)
+		if h, ok := healthByApp[app.ID]; ok {
+			records[i].Health = toApplicationHealthRecord(h)
 		}
 	}
 	return records, nil
@@ -94,3 +128,47 @@ func (r *GormApplicationRepo) List(ctx context.Context) ([]domainrepo.Applicatio
 func (r *GormApplicationRepo) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.Application{}, id).Error
 }
+
+func (r *GormApplicationRepo) Reorder(ctx context.Context, orderedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, "applications", "", 0, orderedIDs)
+	})
+}
+
+func (r *GormApplicationRepo) RecordHealth(ctx context.Context, id uint, health domainrepo.ApplicationHealthRecord) error {
+	// INSERT … SELECT skips applications deleted while they were being probed
+	// instead of failing on the foreign key.
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO application_health (application_id, state, latency_ms, checked_at, error)
+		SELECT id, ?, ?, ?, ? FROM applications WHERE id = ?
+		ON CONFLICT (application_id) DO UPDATE SET
+			state = EXCLUDED.state,
+			latency_ms = EXCLUDED.latency_ms,
+			checked_at = EXCLUDED.checked_at,
+			error = EXCLUDED.error
+	`, health.State, health.LatencyMs, health.CheckedAt, health.Error, id).Error
+}
+
+func toApplicationRecord(app model.Application) domainrepo.ApplicationRecord {
+	return domainrepo.ApplicationRecord{
+		ID:                   app.ID,
+		CreatedBy:            app.CreatedBy,
+		Icon:                 app.Icon,
+		DisplayName:          app.DisplayName,
+		Url:                  app.Url,
+		VisibleToGroups:      app.VisibleToGroups,
+		Position:             app.Position,
+		HealthCheck:          app.HealthCheck,
+		HealthURL:            app.HealthURL,
+		HealthExpectedStatus: app.HealthExpectedStatus,
+	}
+}
+
+func toApplicationHealthRecord(h model.ApplicationHealth) domainrepo.ApplicationHealthRecord {
+	return domainrepo.ApplicationHealthRecord{
+		State:     h.State,
+		LatencyMs: h.LatencyMs,
+		CheckedAt: h.CheckedAt,
+		Error:     h.Error,
+	}
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.AuditLogRepository = (*GormAuditLogRepo)(nil)
+
+type GormAuditLogRepo struct{ db *gorm.DB }
+
+func NewGormAuditLogRepo(db *gorm.DB) (*GormAuditLogRepo, error) {
+	if err := db.AutoMigrate(&model.AuditLog{}); err != nil {
+		return nil, err
+	}
+	return &GormAuditLogRepo{db: db}, nil
+}
+
+func (r *GormAuditLogRepo) Create(ctx context.Context, entry *domainmodel.AuditEntry) error {
+	m := model.AuditLog{
+		CreatedAt: entry.CreatedAt,
+		ActorID:   entry.ActorID,
+		Action:    string(entry.Action),
+		Target:    entry.Target,
+		IP:        entry.IP,
+	}
+	if err := r.db.WithContext(ctx).Create(&m).Error; err != nil {
+		return err
+	}
+	entry.ID = m.ID
+	entry.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *GormAuditLogRepo) List(ctx context.Context, filter domainrepo.AuditLogFilter) ([]domainmodel.AuditEntry, error) {
+	q := r.db.WithContext(ctx).Order("created_at DESC, id DESC")
+	if filter.ActorID != "" {
+		q = q.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		q = q.Where("action = ?", string(filter.Action))
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var rows []model.AuditLog
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]domainmodel.AuditEntry, len(rows))
+	for i, m := range rows {
+		entries[i] = domainmodel.AuditEntry{
+			ID:        m.ID,
+			ActorID:   m.ActorID,
+			Action:    domainmodel.AuditAction(m.Action),
+			Target:    m.Target,
+			IP:        m.IP,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+	return entries, nil
+}
+
+func (r *GormAuditLogRepo) DeleteBefore(ctx context.Context, t time.Time) error {
+	return r.db.WithContext(ctx).Where("created_at < ?", t).Delete(&model.AuditLog{}).Error
+}
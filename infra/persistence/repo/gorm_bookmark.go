@@ -18,6 +18,9 @@ type GormBookmarkRepo struct {
 }
 
 func NewGormBookmarkRepo(db *gorm.DB) (*GormBookmarkRepo, error) {
+	if err := migratePositions(db, "bookmarks", "category_id"); err != nil {
+		return nil, err
+	}
 	if err := db.AutoMigrate(&model.Bookmark{}); err != nil {
 		return nil, err
 	}
@@ -30,11 +33,35 @@ func (r *GormBookmarkRepo) Upsert(ctx context.Context, record *domainrepo.Bookma
 		Icon:        record.Icon,
 		DisplayName: record.DisplayName,
 		Url:         record.Url,
+		Tags:        record.Tags,
 	}
-	if record.ID != 0 {
-		m.ID = record.ID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if record.ID != 0 {
+			var existing model.Bookmark
+			err := tx.Select("id", "category_id", "position").First(&existing, record.ID).Error
+			if err == nil && existing.CategoryID == record.CategoryID {
+				m.ID = existing.ID
+				m.Position = existing.Position
+				return tx.Save(m).Error
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			m.ID = record.ID
+		}
+		// New bookmarks and bookmarks moved to another category go last.
+		next, err := nextPosition(tx, "bookmarks", "category_id", record.CategoryID)
+		if err != nil {
+			return err
+		}
+		m.Position = next
+		return tx.Save(m).Error
+	})
+	if err != nil {
+		return err
 	}
-	return r.db.WithContext(ctx).Save(m).Error
+	record.Position = m.Position
+	return nil
 }
 
 func (r *GormBookmarkRepo) Get(ctx context.Context, id uint) (*domainrepo.BookmarkRecord, error) {
@@ -51,6 +78,8 @@ func (r *GormBookmarkRepo) Get(ctx context.Context, id uint) (*domainrepo.Bookma
 		Icon:        b.Icon,
 		DisplayName: b.DisplayName,
 		Url:         b.Url,
+		Tags:        b.Tags,
+		Position:    b.Position,
 	}, nil
 }
 
@@ -61,7 +90,7 @@ func (r *GormBookmarkRepo) ListByCategoryIDs(ctx context.Context, categoryIDs []
 	}
 	if err := r.db.WithContext(ctx).
 		Where("category_id IN ?", categoryIDs).
-		Order("LOWER(display_name) ASC, id ASC").
+		Order("position ASC, LOWER(display_name) ASC, id ASC").
 		Find(&list).Error; err != nil {
 		return nil, err
 	}
@@ -73,6 +102,8 @@ func (r *GormBookmarkRepo) ListByCategoryIDs(ctx context.Context, categoryIDs []
 			Icon:        b.Icon,
 			DisplayName: b.DisplayName,
 			Url:         b.Url,
+			Tags:        b.Tags,
+			Position:    b.Position,
 		}
 	}
 	return records, nil
@@ -81,3 +112,9 @@ func (r *GormBookmarkRepo) ListByCategoryIDs(ctx context.Context, categoryIDs []
 func (r *GormBookmarkRepo) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&model.Bookmark{}, id).Error
 }
+
+func (r *GormBookmarkRepo) Reorder(ctx context.Context, categoryID uint, orderedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, "bookmarks", "category_id", categoryID, orderedIDs)
+	})
+}
@@ -18,6 +18,9 @@ type GormCategoryRepo struct {
 }
 
 func NewGormCategoryRepo(db *gorm.DB) (*GormCategoryRepo, error) {
+	if err := migratePositions(db, "categories", "dashboard_id"); err != nil {
+		return nil, err
+	}
 	if err := db.AutoMigrate(&model.Category{}); err != nil {
 		return nil, err
 	}
@@ -25,18 +28,53 @@ func NewGormCategoryRepo(db *gorm.DB) (*GormCategoryRepo, error) {
 }
 
 func (r *GormCategoryRepo) Upsert(ctx context.Context, record *domainrepo.CategoryRecord) error {
+	var parentID *uint
+	if record.ParentID != 0 {
+		parentID = &record.ParentID
+	}
 	m := &model.Category{
 		DashboardID: record.DashboardID,
+		ParentID:    parentID,
 		DisplayName: record.DisplayName,
 		IsShelved:   record.IsShelved,
+		TagQuery:    record.TagQuery,
 	}
-	if record.ID != 0 {
-		m.ID = record.ID
-	}
-	if err := r.db.WithContext(ctx).Save(m).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if record.ID != 0 {
+			var existing model.Category
+			err := tx.Select("id", "dashboard_id", "parent_id", "position").First(&existing, record.ID).Error
+			if err == nil && existing.DashboardID == record.DashboardID && sameParent(existing.ParentID, parentID) {
+				m.ID = existing.ID
+				m.Position = existing.Position
+				return tx.Save(m).Error
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			m.ID = record.ID
+			if err == nil && existing.DashboardID != record.DashboardID {
+				ids, err := subtreeIDs(tx, existing.ID)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&model.Category{}).Where("id IN ?", ids).
+					Update("dashboard_id", record.DashboardID).Error; err != nil {
+					return err
+				}
+			}
+		}
+		next, err := nextPosition(tx, "categories", "dashboard_id", record.DashboardID)
+		if err != nil {
+			return err
+		}
+		m.Position = next
+		return tx.Save(m).Error
+	})
+	if err != nil {
 		return err
 	}
 	record.ID = m.ID
+	record.Position = m.Position
 	return nil
 }
 
@@ -48,42 +86,84 @@ func (r *GormCategoryRepo) Get(ctx context.Context, id uint) (*domainrepo.Catego
 		}
 		return nil, err
 	}
-	return &domainrepo.CategoryRecord{
-		ID:          c.ID,
-		DashboardID: c.DashboardID,
-		DisplayName: c.DisplayName,
-		IsShelved:   c.IsShelved,
-	}, nil
+	record := toCategoryRecord(c)
+	return &record, nil
 }
 
 func (r *GormCategoryRepo) ListByDashboardID(ctx context.Context, dashboardID uint) ([]domainrepo.CategoryRecord, error) {
 	var list []model.Category
 	if err := r.db.WithContext(ctx).
 		Where("dashboard_id = ?", dashboardID).
-		Order("LOWER(display_name) ASC, id ASC").
+		Order("position ASC, LOWER(display_name) ASC, id ASC").
 		Find(&list).Error; err != nil {
 		return nil, err
 	}
 	records := make([]domainrepo.CategoryRecord, len(list))
 	for i, c := range list {
-		records[i] = domainrepo.CategoryRecord{
-			ID:          c.ID,
-			DashboardID: c.DashboardID,
-			DisplayName: c.DisplayName,
-			IsShelved:   c.IsShelved,
-		}
+		records[i] = toCategoryRecord(c)
 	}
 	return records, nil
 }
 
 func (r *GormCategoryRepo) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("category_id = ?", id).Delete(&model.Bookmark{}).Error; err != nil {
+		ids, err := subtreeIDs(tx, id)
+		if err != nil {
 			return err
 		}
-		if err := tx.Delete(&model.Category{}, id).Error; err != nil {
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Where("category_id IN ?", ids).Delete(&model.Bookmark{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Category{}, ids).Error; err != nil {
 			return err
 		}
 		return nil
 	})
 }
+
+func (r *GormCategoryRepo) Reorder(ctx context.Context, dashboardID uint, orderedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, "categories", "dashboard_id", dashboardID, orderedIDs)
+	})
+}
+
+// subtreeIDs returns the id of the category and of all categories nested
+// below it, or nothing when the category does not exist.
+func subtreeIDs(tx *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree
+	`, id).Scan(&ids).Error
+	return ids, err
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func toCategoryRecord(c model.Category) domainrepo.CategoryRecord {
+	var parentID uint
+	if c.ParentID != nil {
+		parentID = *c.ParentID
+	}
+	return domainrepo.CategoryRecord{
+		ID:          c.ID,
+		DashboardID: c.DashboardID,
+		ParentID:    parentID,
+		DisplayName: c.DisplayName,
+		IsShelved:   c.IsShelved,
+		TagQuery:    c.TagQuery,
+		Position:    c.Position,
+	}
+}
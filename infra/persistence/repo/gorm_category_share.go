@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ domainrepo.CategoryShareRepository = (*GormCategoryShareRepo)(nil)
+
+type GormCategoryShareRepo struct {
+	db *gorm.DB
+}
+
+func NewGormCategoryShareRepo(db *gorm.DB) (*GormCategoryShareRepo, error) {
+	if err := db.AutoMigrate(&model.CategoryShare{}); err != nil {
+		return nil, err
+	}
+	return &GormCategoryShareRepo{db: db}, nil
+}
+
+func (r *GormCategoryShareRepo) ListByCategory(ctx context.Context, categoryID uint) ([]domainrepo.CategoryShareRecord, error) {
+	var list []model.CategoryShare
+	if err := r.db.WithContext(ctx).
+		Where("category_id = ?", categoryID).
+		Order("created_at ASC, user_id ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.CategoryShareRecord, len(list))
+	for i, s := range list {
+		records[i] = toCategoryShareRecord(s)
+	}
+	return records, nil
+}
+
+func (r *GormCategoryShareRepo) ListByUser(ctx context.Context, userID string) ([]domainrepo.CategoryShareRecord, error) {
+	var rows []struct {
+		CategoryID  uint
+		UserID      string
+		Role        string
+		CreatedAt   time.Time
+		DisplayName string
+		OwnerID     string
+	}
+	if err := r.db.WithContext(ctx).
+		Table("category_shares").
+		Select("category_shares.category_id, category_shares.user_id, category_shares.role, category_shares.created_at, categories.display_name, dashboards.user_id AS owner_id").
+		Joins("JOIN categories ON categories.id = category_shares.category_id").
+		Joins("JOIN dashboards ON dashboards.id = categories.dashboard_id").
+		Where("category_shares.user_id = ?", userID).
+		Order("LOWER(categories.display_name) ASC, category_shares.category_id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.CategoryShareRecord, len(rows))
+	for i, row := range rows {
+		records[i] = domainrepo.CategoryShareRecord{
+			CategoryID:  row.CategoryID,
+			UserID:      row.UserID,
+			Role:        row.Role,
+			CreatedAt:   row.CreatedAt,
+			DisplayName: row.DisplayName,
+			OwnerID:     row.OwnerID,
+		}
+	}
+	return records, nil
+}
+
+func (r *GormCategoryShareRepo) Get(ctx context.Context, categoryID uint, userID string) (*domainrepo.CategoryShareRecord, error) {
+	var s model.CategoryShare
+	if err := r.db.WithContext(ctx).
+		Where("category_id = ? AND user_id = ?", categoryID, userID).
+		First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityCategoryShare)
+		}
+		return nil, err
+	}
+	record := toCategoryShareRecord(s)
+	return &record, nil
+}
+
+func (r *GormCategoryShareRepo) Upsert(ctx context.Context, record *domainrepo.CategoryShareRecord) error {
+	s := model.CategoryShare{
+		CategoryID: record.CategoryID,
+		UserID:     record.UserID,
+		Role:       record.Role,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role"}),
+		}).
+		Create(&s).Error
+}
+
+func (r *GormCategoryShareRepo) Delete(ctx context.Context, categoryID uint, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("category_id = ? AND user_id = ?", categoryID, userID).
+		Delete(&model.CategoryShare{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityCategoryShare)
+	}
+	return nil
+}
+
+func toCategoryShareRecord(s model.CategoryShare) domainrepo.CategoryShareRecord {
+	return domainrepo.CategoryShareRecord{
+		CategoryID: s.CategoryID,
+		UserID:     s.UserID,
+		Role:       s.Role,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.CustomIconRepository = (*GormCustomIconRepo)(nil)
+
+type GormCustomIconRepo struct{ db *gorm.DB }
+
+func NewGormCustomIconRepo(db *gorm.DB) (*GormCustomIconRepo, error) {
+	if err := db.AutoMigrate(&model.CustomIcon{}); err != nil {
+		return nil, err
+	}
+	return &GormCustomIconRepo{db: db}, nil
+}
+
+func (r *GormCustomIconRepo) Create(ctx context.Context, record *domainrepo.CustomIconRecord) error {
+	m := &model.CustomIcon{
+		DisplayName: record.DisplayName,
+		ContentType: record.ContentType,
+		Data:        record.Data,
+	}
+	if record.UserID != "" {
+		m.UserID = &record.UserID
+	}
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	record.ID = m.ID
+	return nil
+}
+
+// GetByID returns the icon with the given id, or a NotFoundError if not found.
+func (r *GormCustomIconRepo) GetByID(ctx context.Context, id uint) (*domainrepo.CustomIconRecord, error) {
+	var i model.CustomIcon
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&i).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityCustomIcon)
+		}
+		return nil, err
+	}
+	record := toCustomIconRecord(i)
+	return &record, nil
+}
+
+func (r *GormCustomIconRepo) ListVisible(ctx context.Context, userID string) ([]domainrepo.CustomIconRecord, error) {
+	var list []model.CustomIcon
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? OR user_id IS NULL", userID).
+		Order("user_id IS NULL ASC, LOWER(display_name) ASC, id ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.CustomIconRecord, len(list))
+	for i, icon := range list {
+		records[i] = toCustomIconRecord(icon)
+	}
+	return records, nil
+}
+
+func (r *GormCustomIconRepo) Delete(ctx context.Context, userID string, id uint) error {
+	q := r.db.WithContext(ctx).Where("id = ?", id)
+	if userID == "" {
+		q = q.Where("user_id IS NULL")
+	} else {
+		q = q.Where("user_id = ?", userID)
+	}
+	return q.Delete(&model.CustomIcon{}).Error
+}
+
+func toCustomIconRecord(i model.CustomIcon) domainrepo.CustomIconRecord {
+	record := domainrepo.CustomIconRecord{
+		ID:          i.ID,
+		DisplayName: i.DisplayName,
+		ContentType: i.ContentType,
+		Data:        i.Data,
+	}
+	if i.UserID != nil {
+		record.UserID = *i.UserID
+	}
+	return record
+}
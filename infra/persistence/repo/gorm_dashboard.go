@@ -18,20 +18,92 @@ type GormDashboardRepo struct {
 }
 
 func NewGormDashboardRepo(db *gorm.DB) (*GormDashboardRepo, error) {
+	// Users used to have exactly one dashboard, enforced by a unique index on
+	// user_id. That dashboard becomes their default, and the index is dropped
+	// so AutoMigrate can recreate it as a plain one. Uniqueness now applies to
+	// default dashboards only, see below.
+	noPS := db.Session(&gorm.Session{PrepareStmt: false})
+	if err := noPS.Exec(`
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'dashboards')
+			AND NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name = 'dashboards' AND column_name = 'is_default'
+			) THEN
+				ALTER TABLE dashboards ADD COLUMN is_default boolean NOT NULL DEFAULT false;
+				UPDATE dashboards SET is_default = true;
+			END IF;
+			IF EXISTS (
+				SELECT 1 FROM pg_indexes
+				WHERE tablename = 'dashboards' AND indexname = 'idx_dashboards_user_id'
+				AND indexdef LIKE 'CREATE UNIQUE INDEX%'
+			) THEN
+				DROP INDEX idx_dashboards_user_id;
+			END IF;
+		END
+		$$
+	`).Error; err != nil {
+		return nil, err
+	}
 	if err := db.AutoMigrate(&model.Dashboard{}); err != nil {
 		return nil, err
 	}
+	// Every user has at most one default dashboard. Concurrent first page loads
+	// used to be able to provision several; all but the first lose the flag
+	// before the index is created.
+	if err := noPS.Exec(`
+		UPDATE dashboards AS d SET is_default = false
+		WHERE d.is_default AND EXISTS (
+			SELECT 1 FROM dashboards AS o
+			WHERE o.user_id = d.user_id AND o.is_default
+			AND (o.position, o.id) < (d.position, d.id)
+		)
+	`).Error; err != nil {
+		return nil, err
+	}
+	if err := noPS.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_dashboards_user_id_default
+			ON dashboards (user_id) WHERE is_default
+	`).Error; err != nil {
+		return nil, err
+	}
 	return &GormDashboardRepo{db: db}, nil
 }
 
 func (r *GormDashboardRepo) Upsert(ctx context.Context, record *domainrepo.DashboardRecord) error {
 	m := &model.Dashboard{
-		UserID: record.UserID,
+		UserID:    record.UserID,
+		Name:      record.Name,
+		IsDefault: record.IsDefault,
 	}
-	if record.ID != 0 {
-		m.ID = record.ID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if record.ID != 0 {
+			var existing model.Dashboard
+			err := tx.Select("id", "position").First(&existing, record.ID).Error
+			if err == nil {
+				m.ID = existing.ID
+				m.Position = existing.Position
+				return tx.Save(m).Error
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			m.ID = record.ID
+		}
+		next, err := nextPosition(tx, "dashboards", "user_id", record.UserID)
+		if err != nil {
+			return err
+		}
+		m.Position = next
+		return tx.Save(m).Error
+	})
+	if err != nil {
+		return err
 	}
-	return r.db.WithContext(ctx).Save(m).Error
+	record.ID = m.ID
+	record.Position = m.Position
+	return nil
 }
 
 func (r *GormDashboardRepo) Get(ctx context.Context, id uint) (*domainrepo.DashboardRecord, error) {
@@ -43,21 +115,110 @@ func (r *GormDashboardRepo) Get(ctx context.Context, id uint) (*domainrepo.Dashb
 		}
 		return nil, err
 	}
-	return &domainrepo.DashboardRecord{ID: dashboard.ID, UserID: dashboard.UserID}, nil
+	record := toDashboardRecord(dashboard)
+	return &record, nil
 }
 
-func (r *GormDashboardRepo) GetByUserID(ctx context.Context, userID string) (*domainrepo.DashboardRecord, error) {
+func (r *GormDashboardRepo) GetDefault(ctx context.Context, userID string) (*domainrepo.DashboardRecord, error) {
 	var dashboard model.Dashboard
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&dashboard).Error
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_default", userID).
+		Order("position ASC, id ASC").
+		First(&dashboard).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainerrors.NotFound(domainerrors.EntityDashboard)
 		}
 		return nil, err
 	}
-	return &domainrepo.DashboardRecord{ID: dashboard.ID, UserID: dashboard.UserID}, nil
+	record := toDashboardRecord(dashboard)
+	return &record, nil
+}
+
+func (r *GormDashboardRepo) ListByUserID(ctx context.Context, userID string) ([]domainrepo.DashboardRecord, error) {
+	var list []model.Dashboard
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("position ASC, id ASC").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.DashboardRecord, len(list))
+	for i, d := range list {
+		records[i] = toDashboardRecord(d)
+	}
+	return records, nil
+}
+
+// SetDefault clears the flag on the previous default first, as the unique
+// index allows only one default dashboard per user at any time.
+func (r *GormDashboardRepo) SetDefault(ctx context.Context, userID string, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Dashboard{}).
+			Where("user_id = ? AND id <> ?", userID, id).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&model.Dashboard{}).Where("id = ? AND user_id = ?", id, userID).Update("is_default", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domainerrors.NotFound(domainerrors.EntityDashboard)
+		}
+		return nil
+	})
 }
 
 func (r *GormDashboardRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Dashboard{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dashboard model.Dashboard
+		if err := tx.First(&dashboard, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		categories := tx.Model(&model.Category{}).Select("id").Where("dashboard_id = ?", id)
+		if err := tx.Where("category_id IN (?)", categories).Delete(&model.Bookmark{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("dashboard_id = ?", id).Delete(&model.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Dashboard{}, id).Error; err != nil {
+			return err
+		}
+		if !dashboard.IsDefault {
+			return nil
+		}
+		var next model.Dashboard
+		err := tx.Select("id").
+			Where("user_id = ?", dashboard.UserID).
+			Order("position ASC, id ASC").
+			First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Dashboard{}).Where("id = ?", next.ID).Update("is_default", true).Error
+	})
+}
+
+func (r *GormDashboardRepo) Reorder(ctx context.Context, userID string, orderedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return reorder(tx, "dashboards", "user_id", userID, orderedIDs)
+	})
+}
+
+func toDashboardRecord(d model.Dashboard) domainrepo.DashboardRecord {
+	return domainrepo.DashboardRecord{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		Name:      d.Name,
+		Position:  d.Position,
+		IsDefault: d.IsDefault,
+	}
 }
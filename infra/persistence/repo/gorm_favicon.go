@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.FaviconRepository = (*GormFaviconRepo)(nil)
+
+type GormFaviconRepo struct{ db *gorm.DB }
+
+func NewGormFaviconRepo(db *gorm.DB) (*GormFaviconRepo, error) {
+	if err := db.AutoMigrate(&model.Favicon{}); err != nil {
+		return nil, err
+	}
+	return &GormFaviconRepo{db: db}, nil
+}
+
+// Get returns the cached favicon of site, or a NotFoundError if there is none.
+func (r *GormFaviconRepo) Get(ctx context.Context, site string) (*domainrepo.FaviconRecord, error) {
+	var f model.Favicon
+	if err := r.db.WithContext(ctx).Where("site = ?", site).First(&f).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityFavicon)
+		}
+		return nil, err
+	}
+	return &domainrepo.FaviconRecord{
+		Site:        f.Site,
+		ContentType: f.ContentType,
+		Data:        f.Data,
+		FetchedAt:   f.FetchedAt,
+	}, nil
+}
+
+func (r *GormFaviconRepo) Upsert(ctx context.Context, record *domainrepo.FaviconRecord) error {
+	data := record.Data
+	if data == nil {
+		data = []byte{}
+	}
+	return r.db.WithContext(ctx).Save(&model.Favicon{
+		Site:        record.Site,
+		ContentType: record.ContentType,
+		Data:        data,
+		FetchedAt:   record.FetchedAt,
+	}).Error
+}
+
+func (r *GormFaviconRepo) DeleteAllButNewest(ctx context.Context, keep int) error {
+	db := r.db.WithContext(ctx)
+	newest := db.Model(&model.Favicon{}).Select("site").Order("fetched_at DESC").Limit(keep)
+	return db.Where("site NOT IN (?)", newest).Delete(&model.Favicon{}).Error
+}
+
+func (r *GormFaviconRepo) DeleteFetchedBefore(ctx context.Context, t time.Time) error {
+	return r.db.WithContext(ctx).Where("fetched_at < ?", t).Delete(&model.Favicon{}).Error
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
 	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
 
@@ -82,6 +83,80 @@ func (r *GormIdpLinkRepo) ResolveOrCreate(ctx context.Context, issuer, sub strin
 	return userID, true, nil
 }
 
+// Link attaches (issuer, sub) to userID as a secondary identity. When the
+// identity is already linked, the existing link is left untouched and its
+// owner is returned so the caller can detect conflicts.
+func (r *GormIdpLinkRepo) Link(ctx context.Context, userID, issuer, sub string) (string, error) {
+	newLink := model.IdpLink{
+		UserID:    userID,
+		Issuer:    issuer,
+		Sub:       sub,
+		IsPrimary: false,
+		LinkedAt:  time.Now(),
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&newLink).Error; err != nil {
+		return "", err
+	}
+
+	var link model.IdpLink
+	if err := r.db.WithContext(ctx).
+		Where("issuer = ? AND sub = ?", issuer, sub).
+		First(&link).Error; err != nil {
+		return "", err
+	}
+	return link.UserID, nil
+}
+
+func (r *GormIdpLinkRepo) ListByUserID(ctx context.Context, userID string) ([]domainrepo.IdpLinkRecord, error) {
+	var links []model.IdpLink
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_primary DESC, linked_at ASC").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return toIdpLinkRecords(links), nil
+}
+
+func (r *GormIdpLinkRepo) List(ctx context.Context) ([]domainrepo.IdpLinkRecord, error) {
+	var links []model.IdpLink
+	if err := r.db.WithContext(ctx).
+		Order("user_id ASC, is_primary DESC, linked_at ASC").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return toIdpLinkRecords(links), nil
+}
+
+func toIdpLinkRecords(links []model.IdpLink) []domainrepo.IdpLinkRecord {
+	records := make([]domainrepo.IdpLinkRecord, 0, len(links))
+	for _, l := range links {
+		records = append(records, domainrepo.IdpLinkRecord{
+			UserID:    l.UserID,
+			Issuer:    l.Issuer,
+			Sub:       l.Sub,
+			IsPrimary: l.IsPrimary,
+			LinkedAt:  l.LinkedAt,
+		})
+	}
+	return records
+}
+
+func (r *GormIdpLinkRepo) Unlink(ctx context.Context, userID, issuer, sub string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND issuer = ? AND sub = ?", userID, issuer, sub).
+		Delete(&model.IdpLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityIdpLink)
+	}
+	return nil
+}
+
 func (r *GormIdpLinkRepo) DeleteByUserID(ctx context.Context, userID string) error {
 	return r.db.WithContext(ctx).
 		Where("user_id = ?", userID).
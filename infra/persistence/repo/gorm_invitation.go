@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.InvitationRepository = (*GormInvitationRepo)(nil)
+
+type GormInvitationRepo struct {
+	db *gorm.DB
+}
+
+func NewGormInvitationRepo(db *gorm.DB) (*GormInvitationRepo, error) {
+	if err := db.AutoMigrate(&model.Invitation{}, &model.InvitationRedemption{}); err != nil {
+		return nil, err
+	}
+	return &GormInvitationRepo{db: db}, nil
+}
+
+func (r *GormInvitationRepo) Create(ctx context.Context, record *domainrepo.InvitationRecord) error {
+	m := &model.Invitation{
+		ID:            record.ID,
+		TokenHash:     record.TokenHash,
+		Groups:        encodeGroups(record.Groups),
+		MaxUses:       record.MaxUses,
+		ExpiresAt:     record.ExpiresAt,
+		GrantDuration: record.GrantDuration,
+		CreatedBy:     record.CreatedBy,
+	}
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	record.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *GormInvitationRepo) List(ctx context.Context) ([]domainrepo.InvitationRecord, error) {
+	var ms []model.Invitation
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&ms).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.InvitationRecord, 0, len(ms))
+	for _, m := range ms {
+		records = append(records, toInvitationRecord(m))
+	}
+	return records, nil
+}
+
+func (r *GormInvitationRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domainrepo.InvitationRecord, error) {
+	var m model.Invitation
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityInvitation)
+		}
+		return nil, err
+	}
+	record := toInvitationRecord(m)
+	return &record, nil
+}
+
+func (r *GormInvitationRepo) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.Invitation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityInvitation)
+	}
+	return nil
+}
+
+// Redeem increments the use count only while the invitation is valid, so
+// concurrent redemptions cannot exceed the limit.
+func (r *GormInvitationRepo) Redeem(ctx context.Context, redemption *domainrepo.InvitationRedemptionRecord, now time.Time) (bool, error) {
+	redeemed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Invitation{}).
+			Where("id = ? AND uses < max_uses AND expires_at > ?", redemption.InvitationID, now).
+			Update("uses", gorm.Expr("uses + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		m := &model.InvitationRedemption{
+			InvitationID: redemption.InvitationID,
+			UserID:       redemption.UserID,
+			Groups:       encodeGroups(redemption.Groups),
+			ExpiresAt:    redemption.ExpiresAt,
+		}
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		redemption.ID = m.ID
+		redemption.CreatedAt = m.CreatedAt
+		redeemed = true
+		return nil
+	})
+	return redeemed, err
+}
+
+func (r *GormInvitationRepo) ListRedemptions(ctx context.Context) ([]domainrepo.InvitationRedemptionRecord, error) {
+	var ms []model.InvitationRedemption
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&ms).Error; err != nil {
+		return nil, err
+	}
+	return toInvitationRedemptionRecords(ms), nil
+}
+
+func (r *GormInvitationRepo) ListActiveRedemptions(ctx context.Context, userID string, now time.Time) ([]domainrepo.InvitationRedemptionRecord, error) {
+	var ms []model.InvitationRedemption
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, now).
+		Order("created_at DESC").
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	return toInvitationRedemptionRecords(ms), nil
+}
+
+func (r *GormInvitationRepo) HasRedeemed(ctx context.Context, invitationID, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.InvitationRedemption{}).
+		Where("invitation_id = ? AND user_id = ?", invitationID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RevokeRedemption leaves redemptions that already ended untouched, so their
+// original expiry stays visible.
+func (r *GormInvitationRepo) RevokeRedemption(ctx context.Context, id uint, now time.Time) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.InvitationRedemption{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domainerrors.NotFound(domainerrors.EntityInvitation)
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.InvitationRedemption{}).
+		Where("id = ? AND expires_at > ?", id, now).
+		Update("expires_at", now).Error
+}
+
+func toInvitationRecord(m model.Invitation) domainrepo.InvitationRecord {
+	return domainrepo.InvitationRecord{
+		ID:            m.ID,
+		TokenHash:     m.TokenHash,
+		Groups:        decodeGroups(m.Groups),
+		MaxUses:       m.MaxUses,
+		Uses:          m.Uses,
+		ExpiresAt:     m.ExpiresAt,
+		GrantDuration: m.GrantDuration,
+		CreatedBy:     m.CreatedBy,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+func toInvitationRedemptionRecords(ms []model.InvitationRedemption) []domainrepo.InvitationRedemptionRecord {
+	records := make([]domainrepo.InvitationRedemptionRecord, 0, len(ms))
+	for _, m := range ms {
+		records = append(records, domainrepo.InvitationRedemptionRecord{
+			ID:           m.ID,
+			InvitationID: m.InvitationID,
+			UserID:       m.UserID,
+			Groups:       decodeGroups(m.Groups),
+			ExpiresAt:    m.ExpiresAt,
+			CreatedAt:    m.CreatedAt,
+		})
+	}
+	return records
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ domainrepo.LocalAccountRepository = (*GormLocalAccountRepo)(nil)
+
+type GormLocalAccountRepo struct {
+	db *gorm.DB
+}
+
+func NewGormLocalAccountRepo(db *gorm.DB) (*GormLocalAccountRepo, error) {
+	if err := db.AutoMigrate(&model.LocalAccount{}); err != nil {
+		return nil, err
+	}
+	return &GormLocalAccountRepo{db: db}, nil
+}
+
+func (r *GormLocalAccountRepo) GetByUsername(ctx context.Context, username string) (*domainrepo.LocalAccountRecord, error) {
+	var a model.LocalAccount
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityLocalAccount)
+		}
+		return nil, err
+	}
+	record := toLocalAccountRecord(a)
+	return &record, nil
+}
+
+func (r *GormLocalAccountRepo) List(ctx context.Context) ([]domainrepo.LocalAccountRecord, error) {
+	var list []model.LocalAccount
+	if err := r.db.WithContext(ctx).Order("username ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.LocalAccountRecord, 0, len(list))
+	for _, a := range list {
+		records = append(records, toLocalAccountRecord(a))
+	}
+	return records, nil
+}
+
+func (r *GormLocalAccountRepo) Create(ctx context.Context, record *domainrepo.LocalAccountRecord) error {
+	m := toLocalAccountModel(*record)
+	return r.db.WithContext(ctx).Create(&m).Error
+}
+
+func (r *GormLocalAccountRepo) SetPassword(ctx context.Context, username, passwordHash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.LocalAccount{}).
+		Where("username = ?", username).
+		Update("password_hash", passwordHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityLocalAccount)
+	}
+	return nil
+}
+
+func (r *GormLocalAccountRepo) Delete(ctx context.Context, username string) error {
+	result := r.db.WithContext(ctx).Where("username = ?", username).Delete(&model.LocalAccount{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityLocalAccount)
+	}
+	return nil
+}
+
+func (r *GormLocalAccountRepo) ReplaceManaged(ctx context.Context, records []domainrepo.LocalAccountRecord) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		usernames := make([]string, 0, len(records))
+		for _, record := range records {
+			record.Managed = true
+			m := toLocalAccountModel(record)
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "username"}},
+				DoUpdates: clause.AssignmentColumns([]string{"password_hash", "display_name", "email", "groups", "managed", "updated_at"}),
+			}).Create(&m).Error; err != nil {
+				return err
+			}
+			usernames = append(usernames, record.Username)
+		}
+
+		stale := tx.Where("managed = ?", true)
+		if len(usernames) > 0 {
+			stale = stale.Where("username NOT IN ?", usernames)
+		}
+		return stale.Delete(&model.LocalAccount{}).Error
+	})
+}
+
+func toLocalAccountModel(r domainrepo.LocalAccountRecord) model.LocalAccount {
+	return model.LocalAccount{
+		Username:     r.Username,
+		PasswordHash: r.PasswordHash,
+		DisplayName:  r.DisplayName,
+		Email:        r.Email,
+		Groups:       encodeGroups(r.Groups),
+		Managed:      r.Managed,
+	}
+}
+
+func toLocalAccountRecord(m model.LocalAccount) domainrepo.LocalAccountRecord {
+	return domainrepo.LocalAccountRecord{
+		Username:     m.Username,
+		PasswordHash: m.PasswordHash,
+		DisplayName:  m.DisplayName,
+		Email:        m.Email,
+		Groups:       decodeGroups(m.Groups),
+		Managed:      m.Managed,
+		CreatedAt:    m.CreatedAt,
+	}
+}
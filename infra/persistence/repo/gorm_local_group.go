@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ domainrepo.LocalGroupRepository = (*GormLocalGroupRepo)(nil)
+
+type GormLocalGroupRepo struct {
+	db *gorm.DB
+}
+
+func NewGormLocalGroupRepo(db *gorm.DB) (*GormLocalGroupRepo, error) {
+	if err := db.AutoMigrate(&model.LocalGroup{}, &model.LocalGroupMember{}); err != nil {
+		return nil, err
+	}
+	return &GormLocalGroupRepo{db: db}, nil
+}
+
+func (r *GormLocalGroupRepo) List(ctx context.Context) ([]domainrepo.LocalGroupRecord, error) {
+	var groups []model.LocalGroup
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	var members []model.LocalGroupMember
+	if err := r.db.WithContext(ctx).Order("user_id ASC").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	byGroup := make(map[uint][]string, len(groups))
+	for _, m := range members {
+		byGroup[m.GroupID] = append(byGroup[m.GroupID], m.UserID)
+	}
+
+	records := make([]domainrepo.LocalGroupRecord, 0, len(groups))
+	for _, g := range groups {
+		record := toLocalGroupRecord(g)
+		record.Members = byGroup[g.ID]
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (r *GormLocalGroupRepo) GetByName(ctx context.Context, name string) (*domainrepo.LocalGroupRecord, error) {
+	var g model.LocalGroup
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&g).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityLocalGroup)
+		}
+		return nil, err
+	}
+	record := toLocalGroupRecord(g)
+	return &record, nil
+}
+
+func (r *GormLocalGroupRepo) Create(ctx context.Context, record *domainrepo.LocalGroupRecord) error {
+	g := model.LocalGroup{
+		Name:      record.Name,
+		IdpGroups: encodeGroups(record.IdpGroups),
+	}
+	if err := r.db.WithContext(ctx).Create(&g).Error; err != nil {
+		return err
+	}
+	record.ID = g.ID
+	record.CreatedAt = g.CreatedAt
+	return nil
+}
+
+func (r *GormLocalGroupRepo) Update(ctx context.Context, record *domainrepo.LocalGroupRecord) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.LocalGroup{}).
+		Where("id = ?", record.ID).
+		Updates(map[string]any{
+			"name":       record.Name,
+			"idp_groups": encodeGroups(record.IdpGroups),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityLocalGroup)
+	}
+	return nil
+}
+
+func (r *GormLocalGroupRepo) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&model.LocalGroup{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityLocalGroup)
+	}
+	return nil
+}
+
+func (r *GormLocalGroupRepo) AddMember(ctx context.Context, groupID uint, userID string) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.LocalGroup{}).Where("id = ?", groupID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domainerrors.NotFound(domainerrors.EntityLocalGroup)
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.LocalGroupMember{GroupID: groupID, UserID: userID}).Error
+}
+
+func (r *GormLocalGroupRepo) RemoveMember(ctx context.Context, groupID uint, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Delete(&model.LocalGroupMember{}).Error
+}
+
+func toLocalGroupRecord(m model.LocalGroup) domainrepo.LocalGroupRecord {
+	return domainrepo.LocalGroupRecord{
+		ID:        m.ID,
+		Name:      m.Name,
+		IdpGroups: decodeGroups(m.IdpGroups),
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+)
+
+var _ domainrepo.SearchProviderRepository = (*GormSearchProviderRepo)(nil)
+
+type GormSearchProviderRepo struct{ db *gorm.DB }
+
+func NewGormSearchProviderRepo(db *gorm.DB) (*GormSearchProviderRepo, error) {
+	if err := db.AutoMigrate(&model.SearchProvider{}); err != nil {
+		return nil, err
+	}
+	return &GormSearchProviderRepo{db: db}, nil
+}
+
+func (r *GormSearchProviderRepo) Create(ctx context.Context, record *domainrepo.SearchProviderRecord) error {
+	m := &model.SearchProvider{
+		UserID:      record.UserID,
+		DisplayName: record.DisplayName,
+		Bang:        record.Bang,
+		URLTemplate: record.URLTemplate,
+	}
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	record.ID = m.ID
+	return nil
+}
+
+func (r *GormSearchProviderRepo) Delete(ctx context.Context, userID string, id uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&model.SearchProvider{}).Error
+}
+
+func (r *GormSearchProviderRepo) ListByUser(ctx context.Context, userID string) ([]domainrepo.SearchProviderRecord, error) {
+	var list []model.SearchProvider
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("LOWER(display_name) ASC, id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.SearchProviderRecord, len(list))
+	for i, p := range list {
+		records[i] = toSearchProviderRecord(p)
+	}
+	return records, nil
+}
+
+// GetByID returns the search provider for the given user and id, or a NotFoundError if not found.
+func (r *GormSearchProviderRepo) GetByID(ctx context.Context, userID string, id uint) (*domainrepo.SearchProviderRecord, error) {
+	var p model.SearchProvider
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntitySearchProvider)
+		}
+		return nil, err
+	}
+	record := toSearchProviderRecord(p)
+	return &record, nil
+}
+
+func toSearchProviderRecord(p model.SearchProvider) domainrepo.SearchProviderRecord {
+	return domainrepo.SearchProviderRecord{
+		ID:          p.ID,
+		UserID:      p.UserID,
+		DisplayName: p.DisplayName,
+		Bang:        p.Bang,
+		URLTemplate: p.URLTemplate,
+	}
+}
@@ -58,7 +58,9 @@ func (r *GormSessionRepo) Create(ctx context.Context, record *domainrepo.Session
 		ExpiresAt:   record.ExpiresAt,
 		LastIP:      record.LastIP,
 		UserAgent:   record.UserAgent,
+		Issuer:      record.Issuer,
 		Sub:         record.Sub,
+		Sid:         record.Sid,
 		Username:    record.Username,
 		Email:       record.Email,
 		FirstName:   record.FirstName,
@@ -67,6 +69,7 @@ func (r *GormSessionRepo) Create(ctx context.Context, record *domainrepo.Session
 		Picture:     record.Picture,
 		ProfileUrl:  record.ProfileUrl,
 		RawIDToken:  record.RawIDToken,
+		RefreshToken: record.RefreshToken,
 		Groups:      encodeGroups(record.Groups),
 		IsAdmin:     record.IsAdmin,
 	}
@@ -134,6 +137,41 @@ func (r *GormSessionRepo) ListByUserID(ctx context.Context, userID string) ([]*d
 	return records, nil
 }
 
+// ListLatest uses DISTINCT ON to pick the most recently accessed session per user.
+func (r *GormSessionRepo) ListLatest(ctx context.Context) ([]*domainrepo.SessionRecord, error) {
+	var ms []model.Session
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (user_id) *
+		FROM sessions
+		ORDER BY user_id, last_accessed_at DESC, created_at DESC`,
+	).Scan(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*domainrepo.SessionRecord, 0, len(ms))
+	for i := range ms {
+		records = append(records, toSessionRecord(&ms[i]))
+	}
+	return records, nil
+}
+
+func (r *GormSessionRepo) FindLatestByUserID(ctx context.Context, userID string) (*domainrepo.SessionRecord, error) {
+	now := time.Now()
+	var ms []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND (expires_at > ? OR pinned_until > ?)", userID, now, now).
+		Order("last_accessed_at DESC, created_at DESC").
+		Limit(1).
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ms) == 0 {
+		return nil, nil
+	}
+	return toSessionRecord(&ms[0]), nil
+}
+
 func (r *GormSessionRepo) DeleteByID(ctx context.Context, recordID string, userID string) error {
 	return r.db.WithContext(ctx).Where("id = ? AND user_id = ?", recordID, userID).Delete(&model.Session{}).Error
 }
@@ -146,6 +184,14 @@ func (r *GormSessionRepo) DeleteByUserID(ctx context.Context, userID string) err
 	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.Session{}).Error
 }
 
+func (r *GormSessionRepo) DeleteBySub(ctx context.Context, issuer, sub string) error {
+	return r.db.WithContext(ctx).Where("issuer = ? AND sub = ?", issuer, sub).Delete(&model.Session{}).Error
+}
+
+func (r *GormSessionRepo) DeleteBySid(ctx context.Context, issuer, sid string) error {
+	return r.db.WithContext(ctx).Where("issuer = ? AND sid = ?", issuer, sid).Delete(&model.Session{}).Error
+}
+
 func (r *GormSessionRepo) RefreshBySessionID(ctx context.Context, record *domainrepo.SessionRecord) error {
 	return r.db.WithContext(ctx).
 		Model(&model.Session{}).
@@ -153,7 +199,9 @@ func (r *GormSessionRepo) RefreshBySessionID(ctx context.Context, record *domain
 		Updates(map[string]any{
 			"issued_at":    record.IssuedAt,
 			"expires_at":   record.ExpiresAt,
+			"issuer":       record.Issuer,
 			"sub":          record.Sub,
+			"sid":          record.Sid,
 			"username":     record.Username,
 			"email":        record.Email,
 			"first_name":   record.FirstName,
@@ -162,11 +210,40 @@ func (r *GormSessionRepo) RefreshBySessionID(ctx context.Context, record *domain
 			"picture":       record.Picture,
 			"profile_url":   record.ProfileUrl,
 			"raw_id_token":  record.RawIDToken,
+			"refresh_token": record.RefreshToken,
+			"stale":         false,
 			"groups":        encodeGroups(record.Groups),
 			"is_admin":     record.IsAdmin,
 		}).Error
 }
 
+func (r *GormSessionRepo) ListRefreshable(ctx context.Context, before time.Time) ([]*domainrepo.SessionRecord, error) {
+	var ms []model.Session
+	now := time.Now()
+	err := r.db.WithContext(ctx).
+		Where("refresh_token <> '' AND NOT stale AND expires_at < ?", before).
+		Where("expires_at > ? OR (pinned_until > ? AND last_accessed_at > expires_at)", now, now).
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*domainrepo.SessionRecord, 0, len(ms))
+	for i := range ms {
+		records = append(records, toSessionRecord(&ms[i]))
+	}
+	return records, nil
+}
+
+func (r *GormSessionRepo) MarkStale(ctx context.Context, sessionID string) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Session{}).
+		Where("session_id = ?", sessionID).
+		Updates(map[string]any{
+			"stale":         true,
+			"refresh_token": "",
+		}).Error
+}
+
 func (r *GormSessionRepo) DeleteExpired(ctx context.Context) error {
 	now := time.Now()
 	return r.db.WithContext(ctx).
@@ -186,7 +263,9 @@ func toSessionRecord(m *model.Session) *domainrepo.SessionRecord {
 		LastIP:         m.LastIP,
 		UserAgent:      m.UserAgent,
 		CreatedAt:      m.CreatedAt,
+		Issuer:         m.Issuer,
 		Sub:            m.Sub,
+		Sid:            m.Sid,
 		Username:       m.Username,
 		Email:          m.Email,
 		FirstName:      m.FirstName,
@@ -195,6 +274,8 @@ func toSessionRecord(m *model.Session) *domainrepo.SessionRecord {
 		Picture:        m.Picture,
 		ProfileUrl:     m.ProfileUrl,
 		RawIDToken:     m.RawIDToken,
+		RefreshToken:   m.RefreshToken,
+		Stale:          m.Stale,
 		Groups:         decodeGroups(m.Groups),
 		IsAdmin:        m.IsAdmin,
 	}
@@ -50,11 +50,16 @@ func (r *GormSettingRepo) Upsert(ctx context.Context, record *domainrepo.Setting
 	if !domainmodel.IsDefaultThemeID(record.ThemeID) {
 		themeID = &record.ThemeID
 	}
+	var searchProviderID *uint
+	if record.SearchProviderID != 0 {
+		searchProviderID = &record.SearchProviderID
+	}
 	m := &model.Setting{
-		UserID:   record.UserID,
-		ThemeID:  themeID,
-		Language: record.Language,
-		Timezone: record.Timezone,
+		UserID:           record.UserID,
+		ThemeID:          themeID,
+		Language:         record.Language,
+		Timezone:         record.Timezone,
+		SearchProviderID: searchProviderID,
 	}
 	if record.ID != 0 {
 		m.ID = record.ID
@@ -79,5 +84,16 @@ func (r *GormSettingRepo) GetByUserID(ctx context.Context, userID string) (*doma
 	if s.ThemeID != nil {
 		themeID = *s.ThemeID
 	}
-	return &domainrepo.SettingRecord{ID: s.ID, UserID: s.UserID, ThemeID: themeID, Language: s.Language, Timezone: s.Timezone}, nil
+	var searchProviderID uint
+	if s.SearchProviderID != nil {
+		searchProviderID = *s.SearchProviderID
+	}
+	return &domainrepo.SettingRecord{
+		ID:               s.ID,
+		UserID:           s.UserID,
+		ThemeID:          themeID,
+		Language:         s.Language,
+		Timezone:         s.Timezone,
+		SearchProviderID: searchProviderID,
+	}, nil
 }
@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	domainerrors "git.at.oechsler.it/samuel/dash/v2/domain/errors"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+	"git.at.oechsler.it/samuel/dash/v2/infra/persistence/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ domainrepo.SharedCategoryRepository = (*GormSharedCategoryRepo)(nil)
+
+type GormSharedCategoryRepo struct {
+	db *gorm.DB
+}
+
+func NewGormSharedCategoryRepo(db *gorm.DB) (*GormSharedCategoryRepo, error) {
+	if err := db.AutoMigrate(&model.SharedCategory{}, &model.SharedCategoryHide{}); err != nil {
+		return nil, err
+	}
+	return &GormSharedCategoryRepo{db: db}, nil
+}
+
+func (r *GormSharedCategoryRepo) List(ctx context.Context) ([]domainrepo.SharedCategoryRecord, error) {
+	var list []model.SharedCategory
+	if err := r.db.WithContext(ctx).
+		Joins("Category").
+		Order(`LOWER("Category"."display_name") ASC, shared_categories.category_id ASC`).
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.SharedCategoryRecord, len(list))
+	for i, s := range list {
+		records[i] = toSharedCategoryRecord(s)
+	}
+	return records, nil
+}
+
+func (r *GormSharedCategoryRepo) Get(ctx context.Context, categoryID uint) (*domainrepo.SharedCategoryRecord, error) {
+	var s model.SharedCategory
+	if err := r.db.WithContext(ctx).
+		Joins("Category").
+		Where("shared_categories.category_id = ?", categoryID).
+		First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.NotFound(domainerrors.EntityCategory)
+		}
+		return nil, err
+	}
+	record := toSharedCategoryRecord(s)
+	return &record, nil
+}
+
+func (r *GormSharedCategoryRepo) Publish(ctx context.Context, record *domainrepo.SharedCategoryRecord) error {
+	groups := record.VisibleToGroups
+	if groups == nil {
+		groups = []string{}
+	}
+	s := model.SharedCategory{
+		CategoryID:      record.CategoryID,
+		VisibleToGroups: groups,
+		PublishedBy:     record.PublishedBy,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"visible_to_groups"}),
+		}).
+		Create(&s).Error
+}
+
+func (r *GormSharedCategoryRepo) Unpublish(ctx context.Context, categoryID uint) error {
+	result := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Delete(&model.SharedCategory{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.NotFound(domainerrors.EntityCategory)
+	}
+	return nil
+}
+
+func (r *GormSharedCategoryRepo) ListHidden(ctx context.Context, userID string) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).
+		Model(&model.SharedCategoryHide{}).
+		Where("user_id = ?", userID).
+		Pluck("category_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *GormSharedCategoryRepo) Hide(ctx context.Context, userID string, categoryID uint) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.SharedCategory{}).Where("category_id = ?", categoryID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domainerrors.NotFound(domainerrors.EntityCategory)
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.SharedCategoryHide{CategoryID: categoryID, UserID: userID}).Error
+}
+
+func (r *GormSharedCategoryRepo) Show(ctx context.Context, userID string, categoryID uint) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND category_id = ?", userID, categoryID).
+		Delete(&model.SharedCategoryHide{}).Error
+}
+
+func toSharedCategoryRecord(s model.SharedCategory) domainrepo.SharedCategoryRecord {
+	return domainrepo.SharedCategoryRecord{
+		CategoryID:      s.CategoryID,
+		DisplayName:     s.Category.DisplayName,
+		VisibleToGroups: s.VisibleToGroups,
+		PublishedBy:     s.PublishedBy,
+		PublishedAt:     s.CreatedAt,
+	}
+}
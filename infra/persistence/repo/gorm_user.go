@@ -89,6 +89,44 @@ func (r *GormUserRepo) EnsureExists(ctx context.Context, id string) error {
 		Error
 }
 
+func (r *GormUserRepo) List(ctx context.Context) ([]domainrepo.UserRecord, error) {
+	var rows []struct {
+		ID            string
+		CategoryCount int
+		BookmarkCount int
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT u.id,
+		       COUNT(DISTINCT c.id) AS category_count,
+		       COUNT(b.id)          AS bookmark_count
+		FROM users u
+		LEFT JOIN dashboards d ON d.user_id = u.id
+		LEFT JOIN categories c ON c.dashboard_id = d.id
+		LEFT JOIN bookmarks b  ON b.category_id = c.id
+		GROUP BY u.id
+		ORDER BY u.id`,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	records := make([]domainrepo.UserRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, domainrepo.UserRecord{
+			ID:            row.ID,
+			CategoryCount: row.CategoryCount,
+			BookmarkCount: row.BookmarkCount,
+		})
+	}
+	return records, nil
+}
+
+func (r *GormUserRepo) Exists(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *GormUserRepo) DeleteByID(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).
 		Where("id = ?", id).
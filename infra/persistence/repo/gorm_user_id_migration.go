@@ -37,7 +37,10 @@ func (r *GormUserIDMigrationRepo) MigrateUserID(ctx context.Context, oldID, newI
 		).Error; err != nil {
 			return err
 		}
-		for _, table := range []string{"dashboards", "settings", "themes", "sessions"} {
+		for _, table := range []string{
+			"dashboards", "settings", "themes", "sessions", "custom_icons",
+			"access_tokens", "invitation_redemptions",
+		} {
 			if err := tx.Exec(
 				"UPDATE "+table+" SET user_id = ? WHERE user_id = ?",
 				newID, oldID,
@@ -45,6 +48,22 @@ func (r *GormUserIDMigrationRepo) MigrateUserID(ctx context.Context, oldID, newI
 				return err
 			}
 		}
+		// Tables keyed by user and another column: rows that newID already has
+		// are left behind and removed with the legacy user below.
+		for table, key := range map[string]string{
+			"search_providers":      "bang",
+			"local_group_members":   "group_id",
+			"category_shares":       "category_id",
+			"shared_category_hides": "category_id",
+		} {
+			if err := tx.Exec(
+				"UPDATE "+table+" AS t SET user_id = ? WHERE t.user_id = ? AND NOT EXISTS ("+
+					"SELECT 1 FROM "+table+" AS n WHERE n.user_id = ? AND n."+key+" = t."+key+")",
+				newID, oldID, newID,
+			).Error; err != nil {
+				return err
+			}
+		}
 		// Remove the legacy users row; CASCADE will clean up any remaining
 		// references (there should be none after the UPDATE loop above).
 		if err := tx.Exec("DELETE FROM users WHERE id = ?", oldID).Error; err != nil {
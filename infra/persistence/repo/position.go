@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// migratePositions adds the position column to table and seeds it with the
+// alphabetical order that was used before manual ordering existed. It must run
+// before AutoMigrate: once AutoMigrate has added the column every row is at
+// position 0 and the previous order can no longer be told apart from a fresh
+// table. partitionBy names the parent column positions are scoped to, or is
+// empty when the whole table shares one order.
+func migratePositions(db *gorm.DB, table, partitionBy string) error {
+	partition := ""
+	if partitionBy != "" {
+		partition = "PARTITION BY " + partitionBy
+	}
+	noPS := db.Session(&gorm.Session{PrepareStmt: false})
+	return noPS.Exec(fmt.Sprintf(`
+		DO $$
+		BEGIN
+			IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = '%[1]s')
+			AND NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name = '%[1]s' AND column_name = 'position'
+			) THEN
+				ALTER TABLE %[1]s ADD COLUMN position bigint NOT NULL DEFAULT 0;
+				UPDATE %[1]s SET position = ordered.rn - 1
+				FROM (
+					SELECT id, ROW_NUMBER() OVER (%[2]s ORDER BY LOWER(display_name) ASC, id ASC) AS rn
+					FROM %[1]s
+				) AS ordered
+				WHERE %[1]s.id = ordered.id;
+			END IF;
+		END
+		$$
+	`, table, partition)).Error
+}
+
+// nextPosition returns the position right after the last row of table whose
+// parentColumn equals parentID, or after the last row overall when
+// parentColumn is empty.
+func nextPosition(tx *gorm.DB, table, parentColumn string, parentID any) (int, error) {
+	q := tx.Table(table)
+	if parentColumn != "" {
+		q = q.Where(parentColumn+" = ?", parentID)
+	}
+	var next int
+	if err := q.Select("COALESCE(MAX(position) + 1, 0)").Scan(&next).Error; err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// reorder assigns position i to orderedIDs[i]. Rows not belonging to the given
+// parent are left untouched.
+func reorder(tx *gorm.DB, table, parentColumn string, parentID any, orderedIDs []uint) error {
+	for i, id := range orderedIDs {
+		q := tx.Table(table).Where("id = ?", id)
+		if parentColumn != "" {
+			q = q.Where(parentColumn+" = ?", parentID)
+		}
+		if err := q.Update("position", i).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
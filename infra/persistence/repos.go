@@ -18,6 +18,16 @@ type Repos struct {
 	Session         domainrepo.SessionRepository
 	UserIDMigration domainrepo.UserIDMigrationRepository
 	IdpLink         domainrepo.IdpLinkRepository
+	AccessToken     domainrepo.AccessTokenRepository
+	SearchProvider  domainrepo.SearchProviderRepository
+	Favicon         domainrepo.FaviconRepository
+	CustomIcon      domainrepo.CustomIconRepository
+	LocalAccount    domainrepo.LocalAccountRepository
+	AuditLog        domainrepo.AuditLogRepository
+	LocalGroup      domainrepo.LocalGroupRepository
+	Invitation      domainrepo.InvitationRepository
+	SharedCategory  domainrepo.SharedCategoryRepository
+	CategoryShare   domainrepo.CategoryShareRepository
 }
 
 func NewRepos(db *gorm.DB) (*Repos, error) {
@@ -72,6 +82,56 @@ func NewRepos(db *gorm.DB) (*Repos, error) {
 		return nil, err
 	}
 
+	accessTokenRepo, err := repo.NewGormAccessTokenRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	searchProviderRepo, err := repo.NewGormSearchProviderRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	faviconRepo, err := repo.NewGormFaviconRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	customIconRepo, err := repo.NewGormCustomIconRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	localAccountRepo, err := repo.NewGormLocalAccountRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLogRepo, err := repo.NewGormAuditLogRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	localGroupRepo, err := repo.NewGormLocalGroupRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	invitationRepo, err := repo.NewGormInvitationRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedCategoryRepo, err := repo.NewGormSharedCategoryRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryShareRepo, err := repo.NewGormCategoryShareRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Repos{
 		User:            userRepo,
 		Dashboard:       dashboardRepo,
@@ -83,5 +143,15 @@ func NewRepos(db *gorm.DB) (*Repos, error) {
 		Session:         sessionRepo,
 		UserIDMigration: repo.NewGormUserIDMigrationRepo(db),
 		IdpLink:         idpLinkRepo,
+		AccessToken:     accessTokenRepo,
+		SearchProvider:  searchProviderRepo,
+		Favicon:         faviconRepo,
+		CustomIcon:      customIconRepo,
+		LocalAccount:    localAccountRepo,
+		AuditLog:        auditLogRepo,
+		LocalGroup:      localGroupRepo,
+		Invitation:      invitationRepo,
+		SharedCategory:  sharedCategoryRepo,
+		CategoryShare:   categoryShareRepo,
 	}, nil
 }
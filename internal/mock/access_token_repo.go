@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type AccessTokenRepository struct{ mock.Mock }
+
+func (m *AccessTokenRepository) Create(ctx context.Context, record *domainrepo.AccessTokenRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *AccessTokenRepository) ListByUserID(ctx context.Context, userID string) ([]*domainrepo.AccessTokenRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainrepo.AccessTokenRecord), args.Error(1)
+}
+
+func (m *AccessTokenRepository) Touch(ctx context.Context, tokenHash, lastIP string) (*domainrepo.AccessTokenRecord, error) {
+	args := m.Called(ctx, tokenHash, lastIP)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.AccessTokenRecord), args.Error(1)
+}
+
+func (m *AccessTokenRepository) UpdateIdentity(ctx context.Context, record *domainrepo.AccessTokenRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *AccessTokenRepository) DeleteByID(ctx context.Context, id, userID string) error {
+	return m.Called(ctx, id, userID).Error(0)
+}
+
+func (m *AccessTokenRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	return m.Called(ctx, userID).Error(0)
+}
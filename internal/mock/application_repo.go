@@ -33,3 +33,11 @@ func (m *ApplicationRepository) List(ctx context.Context) ([]domainrepo.Applicat
 func (m *ApplicationRepository) Delete(ctx context.Context, id uint) error {
 	return m.Called(ctx, id).Error(0)
 }
+
+func (m *ApplicationRepository) Reorder(ctx context.Context, orderedIDs []uint) error {
+	return m.Called(ctx, orderedIDs).Error(0)
+}
+
+func (m *ApplicationRepository) RecordHealth(ctx context.Context, id uint, health domainrepo.ApplicationHealthRecord) error {
+	return m.Called(ctx, id, health).Error(0)
+}
@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	domainmodel "git.at.oechsler.it/samuel/dash/v2/domain/model"
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type AuditLogRepository struct{ mock.Mock }
+
+func (m *AuditLogRepository) Create(ctx context.Context, entry *domainmodel.AuditEntry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+func (m *AuditLogRepository) List(ctx context.Context, filter domainrepo.AuditLogFilter) ([]domainmodel.AuditEntry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainmodel.AuditEntry), args.Error(1)
+}
+
+func (m *AuditLogRepository) DeleteBefore(ctx context.Context, t time.Time) error {
+	return m.Called(ctx, t).Error(0)
+}
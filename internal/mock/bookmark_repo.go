@@ -33,3 +33,7 @@ func (m *BookmarkRepository) ListByCategoryIDs(ctx context.Context, categoryIDs
 func (m *BookmarkRepository) Delete(ctx context.Context, id uint) error {
 	return m.Called(ctx, id).Error(0)
 }
+
+func (m *BookmarkRepository) Reorder(ctx context.Context, categoryID uint, orderedIDs []uint) error {
+	return m.Called(ctx, categoryID, orderedIDs).Error(0)
+}
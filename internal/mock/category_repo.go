@@ -33,3 +33,7 @@ func (m *CategoryRepository) ListByDashboardID(ctx context.Context, dashboardID
 func (m *CategoryRepository) Delete(ctx context.Context, id uint) error {
 	return m.Called(ctx, id).Error(0)
 }
+
+func (m *CategoryRepository) Reorder(ctx context.Context, dashboardID uint, orderedIDs []uint) error {
+	return m.Called(ctx, dashboardID, orderedIDs).Error(0)
+}
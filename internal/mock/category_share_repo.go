@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type CategoryShareRepository struct{ mock.Mock }
+
+func (m *CategoryShareRepository) ListByCategory(ctx context.Context, categoryID uint) ([]domainrepo.CategoryShareRecord, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.CategoryShareRecord), args.Error(1)
+}
+
+func (m *CategoryShareRepository) ListByUser(ctx context.Context, userID string) ([]domainrepo.CategoryShareRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.CategoryShareRecord), args.Error(1)
+}
+
+func (m *CategoryShareRepository) Get(ctx context.Context, categoryID uint, userID string) (*domainrepo.CategoryShareRecord, error) {
+	args := m.Called(ctx, categoryID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.CategoryShareRecord), args.Error(1)
+}
+
+func (m *CategoryShareRepository) Upsert(ctx context.Context, record *domainrepo.CategoryShareRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *CategoryShareRepository) Delete(ctx context.Context, categoryID uint, userID string) error {
+	return m.Called(ctx, categoryID, userID).Error(0)
+}
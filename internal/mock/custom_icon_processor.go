@@ -0,0 +1,11 @@
+package mock
+
+import "github.com/stretchr/testify/mock"
+
+type CustomIconProcessor struct{ mock.Mock }
+
+func (m *CustomIconProcessor) Process(data []byte) (string, []byte, error) {
+	args := m.Called(data)
+	out, _ := args.Get(1).([]byte)
+	return args.String(0), out, args.Error(2)
+}
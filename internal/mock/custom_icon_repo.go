@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type CustomIconRepository struct{ mock.Mock }
+
+func (m *CustomIconRepository) Create(ctx context.Context, record *domainrepo.CustomIconRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *CustomIconRepository) GetByID(ctx context.Context, id uint) (*domainrepo.CustomIconRecord, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.CustomIconRecord), args.Error(1)
+}
+
+func (m *CustomIconRepository) ListVisible(ctx context.Context, userID string) ([]domainrepo.CustomIconRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.CustomIconRecord), args.Error(1)
+}
+
+func (m *CustomIconRepository) Delete(ctx context.Context, userID string, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
@@ -22,7 +22,7 @@ func (m *DashboardRepository) Get(ctx context.Context, id uint) (*domainrepo.Das
 	return args.Get(0).(*domainrepo.DashboardRecord), args.Error(1)
 }
 
-func (m *DashboardRepository) GetByUserID(ctx context.Context, userID string) (*domainrepo.DashboardRecord, error) {
+func (m *DashboardRepository) GetDefault(ctx context.Context, userID string) (*domainrepo.DashboardRecord, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,6 +30,22 @@ func (m *DashboardRepository) GetByUserID(ctx context.Context, userID string) (*
 	return args.Get(0).(*domainrepo.DashboardRecord), args.Error(1)
 }
 
+func (m *DashboardRepository) ListByUserID(ctx context.Context, userID string) ([]domainrepo.DashboardRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.DashboardRecord), args.Error(1)
+}
+
+func (m *DashboardRepository) SetDefault(ctx context.Context, userID string, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
 func (m *DashboardRepository) Delete(ctx context.Context, id uint) error {
 	return m.Called(ctx, id).Error(0)
 }
+
+func (m *DashboardRepository) Reorder(ctx context.Context, userID string, orderedIDs []uint) error {
+	return m.Called(ctx, userID, orderedIDs).Error(0)
+}
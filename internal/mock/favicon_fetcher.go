@@ -0,0 +1,15 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type FaviconFetcher struct{ mock.Mock }
+
+func (m *FaviconFetcher) Fetch(ctx context.Context, site string) (string, []byte, error) {
+	args := m.Called(ctx, site)
+	data, _ := args.Get(1).([]byte)
+	return args.String(0), data, args.Error(2)
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type FaviconRepository struct{ mock.Mock }
+
+func (m *FaviconRepository) Get(ctx context.Context, site string) (*domainrepo.FaviconRecord, error) {
+	args := m.Called(ctx, site)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.FaviconRecord), args.Error(1)
+}
+
+func (m *FaviconRepository) Upsert(ctx context.Context, record *domainrepo.FaviconRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *FaviconRepository) DeleteFetchedBefore(ctx context.Context, t time.Time) error {
+	return m.Called(ctx, t).Error(0)
+}
+
+func (m *FaviconRepository) DeleteAllButNewest(ctx context.Context, keep int) error {
+	return m.Called(ctx, keep).Error(0)
+}
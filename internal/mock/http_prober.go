@@ -0,0 +1,14 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type HTTPProber struct{ mock.Mock }
+
+func (m *HTTPProber) Probe(ctx context.Context, url string) (int, error) {
+	args := m.Called(ctx, url)
+	return args.Int(0), args.Error(1)
+}
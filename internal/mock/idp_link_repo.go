@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
 type IdpLinkRepository struct{ mock.Mock }
@@ -16,3 +18,24 @@ func (m *IdpLinkRepository) ResolveOrCreate(ctx context.Context, issuer, sub str
 func (m *IdpLinkRepository) DeleteByUserID(ctx context.Context, userID string) error {
 	return m.Called(ctx, userID).Error(0)
 }
+
+func (m *IdpLinkRepository) Link(ctx context.Context, userID, issuer, sub string) (string, error) {
+	args := m.Called(ctx, userID, issuer, sub)
+	return args.String(0), args.Error(1)
+}
+
+func (m *IdpLinkRepository) ListByUserID(ctx context.Context, userID string) ([]domainrepo.IdpLinkRecord, error) {
+	args := m.Called(ctx, userID)
+	records, _ := args.Get(0).([]domainrepo.IdpLinkRecord)
+	return records, args.Error(1)
+}
+
+func (m *IdpLinkRepository) List(ctx context.Context) ([]domainrepo.IdpLinkRecord, error) {
+	args := m.Called(ctx)
+	records, _ := args.Get(0).([]domainrepo.IdpLinkRecord)
+	return records, args.Error(1)
+}
+
+func (m *IdpLinkRepository) Unlink(ctx context.Context, userID, issuer, sub string) error {
+	return m.Called(ctx, userID, issuer, sub).Error(0)
+}
@@ -0,0 +1,66 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type InvitationRepository struct{ mock.Mock }
+
+func (m *InvitationRepository) Create(ctx context.Context, record *domainrepo.InvitationRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *InvitationRepository) List(ctx context.Context) ([]domainrepo.InvitationRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.InvitationRecord), args.Error(1)
+}
+
+func (m *InvitationRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domainrepo.InvitationRecord, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.InvitationRecord), args.Error(1)
+}
+
+func (m *InvitationRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *InvitationRepository) Redeem(ctx context.Context, redemption *domainrepo.InvitationRedemptionRecord, now time.Time) (bool, error) {
+	args := m.Called(ctx, redemption, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *InvitationRepository) ListRedemptions(ctx context.Context) ([]domainrepo.InvitationRedemptionRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.InvitationRedemptionRecord), args.Error(1)
+}
+
+func (m *InvitationRepository) ListActiveRedemptions(ctx context.Context, userID string, now time.Time) ([]domainrepo.InvitationRedemptionRecord, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.InvitationRedemptionRecord), args.Error(1)
+}
+
+func (m *InvitationRepository) HasRedeemed(ctx context.Context, invitationID, userID string) (bool, error) {
+	args := m.Called(ctx, invitationID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *InvitationRepository) RevokeRedemption(ctx context.Context, id uint, now time.Time) error {
+	return m.Called(ctx, id, now).Error(0)
+}
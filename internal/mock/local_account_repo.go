@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type LocalAccountRepository struct{ mock.Mock }
+
+func (m *LocalAccountRepository) GetByUsername(ctx context.Context, username string) (*domainrepo.LocalAccountRecord, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.LocalAccountRecord), args.Error(1)
+}
+
+func (m *LocalAccountRepository) List(ctx context.Context) ([]domainrepo.LocalAccountRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.LocalAccountRecord), args.Error(1)
+}
+
+func (m *LocalAccountRepository) Create(ctx context.Context, record *domainrepo.LocalAccountRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *LocalAccountRepository) SetPassword(ctx context.Context, username, passwordHash string) error {
+	return m.Called(ctx, username, passwordHash).Error(0)
+}
+
+func (m *LocalAccountRepository) Delete(ctx context.Context, username string) error {
+	return m.Called(ctx, username).Error(0)
+}
+
+func (m *LocalAccountRepository) ReplaceManaged(ctx context.Context, records []domainrepo.LocalAccountRecord) error {
+	return m.Called(ctx, records).Error(0)
+}
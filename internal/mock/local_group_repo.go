@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type LocalGroupRepository struct{ mock.Mock }
+
+func (m *LocalGroupRepository) List(ctx context.Context) ([]domainrepo.LocalGroupRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.LocalGroupRecord), args.Error(1)
+}
+
+func (m *LocalGroupRepository) GetByName(ctx context.Context, name string) (*domainrepo.LocalGroupRecord, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.LocalGroupRecord), args.Error(1)
+}
+
+func (m *LocalGroupRepository) Create(ctx context.Context, record *domainrepo.LocalGroupRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *LocalGroupRepository) Update(ctx context.Context, record *domainrepo.LocalGroupRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *LocalGroupRepository) Delete(ctx context.Context, id uint) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *LocalGroupRepository) AddMember(ctx context.Context, groupID uint, userID string) error {
+	return m.Called(ctx, groupID, userID).Error(0)
+}
+
+func (m *LocalGroupRepository) RemoveMember(ctx context.Context, groupID uint, userID string) error {
+	return m.Called(ctx, groupID, userID).Error(0)
+}
@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type SearchProviderRepository struct{ mock.Mock }
+
+func (m *SearchProviderRepository) Create(ctx context.Context, record *domainrepo.SearchProviderRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *SearchProviderRepository) Delete(ctx context.Context, userID string, id uint) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *SearchProviderRepository) ListByUser(ctx context.Context, userID string) ([]domainrepo.SearchProviderRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.SearchProviderRecord), args.Error(1)
+}
+
+func (m *SearchProviderRepository) GetByID(ctx context.Context, userID string, id uint) (*domainrepo.SearchProviderRecord, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.SearchProviderRecord), args.Error(1)
+}
@@ -39,6 +39,22 @@ func (m *SessionRepository) ListByUserID(ctx context.Context, userID string) ([]
 	return args.Get(0).([]*domainrepo.SessionRecord), args.Error(1)
 }
 
+func (m *SessionRepository) ListLatest(ctx context.Context) ([]*domainrepo.SessionRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainrepo.SessionRecord), args.Error(1)
+}
+
+func (m *SessionRepository) FindLatestByUserID(ctx context.Context, userID string) (*domainrepo.SessionRecord, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.SessionRecord), args.Error(1)
+}
+
 func (m *SessionRepository) DeleteByID(ctx context.Context, recordID, userID string) error {
 	return m.Called(ctx, recordID, userID).Error(0)
 }
@@ -51,10 +67,30 @@ func (m *SessionRepository) DeleteByUserID(ctx context.Context, userID string) e
 	return m.Called(ctx, userID).Error(0)
 }
 
+func (m *SessionRepository) DeleteBySub(ctx context.Context, issuer, sub string) error {
+	return m.Called(ctx, issuer, sub).Error(0)
+}
+
+func (m *SessionRepository) DeleteBySid(ctx context.Context, issuer, sid string) error {
+	return m.Called(ctx, issuer, sid).Error(0)
+}
+
 func (m *SessionRepository) RefreshBySessionID(ctx context.Context, record *domainrepo.SessionRecord) error {
 	return m.Called(ctx, record).Error(0)
 }
 
+func (m *SessionRepository) ListRefreshable(ctx context.Context, before time.Time) ([]*domainrepo.SessionRecord, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domainrepo.SessionRecord), args.Error(1)
+}
+
+func (m *SessionRepository) MarkStale(ctx context.Context, sessionID string) error {
+	return m.Called(ctx, sessionID).Error(0)
+}
+
 func (m *SessionRepository) DeleteExpired(ctx context.Context) error {
 	return m.Called(ctx).Error(0)
 }
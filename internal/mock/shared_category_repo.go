@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
+)
+
+type SharedCategoryRepository struct{ mock.Mock }
+
+func (m *SharedCategoryRepository) List(ctx context.Context) ([]domainrepo.SharedCategoryRecord, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domainrepo.SharedCategoryRecord), args.Error(1)
+}
+
+func (m *SharedCategoryRepository) Get(ctx context.Context, categoryID uint) (*domainrepo.SharedCategoryRecord, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domainrepo.SharedCategoryRecord), args.Error(1)
+}
+
+func (m *SharedCategoryRepository) Publish(ctx context.Context, record *domainrepo.SharedCategoryRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *SharedCategoryRepository) Unpublish(ctx context.Context, categoryID uint) error {
+	return m.Called(ctx, categoryID).Error(0)
+}
+
+func (m *SharedCategoryRepository) ListHidden(ctx context.Context, userID string) ([]uint, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *SharedCategoryRepository) Hide(ctx context.Context, userID string, categoryID uint) error {
+	return m.Called(ctx, userID, categoryID).Error(0)
+}
+
+func (m *SharedCategoryRepository) Show(ctx context.Context, userID string, categoryID uint) error {
+	return m.Called(ctx, userID, categoryID).Error(0)
+}
@@ -4,10 +4,23 @@ import (
 	"context"
 
 	"github.com/stretchr/testify/mock"
+
+	domainrepo "git.at.oechsler.it/samuel/dash/v2/domain/repo"
 )
 
 type UserRepository struct{ mock.Mock }
 
+func (m *UserRepository) List(ctx context.Context) ([]domainrepo.UserRecord, error) {
+	args := m.Called(ctx)
+	records, _ := args.Get(0).([]domainrepo.UserRecord)
+	return records, args.Error(1)
+}
+
+func (m *UserRepository) Exists(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *UserRepository) DeleteByID(ctx context.Context, id string) error {
 	return m.Called(ctx, id).Error(0)
 }